@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"minisentry/internal/conformance"
+)
+
+func main() {
+	// Keep the output focused on case results
+	log.SetOutput(io.Discard)
+
+	results, err := conformance.Run(conformance.Cases)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "conformance suite failed to run:", err)
+		os.Exit(2)
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Passed() {
+			fmt.Printf("PASS  %s\n", result.Case.Name())
+			continue
+		}
+
+		failed++
+		fmt.Printf("FAIL  %s\n", result.Case.Name())
+		for _, failure := range result.Failures {
+			fmt.Printf("      %s\n", failure)
+		}
+	}
+
+	fmt.Printf("\n%d/%d fixtures passed\n", len(results)-failed, len(results))
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"minisentry/internal/loadgen"
+)
+
+func main() {
+	cfg := loadgen.Config{}
+	flag.StringVar(&cfg.DSN, "dsn", os.Getenv("LOADGEN_DSN"), "target project DSN, e.g. http://<public_key>@localhost:8080/<project_id>")
+	flag.Float64Var(&cfg.Rate, "rate", 10, "requests per second (0 = unthrottled)")
+	flag.IntVar(&cfg.Concurrency, "concurrency", 4, "maximum requests in flight")
+	flag.DurationVar(&cfg.Duration, "duration", 0, "stop after this long (e.g. 30s)")
+	flag.IntVar(&cfg.Requests, "requests", 100, "stop after this many requests (0 = until -duration)")
+	flag.DurationVar(&cfg.Timeout, "timeout", 10*time.Second, "per-request timeout")
+	payloadDir := flag.String("payloads", "", "directory of recorded payloads (*.json store bodies, *.envelope envelopes); built-in samples when empty")
+	minAcceptance := flag.Float64("min-acceptance", 0, "exit non-zero if the accepted fraction falls below this value (0-1)")
+	flag.Parse()
+
+	if cfg.DSN == "" {
+		log.Fatal("A target DSN is required (-dsn or LOADGEN_DSN)")
+	}
+
+	var payloads []loadgen.Payload
+	var err error
+	if *payloadDir != "" {
+		payloads, err = loadgen.LoadPayloads(*payloadDir)
+	} else {
+		payloads, err = loadgen.DefaultPayloads()
+	}
+	if err != nil {
+		log.Fatal("Failed to load payloads:", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("Replaying %d payload(s) at %.1f req/s with concurrency %d", len(payloads), cfg.Rate, cfg.Concurrency)
+
+	report, err := loadgen.Run(ctx, cfg, payloads)
+	if err != nil {
+		log.Fatal("Load generation failed:", err)
+	}
+
+	report.Print(os.Stdout)
+
+	if report.AcceptanceRate() < *minAcceptance {
+		log.Printf("Acceptance rate %.3f is below the required %.3f", report.AcceptanceRate(), *minAcceptance)
+		os.Exit(1)
+	}
+}
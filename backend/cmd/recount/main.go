@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"minisentry/internal/config"
+	"minisentry/internal/database"
+	"minisentry/internal/services"
+
+	"github.com/google/uuid"
+)
+
+// recount repairs the counters of a project's issues (times_seen, first_seen, last_seen)
+// from their stored events, batch by batch. An interrupted repair resumes with -after.
+func main() {
+	projectFlag := flag.String("project", "", "ID of the project whose issue counters to repair")
+	afterFlag := flag.String("after", "", "resume after this issue ID, as printed by an interrupted run")
+	batchSize := flag.Int("batch-size", 200, "issues repaired per batch")
+	flag.Parse()
+
+	projectID, err := uuid.Parse(*projectFlag)
+	if err != nil {
+		log.Fatal("A valid project ID is required (-project)")
+	}
+	var after *uuid.UUID
+	if *afterFlag != "" {
+		id, err := uuid.Parse(*afterFlag)
+		if err != nil {
+			log.Fatal("Invalid issue ID for -after:", err)
+		}
+		after = &id
+	}
+
+	cfg := config.Load()
+	db, err := database.Open(cfg.DatabaseDriver, cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	repairService := services.NewCounterRepairService(db)
+	var checked, repaired, changed int
+	for {
+		if ctx.Err() != nil {
+			if after != nil {
+				log.Printf("Interrupted; resume with -project %s -after %s", projectID, after)
+			}
+			break
+		}
+
+		batch, err := repairService.RepairBatch(projectID, after, *batchSize)
+		if err != nil {
+			if after != nil {
+				log.Printf("Resume with -project %s -after %s", projectID, after)
+			}
+			log.Fatal("Counter repair failed:", err)
+		}
+		if batch.LastIssueID == nil {
+			break
+		}
+		checked += batch.Checked
+		repaired += batch.Repaired
+		changed += batch.Changed
+		after = batch.LastIssueID
+		log.Printf("Checked %d issue(s), repaired %d, up to issue %s", checked, repaired, after)
+	}
+
+	log.Printf("Done: %d issue(s) checked, %d repaired", checked, repaired)
+	if changed > 0 {
+		log.Printf("%d issue(s) received events during the repair and were left alone; run again to repair them", changed)
+	}
+}
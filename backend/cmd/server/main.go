@@ -1,25 +1,38 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"minisentry/internal/config"
 	"minisentry/internal/database"
 	"minisentry/internal/handlers"
+	"minisentry/internal/ingestpb"
+	"minisentry/internal/mail"
+	"minisentry/internal/metrics"
 	"minisentry/internal/middleware"
+	"minisentry/internal/queue"
+	"minisentry/internal/scheduler"
 	"minisentry/internal/services"
+	"minisentry/internal/storage"
 
 	"github.com/go-chi/chi/v5"
+	"google.golang.org/grpc"
 )
 
 func main() {
 	cfg := config.Load()
 	
 	// Connect to database
-	db, err := database.Connect(cfg.DatabaseURL)
+	db, err := database.Open(cfg.DatabaseDriver, cfg.DatabaseURL)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
@@ -37,20 +50,250 @@ func main() {
 	userService := services.NewUserService(db, passwordService)
 	organizationService := services.NewOrganizationService(db)
 	projectService := services.NewProjectService(db, cfg.DSNHost)
+	platformRegistry, err := services.NewPlatformRegistry(cfg.ProjectPlatforms, cfg.AllowUnknownPlatforms)
+	if err != nil {
+		log.Fatal("Invalid PROJECT_PLATFORMS:", err)
+	}
+	projectService.SetPlatformRegistry(platformRegistry)
 	errorService := services.NewErrorService(db)
+	errorService.SetEventLimits(services.EventLimits{
+		MaxEventSize:     cfg.MaxEventSize,
+		MaxMessageLength: cfg.MaxEventMessage,
+		MaxBreadcrumbs:   cfg.MaxEventBreadcrumbs,
+		MaxExtraSize:     cfg.MaxEventExtraSize,
+		MaxContextsSize:  cfg.MaxEventContextsSize,
+	})
+	if cfg.DataScrubbing {
+		errorService.SetDataScrubber(services.NewDataScrubber(cfg.SensitiveFields))
+	}
+	errorService.SetClockSkewThreshold(cfg.ClockSkewThreshold)
+	if cfg.GeoIPDatabase != "" {
+		geoIP, err := services.OpenGeoIP(cfg.GeoIPDatabase)
+		if err != nil {
+			log.Fatal("Failed to open GEOIP_DATABASE:", err)
+		}
+		defer geoIP.Close()
+		errorService.SetGeoIP(geoIP)
+	}
+	eventDedupCache, err := services.OpenEventDedupCache(cfg.EventDedupCache, cfg.EventDedupCacheSize, cfg.RedisURL, cfg.EventDedupKeyPrefix, cfg.EventDedupTTL)
+	if err != nil {
+		log.Fatal("Failed to open the event dedup cache:", err)
+	}
+	if eventDedupCache != nil {
+		errorService.SetEventDedupCache(eventDedupCache)
+	}
+	eventStatusTracker, err := services.OpenEventStatusTracker(cfg.EventStatusTracker, cfg.EventStatusTrackerSize, cfg.RedisURL, cfg.EventStatusKeyPrefix, cfg.EventStatusTTL)
+	if err != nil {
+		log.Fatal("Failed to open the event status tracker:", err)
+	}
+	if eventStatusTracker != nil {
+		errorService.SetEventStatusTracker(eventStatusTracker)
+	}
+	issueCounterBuffer, err := services.OpenIssueCounterBuffer(cfg.IssueCounterBuffer, cfg.RedisURL, cfg.IssueCounterKeyPrefix)
+	if err != nil {
+		log.Fatal("Failed to open the issue counter buffer:", err)
+	}
+	if issueCounterBuffer != nil {
+		errorService.SetIssueCounterBuffer(issueCounterBuffer)
+	}
+	sessionService := services.NewSessionService(db)
+	transactionService := services.NewTransactionService(db)
+	replayService := services.NewReplayService(db)
+	clientReportService := services.NewClientReportService(db)
+	blobStore, err := storage.Open(cfg.AttachmentsStorage, cfg.AttachmentsPath, storage.S3Config{
+		Bucket:          cfg.S3Bucket,
+		Region:          cfg.S3Region,
+		Endpoint:        cfg.S3Endpoint,
+		AccessKeyID:     cfg.S3AccessKeyID,
+		SecretAccessKey: cfg.S3SecretAccessKey,
+		Prefix:          cfg.S3Prefix,
+		PathStyle:       cfg.S3PathStyle,
+	})
+	if err != nil {
+		log.Fatal("Failed to initialize attachment storage:", err)
+	}
+	attachmentService := services.NewAttachmentService(db, blobStore)
 	issueService := services.NewIssueService(db.DB)
+	incidentService := services.NewIncidentService(db, services.AlertStormConfig{
+		Threshold: cfg.IncidentStormThreshold,
+		Window:    cfg.IncidentStormWindow,
+	})
+	errorService.OnIssueCreated(incidentService.DetectAlertStorm)
+	outboxService := services.NewOutboxService(db, mail.New(mail.SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.EmailFrom,
+	}), cfg.OutboxMaxAttempts)
+	statusPageService := services.NewStatusPageService(db, outboxService, cfg.PublicURL)
+	incidentService.OnIncidentChange(statusPageService.NotifyIncidentChange)
+	issueSyncService := services.NewIssueSyncService(db)
+	errorService.OnIssueCreated(issueSyncService.RecordIssueCreated)
+	errorService.OnIssueRegressed(issueSyncService.RecordIssueRegressed)
+	issueService.OnIssueChange(issueSyncService.RecordIssueChange)
+	issueSubscriptionService := services.NewIssueSubscriptionService(db, outboxService, cfg.PublicURL)
+	issueService.OnIssueChange(issueSubscriptionService.NotifyIssueChange)
+	issueService.OnIssueMention(issueSubscriptionService.NotifyMentions)
+	errorService.OnIssueRegressed(issueSubscriptionService.NotifyIssueRegressed)
+	issueSyncService.Start(context.Background())
+	quotaService := services.NewQuotaService(db, services.QuotaConfig{
+		RateLimit:       cfg.ProjectRateLimit,
+		RateLimitWindow: cfg.ProjectRateLimitWindow,
+		DailyQuota:      cfg.ProjectDailyQuota,
+	})
+	spikeProtectionService := services.NewSpikeProtectionService(db, services.SpikeProtectionConfig{
+		Multiplier: cfg.SpikeProtectionMultiplier,
+		Window:     cfg.SpikeProtectionWindow,
+		MinRate:    cfg.SpikeProtectionMinRate,
+		SampleRate: cfg.SpikeProtectionSampleRate,
+	})
+	inboundFilterService := services.NewInboundFilterService(db)
+	errorService.SetInboundFilters(inboundFilterService)
+	eventVolumeService := services.NewEventVolumeService(db)
+	errorService.SetEventVolume(eventVolumeService)
+	issueOutcomeService := services.NewIssueOutcomeService(db)
+	errorService.SetIssueOutcomes(issueOutcomeService)
+	issueService.SetIssueOutcomes(issueOutcomeService)
+	apiUsageService := services.NewAPIUsageService(db)
+	scrubbingRuleService := services.NewScrubbingRuleService(db)
+	errorService.SetScrubbingRules(scrubbingRuleService)
+	ownershipService := services.NewOwnershipService(db)
+	errorService.SetOwnership(ownershipService)
+	groupingConfigService := services.NewGroupingConfigService(db)
+	errorService.SetGroupingConfigs(groupingConfigService)
+	issueService.SetGroupingConfigs(groupingConfigService)
+	issueService.SetAttachments(attachmentService)
+	regroupService := services.NewRegroupService(db, errorService, groupingConfigService)
+	issueTitleService := services.NewIssueTitleService(db, errorService)
+	errorService.SetEventSampler(services.NewEventSampler(db))
+	auditLogService := services.NewAuditLogService(db)
+	maintenanceService := services.NewMaintenanceService(db)
+	errorService.SetMaintenance(maintenanceService)
+	ingestTokenService := services.NewIngestTokenService(db)
+	releaseService := services.NewReleaseService(db, blobStore)
+	if err := releaseService.ParseVersions(); err != nil {
+		log.Printf("Failed to parse release versions: %v", err)
+	}
+	if err := userService.GrantSuperuser(cfg.SuperuserEmails); err != nil {
+		log.Fatal("Failed to grant superusers:", err)
+	}
+	
+	// Periodic maintenance jobs
+	jobs := scheduler.New()
+	jobs.Every("expire-quota-overrides", time.Minute, quotaService.ExpireOverrides)
+	jobs.Every("drain-outbox", cfg.OutboxPollInterval, outboxService.Drain)
+	jobs.Every("prune-outbox", time.Hour, outboxService.Prune)
+	jobs.Every("flush-spike-protection-drops", time.Minute, spikeProtectionService.FlushDrops)
+	jobs.Every("flush-inbound-filter-stats", time.Minute, inboundFilterService.FlushStats)
+	jobs.Every("flush-event-volume", time.Minute, eventVolumeService.FlushStats)
+	jobs.Every("flush-issue-outcomes", time.Minute, issueOutcomeService.FlushStats)
+	jobs.Every("flush-api-usage", time.Minute, apiUsageService.FlushStats)
+	jobs.Every("run-regroup-jobs", 10*time.Second, regroupService.RunPending)
+	jobs.Every("refresh-issue-titles", 5*time.Minute, issueTitleService.RefreshTitles)
+	jobs.Every("delete-queued-issues", 30*time.Second, issueService.DeleteQueuedIssues)
+	if issueCounterBuffer != nil {
+		jobs.Every("flush-issue-counters", cfg.IssueCounterFlushInterval, issueCounterBuffer.Flush)
+	}
+	jobs.Start(context.Background())
 	
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(jwtService)
 	organizationMiddleware := middleware.NewOrganizationMiddleware(organizationService)
 	projectMiddleware := middleware.NewProjectMiddleware(projectService)
+	adminMiddleware := middleware.NewAdminMiddleware(userService)
+	ingestTokenMiddleware := middleware.NewIngestTokenMiddleware(ingestTokenService, authMiddleware, projectMiddleware)
+	maintenanceMiddleware := middleware.NewMaintenanceMiddleware(maintenanceService)
+	apiUsageMiddleware := middleware.NewAPIUsageMiddleware(apiUsageService)
 	
 	// Initialize handlers
 	userHandler := handlers.NewUserHandler(userService, jwtService)
+	if cfg.AuthCookies {
+		csrfKey := cfg.CSRFSecret
+		if csrfKey == "" {
+			csrfKey = middleware.DeriveCSRFKey(cfg.JWTSecret)
+		}
+		sessionCookies, err := middleware.NewSessionCookies(cfg.AuthCookieSameSite, cfg.AuthCookieSecure, cfg.AuthCookieDomain, csrfKey, cfg.JWTExpiry, cfg.RefreshExpiry)
+		if err != nil {
+			log.Fatal("Invalid session cookie configuration:", err)
+		}
+		authMiddleware.UseSessionCookies(sessionCookies)
+		userHandler.UseSessionCookies(sessionCookies)
+	}
 	organizationHandler := handlers.NewOrganizationHandler(organizationService)
 	projectHandler := handlers.NewProjectHandler(projectService)
-	errorHandler := handlers.NewErrorHandler(errorService)
-	issueHandler := handlers.NewIssueHandler(issueService)
+	errorHandler := handlers.NewErrorHandler(errorService, sessionService, transactionService, replayService, attachmentService, clientReportService)
+	sessionHandler := handlers.NewSessionHandler(sessionService)
+	transactionHandler := handlers.NewTransactionHandler(transactionService)
+	clientReportHandler := handlers.NewClientReportHandler(clientReportService)
+	spikeProtectionHandler := handlers.NewSpikeProtectionHandler(spikeProtectionService)
+	eventStatusHandler := handlers.NewEventStatusHandler(errorService)
+	eventVolumeHandler := handlers.NewEventVolumeHandler(eventVolumeService)
+	apiUsageHandler := handlers.NewAPIUsageHandler(apiUsageService)
+	inboundFilterHandler := handlers.NewInboundFilterHandler(inboundFilterService, errorService)
+	scrubbingRuleHandler := handlers.NewScrubbingRuleHandler(scrubbingRuleService, errorService)
+	ownershipHandler := handlers.NewOwnershipHandler(ownershipService)
+	groupingConfigHandler := handlers.NewGroupingConfigHandler(groupingConfigService, regroupService, errorService)
+	incidentHandler := handlers.NewIncidentHandler(incidentService)
+	savedSearchHandler := handlers.NewSavedSearchHandler(services.NewSavedSearchService(db))
+	statusPageHandler := handlers.NewStatusPageHandler(statusPageService)
+	issueSyncHandler := handlers.NewIssueSyncHandler(issueSyncService)
+	issueHandler := handlers.NewIssueHandler(issueService, projectService, attachmentService, issueSubscriptionService)
+	ingestTokenHandler := handlers.NewIngestTokenHandler(ingestTokenService)
+	releaseHandler := handlers.NewReleaseHandler(releaseService)
+	adminHandler := handlers.NewAdminHandler(quotaService, auditLogService, maintenanceService)
+	errorHandler.UseQuotas(quotaService)
+	errorHandler.UseSpikeProtection(spikeProtectionService)
+	errorHandler.UseMaintenance(maintenanceService)
+	
+	// Shed ingestion requests at once while the database or ingest queue is saturated
+	var overload *middleware.OverloadController
+	if cfg.IngestOverloadShedding {
+		overload = middleware.NewOverloadController(middleware.OverloadLimits{
+			MaxInFlight:   cfg.IngestMaxInFlight,
+			MaxDBWait:     cfg.IngestOverloadDBWait,
+			MaxQueueDepth: int64(cfg.IngestOverloadQueueDepth),
+			RetryAfter:    cfg.IngestOverloadRetryAfter,
+		}, metrics.Default)
+		if sqlDB, err := db.DB.DB(); err == nil {
+			overload.WatchDatabase(sqlDB.Stats)
+		}
+		errorHandler.UseOverloadController(overload)
+	}
+	
+	// Queue store endpoint events when asynchronous ingestion is enabled. With INGEST_WORKERS=0
+	// the server only enqueues and cmd/worker processes consume the queue.
+	if cfg.IngestQueue != "" {
+		ingestQueue, err := queue.Open(cfg.IngestQueue, queue.Options{
+			MemorySize:   cfg.IngestQueueSize,
+			RedisURL:     cfg.RedisURL,
+			RedisKey:     cfg.IngestQueueKey,
+			KafkaBrokers: cfg.KafkaBrokers,
+			KafkaTopic:   cfg.KafkaTopic,
+			KafkaGroup:   cfg.KafkaGroup,
+		})
+		if err != nil {
+			log.Fatal("Failed to open the ingest queue:", err)
+		}
+		if cfg.IngestQueue == "memory" && cfg.IngestWorkers < 1 {
+			log.Fatal("The memory ingest queue needs INGEST_WORKERS of at least 1")
+		}
+		errorHandler.UseIngestQueue(ingestQueue)
+		if counter, ok := ingestQueue.(interface{ Len() (int64, error) }); ok && overload != nil {
+			overload.WatchQueue(counter.Len)
+		}
+		if cfg.IngestWorkers > 0 {
+			errorService.StartEventBatching(context.Background(), services.EventBatchConfig{
+				Size:          cfg.EventBatchSize,
+				FlushInterval: cfg.EventBatchFlushInterval,
+			})
+			ingestQueue.Start(context.Background(), cfg.IngestWorkers, errorService.ProcessQueuedEvent)
+		}
+		log.Printf("Asynchronous ingestion enabled on the %s queue with %d workers", cfg.IngestQueue, cfg.IngestWorkers)
+	}
+	if overload != nil {
+		overload.Start(context.Background())
+	}
 	
 	// Skip migrations for now since they're handled by docker-compose init
 	log.Println("Skipping migrations - handled by docker-compose init")
@@ -58,9 +301,18 @@ func main() {
 	// Set up Chi router
 	r := chi.NewRouter()
 	
-	// Apply global middleware
+	// Apply global middleware. Request IDs come first so panics and every error response carry one.
+	requestIDMiddleware, err := middleware.NewRequestIDMiddleware(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
+	r.Use(requestIDMiddleware.Handler)
+	clientIPMiddleware, err := middleware.NewClientIPMiddleware(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
+	r.Use(clientIPMiddleware.Handler)
 	r.Use(middleware.RecoveryMiddleware)
-	r.Use(middleware.RequestIDMiddleware)
 	r.Use(middleware.LoggingMiddleware)
 	r.Use(middleware.SecurityMiddleware)
 	r.Use(middleware.CORSMiddleware(cfg.CORSOrigins))
@@ -99,11 +351,45 @@ func main() {
 		})
 	})
 	
+	// Ingestion and the dashboard API have separate concurrency limits so neither can starve the other
+	ingestLimiter := middleware.NewConcurrencyLimiter("ingestion", middleware.ConcurrencyLimit{
+		MaxInFlight:  cfg.IngestMaxConcurrent,
+		MaxQueued:    cfg.IngestMaxQueued,
+		QueueTimeout: cfg.ConcurrencyQueueTimeout,
+	}, metrics.Default)
+	apiLimiter := middleware.NewConcurrencyLimiter("api", middleware.ConcurrencyLimit{
+		MaxInFlight:  cfg.APIMaxConcurrent,
+		MaxQueued:    cfg.APIMaxQueued,
+		QueueTimeout: cfg.ConcurrencyQueueTimeout,
+	}, metrics.Default)
+	draining := metrics.Default.Gauge("minisentry_http_draining", "1 while the server is shutting down and draining in-flight requests.")
+	
+	if cfg.MetricsPath != "" {
+		r.Method(http.MethodGet, cfg.MetricsPath, metrics.Handler(metrics.Default))
+	}
+	
 	// Error ingestion routes (DSN authenticated, separate from main API)
-	errorHandler.RegisterRoutes(r, projectMiddleware)
+	r.Group(func(r chi.Router) {
+		if overload != nil {
+			r.Use(overload.Middleware)
+		}
+		r.Use(ingestLimiter.Middleware)
+		
+		errorHandler.RegisterRoutes(r, projectMiddleware)
+		if cfg.TunnelPath != "" {
+			errorHandler.RegisterTunnelRoute(r, projectMiddleware, cfg.TunnelPath)
+		}
+	})
 
 	// API routes
 	r.Route("/api/v1", func(r chi.Router) {
+		r.Use(apiLimiter.Middleware)
+		r.Use(apiUsageMiddleware.Track)
+		r.Use(maintenanceMiddleware.ReadOnly)
+		if cfg.ResponseCompression {
+			r.Use(middleware.CompressionMiddleware(cfg.ResponseCompressionMinSize))
+		}
+		
 		// Register user routes
 		userHandler.RegisterRoutes(r, authMiddleware)
 		
@@ -116,6 +402,54 @@ func main() {
 		// Register issue routes
 		issueHandler.RegisterRoutes(r, authMiddleware, projectMiddleware)
 		
+		// Register organization ingest token routes
+		ingestTokenHandler.RegisterRoutes(r, authMiddleware, organizationMiddleware)
+		
+		// Register release routes (user or ingest token authenticated)
+		releaseHandler.RegisterRoutes(r, ingestTokenMiddleware)
+		
+		// Register release health routes
+		sessionHandler.RegisterRoutes(r, authMiddleware, projectMiddleware)
+		
+		// Register performance transaction routes
+		transactionHandler.RegisterRoutes(r, authMiddleware, projectMiddleware)
+		
+		// Register SDK client report routes
+		clientReportHandler.RegisterRoutes(r, authMiddleware, projectMiddleware)
+		
+		// Register spike protection routes
+		spikeProtectionHandler.RegisterRoutes(r, authMiddleware, projectMiddleware)
+		
+		// Register event status routes
+		eventStatusHandler.RegisterRoutes(r, authMiddleware, projectMiddleware)
+		
+		// Register event volume routes
+		eventVolumeHandler.RegisterRoutes(r, authMiddleware, projectMiddleware)
+		
+		// Register API usage routes
+		apiUsageHandler.RegisterRoutes(r, authMiddleware, organizationMiddleware, projectMiddleware)
+		
+		// Register inbound filter routes
+		inboundFilterHandler.RegisterRoutes(r, authMiddleware, projectMiddleware)
+		scrubbingRuleHandler.RegisterRoutes(r, authMiddleware, projectMiddleware)
+		ownershipHandler.RegisterRoutes(r, authMiddleware, projectMiddleware)
+		groupingConfigHandler.RegisterRoutes(r, authMiddleware, projectMiddleware)
+		
+		// Register issue sync routes
+		issueSyncHandler.RegisterRoutes(r, authMiddleware, projectMiddleware)
+		
+		// Register saved search routes
+		savedSearchHandler.RegisterRoutes(r, authMiddleware, organizationMiddleware, projectMiddleware)
+		
+		// Register incident routes
+		incidentHandler.RegisterRoutes(r, authMiddleware, organizationMiddleware)
+		
+		// Register status page routes (management and public status)
+		statusPageHandler.RegisterRoutes(r, authMiddleware, organizationMiddleware)
+		
+		// Register superuser admin routes
+		adminHandler.RegisterRoutes(r, authMiddleware, adminMiddleware)
+		
 		// Example public route
 		r.Get("/public", func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusOK)
@@ -210,35 +544,217 @@ func main() {
 	log.Printf("  GET  /api/v1/organizations/{id} - Get organization details (requires member access)")
 	log.Printf("  PUT  /api/v1/organizations/{id} - Update organization (requires admin/owner)")
 	log.Printf("  DELETE /api/v1/organizations/{id} - Delete organization (requires owner)")
+	log.Printf("  GET  /api/v1/organizations/{id}/settings/history?setting= - Organization setting change history (requires member access)")
 	log.Printf("  GET  /api/v1/organizations/{id}/members - List organization members (requires member access)")
 	log.Printf("  POST /api/v1/organizations/{id}/members - Add member (requires admin/owner)")
 	log.Printf("  PUT  /api/v1/organizations/{id}/members/{user_id} - Update member role (requires owner)")
 	log.Printf("  DELETE /api/v1/organizations/{id}/members/{user_id} - Remove member (requires admin/owner)")
+	log.Printf("  GET  /api/v1/organizations/{id}/ingest-tokens - List ingest tokens (requires admin/owner)")
+	log.Printf("  POST /api/v1/organizations/{id}/ingest-tokens - Create an ingest token for release uploads (requires admin/owner)")
+	log.Printf("  DELETE /api/v1/organizations/{id}/ingest-tokens/{token_id} - Revoke an ingest token (requires admin/owner)")
+	log.Printf("  GET  /api/v1/organizations/{id}/stats/api-usage?since=&until= - API calls per user and ingest token, with route and status breakdowns (requires owner)")
 	log.Printf("Project endpoints:")
+	log.Printf("  GET  /api/v1/platforms - Known project platforms and their aliases (requires auth)")
 	log.Printf("  POST /api/v1/organizations/{org_id}/projects - Create project (requires admin/owner)")
 	log.Printf("  GET  /api/v1/organizations/{org_id}/projects - List organization projects (requires member access)")
 	log.Printf("  GET  /api/v1/projects/{id} - Get project details (requires member access)")
 	log.Printf("  PUT  /api/v1/projects/{id} - Update project (requires admin/owner)")
 	log.Printf("  DELETE /api/v1/projects/{id} - Delete project (requires admin/owner)")
 	log.Printf("  POST /api/v1/projects/{id}/keys/regenerate - Regenerate project API key (requires admin/owner)")
-	log.Printf("  PUT  /api/v1/projects/{id}/configuration - Update project configuration (requires admin/owner)")
+	log.Printf("  PUT  /api/v1/projects/{id}/configuration - Update project configuration, runbook, ingestion IP allow/deny lists and context keys pinned to the issue list (requires admin/owner)")
+	log.Printf("  GET  /api/v1/projects/{id}/settings/history?setting= - Project setting change history (requires member access)")
+	log.Printf("  GET  /api/v1/projects/{id}/environments - Environments the project's data was sent from (requires member access)")
+	log.Printf("  GET  /api/v1/projects/{id}/stats/volume?since=&until=&environment=&release=&group_by=environment,release - Daily error event volume (requires member access)")
+	log.Printf("  GET  /api/v1/projects/{id}/stats/api-usage?since=&until= - API calls per user and ingest token (requires owner)")
+	log.Printf("  GET  /api/v1/projects/{id}/events/{event_id}/status - Whether an event was queued, stored, filtered or rate limited (requires member access)")
+	log.Printf("  GET  /api/v1/projects/{id}/inbound-filters - Inbound filters and events discarded per filter (requires member access)")
+	log.Printf("  PUT  /api/v1/projects/{id}/inbound-filters - Enable filters for extensions, crawlers, localhost, legacy browsers and error messages (requires admin/owner)")
+	log.Printf("  GET  /api/v1/projects/{id}/inbound-filters/blocklist - Message and exception type patterns discarding events (requires member access)")
+	log.Printf("  PUT  /api/v1/projects/{id}/inbound-filters/blocklist - Replace the glob or /regex/ message and exception type patterns (requires admin/owner)")
+	log.Printf("  POST /api/v1/projects/{id}/filters/test - Dry-run a sample event against the saved or given inbound filters (requires member access)")
+	log.Printf("  GET  /api/v1/projects/{id}/scrubbing-rules - Data scrubbing rules applied on top of the defaults (requires member access)")
+	log.Printf("  PUT  /api/v1/projects/{id}/scrubbing-rules - Replace the field selector rules masking, hashing or removing event data (requires admin/owner)")
+	log.Printf("  POST /api/v1/projects/{id}/scrubbing/test - Dry-run data scrubbing on a sample event, listing the redacted fields (requires member access)")
+	log.Printf("  GET  /api/v1/projects/{id}/ownership-rules - Path, module and tag rules auto-assigning new issues (requires member access)")
+	log.Printf("  PUT  /api/v1/projects/{id}/ownership-rules - Replace the ownership rules (requires admin/owner)")
+	log.Printf("  GET  /api/v1/projects/{id}/grouping - How events are grouped into issues (requires member access)")
+	log.Printf("  PUT  /api/v1/projects/{id}/grouping - Set the stack frames and message fingerprinted, or a fingerprint template (requires admin/owner)")
+	log.Printf("  POST /api/v1/projects/{id}/grouping/regroup - Regroup recent events with the current grouping config (requires admin/owner)")
+	log.Printf("  GET  /api/v1/projects/{id}/grouping/regroup - Progress of the latest regroup job (requires member access)")
+	log.Printf("  POST /api/v1/projects/{id}/fingerprint-preview - Explain how an event payload would be grouped (requires member access)")
+	log.Printf("Release endpoints:")
+	log.Printf("  GET  /api/v1/projects/{id}/releases?sort=version - List releases, newest or latest version first (requires member access or ingest token)")
+	log.Printf("  POST /api/v1/projects/{id}/releases - Create or update a release (requires member access or ingest token)")
+	log.Printf("  GET  /api/v1/projects/{id}/releases/latest - Latest release by semantic version (requires member access or ingest token)")
+	log.Printf("  GET  /api/v1/projects/{id}/releases/{version}/files - List release files (requires member access or ingest token)")
+	log.Printf("  POST /api/v1/projects/{id}/releases/{version}/files - Upload a release file such as a source map (requires member access or ingest token)")
 	log.Printf("Issue management endpoints:")
 	log.Printf("  GET  /api/v1/projects/{id}/issues - List project issues with filters (requires member access)")
 	log.Printf("  GET  /api/v1/projects/{id}/issues/stats - Get issue statistics (requires member access)")
-	log.Printf("  GET  /api/v1/issues/{id} - Get issue details (requires auth)")
-	log.Printf("  PUT  /api/v1/issues/{id} - Update issue status/assignment (requires auth)")
-	log.Printf("  POST /api/v1/issues/{id}/comments - Add comment to issue (requires auth)")
-	log.Printf("  GET  /api/v1/issues/{id}/comments - List issue comments (requires auth)")
-	log.Printf("  GET  /api/v1/issues/{id}/activity - Get issue activity timeline (requires auth)")
-	log.Printf("  GET  /api/v1/issues/{id}/events - List issue events (requires auth)")
-	log.Printf("  POST /api/v1/issues/bulk-update - Bulk update issues (requires auth)")
+	log.Printf("  GET  /api/v1/issues/{id} - Get issue details (requires member access)")
+	log.Printf("  PUT  /api/v1/issues/{id} - Update issue status/assignment (requires member access)")
+	log.Printf("  DELETE /api/v1/issues/{id} - Delete issue with its events, comments and activity (requires admin/owner)")
+	log.Printf("  POST /api/v1/issues/{id}/comments - Add comment to issue (requires member access)")
+	log.Printf("  GET  /api/v1/issues/{id}/comments - List issue comments (requires member access)")
+	log.Printf("  PUT  /api/v1/issues/{id}/comments/{comment_id} - Edit a comment, logging its previous content (requires author or admin/owner)")
+	log.Printf("  DELETE /api/v1/issues/{id}/comments/{comment_id} - Delete a comment, logging its content (requires author or admin/owner)")
+	log.Printf("  GET  /api/v1/issues/{id}/participants - List the users notified of an issue's status changes (requires member access)")
+	log.Printf("  POST /api/v1/issues/{id}/subscription - Subscribe to an issue's status changes (requires member access)")
+	log.Printf("  DELETE /api/v1/issues/{id}/subscription - Unsubscribe from an issue, also when commenting or assigned later (requires member access)")
+	log.Printf("  GET  /api/v1/issues/{id}/relations - List issue relations, ?depth= follows linked issues (requires member access)")
+	log.Printf("  POST /api/v1/issues/{id}/relations - Link issue as duplicate_of, blocked_by or related (requires member access)")
+	log.Printf("  DELETE /api/v1/issues/{id}/relations/{relation_id} - Remove an issue relation (requires member access)")
+	log.Printf("  GET  /api/v1/issues/{id}/activity - Get issue activity timeline (requires member access)")
+	log.Printf("  GET  /api/v1/issues/{id}/events - List issue events (requires member access)")
+	log.Printf("  GET  /api/v1/issues/{id}/events/{event_id}/attachments - List event attachments (requires member access)")
+	log.Printf("  GET  /api/v1/issues/{id}/events/{event_id}/attachments/{attachment_id} - Download an event attachment (requires member access)")
+	log.Printf("  POST /api/v1/issues/bulk-update - Bulk update issues, or queue their deletion (requires member access; admin/owner to delete)")
+	log.Printf("  POST /api/v1/issues/merge - Merge issues of a project into a primary issue (requires member access)")
+	log.Printf("  POST /api/v1/issues/{id}/unmerge - Split merged fingerprints or events of an issue into a new issue (requires member access)")
+	log.Printf("  GET  /api/v1/issues/{id}/similar - Suggest likely duplicates of an issue to merge (requires member access)")
+	log.Printf("  GET  /api/v1/issues/{id}/tags - Tags of an issue's events with their top values, ?top= (requires member access)")
+	log.Printf("  GET  /api/v1/issues/{id}/tags/{key}/values - Values of a tag of an issue's events with counts and percentages (requires member access)")
+	log.Printf("  PUT  /api/v1/issues/{id}/fingerprint-rule - Fold future events matching patterns into an issue (requires member access)")
+	log.Printf("  GET  /api/v1/issues/{id}/fingerprint-rule - Get the fingerprint rule of an issue (requires member access)")
+	log.Printf("  DELETE /api/v1/issues/{id}/fingerprint-rule - Remove the fingerprint rule of an issue (requires member access)")
+	log.Printf("  GET  /api/v1/projects/{id}/saved-searches - List your saved issue searches and the organization's (requires member access)")
+	log.Printf("  POST /api/v1/projects/{id}/saved-searches - Save issue filters, personal or shared with the organization (requires member access; admin/owner to share)")
+	log.Printf("  GET  /api/v1/organizations/{id}/saved-searches - List the organization's saved searches of every project (requires member access)")
+	log.Printf("  POST /api/v1/organizations/{id}/saved-searches - Save issue filters shared for every project (requires admin/owner)")
+	log.Printf("  PUT  /api/v1/saved-searches/{id} - Update a saved search (requires ownership; admin/owner for shared ones)")
+	log.Printf("  DELETE /api/v1/saved-searches/{id} - Delete a saved search (requires ownership; admin/owner for shared ones)")
+	log.Printf("Release health endpoints:")
+	log.Printf("  GET  /api/v1/projects/{id}/release-health - Crash-free sessions/users per release and environment (requires member access)")
+	log.Printf("Performance endpoints:")
+	log.Printf("  GET  /api/v1/projects/{id}/transactions - List transactions (requires member access)")
+	log.Printf("  GET  /api/v1/projects/{id}/transactions/summary - Duration summary per transaction name (requires member access)")
+	log.Printf("  GET  /api/v1/projects/{id}/transactions/{transaction_id} - Get transaction with spans (requires member access)")
+	log.Printf("Incident endpoints:")
+	log.Printf("  GET  /api/v1/organizations/{id}/incidents - List incidents (requires member access)")
+	log.Printf("  POST /api/v1/organizations/{id}/incidents - Open an incident grouping issues (requires member access)")
+	log.Printf("  GET  /api/v1/incidents/{id} - Get incident with its issues (requires member access)")
+	log.Printf("  PUT  /api/v1/incidents/{id} - Update incident title, description or status (requires member access)")
+	log.Printf("  POST /api/v1/incidents/{id}/issues - Add issues to an incident (requires member access)")
+	log.Printf("  DELETE /api/v1/incidents/{id}/issues/{issue_id} - Remove an issue from an incident (requires member access)")
+	log.Printf("  GET  /api/v1/incidents/{id}/timeline - Incident timeline (requires member access)")
+	log.Printf("  POST /api/v1/incidents/{id}/timeline - Post a note to the incident timeline (requires member access)")
+	log.Printf("  GET  /api/v1/incidents/{id}/event-rate - Combined event rate of the incident's issues (requires member access)")
+	log.Printf("Status page endpoints:")
+	log.Printf("  GET  /api/v1/organizations/{id}/status-page - Get status page configuration (requires member access)")
+	log.Printf("  PUT  /api/v1/organizations/{id}/status-page - Enable or configure the status page (requires admin/owner)")
+	log.Printf("  GET  /api/v1/organizations/{id}/status-page/components - List components with their status (requires member access)")
+	log.Printf("  POST /api/v1/organizations/{id}/status-page/components - Add a component mapped to projects (requires admin/owner)")
+	log.Printf("  PUT  /api/v1/organizations/{id}/status-page/components/{component_id} - Update a component (requires admin/owner)")
+	log.Printf("  DELETE /api/v1/organizations/{id}/status-page/components/{component_id} - Remove a component (requires admin/owner)")
+	log.Printf("  GET  /api/v1/organizations/{id}/status-page/subscribers - List email subscribers (requires admin/owner)")
+	log.Printf("  DELETE /api/v1/organizations/{id}/status-page/subscribers/{subscriber_id} - Remove a subscriber (requires admin/owner)")
+	log.Printf("  GET  /api/v1/status/{org_slug} - Public status page")
+	log.Printf("  POST /api/v1/status/{org_slug}/subscribe - Subscribe to status updates by email")
+	log.Printf("  GET  /api/v1/status/{org_slug}/confirm?token=... - Confirm an email subscription")
+	log.Printf("  GET  /api/v1/status/{org_slug}/unsubscribe?token=... - Unsubscribe from status updates")
+	log.Printf("Client report endpoints:")
+	log.Printf("  GET  /api/v1/projects/{id}/client-reports - Events discarded by SDKs per reason and category (requires member access)")
+	log.Printf("Issue sync endpoints:")
+	log.Printf("  GET  /api/v1/projects/{id}/issue-sync - Issue sync configuration and delivery state (requires member access)")
+	log.Printf("  PUT  /api/v1/projects/{id}/issue-sync - Configure the issue sync webhook, rotate its secret or move its cursor (requires admin/owner)")
+	log.Printf("  DELETE /api/v1/projects/{id}/issue-sync - Remove the issue sync (requires admin/owner)")
+	log.Printf("  GET  /api/v1/projects/{id}/issue-sync/deltas?cursor= - Issue create/update/resolve/assign deltas after a cursor (requires member access)")
+	log.Printf("Admin endpoints (require superuser):")
+	log.Printf("  GET  /api/v1/admin/projects/{id}/quota - Rate limit and daily quota applied to a project, with override history")
+	log.Printf("  POST /api/v1/admin/projects/{id}/quota-overrides - Temporarily override a project's rate limit or daily quota")
+	log.Printf("  DELETE /api/v1/admin/quota-overrides/{override_id} - Revoke a quota override before it expires")
+	log.Printf("  GET  /api/v1/admin/audit-log?project_id=&target_type=&action= - Audit log of administrative actions")
+	log.Printf("  GET  /api/v1/admin/maintenance - Active maintenance windows and those ended in the last day")
+	log.Printf("  POST /api/v1/admin/maintenance - Make an organization or the instance read-only, accepting, buffering or rejecting ingestion")
+	log.Printf("  DELETE /api/v1/admin/maintenance/{window_id} - End a maintenance window")
 	log.Printf("Error ingestion endpoints:")
-	log.Printf("  POST /api/{project_id}/store/ - Sentry-compatible error ingestion (requires DSN)")
+	log.Printf("  POST /api/{project_id}/store/ - Sentry-compatible error ingestion, 202 when INGEST_QUEUE is set (requires DSN)")
+	log.Printf("  POST /api/{project_id}/envelope/ - Sentry-compatible envelope ingestion (requires DSN)")
+	log.Printf("  POST /api/{project_id}/security/?sentry_key=... - CSP violation reports (requires DSN)")
+	log.Printf("  POST /api/{project_id}/minidump/?sentry_key=... - Native crash minidump uploads (requires DSN)")
+	log.Printf("  POST /api/{project_id}/integration/otlp/v1/logs - OpenTelemetry OTLP/HTTP log records with exceptions (requires DSN)")
+	if cfg.TunnelPath != "" {
+		log.Printf("  POST %s - SDK tunnel for envelopes (DSN read from envelope header)", cfg.TunnelPath)
+	}
 	log.Printf("  POST /api/v1/errors/ingest - Alternative error ingestion (requires DSN)")
+	log.Printf("  POST /api/v1/errors/bulk - Bulk error ingestion, one JSON event per line, with per-line results (requires DSN)")
+	log.Printf("  POST /api/v1/errors/logs - Structured log input from Logstash or Fluentd, error and fatal entries become events (requires DSN)")
 	log.Printf("  GET  /api/v1/errors/stats - Get error statistics (requires DSN)")
 	log.Printf("  GET  /api/v1/errors/issues/{issue_id}/events - Get issue events (requires DSN)")
 	
-	if err := http.ListenAndServe(addr, r); err != nil {
+	if cfg.MetricsPath != "" {
+		log.Printf("  GET  %s - Prometheus metrics", cfg.MetricsPath)
+	}
+	
+	server := &http.Server{Addr: addr, Handler: r}
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+	
+	var grpcServer *grpc.Server
+	if cfg.GRPCAddr != "" {
+		listener, err := net.Listen("tcp", cfg.GRPCAddr)
+		if err != nil {
+			log.Fatal("Failed to listen for gRPC ingestion:", err)
+		}
+		grpcServer = grpc.NewServer()
+		ingestpb.RegisterEventIngestServer(grpcServer, handlers.NewIngestGRPCServer(errorHandler, projectMiddleware))
+		log.Printf("Serving gRPC EventIngest on %s (requires DSN)", cfg.GRPCAddr)
+		go func() {
+			serverErr <- grpcServer.Serve(listener)
+		}()
+	}
+	
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	select {
+	case err := <-serverErr:
 		log.Fatal("Server failed to start:", err)
+	case <-ctx.Done():
+	}
+	
+	// Stop accepting connections and let in-flight requests finish
+	draining.Set(1)
+	log.Printf("Shutting down, draining %d ingestion and %d API requests in flight", ingestLimiter.InFlight(), apiLimiter.InFlight())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Shutdown timed out with %d ingestion and %d API requests in flight: %v", ingestLimiter.InFlight(), apiLimiter.InFlight(), err)
+	}
+	if grpcServer != nil {
+		// Streams are cut once the shutdown timeout is over
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			grpcServer.Stop()
+		}
+	}
+	errorService.FlushEvents()
+	if err := spikeProtectionService.FlushDrops(context.Background()); err != nil {
+		log.Printf("Failed to flush spike protection drops: %v", err)
+	}
+	if err := inboundFilterService.FlushStats(context.Background()); err != nil {
+		log.Printf("Failed to flush inbound filter stats: %v", err)
+	}
+	if err := eventVolumeService.FlushStats(context.Background()); err != nil {
+		log.Printf("Failed to flush event volume: %v", err)
+	}
+	if err := issueOutcomeService.FlushStats(context.Background()); err != nil {
+		log.Printf("Failed to flush issue outcomes: %v", err)
+	}
+	if err := apiUsageService.FlushStats(context.Background()); err != nil {
+		log.Printf("Failed to flush API usage: %v", err)
+	}
+	if issueCounterBuffer != nil {
+		if err := issueCounterBuffer.Flush(context.Background()); err != nil {
+			log.Printf("Failed to flush issue counters: %v", err)
+		}
 	}
 }
\ No newline at end of file
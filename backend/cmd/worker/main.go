@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"minisentry/internal/config"
+	"minisentry/internal/database"
+	"minisentry/internal/mail"
+	"minisentry/internal/queue"
+	"minisentry/internal/scheduler"
+	"minisentry/internal/services"
+)
+
+// The ingestion worker consumes the Redis or Kafka ingest queue filled by API servers
+// running with INGEST_WORKERS=0, so ingestion can be scaled separately from the API.
+func main() {
+	cfg := config.Load()
+
+	if cfg.IngestQueue != "redis" && cfg.IngestQueue != "kafka" {
+		log.Fatalf("INGEST_QUEUE must be redis or kafka to run standalone workers (got %q)", cfg.IngestQueue)
+	}
+	if cfg.IngestWorkers < 1 {
+		cfg.IngestWorkers = 1
+	}
+
+	db, err := database.Open(cfg.DatabaseDriver, cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer db.Close()
+
+	// Ingestion side effects: alert storms, status page notifications and issue sync deltas.
+	// Issue sync webhooks are delivered by the API server; outbox messages by both.
+	errorService := services.NewErrorService(db)
+	errorService.SetEventLimits(services.EventLimits{
+		MaxEventSize:     cfg.MaxEventSize,
+		MaxMessageLength: cfg.MaxEventMessage,
+		MaxBreadcrumbs:   cfg.MaxEventBreadcrumbs,
+		MaxExtraSize:     cfg.MaxEventExtraSize,
+		MaxContextsSize:  cfg.MaxEventContextsSize,
+	})
+	if cfg.DataScrubbing {
+		errorService.SetDataScrubber(services.NewDataScrubber(cfg.SensitiveFields))
+	}
+	if cfg.GeoIPDatabase != "" {
+		geoIP, err := services.OpenGeoIP(cfg.GeoIPDatabase)
+		if err != nil {
+			log.Fatal("Failed to open GEOIP_DATABASE:", err)
+		}
+		defer geoIP.Close()
+		errorService.SetGeoIP(geoIP)
+	}
+	eventDedupCache, err := services.OpenEventDedupCache(cfg.EventDedupCache, cfg.EventDedupCacheSize, cfg.RedisURL, cfg.EventDedupKeyPrefix, cfg.EventDedupTTL)
+	if err != nil {
+		log.Fatal("Failed to open the event dedup cache:", err)
+	}
+	if eventDedupCache != nil {
+		errorService.SetEventDedupCache(eventDedupCache)
+	}
+	eventStatusTracker, err := services.OpenEventStatusTracker(cfg.EventStatusTracker, cfg.EventStatusTrackerSize, cfg.RedisURL, cfg.EventStatusKeyPrefix, cfg.EventStatusTTL)
+	if err != nil {
+		log.Fatal("Failed to open the event status tracker:", err)
+	}
+	if eventStatusTracker != nil {
+		errorService.SetEventStatusTracker(eventStatusTracker)
+	}
+	issueCounterBuffer, err := services.OpenIssueCounterBuffer(cfg.IssueCounterBuffer, cfg.RedisURL, cfg.IssueCounterKeyPrefix)
+	if err != nil {
+		log.Fatal("Failed to open the issue counter buffer:", err)
+	}
+	if issueCounterBuffer != nil {
+		errorService.SetIssueCounterBuffer(issueCounterBuffer)
+	}
+	inboundFilterService := services.NewInboundFilterService(db)
+	errorService.SetInboundFilters(inboundFilterService)
+	eventVolumeService := services.NewEventVolumeService(db)
+	errorService.SetEventVolume(eventVolumeService)
+	issueOutcomeService := services.NewIssueOutcomeService(db)
+	errorService.SetIssueOutcomes(issueOutcomeService)
+	scrubbingRuleService := services.NewScrubbingRuleService(db)
+	errorService.SetScrubbingRules(scrubbingRuleService)
+	errorService.SetGroupingConfigs(services.NewGroupingConfigService(db))
+	errorService.SetOwnership(services.NewOwnershipService(db))
+	errorService.SetEventSampler(services.NewEventSampler(db))
+	errorService.SetMaintenance(services.NewMaintenanceService(db))
+	incidentService := services.NewIncidentService(db, services.AlertStormConfig{
+		Threshold: cfg.IncidentStormThreshold,
+		Window:    cfg.IncidentStormWindow,
+	})
+	errorService.OnIssueCreated(incidentService.DetectAlertStorm)
+	outboxService := services.NewOutboxService(db, mail.New(mail.SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.EmailFrom,
+	}), cfg.OutboxMaxAttempts)
+	statusPageService := services.NewStatusPageService(db, outboxService, cfg.PublicURL)
+	incidentService.OnIncidentChange(statusPageService.NotifyIncidentChange)
+	issueSyncService := services.NewIssueSyncService(db)
+	errorService.OnIssueCreated(issueSyncService.RecordIssueCreated)
+	errorService.OnIssueRegressed(issueSyncService.RecordIssueRegressed)
+	errorService.OnIssueRegressed(services.NewIssueSubscriptionService(db, outboxService, cfg.PublicURL).NotifyIssueRegressed)
+
+	ingestQueue, err := queue.Open(cfg.IngestQueue, queue.Options{
+		RedisURL:     cfg.RedisURL,
+		RedisKey:     cfg.IngestQueueKey,
+		KafkaBrokers: cfg.KafkaBrokers,
+		KafkaTopic:   cfg.KafkaTopic,
+		KafkaGroup:   cfg.KafkaGroup,
+	})
+	if err != nil {
+		log.Fatal("Failed to open the ingest queue:", err)
+	}
+	defer ingestQueue.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Batching outlives the signal context so events buffered by in-flight jobs are flushed below
+	errorService.StartEventBatching(context.Background(), services.EventBatchConfig{
+		Size:          cfg.EventBatchSize,
+		FlushInterval: cfg.EventBatchFlushInterval,
+	})
+	ingestQueue.Start(ctx, cfg.IngestWorkers, errorService.ProcessQueuedEvent)
+
+	jobs := scheduler.New()
+	jobs.Every("drain-outbox", cfg.OutboxPollInterval, outboxService.Drain)
+	jobs.Every("flush-inbound-filter-stats", time.Minute, inboundFilterService.FlushStats)
+	jobs.Every("flush-event-volume", time.Minute, eventVolumeService.FlushStats)
+	jobs.Every("flush-issue-outcomes", time.Minute, issueOutcomeService.FlushStats)
+	if issueCounterBuffer != nil {
+		jobs.Every("flush-issue-counters", cfg.IssueCounterFlushInterval, issueCounterBuffer.Flush)
+	}
+	jobs.Start(ctx)
+	log.Printf("Ingestion worker consuming the %s queue with %d workers", cfg.IngestQueue, cfg.IngestWorkers)
+
+	<-ctx.Done()
+	log.Println("Shutting down, waiting for in-flight events")
+	ingestQueue.Wait()
+	errorService.FlushEvents()
+	jobs.Wait()
+	if err := inboundFilterService.FlushStats(context.Background()); err != nil {
+		log.Printf("Failed to flush inbound filter stats: %v", err)
+	}
+	if err := eventVolumeService.FlushStats(context.Background()); err != nil {
+		log.Printf("Failed to flush event volume: %v", err)
+	}
+	if err := issueOutcomeService.FlushStats(context.Background()); err != nil {
+		log.Printf("Failed to flush issue outcomes: %v", err)
+	}
+	if issueCounterBuffer != nil {
+		if err := issueCounterBuffer.Flush(context.Background()); err != nil {
+			log.Printf("Failed to flush issue counters: %v", err)
+		}
+	}
+}
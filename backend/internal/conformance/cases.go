@@ -0,0 +1,154 @@
+package conformance
+
+// Cases are the recorded SDK payloads and the values they must produce.
+// Update an expectation only when the normalization change behind it is intended.
+var Cases = []Case{
+	{
+		Fixture: "javascript/store_exception.json",
+		Want: Expectation{
+			Title:         "TypeError: Cannot read properties of undefined (reading 'foo')",
+			Culprit:       "handleClick at main.js:42",
+			Level:         "error",
+			ExceptionType: "TypeError",
+			Environment:   "production",
+			Release:       "frontend@1.4.2",
+		},
+	},
+	{
+		Fixture: "javascript/envelope_event.envelope",
+		Want: Expectation{
+			Title:         "Error: Request failed with status code 500",
+			Culprit:       "fetchProfile at api.js:12",
+			Level:         "error",
+			ExceptionType: "Error",
+			Environment:   "staging",
+			Release:       "frontend@1.5.0",
+		},
+	},
+	{
+		Fixture: "python/store_exception.json",
+		Want: Expectation{
+			Title:         "ZeroDivisionError: division by zero",
+			Culprit:       "checkout at views.py:27",
+			Level:         "error",
+			ExceptionType: "ZeroDivisionError",
+			Environment:   "production",
+			Release:       "backend@2.0.1",
+		},
+	},
+	{
+		Fixture: "python/store_logging.json",
+		Want: Expectation{
+			Title:       "Payment %s declined by provider",
+			Level:       "error",
+			Message:     "Payment %s declined by provider",
+			Environment: "production",
+			Release:     "backend@2.0.1",
+		},
+	},
+	{
+		Fixture: "python/store_chained_exception.json",
+		Want: Expectation{
+			Title:         "OperationalError: could not connect to the orders database",
+			Culprit:       "connection at db.py:22",
+			Level:         "error",
+			ExceptionType: "OperationalError",
+			Environment:   "production",
+			Release:       "backend@2.0.1",
+		},
+	},
+	{
+		// Different exceptions thrown from the same cause are different issues by default...
+		Fixture: "python/store_chained_exception_task.json",
+		Before:  "python/store_chained_exception.json",
+		Want: Expectation{
+			Title:         "SyncFailed: inventory sync failed",
+			ExceptionType: "SyncFailed",
+			Issues:        2,
+		},
+	},
+	{
+		// ...and one issue when grouped by their root cause
+		Fixture:  "python/store_chained_exception_task.json",
+		Before:   "python/store_chained_exception.json",
+		Grouping: `{"group_by_root_cause":true}`,
+		Want: Expectation{
+			Title:         "OperationalError: could not connect to the orders database",
+			ExceptionType: "SyncFailed",
+			Issues:        1,
+		},
+	},
+	{
+		Fixture: "python/envelope_message.envelope",
+		Want: Expectation{
+			Title:       "Nightly export finished with warnings",
+			Level:       "info",
+			Message:     "Nightly export finished with warnings",
+			Environment: "production",
+			Release:     "backend@2.0.1",
+		},
+	},
+	{
+		Fixture: "go/store_exception.json",
+		Want: Expectation{
+			Title:         "*errors.errorString: record not found",
+			Culprit:       "getUser at repo.go:42",
+			Level:         "error",
+			ExceptionType: "*errors.errorString",
+			Environment:   "production",
+			Release:       "api@1.2.0",
+		},
+	},
+	{
+		Fixture: "go/envelope_message.envelope",
+		Want: Expectation{
+			Title:       "cache miss rate above threshold",
+			Level:       "warning",
+			Message:     "cache miss rate above threshold",
+			Environment: "staging",
+			Release:     "api@1.2.0",
+		},
+	},
+	{
+		Fixture: "java/envelope_exception.envelope",
+		Want: Expectation{
+			Title:         `NullPointerException: Cannot invoke "com.example.model.User.getEmail()" because "user" is null`,
+			Culprit:       "notify at UserService.java:58",
+			Level:         "error",
+			ExceptionType: "NullPointerException",
+			Environment:   "production",
+			Release:       "orders-service@3.1.0",
+		},
+	},
+	{
+		Fixture: "java/envelope_message.envelope",
+		Want: Expectation{
+			Title:       "Inventory sync skipped 3 SKUs",
+			Level:       "warning",
+			Message:     "Inventory sync skipped 3 SKUs",
+			Environment: "production",
+			Release:     "inventory@0.9.4",
+		},
+	},
+	{
+		Fixture: "php/store_exception.json",
+		Want: Expectation{
+			Title:         "PDOException: SQLSTATE[HY000] [2002] Connection refused",
+			Culprit:       `App\Repositories\CartRepository::connect at CartRepository.php:31`,
+			Level:         "error",
+			ExceptionType: "PDOException",
+			Environment:   "production",
+			Release:       "storefront@5.2.0",
+		},
+	},
+	{
+		Fixture: "php/store_message.json",
+		Want: Expectation{
+			Title:       "Queue emails is backing up",
+			Level:       "warning",
+			Message:     "Queue emails is backing up",
+			Environment: "production",
+			Release:     "storefront@5.2.0",
+		},
+	},
+}
@@ -0,0 +1,285 @@
+// Package conformance replays recorded Sentry SDK payloads through the real ingestion
+// handlers against an in-memory SQLite database and checks what ends up stored.
+// It guards normalization changes from silently breaking a platform.
+package conformance
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"minisentry/internal/database"
+	"minisentry/internal/handlers"
+	"minisentry/internal/middleware"
+	"minisentry/internal/models"
+	"minisentry/internal/services"
+	"minisentry/internal/storage"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/datatypes"
+	"gorm.io/gorm/logger"
+)
+
+//go:embed fixtures
+var fixtures embed.FS
+
+// publicKey is the DSN key of the project fixtures are sent to
+const publicKey = "0123456789abcdef0123456789abcdef"
+
+// Case describes one recorded payload and the outcome it must produce
+type Case struct {
+	// Fixture is the path below fixtures/; .json files go to /store/, .envelope files to /envelope/
+	Fixture string
+	// Before is a fixture sent to the project first, e.g. an event Fixture must group with
+	Before string
+	// Grouping is the grouping config of the project as stored; empty for the defaults
+	Grouping string
+	Want     Expectation
+}
+
+// Name identifies the case in results
+func (c Case) Name() string {
+	name := c.Fixture
+	if c.Before != "" {
+		name = c.Before + " then " + name
+	}
+	if c.Grouping != "" {
+		name += " with grouping " + c.Grouping
+	}
+	return name
+}
+
+// Expectation lists the stored values a fixture must produce; empty strings are not checked
+type Expectation struct {
+	Status        int
+	Title         string
+	Culprit       string
+	Level         string
+	Message       string
+	ExceptionType string
+	Environment   string
+	Release       string
+	// Issues is the number of issues the project has after the case; 0 is not checked
+	Issues int
+}
+
+// Result is the outcome of running one case
+type Result struct {
+	Case     Case
+	Failures []string
+}
+
+// Passed reports whether the case met every expectation
+func (r Result) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// suite holds the server under test
+type suite struct {
+	db        *database.DB
+	server    *httptest.Server
+	projectID string
+}
+
+// Run executes the given cases, each against a fresh database
+func Run(cases []Case) ([]Result, error) {
+	results := make([]Result, 0, len(cases))
+	for _, c := range cases {
+		s, err := newSuite(c.Grouping)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, s.run(c))
+
+		if err := s.close(); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// newSuite starts a server with a fresh database holding one project, whose grouping config
+// is grouping when set
+func newSuite(grouping string) (*suite, error) {
+	db, err := database.ConnectSQLite(":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open conformance database: %w", err)
+	}
+	db.Logger = logger.Discard
+
+	org := models.Organization{Name: "Conformance", Slug: "conformance"}
+	if err := db.Create(&org).Error; err != nil {
+		return nil, fmt.Errorf("failed to seed organization: %w", err)
+	}
+
+	project := models.Project{
+		OrganizationID: org.ID,
+		Name:           "Conformance",
+		Slug:           "conformance",
+		Platform:       "other",
+		PublicKey:      publicKey,
+		SecretKey:      "fedcba9876543210fedcba9876543210",
+		IsActive:       true,
+	}
+	if grouping != "" {
+		project.GroupingConfig = datatypes.JSON(grouping)
+	}
+	if err := db.Create(&project).Error; err != nil {
+		return nil, fmt.Errorf("failed to seed project: %w", err)
+	}
+	project.DSN = fmt.Sprintf("http://%s@localhost/%s", publicKey, project.ID)
+	if err := db.Save(&project).Error; err != nil {
+		return nil, fmt.Errorf("failed to seed project DSN: %w", err)
+	}
+
+	projectService := services.NewProjectService(db, "localhost")
+	attachmentService := services.NewAttachmentService(db, storage.NewLocalBlobStore(filepath.Join(os.TempDir(), "minisentry-conformance")))
+	errorService := services.NewErrorService(db)
+	errorService.SetGroupingConfigs(services.NewGroupingConfigService(db))
+	errorHandler := handlers.NewErrorHandler(errorService, services.NewSessionService(db), services.NewTransactionService(db), services.NewReplayService(db), attachmentService, services.NewClientReportService(db))
+
+	r := chi.NewRouter()
+	errorHandler.RegisterRoutes(r, middleware.NewProjectMiddleware(projectService))
+
+	return &suite{db: db, server: httptest.NewServer(r), projectID: project.ID.String()}, nil
+}
+
+// close stops the server and closes the database of the suite
+func (s *suite) close() error {
+	s.server.Close()
+	return s.db.Close()
+}
+
+func (s *suite) run(c Case) Result {
+	result := Result{Case: c}
+	fail := func(format string, args ...interface{}) {
+		result.Failures = append(result.Failures, fmt.Sprintf(format, args...))
+	}
+
+	if c.Before != "" {
+		status, respBody, err := s.send(c.Before)
+		if err != nil {
+			fail("send %s: %v", c.Before, err)
+			return result
+		}
+		if status != http.StatusOK {
+			fail("%s: status = %d, want %d (body: %s)", c.Before, status, http.StatusOK, strings.TrimSpace(string(respBody)))
+			return result
+		}
+	}
+
+	status, respBody, err := s.send(c.Fixture)
+	if err != nil {
+		fail("send fixture: %v", err)
+		return result
+	}
+
+	wantStatus := c.Want.Status
+	if wantStatus == 0 {
+		wantStatus = http.StatusOK
+	}
+	if status != wantStatus {
+		fail("status = %d, want %d (body: %s)", status, wantStatus, strings.TrimSpace(string(respBody)))
+		return result
+	}
+	if wantStatus != http.StatusOK {
+		return result
+	}
+
+	var response struct {
+		ID      string `json:"id"`
+		EventID string `json:"event_id"`
+	}
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		fail("decode response: %v", err)
+		return result
+	}
+	eventID := response.EventID
+	if eventID == "" {
+		eventID = response.ID
+	}
+
+	var event models.Event
+	if err := s.db.Where("event_id = ?", eventID).First(&event).Error; err != nil {
+		fail("stored event %q not found: %v", eventID, err)
+		return result
+	}
+	var issue models.Issue
+	if err := s.db.First(&issue, "id = ?", event.IssueID).Error; err != nil {
+		fail("issue for event not found: %v", err)
+		return result
+	}
+
+	check := func(field, got, want string) {
+		if want != "" && got != want {
+			fail("%s = %q, want %q", field, got, want)
+		}
+	}
+	check("title", issue.Title, c.Want.Title)
+	check("culprit", deref(issue.Culprit), c.Want.Culprit)
+	check("level", string(event.Level), c.Want.Level)
+	check("message", deref(event.Message), c.Want.Message)
+	check("exception type", deref(event.ExceptionType), c.Want.ExceptionType)
+	check("environment", event.Environment, c.Want.Environment)
+	check("release", deref(event.ReleaseVersion), c.Want.Release)
+
+	if c.Want.Issues > 0 {
+		var issues int64
+		if err := s.db.Model(&models.Issue{}).Where("project_id = ?", s.projectID).Count(&issues).Error; err != nil {
+			fail("count issues: %v", err)
+		} else if int(issues) != c.Want.Issues {
+			fail("issues = %d, want %d", issues, c.Want.Issues)
+		}
+	}
+
+	return result
+}
+
+// send posts a fixture to the project: .json files to /store/, .envelope files to
+// /envelope/. It returns the status and body of the response.
+func (s *suite) send(fixture string) (int, []byte, error) {
+	body, err := fixtures.ReadFile(path.Join("fixtures", fixture))
+	if err != nil {
+		return 0, nil, fmt.Errorf("read fixture: %w", err)
+	}
+
+	endpoint := "/api/" + s.projectID + "/store/"
+	contentType := "application/json"
+	if strings.HasSuffix(fixture, ".envelope") {
+		endpoint = "/api/" + s.projectID + "/envelope/"
+		contentType = "application/x-sentry-envelope"
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.server.URL+endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Sentry-Auth", "Sentry sentry_version=7, sentry_client=conformance/1.0, sentry_key="+publicKey)
+	resp, err := s.server.Client().Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("read response: %w", err)
+	}
+	return resp.StatusCode, respBody, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
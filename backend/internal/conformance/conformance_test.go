@@ -0,0 +1,36 @@
+package conformance
+
+import (
+	"io"
+	"log"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// Keep the output focused on case results
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// TestCases replays every recorded payload through the ingestion handlers, so a
+// normalization change that breaks a platform fails the build
+func TestCases(t *testing.T) {
+	for _, c := range Cases {
+		t.Run(c.Name(), func(t *testing.T) {
+			s, err := newSuite(c.Grouping)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() {
+				if err := s.close(); err != nil {
+					t.Error(err)
+				}
+			})
+
+			for _, failure := range s.run(c).Failures {
+				t.Error(failure)
+			}
+		})
+	}
+}
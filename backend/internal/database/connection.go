@@ -10,10 +10,27 @@ import (
 	"gorm.io/gorm/logger"
 )
 
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 type DB struct {
 	*gorm.DB
 }
 
+// Open connects to the database using the configured driver
+func Open(driver, databaseURL string) (*DB, error) {
+	switch driver {
+	case DriverPostgres, "":
+		return Connect(databaseURL)
+	case DriverSQLite:
+		return ConnectSQLite(databaseURL)
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", driver)
+	}
+}
+
 func Connect(databaseURL string) (*DB, error) {
 	config := &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
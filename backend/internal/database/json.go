@@ -0,0 +1,21 @@
+package database
+
+import "gorm.io/gorm"
+
+// JSONText returns an SQL expression selecting the text of a top-level key of a JSON
+// column, with its argument, for the database in use. The key must not contain quotes.
+func JSONText(db *gorm.DB, column, key string) (string, interface{}) {
+	if db.Dialector.Name() == DriverSQLite {
+		return "json_extract(" + column + ", ?)", `$."` + key + `"`
+	}
+	return column + " ->> ?", key
+}
+
+// JSONEquals returns an SQL condition comparing a JSON column to a JSON document given as
+// its argument, for the database in use
+func JSONEquals(db *gorm.DB, column string) string {
+	if db.Dialector.Name() == DriverSQLite {
+		return column + " = ?"
+	}
+	return column + " = CAST(? AS jsonb)"
+}
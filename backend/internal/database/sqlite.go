@@ -0,0 +1,149 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+const sqliteDriverName = "sqlite3_minisentry"
+
+var registerSQLiteOnce sync.Once
+
+// sqliteModels lists every model whose table is created when running on SQLite.
+// The SQL migrations are Postgres-specific, so SQLite mode builds its schema from the models instead.
+var sqliteModels = []interface{}{
+	&models.User{},
+	&models.Organization{},
+	&models.OrganizationMember{},
+	&models.Project{},
+	&models.Issue{},
+	&models.Event{},
+	&models.IssueComment{},
+	&models.IssueCommentReaction{},
+	&models.IssueCommentMention{},
+	&models.IssueSubscription{},
+	&models.IssueRelation{},
+	&models.IssueActivity{},
+	&models.IssueMergedFingerprint{},
+	&models.IssueFingerprintRule{},
+	&models.GroupingRegroupJob{},
+	&models.Release{},
+	&models.Environment{},
+	&models.Session{},
+	&models.Transaction{},
+	&models.Span{},
+	&models.Replay{},
+	&models.ReplaySegment{},
+	&models.Attachment{},
+	&models.ClientReport{},
+	&models.Incident{},
+	&models.IncidentIssue{},
+	&models.IncidentActivity{},
+	&models.StatusPage{},
+	&models.StatusComponent{},
+	&models.StatusComponentProject{},
+	&models.StatusSubscriber{},
+	&models.IssueSyncIntegration{},
+	&models.IssueSyncDelta{},
+	&models.QuotaOverride{},
+	&models.AuditLogEntry{},
+	&models.OutboxMessage{},
+	&models.SpikeProtectionDrop{},
+	&models.OrgIngestToken{},
+	&models.ReleaseArtifact{},
+	&models.ProjectInboundFilters{},
+	&models.InboundFilterStat{},
+	&models.EventVolumeStat{},
+	&models.IssueOutcomeStat{},
+	&models.APIUsageStat{},
+	&models.MaintenanceWindow{},
+	&models.SavedSearch{},
+	&models.EventTag{},
+	&models.IssueTagValue{},
+}
+
+// ConnectSQLite opens a SQLite database (a file path or ":memory:") and creates the schema.
+// It is meant for local development and integration tests without Postgres.
+func ConnectSQLite(databaseURL string) (*DB, error) {
+	registerSQLiteOnce.Do(registerSQLiteDriver)
+
+	config := &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Warn),
+		NowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+
+	dialector := sqliteDialector{sqlite.Dialector{DriverName: sqliteDriverName, DSN: databaseURL}}
+	db, err := gorm.Open(dialector, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get underlying sql.DB: %w", err)
+	}
+
+	// A single connection keeps ":memory:" databases shared and avoids SQLITE_BUSY on writes
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(sqliteModels...); err != nil {
+		return nil, fmt.Errorf("failed to create sqlite schema: %w", err)
+	}
+
+	log.Printf("Using SQLite database at %s", databaseURL)
+	return &DB{db}, nil
+}
+
+// registerSQLiteDriver registers a SQLite driver providing the Postgres functions used as column defaults
+func registerSQLiteDriver() {
+	sql.Register(sqliteDriverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			if err := conn.RegisterFunc("gen_random_uuid", func() string {
+				return uuid.New().String()
+			}, false); err != nil {
+				return err
+			}
+			return conn.RegisterFunc("now", func() string {
+				return time.Now().UTC().Format(sqlite3.SQLiteTimestampFormats[0])
+			}, false)
+		},
+	})
+}
+
+// sqliteDialector wraps the SQLite dialector to adapt Postgres-style column defaults
+type sqliteDialector struct {
+	sqlite.Dialector
+}
+
+func (d sqliteDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return sqliteMigrator{d.Dialector.Migrator(db)}
+}
+
+type sqliteMigrator struct {
+	gorm.Migrator
+}
+
+// FullDataTypeOf wraps function-call defaults such as now() in parentheses, which SQLite requires
+func (m sqliteMigrator) FullDataTypeOf(field *schema.Field) clause.Expr {
+	expr := m.Migrator.FullDataTypeOf(field)
+	if strings.HasSuffix(field.DefaultValue, "()") {
+		expr.SQL = strings.Replace(expr.SQL, "DEFAULT "+field.DefaultValue, "DEFAULT ("+field.DefaultValue+")", 1)
+	}
+	return expr
+}
@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuotaOverrideRequest represents the request payload for a temporary quota override.
+// Duration is a Go duration such as "24h"; the override ends when it elapses.
+type QuotaOverrideRequest struct {
+	Kind     string  `json:"kind" validate:"required,oneof=rate_limit daily_quota"`
+	Value    *int    `json:"value" validate:"required,min=0"`
+	Duration string  `json:"duration" validate:"required"`
+	Reason   *string `json:"reason,omitempty"`
+}
+
+// QuotaOverrideResponse represents a quota override
+type QuotaOverrideResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	ProjectID uuid.UUID  `json:"project_id"`
+	Kind      string     `json:"kind"`
+	Value     int        `json:"value"`
+	Reason    *string    `json:"reason"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Active    bool       `json:"active"`
+	CreatedBy *uuid.UUID `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ProjectQuotaResponse represents the ingestion limits currently applied to a project,
+// with the configured defaults and the project's override history. A limit of 0 is unlimited.
+type ProjectQuotaResponse struct {
+	ProjectID         uuid.UUID               `json:"project_id"`
+	RateLimit         int                     `json:"rate_limit"`
+	RateLimitWindow   int                     `json:"rate_limit_window_seconds"`
+	DailyQuota        int                     `json:"daily_quota"`
+	DefaultRateLimit  int                     `json:"default_rate_limit"`
+	DefaultDailyQuota int                     `json:"default_daily_quota"`
+	Overrides         []QuotaOverrideResponse `json:"overrides"`
+}
+
+// AuditLogFilters represents query parameters for listing audit log entries
+type AuditLogFilters struct {
+	ProjectID  *uuid.UUID `json:"project_id,omitempty"`
+	TargetType string     `json:"target_type,omitempty"`
+	TargetID   *uuid.UUID `json:"target_id,omitempty"`
+	Action     string     `json:"action,omitempty"`
+	Page       int        `json:"page"`
+	Limit      int        `json:"limit"`
+}
+
+// AuditLogEntryResponse represents an audit log entry
+type AuditLogEntryResponse struct {
+	ID             uuid.UUID       `json:"id"`
+	ActorID        *uuid.UUID      `json:"actor_id"`
+	Action         string          `json:"action"`
+	TargetType     string          `json:"target_type"`
+	TargetID       uuid.UUID       `json:"target_id"`
+	OrganizationID *uuid.UUID      `json:"organization_id"`
+	ProjectID      *uuid.UUID      `json:"project_id"`
+	Data           json.RawMessage `json:"data"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// AuditLogListResponse represents paginated audit log entries, newest first
+type AuditLogListResponse struct {
+	Entries    []AuditLogEntryResponse `json:"entries"`
+	Total      int64                   `json:"total"`
+	Page       int                     `json:"page"`
+	Limit      int                     `json:"limit"`
+	TotalPages int                     `json:"total_pages"`
+}
+
+// MaintenanceWindowRequest represents the request payload starting a maintenance window,
+// for one organization or, without OrganizationID, the whole instance
+type MaintenanceWindowRequest struct {
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty"`
+	Message        string     `json:"message,omitempty"`
+	Ingestion      string     `json:"ingestion,omitempty" validate:"omitempty,oneof=accept buffer reject"`
+}
+
+// MaintenanceWindowResponse represents a maintenance window
+type MaintenanceWindowResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	OrganizationID *uuid.UUID `json:"organization_id"`
+	Message        string     `json:"message"`
+	Ingestion      string     `json:"ingestion"`
+	Active         bool       `json:"active"`
+	StartedBy      *uuid.UUID `json:"started_by"`
+	StartedAt      time.Time  `json:"started_at"`
+	EndedAt        *time.Time `json:"ended_at"`
+	EndedBy        *uuid.UUID `json:"ended_by"`
+}
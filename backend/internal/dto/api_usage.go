@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIUsageFilters represents query parameters for the API usage of an organization or project
+type APIUsageFilters struct {
+	Since time.Time `json:"since"` // First day counted, in UTC
+	Until time.Time `json:"until"` // Last day counted, in UTC
+}
+
+// APIUsageRoute is the calls a caller made to a route, by status class
+type APIUsageRoute struct {
+	Route    string           `json:"route"` // method and route pattern, e.g. GET /api/v1/issues/{issue_id}
+	Calls    int64            `json:"calls"`
+	Statuses map[string]int64 `json:"statuses"` // 2xx, 3xx, 4xx, 429 and 5xx
+}
+
+// APIUsageDay is the calls a caller made on a UTC day
+type APIUsageDay struct {
+	Day   time.Time `json:"day"`
+	Calls int64     `json:"calls"`
+}
+
+// APIUsageCaller is the API usage of a user or ingest token. Name is the user's name or
+// email, or the token's name; it is "" for deleted users and tokens.
+type APIUsageCaller struct {
+	Type     string           `json:"type"` // user or ingest_token
+	ID       uuid.UUID        `json:"id"`
+	Name     string           `json:"name"`
+	Calls    int64            `json:"calls"`
+	Statuses map[string]int64 `json:"statuses"`
+	Routes   []APIUsageRoute  `json:"routes"` // most called first
+	Days     []APIUsageDay    `json:"days"`   // days without calls are left out
+}
+
+// APIUsageResponse is the API usage of an organization or project between two days, per
+// caller, most active first
+type APIUsageResponse struct {
+	OrganizationID uuid.UUID        `json:"organization_id"`
+	ProjectID      *uuid.UUID       `json:"project_id,omitempty"`
+	Since          time.Time        `json:"since"`
+	Until          time.Time        `json:"until"`
+	TotalCalls     int64            `json:"total_calls"`
+	Callers        []APIUsageCaller `json:"callers"`
+}
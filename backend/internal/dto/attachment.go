@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AttachmentResponse describes a file uploaded with an event
+type AttachmentResponse struct {
+	ID             uuid.UUID `json:"id"`
+	EventID        uuid.UUID `json:"event_id"`
+	Name           string    `json:"name"`
+	ContentType    string    `json:"content_type"`
+	AttachmentType string    `json:"attachment_type"`
+	Size           int64     `json:"size"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// AttachmentListResponse lists the attachments of an event
+type AttachmentListResponse struct {
+	Attachments []AttachmentResponse `json:"attachments"`
+}
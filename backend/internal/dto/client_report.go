@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+// DiscardedEvent counts events of one category an SDK dropped for the same reason
+type DiscardedEvent struct {
+	Reason   string `json:"reason"`
+	Category string `json:"category"`
+	Quantity int64  `json:"quantity"`
+}
+
+// ClientReport represents a `client_report` envelope item sent by SDKs
+type ClientReport struct {
+	Timestamp       *time.Time       `json:"timestamp,omitempty"`
+	DiscardedEvents []DiscardedEvent `json:"discarded_events"`
+}
+
+// ClientReportFilters represents query parameters for client report stats
+type ClientReportFilters struct {
+	Category string    `json:"category,omitempty"`
+	Since    time.Time `json:"since"`
+}
+
+// ClientReportReasonStats is the number of discarded events for one reason
+type ClientReportReasonStats struct {
+	Reason   string `json:"reason"`
+	Quantity int64  `json:"quantity"`
+}
+
+// ClientReportCategoryStats is the number of discarded events for one data category
+type ClientReportCategoryStats struct {
+	Category string `json:"category"`
+	Quantity int64  `json:"quantity"`
+}
+
+// ClientReportStatsResponse summarizes the events SDKs discarded since a point in time
+type ClientReportStatsResponse struct {
+	Since          time.Time                   `json:"since"`
+	TotalDiscarded int64                       `json:"total_discarded"`
+	ByReason       []ClientReportReasonStats   `json:"by_reason"`
+	ByCategory     []ClientReportCategoryStats `json:"by_category"`
+	Outcomes       []DiscardedEvent            `json:"outcomes"`
+}
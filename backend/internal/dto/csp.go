@@ -0,0 +1,101 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// CSPReport represents a Content-Security-Policy violation as sent by browsers
+type CSPReport struct {
+	DocumentURI        string `json:"document-uri"`
+	Referrer           string `json:"referrer,omitempty"`
+	ViolatedDirective  string `json:"violated-directive,omitempty"`
+	EffectiveDirective string `json:"effective-directive,omitempty"`
+	OriginalPolicy     string `json:"original-policy,omitempty"`
+	BlockedURI         string `json:"blocked-uri,omitempty"`
+	SourceFile         string `json:"source-file,omitempty"`
+	LineNumber         *int   `json:"line-number,omitempty"`
+	ColumnNumber       *int   `json:"column-number,omitempty"`
+	StatusCode         *int   `json:"status-code,omitempty"`
+	ScriptSample       string `json:"script-sample,omitempty"`
+	Disposition        string `json:"disposition,omitempty"`
+}
+
+// CSPReportRequest is the `application/csp-report` body sent for the report-uri directive
+type CSPReportRequest struct {
+	CSPReport *CSPReport `json:"csp-report"`
+}
+
+// reportingAPIReport is a single entry of an `application/reports+json` body (report-to directive)
+type reportingAPIReport struct {
+	Type string              `json:"type"`
+	URL  string              `json:"url"`
+	Body reportingAPICSPBody `json:"body"`
+}
+
+// reportingAPICSPBody is the camelCase violation body used by the Reporting API
+type reportingAPICSPBody struct {
+	DocumentURL        string `json:"documentURL"`
+	Referrer           string `json:"referrer"`
+	BlockedURL         string `json:"blockedURL"`
+	EffectiveDirective string `json:"effectiveDirective"`
+	OriginalPolicy     string `json:"originalPolicy"`
+	SourceFile         string `json:"sourceFile"`
+	Sample             string `json:"sample"`
+	Disposition        string `json:"disposition"`
+	StatusCode         *int   `json:"statusCode"`
+	LineNumber         *int   `json:"lineNumber"`
+	ColumnNumber       *int   `json:"columnNumber"`
+}
+
+// ParseCSPReports parses a CSP violation body in either the legacy report-uri format
+// or the Reporting API format, which batches several reports in one array
+func ParseCSPReports(data []byte) ([]CSPReport, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("CSP report body is empty")
+	}
+
+	if trimmed[0] == '[' {
+		var entries []reportingAPIReport
+		if err := json.Unmarshal(trimmed, &entries); err != nil {
+			return nil, fmt.Errorf("invalid CSP report: %w", err)
+		}
+
+		reports := make([]CSPReport, 0, len(entries))
+		for _, entry := range entries {
+			if entry.Type != "csp-violation" {
+				continue
+			}
+			documentURI := entry.Body.DocumentURL
+			if documentURI == "" {
+				documentURI = entry.URL
+			}
+			reports = append(reports, CSPReport{
+				DocumentURI:        documentURI,
+				Referrer:           entry.Body.Referrer,
+				ViolatedDirective:  entry.Body.EffectiveDirective,
+				EffectiveDirective: entry.Body.EffectiveDirective,
+				OriginalPolicy:     entry.Body.OriginalPolicy,
+				BlockedURI:         entry.Body.BlockedURL,
+				SourceFile:         entry.Body.SourceFile,
+				LineNumber:         entry.Body.LineNumber,
+				ColumnNumber:       entry.Body.ColumnNumber,
+				StatusCode:         entry.Body.StatusCode,
+				ScriptSample:       entry.Body.Sample,
+				Disposition:        entry.Body.Disposition,
+			})
+		}
+		return reports, nil
+	}
+
+	var request CSPReportRequest
+	if err := json.Unmarshal(trimmed, &request); err != nil {
+		return nil, fmt.Errorf("invalid CSP report: %w", err)
+	}
+	if request.CSPReport == nil {
+		return nil, fmt.Errorf("invalid CSP report: missing csp-report object")
+	}
+	return []CSPReport{*request.CSPReport}, nil
+}
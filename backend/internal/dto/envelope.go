@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EnvelopeHeader represents the first line of a Sentry envelope
+type EnvelopeHeader struct {
+	EventID *string    `json:"event_id,omitempty"`
+	DSN     string     `json:"dsn,omitempty"`
+	SentAt  *time.Time `json:"sent_at,omitempty"`
+}
+
+// EnvelopeItemHeader represents the header line preceding each envelope item
+type EnvelopeItemHeader struct {
+	Type        string `json:"type"`
+	Length      *int   `json:"length,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Filename    string `json:"filename,omitempty"`
+	// AttachmentType is set on attachment items, e.g. event.attachment or event.minidump
+	AttachmentType string `json:"attachment_type,omitempty"`
+}
+
+// EnvelopeItem represents a single item (event, session, attachment...) in an envelope
+type EnvelopeItem struct {
+	Header  EnvelopeItemHeader
+	Payload []byte
+}
+
+// Envelope represents a parsed Sentry envelope
+type Envelope struct {
+	Header EnvelopeHeader
+	Items  []EnvelopeItem
+}
+
+// EnvelopeResponse represents the response returned for an accepted envelope
+type EnvelopeResponse struct {
+	ID string `json:"id"`
+}
+
+// ParseEnvelope parses a newline-delimited Sentry envelope
+func ParseEnvelope(data []byte) (*Envelope, error) {
+	headerLine, rest := splitEnvelopeLine(data)
+	if len(bytes.TrimSpace(headerLine)) == 0 {
+		return nil, fmt.Errorf("envelope header is empty")
+	}
+
+	envelope := &Envelope{}
+	if err := json.Unmarshal(headerLine, &envelope.Header); err != nil {
+		return nil, fmt.Errorf("invalid envelope header: %w", err)
+	}
+
+	for len(rest) > 0 {
+		var itemHeaderLine []byte
+		itemHeaderLine, rest = splitEnvelopeLine(rest)
+		if len(bytes.TrimSpace(itemHeaderLine)) == 0 {
+			continue
+		}
+
+		var item EnvelopeItem
+		if err := json.Unmarshal(itemHeaderLine, &item.Header); err != nil {
+			return nil, fmt.Errorf("invalid envelope item header: %w", err)
+		}
+
+		if item.Header.Length != nil {
+			length := *item.Header.Length
+			if length < 0 || length > len(rest) {
+				return nil, fmt.Errorf("envelope item length %d exceeds remaining payload", length)
+			}
+			item.Payload = rest[:length]
+			rest = rest[length:]
+			// Skip the newline terminating the payload, if present
+			if len(rest) > 0 && rest[0] == '\n' {
+				rest = rest[1:]
+			}
+		} else {
+			item.Payload, rest = splitEnvelopeLine(rest)
+		}
+
+		envelope.Items = append(envelope.Items, item)
+	}
+
+	return envelope, nil
+}
+
+// splitEnvelopeLine returns the bytes up to the next newline and the remainder after it
+func splitEnvelopeLine(data []byte) ([]byte, []byte) {
+	if idx := bytes.IndexByte(data, '\n'); idx != -1 {
+		return data[:idx], data[idx+1:]
+	}
+	return data, nil
+}
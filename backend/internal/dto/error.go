@@ -16,6 +16,7 @@ type ErrorEventRequest struct {
 	Release     *string           `json:"release,omitempty"`
 	Environment *string           `json:"environment,omitempty"`
 	ServerName  *string           `json:"server_name,omitempty"`
+	Transaction *string           `json:"transaction,omitempty"`
 	Message     *MessageData      `json:"message,omitempty"`
 	Exception   *ExceptionData    `json:"exception,omitempty"`
 	User        *UserContext      `json:"user,omitempty"`
@@ -26,6 +27,17 @@ type ErrorEventRequest struct {
 	Contexts    map[string]interface{} `json:"contexts,omitempty"`
 	Fingerprint []string          `json:"fingerprint,omitempty"`
 	Modules     map[string]string `json:"modules,omitempty"`
+	SDK         *SDKInfo          `json:"sdk,omitempty"`
+
+	// OriginalTimestamp is the timestamp sent by the SDK when ingestion corrected its
+	// clock skew; it is set on receipt, never taken from SDKs
+	OriginalTimestamp *time.Time `json:"original_timestamp,omitempty"`
+}
+
+// SDKInfo identifies the SDK that sent an event
+type SDKInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
 }
 
 // MessageData represents structured message information
@@ -56,6 +68,11 @@ type MechanismData struct {
 	HelpLink    *string                `json:"help_link,omitempty"`
 	Handled     *bool                  `json:"handled,omitempty"`
 	Data        map[string]interface{} `json:"data,omitempty"`
+
+	// ExceptionID and ParentID link the exceptions of a chain: a cause's ParentID is the
+	// ExceptionID of the exception it caused
+	ExceptionID *int `json:"exception_id,omitempty"`
+	ParentID    *int `json:"parent_id,omitempty"`
 }
 
 // StacktraceData represents stack trace information
@@ -129,6 +146,14 @@ type ErrorEventResponse struct {
 	ProjectID uuid.UUID `json:"project_id"`
 	IssueID   uuid.UUID `json:"issue_id"`
 	CreatedAt time.Time `json:"created_at"`
+	Sampled   bool      `json:"sampled,omitempty"` // counted in the issue's stats but not stored
+}
+
+// FieldError is a problem with one field of a rejected event, such as exception.values,
+// returned to SDKs so integrators can fix their payloads
+type FieldError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
 }
 
 
@@ -147,6 +172,7 @@ type FingerprintComponents struct {
 	StackFrames   []string `json:"stack_frames"`
 	Platform      string   `json:"platform"`
 	Filename      string   `json:"filename"`
+	Transaction   string   `json:"transaction,omitempty"` // Only with the include_transaction grouping
 }
 
 // NormalizedErrorData represents cleaned error data ready for storage
@@ -169,4 +195,58 @@ type NormalizedErrorData struct {
 	Release         *string                `json:"release"`
 	ServerName      *string                `json:"server_name"`
 	Platform        string                 `json:"platform"`
-}
\ No newline at end of file
+	Culprit         *string                `json:"culprit,omitempty"` // Overrides the stack-derived culprit
+	ReplayID        *string                `json:"replay_id,omitempty"`
+	Transaction     *string                `json:"transaction,omitempty"` // Route the error happened on, IDs templated
+	Contexts        map[string]interface{} `json:"contexts,omitempty"`
+
+	// Exceptions is the whole exception chain, in the order sent (oldest first, the root cause
+	// first and the outermost wrapper last), when the exception has causes; ExceptionType,
+	// ExceptionValue and StackTrace describe its last exception, the one thrown
+	Exceptions []ExceptionValue `json:"exceptions,omitempty"`
+
+	// Meta describes what the event limits trimmed, keyed by field path like Sentry's _meta
+	Meta map[string]interface{} `json:"_meta,omitempty"`
+
+	// GroupingVersion is the project grouping config version the event was fingerprinted
+	// with; CustomFingerprint is set when Fingerprint was not computed with the grouping config
+	GroupingVersion   int  `json:"grouping_version"`
+	CustomFingerprint bool `json:"custom_fingerprint"`
+
+	// OriginalTimestamp is the timestamp as sent when its clock skew was corrected
+	OriginalTimestamp *time.Time `json:"original_timestamp,omitempty"`
+}
+
+// EventStatusResponse tells what became of an event sent to a project
+type EventStatusResponse struct {
+	EventID   string     `json:"event_id"`
+	Status    string     `json:"status"` // queued, stored, sampled, duplicate, filtered, dropped, invalid or rate_limited
+	Reason    string     `json:"reason,omitempty"`
+	IssueID   *uuid.UUID `json:"issue_id,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// BulkEventResult is the outcome of one line of a bulk error submission
+type BulkEventResult struct {
+	Line    int          `json:"line"` // 1-based, counting blank lines
+	EventID string       `json:"event_id,omitempty"`
+	Status  string       `json:"status"` // stored, sampled or queued when accepted
+	Error   string       `json:"error,omitempty"`
+	Errors  []FieldError `json:"errors,omitempty"`
+}
+
+// BulkEventResponse lists the outcome of every event of a bulk error submission
+type BulkEventResponse struct {
+	Accepted int               `json:"accepted"`
+	Rejected int               `json:"rejected"`
+	Results  []BulkEventResult `json:"results"`
+}
+
+// LogInputResponse lists the outcome of the error entries of a log input submission;
+// entries below the error level are only counted as ignored
+type LogInputResponse struct {
+	Accepted int               `json:"accepted"`
+	Rejected int               `json:"rejected"`
+	Ignored  int               `json:"ignored"`
+	Results  []BulkEventResult `json:"results"` // Line is the entry's line, or position in a JSON array
+}
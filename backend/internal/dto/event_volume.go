@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventVolumeFilters represents query parameters for a project's event volume
+type EventVolumeFilters struct {
+	Since        time.Time `json:"since"` // First day counted, in UTC
+	Until        time.Time `json:"until"` // Last day counted, in UTC
+	Environments []string  `json:"environments,omitempty"`
+	Releases     []string  `json:"releases,omitempty"` // "" selects events sent without a release
+	GroupBy      []string  `json:"group_by,omitempty"` // environment and/or release
+}
+
+// EventVolumePoint is the number of error events accepted in a day, for an environment and
+// release when the volume is grouped by them
+type EventVolumePoint struct {
+	Day         time.Time `json:"day"`
+	Environment *string   `json:"environment,omitempty"`
+	Release     *string   `json:"release,omitempty"`
+	Events      int64     `json:"events"`
+}
+
+// EventVolumeResponse is a project's daily error event volume. Days without events are left
+// out of the series.
+type EventVolumeResponse struct {
+	ProjectID   uuid.UUID          `json:"project_id"`
+	Since       time.Time          `json:"since"`
+	Until       time.Time          `json:"until"`
+	GroupBy     []string           `json:"group_by"`
+	TotalEvents int64              `json:"total_events"`
+	Series      []EventVolumePoint `json:"series"`
+}
@@ -0,0 +1,90 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GroupingConfig is how a project groups error events into issues
+type GroupingConfig struct {
+	StackFrames    int  `json:"stack_frames"`    // In-app stack frames fingerprinted
+	IncludeMessage bool `json:"include_message"` // Fingerprint the normalized error message
+	// Fingerprint the transaction, or the culprit of events sent without one, so the same
+	// error on different routes groups into different issues
+	IncludeTransaction bool `json:"include_transaction"`
+	// Fingerprint the innermost cause of chained exceptions instead of the exception thrown
+	GroupByRootCause bool `json:"group_by_root_cause"`
+	// Path patterns of frames treated as in-app, or not, when SDKs do not say; exclusions
+	// take precedence
+	InAppInclude []string `json:"in_app_include"`
+	InAppExclude []string `json:"in_app_exclude"`
+	// Function and module patterns of frames left out of fingerprints, e.g. framework
+	// internals and polyfills at the top of traces
+	IgnoreFrames []string `json:"ignore_frames"`
+	// Retitle issues after their latest event periodically, instead of keeping the title and
+	// culprit of their first event
+	RefreshIssueTitles bool `json:"refresh_issue_titles"`
+	// Fingerprint template of events sent without a fingerprint, e.g.
+	// ["{{ error.type }}", "{{ transaction }}"]; empty uses the default grouping
+	Fingerprint []string `json:"fingerprint"`
+	// Version counts the changes of the config; issues and events record the version they
+	// were grouped with
+	Version int `json:"version"`
+}
+
+// GroupingConfigRequest represents the request payload updating a project's grouping
+// config; fields left out keep their value
+type GroupingConfigRequest struct {
+	StackFrames        *int      `json:"stack_frames,omitempty"` // 0 restores the default
+	IncludeMessage     *bool     `json:"include_message,omitempty"`
+	IncludeTransaction *bool     `json:"include_transaction,omitempty"`
+	GroupByRootCause   *bool     `json:"group_by_root_cause,omitempty"`
+	RefreshIssueTitles *bool     `json:"refresh_issue_titles,omitempty"`
+	InAppInclude       *[]string `json:"in_app_include,omitempty"` // empty clears them
+	InAppExclude       *[]string `json:"in_app_exclude,omitempty"` // empty clears them
+	IgnoreFrames       *[]string `json:"ignore_frames,omitempty"`  // empty clears them
+	Fingerprint        *[]string `json:"fingerprint,omitempty"`    // empty restores the default grouping
+}
+
+// GroupingConfigResponse describes a project's grouping config
+type GroupingConfigResponse struct {
+	ProjectID uuid.UUID `json:"project_id"`
+	GroupingConfig
+}
+
+// RegroupRequest represents the request payload starting the regrouping of a project's
+// recent events
+type RegroupRequest struct {
+	Days int `json:"days,omitempty"` // Events received in the last days are regrouped; 0 uses the default
+}
+
+// RegroupJobResponse describes the progress of a project's regroup job
+type RegroupJobResponse struct {
+	ID              uuid.UUID  `json:"id"`
+	ProjectID       uuid.UUID  `json:"project_id"`
+	RequestedByID   *uuid.UUID `json:"requested_by_id"`
+	Status          string     `json:"status"`
+	GroupingVersion int        `json:"grouping_version"` // Grouping config version events are regrouped with
+	Since           time.Time  `json:"since"`
+	EventsChecked   int        `json:"events_checked"`
+	EventsMoved     int        `json:"events_moved"`
+	IssuesCreated   int        `json:"issues_created"`
+	Error           *string    `json:"error,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	StartedAt       *time.Time `json:"started_at"`
+	FinishedAt      *time.Time `json:"finished_at"`
+}
+
+// FingerprintPreviewResponse explains how the project would group an event sent to it
+type FingerprintPreviewResponse struct {
+	Fingerprint string `json:"fingerprint"` // Hash the event would be grouped by
+	// What decided the fingerprint: default, grouping_template, event or fingerprint_rule
+	Source string `json:"source"`
+	// Normalized string hashed into the fingerprint; empty when a fingerprint rule matched
+	FingerprintString string                 `json:"fingerprint_string"`
+	Components        *FingerprintComponents `json:"components"` // Of the default grouping
+	GroupingVersion   int                    `json:"grouping_version"`
+	// Issue the event would be grouped into; null when it would open a new one
+	IssueID *uuid.UUID `json:"issue_id"`
+}
@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InboundFiltersRequest represents the request payload for updating a project's inbound
+// filters; omitted fields are left unchanged
+type InboundFiltersRequest struct {
+	BrowserExtensions *bool     `json:"browser_extensions,omitempty"`
+	WebCrawlers       *bool     `json:"web_crawlers,omitempty"`
+	Localhost         *bool     `json:"localhost,omitempty"`
+	LegacyBrowsers    *bool     `json:"legacy_browsers,omitempty"`
+	ErrorMessages     *[]string `json:"error_messages,omitempty"`  // Glob patterns, * matching any text, or /regex/; replaces the list
+	ExceptionTypes    *[]string `json:"exception_types,omitempty"` // Same syntax, matched against the exception type
+}
+
+// InboundFilterStats is the number of events a filter discarded since a point in time
+type InboundFilterStats struct {
+	Reason   string `json:"reason"`
+	Filtered int64  `json:"filtered"`
+}
+
+// InboundFiltersResponse describes a project's inbound filters and the events they discarded
+type InboundFiltersResponse struct {
+	ProjectID         uuid.UUID            `json:"project_id"`
+	BrowserExtensions bool                 `json:"browser_extensions"`
+	WebCrawlers       bool                 `json:"web_crawlers"`
+	Localhost         bool                 `json:"localhost"`
+	LegacyBrowsers    bool                 `json:"legacy_browsers"`
+	ErrorMessages     []string             `json:"error_messages"`
+	ExceptionTypes    []string             `json:"exception_types"`
+	Since             time.Time            `json:"since"`
+	TotalFiltered     int64                `json:"total_filtered"`
+	Stats             []InboundFilterStats `json:"stats"`
+}
+
+// ErrorBlocklistRequest represents the request payload for updating the message and exception
+// type patterns of a project's inbound filters; omitted lists are left unchanged
+type ErrorBlocklistRequest struct {
+	Messages       *[]string `json:"messages,omitempty"`        // e.g. "ResizeObserver loop*" or "/^Script error\.?$/"
+	ExceptionTypes *[]string `json:"exception_types,omitempty"` // e.g. "ChunkLoadError"
+}
+
+// ErrorBlocklistResponse describes the message and exception type patterns of a project
+type ErrorBlocklistResponse struct {
+	ProjectID      uuid.UUID `json:"project_id"`
+	Messages       []string  `json:"messages"`
+	ExceptionTypes []string  `json:"exception_types"`
+}
+
+// InboundFilterTestRequest represents a sample event to test against a project's inbound
+// filters; Filters, when set, are tested instead of the saved filters without saving them
+type InboundFilterTestRequest struct {
+	Event     ErrorEventRequest      `json:"event"`
+	UserAgent string                 `json:"user_agent,omitempty"` // of the SDK request, when the event has none
+	ClientIP  string                 `json:"client_ip,omitempty"`  // of the SDK request
+	Filters   *InboundFiltersRequest `json:"filters,omitempty"`
+}
+
+// InboundFilterMatch is a filter discarding a tested event and why
+type InboundFilterMatch struct {
+	Filter string `json:"filter"`
+	Detail string `json:"detail"`
+}
+
+// InboundFilterTestResponse tells whether a tested event would be discarded: Reason is the
+// filter discarding it and Matches every filter matching it, in the order they apply
+type InboundFilterTestResponse struct {
+	Dropped bool                 `json:"dropped"`
+	Reason  string               `json:"reason,omitempty"`
+	Matches []InboundFilterMatch `json:"matches"`
+}
@@ -0,0 +1,114 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// CreateIncidentRequest represents the request payload for opening an incident
+type CreateIncidentRequest struct {
+	Title       string      `json:"title" validate:"required,min=1,max=255"`
+	Description *string     `json:"description,omitempty"`
+	IssueIDs    []uuid.UUID `json:"issue_ids,omitempty"`
+}
+
+// UpdateIncidentRequest represents the request payload for updating an incident
+type UpdateIncidentRequest struct {
+	Title       *string `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
+	Description *string `json:"description,omitempty"`
+	Status      *string `json:"status,omitempty" validate:"omitempty,oneof=open investigating mitigated resolved"`
+}
+
+// IncidentIssuesRequest represents the request payload for adding issues to an incident
+type IncidentIssuesRequest struct {
+	IssueIDs []uuid.UUID `json:"issue_ids" validate:"required,min=1"`
+}
+
+// IncidentNoteRequest represents a note posted to an incident timeline
+type IncidentNoteRequest struct {
+	Content string `json:"content" validate:"required"`
+}
+
+// IncidentFilters represents query parameters for listing incidents
+type IncidentFilters struct {
+	Status string `json:"status,omitempty"`
+	Page   int    `json:"page"`
+	Limit  int    `json:"limit"`
+}
+
+// IncidentResponse represents an incident with its issues
+type IncidentResponse struct {
+	ID             uuid.UUID               `json:"id"`
+	OrganizationID uuid.UUID               `json:"organization_id"`
+	Title          string                  `json:"title"`
+	Description    *string                 `json:"description"`
+	Status         string                  `json:"status"`
+	Source         string                  `json:"source"`
+	StartedAt      time.Time               `json:"started_at"`
+	ResolvedAt     *time.Time              `json:"resolved_at"`
+	CreatedByID    *uuid.UUID              `json:"created_by_id"`
+	IssueCount     int                     `json:"issue_count"`
+	Issues         []IncidentIssueResponse `json:"issues,omitempty"`
+	CreatedAt      time.Time               `json:"created_at"`
+	UpdatedAt      time.Time               `json:"updated_at"`
+}
+
+// IncidentIssueResponse represents an issue grouped in an incident
+type IncidentIssueResponse struct {
+	IssueID     uuid.UUID `json:"issue_id"`
+	ProjectID   uuid.UUID `json:"project_id"`
+	ProjectSlug string    `json:"project_slug"`
+	Title       string    `json:"title"`
+	Status      string    `json:"status"`
+	Level       string    `json:"level"`
+	TimesSeen   int       `json:"times_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+	AddedAt     time.Time `json:"added_at"`
+}
+
+// IncidentListResponse represents paginated incidents
+type IncidentListResponse struct {
+	Incidents  []IncidentResponse `json:"incidents"`
+	Total      int64              `json:"total"`
+	Page       int                `json:"page"`
+	Limit      int                `json:"limit"`
+	TotalPages int                `json:"total_pages"`
+}
+
+// IncidentActivityResponse represents an incident timeline entry
+type IncidentActivityResponse struct {
+	ID        uuid.UUID      `json:"id"`
+	UserID    *uuid.UUID     `json:"user_id"`
+	Type      string         `json:"type"`
+	Data      datatypes.JSON `json:"data"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// IncidentTimelineResponse represents an incident timeline, oldest entry first
+type IncidentTimelineResponse struct {
+	Activities []IncidentActivityResponse `json:"activities"`
+}
+
+// IncidentEventRatePoint is the number of events in one time bucket
+type IncidentEventRatePoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Count     int64     `json:"count"`
+}
+
+// IncidentIssueEventCount is the number of events of one issue over the chart range
+type IncidentIssueEventCount struct {
+	IssueID uuid.UUID `json:"issue_id"`
+	Count   int64     `json:"count"`
+}
+
+// IncidentEventRateResponse is the combined event rate of all issues of an incident
+type IncidentEventRateResponse struct {
+	Interval string                    `json:"interval"`
+	Since    time.Time                 `json:"since"`
+	Until    time.Time                 `json:"until"`
+	Total    int64                     `json:"total"`
+	Points   []IncidentEventRatePoint  `json:"points"`
+	ByIssue  []IncidentIssueEventCount `json:"by_issue"`
+}
@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IngestTokenRequest represents the request payload for creating an organization ingest token
+type IngestTokenRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+// IngestTokenResponse represents an organization ingest token. The token itself is only
+// returned when it is created.
+type IngestTokenResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	OrganizationID uuid.UUID  `json:"organization_id"`
+	Name           string     `json:"name"`
+	Token          string     `json:"token,omitempty"`
+	TokenPrefix    string     `json:"token_prefix"`
+	Scopes         []string   `json:"scopes"`
+	CreatedBy      *uuid.UUID `json:"created_by"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// IngestTokenListResponse lists the ingest tokens of an organization, revoked ones included
+type IngestTokenListResponse struct {
+	Tokens []IngestTokenResponse `json:"tokens"`
+}
@@ -9,17 +9,23 @@ import (
 
 // IssueFilters represents filtering and sorting options for issue queries
 type IssueFilters struct {
-	Status      []string  `form:"status" json:"status,omitempty"`           // unresolved, resolved, ignored
-	Level       []string  `form:"level" json:"level,omitempty"`             // error, warning, info, debug
-	AssignedTo  *string   `form:"assigned_to" json:"assigned_to,omitempty"` // user_id
-	DateFrom    *string   `form:"date_from" json:"date_from,omitempty"`     // ISO date string
-	DateTo      *string   `form:"date_to" json:"date_to,omitempty"`         // ISO date string
-	Search      *string   `form:"search" json:"search,omitempty"`           // text search in title/message
-	Sort        string    `form:"sort" json:"sort"`                         // frequency, first_seen, last_seen
-	Order       string    `form:"order" json:"order"`                       // asc, desc
-	Page        int       `form:"page" json:"page"`                         // page number (1-based)
-	Limit       int       `form:"limit" json:"limit"`                       // items per page
-	Environment *string   `form:"environment" json:"environment,omitempty"` // production, staging, etc
+	Status      []string          `form:"status" json:"status,omitempty"`           // unresolved, resolved, ignored
+	Level       []string          `form:"level" json:"level,omitempty"`             // error, warning, info, debug
+	Priority    []string          `form:"priority" json:"priority,omitempty"`       // low, medium, high
+	AssignedTo  *string           `form:"assigned_to" json:"assigned_to,omitempty"` // user_id, me, none (or unassigned)
+	DateFrom    *string           `form:"date_from" json:"date_from,omitempty"`     // ISO date string
+	DateTo      *string           `form:"date_to" json:"date_to,omitempty"`         // ISO date string
+	Search      *string           `form:"search" json:"search,omitempty"`           // text search in title/message
+	Sort        string            `form:"sort" json:"sort"`                         // frequency, first_seen, last_seen, transaction, priority, relevance
+	Order       string            `form:"order" json:"order"`                       // asc, desc
+	Page        int               `form:"page" json:"page"`                         // page number (1-based)
+	Limit       int               `form:"limit" json:"limit"`                       // items per page
+	Environment *string           `form:"environment" json:"environment,omitempty"` // production, staging, etc
+	Transaction *string           `form:"transaction" json:"transaction,omitempty"` // route such as /checkout; a trailing * matches a prefix
+	Release     *string           `form:"release" json:"release,omitempty"`         // latest, 2.3.0, >=2.3.0, <1200 (build numbers)
+	ContextTags map[string]string `form:"-" json:"context_tags,omitempty"`          // os.name:Windows search tokens; a trailing * matches a prefix
+	Tags        map[string]string `form:"-" json:"tags,omitempty"`                  // event tags of query terms; a trailing * matches a prefix
+	Regression  *bool             `form:"is_regression" json:"is_regression,omitempty"` // true for regressed issues only, false to leave them out
 }
 
 // IssueListResponse represents paginated issue list response
@@ -44,6 +50,8 @@ type IssueListItemResponse struct {
 	FirstSeen   time.Time `json:"first_seen"`
 	LastSeen    time.Time `json:"last_seen"`
 	TimesSeen   int       `json:"times_seen"`
+	Transaction *string   `json:"transaction"`
+	IsRegression bool     `json:"is_regression"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -62,6 +70,14 @@ type IssueResponse struct {
 	LastSeen     time.Time                `json:"last_seen"`
 	TimesSeen    int                      `json:"times_seen"`
 	AssigneeID   *uuid.UUID               `json:"assignee_id"`
+	Transaction  *string                  `json:"transaction"`
+	Platform     string                   `json:"platform,omitempty"`
+	IsRegression bool                     `json:"is_regression"` // Reopened by new events after being resolved
+	ResolvedInRelease *string             `json:"resolved_in_release,omitempty"` // Events of newer releases reopen the issue
+	GroupingVersion int                   `json:"grouping_version"` // Project grouping config version the issue was grouped with
+	IgnoreCondition *IgnoreConditionResponse `json:"ignore_condition,omitempty"` // When the ignored issue is unignored
+	Priority     string                   `json:"priority"`        // low, medium or high
+	PriorityLocked bool                   `json:"priority_locked"` // Set by a user, so events no longer raise it
 	CreatedAt    time.Time                `json:"created_at"`
 	UpdatedAt    time.Time                `json:"updated_at"`
 	
@@ -71,6 +87,21 @@ type IssueResponse struct {
 	LatestEvent  *IssueEventResponse      `json:"latest_event,omitempty"`
 	CommentCount int                      `json:"comment_count,omitempty"`
 	Tags         map[string]string        `json:"tags,omitempty"`
+	Relations    []IssueRelationResponse  `json:"relations,omitempty"`
+	Pinned       map[string]string        `json:"pinned,omitempty"` // Latest event values of the project's pinned context keys
+	Outcomes     *IssueOutcomesResponse   `json:"outcomes,omitempty"` // Only included in issue details
+}
+
+// IssueOutcomesResponse counts the events of an issue that were received but not stored,
+// which times_seen and the issue's events leave out
+type IssueOutcomesResponse struct {
+	Duplicate int64 `json:"duplicate"` // retries of events already received, not counted in times_seen
+	Sampled   int64 `json:"sampled"`   // counted in times_seen but sampled out of the stored events
+
+	// ProjectRateLimited counts the project's events rejected by rate limits, quotas or
+	// spike protection since the issue was first seen. They are rejected before grouping,
+	// so they may belong to any of the project's issues.
+	ProjectRateLimited int64 `json:"project_rate_limited"`
 }
 
 // IssueAssigneeResponse represents assignee information in issue response
@@ -83,25 +114,34 @@ type IssueAssigneeResponse struct {
 
 // IssueProjectResponse represents project information in issue response
 type IssueProjectResponse struct {
-	ID   uuid.UUID `json:"id"`
-	Name string    `json:"name"`
-	Slug string    `json:"slug"`
+	ID      uuid.UUID `json:"id"`
+	Name    string    `json:"name"`
+	Slug    string    `json:"slug"`
+	Runbook *string   `json:"runbook,omitempty"` // Only included in issue details
 }
 
 // IssueEventResponse represents event information in issue response
 type IssueEventResponse struct {
-	ID             uuid.UUID      `json:"id"`
-	EventID        string         `json:"event_id"`
-	Timestamp      time.Time      `json:"timestamp"`
-	Level          string         `json:"level"`
-	Message        *string        `json:"message"`
-	ExceptionType  *string        `json:"exception_type"`
-	ExceptionValue *string        `json:"exception_value"`
-	Environment    string         `json:"environment"`
-	ReleaseVersion *string        `json:"release_version"`
-	ServerName     *string        `json:"server_name"`
-	UserContext    datatypes.JSON `json:"user_context,omitempty"`
-	Tags           datatypes.JSON `json:"tags,omitempty"`
+	ID                uuid.UUID      `json:"id"`
+	EventID           string         `json:"event_id"`
+	Timestamp         time.Time      `json:"timestamp"`
+	OriginalTimestamp *time.Time     `json:"original_timestamp,omitempty"` // Sent by the SDK, when clock skew was corrected
+	Level             string         `json:"level"`
+	Platform          string         `json:"platform,omitempty"`
+	Message           *string        `json:"message"`
+	ExceptionType     *string        `json:"exception_type"`
+	ExceptionValue    *string        `json:"exception_value"`
+	Exceptions        []ExceptionValue `json:"exceptions,omitempty"` // The exception chain with stack traces, in the order sent
+	Environment       string         `json:"environment"`
+	ReleaseVersion    *string        `json:"release_version"`
+	ServerName        *string        `json:"server_name"`
+	UserContext       datatypes.JSON `json:"user_context,omitempty"`
+	Tags              datatypes.JSON `json:"tags,omitempty"`
+	Contexts          datatypes.JSON `json:"contexts,omitempty"`
+	Meta              datatypes.JSON `json:"_meta,omitempty"` // What the event limits trimmed
+	ReplayID          *string        `json:"replay_id,omitempty"`
+	Transaction       *string        `json:"transaction,omitempty"`
+	Replay            *EventReplayResponse `json:"replay,omitempty"`
 }
 
 // IssueUpdateRequest represents request to update issue status or assignment
@@ -109,6 +149,27 @@ type IssueUpdateRequest struct {
 	Status     *string     `json:"status,omitempty"`     // resolved, ignored, unresolved
 	AssigneeID *uuid.UUID  `json:"assignee_id,omitempty"` // null to unassign
 	Resolution *string     `json:"resolution,omitempty"`  // resolution reason
+	Priority   *string     `json:"priority,omitempty"`    // low, medium or high; kept as events are counted
+
+	// ResolvedInRelease resolves the issue in a version, or "latest" for the project's latest
+	// release; only events of newer releases reopen it
+	ResolvedInRelease *string `json:"resolved_in_release,omitempty"`
+
+	// Ignore conditions, with the ignored status: the issue is unignored by the first event
+	// received after the time, or reaching the count of events or of users affected since it
+	// was ignored, whichever comes first
+	IgnoreUntil     *time.Time `json:"ignore_until,omitempty"`
+	IgnoreCount     *int       `json:"ignore_count,omitempty"`
+	IgnoreUserCount *int       `json:"ignore_user_count,omitempty"`
+}
+
+// IgnoreConditionResponse describes when an ignored issue is unignored, and how close it is
+type IgnoreConditionResponse struct {
+	Until         *time.Time `json:"until,omitempty"`
+	Count         *int       `json:"count,omitempty"`
+	UserCount     *int       `json:"user_count,omitempty"`
+	EventsSeen    int        `json:"events_seen"`    // Since ignored, as counted in times_seen
+	UsersAffected int        `json:"users_affected"` // Since ignored; only counted with user_count
 }
 
 // IssueCommentRequest represents request to add comment to issue
@@ -118,15 +179,75 @@ type IssueCommentRequest struct {
 
 // IssueCommentResponse represents issue comment response
 type IssueCommentResponse struct {
-	ID        uuid.UUID `json:"id"`
-	IssueID   uuid.UUID `json:"issue_id"`
-	UserID    uuid.UUID `json:"user_id"`
-	Content   string    `json:"content"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        uuid.UUID  `json:"id"`
+	IssueID   uuid.UUID  `json:"issue_id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Content   string     `json:"content"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	EditedAt  *time.Time `json:"edited_at"`
 	
 	// User information
 	User IssueCommentUserResponse `json:"user"`
+	
+	// Reactions grouped by emoji
+	Reactions []IssueCommentReactionResponse `json:"reactions"`
+	
+	// Organization members mentioned in the content
+	Mentions []IssueCommentMentionResponse `json:"mentions"`
+}
+
+// IssueCommentMentionResponse represents a member mentioned in a comment; Text is the
+// mention as written in the content, without the @, for highlighting
+type IssueCommentMentionResponse struct {
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name"`
+	Email  string    `json:"email"`
+	Text   string    `json:"text"`
+}
+
+// IssueCommentReactionRequest represents request to react to a comment
+type IssueCommentReactionRequest struct {
+	Emoji string `json:"emoji" binding:"required"`
+}
+
+// IssueCommentReactionResponse represents the count of one emoji on a comment
+type IssueCommentReactionResponse struct {
+	Emoji   string      `json:"emoji"`
+	Count   int         `json:"count"`
+	UserIDs []uuid.UUID `json:"user_ids"`
+}
+
+// IssueRelationRequest represents request to link an issue to another issue
+type IssueRelationRequest struct {
+	TargetIssueID uuid.UUID `json:"target_issue_id" binding:"required"`
+	RelationType  string    `json:"relation_type" binding:"required"`
+}
+
+// IssueRelationResponse represents a relation as "source_issue relation_type target_issue"
+type IssueRelationResponse struct {
+	ID           uuid.UUID                 `json:"id"`
+	RelationType string                    `json:"relation_type"`
+	SourceIssue  IssueRelatedIssueResponse `json:"source_issue"`
+	TargetIssue  IssueRelatedIssueResponse `json:"target_issue"`
+	Depth        int                       `json:"depth"` // Hops from the requested issue; 1 for direct relations
+	CreatedByID  *uuid.UUID                `json:"created_by_id"`
+	CreatedAt    time.Time                 `json:"created_at"`
+}
+
+// IssueRelatedIssueResponse represents the summary of an issue on either end of a relation
+type IssueRelatedIssueResponse struct {
+	ID          uuid.UUID `json:"id"`
+	ProjectID   uuid.UUID `json:"project_id"`
+	ProjectSlug string    `json:"project_slug"`
+	Title       string    `json:"title"`
+	Status      string    `json:"status"`
+	Level       string    `json:"level"`
+}
+
+// IssueRelationsResponse represents the relations of an issue
+type IssueRelationsResponse struct {
+	Relations []IssueRelationResponse `json:"relations"`
 }
 
 // IssueCommentUserResponse represents user info in comment response
@@ -193,8 +314,12 @@ type IssueStatsResponse struct {
 	Ignored       int64                    `json:"ignored"`
 	NewToday      int64                    `json:"new_today"`
 	NewThisWeek   int64                    `json:"new_this_week"`
+	Assigned      int64                    `json:"assigned"`
+	Unassigned    int64                    `json:"unassigned"`
+	AssignedToMe  int64                    `json:"assigned_to_me"`
 	ByLevel       map[string]int64         `json:"by_level"`
 	ByEnvironment map[string]int64         `json:"by_environment"`
+	ByCountry     map[string]int64         `json:"by_country"` // Issues with events from each country, when GeoIP is enabled
 	TopIssues     []IssueResponse          `json:"top_issues"`
 	Timeline      []IssueTimelineEntry     `json:"timeline"`
 }
@@ -208,9 +333,10 @@ type IssueTimelineEntry struct {
 // BulkUpdateIssuesRequest represents request to bulk update issues
 type BulkUpdateIssuesRequest struct {
 	IssueIDs   []uuid.UUID `json:"issue_ids" binding:"required"`
-	Action     string      `json:"action" binding:"required"`     // resolve, ignore, unresolve, assign
+	Action     string      `json:"action" binding:"required"`     // resolve, ignore, unresolve, assign, delete
 	AssigneeID *uuid.UUID  `json:"assignee_id,omitempty"`         // for assign action
 	Resolution *string     `json:"resolution,omitempty"`          // resolution reason
+	ResolvedInRelease *string `json:"resolved_in_release,omitempty"` // for resolve action, see IssueUpdateRequest
 }
 
 // BulkUpdateIssuesResponse represents response from bulk update operation
@@ -221,6 +347,65 @@ type BulkUpdateIssuesResponse struct {
 	UpdatedIDs   []uuid.UUID `json:"updated_ids"`
 }
 
+// MergeIssuesRequest represents a request to merge issues into a primary issue
+type MergeIssuesRequest struct {
+	PrimaryIssueID uuid.UUID   `json:"primary_issue_id"`
+	IssueIDs       []uuid.UUID `json:"issue_ids"` // issues merged into the primary issue
+}
+
+// MergeIssuesResponse represents the primary issue after a merge
+type MergeIssuesResponse struct {
+	Issue          *IssueResponse `json:"issue"`
+	MergedIssueIDs []uuid.UUID    `json:"merged_issue_ids"`
+	Fingerprints   []string       `json:"fingerprints"` // fingerprints now grouped into the primary issue
+}
+
+// UnmergeIssueRequest represents a request to split fingerprints merged into an issue, or
+// some of its events, into a new issue; exactly one of the two is given
+type UnmergeIssueRequest struct {
+	Fingerprints []string    `json:"fingerprints,omitempty"` // fingerprints merged into the issue
+	EventIDs     []uuid.UUID `json:"event_ids,omitempty"`    // ids of events of the issue
+}
+
+// UnmergeIssueResponse represents the issue split and the new issue after an unmerge
+type UnmergeIssueResponse struct {
+	Issue      *IssueResponse `json:"issue"`
+	NewIssue   *IssueResponse `json:"new_issue"`
+	EventCount int            `json:"event_count"` // events moved to the new issue
+}
+
+// SimilarIssueResponse represents an issue suggested as a likely duplicate of another
+type SimilarIssueResponse struct {
+	Issue IssueResponse `json:"issue"`
+	Score float64       `json:"score"` // 0 to 1, from title and top stack frames
+}
+
+// SimilarIssuesResponse represents the issues similar to an issue, most similar first
+type SimilarIssuesResponse struct {
+	Similar []SimilarIssueResponse `json:"similar"`
+}
+
+// IssueFingerprintRuleRequest represents a request setting the rule folding matching future
+// events into an issue; at least one pattern is required
+type IssueFingerprintRuleRequest struct {
+	Key           string  `json:"key"`                      // names the grouping, unique within the project
+	ExceptionType *string `json:"exception_type,omitempty"` // glob or /regex/ pattern
+	Message       *string `json:"message,omitempty"`        // glob or /regex/ pattern
+	Transaction   *string `json:"transaction,omitempty"`    // glob or /regex/ pattern
+}
+
+// IssueFingerprintRuleResponse represents the rule folding matching future events into an issue
+type IssueFingerprintRuleResponse struct {
+	IssueID       uuid.UUID  `json:"issue_id"`
+	Key           string     `json:"key"`
+	ExceptionType *string    `json:"exception_type"`
+	Message       *string    `json:"message"`
+	Transaction   *string    `json:"transaction"`
+	CreatedByID   *uuid.UUID `json:"created_by_id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
 // IssueSearchResponse represents search results for issues
 type IssueSearchResponse struct {
 	Results    []IssueResponse `json:"results"`
@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IssueParticipantResponse represents a user notified of an issue's status changes
+type IssueParticipantResponse struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Reason    string    `json:"reason"` // commented, mentioned, assigned or subscribed
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IssueParticipantsResponse represents the participants of an issue and whether the
+// requesting user is one of them
+type IssueParticipantsResponse struct {
+	Participants []IssueParticipantResponse `json:"participants"`
+	IsSubscribed bool                       `json:"is_subscribed"`
+}
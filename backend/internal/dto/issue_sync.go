@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IssueSyncSchemaVersion is bumped on incompatible changes to IssueSyncDelta
+const IssueSyncSchemaVersion = 1
+
+// IssueSyncRequest represents the request payload for configuring a project's issue sync.
+// Setting cursor rewinds (or skips) webhook delivery to the deltas after that sequence.
+type IssueSyncRequest struct {
+	URL          *string `json:"url,omitempty"`
+	Enabled      *bool   `json:"enabled,omitempty"`
+	RotateSecret bool    `json:"rotate_secret,omitempty"`
+	Cursor       *int64  `json:"cursor,omitempty"`
+}
+
+// IssueSyncResponse represents a project's issue sync configuration and delivery state.
+// The secret is only returned when it is generated or rotated.
+type IssueSyncResponse struct {
+	ProjectID         uuid.UUID  `json:"project_id"`
+	URL               *string    `json:"url"`
+	Enabled           bool       `json:"enabled"`
+	Secret            string     `json:"secret,omitempty"`
+	LastSequence      int64      `json:"last_sequence"`
+	DeliveredSequence int64      `json:"delivered_sequence"`
+	FailureCount      int        `json:"failure_count"`
+	LastError         *string    `json:"last_error"`
+	LastDeliveryAt    *time.Time `json:"last_delivery_at"`
+	NextAttemptAt     *time.Time `json:"next_attempt_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// IssueSyncDelta is a single issue state change. Sequence numbers are per project, start
+// at 1 and have no gaps, so consumers can detect missed deltas and resume from a cursor.
+type IssueSyncDelta struct {
+	SchemaVersion int                             `json:"schema_version"`
+	Sequence      int64                           `json:"sequence"`
+	Type          string                          `json:"type"`
+	ProjectID     uuid.UUID                       `json:"project_id"`
+	IssueID       uuid.UUID                       `json:"issue_id"`
+	OccurredAt    time.Time                       `json:"occurred_at"`
+	ActorID       *uuid.UUID                      `json:"actor_id"`
+	Changes       map[string]IssueSyncFieldChange `json:"changes"`
+	Issue         IssueSyncSnapshot               `json:"issue"`
+}
+
+// IssueSyncFieldChange is the previous and new value of a changed issue field
+type IssueSyncFieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// IssueSyncSnapshot is the state of the issue right after the change
+type IssueSyncSnapshot struct {
+	ID           uuid.UUID  `json:"id"`
+	ProjectID    uuid.UUID  `json:"project_id"`
+	Title        string     `json:"title"`
+	Culprit      *string    `json:"culprit"`
+	Type         string     `json:"type"`
+	Level        string     `json:"level"`
+	Status       string     `json:"status"`
+	AssigneeID   *uuid.UUID `json:"assignee_id"`
+	FirstSeen    time.Time  `json:"first_seen"`
+	LastSeen     time.Time  `json:"last_seen"`
+	TimesSeen    int        `json:"times_seen"`
+	IsRegression bool       `json:"is_regression"`
+}
+
+// IssueSyncDeltasResponse is a page of deltas after a cursor; it is also the webhook body
+type IssueSyncDeltasResponse struct {
+	ProjectID  uuid.UUID         `json:"project_id"`
+	Deltas     []json.RawMessage `json:"deltas"`
+	NextCursor int64             `json:"next_cursor"`
+	HasMore    bool              `json:"has_more"`
+}
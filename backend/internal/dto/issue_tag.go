@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+// IssueTagValueResponse represents a value of a tag of an issue's events
+type IssueTagValueResponse struct {
+	Value      string    `json:"value"`
+	Count      int64     `json:"count"`      // stored events with the value
+	Percentage float64   `json:"percentage"` // of the issue's stored events with the tag, 0 to 100
+	FirstSeen  time.Time `json:"first_seen"`
+	LastSeen   time.Time `json:"last_seen"`
+}
+
+// IssueTagResponse represents a tag of an issue's events, with its most common values
+type IssueTagResponse struct {
+	Key          string                  `json:"key"`
+	Count        int64                   `json:"count"`         // stored events with the tag
+	UniqueValues int64                   `json:"unique_values"` // distinct values of the tag
+	TopValues    []IssueTagValueResponse `json:"top_values"`    // most common first
+}
+
+// IssueTagsResponse represents the tags of an issue's events, the most common first
+type IssueTagsResponse struct {
+	Tags []IssueTagResponse `json:"tags"`
+}
+
+// IssueTagValuesResponse represents a page of the values of a tag of an issue's events, the
+// most common first
+type IssueTagValuesResponse struct {
+	Key        string                  `json:"key"`
+	Count      int64                   `json:"count"` // stored events with the tag
+	Values     []IssueTagValueResponse `json:"values"`
+	Total      int64                   `json:"total"` // distinct values of the tag
+	Page       int                     `json:"page"`
+	Limit      int                     `json:"limit"`
+	TotalPages int                     `json:"total_pages"`
+}
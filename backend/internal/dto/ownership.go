@@ -0,0 +1,25 @@
+package dto
+
+import "github.com/google/uuid"
+
+// OwnershipRule assigns the new issues whose events match a pattern to an owner. Path rules
+// match the absolute paths or file names of stack frames with in-app path patterns (** any
+// part of a path, * of a name); module rules match frame modules and the issue culprit, and
+// tag rules the value of the tag named by Key, with globs or /regular expressions/.
+type OwnershipRule struct {
+	Type    string    `json:"type"` // path, module or tag
+	Key     string    `json:"key,omitempty"`
+	Pattern string    `json:"pattern"`
+	OwnerID uuid.UUID `json:"owner_id"` // a member of the project's organization
+}
+
+// OwnershipRulesRequest represents the request payload replacing a project's ownership rules
+type OwnershipRulesRequest struct {
+	Rules []OwnershipRule `json:"rules"`
+}
+
+// OwnershipRulesResponse describes a project's ownership rules, evaluated in order
+type OwnershipRulesResponse struct {
+	ProjectID uuid.UUID       `json:"project_id"`
+	Rules     []OwnershipRule `json:"rules"`
+}
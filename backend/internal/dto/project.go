@@ -18,29 +18,36 @@ type CreateProjectRequest struct {
 	Name        string  `json:"name" validate:"required,min=1,max=255"`
 	Slug        string  `json:"slug" validate:"required,min=1,max=100,alphanum"`
 	Description *string `json:"description,omitempty" validate:"omitempty,max=1000"`
-	Platform    string  `json:"platform" validate:"required,oneof=javascript python go java dotnet php ruby"`
+	Platform    string  `json:"platform" validate:"required,max=50"`
 }
 
 // UpdateProjectRequest represents the request payload for updating a project
 type UpdateProjectRequest struct {
 	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
 	Description *string `json:"description,omitempty" validate:"omitempty,max=1000"`
-	Platform    *string `json:"platform,omitempty" validate:"omitempty,oneof=javascript python go java dotnet php ruby"`
+	Platform    *string `json:"platform,omitempty" validate:"omitempty,max=50"`
 }
 
 // ProjectResponse represents the response payload for project details
 type ProjectResponse struct {
-	ID             uuid.UUID `json:"id"`
-	OrganizationID uuid.UUID `json:"organization_id"`
-	Name           string    `json:"name"`
-	Slug           string    `json:"slug"`
-	Description    *string   `json:"description"`
-	Platform       string    `json:"platform"`
-	DSN            string    `json:"dsn"`
-	PublicKey      string    `json:"public_key"`
-	IsActive       bool      `json:"is_active"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID                   uuid.UUID `json:"id"`
+	OrganizationID       uuid.UUID `json:"organization_id"`
+	Name                 string    `json:"name"`
+	Slug                 string    `json:"slug"`
+	Description          *string   `json:"description"`
+	Platform             string    `json:"platform"`
+	DSN                  string    `json:"dsn"`
+	PublicKey            string    `json:"public_key"`
+	IsActive             bool      `json:"is_active"`
+	Runbook              *string   `json:"runbook"`
+	MaxEventSize         *int      `json:"max_event_size"`
+	RequireSecretKey     bool      `json:"require_secret_key"`
+	IPAllowList          []string  `json:"ip_allow_list"`
+	IPDenyList           []string  `json:"ip_deny_list"`
+	EventSampleThreshold *int      `json:"event_sample_threshold"`
+	EventSampleRate      *int      `json:"event_sample_rate"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
 }
 
 // ProjectListResponse represents the response payload for listing projects
@@ -50,13 +57,22 @@ type ProjectListResponse struct {
 
 // ProjectConfigurationRequest represents the request payload for updating project configuration
 type ProjectConfigurationRequest struct {
-	IsActive *bool `json:"is_active,omitempty"`
-	Platform *string `json:"platform,omitempty" validate:"omitempty,oneof=javascript python go java dotnet php ruby"`
+	IsActive             *bool     `json:"is_active,omitempty"`
+	Platform             *string   `json:"platform,omitempty" validate:"omitempty,max=50"`
+	Runbook              *string   `json:"runbook,omitempty" validate:"omitempty,max=50000"`            // Markdown; an empty string clears it
+	MaxEventSize         *int      `json:"max_event_size,omitempty" validate:"omitempty,min=0"`         // Bytes; 0 clears it
+	RequireSecretKey     *bool     `json:"require_secret_key,omitempty"`                                // Require sentry_secret on ingestion, for server-side SDKs
+	IPAllowList          *[]string `json:"ip_allow_list,omitempty"`                                     // CIDR ranges events are accepted from; empty allows all
+	IPDenyList           *[]string `json:"ip_deny_list,omitempty"`                                      // CIDR ranges events are rejected from
+	EventSampleThreshold *int      `json:"event_sample_threshold,omitempty" validate:"omitempty,min=0"` // Events of an issue stored per hour before sampling; 0 stores all
+	EventSampleRate      *int      `json:"event_sample_rate,omitempty" validate:"omitempty,min=0"`      // Store one in this many events beyond the threshold; 0 uses the default
+	PinnedContextKeys    *[]string `json:"pinned_context_keys,omitempty"`                               // Tag, extra or user.* keys shown in the issue list; empty clears them
 }
 
 // ProjectKeyResponse represents the response after regenerating project key
 type ProjectKeyResponse struct {
 	PublicKey string `json:"public_key"`
+	SecretKey string `json:"secret_key"` // Sent by server-side SDKs as sentry_secret
 	DSN       string `json:"dsn"`
 }
 
@@ -147,17 +163,24 @@ func ValidateDSN(dsn string) (uuid.UUID, error) {
 // ToProjectResponse converts a Project model to ProjectResponse
 func ToProjectResponse(project *models.Project) ProjectResponse {
 	return ProjectResponse{
-		ID:             project.ID,
-		OrganizationID: project.OrganizationID,
-		Name:           project.Name,
-		Slug:           project.Slug,
-		Description:    project.Description,
-		Platform:       project.Platform,
-		DSN:            project.DSN,
-		PublicKey:      project.PublicKey,
-		IsActive:       project.IsActive,
-		CreatedAt:      project.CreatedAt,
-		UpdatedAt:      project.UpdatedAt,
+		ID:                   project.ID,
+		OrganizationID:       project.OrganizationID,
+		Name:                 project.Name,
+		Slug:                 project.Slug,
+		Description:          project.Description,
+		Platform:             project.Platform,
+		DSN:                  project.DSN,
+		PublicKey:            project.PublicKey,
+		IsActive:             project.IsActive,
+		Runbook:              project.Runbook,
+		MaxEventSize:         project.MaxEventSize,
+		RequireSecretKey:     project.RequireSecretKey,
+		IPAllowList:          models.ProjectIPRanges(project.IPAllowList),
+		IPDenyList:           models.ProjectIPRanges(project.IPDenyList),
+		EventSampleThreshold: project.EventSampleThreshold,
+		EventSampleRate:      project.EventSampleRate,
+		CreatedAt:            project.CreatedAt,
+		UpdatedAt:            project.UpdatedAt,
 	}
 }
 
@@ -208,25 +231,18 @@ func NormalizeProjectSlug(slug string) (string, error) {
 	return normalized, nil
 }
 
-// SupportedPlatforms returns list of supported project platforms
-func SupportedPlatforms() []string {
-	return []string{
-		"javascript",
-		"python", 
-		"go",
-		"java",
-		"dotnet",
-		"php",
-		"ruby",
-	}
+// PlatformResponse represents a known project platform and the aliases it is also known by
+type PlatformResponse struct {
+	ID      string   `json:"id"`
+	Aliases []string `json:"aliases,omitempty"`
+}
+// EnvironmentResponse is an environment a project's data was sent from
+type EnvironmentResponse struct {
+	Name      string    `json:"name"`
+	FirstSeen time.Time `json:"first_seen"`
 }
 
-// IsPlatformSupported checks if platform is supported
-func IsPlatformSupported(platform string) bool {
-	for _, supported := range SupportedPlatforms() {
-		if platform == supported {
-			return true
-		}
-	}
-	return false
-}
\ No newline at end of file
+// EnvironmentListResponse lists the environments of a project
+type EnvironmentListResponse struct {
+	Environments []EnvironmentResponse `json:"environments"`
+}
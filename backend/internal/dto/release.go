@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReleaseRequest represents the request payload for creating a release. Creating an
+// existing version updates its ref, URL and release date instead.
+type ReleaseRequest struct {
+	Version      string     `json:"version" validate:"required,max=100"`
+	Ref          *string    `json:"ref,omitempty" validate:"omitempty,max=255"`
+	URL          *string    `json:"url,omitempty" validate:"omitempty,max=500"`
+	DateReleased *time.Time `json:"date_released,omitempty"`
+}
+
+// ReleaseResponse represents a release of a project
+type ReleaseResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	ProjectID     uuid.UUID  `json:"project_id"`
+	Version       string     `json:"version"`
+	Ref           *string    `json:"ref"`
+	URL           *string    `json:"url"`
+	DateReleased  *time.Time `json:"date_released"`
+	Package       string     `json:"package,omitempty"`
+	VersionFormat string     `json:"version_format"` // semver, build, commit or other
+	ArtifactCount int64      `json:"artifact_count"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// ReleaseListResponse represents paginated releases
+type ReleaseListResponse struct {
+	Releases   []ReleaseResponse `json:"releases"`
+	Total      int64             `json:"total"`
+	Page       int               `json:"page"`
+	Limit      int               `json:"limit"`
+	TotalPages int               `json:"total_pages"`
+}
+
+// ReleaseArtifactResponse describes a file uploaded for a release
+type ReleaseArtifactResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Dist        *string   `json:"dist"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Checksum    string    `json:"checksum"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ReleaseArtifactListResponse lists the files of a release
+type ReleaseArtifactListResponse struct {
+	Artifacts []ReleaseArtifactResponse `json:"artifacts"`
+}
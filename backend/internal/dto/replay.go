@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+)
+
+// ReplayEventRequest represents a `replay_event` envelope item sent by the replay SDK
+// with every uploaded segment
+type ReplayEventRequest struct {
+	EventID              *string           `json:"event_id,omitempty"`
+	Type                 string            `json:"type"`
+	ReplayID             string            `json:"replay_id"`
+	ReplayType           *string           `json:"replay_type,omitempty"`
+	SegmentID            int               `json:"segment_id"`
+	Timestamp            *time.Time        `json:"timestamp,omitempty"`
+	ReplayStartTimestamp *time.Time        `json:"replay_start_timestamp,omitempty"`
+	URLs                 []string          `json:"urls,omitempty"`
+	ErrorIDs             []string          `json:"error_ids,omitempty"`
+	TraceIDs             []string          `json:"trace_ids,omitempty"`
+	Platform             *string           `json:"platform,omitempty"`
+	Release              *string           `json:"release,omitempty"`
+	Environment          *string           `json:"environment,omitempty"`
+	Tags                 map[string]string `json:"tags,omitempty"`
+}
+
+// ReplayRecordingHeader is the JSON line that precedes the recording data in a
+// `replay_recording` envelope item
+type ReplayRecordingHeader struct {
+	SegmentID int `json:"segment_id"`
+}
+
+// EventReplayResponse is the replay reference attached to an event
+type EventReplayResponse struct {
+	ReplayID     string     `json:"replay_id"`
+	ReplayType   *string    `json:"replay_type,omitempty"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+	SegmentCount int        `json:"segment_count"`
+	URLs         []string   `json:"urls,omitempty"`
+}
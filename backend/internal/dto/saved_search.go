@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SavedSearchRequest represents the request payload creating or replacing a saved search.
+// Visibility is owner (the default) for personal searches or organization for searches
+// shared with every member, which only admins and owners manage.
+type SavedSearchRequest struct {
+	Name       string       `json:"name" validate:"required,min=1,max=100"`
+	Filters    IssueFilters `json:"filters"`
+	Visibility string       `json:"visibility,omitempty" validate:"omitempty,oneof=owner organization"`
+}
+
+// SavedSearchResponse represents a saved search
+type SavedSearchResponse struct {
+	ID             uuid.UUID    `json:"id"`
+	OrganizationID uuid.UUID    `json:"organization_id"`
+	ProjectID      *uuid.UUID   `json:"project_id"` // null for organization searches of every project
+	OwnerID        *uuid.UUID   `json:"owner_id"`   // null for organization searches
+	Visibility     string       `json:"visibility"`
+	Name           string       `json:"name"`
+	Filters        IssueFilters `json:"filters"`
+	CreatedByID    *uuid.UUID   `json:"created_by_id"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+import "github.com/google/uuid"
+
+// ScrubbingRule selects event fields by path and scrubs their values. Selectors are dot
+// separated keys under a root (message, exception, request, user, extra, tags,
+// breadcrumbs, frames), where * matches any key, ** any number of keys, and keys may
+// contain glob wildcards; e.g. "request.headers.x-*" or "**.ssn".
+type ScrubbingRule struct {
+	Selector string `json:"selector"`
+	Action   string `json:"action"` // mask, hash or remove
+}
+
+// ScrubbingRulesRequest represents the request payload replacing a project's scrubbing rules
+type ScrubbingRulesRequest struct {
+	Rules []ScrubbingRule `json:"rules"`
+}
+
+// ScrubbingRulesResponse describes a project's data scrubbing rules
+type ScrubbingRulesResponse struct {
+	ProjectID uuid.UUID       `json:"project_id"`
+	Rules     []ScrubbingRule `json:"rules"`
+}
+
+// ScrubbingTestRequest represents a sample event to scrub; Rules, when set, are tested
+// instead of the project's saved rules without saving them
+type ScrubbingTestRequest struct {
+	Event ErrorEventRequest `json:"event"`
+	Rules *[]ScrubbingRule  `json:"rules,omitempty"`
+}
+
+// ScrubbingChange is a field of a tested event that would be redacted, and why
+type ScrubbingChange struct {
+	Path   string `json:"path"`   // e.g. request.headers.Authorization or breadcrumbs.0.data.password
+	Action string `json:"action"` // filter for the default scrubbing, else the rule's action
+	Reason string `json:"reason"`
+}
+
+// ScrubbingTestResponse is a tested event as it would be stored, with the fields scrubbing
+// changed in the order the defaults and the rules apply
+type ScrubbingTestResponse struct {
+	Event   map[string]interface{} `json:"event"`
+	Changes []ScrubbingChange      `json:"changes"`
+}
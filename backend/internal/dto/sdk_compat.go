@@ -0,0 +1,281 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UnmarshalJSON decodes an event after smoothing over the payload shapes used by
+// different Sentry SDKs, so the typed fields below only ever see one representation.
+func (e *ErrorEventRequest) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var raw map[string]interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return err
+	}
+
+	normalizeSDKPayload(raw)
+
+	normalized, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	// The alias type has no methods, which avoids recursing into this function
+	type eventAlias ErrorEventRequest
+	return json.Unmarshal(normalized, (*eventAlias)(e))
+}
+
+// UnmarshalJSON decodes a transaction, accepting the same SDK payload variants as error events
+func (t *TransactionEventRequest) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var raw map[string]interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return err
+	}
+
+	normalizeSDKPayload(raw)
+	normalizeTimestampField(raw, "start_timestamp")
+	if spans, ok := raw["spans"].([]interface{}); ok {
+		for _, span := range spans {
+			if s, ok := span.(map[string]interface{}); ok {
+				normalizeTimestampField(s, "start_timestamp")
+				normalizeTimestampField(s, "timestamp")
+				normalizeStringMap(s, "tags")
+			}
+		}
+	}
+
+	normalized, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	type transactionAlias TransactionEventRequest
+	return json.Unmarshal(normalized, (*transactionAlias)(t))
+}
+
+// UnmarshalJSON decodes a replay event, converting its unix timestamps like other events
+func (r *ReplayEventRequest) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var raw map[string]interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return err
+	}
+
+	normalizeTimestampField(raw, "timestamp")
+	normalizeTimestampField(raw, "replay_start_timestamp")
+	normalizeStringMap(raw, "tags")
+
+	normalized, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	type replayAlias ReplayEventRequest
+	return json.Unmarshal(normalized, (*replayAlias)(r))
+}
+
+// UnmarshalJSON decodes a client report, converting its unix timestamp like other items
+func (c *ClientReport) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var raw map[string]interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return err
+	}
+
+	normalizeTimestampField(raw, "timestamp")
+
+	normalized, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	type clientReportAlias ClientReport
+	return json.Unmarshal(normalized, (*clientReportAlias)(c))
+}
+
+// PeekEventType returns the `type` field of an event payload, or an empty string for error events
+func PeekEventType(data []byte) string {
+	var peek struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &peek); err != nil {
+		return ""
+	}
+	return peek.Type
+}
+
+// normalizeSDKPayload rewrites SDK-specific variants into the canonical protocol shape:
+//   - timestamps sent as unix seconds (JS, PHP) become RFC 3339 strings
+//   - a plain string message (Go, Python capture_message) becomes a message object
+//   - logentry (Python logging) is used as the message when none is given
+//   - exception and breadcrumbs sent as bare lists or as {"values": [...]} are both accepted
+//   - tags sent as [key, value] pairs become a map
+//   - non-string tag, header and env values (PHP header lists, numbers) become strings
+//   - cookies sent as a raw Cookie header string (Go, Python) become a map
+//   - numeric user IDs become strings
+func normalizeSDKPayload(raw map[string]interface{}) {
+	normalizeTimestampField(raw, "timestamp")
+
+	if message, ok := raw["message"].(string); ok {
+		raw["message"] = map[string]interface{}{"message": message, "formatted": message}
+	}
+	if _, hasMessage := raw["message"]; !hasMessage {
+		if logentry, ok := raw["logentry"].(map[string]interface{}); ok {
+			raw["message"] = logentry
+		}
+	}
+
+	if exceptions, ok := raw["exception"].([]interface{}); ok {
+		raw["exception"] = map[string]interface{}{"values": exceptions}
+	}
+
+	if breadcrumbs, ok := raw["breadcrumbs"].(map[string]interface{}); ok {
+		raw["breadcrumbs"] = breadcrumbs["values"]
+	}
+	if breadcrumbs, ok := raw["breadcrumbs"].([]interface{}); ok {
+		for _, breadcrumb := range breadcrumbs {
+			if crumb, ok := breadcrumb.(map[string]interface{}); ok {
+				normalizeTimestampField(crumb, "timestamp")
+			}
+		}
+	}
+
+	if tags, ok := raw["tags"].([]interface{}); ok {
+		tagMap := make(map[string]interface{}, len(tags))
+		for _, tag := range tags {
+			if pair, ok := tag.([]interface{}); ok && len(pair) == 2 {
+				tagMap[stringifyValue(pair[0])] = pair[1]
+			}
+		}
+		raw["tags"] = tagMap
+	}
+	normalizeStringMap(raw, "tags")
+	normalizeStringMap(raw, "modules")
+
+	if request, ok := raw["request"].(map[string]interface{}); ok {
+		normalizeStringMap(request, "headers")
+		normalizeStringMap(request, "env")
+		if cookies, ok := request["cookies"].(string); ok {
+			request["cookies"] = parseCookieHeader(cookies)
+		}
+		normalizeStringMap(request, "cookies")
+		if queryString, ok := request["query_string"]; ok && queryString != nil {
+			if _, isString := queryString.(string); !isString {
+				// Some SDKs send parsed query pairs; the raw string cannot be recovered, so drop it
+				delete(request, "query_string")
+			}
+		}
+	}
+
+	if user, ok := raw["user"].(map[string]interface{}); ok {
+		if id, ok := user["id"]; ok && id != nil {
+			user["id"] = stringifyValue(id)
+		}
+	}
+}
+
+// normalizeTimestampField converts a unix timestamp in seconds to an RFC 3339 string
+func normalizeTimestampField(obj map[string]interface{}, key string) {
+	number, ok := obj[key].(json.Number)
+	if !ok {
+		return
+	}
+
+	// Parse the decimal digits directly; going through float64 loses sub-millisecond precision
+	wholeStr, fracStr, _ := strings.Cut(number.String(), ".")
+	whole, err := strconv.ParseInt(wholeStr, 10, 64)
+	if err != nil || whole < 0 || strings.ContainsAny(fracStr, "eE") {
+		seconds, err := number.Float64()
+		if err != nil {
+			delete(obj, key)
+			return
+		}
+		wholeF, frac := math.Modf(seconds)
+		obj[key] = time.Unix(int64(wholeF), int64(frac*1e9)).UTC().Format(time.RFC3339Nano)
+		return
+	}
+
+	var nanos int64
+	if fracStr != "" {
+		fracStr = (fracStr + "000000000")[:9]
+		if nanos, err = strconv.ParseInt(fracStr, 10, 64); err != nil {
+			nanos = 0
+		}
+	}
+	obj[key] = time.Unix(whole, nanos).UTC().Format(time.RFC3339Nano)
+}
+
+// normalizeStringMap coerces the values of a map field to strings, dropping nulls
+func normalizeStringMap(obj map[string]interface{}, key string) {
+	values, ok := obj[key].(map[string]interface{})
+	if !ok {
+		if obj[key] != nil {
+			// Not a map at all; drop rather than fail the whole event
+			delete(obj, key)
+		}
+		return
+	}
+
+	for k, v := range values {
+		if v == nil {
+			delete(values, k)
+			continue
+		}
+		values[k] = stringifyValue(v)
+	}
+}
+
+// stringifyValue renders a decoded JSON value as a string
+func stringifyValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, stringifyValue(item))
+		}
+		return strings.Join(parts, ", ")
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(encoded)
+	}
+}
+
+// parseCookieHeader splits a raw Cookie header into name/value pairs
+func parseCookieHeader(header string) map[string]interface{} {
+	cookies := make(map[string]interface{})
+	for _, part := range strings.Split(header, ";") {
+		name, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found || name == "" {
+			continue
+		}
+		cookies[name] = value
+	}
+	return cookies
+}
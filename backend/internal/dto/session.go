@@ -0,0 +1,67 @@
+package dto
+
+import "time"
+
+// SessionAttributes carries the release/environment a session belongs to
+type SessionAttributes struct {
+	Release     string  `json:"release"`
+	Environment *string `json:"environment,omitempty"`
+	IPAddress   *string `json:"ip_address,omitempty"`
+	UserAgent   *string `json:"user_agent,omitempty"`
+}
+
+// SessionUpdate represents a `session` envelope item sent by SDKs
+type SessionUpdate struct {
+	SessionID  string            `json:"sid"`
+	DistinctID *string           `json:"did,omitempty"`
+	Sequence   *int64            `json:"seq,omitempty"`
+	Init       bool              `json:"init,omitempty"`
+	Started    *time.Time        `json:"started"`
+	Timestamp  *time.Time        `json:"timestamp,omitempty"`
+	Duration   *float64          `json:"duration,omitempty"`
+	Status     *string           `json:"status,omitempty"`
+	Errors     int               `json:"errors,omitempty"`
+	Attrs      SessionAttributes `json:"attrs"`
+}
+
+// SessionAggregateBucket counts sessions that started in the same time bucket
+type SessionAggregateBucket struct {
+	Started    time.Time `json:"started"`
+	DistinctID *string   `json:"did,omitempty"`
+	Exited     int       `json:"exited,omitempty"`
+	Errored    int       `json:"errored,omitempty"`
+	Abnormal   int       `json:"abnormal,omitempty"`
+	Crashed    int       `json:"crashed,omitempty"`
+}
+
+// SessionAggregates represents a `sessions` envelope item sent by server-mode SDKs
+type SessionAggregates struct {
+	Aggregates []SessionAggregateBucket `json:"aggregates"`
+	Attrs      SessionAttributes        `json:"attrs"`
+}
+
+// ReleaseHealthFilters represents query parameters for release health
+type ReleaseHealthFilters struct {
+	Release     string     `json:"release,omitempty"`
+	Environment string     `json:"environment,omitempty"`
+	Since       *time.Time `json:"since,omitempty"`
+}
+
+// ReleaseHealthResponse represents session health for one release and environment
+type ReleaseHealthResponse struct {
+	Release           string  `json:"release"`
+	Environment       string  `json:"environment"`
+	TotalSessions     int64   `json:"total_sessions"`
+	CrashedSessions   int64   `json:"crashed_sessions"`
+	ErroredSessions   int64   `json:"errored_sessions"`
+	AbnormalSessions  int64   `json:"abnormal_sessions"`
+	CrashFreeSessions float64 `json:"crash_free_sessions"`
+	TotalUsers        int64   `json:"total_users"`
+	CrashedUsers      int64   `json:"crashed_users"`
+	CrashFreeUsers    float64 `json:"crash_free_users"`
+}
+
+// ReleaseHealthListResponse represents release health grouped by release and environment
+type ReleaseHealthListResponse struct {
+	Results []ReleaseHealthResponse `json:"results"`
+}
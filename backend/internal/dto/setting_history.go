@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SettingChangeActorResponse represents the user who changed a setting
+type SettingChangeActorResponse struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+}
+
+// SettingChangeResponse represents one change of a project or organization setting.
+// Actor is nil for changes made by the system or by a since deleted user.
+type SettingChangeResponse struct {
+	ID        uuid.UUID                   `json:"id"`
+	Setting   string                      `json:"setting"`
+	From      json.RawMessage             `json:"from"`
+	To        json.RawMessage             `json:"to"`
+	Actor     *SettingChangeActorResponse `json:"actor"`
+	CreatedAt time.Time                   `json:"created_at"`
+}
+
+// SettingHistoryResponse represents paginated setting changes, newest first
+type SettingHistoryResponse struct {
+	Changes    []SettingChangeResponse `json:"changes"`
+	Total      int64                   `json:"total"`
+	Page       int                     `json:"page"`
+	Limit      int                     `json:"limit"`
+	TotalPages int                     `json:"total_pages"`
+}
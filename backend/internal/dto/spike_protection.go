@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SpikeProtectionDropStats is the number of events spike protection dropped in an hour
+type SpikeProtectionDropStats struct {
+	Bucket  time.Time `json:"bucket"`
+	Dropped int64     `json:"dropped"`
+}
+
+// SpikeProtectionResponse describes a project's spike protection: the configured limits,
+// the current rate against the threshold, and the events dropped since a point in time
+type SpikeProtectionResponse struct {
+	ProjectID     uuid.UUID                  `json:"project_id"`
+	Enabled       bool                       `json:"enabled"`
+	Multiplier    float64                    `json:"multiplier"`
+	MinRate       int                        `json:"min_rate"`
+	WindowSeconds int                        `json:"window_seconds"`
+	SampleRate    float64                    `json:"sample_rate"`
+	Active        bool                       `json:"active"`        // events are being dropped this minute
+	CurrentRate   int                        `json:"current_rate"`  // events accepted this minute
+	BaselineRate  float64                    `json:"baseline_rate"` // trailing average of events per minute
+	Threshold     int                        `json:"threshold"`     // events per minute accepted before dropping; 0 while the baseline is recorded
+	Since         time.Time                  `json:"since"`
+	TotalDropped  int64                      `json:"total_dropped"`
+	Drops         []SpikeProtectionDropStats `json:"drops"`
+}
@@ -0,0 +1,94 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatusPageRequest represents the request payload for configuring an organization's status page
+type StatusPageRequest struct {
+	Enabled     *bool   `json:"enabled,omitempty"`
+	Title       *string `json:"title,omitempty" validate:"omitempty,max=255"`
+	Description *string `json:"description,omitempty"`
+}
+
+// StatusPageResponse represents the status page configuration of an organization
+type StatusPageResponse struct {
+	OrganizationID  uuid.UUID `json:"organization_id"`
+	Enabled         bool      `json:"enabled"`
+	Title           string    `json:"title"`
+	Description     *string   `json:"description"`
+	URL             string    `json:"url"`
+	SubscriberCount int64     `json:"subscriber_count"`
+}
+
+// CreateStatusComponentRequest represents the request payload for adding a status page component
+type CreateStatusComponentRequest struct {
+	Name        string      `json:"name" validate:"required,min=1,max=255"`
+	Description *string     `json:"description,omitempty"`
+	Position    int         `json:"position"`
+	ProjectIDs  []uuid.UUID `json:"project_ids,omitempty"`
+}
+
+// UpdateStatusComponentRequest represents the request payload for updating a component;
+// project_ids, when present, replaces the projects mapped to the component
+type UpdateStatusComponentRequest struct {
+	Name        *string      `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Description *string      `json:"description,omitempty"`
+	Position    *int         `json:"position,omitempty"`
+	ProjectIDs  *[]uuid.UUID `json:"project_ids,omitempty"`
+}
+
+// StatusComponentResponse represents a status page component and its current status
+type StatusComponentResponse struct {
+	ID          uuid.UUID   `json:"id"`
+	Name        string      `json:"name"`
+	Description *string     `json:"description"`
+	Position    int         `json:"position"`
+	ProjectIDs  []uuid.UUID `json:"project_ids"`
+	Status      string      `json:"status"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// StatusSubscriberResponse represents an email subscriber of a status page
+type StatusSubscriberResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Email       string     `json:"email"`
+	ConfirmedAt *time.Time `json:"confirmed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// StatusSubscribeRequest represents a public request to receive status updates by email
+type StatusSubscribeRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// PublicStatusResponse is the customer-facing status of an organization
+type PublicStatusResponse struct {
+	Organization string                  `json:"organization"`
+	Title        string                  `json:"title"`
+	Description  *string                 `json:"description"`
+	Status       string                  `json:"status"`
+	Components   []PublicStatusComponent `json:"components"`
+	Incidents    []PublicStatusIncident  `json:"incidents"`
+	UpdatedAt    time.Time               `json:"updated_at"`
+}
+
+// PublicStatusComponent is a component as shown on the public status page
+type PublicStatusComponent struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	Status      string  `json:"status"`
+}
+
+// PublicStatusIncident is an ongoing or recently resolved incident affecting public components
+type PublicStatusIncident struct {
+	ID         uuid.UUID  `json:"id"`
+	Title      string     `json:"title"`
+	Status     string     `json:"status"`
+	Components []string   `json:"components"`
+	StartedAt  time.Time  `json:"started_at"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+}
@@ -0,0 +1,140 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// TransactionEventRequest represents a Sentry transaction (`type: transaction`) payload
+type TransactionEventRequest struct {
+	EventID        *string                     `json:"event_id,omitempty"`
+	Type           string                      `json:"type"`
+	Transaction    *string                     `json:"transaction,omitempty"`
+	StartTimestamp *time.Time                  `json:"start_timestamp,omitempty"`
+	Timestamp      *time.Time                  `json:"timestamp,omitempty"`
+	Platform       *string                     `json:"platform,omitempty"`
+	Release        *string                     `json:"release,omitempty"`
+	Environment    *string                     `json:"environment,omitempty"`
+	ServerName     *string                     `json:"server_name,omitempty"`
+	Contexts       TransactionContexts         `json:"contexts"`
+	Spans          []SpanData                  `json:"spans,omitempty"`
+	Tags           map[string]string           `json:"tags,omitempty"`
+	Measurements   map[string]MeasurementValue `json:"measurements,omitempty"`
+	User           *UserContext                `json:"user,omitempty"`
+	Request        *RequestData                `json:"request,omitempty"`
+}
+
+// TransactionContexts holds the contexts a transaction needs; other contexts are ignored
+type TransactionContexts struct {
+	Trace *TraceContext `json:"trace,omitempty"`
+}
+
+// TraceContext describes the root span of a transaction
+type TraceContext struct {
+	TraceID      string                 `json:"trace_id"`
+	SpanID       *string                `json:"span_id,omitempty"`
+	ParentSpanID *string                `json:"parent_span_id,omitempty"`
+	Op           *string                `json:"op,omitempty"`
+	Status       *string                `json:"status,omitempty"`
+	Data         map[string]interface{} `json:"data,omitempty"`
+}
+
+// SpanData represents a child span of a transaction
+type SpanData struct {
+	SpanID         string                 `json:"span_id"`
+	ParentSpanID   *string                `json:"parent_span_id,omitempty"`
+	TraceID        *string                `json:"trace_id,omitempty"`
+	Op             *string                `json:"op,omitempty"`
+	Description    *string                `json:"description,omitempty"`
+	Status         *string                `json:"status,omitempty"`
+	StartTimestamp *time.Time             `json:"start_timestamp,omitempty"`
+	Timestamp      *time.Time             `json:"timestamp,omitempty"`
+	Tags           map[string]string      `json:"tags,omitempty"`
+	Data           map[string]interface{} `json:"data,omitempty"`
+}
+
+// MeasurementValue represents a measurement such as a web vital
+type MeasurementValue struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit,omitempty"`
+}
+
+// TransactionIngestResponse represents the response after a transaction is stored
+type TransactionIngestResponse struct {
+	ID        uuid.UUID `json:"id"`
+	EventID   string    `json:"event_id"`
+	ProjectID uuid.UUID `json:"project_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TransactionFilters represents filtering options for transaction queries
+type TransactionFilters struct {
+	Name        *string `json:"name,omitempty"`
+	Op          *string `json:"op,omitempty"`
+	Environment *string `json:"environment,omitempty"`
+	Page        int     `json:"page"`
+	Limit       int     `json:"limit"`
+}
+
+// TransactionResponse represents a transaction in list responses
+type TransactionResponse struct {
+	ID             uuid.UUID      `json:"id"`
+	EventID        string         `json:"event_id"`
+	Name           string         `json:"name"`
+	Op             *string        `json:"op"`
+	Status         *string        `json:"status"`
+	TraceID        string         `json:"trace_id"`
+	StartTimestamp time.Time      `json:"start_timestamp"`
+	Timestamp      time.Time      `json:"timestamp"`
+	DurationMs     float64        `json:"duration_ms"`
+	Environment    string         `json:"environment"`
+	Release        *string        `json:"release"`
+	Tags           datatypes.JSON `json:"tags,omitempty"`
+	Measurements   datatypes.JSON `json:"measurements,omitempty"`
+}
+
+// TransactionDetailResponse represents a transaction with its spans
+type TransactionDetailResponse struct {
+	TransactionResponse
+	Spans []SpanResponse `json:"spans"`
+}
+
+// SpanResponse represents a span in transaction detail responses
+type SpanResponse struct {
+	SpanID         string         `json:"span_id"`
+	ParentSpanID   *string        `json:"parent_span_id"`
+	Op             *string        `json:"op"`
+	Description    *string        `json:"description"`
+	Status         *string        `json:"status"`
+	StartTimestamp time.Time      `json:"start_timestamp"`
+	Timestamp      time.Time      `json:"timestamp"`
+	DurationMs     float64        `json:"duration_ms"`
+	Tags           datatypes.JSON `json:"tags,omitempty"`
+	Data           datatypes.JSON `json:"data,omitempty"`
+}
+
+// TransactionListResponse represents paginated transaction list response
+type TransactionListResponse struct {
+	Transactions []TransactionResponse `json:"transactions"`
+	Total        int64                 `json:"total"`
+	Page         int                   `json:"page"`
+	Limit        int                   `json:"limit"`
+	TotalPages   int                   `json:"total_pages"`
+}
+
+// TransactionSummaryResponse aggregates durations for one transaction name
+type TransactionSummaryResponse struct {
+	Name          string  `json:"name"`
+	Op            *string `json:"op"`
+	Count         int64   `json:"count"`
+	AvgDurationMs float64 `json:"avg_duration_ms"`
+	MinDurationMs float64 `json:"min_duration_ms"`
+	MaxDurationMs float64 `json:"max_duration_ms"`
+}
+
+// TransactionSummaryListResponse represents per-name transaction summaries
+type TransactionSummaryListResponse struct {
+	Results []TransactionSummaryResponse `json:"results"`
+}
@@ -21,15 +21,23 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
-// AuthResponse represents the response after successful authentication
+// AuthResponse represents the response after successful authentication. Cookie sessions
+// return the CSRF token instead of the tokens, which stay in httpOnly cookies.
 type AuthResponse struct {
-	AccessToken  string       `json:"access_token"`
-	RefreshToken string       `json:"refresh_token"`
+	AccessToken  string       `json:"access_token,omitempty"`
+	RefreshToken string       `json:"refresh_token,omitempty"`
 	TokenType    string       `json:"token_type"`
 	ExpiresIn    int64        `json:"expires_in"`
+	CSRFToken    string       `json:"csrf_token,omitempty"`
 	User         UserResponse `json:"user"`
 }
 
+// CookieSessionResponse is the response to refreshing a cookie session
+type CookieSessionResponse struct {
+	CSRFToken string `json:"csrf_token"`
+	ExpiresIn int64  `json:"expires_in"`
+}
+
 // UserResponse represents user data returned to clients (without sensitive fields)
 type UserResponse struct {
 	ID            uuid.UUID `json:"id"`
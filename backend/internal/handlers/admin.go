@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/middleware"
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type AdminHandler struct {
+	quotaService       *services.QuotaService
+	auditLogService    *services.AuditLogService
+	maintenanceService *services.MaintenanceService
+}
+
+// NewAdminHandler creates a new handler for the superuser admin API
+func NewAdminHandler(quotaService *services.QuotaService, auditLogService *services.AuditLogService, maintenanceService *services.MaintenanceService) *AdminHandler {
+	return &AdminHandler{
+		quotaService:       quotaService,
+		auditLogService:    auditLogService,
+		maintenanceService: maintenanceService,
+	}
+}
+
+// RegisterRoutes registers admin routes, all restricted to superusers
+func (h *AdminHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware.AuthMiddleware, adminMiddleware *middleware.AdminMiddleware) {
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(adminMiddleware.RequireSuperuser)
+
+		r.Get("/projects/{id}/quota", h.GetProjectQuota)
+		r.Post("/projects/{id}/quota-overrides", h.CreateQuotaOverride)
+		r.Delete("/quota-overrides/{override_id}", h.RevokeQuotaOverride)
+		r.Get("/audit-log", h.ListAuditLog)
+		r.Get("/maintenance", h.ListMaintenanceWindows)
+		r.Post("/maintenance", h.StartMaintenanceWindow)
+		r.Delete("/maintenance/{window_id}", h.EndMaintenanceWindow)
+	})
+}
+
+// GetProjectQuota returns the limits applied to a project and its override history
+func (h *AdminHandler) GetProjectQuota(w http.ResponseWriter, r *http.Request) {
+	projectID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid project ID", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.quotaService.GetProjectQuota(projectID)
+	if err != nil {
+		h.writeAdminError(w, err, "Failed to get project quota")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateQuotaOverride temporarily overrides a project's rate limit or daily quota
+func (h *AdminHandler) CreateQuotaOverride(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	projectID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid project ID", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.QuotaOverrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Reason != nil && len(*req.Reason) > 1000 {
+		http.Error(w, "Reason is too long (max 1000 characters)", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.quotaService.CreateOverride(projectID, user.ID, req)
+	if err != nil {
+		h.writeAdminError(w, err, "Failed to create quota override")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// RevokeQuotaOverride ends a quota override before it expires
+func (h *AdminHandler) RevokeQuotaOverride(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	overrideID, err := uuid.Parse(chi.URLParam(r, "override_id"))
+	if err != nil {
+		http.Error(w, "Invalid override ID", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.quotaService.RevokeOverride(overrideID, user.ID)
+	if err != nil {
+		h.writeAdminError(w, err, "Failed to revoke quota override")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// ListAuditLog lists audit log entries, filtered by ?project_id=, ?target_type=, ?target_id= and ?action=
+func (h *AdminHandler) ListAuditLog(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filters := dto.AuditLogFilters{
+		TargetType: query.Get("target_type"),
+		Action:     query.Get("action"),
+	}
+	if value := query.Get("project_id"); value != "" {
+		projectID, err := uuid.Parse(value)
+		if err != nil {
+			http.Error(w, "Invalid project_id", http.StatusBadRequest)
+			return
+		}
+		filters.ProjectID = &projectID
+	}
+	if value := query.Get("target_id"); value != "" {
+		targetID, err := uuid.Parse(value)
+		if err != nil {
+			http.Error(w, "Invalid target_id", http.StatusBadRequest)
+			return
+		}
+		filters.TargetID = &targetID
+	}
+	if page, err := strconv.Atoi(query.Get("page")); err == nil {
+		filters.Page = page
+	}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil {
+		filters.Limit = limit
+	}
+
+	response, err := h.auditLogService.ListEntries(filters)
+	if err != nil {
+		http.Error(w, "Failed to list audit log", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// ListMaintenanceWindows lists the active maintenance windows and the ones ended in the last day
+func (h *AdminHandler) ListMaintenanceWindows(w http.ResponseWriter, r *http.Request) {
+	response, err := h.maintenanceService.ListWindows()
+	if err != nil {
+		http.Error(w, "Failed to list maintenance windows", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// StartMaintenanceWindow makes the API read-only for an organization, or the whole instance
+// when no organization is given, until the window is ended
+func (h *AdminHandler) StartMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.MaintenanceWindowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.maintenanceService.StartWindow(user.ID, req)
+	if err != nil {
+		h.writeAdminError(w, err, "Failed to start maintenance window")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// EndMaintenanceWindow ends a maintenance window, making its scope writable again
+func (h *AdminHandler) EndMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	windowID, err := uuid.Parse(chi.URLParam(r, "window_id"))
+	if err != nil {
+		http.Error(w, "Invalid maintenance window ID", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.maintenanceService.EndWindow(windowID, user.ID)
+	if err != nil {
+		h.writeAdminError(w, err, "Failed to end maintenance window")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeAdminError maps admin service errors to HTTP responses
+func (h *AdminHandler) writeAdminError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrProjectNotFound):
+		http.Error(w, "Project not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrQuotaOverrideNotFound):
+		http.Error(w, "Quota override not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrQuotaOverrideNotActive):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, services.ErrQuotaOverrideInvalid):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, services.ErrOrganizationNotFound):
+		http.Error(w, "Organization not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrMaintenanceWindowNotFound):
+		http.Error(w, "Maintenance window not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrMaintenanceWindowNotActive):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, services.ErrMaintenanceWindowInvalid):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/middleware"
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type APIUsageHandler struct {
+	usageService *services.APIUsageService
+}
+
+// NewAPIUsageHandler creates a new handler for the API usage of organizations and projects
+func NewAPIUsageHandler(usageService *services.APIUsageService) *APIUsageHandler {
+	return &APIUsageHandler{
+		usageService: usageService,
+	}
+}
+
+// RegisterRoutes registers API usage routes, restricted to organization owners
+func (h *APIUsageHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware.AuthMiddleware, orgMiddleware *middleware.OrganizationMiddleware, projectMiddleware *middleware.ProjectMiddleware) {
+	r.Route("/organizations/{id}/stats/api-usage", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(orgMiddleware.RequireOrganizationAccess)
+		r.Use(orgMiddleware.RequireOwner)
+
+		r.Get("/", h.GetOrganizationUsage)
+	})
+
+	r.Route("/projects/{id}/stats/api-usage", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(projectMiddleware.RequireProjectAccess)
+		r.Use(projectMiddleware.RequireProjectOwner)
+
+		r.Get("/", h.GetProjectUsage)
+	})
+}
+
+// GetOrganizationUsage returns the API calls made against the organization and its projects
+// per user and ingest token, between the since and until days
+func (h *APIUsageHandler) GetOrganizationUsage(w http.ResponseWriter, r *http.Request) {
+	orgCtx, ok := middleware.GetOrganizationFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Organization not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	filters, ok := parseAPIUsageFilters(w, r)
+	if !ok {
+		return
+	}
+
+	usage, err := h.usageService.GetOrganizationUsage(orgCtx.ID, filters)
+	h.writeUsage(w, usage, err)
+}
+
+// GetProjectUsage returns the API calls made against the project per user and ingest token,
+// between the since and until days
+func (h *APIUsageHandler) GetProjectUsage(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	filters, ok := parseAPIUsageFilters(w, r)
+	if !ok {
+		return
+	}
+
+	usage, err := h.usageService.GetProjectUsage(project.OrganizationID, project.ID, filters)
+	h.writeUsage(w, usage, err)
+}
+
+func (h *APIUsageHandler) writeUsage(w http.ResponseWriter, usage *dto.APIUsageResponse, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidAPIUsageWindow), errors.Is(err, services.ErrAPIUsageWindowTooLong):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Failed to get API usage", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(usage)
+}
+
+// parseAPIUsageFilters reads the since and until days of an API usage query, writing a 400
+// when either is invalid
+func parseAPIUsageFilters(w http.ResponseWriter, r *http.Request) (*dto.APIUsageFilters, bool) {
+	query := r.URL.Query()
+	filters := &dto.APIUsageFilters{}
+	for name, day := range map[string]*time.Time{"since": &filters.Since, "until": &filters.Until} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := parseEventVolumeDay(value)
+		if err != nil {
+			http.Error(w, "Invalid "+name+" parameter, expected a YYYY-MM-DD date or RFC3339 timestamp", http.StatusBadRequest)
+			return nil, false
+		}
+		*day = parsed
+	}
+	return filters, true
+}
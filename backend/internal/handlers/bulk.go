@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/middleware"
+	"minisentry/internal/models"
+	"minisentry/internal/queue"
+	"minisentry/internal/services"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxBulkSize bounds how much of a bulk submission is buffered in memory
+	maxBulkSize = 20 << 20
+
+	// maxBulkEvents bounds the events of a single bulk submission
+	maxBulkEvents = 1000
+)
+
+// errBulkTooLarge is returned for bulk submissions over maxBulkSize
+var errBulkTooLarge = fmt.Errorf("bulk submission exceeds maximum size of %d bytes", maxBulkSize)
+
+// ingestStatusFailed is the status of events that failed for a reason SDKs cannot fix
+const ingestStatusFailed = "failed"
+
+// bulkIngestHandler handles bulk error submission: newline-delimited JSON error events, such
+// as those of log shippers or backfills, answered with the outcome of each line. Every event
+// counts against the project's rate limits and quotas; the request itself counts as its first.
+func (eh *ErrorHandler) bulkIngestHandler(w http.ResponseWriter, r *http.Request) {
+	projectCtx, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		eh.writeErrorResponse(w, http.StatusInternalServerError, "project not found in context")
+		return
+	}
+	receivedAt := time.Now()
+
+	if !isBulkContentType(r.Header.Get("Content-Type")) {
+		eh.writeErrorResponse(w, http.StatusUnsupportedMediaType,
+			"unsupported content type, expected application/x-ndjson")
+		return
+	}
+
+	lines, err := eh.readBulkLines(w, r)
+	if err != nil {
+		if isMaxBytesError(err) || errors.Is(err, errBulkTooLarge) {
+			eh.writeErrorResponse(w, http.StatusRequestEntityTooLarge, errBulkTooLarge.Error())
+			return
+		}
+		eh.writeErrorResponse(w, bodyErrorStatus(err), fmt.Sprintf("failed to read request body: %v", err))
+		return
+	}
+
+	events := 0
+	for _, line := range lines {
+		if len(line) > 0 {
+			events++
+		}
+	}
+	if events == 0 {
+		eh.writeErrorResponse(w, http.StatusBadRequest, "no events in bulk submission")
+		return
+	}
+	if events > maxBulkEvents {
+		eh.writeErrorResponse(w, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("bulk submission exceeds maximum of %d events", maxBulkEvents))
+		return
+	}
+
+	response := dto.BulkEventResponse{Results: make([]dto.BulkEventResult, 0, events)}
+	first := true
+	for i, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+		result := eh.ingestBulkEvent(r, projectCtx.ID, line, first, receivedAt)
+		result.Line = i + 1
+		first = false
+
+		switch result.Status {
+		case services.EventStatusStored, services.EventStatusSampled, services.EventStatusQueued:
+			response.Accepted++
+		default:
+			response.Rejected++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// readBulkLines reads the decompressed body of a bulk submission and splits it into lines,
+// with surrounding whitespace trimmed
+func (eh *ErrorHandler) readBulkLines(w http.ResponseWriter, r *http.Request) ([][]byte, error) {
+	body, err := eh.readBulkBody(w, r)
+	if err != nil {
+		return nil, err
+	}
+	return splitBulkLines(body), nil
+}
+
+// readBulkBody reads the decompressed body of a bulk submission, up to maxBulkSize
+func (eh *ErrorHandler) readBulkBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	if r.ContentLength > maxBulkSize {
+		return nil, errBulkTooLarge
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBulkSize)
+
+	bodyReader, err := eh.getBodyReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer bodyReader.Close()
+
+	// Also bounds the decompressed size of compressed bodies
+	body, err := io.ReadAll(io.LimitReader(bodyReader, maxBulkSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxBulkSize {
+		return nil, errBulkTooLarge
+	}
+	return body, nil
+}
+
+// splitBulkLines splits a body into lines, with surrounding whitespace trimmed
+func splitBulkLines(body []byte) [][]byte {
+	lines := bytes.Split(body, []byte("\n"))
+	for i, line := range lines {
+		lines[i] = bytes.TrimSpace(line)
+	}
+	return lines
+}
+
+// ingestBulkEvent decodes and ingests one event of a bulk submission. The first event was
+// already counted by the rate limit middleware.
+func (eh *ErrorHandler) ingestBulkEvent(r *http.Request, projectID uuid.UUID, line []byte, first bool, receivedAt time.Time) dto.BulkEventResult {
+	if maxSize := eh.maxEventSize(r); maxSize > 0 && len(line) > maxSize {
+		return dto.BulkEventResult{
+			Status: services.EventStatusInvalid,
+			Error:  fmt.Sprintf("event exceeds maximum size of %d bytes", maxSize),
+		}
+	}
+	if dto.PeekEventType(line) == "transaction" {
+		return dto.BulkEventResult{
+			Status: services.EventStatusInvalid,
+			Error:  "transactions are not accepted by bulk submission",
+		}
+	}
+
+	var eventData dto.ErrorEventRequest
+	if err := json.Unmarshal(line, &eventData); err != nil {
+		result := dto.BulkEventResult{
+			EventID: peekEventID(line),
+			Status:  services.EventStatusInvalid,
+			Error:   fmt.Sprintf("invalid JSON payload: %v", err),
+		}
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			result.Errors = []dto.FieldError{{
+				Path:    typeErr.Field,
+				Message: fmt.Sprintf("expected %s, got %s", jsonTypeName(typeErr.Type), typeErr.Value),
+			}}
+		}
+		return result
+	}
+	eh.errorService.CorrectClockSkew(&eventData, middleware.SentryAuthTimestamp(r), receivedAt)
+
+	source := eventSource{
+		ctx:       r.Context(),
+		clientIP:  middleware.ClientIP(r),
+		userAgent: r.Header.Get("User-Agent"),
+		buffering: bufferingWindow(r),
+	}
+	return eh.ingestEvent(source, projectID, &eventData, line, !first)
+}
+
+// eventSource is the request an event of a bulk submission or gRPC call was received with
+type eventSource struct {
+	ctx       context.Context
+	clientIP  string
+	userAgent string
+	buffering *models.MaintenanceWindow // set when the event may only be queued
+}
+
+// ingestEvent processes an error event of a bulk submission or gRPC call, queueing it when
+// asynchronous ingestion is enabled, and returns its outcome. checkLimits counts the event
+// against the project's rate limits and quotas.
+func (eh *ErrorHandler) ingestEvent(source eventSource, projectID uuid.UUID, eventData *dto.ErrorEventRequest, payload []byte, checkLimits bool) dto.BulkEventResult {
+	var eventID string
+	if eventData.EventID != nil {
+		eventID = *eventData.EventID
+	}
+	if checkLimits {
+		if limited := eh.checkRateLimits(projectID); limited != nil {
+			eh.errorService.RecordEventStatus(projectID, eventID, services.EventStatusRateLimited, limited.message)
+			return dto.BulkEventResult{EventID: eventID, Status: services.EventStatusRateLimited, Error: limited.message}
+		}
+	}
+
+	if eh.ingestQueue != nil {
+		if err := eh.errorService.ValidateErrorPayload(eventData); err != nil {
+			logRejectedPayload(source.ctx, err, payload)
+			return eventFailure(eventID, err)
+		}
+		err := eh.queueErrorEvent(projectID, eventData, source.clientIP, source.userAgent)
+		if err == nil {
+			return dto.BulkEventResult{EventID: *eventData.EventID, Status: services.EventStatusQueued}
+		}
+		if errors.Is(err, queue.ErrQueueFull) {
+			return dto.BulkEventResult{EventID: *eventData.EventID, Status: services.EventStatusRateLimited, Error: err.Error()}
+		}
+		middleware.Logf(source.ctx, "Failed to queue event %s, processing synchronously: %v", *eventData.EventID, err)
+	}
+	if source.buffering != nil {
+		return dto.BulkEventResult{EventID: eventID, Status: ingestStatusFailed, Error: source.buffering.Message}
+	}
+
+	response, err := eh.errorService.ProcessErrorEvent(projectID, eventData, source.clientIP, source.userAgent)
+	if eventData.EventID != nil {
+		eventID = *eventData.EventID
+		eh.errorService.RecordEventOutcome(projectID, eventID, err == nil && response.Sampled, err)
+	}
+	if err != nil {
+		logRejectedPayload(source.ctx, err, payload)
+		return eventFailure(eventID, err)
+	}
+	return dto.BulkEventResult{
+		EventID: response.EventID,
+		Status:  services.EventOutcomeStatus(response.Sampled, nil),
+	}
+}
+
+// eventFailure describes why an event of a bulk submission or gRPC call was not accepted
+func eventFailure(eventID string, err error) dto.BulkEventResult {
+	result := dto.BulkEventResult{EventID: eventID, Status: services.EventOutcomeStatus(false, err), Error: err.Error()}
+	var validationErr *services.ValidationError
+	if errors.As(err, &validationErr) {
+		result.Errors = validationErr.Fields
+	}
+	if result.Status == "" {
+		result.Status = ingestStatusFailed
+		result.Error = "failed to process error event"
+	}
+	return result
+}
+
+// isBulkContentType reports whether a content type is acceptable for bulk submission
+func isBulkContentType(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	switch mediaType {
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/json",
+		"application/octet-stream", "text/plain":
+		return true
+	}
+	return false
+}
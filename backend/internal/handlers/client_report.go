@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/middleware"
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ClientReportHandler struct {
+	clientReportService *services.ClientReportService
+}
+
+// NewClientReportHandler creates a new handler for SDK client report stats
+func NewClientReportHandler(clientReportService *services.ClientReportService) *ClientReportHandler {
+	return &ClientReportHandler{
+		clientReportService: clientReportService,
+	}
+}
+
+// RegisterRoutes registers client report routes
+func (h *ClientReportHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware.AuthMiddleware, projectMiddleware *middleware.ProjectMiddleware) {
+	r.Route("/projects/{id}/client-reports", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(projectMiddleware.RequireProjectAccess)
+
+		r.Get("/", h.GetClientReportStats)
+	})
+}
+
+// GetClientReportStats returns how many events the project's SDKs discarded before sending them
+func (h *ClientReportHandler) GetClientReportStats(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	filters := &dto.ClientReportFilters{
+		Category: r.URL.Query().Get("category"),
+	}
+
+	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+		since, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			http.Error(w, "Invalid since parameter, expected RFC3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		filters.Since = since
+	}
+
+	stats, err := h.clientReportService.GetClientReportStats(project.ID, filters)
+	if err != nil {
+		http.Error(w, "Failed to get client report stats", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
@@ -1,30 +1,77 @@
 package handlers
 
 import (
+	"bufio"
+	"bytes"
+	"compress/flate"
 	"compress/gzip"
+	"compress/zlib"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"minisentry/internal/dto"
 	"minisentry/internal/middleware"
+	"minisentry/internal/models"
+	"minisentry/internal/queue"
 	"minisentry/internal/services"
 
+	"github.com/andybalholm/brotli"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/klauspost/compress/zstd"
 )
 
+// maxEnvelopeSize bounds how much of an envelope body is buffered in memory
+const maxEnvelopeSize = 20 << 20
+
+// maxZstdWindowSize bounds the decoder memory a zstd-compressed body can request
+const maxZstdWindowSize = 8 << 20
+
+// errUnsupportedContentEncoding is returned for request bodies in an unknown compression
+var errUnsupportedContentEncoding = errors.New("unsupported content encoding")
+
 type ErrorHandler struct {
-	errorService *services.ErrorService
+	errorService        *services.ErrorService
+	sessionService      *services.SessionService
+	transactionService  *services.TransactionService
+	replayService       *services.ReplayService
+	attachmentService   *services.AttachmentService
+	clientReportService *services.ClientReportService
+
+	// ingestQueue, when set, makes the store endpoint process error events asynchronously
+	ingestQueue queue.EventQueue
+
+	// quotaService, when set, enforces project rate limits and daily quotas on ingestion
+	quotaService *services.QuotaService
+
+	// spikeProtection, when set, drops the excess of sudden spikes in a project's event rate
+	spikeProtection *services.SpikeProtectionService
+
+	// overload, when set, sheds events the full ingest queue cannot take instead of
+	// processing them within the request
+	overload *middleware.OverloadController
+
+	// maintenance, when set, rejects or buffers ingestion during maintenance windows
+	maintenance *services.MaintenanceService
 }
 
 // NewErrorHandler creates a new error handler
-func NewErrorHandler(errorService *services.ErrorService) *ErrorHandler {
+func NewErrorHandler(errorService *services.ErrorService, sessionService *services.SessionService, transactionService *services.TransactionService, replayService *services.ReplayService, attachmentService *services.AttachmentService, clientReportService *services.ClientReportService) *ErrorHandler {
 	return &ErrorHandler{
-		errorService: errorService,
+		errorService:        errorService,
+		sessionService:      sessionService,
+		transactionService:  transactionService,
+		replayService:       replayService,
+		attachmentService:   attachmentService,
+		clientReportService: clientReportService,
 	}
 }
 
@@ -33,18 +80,32 @@ func (eh *ErrorHandler) RegisterRoutes(r chi.Router, projectMiddleware *middlewa
 	// Sentry-compatible error ingestion endpoint (specific path to avoid conflicts)
 	r.Group(func(r chi.Router) {
 		r.Use(projectMiddleware.DSNAuth) // Use DSN authentication
+		r.Use(eh.maintenanceMiddleware)
+		r.Use(eh.rateLimitMiddleware)
 		r.Post("/api/{project_id}/store/", eh.sentryStoreHandler)
+		r.Post("/api/{project_id}/envelope/", eh.sentryEnvelopeHandler)
+		r.Post("/api/{project_id}/security/", eh.sentrySecurityHandler)
+		r.Post("/api/{project_id}/minidump/", eh.sentryMinidumpHandler)
+		r.Post("/api/{project_id}/integration/otlp/v1/logs", eh.otlpLogsHandler)
 	})
 
 	// Alternative error ingestion endpoints
 	r.Route("/api/v1/errors", func(r chi.Router) {
 		r.Use(projectMiddleware.DSNAuth) // Use DSN authentication
-		r.Post("/ingest", eh.errorIngestHandler)
+		r.With(eh.maintenanceMiddleware, eh.rateLimitMiddleware).Post("/ingest", eh.errorIngestHandler)
+		r.With(eh.maintenanceMiddleware, eh.rateLimitMiddleware).Post("/bulk", eh.bulkIngestHandler)
+		r.With(eh.maintenanceMiddleware, eh.rateLimitMiddleware).Post("/logs", eh.logInputHandler)
 		r.Get("/stats", eh.errorStatsHandler)
 		r.Get("/issues/{issue_id}/events", eh.issueEventsHandler)
 	})
 }
 
+// RegisterTunnelRoute registers the first-party tunnel endpoint used by the SDK `tunnel` option.
+// Tunneled requests carry no auth headers, so the DSN is read from the envelope header instead.
+func (eh *ErrorHandler) RegisterTunnelRoute(r chi.Router, projectMiddleware *middleware.ProjectMiddleware, path string) {
+	r.Post(path, eh.tunnelHandler(projectMiddleware))
+}
+
 // sentryStoreHandler handles the Sentry-compatible store endpoint
 func (eh *ErrorHandler) sentryStoreHandler(w http.ResponseWriter, r *http.Request) {
 	// Get project from context (set by DSN auth middleware)
@@ -75,6 +136,333 @@ func (eh *ErrorHandler) sentryStoreHandler(w http.ResponseWriter, r *http.Reques
 	eh.handleErrorIngestion(w, r, projectID)
 }
 
+// sentryEnvelopeHandler handles the Sentry-compatible envelope endpoint
+func (eh *ErrorHandler) sentryEnvelopeHandler(w http.ResponseWriter, r *http.Request) {
+	projectCtx, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		eh.writeErrorResponse(w, http.StatusInternalServerError, "project not found in context")
+		return
+	}
+
+	projectID, err := uuid.Parse(chi.URLParam(r, "project_id"))
+	if err != nil {
+		eh.writeErrorResponse(w, http.StatusBadRequest, "invalid project ID format")
+		return
+	}
+
+	if projectID != projectCtx.ID {
+		eh.writeErrorResponse(w, http.StatusForbidden, "project ID mismatch")
+		return
+	}
+
+	envelope, err := eh.readEnvelope(r)
+	if err != nil {
+		eh.writeErrorResponse(w, bodyErrorStatus(err), err.Error())
+		return
+	}
+
+	eh.handleEnvelope(w, r, projectID, envelope)
+}
+
+// sentrySecurityHandler handles browser CSP violation reports. Browsers cannot send auth
+// headers, so the report-uri carries sentry_key (and optionally sentry_environment and
+// sentry_release) as query parameters.
+func (eh *ErrorHandler) sentrySecurityHandler(w http.ResponseWriter, r *http.Request) {
+	projectCtx, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		eh.writeErrorResponse(w, http.StatusInternalServerError, "project not found in context")
+		return
+	}
+
+	projectID, err := uuid.Parse(chi.URLParam(r, "project_id"))
+	if err != nil {
+		eh.writeErrorResponse(w, http.StatusBadRequest, "invalid project ID format")
+		return
+	}
+
+	if projectID != projectCtx.ID {
+		eh.writeErrorResponse(w, http.StatusForbidden, "project ID mismatch")
+		return
+	}
+
+	mediaType := strings.TrimSpace(strings.ToLower(strings.Split(r.Header.Get("Content-Type"), ";")[0]))
+	switch mediaType {
+	case "application/csp-report", "application/reports+json", "application/json":
+	default:
+		eh.writeErrorResponse(w, http.StatusUnsupportedMediaType,
+			"unsupported content type, expected application/csp-report or application/reports+json")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxEnvelopeSize))
+	if err != nil {
+		eh.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("failed to read request body: %v", err))
+		return
+	}
+
+	reports, err := dto.ParseCSPReports(body)
+	if err != nil {
+		eh.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	var release *string
+	if value := query.Get("sentry_release"); value != "" {
+		release = &value
+	}
+	clientIP := middleware.ClientIP(r)
+	userAgent := r.Header.Get("User-Agent")
+
+	for i := range reports {
+		if _, err := eh.errorService.ProcessCSPReport(projectID, &reports[i], query.Get("sentry_environment"), release, clientIP, userAgent); err != nil {
+			eh.writeProcessingError(w, err)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// tunnelHandler parses the envelope, authenticates it through DSNAuth and ingests its items
+func (eh *ErrorHandler) tunnelHandler(projectMiddleware *middleware.ProjectMiddleware) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		envelope, err := eh.readEnvelope(r)
+		if err != nil {
+			eh.writeErrorResponse(w, bodyErrorStatus(err), err.Error())
+			return
+		}
+
+		if envelope.Header.DSN == "" {
+			eh.writeErrorResponse(w, http.StatusBadRequest, "envelope header is missing the DSN")
+			return
+		}
+
+		ingest := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			projectCtx, ok := middleware.GetProjectFromContext(r.Context())
+			if !ok {
+				eh.writeErrorResponse(w, http.StatusInternalServerError, "project not found in context")
+				return
+			}
+			eh.handleEnvelope(w, r, projectCtx.ID, envelope)
+		})
+
+		ctx := middleware.WithEnvelopeDSN(r.Context(), envelope.Header.DSN)
+		projectMiddleware.DSNAuth(eh.maintenanceMiddleware(eh.rateLimitMiddleware(ingest))).ServeHTTP(w, r.WithContext(ctx))
+	}
+}
+
+// readEnvelope reads and parses an envelope request body
+func (eh *ErrorHandler) readEnvelope(r *http.Request) (*dto.Envelope, error) {
+	bodyReader, err := eh.getBodyReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	defer bodyReader.Close()
+
+	body, err := io.ReadAll(io.LimitReader(bodyReader, maxEnvelopeSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %v", err)
+	}
+	if len(body) > maxEnvelopeSize {
+		return nil, fmt.Errorf("envelope exceeds maximum size of %d bytes", maxEnvelopeSize)
+	}
+
+	envelope, err := dto.ParseEnvelope(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid envelope: %v", err)
+	}
+
+	return envelope, nil
+}
+
+// handleEnvelope ingests the supported items of an envelope for the given project
+func (eh *ErrorHandler) handleEnvelope(w http.ResponseWriter, r *http.Request, projectID uuid.UUID, envelope *dto.Envelope) {
+	receivedAt := time.Now()
+	sentAt := envelope.Header.SentAt
+	if sentAt == nil {
+		sentAt = middleware.SentryAuthTimestamp(r)
+	}
+	clientIP := middleware.ClientIP(r)
+	userAgent := r.Header.Get("User-Agent")
+
+	response := dto.EnvelopeResponse{}
+	if envelope.Header.EventID != nil {
+		response.ID = *envelope.Header.EventID
+	}
+
+	// Attachments are stored once the event they belong to has been ingested
+	var eventID uuid.UUID
+	var attachments []dto.EnvelopeItem
+
+	for _, item := range envelope.Items {
+		switch item.Header.Type {
+		case "event":
+			if maxSize := eh.maxEventSize(r); maxSize > 0 && len(item.Payload) > maxSize {
+				eh.writeEventTooLarge(w, maxSize)
+				return
+			}
+			var eventData dto.ErrorEventRequest
+			if err := json.Unmarshal(item.Payload, &eventData); err != nil {
+				eh.writeDecodeError(w, r, "invalid event item", err, item.Payload)
+				return
+			}
+			if eventData.EventID == nil {
+				eventData.EventID = envelope.Header.EventID
+			}
+			eh.errorService.CorrectClockSkew(&eventData, sentAt, receivedAt)
+
+			result, err := eh.errorService.ProcessErrorEvent(projectID, &eventData, clientIP, userAgent)
+			if eventData.EventID != nil {
+				eh.errorService.RecordEventOutcome(projectID, *eventData.EventID, err == nil && result.Sampled, err)
+			}
+			if err != nil {
+				logRejectedPayload(r.Context(), err, item.Payload)
+				eh.writeProcessingError(w, err)
+				return
+			}
+			response.ID = result.EventID
+			eventID, _ = uuid.Parse(result.ID)
+		case "attachment":
+			attachments = append(attachments, item)
+		case "transaction":
+			var transaction dto.TransactionEventRequest
+			if err := json.Unmarshal(item.Payload, &transaction); err != nil {
+				eh.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid transaction item: %v", err))
+				return
+			}
+			if transaction.EventID == nil {
+				transaction.EventID = envelope.Header.EventID
+			}
+
+			result, err := eh.transactionService.ProcessTransaction(projectID, &transaction)
+			if err != nil {
+				eh.writeProcessingError(w, err)
+				return
+			}
+			response.ID = result.EventID
+		case "session":
+			var update dto.SessionUpdate
+			if err := json.Unmarshal(item.Payload, &update); err != nil {
+				eh.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid session item: %v", err))
+				return
+			}
+
+			if err := eh.sessionService.ProcessSession(projectID, &update); err != nil {
+				eh.writeProcessingError(w, err)
+				return
+			}
+		case "sessions":
+			var aggregates dto.SessionAggregates
+			if err := json.Unmarshal(item.Payload, &aggregates); err != nil {
+				eh.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid sessions item: %v", err))
+				return
+			}
+
+			if err := eh.sessionService.ProcessSessionAggregates(projectID, &aggregates); err != nil {
+				eh.writeProcessingError(w, err)
+				return
+			}
+		case "replay_event":
+			var replayEvent dto.ReplayEventRequest
+			if err := json.Unmarshal(item.Payload, &replayEvent); err != nil {
+				eh.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid replay_event item: %v", err))
+				return
+			}
+
+			if err := eh.replayService.ProcessReplayEvent(projectID, &replayEvent); err != nil {
+				eh.writeProcessingError(w, err)
+				return
+			}
+		case "client_report":
+			var report dto.ClientReport
+			if err := json.Unmarshal(item.Payload, &report); err != nil {
+				eh.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid client_report item: %v", err))
+				return
+			}
+
+			if err := eh.clientReportService.ProcessClientReport(projectID, &report); err != nil {
+				eh.writeProcessingError(w, err)
+				return
+			}
+		case "replay_recording":
+			// The envelope header carries the replay ID; the recording itself is not kept
+			var replayID string
+			if envelope.Header.EventID != nil {
+				replayID = *envelope.Header.EventID
+			}
+
+			if err := eh.replayService.ProcessReplayRecording(projectID, replayID, item.Payload); err != nil {
+				eh.writeProcessingError(w, err)
+				return
+			}
+		default:
+			// Item types that are not supported yet are accepted and dropped, like Sentry does
+		}
+	}
+
+	if len(attachments) > 0 {
+		if err := eh.storeEnvelopeAttachments(r, projectID, envelope, eventID, attachments, &response); err != nil {
+			eh.writeProcessingError(w, err)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// storeEnvelopeAttachments stores attachment items for the envelope's event. A minidump sent
+// without an event item (sentry-native) creates the crash event itself.
+func (eh *ErrorHandler) storeEnvelopeAttachments(r *http.Request, projectID uuid.UUID, envelope *dto.Envelope, eventID uuid.UUID, attachments []dto.EnvelopeItem, response *dto.EnvelopeResponse) error {
+	if eventID == uuid.Nil {
+		for _, item := range attachments {
+			if item.Header.AttachmentType != models.AttachmentTypeMinidump {
+				continue
+			}
+
+			result, err := eh.errorService.ProcessMinidump(projectID, &dto.ErrorEventRequest{EventID: envelope.Header.EventID}, item.Payload, middleware.ClientIP(r), r.Header.Get("User-Agent"))
+			if err != nil {
+				return err
+			}
+			response.ID = result.EventID
+			eventID, _ = uuid.Parse(result.ID)
+			break
+		}
+	}
+
+	if eventID == uuid.Nil && envelope.Header.EventID != nil {
+		// The event was sent in an earlier envelope
+		resolved, err := eh.attachmentService.ResolveEventID(projectID, *envelope.Header.EventID)
+		if err != nil && !errors.Is(err, services.ErrEventNotFound) {
+			return err
+		}
+		eventID = resolved
+	}
+
+	if eventID == uuid.Nil {
+		middleware.Logf(r.Context(), "Dropping %d attachment(s) without a known event for project %s", len(attachments), projectID)
+		return nil
+	}
+
+	for _, item := range attachments {
+		name := item.Header.Filename
+		if name == "" {
+			name = "attachment"
+		}
+		attachmentType := item.Header.AttachmentType
+		if attachmentType == "" {
+			attachmentType = models.AttachmentTypeDefault
+		}
+
+		if _, err := eh.attachmentService.StoreAttachment(projectID, eventID, name, item.Header.ContentType, attachmentType, bytes.NewReader(item.Payload)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // errorIngestHandler handles the alternative error ingestion endpoint
 func (eh *ErrorHandler) errorIngestHandler(w http.ResponseWriter, r *http.Request) {
 	// Get project from context (set by DSN auth middleware)
@@ -89,6 +477,8 @@ func (eh *ErrorHandler) errorIngestHandler(w http.ResponseWriter, r *http.Reques
 
 // handleErrorIngestion processes the error ingestion request
 func (eh *ErrorHandler) handleErrorIngestion(w http.ResponseWriter, r *http.Request, projectID uuid.UUID) {
+	receivedAt := time.Now()
+
 	// Check content type
 	contentType := r.Header.Get("Content-Type")
 	if !eh.isValidContentType(contentType) {
@@ -97,41 +487,86 @@ func (eh *ErrorHandler) handleErrorIngestion(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// Read the body up to the event size limit before decoding anything
+	maxSize := eh.maxEventSize(r)
+	if maxSize > 0 {
+		if r.ContentLength > int64(maxSize) {
+			eh.writeEventTooLarge(w, maxSize)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, int64(maxSize))
+	}
+
 	// Get request body reader
 	bodyReader, err := eh.getBodyReader(r)
 	if err != nil {
-		eh.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("failed to read request body: %v", err))
+		if isMaxBytesError(err) {
+			eh.writeEventTooLarge(w, maxSize)
+			return
+		}
+		eh.writeErrorResponse(w, bodyErrorStatus(err), fmt.Sprintf("failed to read request body: %v", err))
 		return
 	}
 	defer bodyReader.Close()
 
+	var limitedReader io.Reader = bodyReader
+	if maxSize > 0 {
+		// Also bounds the decompressed size of compressed bodies
+		limitedReader = io.LimitReader(bodyReader, int64(maxSize)+1)
+	}
+	body, err := io.ReadAll(limitedReader)
+	if err != nil {
+		if isMaxBytesError(err) {
+			eh.writeEventTooLarge(w, maxSize)
+			return
+		}
+		eh.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("failed to read request body: %v", err))
+		return
+	}
+	if maxSize > 0 && len(body) > maxSize {
+		eh.writeEventTooLarge(w, maxSize)
+		return
+	}
+
+	// Transactions share the store endpoint but go through their own pipeline
+	if dto.PeekEventType(body) == "transaction" {
+		if window := bufferingWindow(r); window != nil {
+			eh.writeMaintenance(w, window)
+			return
+		}
+		eh.handleTransaction(w, projectID, body)
+		return
+	}
+
 	// Parse the error event data
 	var eventData dto.ErrorEventRequest
-	if err := json.NewDecoder(bodyReader).Decode(&eventData); err != nil {
-		eh.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON payload: %v", err))
+	if err := json.Unmarshal(body, &eventData); err != nil {
+		eh.writeDecodeError(w, r, "invalid JSON payload", err, body)
+		return
+	}
+	eh.errorService.CorrectClockSkew(&eventData, middleware.SentryAuthTimestamp(r), receivedAt)
+
+	// Queue the event when asynchronous ingestion is enabled
+	if eh.ingestQueue != nil && eh.enqueueErrorEvent(w, r, projectID, &eventData, body) {
+		return
+	}
+	if window := bufferingWindow(r); window != nil {
+		eh.writeMaintenance(w, window)
 		return
 	}
 
 	// Get client information
-	clientIP := eh.getClientIP(r)
+	clientIP := middleware.ClientIP(r)
 	userAgent := r.Header.Get("User-Agent")
 
 	// Process the error event
 	response, err := eh.errorService.ProcessErrorEvent(projectID, &eventData, clientIP, userAgent)
+	if eventData.EventID != nil {
+		eh.errorService.RecordEventOutcome(projectID, *eventData.EventID, err == nil && response.Sampled, err)
+	}
 	if err != nil {
-		// Handle different types of errors
-		switch {
-		case strings.Contains(err.Error(), "validation failed"):
-			eh.writeErrorResponse(w, http.StatusBadRequest, err.Error())
-		case strings.Contains(err.Error(), "project not found"):
-			eh.writeErrorResponse(w, http.StatusNotFound, "project not found")
-		case strings.Contains(err.Error(), "project is inactive"):
-			eh.writeErrorResponse(w, http.StatusForbidden, "project is inactive")
-		case strings.Contains(err.Error(), "event already exists"):
-			eh.writeErrorResponse(w, http.StatusConflict, "event already exists")
-		default:
-			eh.writeErrorResponse(w, http.StatusInternalServerError, "failed to process error event")
-		}
+		logRejectedPayload(r.Context(), err, body)
+		eh.writeProcessingError(w, err)
 		return
 	}
 
@@ -141,6 +576,130 @@ func (eh *ErrorHandler) handleErrorIngestion(w http.ResponseWriter, r *http.Requ
 	json.NewEncoder(w).Encode(response)
 }
 
+// maxEventSize returns the event size limit of the request's project, 0 when unlimited
+func (eh *ErrorHandler) maxEventSize(r *http.Request) int {
+	projectLimit := 0
+	if projectCtx, ok := middleware.GetProjectFromContext(r.Context()); ok {
+		projectLimit = projectCtx.MaxEventSize
+	}
+	return eh.errorService.MaxEventSize(projectLimit)
+}
+
+// writeEventTooLarge rejects an event over the size limit
+func (eh *ErrorHandler) writeEventTooLarge(w http.ResponseWriter, maxSize int) {
+	eh.writeErrorResponse(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("event exceeds maximum size of %d bytes", maxSize))
+}
+
+func isMaxBytesError(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+// handleTransaction stores a transaction event sent to the store endpoint
+func (eh *ErrorHandler) handleTransaction(w http.ResponseWriter, projectID uuid.UUID, body []byte) {
+	var transaction dto.TransactionEventRequest
+	if err := json.Unmarshal(body, &transaction); err != nil {
+		eh.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON payload: %v", err))
+		return
+	}
+
+	response, err := eh.transactionService.ProcessTransaction(projectID, &transaction)
+	if err != nil {
+		eh.writeProcessingError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeProcessingError maps ingestion service failures to HTTP responses
+func (eh *ErrorHandler) writeProcessingError(w http.ResponseWriter, err error) {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		middleware.WriteSentryValidationError(w, http.StatusBadRequest, err.Error(), validationErr.Fields)
+	case errors.Is(err, services.ErrInvalidEventData), errors.Is(err, services.ErrInvalidSessionData),
+		errors.Is(err, services.ErrInvalidTransactionData), errors.Is(err, services.ErrInvalidCSPReport),
+		errors.Is(err, services.ErrInvalidReplayData), errors.Is(err, services.ErrInvalidMinidump),
+		errors.Is(err, services.ErrInvalidClientReport):
+		eh.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+	case strings.Contains(err.Error(), "project not found"):
+		eh.writeErrorResponse(w, http.StatusNotFound, "project not found")
+	case strings.Contains(err.Error(), "project is inactive"):
+		eh.writeErrorResponse(w, http.StatusForbidden, "project is inactive")
+	case errors.Is(err, services.ErrEventExists):
+		eh.writeErrorResponse(w, http.StatusConflict, "event already exists")
+	case errors.Is(err, services.ErrEventFiltered):
+		eh.writeErrorResponse(w, http.StatusForbidden, "event discarded by inbound filter")
+	case errors.Is(err, services.ErrEventDropped):
+		eh.writeErrorResponse(w, http.StatusForbidden, "event dropped by event processor")
+	default:
+		eh.writeErrorResponse(w, http.StatusInternalServerError, "failed to process error event")
+	}
+}
+
+// writeDecodeError rejects an event that is not valid JSON, naming the offending field when
+// one has a value of the wrong type
+func (eh *ErrorHandler) writeDecodeError(w http.ResponseWriter, r *http.Request, detail string, err error, payload []byte) {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || typeErr.Field == "" {
+		eh.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("%s: %v", detail, err))
+		return
+	}
+
+	fieldErrors := []dto.FieldError{{
+		Path:    typeErr.Field,
+		Message: fmt.Sprintf("expected %s, got %s", jsonTypeName(typeErr.Type), typeErr.Value),
+	}}
+	logRejectedPayload(r.Context(), &services.ValidationError{Fields: fieldErrors}, payload)
+	middleware.WriteSentryValidationError(w, http.StatusBadRequest, fmt.Sprintf("%s: %v", detail, err), fieldErrors)
+}
+
+// jsonTypeName names the JSON type a Go type is decoded from
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return jsonTypeName(t.Elem())
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return t.String()
+}
+
+// maxRejectedPayloadSample bounds the bytes of a rejected event's payload that are logged
+const maxRejectedPayloadSample = 1024
+
+// logRejectedPayload logs why an event failed validation with the start of its payload, so
+// operators can see what an SDK sent
+func logRejectedPayload(ctx context.Context, err error, payload []byte) {
+	var validationErr *services.ValidationError
+	if !errors.As(err, &validationErr) {
+		return
+	}
+	if payload == nil {
+		// Events not received as JSON, such as those of gRPC calls
+		middleware.Logf(ctx, "Rejected invalid event: %v", validationErr)
+		return
+	}
+	sample := string(payload)
+	if len(payload) > maxRejectedPayloadSample {
+		sample = string(payload[:maxRejectedPayloadSample]) + fmt.Sprintf("... (%d bytes)", len(payload))
+	}
+	middleware.Logf(ctx, "Rejected invalid event: %v; payload: %s", validationErr, sample)
+}
+
 // errorStatsHandler returns error statistics for the authenticated project
 func (eh *ErrorHandler) errorStatsHandler(w http.ResponseWriter, r *http.Request) {
 	// Get project from context
@@ -229,7 +788,7 @@ func (eh *ErrorHandler) issueEventsHandler(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Get events for the issue
-	events, err := eh.errorService.GetIssueEvents(issueID, limit, offset)
+	events, err := eh.errorService.GetIssueEvents(projectCtx.ID, issueID, limit, offset)
 	if err != nil {
 		eh.writeErrorResponse(w, http.StatusInternalServerError, "failed to get issue events")
 		return
@@ -258,7 +817,7 @@ func (eh *ErrorHandler) isValidContentType(contentType string) bool {
 
 	validTypes := []string{
 		"application/json",
-		"application/octet-stream", // For gzipped payloads
+		"application/octet-stream", // For compressed payloads
 		"text/plain",               // Some clients send this
 	}
 
@@ -271,68 +830,97 @@ func (eh *ErrorHandler) isValidContentType(contentType string) bool {
 	return false
 }
 
-// getBodyReader returns an appropriate reader for the request body
+// getBodyReader returns a reader of the decompressed request body. Bodies compressed with
+// gzip, deflate, br or zstd per Content-Encoding are decoded; application/octet-stream
+// bodies without an encoding are sniffed for gzip, zlib and zstd magic bytes.
 func (eh *ErrorHandler) getBodyReader(r *http.Request) (io.ReadCloser, error) {
-	// Check if the content is gzip compressed
-	if r.Header.Get("Content-Encoding") == "gzip" {
-		gzipReader, err := gzip.NewReader(r.Body)
+	body := bufio.NewReader(r.Body)
+	
+	encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+	if encoding == "" || encoding == "identity" {
+		mediaType := strings.ToLower(strings.TrimSpace(strings.Split(r.Header.Get("Content-Type"), ";")[0]))
+		if mediaType != "application/octet-stream" {
+			return r.Body, nil
+		}
+		encoding = sniffContentEncoding(body)
+	}
+	
+	switch encoding {
+	case "", "identity":
+		return readCloser{Reader: body, Closer: r.Body}, nil
+	case "gzip", "x-gzip":
+		gzipReader, err := gzip.NewReader(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
 		}
 		return gzipReader, nil
-	}
-
-	// For application/octet-stream, also try gzip
-	if r.Header.Get("Content-Type") == "application/octet-stream" {
-		// Try to read as gzip first
-		gzipReader, err := gzip.NewReader(r.Body)
-		if err == nil {
-			return gzipReader, nil
+	case "deflate":
+		// HTTP deflate is zlib-wrapped, but some clients send raw deflate streams
+		if isZlibHeader(body) {
+			zlibReader, err := zlib.NewReader(body)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create zlib reader: %w", err)
+			}
+			return zlibReader, nil
+		}
+		return flate.NewReader(body), nil
+	case "br":
+		return io.NopCloser(brotli.NewReader(body)), nil
+	case "zstd":
+		zstdReader, err := zstd.NewReader(body,
+			zstd.WithDecoderConcurrency(1),
+			zstd.WithDecoderLowmem(true),
+			zstd.WithDecoderMaxWindow(maxZstdWindowSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd reader: %w", err)
 		}
-		// If not gzip, reset and use raw body
-		// Note: This is a simplified approach. In production, you might want to buffer and detect format
+		return zstdReader.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedContentEncoding, encoding)
 	}
-
-	return r.Body, nil
 }
 
-// getClientIP extracts the client IP address from the request
-func (eh *ErrorHandler) getClientIP(r *http.Request) string {
-	// Check X-Forwarded-For header (used by proxies)
-	xForwardedFor := r.Header.Get("X-Forwarded-For")
-	if xForwardedFor != "" {
-		// X-Forwarded-For can contain multiple IPs, use the first one
-		ips := strings.Split(xForwardedFor, ",")
-		return strings.TrimSpace(ips[0])
+// sniffContentEncoding detects a compressed body from its magic bytes, "" when it is not compressed
+func sniffContentEncoding(body *bufio.Reader) string {
+	magic, _ := body.Peek(4)
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		return "gzip"
+	case bytes.HasPrefix(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return "zstd"
+	case isZlibHeader(body):
+		return "deflate"
 	}
+	return ""
+}
 
-	// Check X-Real-IP header (used by nginx)
-	xRealIP := r.Header.Get("X-Real-IP")
-	if xRealIP != "" {
-		return xRealIP
+// isZlibHeader reports whether the body starts with a zlib header: deflate compression
+// with a header checksum divisible by 31
+func isZlibHeader(body *bufio.Reader) bool {
+	header, err := body.Peek(2)
+	if err != nil {
+		return false
 	}
+	return header[0]&0x0f == 8 && header[0]>>4 <= 7 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0
+}
 
-	// Fall back to RemoteAddr
-	ip := r.RemoteAddr
-	if colonIndex := strings.LastIndex(ip, ":"); colonIndex != -1 {
-		ip = ip[:colonIndex]
+// bodyErrorStatus is the response status for a request body that cannot be read
+func bodyErrorStatus(err error) int {
+	if errors.Is(err, errUnsupportedContentEncoding) {
+		return http.StatusUnsupportedMediaType
 	}
+	return http.StatusBadRequest
+}
 
-	return ip
+// readCloser reads from a wrapped body and closes the original
+type readCloser struct {
+	io.Reader
+	io.Closer
 }
 
-// writeErrorResponse writes a JSON error response
+// writeErrorResponse writes an error in Sentry's format, with the X-Sentry-Error header SDKs log
 func (eh *ErrorHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-
-	response := map[string]interface{}{
-		"error":   http.StatusText(statusCode),
-		"message": message,
-		"status":  statusCode,
-	}
-
-	json.NewEncoder(w).Encode(response)
+	middleware.WriteSentryError(w, statusCode, message)
 }
 
 // authMiddleware for error ingestion endpoints (uses DSN authentication)
@@ -343,11 +931,144 @@ func (eh *ErrorHandler) authMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// rateLimitMiddleware could be added for rate limiting error ingestion
+// UseQuotas makes the ingestion endpoints enforce project rate limits and daily quotas
+func (eh *ErrorHandler) UseQuotas(quotaService *services.QuotaService) {
+	eh.quotaService = quotaService
+}
+
+// UseSpikeProtection makes the ingestion endpoints drop the excess of event spikes
+func (eh *ErrorHandler) UseSpikeProtection(spikeProtection *services.SpikeProtectionService) {
+	eh.spikeProtection = spikeProtection
+}
+
+// rateLimitMiddleware rejects ingestion requests dropped by spike protection or over the
+// project's rate limit or daily quota with 429, telling SDKs when to retry through
+// Retry-After and X-Sentry-Rate-Limits. It must run after DSN authentication.
 func (eh *ErrorHandler) rateLimitMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// TODO: Implement rate limiting based on project/DSN
-		// For now, just pass through
+		if eh.quotaService == nil && eh.spikeProtection == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		projectCtx, ok := middleware.GetProjectFromContext(r.Context())
+		if !ok {
+			eh.writeErrorResponse(w, http.StatusInternalServerError, "project not found in context")
+			return
+		}
+
+		if limited := eh.checkRateLimits(projectCtx.ID); limited != nil {
+			eh.recordRateLimited(r, projectCtx.ID, limited.message)
+			eh.writeRateLimited(w, limited.retryAfter, limited.reasonCode, limited.message)
+			return
+		}
+
 		next.ServeHTTP(w, r)
 	})
-}
\ No newline at end of file
+}
+
+// rateLimited describes why an event was rate limited
+type rateLimited struct {
+	retryAfter time.Duration
+	reasonCode string // reported to SDKs when set
+	message    string
+}
+
+// checkRateLimits counts an event against the project's spike protection and quotas,
+// returning why it is rate limited or nil when it is allowed
+func (eh *ErrorHandler) checkRateLimits(projectID uuid.UUID) *rateLimited {
+	limited := eh.rateLimitReason(projectID)
+	if limited != nil {
+		eh.errorService.CountRateLimited(projectID)
+	}
+	return limited
+}
+
+// rateLimitReason checks an event against the project's spike protection and quotas
+func (eh *ErrorHandler) rateLimitReason(projectID uuid.UUID) *rateLimited {
+	// Spike protection runs first so dropped events do not use up the project's quota
+	if eh.spikeProtection != nil {
+		if check := eh.spikeProtection.Check(projectID); !check.Allowed {
+			return &rateLimited{
+				retryAfter: check.RetryAfter,
+				reasonCode: "spike_protection",
+				message:    "project event rate spike, excess events are dropped",
+			}
+		}
+	}
+
+	if eh.quotaService != nil {
+		if check := eh.quotaService.Check(projectID); !check.Allowed {
+			message := "project rate limit exceeded"
+			if check.Kind == models.QuotaDailyQuota {
+				message = "project daily quota exceeded"
+			}
+			return &rateLimited{retryAfter: check.RetryAfter, message: message}
+		}
+	}
+	return nil
+}
+
+// maxRateLimitedPeek bounds how much of a rate limited request is read for its event ID
+const maxRateLimitedPeek = 64 << 10
+
+// recordRateLimited records the status of a rate limited event when event statuses are
+// tracked, reading its ID from the start of the request: the envelope header or the event
+func (eh *ErrorHandler) recordRateLimited(r *http.Request, projectID uuid.UUID, reason string) {
+	if !eh.errorService.TracksEventStatus() {
+		return
+	}
+	bodyReader, err := eh.getBodyReader(r)
+	if err != nil {
+		return
+	}
+	defer bodyReader.Close()
+
+	head, _ := io.ReadAll(io.LimitReader(bodyReader, maxRateLimitedPeek))
+	if strings.HasSuffix(r.URL.Path, "/envelope/") {
+		head, _, _ = bytes.Cut(head, []byte("\n"))
+	}
+	if eventID := peekEventID(head); eventID != "" {
+		eh.errorService.RecordEventStatus(projectID, eventID, services.EventStatusRateLimited, reason)
+	}
+}
+
+// peekEventID returns the top-level event_id of a JSON object, which may be cut short
+// after it
+func peekEventID(data []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return ""
+	}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		if key == "event_id" {
+			var eventID string
+			if err := decoder.Decode(&eventID); err != nil {
+				return ""
+			}
+			return eventID
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return ""
+		}
+	}
+	return ""
+}
+
+// writeRateLimited writes a 429 response with the Retry-After and X-Sentry-Rate-Limits
+// headers SDKs back off on; reasonCode is reported to SDKs when set
+func (eh *ErrorHandler) writeRateLimited(w http.ResponseWriter, retry time.Duration, reasonCode, message string) {
+	retryAfter := int(retry.Seconds()) + 1
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	rateLimits := fmt.Sprintf("%d::project", retryAfter)
+	if reasonCode != "" {
+		rateLimits += ":" + reasonCode
+	}
+	w.Header().Set("X-Sentry-Rate-Limits", rateLimits)
+	eh.writeErrorResponse(w, http.StatusTooManyRequests, message)
+}
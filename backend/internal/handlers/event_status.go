@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"minisentry/internal/middleware"
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type EventStatusHandler struct {
+	errorService *services.ErrorService
+}
+
+// NewEventStatusHandler creates a handler telling what became of events sent to projects
+func NewEventStatusHandler(errorService *services.ErrorService) *EventStatusHandler {
+	return &EventStatusHandler{
+		errorService: errorService,
+	}
+}
+
+// RegisterRoutes registers the event status route
+func (h *EventStatusHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware.AuthMiddleware, projectMiddleware *middleware.ProjectMiddleware) {
+	r.Route("/projects/{id}/events/{event_id}/status", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(projectMiddleware.RequireProjectAccess)
+
+		r.Get("/", h.GetEventStatus)
+	})
+}
+
+// GetEventStatus tells whether an event sent to the project was queued, stored, filtered,
+// rate limited or otherwise discarded, so SDK integration tests can confirm events
+// accepted with 202 by asynchronous ingestion
+func (h *EventStatusHandler) GetEventStatus(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	status, err := h.errorService.GetEventStatus(project.ID, chi.URLParam(r, "event_id"))
+	if err != nil {
+		if errors.Is(err, services.ErrEventStatusUnknown) {
+			http.Error(w, "Event not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get event status", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/middleware"
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type EventVolumeHandler struct {
+	eventVolumeService *services.EventVolumeService
+}
+
+// NewEventVolumeHandler creates a new handler for the daily event volume of projects
+func NewEventVolumeHandler(eventVolumeService *services.EventVolumeService) *EventVolumeHandler {
+	return &EventVolumeHandler{
+		eventVolumeService: eventVolumeService,
+	}
+}
+
+// RegisterRoutes registers event volume routes
+func (h *EventVolumeHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware.AuthMiddleware, projectMiddleware *middleware.ProjectMiddleware) {
+	r.Route("/projects/{id}/stats/volume", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(projectMiddleware.RequireProjectAccess)
+
+		r.Get("/", h.GetEventVolume)
+	})
+}
+
+// GetEventVolume returns the project's error events per day, sliced by the environment and
+// release query parameters (repeatable) and grouped by group_by=environment,release
+func (h *EventVolumeHandler) GetEventVolume(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	query := r.URL.Query()
+	filters := &dto.EventVolumeFilters{
+		Environments: query["environment"],
+		Releases:     query["release"],
+	}
+	if groupBy := query.Get("group_by"); groupBy != "" {
+		for _, group := range strings.Split(groupBy, ",") {
+			filters.GroupBy = append(filters.GroupBy, strings.TrimSpace(group))
+		}
+	}
+
+	for name, day := range map[string]*time.Time{"since": &filters.Since, "until": &filters.Until} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := parseEventVolumeDay(value)
+		if err != nil {
+			http.Error(w, "Invalid "+name+" parameter, expected a YYYY-MM-DD date or RFC3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		*day = parsed
+	}
+
+	volume, err := h.eventVolumeService.GetEventVolume(project.ID, filters)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidEventVolumeGroup),
+			errors.Is(err, services.ErrInvalidEventVolumeWindow),
+			errors.Is(err, services.ErrEventVolumeWindowTooLong):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Failed to get event volume", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(volume)
+}
+
+// parseEventVolumeDay parses a day given as a date or a timestamp
+func parseEventVolumeDay(value string) (time.Time, error) {
+	if day, err := time.Parse(time.DateOnly, value); err == nil {
+		return day, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
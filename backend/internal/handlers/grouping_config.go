@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/middleware"
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type GroupingConfigHandler struct {
+	groupingConfigService *services.GroupingConfigService
+	regroupService        *services.RegroupService
+	errorService          *services.ErrorService
+}
+
+// NewGroupingConfigHandler creates a new handler for project grouping configs
+func NewGroupingConfigHandler(groupingConfigService *services.GroupingConfigService, regroupService *services.RegroupService, errorService *services.ErrorService) *GroupingConfigHandler {
+	return &GroupingConfigHandler{
+		groupingConfigService: groupingConfigService,
+		regroupService:        regroupService,
+		errorService:          errorService,
+	}
+}
+
+// RegisterRoutes registers grouping config routes
+func (h *GroupingConfigHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware.AuthMiddleware, projectMiddleware *middleware.ProjectMiddleware) {
+	r.Route("/projects/{id}/grouping", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(projectMiddleware.RequireProjectAccess)
+
+		r.Get("/", h.GetGroupingConfig)
+		r.With(projectMiddleware.RequireProjectOwnerOrAdmin).Put("/", h.UpdateGroupingConfig)
+		r.Get("/regroup", h.GetRegroupJob)
+		r.With(projectMiddleware.RequireProjectOwnerOrAdmin).Post("/regroup", h.StartRegroup)
+	})
+
+	r.With(authMiddleware.RequireAuth, projectMiddleware.RequireProjectAccess).
+		Post("/projects/{id}/fingerprint-preview", h.PreviewFingerprint)
+}
+
+// GetGroupingConfig returns how the project groups events into issues
+func (h *GroupingConfigHandler) GetGroupingConfig(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := h.groupingConfigService.GetGroupingConfig(project.ID)
+	if err != nil {
+		http.Error(w, "Failed to get grouping config", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// UpdateGroupingConfig changes how the project groups events received from now on
+func (h *GroupingConfigHandler) UpdateGroupingConfig(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.GroupingConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.groupingConfigService.UpdateGroupingConfig(user.ID, project.ID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrGroupingConfigInvalid):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, services.ErrProjectNotFound):
+			http.Error(w, "Project not found", http.StatusNotFound)
+		default:
+			http.Error(w, "Failed to update grouping config", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// StartRegroup requests the regrouping of the project's recent events with its current
+// grouping config; the job runs in the background
+func (h *GroupingConfigHandler) StartRegroup(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	// The body is optional; without one the default period is regrouped
+	var req dto.RegroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.regroupService.StartRegroup(user.ID, project.ID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrRegroupInvalid):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, services.ErrRegroupInProgress):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, services.ErrProjectNotFound):
+			http.Error(w, "Project not found", http.StatusNotFound)
+		default:
+			http.Error(w, "Failed to start regrouping", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetRegroupJob returns the progress of the project's latest regroup job
+func (h *GroupingConfigHandler) GetRegroupJob(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := h.regroupService.GetLatestRegroupJob(project.ID)
+	if err != nil {
+		if errors.Is(err, services.ErrRegroupJobNotFound) {
+			http.Error(w, "Regroup job not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get regroup job", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// PreviewFingerprint explains how the project would group an event payload, without
+// storing it
+func (h *GroupingConfigHandler) PreviewFingerprint(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.ErrorEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.errorService.PreviewFingerprint(project.ID, &req)
+	if err != nil {
+		var validationErr *services.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, services.ErrEventDropped):
+			http.Error(w, "Event dropped by event processor", http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, "Failed to preview fingerprint", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
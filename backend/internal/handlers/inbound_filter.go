@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/middleware"
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type InboundFilterHandler struct {
+	inboundFilterService *services.InboundFilterService
+	errorService         *services.ErrorService
+}
+
+// NewInboundFilterHandler creates a new handler for project inbound filters
+func NewInboundFilterHandler(inboundFilterService *services.InboundFilterService, errorService *services.ErrorService) *InboundFilterHandler {
+	return &InboundFilterHandler{
+		inboundFilterService: inboundFilterService,
+		errorService:         errorService,
+	}
+}
+
+// RegisterRoutes registers inbound filter routes
+func (h *InboundFilterHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware.AuthMiddleware, projectMiddleware *middleware.ProjectMiddleware) {
+	r.Route("/projects/{id}/inbound-filters", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(projectMiddleware.RequireProjectAccess)
+
+		r.Get("/", h.GetInboundFilters)
+		r.With(projectMiddleware.RequireProjectOwnerOrAdmin).Put("/", h.UpdateInboundFilters)
+		r.Get("/blocklist", h.GetErrorBlocklist)
+		r.With(projectMiddleware.RequireProjectOwnerOrAdmin).Put("/blocklist", h.UpdateErrorBlocklist)
+	})
+
+	r.Route("/projects/{id}/filters/test", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(projectMiddleware.RequireProjectAccess)
+
+		r.Post("/", h.TestInboundFilters)
+	})
+}
+
+// GetInboundFilters returns the project's inbound filters and how many events each
+// discarded since ?since= (RFC3339, default 30 days ago)
+func (h *InboundFilterHandler) GetInboundFilters(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var since time.Time
+	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+		parsed, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			http.Error(w, "Invalid since parameter, expected RFC3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		since = parsed
+	}
+
+	response, err := h.inboundFilterService.GetInboundFilters(project.ID, since)
+	if err != nil {
+		http.Error(w, "Failed to get inbound filters", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// UpdateInboundFilters enables or disables the project's inbound filters; omitted filters
+// are left unchanged
+func (h *InboundFilterHandler) UpdateInboundFilters(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.InboundFiltersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.inboundFilterService.UpdateInboundFilters(user.ID, project.ID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInboundFiltersInvalid):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, services.ErrProjectNotFound):
+			http.Error(w, "Project not found", http.StatusNotFound)
+		default:
+			http.Error(w, "Failed to update inbound filters", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetErrorBlocklist returns the message and exception type patterns discarding the project's events
+func (h *InboundFilterHandler) GetErrorBlocklist(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := h.inboundFilterService.GetErrorBlocklist(project.ID)
+	if err != nil {
+		http.Error(w, "Failed to get error blocklist", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// UpdateErrorBlocklist replaces the message or exception type patterns discarding the
+// project's events; omitted lists are left unchanged
+func (h *InboundFilterHandler) UpdateErrorBlocklist(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.ErrorBlocklistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.inboundFilterService.UpdateErrorBlocklist(user.ID, project.ID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInboundFiltersInvalid):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, services.ErrProjectNotFound):
+			http.Error(w, "Project not found", http.StatusNotFound)
+		default:
+			http.Error(w, "Failed to update error blocklist", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// TestInboundFilters reports whether a sample event would be discarded by the project's
+// inbound filters, or by the filters in the request, without storing or counting it
+func (h *InboundFilterHandler) TestInboundFilters(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.InboundFilterTestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := h.errorService.ValidateErrorPayload(&req.Event); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	normalized, err := h.errorService.NormalizeErrorData(project.ID, &req.Event, req.ClientIP, req.UserAgent)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.inboundFilterService.TestFilters(project.ID, normalized, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInboundFiltersInvalid):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, services.ErrProjectNotFound):
+			http.Error(w, "Project not found", http.StatusNotFound)
+		default:
+			http.Error(w, "Failed to test inbound filters", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/middleware"
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type IncidentHandler struct {
+	incidentService *services.IncidentService
+}
+
+// NewIncidentHandler creates a new incident handler
+func NewIncidentHandler(incidentService *services.IncidentService) *IncidentHandler {
+	return &IncidentHandler{
+		incidentService: incidentService,
+	}
+}
+
+// RegisterRoutes registers incident routes
+func (h *IncidentHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware.AuthMiddleware, orgMiddleware *middleware.OrganizationMiddleware) {
+	// Organization incident routes
+	r.Route("/organizations/{org_id}/incidents", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(orgMiddleware.RequireOrganizationAccess)
+
+		r.Get("/", h.ListIncidents)
+		r.Post("/", h.CreateIncident)
+	})
+
+	// Individual incident routes; the service checks organization membership
+	r.Route("/incidents/{incident_id}", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+
+		r.Get("/", h.GetIncident)
+		r.Put("/", h.UpdateIncident)
+		r.Post("/issues", h.AddIncidentIssues)
+		r.Delete("/issues/{issue_id}", h.RemoveIncidentIssue)
+		r.Get("/timeline", h.GetIncidentTimeline)
+		r.Post("/timeline", h.AddIncidentNote)
+		r.Get("/event-rate", h.GetIncidentEventRate)
+	})
+}
+
+// ListIncidents lists the organization's incidents, most recent first
+func (h *IncidentHandler) ListIncidents(w http.ResponseWriter, r *http.Request) {
+	org, ok := middleware.GetOrganizationFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Organization not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	query := r.URL.Query()
+	filters := dto.IncidentFilters{Status: query.Get("status")}
+	if filters.Status != "" && !services.IsValidIncidentStatus(filters.Status) {
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+	if page, err := strconv.Atoi(query.Get("page")); err == nil {
+		filters.Page = page
+	}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil {
+		filters.Limit = limit
+	}
+
+	response, err := h.incidentService.ListIncidents(org.ID, filters)
+	if err != nil {
+		http.Error(w, "Failed to list incidents", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateIncident opens an incident in the organization
+func (h *IncidentHandler) CreateIncident(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	org, ok := middleware.GetOrganizationFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Organization not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.CreateIncidentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if len(req.Title) > 255 {
+		http.Error(w, "Title is too long (max 255 characters)", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.incidentService.CreateIncident(user.ID, org.ID, req)
+	if err != nil {
+		h.writeIncidentError(w, err, "Failed to create incident")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetIncident returns an incident with its issues
+func (h *IncidentHandler) GetIncident(w http.ResponseWriter, r *http.Request) {
+	user, incidentID, ok := h.parseIncidentRequest(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.incidentService.GetIncident(user, incidentID)
+	if err != nil {
+		h.writeIncidentError(w, err, "Failed to get incident")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// UpdateIncident changes an incident's title, description or status
+func (h *IncidentHandler) UpdateIncident(w http.ResponseWriter, r *http.Request) {
+	user, incidentID, ok := h.parseIncidentRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateIncidentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Status != nil && !services.IsValidIncidentStatus(*req.Status) {
+		http.Error(w, "Invalid status, expected open, investigating, mitigated or resolved", http.StatusBadRequest)
+		return
+	}
+	if req.Title != nil && len(*req.Title) > 255 {
+		http.Error(w, "Title is too long (max 255 characters)", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.incidentService.UpdateIncident(user, incidentID, req)
+	if err != nil {
+		h.writeIncidentError(w, err, "Failed to update incident")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// AddIncidentIssues groups issues into the incident
+func (h *IncidentHandler) AddIncidentIssues(w http.ResponseWriter, r *http.Request) {
+	user, incidentID, ok := h.parseIncidentRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.IncidentIssuesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.incidentService.AddIncidentIssues(user, incidentID, req.IssueIDs)
+	if err != nil {
+		h.writeIncidentError(w, err, "Failed to add issues to incident")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// RemoveIncidentIssue ungroups an issue from the incident
+func (h *IncidentHandler) RemoveIncidentIssue(w http.ResponseWriter, r *http.Request) {
+	user, incidentID, ok := h.parseIncidentRequest(w, r)
+	if !ok {
+		return
+	}
+
+	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
+	if err != nil {
+		http.Error(w, "Invalid issue ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.incidentService.RemoveIncidentIssue(user, incidentID, issueID); err != nil {
+		h.writeIncidentError(w, err, "Failed to remove issue from incident")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetIncidentTimeline returns the incident timeline
+func (h *IncidentHandler) GetIncidentTimeline(w http.ResponseWriter, r *http.Request) {
+	user, incidentID, ok := h.parseIncidentRequest(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.incidentService.GetIncidentTimeline(user, incidentID)
+	if err != nil {
+		h.writeIncidentError(w, err, "Failed to get incident timeline")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// AddIncidentNote posts a note to the incident timeline
+func (h *IncidentHandler) AddIncidentNote(w http.ResponseWriter, r *http.Request) {
+	user, incidentID, ok := h.parseIncidentRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.IncidentNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.incidentService.AddIncidentNote(user, incidentID, req.Content)
+	if err != nil {
+		h.writeIncidentError(w, err, "Failed to add note")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetIncidentEventRate returns the combined event rate chart of the incident's issues
+func (h *IncidentHandler) GetIncidentEventRate(w http.ResponseWriter, r *http.Request) {
+	user, incidentID, ok := h.parseIncidentRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var since, until *time.Time
+	for param, target := range map[string]**time.Time{"since": &since, "until": &until} {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "Invalid "+param+" parameter, expected RFC3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		*target = &parsed
+	}
+
+	response, err := h.incidentService.GetIncidentEventRate(user, incidentID, since, until, r.URL.Query().Get("interval"))
+	if err != nil {
+		h.writeIncidentError(w, err, "Failed to get incident event rate")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// parseIncidentRequest reads the authenticated user and the incident ID of the route
+func (h *IncidentHandler) parseIncidentRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	incidentID, err := uuid.Parse(chi.URLParam(r, "incident_id"))
+	if err != nil {
+		http.Error(w, "Invalid incident ID format", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return user.ID, incidentID, true
+}
+
+// writeIncidentError maps incident service errors to HTTP responses
+func (h *IncidentHandler) writeIncidentError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrIncidentNotFound):
+		http.Error(w, "Incident not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrIncidentAccessDenied):
+		http.Error(w, "Access denied to incident", http.StatusForbidden)
+	case errors.Is(err, services.ErrIncidentIssueNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, services.ErrIncidentInvalidStatus), errors.Is(err, services.ErrIncidentInvalidRequest):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
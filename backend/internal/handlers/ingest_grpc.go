@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/ingestpb"
+	"minisentry/internal/middleware"
+	"minisentry/internal/models"
+	"minisentry/internal/services"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// IngestGRPCServer serves the EventIngest gRPC service for internal producers sending error
+// events at high rates. Events share the pipeline, rate limits, quotas and ingest queue of
+// the HTTP endpoints, and each one counts against the project's limits.
+type IngestGRPCServer struct {
+	ingestpb.UnimplementedEventIngestServer
+
+	errorHandler      *ErrorHandler
+	projectMiddleware *middleware.ProjectMiddleware
+}
+
+// NewIngestGRPCServer creates the gRPC ingestion service, authenticating calls by DSN
+func NewIngestGRPCServer(errorHandler *ErrorHandler, projectMiddleware *middleware.ProjectMiddleware) *IngestGRPCServer {
+	return &IngestGRPCServer{
+		errorHandler:      errorHandler,
+		projectMiddleware: projectMiddleware,
+	}
+}
+
+// SendEvent ingests one error event
+func (s *IngestGRPCServer) SendEvent(ctx context.Context, request *ingestpb.SendEventRequest) (*ingestpb.SendEventResponse, error) {
+	project, source, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.ingest(source, project, request), nil
+}
+
+// StreamEvents ingests a stream of error events, authenticated once for the stream
+func (s *IngestGRPCServer) StreamEvents(stream ingestpb.EventIngest_StreamEventsServer) error {
+	project, source, err := s.authenticate(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	for {
+		request, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(s.ingest(source, project, request)); err != nil {
+			return err
+		}
+	}
+}
+
+// authenticate returns the project of the DSN sent in the call's authorization metadata, as
+// is or after "Bearer ", and where the call comes from
+func (s *IngestGRPCServer) authenticate(ctx context.Context) (*middleware.ProjectContext, eventSource, error) {
+	source := eventSource{ctx: ctx}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		source.clientIP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(source.clientIP); err == nil {
+			source.clientIP = host
+		}
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if userAgent := md.Get("user-agent"); len(userAgent) > 0 {
+		source.userAgent = userAgent[0]
+	}
+	var dsn string
+	if authorization := md.Get("authorization"); len(authorization) > 0 {
+		dsn = strings.TrimSpace(strings.TrimPrefix(authorization[0], "Bearer "))
+	}
+	if dsn == "" {
+		return nil, source, status.Error(codes.Unauthenticated, "DSN authentication required")
+	}
+
+	project, err := s.projectMiddleware.AuthenticateDSN(dsn, source.clientIP)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProjectNotFound):
+			return nil, source, status.Error(codes.Unauthenticated, "invalid DSN")
+		case errors.Is(err, middleware.ErrSecretKeyInvalid):
+			return nil, source, status.Error(codes.Unauthenticated, err.Error())
+		case errors.Is(err, services.ErrProjectInactive):
+			return nil, source, status.Error(codes.PermissionDenied, "project is inactive")
+		case errors.Is(err, middleware.ErrIPNotAllowed):
+			return nil, source, status.Error(codes.PermissionDenied, err.Error())
+		default:
+			return nil, source, status.Error(codes.Internal, "failed to authenticate DSN")
+		}
+	}
+	return project, source, nil
+}
+
+// ingest processes one event of a call, following maintenance windows like the store endpoint
+func (s *IngestGRPCServer) ingest(source eventSource, project *middleware.ProjectContext, request *ingestpb.SendEventRequest) *ingestpb.SendEventResponse {
+	receivedAt := time.Now()
+	eh := s.errorHandler
+
+	if request.Event == nil {
+		return &ingestpb.SendEventResponse{Status: services.EventStatusInvalid, Error: "event is required"}
+	}
+	if maxSize := eh.errorService.MaxEventSize(project.MaxEventSize); maxSize > 0 && proto.Size(request.Event) > maxSize {
+		return &ingestpb.SendEventResponse{
+			EventId: request.Event.GetEventId(),
+			Status:  services.EventStatusInvalid,
+			Error:   fmt.Sprintf("event exceeds maximum size of %d bytes", maxSize),
+		}
+	}
+
+	if eh.maintenance != nil {
+		switch window := eh.maintenance.ActiveWindow(&project.OrganizationID); {
+		case window == nil || window.Ingestion == models.MaintenanceIngestionAccept:
+		case window.Ingestion == models.MaintenanceIngestionBuffer && eh.ingestQueue != nil:
+			source.buffering = window
+		default:
+			return &ingestpb.SendEventResponse{EventId: request.Event.GetEventId(), Status: ingestStatusFailed, Error: window.Message}
+		}
+	}
+
+	eventData := eventFromProto(request.Event)
+	var sentAt *time.Time
+	if request.SentAt != nil {
+		sent := request.SentAt.AsTime()
+		sentAt = &sent
+	}
+	eh.errorService.CorrectClockSkew(eventData, sentAt, receivedAt)
+
+	result := eh.ingestEvent(source, project.ID, eventData, nil, true)
+	response := &ingestpb.SendEventResponse{
+		EventId: result.EventID,
+		Status:  result.Status,
+		Error:   result.Error,
+	}
+	for _, fieldError := range result.Errors {
+		response.Errors = append(response.Errors, &ingestpb.FieldError{Path: fieldError.Path, Message: fieldError.Message})
+	}
+	return response
+}
+
+// eventFromProto converts a gRPC error event to the payload of the store endpoint
+func eventFromProto(event *ingestpb.ErrorEvent) *dto.ErrorEventRequest {
+	eventData := &dto.ErrorEventRequest{
+		EventID:     event.EventId,
+		Level:       event.Level,
+		Logger:      event.Logger,
+		Platform:    event.Platform,
+		Release:     event.Release,
+		Environment: event.Environment,
+		ServerName:  event.ServerName,
+		Transaction: event.Transaction,
+		Tags:        event.Tags,
+		Extra:       structMap(event.Extra),
+		Contexts:    structMap(event.Contexts),
+		Fingerprint: event.Fingerprint,
+		Modules:     event.Modules,
+	}
+	if event.Timestamp != nil {
+		timestamp := event.Timestamp.AsTime()
+		eventData.Timestamp = &timestamp
+	}
+
+	if message := event.Message; message != nil {
+		eventData.Message = &dto.MessageData{Message: message.Message, Formatted: message.Formatted}
+		for _, param := range message.Params {
+			eventData.Message.Params = append(eventData.Message.Params, param.AsInterface())
+		}
+	}
+
+	if len(event.Exceptions) > 0 {
+		eventData.Exception = &dto.ExceptionData{Values: make([]dto.ExceptionValue, len(event.Exceptions))}
+		for i, exception := range event.Exceptions {
+			value := dto.ExceptionValue{Type: exception.Type, Value: exception.Value, Module: exception.Module}
+			if mechanism := exception.Mechanism; mechanism != nil {
+				value.Mechanism = &dto.MechanismData{
+					Type:        mechanism.Type,
+					Description: mechanism.Description,
+					HelpLink:    mechanism.HelpLink,
+					Handled:     mechanism.Handled,
+					Data:        structMap(mechanism.Data),
+				}
+			}
+			if len(exception.Frames) > 0 {
+				value.Stacktrace = &dto.StacktraceData{Frames: make([]dto.StackFrame, len(exception.Frames))}
+				for j, frame := range exception.Frames {
+					value.Stacktrace.Frames[j] = frameFromProto(frame)
+				}
+			}
+			eventData.Exception.Values[i] = value
+		}
+	}
+
+	if user := event.User; user != nil {
+		eventData.User = &dto.UserContext{
+			ID:        user.Id,
+			Email:     user.Email,
+			Username:  user.Username,
+			IPAddress: user.IpAddress,
+			Name:      user.Name,
+			Data:      structMap(user.Data),
+		}
+	}
+
+	if request := event.Request; request != nil {
+		eventData.Request = &dto.RequestData{
+			URL:         request.Url,
+			Method:      request.Method,
+			QueryString: request.QueryString,
+			Headers:     request.Headers,
+			Env:         request.Env,
+			Cookies:     request.Cookies,
+		}
+		if request.Data != nil {
+			eventData.Request.Data = request.Data.AsInterface()
+		}
+	}
+
+	for _, breadcrumb := range event.Breadcrumbs {
+		data := dto.BreadcrumbData{
+			Type:     breadcrumb.Type,
+			Category: breadcrumb.Category,
+			Message:  breadcrumb.Message,
+			Data:     structMap(breadcrumb.Data),
+			Level:    breadcrumb.Level,
+		}
+		if breadcrumb.Timestamp != nil {
+			timestamp := breadcrumb.Timestamp.AsTime()
+			data.Timestamp = &timestamp
+		}
+		eventData.Breadcrumbs = append(eventData.Breadcrumbs, data)
+	}
+
+	if sdk := event.Sdk; sdk != nil {
+		eventData.SDK = &dto.SDKInfo{Name: sdk.Name, Version: sdk.Version}
+	}
+	return eventData
+}
+
+func frameFromProto(frame *ingestpb.Frame) dto.StackFrame {
+	stackFrame := dto.StackFrame{
+		Filename:        frame.Filename,
+		Function:        frame.Function,
+		Module:          frame.Module,
+		AbsPath:         frame.AbsPath,
+		ContextLine:     frame.ContextLine,
+		PreContext:      frame.PreContext,
+		PostContext:     frame.PostContext,
+		InApp:           frame.InApp,
+		Vars:            structMap(frame.Vars),
+		Package:         frame.Package,
+		Platform:        frame.Platform,
+		InstructionAddr: frame.InstructionAddr,
+		Symbol:          frame.Symbol,
+		SymbolAddr:      frame.SymbolAddr,
+		ImageAddr:       frame.ImageAddr,
+	}
+	if frame.Lineno != nil {
+		lineno := int(*frame.Lineno)
+		stackFrame.Lineno = &lineno
+	}
+	if frame.Colno != nil {
+		colno := int(*frame.Colno)
+		stackFrame.Colno = &colno
+	}
+	return stackFrame
+}
+
+// structMap returns the fields of a protobuf struct, nil when it is not set
+func structMap(s *structpb.Struct) map[string]interface{} {
+	if s == nil {
+		return nil
+	}
+	return s.AsMap()
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/middleware"
+	"minisentry/internal/queue"
+	"minisentry/internal/services"
+
+	"github.com/google/uuid"
+)
+
+// UseIngestQueue makes the store endpoint queue error events for asynchronous processing
+// instead of processing them within the request
+func (eh *ErrorHandler) UseIngestQueue(ingestQueue queue.EventQueue) {
+	eh.ingestQueue = ingestQueue
+}
+
+// UseOverloadController makes the store endpoint shed events when the ingest queue is full
+func (eh *ErrorHandler) UseOverloadController(overload *middleware.OverloadController) {
+	eh.overload = overload
+}
+
+// enqueueErrorEvent validates an error event and queues it, answering 202 with the event ID.
+// It returns false when the event could not be queued and should be processed synchronously,
+// unless the queue is full and the overload controller sheds it.
+func (eh *ErrorHandler) enqueueErrorEvent(w http.ResponseWriter, r *http.Request, projectID uuid.UUID, eventData *dto.ErrorEventRequest, payload []byte) bool {
+	// Invalid payloads are still rejected up front, since SDKs do not see worker failures
+	if err := eh.errorService.ValidateErrorPayload(eventData); err != nil {
+		logRejectedPayload(r.Context(), err, payload)
+		eh.writeProcessingError(w, err)
+		return true
+	}
+
+	if err := eh.queueErrorEvent(projectID, eventData, middleware.ClientIP(r), r.Header.Get("User-Agent")); err != nil {
+		if eh.overload != nil && errors.Is(err, queue.ErrQueueFull) {
+			eh.overload.Shed(w, middleware.OverloadQueue)
+			return true
+		}
+		middleware.Logf(r.Context(), "Failed to queue event %s, processing synchronously: %v", *eventData.EventID, err)
+		return false
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"id": *eventData.EventID})
+	return true
+}
+
+// queueErrorEvent assigns a validated error event its ID, so it can be returned before the
+// event is stored, and queues it. Events the full queue cannot take are recorded as rate limited.
+func (eh *ErrorHandler) queueErrorEvent(projectID uuid.UUID, eventData *dto.ErrorEventRequest, clientIP, userAgent string) error {
+	if eventData.EventID == nil || *eventData.EventID == "" {
+		eventID := strings.ReplaceAll(uuid.New().String(), "-", "")
+		eventData.EventID = &eventID
+	}
+
+	// Recorded before the event is queued so a worker's outcome cannot be overwritten
+	eh.errorService.RecordEventStatus(projectID, *eventData.EventID, services.EventStatusQueued, "")
+
+	event, err := json.Marshal(eventData)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	job := &queue.IngestJob{
+		ProjectID: projectID,
+		Event:     event,
+		ClientIP:  clientIP,
+		UserAgent: userAgent,
+	}
+	if err := eh.ingestQueue.Enqueue(job); err != nil {
+		if errors.Is(err, queue.ErrQueueFull) {
+			eh.errorService.RecordEventStatus(projectID, *eventData.EventID, services.EventStatusRateLimited, err.Error())
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/middleware"
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type IngestTokenHandler struct {
+	tokenService *services.IngestTokenService
+}
+
+// NewIngestTokenHandler creates a new handler for organization ingest tokens
+func NewIngestTokenHandler(tokenService *services.IngestTokenService) *IngestTokenHandler {
+	return &IngestTokenHandler{
+		tokenService: tokenService,
+	}
+}
+
+// RegisterRoutes registers ingest token management routes, restricted to organization admins
+func (h *IngestTokenHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware.AuthMiddleware, orgMiddleware *middleware.OrganizationMiddleware) {
+	r.Route("/organizations/{id}/ingest-tokens", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(orgMiddleware.RequireOrganizationAccess)
+		r.Use(orgMiddleware.RequireOwnerOrAdmin)
+
+		r.Get("/", h.ListTokens)
+		r.Post("/", h.CreateToken)
+		r.Delete("/{token_id}", h.RevokeToken)
+	})
+}
+
+// ListTokens lists the organization's ingest tokens without their secrets
+func (h *IngestTokenHandler) ListTokens(w http.ResponseWriter, r *http.Request) {
+	orgCtx, ok := middleware.GetOrganizationFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Organization not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := h.tokenService.ListTokens(orgCtx.ID)
+	if err != nil {
+		http.Error(w, "Failed to list ingest tokens", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateToken creates an ingest token; the response is the only time the token is shown
+func (h *IngestTokenHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	orgCtx, ok := middleware.GetOrganizationFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Organization not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.IngestTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		http.Error(w, "Name is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.Name) > 255 {
+		http.Error(w, "Name is too long (max 255 characters)", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.tokenService.CreateToken(orgCtx.ID, user.ID, req)
+	if err != nil {
+		http.Error(w, "Failed to create ingest token", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// RevokeToken revokes an ingest token so it can no longer authenticate
+func (h *IngestTokenHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	orgCtx, ok := middleware.GetOrganizationFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Organization not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	tokenID, err := uuid.Parse(chi.URLParam(r, "token_id"))
+	if err != nil {
+		http.Error(w, "Invalid token ID", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.tokenService.RevokeToken(orgCtx.ID, tokenID)
+	if err != nil {
+		if errors.Is(err, services.ErrIngestTokenNotFound) {
+			http.Error(w, "Ingest token not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to revoke ingest token", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -2,12 +2,20 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"minisentry/internal/dto"
 	"minisentry/internal/middleware"
+	"minisentry/internal/models"
 	"minisentry/internal/services"
 
 	"github.com/go-chi/chi/v5"
@@ -15,12 +23,18 @@ import (
 )
 
 type IssueHandler struct {
-	issueService *services.IssueService
+	issueService        *services.IssueService
+	projectService      *services.ProjectService
+	attachmentService   *services.AttachmentService
+	subscriptionService *services.IssueSubscriptionService
 }
 
-func NewIssueHandler(issueService *services.IssueService) *IssueHandler {
+func NewIssueHandler(issueService *services.IssueService, projectService *services.ProjectService, attachmentService *services.AttachmentService, subscriptionService *services.IssueSubscriptionService) *IssueHandler {
 	return &IssueHandler{
-		issueService: issueService,
+		issueService:        issueService,
+		projectService:      projectService,
+		attachmentService:   attachmentService,
+		subscriptionService: subscriptionService,
 	}
 }
 
@@ -30,7 +44,7 @@ func (h *IssueHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware.A
 		r.Use(authMiddleware.RequireAuth)
 		
 		// Project-scoped issue routes
-		r.Route("/projects/{project_id}/issues", func(r chi.Router) {
+		r.Route("/projects/{id}/issues", func(r chi.Router) {
 			r.Use(projectMiddleware.RequireProjectAccess)
 			r.Get("/", h.ListProjectIssues)    // GET /api/v1/projects/{id}/issues
 			r.Get("/stats", h.GetIssueStats)   // GET /api/v1/projects/{id}/issues/stats
@@ -41,14 +55,35 @@ func (h *IssueHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware.A
 			r.Use(h.issueAccessMiddleware)
 			r.Get("/", h.GetIssue)                    // GET /api/v1/issues/{id}
 			r.Put("/", h.UpdateIssue)                 // PUT /api/v1/issues/{id}
+			r.Delete("/", h.DeleteIssue)              // DELETE /api/v1/issues/{id}
 			r.Post("/comments", h.AddIssueComment)    // POST /api/v1/issues/{id}/comments
 			r.Get("/comments", h.GetIssueComments)    // GET /api/v1/issues/{id}/comments
+			r.Put("/comments/{comment_id}", h.UpdateIssueComment)                         // PUT /api/v1/issues/{id}/comments/{comment_id}
+			r.Delete("/comments/{comment_id}", h.DeleteIssueComment)                      // DELETE /api/v1/issues/{id}/comments/{comment_id}
+			r.Post("/comments/{comment_id}/reactions", h.AddCommentReaction)             // POST /api/v1/issues/{id}/comments/{comment_id}/reactions
+			r.Delete("/comments/{comment_id}/reactions/{emoji}", h.RemoveCommentReaction) // DELETE /api/v1/issues/{id}/comments/{comment_id}/reactions/{emoji}
+			r.Get("/participants", h.GetIssueParticipants)  // GET /api/v1/issues/{id}/participants
+			r.Post("/subscription", h.SubscribeToIssue)      // POST /api/v1/issues/{id}/subscription
+			r.Delete("/subscription", h.UnsubscribeFromIssue) // DELETE /api/v1/issues/{id}/subscription
+			r.Get("/relations", h.GetIssueRelations)                        // GET /api/v1/issues/{id}/relations
+			r.Post("/relations", h.AddIssueRelation)                        // POST /api/v1/issues/{id}/relations
+			r.Delete("/relations/{relation_id}", h.RemoveIssueRelation)     // DELETE /api/v1/issues/{id}/relations/{relation_id}
+			r.Post("/unmerge", h.UnmergeIssue)        // POST /api/v1/issues/{id}/unmerge
+			r.Get("/similar", h.GetSimilarIssues)     // GET /api/v1/issues/{id}/similar
+			r.Get("/tags", h.GetIssueTags)            // GET /api/v1/issues/{id}/tags
+			r.Get("/tags/{key}/values", h.GetIssueTagValues) // GET /api/v1/issues/{id}/tags/{key}/values
+			r.Get("/fingerprint-rule", h.GetIssueFingerprintRule)       // GET /api/v1/issues/{id}/fingerprint-rule
+			r.Put("/fingerprint-rule", h.SetIssueFingerprintRule)       // PUT /api/v1/issues/{id}/fingerprint-rule
+			r.Delete("/fingerprint-rule", h.DeleteIssueFingerprintRule) // DELETE /api/v1/issues/{id}/fingerprint-rule
 			r.Get("/activity", h.GetIssueActivity)    // GET /api/v1/issues/{id}/activity
 			r.Get("/events", h.GetIssueEvents)        // GET /api/v1/issues/{id}/events
+			r.Get("/events/{event_id}/attachments", h.ListEventAttachments)                   // GET /api/v1/issues/{id}/events/{event_id}/attachments
+			r.Get("/events/{event_id}/attachments/{attachment_id}", h.DownloadAttachment)     // GET /api/v1/issues/{id}/events/{event_id}/attachments/{attachment_id}
 		})
 		
 		// Bulk operations
 		r.Post("/issues/bulk-update", h.BulkUpdateIssues) // POST /api/v1/issues/bulk-update
+		r.Post("/issues/merge", h.MergeIssues)             // POST /api/v1/issues/merge
 	})
 }
 
@@ -61,7 +96,11 @@ func (h *IssueHandler) ListProjectIssues(w http.ResponseWriter, r *http.Request)
 	}
 	
 	// Parse query parameters
-	filters := h.parseIssueFilters(r)
+	filters, err := h.parseIssueFilters(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	
 	// Get issues
 	response, err := h.issueService.GetProjectIssues(project.ID, filters)
@@ -70,34 +109,717 @@ func (h *IssueHandler) ListProjectIssues(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
-}
-
-// GetIssue handles GET /api/v1/issues/{id}
-func (h *IssueHandler) GetIssue(w http.ResponseWriter, r *http.Request) {
-	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetIssue handles GET /api/v1/issues/{id}
+func (h *IssueHandler) GetIssue(w http.ResponseWriter, r *http.Request) {
+	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
+		return
+	}
+	
+	issue, err := h.issueService.GetIssue(issueID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Issue not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to retrieve issue: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(issue)
+}
+
+// UpdateIssue handles PUT /api/v1/issues/{id}
+func (h *IssueHandler) UpdateIssue(w http.ResponseWriter, r *http.Request) {
+	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
+		return
+	}
+	
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	
+	var request dto.IssueUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+	
+	// Validate status if provided
+	if request.Status != nil {
+		if !h.isValidStatus(*request.Status) {
+			http.Error(w, "Invalid status value", http.StatusBadRequest)
+			return
+		}
+	}
+	if request.ResolvedInRelease != nil && (request.Status == nil || *request.Status != "resolved") {
+		http.Error(w, "resolved_in_release requires the resolved status", http.StatusBadRequest)
+		return
+	}
+	ignoreCondition := request.IgnoreUntil != nil || request.IgnoreCount != nil || request.IgnoreUserCount != nil
+	if ignoreCondition && (request.Status == nil || *request.Status != "ignored") {
+		http.Error(w, "ignore_until, ignore_count and ignore_user_count require the ignored status", http.StatusBadRequest)
+		return
+	}
+	
+	// Update issue
+	updatedIssue, err := h.issueService.UpdateIssueStatus(issueID, user.ID, request)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Issue not found", http.StatusNotFound)
+			return
+		}
+		if strings.Contains(err.Error(), "invalid status transition") || errors.Is(err, services.ErrNoReleaseToResolveIn) ||
+			errors.Is(err, services.ErrIgnoreConditionInvalid) || errors.Is(err, services.ErrInvalidIssuePriority) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to update issue: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updatedIssue)
+}
+
+// AddIssueComment handles POST /api/v1/issues/{id}/comments
+func (h *IssueHandler) AddIssueComment(w http.ResponseWriter, r *http.Request) {
+	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
+		return
+	}
+	
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	
+	var request dto.IssueCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+	
+	// Validate content
+	if strings.TrimSpace(request.Content) == "" {
+		http.Error(w, "Comment content cannot be empty", http.StatusBadRequest)
+		return
+	}
+	
+	// Add comment
+	comment, err := h.issueService.AddIssueComment(issueID, user.ID, request)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Issue not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to add comment: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(comment)
+}
+
+// GetIssueComments handles GET /api/v1/issues/{id}/comments
+func (h *IssueHandler) GetIssueComments(w http.ResponseWriter, r *http.Request) {
+	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
+		return
+	}
+	
+	// Parse pagination
+	page, limit := h.parsePagination(r)
+	
+	// Get comments
+	response, err := h.issueService.GetIssueComments(issueID, page, limit)
+	if err != nil {
+		http.Error(w, "Failed to retrieve comments: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// UpdateIssueComment handles PUT /api/v1/issues/{id}/comments/{comment_id}; authors may edit
+// their comments, and organization owners and admins any comment
+func (h *IssueHandler) UpdateIssueComment(w http.ResponseWriter, r *http.Request) {
+	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
+		return
+	}
+	
+	commentID, err := uuid.Parse(chi.URLParam(r, "comment_id"))
+	if err != nil {
+		http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+		return
+	}
+	
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	
+	var request dto.IssueCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+	
+	if strings.TrimSpace(request.Content) == "" {
+		http.Error(w, "Comment content cannot be empty", http.StatusBadRequest)
+		return
+	}
+	
+	moderator, err := h.isIssueModerator(issueID, user.ID)
+	if err != nil {
+		http.Error(w, "Failed to check project access", http.StatusInternalServerError)
+		return
+	}
+	
+	comment, err := h.issueService.UpdateIssueComment(issueID, commentID, user.ID, moderator, request)
+	if err != nil {
+		h.writeCommentError(w, err, "Failed to update comment")
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comment)
+}
+
+// DeleteIssueComment handles DELETE /api/v1/issues/{id}/comments/{comment_id}; authors may
+// delete their comments, and organization owners and admins any comment
+func (h *IssueHandler) DeleteIssueComment(w http.ResponseWriter, r *http.Request) {
+	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
+		return
+	}
+	
+	commentID, err := uuid.Parse(chi.URLParam(r, "comment_id"))
+	if err != nil {
+		http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+		return
+	}
+	
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	
+	moderator, err := h.isIssueModerator(issueID, user.ID)
+	if err != nil {
+		http.Error(w, "Failed to check project access", http.StatusInternalServerError)
+		return
+	}
+	
+	if err := h.issueService.DeleteIssueComment(issueID, commentID, user.ID, moderator); err != nil {
+		h.writeCommentError(w, err, "Failed to delete comment")
+		return
+	}
+	
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeCommentError maps errors of changing a comment to responses
+func (h *IssueHandler) writeCommentError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrCommentNotFound):
+		http.Error(w, "Comment not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrCommentAccessDenied):
+		http.Error(w, "Only the author and organization owners and admins can change a comment", http.StatusForbidden)
+	default:
+		http.Error(w, message+": "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// AddCommentReaction handles POST /api/v1/issues/{id}/comments/{comment_id}/reactions
+func (h *IssueHandler) AddCommentReaction(w http.ResponseWriter, r *http.Request) {
+	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
+		return
+	}
+	
+	commentID, err := uuid.Parse(chi.URLParam(r, "comment_id"))
+	if err != nil {
+		http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+		return
+	}
+	
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	
+	var request dto.IssueCommentReactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+	
+	emoji := strings.TrimSpace(request.Emoji)
+	if !h.isValidEmoji(emoji) {
+		http.Error(w, "Invalid emoji", http.StatusBadRequest)
+		return
+	}
+	
+	reactions, err := h.issueService.AddCommentReaction(issueID, commentID, user.ID, emoji)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Comment not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to add reaction: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(reactions)
+}
+
+// RemoveCommentReaction handles DELETE /api/v1/issues/{id}/comments/{comment_id}/reactions/{emoji}
+func (h *IssueHandler) RemoveCommentReaction(w http.ResponseWriter, r *http.Request) {
+	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
+		return
+	}
+	
+	commentID, err := uuid.Parse(chi.URLParam(r, "comment_id"))
+	if err != nil {
+		http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+		return
+	}
+	
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	
+	// Emoji are URL-encoded in the path
+	emoji, err := url.PathUnescape(chi.URLParam(r, "emoji"))
+	if err != nil || !h.isValidEmoji(emoji) {
+		http.Error(w, "Invalid emoji", http.StatusBadRequest)
+		return
+	}
+	
+	reactions, err := h.issueService.RemoveCommentReaction(issueID, commentID, user.ID, emoji)
+	if err != nil {
+		if strings.Contains(err.Error(), "reaction not found") {
+			http.Error(w, "Reaction not found", http.StatusNotFound)
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Comment not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to remove reaction: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reactions)
+}
+
+// GetIssueRelations handles GET /api/v1/issues/{id}/relations
+func (h *IssueHandler) GetIssueRelations(w http.ResponseWriter, r *http.Request) {
+	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
+		return
+	}
+	
+	// depth > 1 follows the relations of related issues
+	depth := 1
+	if depthStr := r.URL.Query().Get("depth"); depthStr != "" {
+		depth, err = strconv.Atoi(depthStr)
+		if err != nil || depth < 1 {
+			http.Error(w, "Invalid depth", http.StatusBadRequest)
+			return
+		}
+	}
+	
+	relationType := r.URL.Query().Get("type")
+	if relationType != "" && !services.IsValidIssueRelationType(relationType) {
+		http.Error(w, "Invalid relation type", http.StatusBadRequest)
+		return
+	}
+	
+	relations, err := h.issueService.GetIssueRelations(issueID, depth, relationType)
+	if err != nil {
+		http.Error(w, "Failed to retrieve relations: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.IssueRelationsResponse{Relations: relations})
+}
+
+// AddIssueRelation handles POST /api/v1/issues/{id}/relations
+func (h *IssueHandler) AddIssueRelation(w http.ResponseWriter, r *http.Request) {
+	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
+		return
+	}
+	
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	
+	var request dto.IssueRelationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+	
+	if request.TargetIssueID == uuid.Nil {
+		http.Error(w, "target_issue_id is required", http.StatusBadRequest)
+		return
+	}
+	if !services.IsValidIssueRelationType(request.RelationType) {
+		http.Error(w, "Invalid relation type, expected duplicate_of, blocked_by or related", http.StatusBadRequest)
+		return
+	}
+	
+	relation, err := h.issueService.AddIssueRelation(issueID, user.ID, request)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			http.Error(w, "Target issue not found", http.StatusNotFound)
+		case strings.Contains(err.Error(), "already exists"):
+			http.Error(w, "Relation already exists", http.StatusConflict)
+		case strings.Contains(err.Error(), "itself"), strings.Contains(err.Error(), "same organization"):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Failed to add relation: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(relation)
+}
+
+// RemoveIssueRelation handles DELETE /api/v1/issues/{id}/relations/{relation_id}
+func (h *IssueHandler) RemoveIssueRelation(w http.ResponseWriter, r *http.Request) {
+	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
+		return
+	}
+	
+	relationID, err := uuid.Parse(chi.URLParam(r, "relation_id"))
+	if err != nil {
+		http.Error(w, "Invalid relation ID", http.StatusBadRequest)
+		return
+	}
+	
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	
+	if err := h.issueService.RemoveIssueRelation(issueID, relationID, user.ID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Relation not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to remove relation: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetIssueActivity handles GET /api/v1/issues/{id}/activity
+func (h *IssueHandler) GetIssueActivity(w http.ResponseWriter, r *http.Request) {
+	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
+		return
+	}
+	
+	// Parse pagination
+	page, limit := h.parsePagination(r)
+	
+	// Get activity
+	response, err := h.issueService.GetIssueActivity(issueID, page, limit)
+	if err != nil {
+		http.Error(w, "Failed to retrieve activity: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetIssueEvents handles GET /api/v1/issues/{id}/events
+func (h *IssueHandler) GetIssueEvents(w http.ResponseWriter, r *http.Request) {
+	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
+		return
+	}
+	
+	// Parse pagination
+	page, limit := h.parsePagination(r)
+	
+	// Get events
+	response, err := h.issueService.GetIssueEvents(issueID, page, limit)
+	if err != nil {
+		http.Error(w, "Failed to retrieve events: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// ListEventAttachments handles GET /api/v1/issues/{id}/events/{event_id}/attachments
+func (h *IssueHandler) ListEventAttachments(w http.ResponseWriter, r *http.Request) {
+	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
+		return
+	}
+	
+	eventID, err := uuid.Parse(chi.URLParam(r, "event_id"))
+	if err != nil {
+		http.Error(w, "Invalid event ID", http.StatusBadRequest)
+		return
+	}
+	
+	attachments, err := h.attachmentService.ListEventAttachments(issueID, eventID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve attachments: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.AttachmentListResponse{Attachments: attachments})
+}
+
+// DownloadAttachment handles GET /api/v1/issues/{id}/events/{event_id}/attachments/{attachment_id}
+func (h *IssueHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
+		return
+	}
+	
+	eventID, err := uuid.Parse(chi.URLParam(r, "event_id"))
+	if err != nil {
+		http.Error(w, "Invalid event ID", http.StatusBadRequest)
+		return
+	}
+	
+	attachmentID, err := uuid.Parse(chi.URLParam(r, "attachment_id"))
+	if err != nil {
+		http.Error(w, "Invalid attachment ID", http.StatusBadRequest)
+		return
+	}
+	
+	attachment, content, err := h.attachmentService.OpenAttachment(issueID, eventID, attachmentID)
+	if err != nil {
+		if errors.Is(err, services.ErrAttachmentNotFound) {
+			http.Error(w, "Attachment not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to retrieve attachment: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer content.Close()
+	
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}))
+	io.Copy(w, content)
+}
+
+// GetIssueStats handles GET /api/v1/projects/{id}/issues/stats
+func (h *IssueHandler) GetIssueStats(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+	
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	
+	// Get statistics
+	stats, err := h.issueService.GetIssueStats(project.ID, user.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve issue statistics: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// BulkUpdateIssues handles POST /api/v1/issues/bulk-update
+func (h *IssueHandler) BulkUpdateIssues(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	
+	var request dto.BulkUpdateIssuesRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+	
+	// Validate request
+	if len(request.IssueIDs) == 0 {
+		http.Error(w, "No issues specified", http.StatusBadRequest)
+		return
+	}
+	
+	if len(request.IssueIDs) > 100 {
+		http.Error(w, "Too many issues specified (max 100)", http.StatusBadRequest)
+		return
+	}
+	
+	if !h.isValidBulkAction(request.Action) {
+		http.Error(w, "Invalid action", http.StatusBadRequest)
+		return
+	}
+	
+	if request.Action == "assign" && request.AssigneeID == nil {
+		http.Error(w, "Assignee ID required for assign action", http.StatusBadRequest)
+		return
+	}
+	
+	if request.ResolvedInRelease != nil && request.Action != "resolve" {
+		http.Error(w, "resolved_in_release requires the resolve action", http.StatusBadRequest)
+		return
+	}
+	
+	// Only issues of projects the user belongs to are updated, and only owners and admins
+	// may delete them
+	var requiredRoles []models.OrganizationRole
+	if request.Action == "delete" {
+		requiredRoles = []models.OrganizationRole{models.RoleOwner, models.RoleAdmin}
+	}
+	allowed, denied, err := h.accessibleIssueIDs(user.ID, request.IssueIDs, requiredRoles...)
+	if err != nil {
+		http.Error(w, "Failed to check issue access: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	response := &dto.BulkUpdateIssuesResponse{
+		UpdatedIDs: make([]uuid.UUID, 0),
+		Errors:     make([]string, 0),
+	}
+	if len(allowed) > 0 {
+		request.IssueIDs = allowed
+		response, err = h.issueService.BulkUpdateIssues(user.ID, request)
+		if err != nil {
+			http.Error(w, "Failed to perform bulk update: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	response.FailedCount += len(denied)
+	response.Errors = append(response.Errors, denied...)
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// MergeIssues handles POST /api/v1/issues/merge
+func (h *IssueHandler) MergeIssues(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	
+	var request dto.MergeIssuesRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+	
+	if request.PrimaryIssueID == uuid.Nil {
+		http.Error(w, "primary_issue_id is required", http.StatusBadRequest)
+		return
+	}
+	if len(request.IssueIDs) == 0 {
+		http.Error(w, "No issues specified", http.StatusBadRequest)
+		return
+	}
+	if len(request.IssueIDs) > services.MaxMergedIssues {
+		http.Error(w, fmt.Sprintf("Too many issues specified (max %d)", services.MaxMergedIssues), http.StatusBadRequest)
+		return
+	}
+	
+	// Every issue merged must belong to a project the user belongs to
+	_, denied, err := h.accessibleIssueIDs(user.ID, append([]uuid.UUID{request.PrimaryIssueID}, request.IssueIDs...))
 	if err != nil {
-		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
+		http.Error(w, "Failed to check issue access: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(denied) > 0 {
+		http.Error(w, denied[0], http.StatusNotFound)
 		return
 	}
 	
-	issue, err := h.issueService.GetIssue(issueID)
+	response, err := h.issueService.MergeIssues(user.ID, request)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		switch {
+		case errors.Is(err, services.ErrIssueNotFound):
 			http.Error(w, "Issue not found", http.StatusNotFound)
-			return
+		case errors.Is(err, services.ErrIssueMergeInvalid):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Failed to merge issues: "+err.Error(), http.StatusInternalServerError)
 		}
-		http.Error(w, "Failed to retrieve issue: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 	
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(issue)
+	json.NewEncoder(w).Encode(response)
 }
 
-// UpdateIssue handles PUT /api/v1/issues/{id}
-func (h *IssueHandler) UpdateIssue(w http.ResponseWriter, r *http.Request) {
+// UnmergeIssue handles POST /api/v1/issues/{id}/unmerge
+func (h *IssueHandler) UnmergeIssue(w http.ResponseWriter, r *http.Request) {
 	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
 	if err != nil {
 		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
@@ -110,96 +832,94 @@ func (h *IssueHandler) UpdateIssue(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	var request dto.IssueUpdateRequest
+	var request dto.UnmergeIssueRequest
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
 		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
 		return
 	}
 	
-	// Validate status if provided
-	if request.Status != nil {
-		if !h.isValidStatus(*request.Status) {
-			http.Error(w, "Invalid status value", http.StatusBadRequest)
-			return
-		}
-	}
-	
-	// Update issue
-	updatedIssue, err := h.issueService.UpdateIssueStatus(issueID, user.ID, request)
+	response, err := h.issueService.UnmergeIssue(issueID, user.ID, request)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		switch {
+		case errors.Is(err, services.ErrIssueNotFound):
 			http.Error(w, "Issue not found", http.StatusNotFound)
-			return
-		}
-		if strings.Contains(err.Error(), "invalid status transition") {
+		case errors.Is(err, services.ErrIssueUnmergeInvalid):
 			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
+		default:
+			http.Error(w, "Failed to unmerge issue: "+err.Error(), http.StatusInternalServerError)
 		}
-		http.Error(w, "Failed to update issue: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 	
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(updatedIssue)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
 }
 
-// AddIssueComment handles POST /api/v1/issues/{id}/comments
-func (h *IssueHandler) AddIssueComment(w http.ResponseWriter, r *http.Request) {
+// GetSimilarIssues handles GET /api/v1/issues/{id}/similar
+func (h *IssueHandler) GetSimilarIssues(w http.ResponseWriter, r *http.Request) {
 	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
 	if err != nil {
 		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
 		return
 	}
 	
-	user, ok := middleware.GetUserFromContext(r.Context())
-	if !ok {
-		http.Error(w, "User not found in context", http.StatusInternalServerError)
-		return
-	}
-	
-	var request dto.IssueCommentRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
-		return
-	}
-	
-	// Validate content
-	if strings.TrimSpace(request.Content) == "" {
-		http.Error(w, "Comment content cannot be empty", http.StatusBadRequest)
-		return
+	limit := 10
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > services.MaxSimilarIssues {
+			http.Error(w, fmt.Sprintf("Invalid limit (1 to %d)", services.MaxSimilarIssues), http.StatusBadRequest)
+			return
+		}
 	}
 	
-	// Add comment
-	comment, err := h.issueService.AddIssueComment(issueID, user.ID, request)
+	similar, err := h.issueService.GetSimilarIssues(issueID, limit)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, services.ErrIssueNotFound) {
 			http.Error(w, "Issue not found", http.StatusNotFound)
 			return
 		}
-		http.Error(w, "Failed to add comment: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Failed to find similar issues: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 	
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(comment)
+	json.NewEncoder(w).Encode(dto.SimilarIssuesResponse{Similar: similar})
 }
 
-// GetIssueComments handles GET /api/v1/issues/{id}/comments
-func (h *IssueHandler) GetIssueComments(w http.ResponseWriter, r *http.Request) {
+// GetIssueParticipants handles GET /api/v1/issues/{id}/participants
+func (h *IssueHandler) GetIssueParticipants(w http.ResponseWriter, r *http.Request) {
+	h.handleSubscription(w, r, h.subscriptionService.GetParticipants)
+}
+
+// SubscribeToIssue handles POST /api/v1/issues/{id}/subscription
+func (h *IssueHandler) SubscribeToIssue(w http.ResponseWriter, r *http.Request) {
+	h.handleSubscription(w, r, h.subscriptionService.Subscribe)
+}
+
+// UnsubscribeFromIssue handles DELETE /api/v1/issues/{id}/subscription
+func (h *IssueHandler) UnsubscribeFromIssue(w http.ResponseWriter, r *http.Request) {
+	h.handleSubscription(w, r, h.subscriptionService.Unsubscribe)
+}
+
+// handleSubscription runs a subscription action of the authenticated user on an issue and
+// responds with the issue's participants
+func (h *IssueHandler) handleSubscription(w http.ResponseWriter, r *http.Request, action func(issueID, userID uuid.UUID) (*dto.IssueParticipantsResponse, error)) {
 	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
 	if err != nil {
 		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
 		return
 	}
 	
-	// Parse pagination
-	page, limit := h.parsePagination(r)
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
 	
-	// Get comments
-	response, err := h.issueService.GetIssueComments(issueID, page, limit)
+	response, err := action(issueID, user.ID)
 	if err != nil {
-		http.Error(w, "Failed to retrieve comments: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Failed to update subscription: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 	
@@ -207,21 +927,26 @@ func (h *IssueHandler) GetIssueComments(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(response)
 }
 
-// GetIssueActivity handles GET /api/v1/issues/{id}/activity
-func (h *IssueHandler) GetIssueActivity(w http.ResponseWriter, r *http.Request) {
+// GetIssueTags handles GET /api/v1/issues/{id}/tags
+func (h *IssueHandler) GetIssueTags(w http.ResponseWriter, r *http.Request) {
 	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
 	if err != nil {
 		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
 		return
 	}
 	
-	// Parse pagination
-	page, limit := h.parsePagination(r)
+	top := services.DefaultIssueTagTopValues
+	if topStr := r.URL.Query().Get("top"); topStr != "" {
+		top, err = strconv.Atoi(topStr)
+		if err != nil || top < 1 || top > services.MaxIssueTagTopValues {
+			http.Error(w, fmt.Sprintf("Invalid top (1 to %d)", services.MaxIssueTagTopValues), http.StatusBadRequest)
+			return
+		}
+	}
 	
-	// Get activity
-	response, err := h.issueService.GetIssueActivity(issueID, page, limit)
+	response, err := h.issueService.GetIssueTags(issueID, top)
 	if err != nil {
-		http.Error(w, "Failed to retrieve activity: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Failed to retrieve tags: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 	
@@ -229,21 +954,23 @@ func (h *IssueHandler) GetIssueActivity(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(response)
 }
 
-// GetIssueEvents handles GET /api/v1/issues/{id}/events
-func (h *IssueHandler) GetIssueEvents(w http.ResponseWriter, r *http.Request) {
+// GetIssueTagValues handles GET /api/v1/issues/{id}/tags/{key}/values
+func (h *IssueHandler) GetIssueTagValues(w http.ResponseWriter, r *http.Request) {
 	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
 	if err != nil {
 		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
 		return
 	}
 	
-	// Parse pagination
 	page, limit := h.parsePagination(r)
 	
-	// Get events
-	response, err := h.issueService.GetIssueEvents(issueID, page, limit)
+	response, err := h.issueService.GetIssueTagValues(issueID, chi.URLParam(r, "key"), page, limit)
 	if err != nil {
-		http.Error(w, "Failed to retrieve events: "+err.Error(), http.StatusInternalServerError)
+		if errors.Is(err, services.ErrIssueTagNotFound) {
+			http.Error(w, "Tag not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to retrieve tag values: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 	
@@ -251,69 +978,137 @@ func (h *IssueHandler) GetIssueEvents(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-// GetIssueStats handles GET /api/v1/projects/{id}/issues/stats
-func (h *IssueHandler) GetIssueStats(w http.ResponseWriter, r *http.Request) {
-	project, ok := middleware.GetProjectFromContext(r.Context())
-	if !ok {
-		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+// GetIssueFingerprintRule handles GET /api/v1/issues/{id}/fingerprint-rule
+func (h *IssueHandler) GetIssueFingerprintRule(w http.ResponseWriter, r *http.Request) {
+	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
 		return
 	}
 	
-	// Get statistics
-	stats, err := h.issueService.GetIssueStats(project.ID)
+	rule, err := h.issueService.GetIssueFingerprintRule(issueID)
 	if err != nil {
-		http.Error(w, "Failed to retrieve issue statistics: "+err.Error(), http.StatusInternalServerError)
+		if errors.Is(err, services.ErrIssueFingerprintRuleNotFound) {
+			http.Error(w, "Fingerprint rule not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get fingerprint rule: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 	
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(stats)
+	json.NewEncoder(w).Encode(rule)
 }
 
-// BulkUpdateIssues handles POST /api/v1/issues/bulk-update
-func (h *IssueHandler) BulkUpdateIssues(w http.ResponseWriter, r *http.Request) {
+// SetIssueFingerprintRule handles PUT /api/v1/issues/{id}/fingerprint-rule
+func (h *IssueHandler) SetIssueFingerprintRule(w http.ResponseWriter, r *http.Request) {
+	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
+		return
+	}
+	
 	user, ok := middleware.GetUserFromContext(r.Context())
 	if !ok {
 		http.Error(w, "User not found in context", http.StatusInternalServerError)
 		return
 	}
 	
-	var request dto.BulkUpdateIssuesRequest
+	var request dto.IssueFingerprintRuleRequest
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
 		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
 		return
 	}
 	
-	// Validate request
-	if len(request.IssueIDs) == 0 {
-		http.Error(w, "No issues specified", http.StatusBadRequest)
+	rule, err := h.issueService.SetIssueFingerprintRule(issueID, user.ID, request)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrIssueNotFound):
+			http.Error(w, "Issue not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrIssueFingerprintRuleInvalid):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, services.ErrIssueFingerprintRuleKeyTaken):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "Failed to set fingerprint rule: "+err.Error(), http.StatusInternalServerError)
+		}
 		return
 	}
 	
-	if len(request.IssueIDs) > 100 {
-		http.Error(w, "Too many issues specified (max 100)", http.StatusBadRequest)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+// DeleteIssueFingerprintRule handles DELETE /api/v1/issues/{id}/fingerprint-rule
+func (h *IssueHandler) DeleteIssueFingerprintRule(w http.ResponseWriter, r *http.Request) {
+	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
 		return
 	}
 	
-	if !h.isValidBulkAction(request.Action) {
-		http.Error(w, "Invalid action", http.StatusBadRequest)
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
 		return
 	}
 	
-	if request.Action == "assign" && request.AssigneeID == nil {
-		http.Error(w, "Assignee ID required for assign action", http.StatusBadRequest)
+	if err := h.issueService.DeleteIssueFingerprintRule(issueID, user.ID); err != nil {
+		if errors.Is(err, services.ErrIssueFingerprintRuleNotFound) {
+			http.Error(w, "Fingerprint rule not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to delete fingerprint rule: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 	
-	// Perform bulk update
-	response, err := h.issueService.BulkUpdateIssues(user.ID, request)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteIssue handles DELETE /api/v1/issues/{id}; only organization owners and admins may
+// delete issues
+func (h *IssueHandler) DeleteIssue(w http.ResponseWriter, r *http.Request) {
+	issueID, err := uuid.Parse(chi.URLParam(r, "issue_id"))
 	if err != nil {
-		http.Error(w, "Failed to perform bulk update: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
 		return
 	}
 	
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+	
+	projectID, err := h.issueService.GetIssueProjectID(issueID)
+	if err != nil {
+		if errors.Is(err, services.ErrIssueNotFound) {
+			http.Error(w, "Issue not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to retrieve issue", http.StatusInternalServerError)
+		return
+	}
+	role, err := h.projectService.CheckProjectAccess(user.ID, projectID)
+	if err != nil {
+		http.Error(w, "Failed to check project access", http.StatusInternalServerError)
+		return
+	}
+	if role != models.RoleOwner && role != models.RoleAdmin {
+		http.Error(w, "Only organization owners and admins can delete issues", http.StatusForbidden)
+		return
+	}
+	
+	if err := h.issueService.DeleteIssue(issueID, user.ID); err != nil {
+		if errors.Is(err, services.ErrIssueNotFound) {
+			http.Error(w, "Issue not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to delete issue: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	w.WriteHeader(http.StatusNoContent)
 }
 
 // Helper methods
@@ -327,10 +1122,16 @@ func (h *IssueHandler) issueAccessMiddleware(next http.Handler) http.Handler {
 			return
 		}
 		
-		// Get the issue to find its project
-		_, err = h.issueService.GetIssue(issueID)
+		user, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			http.Error(w, "User not found in context", http.StatusInternalServerError)
+			return
+		}
+		
+		// Get the issue's project
+		projectID, err := h.issueService.GetIssueProjectID(issueID)
 		if err != nil {
-			if strings.Contains(err.Error(), "not found") {
+			if errors.Is(err, services.ErrIssueNotFound) {
 				http.Error(w, "Issue not found", http.StatusNotFound)
 				return
 			}
@@ -338,22 +1139,92 @@ func (h *IssueHandler) issueAccessMiddleware(next http.Handler) http.Handler {
 			return
 		}
 		
-		// Verify user has access to the project
-		_, ok := middleware.GetUserFromContext(r.Context())
-		if !ok {
-			http.Error(w, "User not found in context", http.StatusInternalServerError)
+		// Issues of projects the user cannot access are reported as missing, so issue IDs
+		// of other organizations cannot be probed
+		if !h.canAccessProject(w, user.ID, projectID) {
+			return
+		}
+		middleware.RecordAPIProject(r.Context(), uuid.Nil, projectID)
+		if middleware.RejectProjectDuringMaintenance(w, r, projectID) {
 			return
 		}
-		
-		// This is a simplified check - in a real implementation, you'd verify project membership
-		// For now, we'll assume if the user can authenticate, they can access any issue
-		// In production, add proper project membership verification here
 		
 		next.ServeHTTP(w, r)
 	})
 }
 
-func (h *IssueHandler) parseIssueFilters(r *http.Request) dto.IssueFilters {
+// canAccessProject checks the user's membership of a project, writing a 404 when access is
+// denied or a 500 when the check fails
+func (h *IssueHandler) canAccessProject(w http.ResponseWriter, userID, projectID uuid.UUID) bool {
+	_, err := h.projectService.CheckProjectAccess(userID, projectID)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrProjectAccessDenied), errors.Is(err, services.ErrProjectNotFound):
+		http.Error(w, "Issue not found", http.StatusNotFound)
+	default:
+		http.Error(w, "Failed to check project access", http.StatusInternalServerError)
+	}
+	return false
+}
+
+// isIssueModerator reports whether the user is an owner or admin of the organization of the
+// issue, who may change the comments of other members
+func (h *IssueHandler) isIssueModerator(issueID, userID uuid.UUID) (bool, error) {
+	projectID, err := h.issueService.GetIssueProjectID(issueID)
+	if err != nil {
+		return false, err
+	}
+	role, err := h.projectService.CheckProjectAccess(userID, projectID)
+	if err != nil {
+		return false, err
+	}
+	return role == models.RoleOwner || role == models.RoleAdmin, nil
+}
+
+// accessibleIssueIDs splits issue IDs into those the user can access and errors for the rest,
+// which are reported as not found like in issueAccessMiddleware. When roles are given, issues
+// of projects where the user has none of them are reported as forbidden.
+func (h *IssueHandler) accessibleIssueIDs(userID uuid.UUID, issueIDs []uuid.UUID, roles ...models.OrganizationRole) ([]uuid.UUID, []string, error) {
+	projectIDs, err := h.issueService.GetIssueProjectIDs(issueIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	
+	allowed := make([]uuid.UUID, 0, len(issueIDs))
+	var denied []string
+	for _, issueID := range issueIDs {
+		projectID, ok := projectIDs[issueID]
+		if ok {
+			role, err := h.projectService.CheckProjectAccess(userID, projectID)
+			switch {
+			case err == nil && len(roles) > 0 && !hasRole(role, roles):
+				denied = append(denied, fmt.Sprintf("Issue %s: insufficient permissions", issueID))
+				continue
+			case err == nil:
+				allowed = append(allowed, issueID)
+				continue
+			case !errors.Is(err, services.ErrProjectAccessDenied) && !errors.Is(err, services.ErrProjectNotFound):
+				return nil, nil, err
+			}
+		}
+		denied = append(denied, fmt.Sprintf("Issue %s not found", issueID))
+	}
+	return allowed, denied, nil
+}
+
+func hasRole(role models.OrganizationRole, roles []models.OrganizationRole) bool {
+	for _, candidate := range roles {
+		if role == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIssueFilters reads the filters of an issue list request; the error, for invalid
+// queries, is meant for the client
+func (h *IssueHandler) parseIssueFilters(r *http.Request) (dto.IssueFilters, error) {
 	query := r.URL.Query()
 	
 	filters := dto.IssueFilters{
@@ -391,12 +1262,78 @@ func (h *IssueHandler) parseIssueFilters(r *http.Request) dto.IssueFilters {
 		filters.Environment = &environment
 	}
 	
-	// Parse search
+	// Parse transaction filter
+	if transaction := query.Get("transaction"); transaction != "" {
+		filters.Transaction = &transaction
+	}
+	
+	// Parse release filter
+	if release := query.Get("release"); release != "" {
+		filters.Release = &release
+	}
+	
+	// Parse regression filter
+	if regression, err := strconv.ParseBool(query.Get("is_regression")); err == nil {
+		filters.Regression = &regression
+	}
+	
+	// Parse search; an assigned:<user_id|me|none> token filters by assignee, and tokens
+	// such as os.name:Windows or browser:Chrome* by the tags derived from event contexts
 	if search := query.Get("search"); search != "" {
-		filters.Search = &search
+		search, assignee := extractAssigneeToken(search)
+		if assignee != "" {
+			filters.AssignedTo = &assignee
+		}
+		search, filters.ContextTags = extractContextTagTokens(search)
+		if search != "" {
+			filters.Search = &search
+		}
 	}
 	
-	// Parse sort and order
+	// Parse priority filter
+	if priorityStr := query.Get("priority"); priorityStr != "" {
+		filters.Priority = strings.Split(priorityStr, ",")
+	}
+	
+	// Parse tag filters such as tags[browser]=Chrome
+	for param, values := range query {
+		key, ok := strings.CutPrefix(param, "tags[")
+		if !ok {
+			continue
+		}
+		key, ok = strings.CutSuffix(key, "]")
+		if !ok || !services.IsValidTagKey(key) {
+			return filters, fmt.Errorf("%w: invalid tag filter %s", services.ErrIssueQueryInvalid, param)
+		}
+		if values[0] == "" {
+			continue
+		}
+		if filters.Tags == nil {
+			filters.Tags = make(map[string]string)
+		}
+		filters.Tags[key] = values[0]
+	}
+	
+	// Parse a Sentry-style query such as is:unresolved level:error assigned:me, whose
+	// terms add to the other parameters
+	if issueQuery := query.Get("query"); issueQuery != "" {
+		if err := services.ApplyIssueQuery(&filters, issueQuery); err != nil {
+			return filters, err
+		}
+	}
+	
+	// Resolve assigned to me to the authenticated user
+	if filters.AssignedTo != nil && *filters.AssignedTo == "me" {
+		if user, ok := middleware.GetUserFromContext(r.Context()); ok {
+			assignee := user.ID.String()
+			filters.AssignedTo = &assignee
+		}
+	}
+	
+	// Parse sort and order; searches are sorted by relevance unless a sort is given
+	if filters.Search != nil {
+		filters.Sort = "relevance"
+	}
 	if sort := query.Get("sort"); sort != "" {
 		if h.isValidSortField(sort) {
 			filters.Sort = sort
@@ -420,7 +1357,47 @@ func (h *IssueHandler) parseIssueFilters(r *http.Request) dto.IssueFilters {
 		}
 	}
 	
-	return filters
+	return filters, nil
+}
+
+// extractAssigneeToken removes the assigned: tokens from a search, returning the rest of
+// the search and the value of the last token
+func extractAssigneeToken(search string) (string, string) {
+	var terms []string
+	assignee := ""
+	for _, term := range strings.Fields(search) {
+		if value, ok := strings.CutPrefix(strings.ToLower(term), "assigned:"); ok && value != "" {
+			assignee = value
+			continue
+		}
+		terms = append(terms, term)
+	}
+	if assignee == "" {
+		return search, ""
+	}
+	return strings.Join(terms, " "), assignee
+}
+
+// extractContextTagTokens removes the tokens filtering by context tags, such as
+// os.name:Windows, from a search, returning the rest of the search and the tag values
+func extractContextTagTokens(search string) (string, map[string]string) {
+	var terms []string
+	var tags map[string]string
+	for _, term := range strings.Fields(search) {
+		key, value, ok := strings.Cut(term, ":")
+		if key = strings.ToLower(key); ok && value != "" && services.IsContextTagKey(key) {
+			if tags == nil {
+				tags = make(map[string]string)
+			}
+			tags[key] = value
+			continue
+		}
+		terms = append(terms, term)
+	}
+	if tags == nil {
+		return search, nil
+	}
+	return strings.Join(terms, " "), tags
 }
 
 func (h *IssueHandler) parsePagination(r *http.Request) (int, int) {
@@ -455,7 +1432,7 @@ func (h *IssueHandler) isValidStatus(status string) bool {
 }
 
 func (h *IssueHandler) isValidSortField(sort string) bool {
-	validSorts := []string{"frequency", "first_seen", "last_seen"}
+	validSorts := []string{"frequency", "first_seen", "last_seen", "transaction", "priority", "relevance"}
 	for _, validSort := range validSorts {
 		if sort == validSort {
 			return true
@@ -464,8 +1441,21 @@ func (h *IssueHandler) isValidSortField(sort string) bool {
 	return false
 }
 
+// isValidEmoji accepts a short token without whitespace: a Unicode emoji sequence or a :shortcode:
+func (h *IssueHandler) isValidEmoji(emoji string) bool {
+	if emoji == "" || len(emoji) > 64 || !utf8.ValidString(emoji) {
+		return false
+	}
+	for _, r := range emoji {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return false
+		}
+	}
+	return true
+}
+
 func (h *IssueHandler) isValidBulkAction(action string) bool {
-	validActions := []string{"resolve", "ignore", "unresolve", "assign"}
+	validActions := []string{"resolve", "ignore", "unresolve", "assign", "delete"}
 	for _, validAction := range validActions {
 		if action == validAction {
 			return true
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/middleware"
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type IssueSyncHandler struct {
+	issueSyncService *services.IssueSyncService
+}
+
+// NewIssueSyncHandler creates a new handler for the issue sync integration
+func NewIssueSyncHandler(issueSyncService *services.IssueSyncService) *IssueSyncHandler {
+	return &IssueSyncHandler{
+		issueSyncService: issueSyncService,
+	}
+}
+
+// RegisterRoutes registers issue sync routes
+func (h *IssueSyncHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware.AuthMiddleware, projectMiddleware *middleware.ProjectMiddleware) {
+	r.Route("/projects/{id}/issue-sync", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(projectMiddleware.RequireProjectAccess)
+
+		r.Get("/", h.GetIssueSync)
+		r.With(projectMiddleware.RequireProjectOwnerOrAdmin).Put("/", h.ConfigureIssueSync)
+		r.With(projectMiddleware.RequireProjectOwnerOrAdmin).Delete("/", h.DeleteIssueSync)
+		r.Get("/deltas", h.ListDeltas)
+	})
+}
+
+// GetIssueSync returns the project's issue sync configuration and delivery state
+func (h *IssueSyncHandler) GetIssueSync(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := h.issueSyncService.GetIssueSync(project.ID)
+	if err != nil {
+		h.writeIssueSyncError(w, err, "Failed to get issue sync")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// ConfigureIssueSync creates or updates the project's issue sync
+func (h *IssueSyncHandler) ConfigureIssueSync(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.IssueSyncRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.URL != nil && len(*req.URL) > 2048 {
+		http.Error(w, "URL is too long (max 2048 characters)", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.issueSyncService.ConfigureIssueSync(project.ID, req)
+	if err != nil {
+		h.writeIssueSyncError(w, err, "Failed to configure issue sync")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// DeleteIssueSync removes the project's issue sync and its deltas
+func (h *IssueSyncHandler) DeleteIssueSync(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.issueSyncService.DeleteIssueSync(project.ID); err != nil {
+		h.writeIssueSyncError(w, err, "Failed to delete issue sync")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeltas returns the issue deltas after ?cursor=, for consumers pulling instead of receiving webhooks
+func (h *IssueSyncHandler) ListDeltas(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var cursor int64
+	if cursorStr := r.URL.Query().Get("cursor"); cursorStr != "" {
+		parsed, err := strconv.ParseInt(cursorStr, 10, 64)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Invalid cursor parameter", http.StatusBadRequest)
+			return
+		}
+		cursor = parsed
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	response, err := h.issueSyncService.ListDeltas(project.ID, cursor, limit)
+	if err != nil {
+		h.writeIssueSyncError(w, err, "Failed to list issue sync deltas")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeIssueSyncError maps issue sync service errors to HTTP responses
+func (h *IssueSyncHandler) writeIssueSyncError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrIssueSyncNotFound):
+		http.Error(w, "Issue sync is not configured for this project", http.StatusNotFound)
+	case errors.Is(err, services.ErrIssueSyncInvalidRequest):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
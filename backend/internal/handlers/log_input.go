@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/middleware"
+	"minisentry/internal/services"
+)
+
+// logEntry is an entry of a log input submission at the error level
+type logEntry struct {
+	line  int
+	raw   []byte
+	event *dto.ErrorEventRequest
+	err   error // why the entry could not be read
+}
+
+// logInputHandler handles structured logs from log pipelines such as Logstash's http output
+// or Fluentd's out_http: JSON lines, or a JSON array of entries. Entries at the error or
+// fatal level become error events; the others are ignored. Every error entry counts against
+// the project's rate limits and quotas; the request itself counts as its first.
+func (eh *ErrorHandler) logInputHandler(w http.ResponseWriter, r *http.Request) {
+	projectCtx, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		eh.writeErrorResponse(w, http.StatusInternalServerError, "project not found in context")
+		return
+	}
+
+	if !isBulkContentType(r.Header.Get("Content-Type")) {
+		eh.writeErrorResponse(w, http.StatusUnsupportedMediaType,
+			"unsupported content type, expected application/x-ndjson or application/json")
+		return
+	}
+
+	body, err := eh.readBulkBody(w, r)
+	if err != nil {
+		if isMaxBytesError(err) || errors.Is(err, errBulkTooLarge) {
+			eh.writeErrorResponse(w, http.StatusRequestEntityTooLarge, errBulkTooLarge.Error())
+			return
+		}
+		eh.writeErrorResponse(w, bodyErrorStatus(err), fmt.Sprintf("failed to read request body: %v", err))
+		return
+	}
+
+	entries, ignored, err := readLogEntries(body)
+	if err != nil {
+		eh.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(entries) > maxBulkEvents {
+		eh.writeErrorResponse(w, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("log input exceeds maximum of %d error entries", maxBulkEvents))
+		return
+	}
+
+	source := eventSource{
+		ctx:       r.Context(),
+		clientIP:  middleware.ClientIP(r),
+		userAgent: r.Header.Get("User-Agent"),
+		buffering: bufferingWindow(r),
+	}
+	maxSize := eh.maxEventSize(r)
+
+	response := dto.LogInputResponse{Ignored: ignored, Results: make([]dto.BulkEventResult, 0, len(entries))}
+	for i, entry := range entries {
+		var result dto.BulkEventResult
+		switch {
+		case entry.err != nil:
+			result = dto.BulkEventResult{Status: services.EventStatusInvalid, Error: entry.err.Error()}
+		case maxSize > 0 && len(entry.raw) > maxSize:
+			result = dto.BulkEventResult{
+				Status: services.EventStatusInvalid,
+				Error:  fmt.Sprintf("event exceeds maximum size of %d bytes", maxSize),
+			}
+		default:
+			result = eh.ingestEvent(source, projectCtx.ID, entry.event, entry.raw, i > 0)
+		}
+		result.Line = entry.line
+
+		switch result.Status {
+		case services.EventStatusStored, services.EventStatusSampled, services.EventStatusQueued:
+			response.Accepted++
+		default:
+			response.Rejected++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// readLogEntries reads the entries of a log input submission, a JSON array or JSON lines,
+// returning those at the error level and how many were ignored
+func readLogEntries(body []byte) ([]logEntry, int, error) {
+	var raws [][]byte
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		var array []json.RawMessage
+		if err := json.Unmarshal(trimmed, &array); err != nil {
+			return nil, 0, fmt.Errorf("invalid JSON array of log entries: %v", err)
+		}
+		for _, raw := range array {
+			raws = append(raws, raw)
+		}
+	} else {
+		raws = splitBulkLines(body)
+	}
+
+	var entries []logEntry
+	ignored, read := 0, 0
+	for i, raw := range raws {
+		if len(raw) == 0 {
+			continue
+		}
+		read++
+
+		var fields map[string]interface{}
+		if err := json.Unmarshal(raw, &fields); err != nil || fields == nil {
+			entries = append(entries, logEntry{line: i + 1, raw: raw, err: errors.New("log entry is not a JSON object")})
+			continue
+		}
+		event := services.LogEntryEvent(fields)
+		if event == nil {
+			ignored++
+			continue
+		}
+		entries = append(entries, logEntry{line: i + 1, raw: raw, event: event})
+	}
+	if read == 0 {
+		return nil, 0, errors.New("no log entries in submission")
+	}
+	return entries, ignored, nil
+}
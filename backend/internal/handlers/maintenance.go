@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"minisentry/internal/middleware"
+	"minisentry/internal/models"
+	"minisentry/internal/services"
+)
+
+type bufferingWindowContextKey struct{}
+
+// UseMaintenance makes ingestion follow maintenance windows: rejected with 503, or only
+// accepted on the endpoints that queue events when the window buffers ingestion
+func (eh *ErrorHandler) UseMaintenance(maintenance *services.MaintenanceService) {
+	eh.maintenance = maintenance
+}
+
+// maintenanceMiddleware applies the active maintenance window of the project's organization
+// to ingestion. It must run after DSN authentication.
+func (eh *ErrorHandler) maintenanceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if eh.maintenance == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		projectCtx, ok := middleware.GetProjectFromContext(r.Context())
+		if !ok {
+			eh.writeErrorResponse(w, http.StatusInternalServerError, "project not found in context")
+			return
+		}
+
+		window := eh.maintenance.ActiveWindow(&projectCtx.OrganizationID)
+		switch {
+		case window == nil || window.Ingestion == models.MaintenanceIngestionAccept:
+			next.ServeHTTP(w, r)
+		case window.Ingestion == models.MaintenanceIngestionBuffer && eh.ingestQueue != nil && isQueueableIngestPath(r.URL.Path):
+			// Events are queued and held by the workers until the window ends
+			ctx := context.WithValue(r.Context(), bufferingWindowContextKey{}, window)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		default:
+			eh.writeMaintenance(w, window)
+		}
+	})
+}
+
+// bufferingWindow returns the maintenance window an ingestion request is buffered for, nil
+// when events may be processed within the request
+func bufferingWindow(r *http.Request) *models.MaintenanceWindow {
+	window, _ := r.Context().Value(bufferingWindowContextKey{}).(*models.MaintenanceWindow)
+	return window
+}
+
+// isQueueableIngestPath reports whether an ingestion endpoint queues error events
+func isQueueableIngestPath(path string) bool {
+	return strings.HasSuffix(path, "/store/") || strings.HasSuffix(path, "/integration/otlp/v1/logs") ||
+		path == "/api/v1/errors/ingest" || path == "/api/v1/errors/bulk" || path == "/api/v1/errors/logs"
+}
+
+// writeMaintenance refuses an event during a maintenance window with 503, telling SDKs when
+// to retry through Retry-After and X-Sentry-Rate-Limits
+func (eh *ErrorHandler) writeMaintenance(w http.ResponseWriter, window *models.MaintenanceWindow) {
+	retryAfter := int(middleware.MaintenanceRetryAfter.Seconds())
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	w.Header().Set("X-Sentry-Rate-Limits", fmt.Sprintf("%d::organization:maintenance", retryAfter))
+	middleware.WriteSentryError(w, http.StatusServiceUnavailable, window.Message)
+}
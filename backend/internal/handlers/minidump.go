@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/middleware"
+	"minisentry/internal/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxMinidumpSize bounds minidump uploads, which are much larger than JSON events
+	maxMinidumpSize = 100 << 20
+
+	// minidumpFormField is the multipart field Breakpad, Crashpad and sentry-native upload the dump in
+	minidumpFormField = "upload_file_minidump"
+)
+
+// sentryMinidumpHandler handles multipart minidump uploads from native crash reporters
+func (eh *ErrorHandler) sentryMinidumpHandler(w http.ResponseWriter, r *http.Request) {
+	receivedAt := time.Now()
+	projectCtx, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		eh.writeErrorResponse(w, http.StatusInternalServerError, "project not found in context")
+		return
+	}
+
+	projectID, err := uuid.Parse(chi.URLParam(r, "project_id"))
+	if err != nil {
+		eh.writeErrorResponse(w, http.StatusBadRequest, "invalid project ID format")
+		return
+	}
+
+	if projectID != projectCtx.ID {
+		eh.writeErrorResponse(w, http.StatusForbidden, "project ID mismatch")
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		eh.writeErrorResponse(w, http.StatusUnsupportedMediaType, "unsupported content type, expected multipart/form-data")
+		return
+	}
+
+	bodyReader, err := eh.getBodyReader(r)
+	if err != nil {
+		eh.writeErrorResponse(w, bodyErrorStatus(err), fmt.Sprintf("failed to read request body: %v", err))
+		return
+	}
+	defer bodyReader.Close()
+	r.Body = http.MaxBytesReader(w, bodyReader, maxMinidumpSize)
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		eh.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid multipart upload: %v", err))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, fileHeader, err := r.FormFile(minidumpFormField)
+	if err != nil {
+		eh.writeErrorResponse(w, http.StatusBadRequest, "missing "+minidumpFormField+" file")
+		return
+	}
+	dump, err := readMinidumpFile(file)
+	file.Close()
+	if err != nil {
+		eh.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("failed to read minidump: %v", err))
+		return
+	}
+
+	eventData, err := eh.parseMinidumpEventData(r.MultipartForm.Value)
+	if err != nil {
+		eh.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid event data: %v", err))
+		return
+	}
+	eh.errorService.CorrectClockSkew(eventData, middleware.SentryAuthTimestamp(r), receivedAt)
+
+	response, err := eh.errorService.ProcessMinidump(projectID, eventData, dump, middleware.ClientIP(r), r.Header.Get("User-Agent"))
+	if err != nil {
+		eh.writeProcessingError(w, err)
+		return
+	}
+
+	eventID, err := uuid.Parse(response.ID)
+	if err != nil {
+		eh.writeErrorResponse(w, http.StatusInternalServerError, "failed to store minidump")
+		return
+	}
+
+	name := fileHeader.Filename
+	if name == "" {
+		name = "minidump.dmp"
+	}
+	if _, err := eh.attachmentService.StoreAttachment(projectID, eventID, name, "application/x-dmp", models.AttachmentTypeMinidump, bytes.NewReader(dump)); err != nil {
+		middleware.Logf(r.Context(), "Failed to store minidump for event %s: %v", response.EventID, err)
+		eh.writeErrorResponse(w, http.StatusInternalServerError, "failed to store minidump")
+		return
+	}
+
+	// Any other uploaded files (logs, crashpad annotations) are kept as regular attachments
+	for field, headers := range r.MultipartForm.File {
+		if field == minidumpFormField {
+			continue
+		}
+		for _, header := range headers {
+			attachment, err := header.Open()
+			if err != nil {
+				continue
+			}
+			contentType := header.Header.Get("Content-Type")
+			if _, err := eh.attachmentService.StoreAttachment(projectID, eventID, header.Filename, contentType, models.AttachmentTypeDefault, attachment); err != nil {
+				middleware.Logf(r.Context(), "Failed to store attachment %s for event %s: %v", header.Filename, response.EventID, err)
+			}
+			attachment.Close()
+		}
+	}
+
+	// Crash reporters log the response body as the report ID, so it is the plain event ID
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, response.EventID)
+}
+
+// readMinidumpFile reads the uploaded dump, which some reporters gzip before upload
+func readMinidumpFile(file io.Reader) ([]byte, error) {
+	dump, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(dump) >= 2 && dump[0] == 0x1f && dump[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(bytes.NewReader(dump))
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+		return io.ReadAll(io.LimitReader(gzipReader, maxMinidumpSize))
+	}
+	return dump, nil
+}
+
+// parseMinidumpEventData builds the event sent alongside a minidump. SDKs send it either as
+// a JSON `sentry` field or as bracketed fields like `sentry[release]` and `sentry[tags][key]`;
+// any other form field is kept as extra data.
+func (eh *ErrorHandler) parseMinidumpEventData(fields map[string][]string) (*dto.ErrorEventRequest, error) {
+	event := make(map[string]interface{})
+	if values := fields["sentry"]; len(values) > 0 && strings.TrimSpace(values[0]) != "" {
+		if err := json.Unmarshal([]byte(values[0]), &event); err != nil {
+			return nil, fmt.Errorf("sentry field is not valid JSON: %w", err)
+		}
+	}
+
+	extra, _ := event["extra"].(map[string]interface{})
+	if extra == nil {
+		extra = make(map[string]interface{})
+	}
+
+	for key, values := range fields {
+		if key == "sentry" || len(values) == 0 {
+			continue
+		}
+		if !strings.HasPrefix(key, "sentry[") || !strings.HasSuffix(key, "]") {
+			extra[key] = values[0]
+			continue
+		}
+
+		path := strings.Split(strings.TrimSuffix(strings.TrimPrefix(key, "sentry["), "]"), "][")
+		if err := setNestedField(event, path, values[0]); err != nil {
+			return nil, fmt.Errorf("field %s: %w", key, err)
+		}
+	}
+
+	if len(extra) > 0 {
+		event["extra"] = extra
+	}
+
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	var eventData dto.ErrorEventRequest
+	if err := json.Unmarshal(encoded, &eventData); err != nil {
+		return nil, err
+	}
+	return &eventData, nil
+}
+
+// setNestedField assigns value at the given path, creating intermediate objects
+func setNestedField(target map[string]interface{}, path []string, value string) error {
+	for i, key := range path {
+		if key == "" {
+			return errors.New("empty key")
+		}
+		if i == len(path)-1 {
+			target[key] = value
+			return nil
+		}
+
+		next, ok := target[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			target[key] = next
+		}
+		target = next
+	}
+	return nil
+}
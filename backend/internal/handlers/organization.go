@@ -53,6 +53,7 @@ func (h *OrganizationHandler) RegisterRoutes(r chi.Router, authMiddleware *middl
 			r.Get("/", h.GetOrganization)
 			r.Put("/", h.UpdateOrganization)
 			r.Delete("/", h.DeleteOrganization)
+			r.Get("/settings/history", h.GetSettingHistory)
 
 			// Organization members
 			r.Route("/members", func(r chi.Router) {
@@ -214,6 +215,24 @@ func (h *OrganizationHandler) UpdateOrganization(w http.ResponseWriter, r *http.
 	h.writeJSONResponse(w, http.StatusOK, response)
 }
 
+// GetSettingHistory lists changes of the organization's settings, optionally of one ?setting=
+func (h *OrganizationHandler) GetSettingHistory(w http.ResponseWriter, r *http.Request) {
+	orgCtx, ok := middleware.GetOrganizationFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "organization not found in context")
+		return
+	}
+
+	page, limit := parseSettingHistoryPagination(r)
+	history, err := h.orgService.GetSettingHistory(orgCtx.ID, r.URL.Query().Get("setting"), page, limit)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "failed to get setting history")
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, history)
+}
+
 // DeleteOrganization deletes organization (owner only)
 func (h *OrganizationHandler) DeleteOrganization(w http.ResponseWriter, r *http.Request) {
 	// Get user and organization from context
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"minisentry/internal/middleware"
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// OTLP/HTTP encodings of export requests and responses
+const (
+	otlpProtobufContentType = "application/x-protobuf"
+	otlpJSONContentType     = "application/json"
+)
+
+// otlpLogsHandler handles OTLP/HTTP log exports, so services instrumented with OpenTelemetry
+// can report errors without a Sentry SDK. Log records carrying an exception become error
+// events sharing the pipeline of the other endpoints; other records are ignored. Every
+// exception counts against the project's rate limits and quotas, the request as its first.
+// Records that are not accepted are reported as rejected in the export's partial success.
+func (eh *ErrorHandler) otlpLogsHandler(w http.ResponseWriter, r *http.Request) {
+	projectCtx, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		eh.writeErrorResponse(w, http.StatusInternalServerError, "project not found in context")
+		return
+	}
+
+	projectID, err := uuid.Parse(chi.URLParam(r, "project_id"))
+	if err != nil {
+		eh.writeErrorResponse(w, http.StatusBadRequest, "invalid project ID format")
+		return
+	}
+
+	if projectID != projectCtx.ID {
+		eh.writeErrorResponse(w, http.StatusForbidden, "project ID mismatch")
+		return
+	}
+
+	contentType := strings.ToLower(strings.TrimSpace(strings.Split(r.Header.Get("Content-Type"), ";")[0]))
+	if contentType != otlpProtobufContentType && contentType != otlpJSONContentType {
+		eh.writeErrorResponse(w, http.StatusUnsupportedMediaType,
+			"unsupported content type, expected application/x-protobuf or application/json")
+		return
+	}
+
+	body, err := eh.readOTLPBody(w, r)
+	if err != nil {
+		if isMaxBytesError(err) {
+			eh.writeErrorResponse(w, http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("export exceeds maximum size of %d bytes", maxBulkSize))
+			return
+		}
+		eh.writeErrorResponse(w, bodyErrorStatus(err), fmt.Sprintf("failed to read request body: %v", err))
+		return
+	}
+
+	// LogsData has the fields of ExportLogsServiceRequest, and the same encodings
+	var logs logspb.LogsData
+	if contentType == otlpJSONContentType {
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, &logs)
+	} else {
+		err = proto.Unmarshal(body, &logs)
+	}
+	if err != nil {
+		eh.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid OTLP logs export: %v", err))
+		return
+	}
+
+	source := eventSource{
+		ctx:       r.Context(),
+		clientIP:  middleware.ClientIP(r),
+		userAgent: r.Header.Get("User-Agent"),
+		buffering: bufferingWindow(r),
+	}
+	maxSize := eh.maxEventSize(r)
+
+	var rejected int64
+	var rejectedMessage string
+	first := true
+	for _, resourceLogs := range logs.GetResourceLogs() {
+		for _, scopeLogs := range resourceLogs.GetScopeLogs() {
+			for _, record := range scopeLogs.GetLogRecords() {
+				eventData := services.OTLPLogException(resourceLogs.GetResource(), scopeLogs.GetScope(), record)
+				if eventData == nil {
+					continue
+				}
+
+				var result string
+				if maxSize > 0 && proto.Size(record) > maxSize {
+					result = fmt.Sprintf("event exceeds maximum size of %d bytes", maxSize)
+				} else {
+					outcome := eh.ingestEvent(source, projectID, eventData, nil, !first)
+					switch outcome.Status {
+					case services.EventStatusStored, services.EventStatusSampled, services.EventStatusQueued,
+						services.EventStatusDuplicate: // records of an export that is retried
+					default:
+						result = outcome.Error
+					}
+				}
+				first = false
+
+				if result != "" {
+					rejected++
+					if rejectedMessage == "" {
+						rejectedMessage = result
+					}
+				}
+			}
+		}
+	}
+
+	eh.writeOTLPResponse(w, contentType, rejected, rejectedMessage)
+}
+
+// readOTLPBody reads the decompressed body of an OTLP export
+func (eh *ErrorHandler) readOTLPBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBulkSize)
+
+	bodyReader, err := eh.getBodyReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer bodyReader.Close()
+
+	// Also bounds the decompressed size of compressed bodies
+	body, err := io.ReadAll(io.LimitReader(bodyReader, maxBulkSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxBulkSize {
+		return nil, &http.MaxBytesError{Limit: maxBulkSize}
+	}
+	return body, nil
+}
+
+// writeOTLPResponse answers an export with an ExportLogsServiceResponse in the encoding of
+// the request, reporting the records that were rejected as a partial success
+func (eh *ErrorHandler) writeOTLPResponse(w http.ResponseWriter, contentType string, rejected int64, message string) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+
+	if contentType == otlpJSONContentType {
+		response := map[string]interface{}{}
+		if rejected > 0 {
+			response["partialSuccess"] = map[string]interface{}{
+				"rejectedLogRecords": rejected,
+				"errorMessage":       message,
+			}
+		}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if rejected > 0 {
+		// partial_success (1) { rejected_log_records (1), error_message (2) }
+		var partialSuccess []byte
+		partialSuccess = protowire.AppendTag(partialSuccess, 1, protowire.VarintType)
+		partialSuccess = protowire.AppendVarint(partialSuccess, uint64(rejected))
+		partialSuccess = protowire.AppendTag(partialSuccess, 2, protowire.BytesType)
+		partialSuccess = protowire.AppendString(partialSuccess, message)
+
+		var response []byte
+		response = protowire.AppendTag(response, 1, protowire.BytesType)
+		response = protowire.AppendBytes(response, partialSuccess)
+		w.Write(response)
+	}
+}
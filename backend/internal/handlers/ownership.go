@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/middleware"
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type OwnershipHandler struct {
+	ownershipService *services.OwnershipService
+}
+
+// NewOwnershipHandler creates a new handler for project ownership rules
+func NewOwnershipHandler(ownershipService *services.OwnershipService) *OwnershipHandler {
+	return &OwnershipHandler{
+		ownershipService: ownershipService,
+	}
+}
+
+// RegisterRoutes registers ownership rule routes
+func (h *OwnershipHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware.AuthMiddleware, projectMiddleware *middleware.ProjectMiddleware) {
+	r.Route("/projects/{id}/ownership-rules", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(projectMiddleware.RequireProjectAccess)
+
+		r.Get("/", h.GetOwnershipRules)
+		r.With(projectMiddleware.RequireProjectOwnerOrAdmin).Put("/", h.UpdateOwnershipRules)
+	})
+}
+
+// GetOwnershipRules returns the project's ownership rules
+func (h *OwnershipHandler) GetOwnershipRules(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := h.ownershipService.GetOwnershipRules(project.ID)
+	if err != nil {
+		if errors.Is(err, services.ErrProjectNotFound) {
+			http.Error(w, "Project not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get ownership rules", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// UpdateOwnershipRules replaces the project's ownership rules
+func (h *OwnershipHandler) UpdateOwnershipRules(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.OwnershipRulesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.ownershipService.UpdateOwnershipRules(user.ID, project.ID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOwnershipRulesInvalid):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, services.ErrProjectNotFound):
+			http.Error(w, "Project not found", http.StatusNotFound)
+		default:
+			http.Error(w, "Failed to update ownership rules", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
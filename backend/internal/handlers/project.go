@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
 
 	"minisentry/internal/dto"
 	"minisentry/internal/middleware"
@@ -15,13 +16,13 @@ import (
 
 // Project validation errors
 var (
-	ErrProjectEmptyName        = errors.New("project name cannot be empty")
-	ErrProjectEmptySlug        = errors.New("project slug cannot be empty")
-	ErrProjectEmptyPlatform    = errors.New("project platform cannot be empty")
-	ErrProjectNameTooLong      = errors.New("project name is too long (max 255 characters)")
-	ErrProjectSlugTooLong      = errors.New("project slug is too long (max 100 characters)")
-	ErrProjectDescTooLong      = errors.New("project description is too long (max 1000 characters)")
-	ErrProjectInvalidPlatform  = errors.New("invalid project platform")
+	ErrProjectEmptyName      = errors.New("project name cannot be empty")
+	ErrProjectEmptySlug      = errors.New("project slug cannot be empty")
+	ErrProjectEmptyPlatform  = errors.New("project platform cannot be empty")
+	ErrProjectNameTooLong    = errors.New("project name is too long (max 255 characters)")
+	ErrProjectSlugTooLong    = errors.New("project slug is too long (max 100 characters)")
+	ErrProjectDescTooLong    = errors.New("project description is too long (max 1000 characters)")
+	ErrProjectRunbookTooLong = errors.New("project runbook is too long (max 50000 characters)")
 )
 
 type ProjectHandler struct {
@@ -55,11 +56,25 @@ func (h *ProjectHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware
 		r.Put("/", h.UpdateProject)
 		r.Delete("/", h.DeleteProject)
 		r.Put("/configuration", h.UpdateProjectConfiguration)
+		r.Get("/settings/history", h.GetSettingHistory)
+		r.Get("/environments", h.ListEnvironments)
 		
 		r.Route("/keys", func(r chi.Router) {
 			r.Post("/regenerate", h.RegenerateProjectKey)
 		})
 	})
+
+	// Known project platforms
+	r.With(authMiddleware.RequireAuth).Get("/platforms", h.ListPlatforms)
+}
+
+// ListPlatforms lists the known project platforms and their aliases. Other platform
+// identifiers may be accepted too, unless the server only allows known platforms.
+func (h *ProjectHandler) ListPlatforms(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"platforms": h.projectService.Platforms(),
+	})
 }
 
 // CreateProject creates a new project within an organization
@@ -100,7 +115,7 @@ func (h *ProjectHandler) CreateProject(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, services.ErrInsufficientPermissions):
 			http.Error(w, "Insufficient permissions to create project", http.StatusForbidden)
 		case errors.Is(err, services.ErrProjectInvalidPlatform):
-			http.Error(w, "Invalid project platform", http.StatusBadRequest)
+			http.Error(w, err.Error(), http.StatusBadRequest)
 		default:
 			http.Error(w, "Failed to create project", http.StatusInternalServerError)
 		}
@@ -199,7 +214,7 @@ func (h *ProjectHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, services.ErrInsufficientPermissions):
 			http.Error(w, "Insufficient permissions to update project", http.StatusForbidden)
 		case errors.Is(err, services.ErrProjectInvalidPlatform):
-			http.Error(w, "Invalid project platform", http.StatusBadRequest)
+			http.Error(w, err.Error(), http.StatusBadRequest)
 		default:
 			http.Error(w, "Failed to update project", http.StatusInternalServerError)
 		}
@@ -274,6 +289,7 @@ func (h *ProjectHandler) RegenerateProjectKey(w http.ResponseWriter, r *http.Req
 	// Return key response
 	response := dto.ProjectKeyResponse{
 		PublicKey: updatedProject.PublicKey,
+		SecretKey: updatedProject.SecretKey,
 		DSN:       updatedProject.DSN,
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -303,20 +319,31 @@ func (h *ProjectHandler) UpdateProjectConfiguration(w http.ResponseWriter, r *ht
 		return
 	}
 
-	// Validate platform if provided
-	if req.Platform != nil && !dto.IsPlatformSupported(*req.Platform) {
-		http.Error(w, "Invalid project platform", http.StatusBadRequest)
+	if req.Runbook != nil && len(*req.Runbook) > 50000 {
+		http.Error(w, ErrProjectRunbookTooLong.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.MaxEventSize != nil && *req.MaxEventSize < 0 {
+		http.Error(w, "Maximum event size cannot be negative", http.StatusBadRequest)
+		return
+	}
+
+	if (req.EventSampleThreshold != nil && *req.EventSampleThreshold < 0) || (req.EventSampleRate != nil && *req.EventSampleRate < 0) {
+		http.Error(w, "Event sampling threshold and rate cannot be negative", http.StatusBadRequest)
 		return
 	}
 
 	// Update configuration
-	updatedProject, err := h.projectService.UpdateProjectConfiguration(user.ID, project.ID, req.IsActive, req.Platform)
+	updatedProject, err := h.projectService.UpdateProjectConfiguration(user.ID, project.ID, req.IsActive, req.Platform, req.Runbook, req.MaxEventSize, req.RequireSecretKey, req.IPAllowList, req.IPDenyList, req.EventSampleThreshold, req.EventSampleRate, req.PinnedContextKeys)
 	if err != nil {
 		switch {
 		case errors.Is(err, services.ErrInsufficientPermissions):
 			http.Error(w, "Insufficient permissions to update project configuration", http.StatusForbidden)
 		case errors.Is(err, services.ErrProjectInvalidPlatform):
-			http.Error(w, "Invalid project platform", http.StatusBadRequest)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, services.ErrProjectInvalidIPRange), errors.Is(err, services.ErrProjectInvalidPinnedKey):
+			http.Error(w, err.Error(), http.StatusBadRequest)
 		default:
 			http.Error(w, "Failed to update project configuration", http.StatusInternalServerError)
 		}
@@ -329,6 +356,50 @@ func (h *ProjectHandler) UpdateProjectConfiguration(w http.ResponseWriter, r *ht
 	json.NewEncoder(w).Encode(response)
 }
 
+// GetSettingHistory lists changes of the project's settings, optionally of one ?setting=
+func (h *ProjectHandler) GetSettingHistory(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	page, limit := parseSettingHistoryPagination(r)
+	history, err := h.projectService.GetSettingHistory(project.ID, r.URL.Query().Get("setting"), page, limit)
+	if err != nil {
+		http.Error(w, "Failed to get setting history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// ListEnvironments lists the environments the project's data was sent from
+func (h *ProjectHandler) ListEnvironments(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	environments, err := h.projectService.ListEnvironments(project.ID)
+	if err != nil {
+		http.Error(w, "Failed to list environments", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(environments)
+}
+
+// parseSettingHistoryPagination reads ?page= and ?limit=; the service applies defaults
+func parseSettingHistoryPagination(r *http.Request) (int, int) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	return page, limit
+}
+
 // Validation helpers
 
 func (h *ProjectHandler) validateCreateProjectRequest(req *dto.CreateProjectRequest) error {
@@ -349,9 +420,6 @@ func (h *ProjectHandler) validateCreateProjectRequest(req *dto.CreateProjectRequ
 	if req.Platform == "" {
 		return ErrProjectEmptyPlatform
 	}
-	if !dto.IsPlatformSupported(req.Platform) {
-		return ErrProjectInvalidPlatform
-	}
 
 	if req.Description != nil && len(*req.Description) > 1000 {
 		return ErrProjectDescTooLong
@@ -374,9 +442,6 @@ func (h *ProjectHandler) validateUpdateProjectRequest(req *dto.UpdateProjectRequ
 		if *req.Platform == "" {
 			return ErrProjectEmptyPlatform
 		}
-		if !dto.IsPlatformSupported(*req.Platform) {
-			return ErrProjectInvalidPlatform
-		}
 	}
 
 	if req.Description != nil && len(*req.Description) > 1000 {
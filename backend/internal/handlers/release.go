@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/middleware"
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxReleaseArtifactSize bounds a single uploaded release file, such as a source map
+const maxReleaseArtifactSize = 40 << 20
+
+type ReleaseHandler struct {
+	releaseService *services.ReleaseService
+}
+
+// NewReleaseHandler creates a new handler for releases and their artifacts
+func NewReleaseHandler(releaseService *services.ReleaseService) *ReleaseHandler {
+	return &ReleaseHandler{
+		releaseService: releaseService,
+	}
+}
+
+// RegisterRoutes registers release routes. Besides project members, organization ingest
+// tokens may call them, so CI systems can upload source maps.
+func (h *ReleaseHandler) RegisterRoutes(r chi.Router, ingestTokenMiddleware *middleware.IngestTokenMiddleware) {
+	r.Route("/projects/{id}/releases", func(r chi.Router) {
+		r.Use(ingestTokenMiddleware.RequireReleaseAccess)
+
+		r.Get("/", h.ListReleases)
+		r.Post("/", h.CreateRelease)
+		r.Get("/latest", h.GetLatestRelease)
+		r.Get("/{version}/files", h.ListArtifacts)
+		r.Post("/{version}/files", h.UploadArtifact)
+	})
+}
+
+// ListReleases returns the project's releases, newest first or, with ?sort=version, from
+// the latest version
+func (h *ReleaseHandler) ListReleases(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	response, err := h.releaseService.ListReleases(project.ID, page, limit, r.URL.Query().Get("sort"))
+	if err != nil {
+		h.writeReleaseError(w, err, "Failed to list releases")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetLatestRelease returns the project's highest semantic version, or its most recent
+// release when it has no semantic versions
+func (h *ReleaseHandler) GetLatestRelease(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := h.releaseService.GetLatestRelease(project.ID)
+	if err != nil {
+		h.writeReleaseError(w, err, "Failed to get latest release")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateRelease creates a release, or updates it when the version already exists
+func (h *ReleaseHandler) CreateRelease(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.ReleaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Ref != nil && len(*req.Ref) > 255 {
+		http.Error(w, "Ref is too long (max 255 characters)", http.StatusBadRequest)
+		return
+	}
+	if req.URL != nil && len(*req.URL) > 500 {
+		http.Error(w, "URL is too long (max 500 characters)", http.StatusBadRequest)
+		return
+	}
+
+	response, created, err := h.releaseService.CreateRelease(project.ID, req)
+	if err != nil {
+		h.writeReleaseError(w, err, "Failed to create release")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if created {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// ListArtifacts lists the files uploaded for a release
+func (h *ReleaseHandler) ListArtifacts(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	version, err := url.PathUnescape(chi.URLParam(r, "version"))
+	if err != nil {
+		http.Error(w, "Invalid release version", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.releaseService.ListArtifacts(project.ID, version)
+	if err != nil {
+		h.writeReleaseError(w, err, "Failed to list release files")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// UploadArtifact stores a file for a release from a multipart upload with a "file" part and
+// optional "name" (defaults to the file name) and "dist" fields
+func (h *ReleaseHandler) UploadArtifact(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	version, err := url.PathUnescape(chi.URLParam(r, "version"))
+	if err != nil {
+		http.Error(w, "Invalid release version", http.StatusBadRequest)
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		http.Error(w, "Unsupported content type, expected multipart/form-data", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxReleaseArtifactSize+1<<20)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, fmt.Sprintf("File exceeds maximum size of %d bytes", maxReleaseArtifactSize), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Invalid multipart upload", http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Missing file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	if header.Size > maxReleaseArtifactSize {
+		http.Error(w, fmt.Sprintf("File exceeds maximum size of %d bytes", maxReleaseArtifactSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	name := r.FormValue("name")
+	if strings.TrimSpace(name) == "" {
+		name = header.Filename
+	}
+	var dist *string
+	if value := r.FormValue("dist"); value != "" {
+		dist = &value
+	}
+	contentType := header.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	response, err := h.releaseService.UploadArtifact(project.ID, version, name, dist, contentType, file)
+	if err != nil {
+		h.writeReleaseError(w, err, "Failed to upload release file")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeReleaseError maps release service errors to HTTP responses
+func (h *ReleaseHandler) writeReleaseError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrReleaseNotFound):
+		http.Error(w, "Release not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrReleaseInvalidVersion), errors.Is(err, services.ErrReleaseArtifactInvalid):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/middleware"
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type SavedSearchHandler struct {
+	savedSearchService *services.SavedSearchService
+}
+
+// NewSavedSearchHandler creates a new handler for saved issue searches
+func NewSavedSearchHandler(savedSearchService *services.SavedSearchService) *SavedSearchHandler {
+	return &SavedSearchHandler{
+		savedSearchService: savedSearchService,
+	}
+}
+
+// RegisterRoutes registers saved search routes
+func (h *SavedSearchHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware.AuthMiddleware, orgMiddleware *middleware.OrganizationMiddleware, projectMiddleware *middleware.ProjectMiddleware) {
+	r.Route("/projects/{id}/saved-searches", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(projectMiddleware.RequireProjectAccess)
+
+		r.Get("/", h.ListProjectSavedSearches)
+		r.Post("/", h.CreateProjectSavedSearch)
+	})
+
+	r.Route("/organizations/{org_id}/saved-searches", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(orgMiddleware.RequireOrganizationAccess)
+
+		r.Get("/", h.ListOrganizationSavedSearches)
+		r.With(orgMiddleware.RequireOwnerOrAdmin).Post("/", h.CreateOrganizationSavedSearch)
+	})
+
+	// Individual saved search routes; the service checks who manages the search
+	r.Route("/saved-searches/{search_id}", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+
+		r.Put("/", h.UpdateSavedSearch)
+		r.Delete("/", h.DeleteSavedSearch)
+	})
+}
+
+// ListProjectSavedSearches returns the user's saved searches of the project and the
+// organization's shared ones
+func (h *SavedSearchHandler) ListProjectSavedSearches(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := h.savedSearchService.ListProjectSavedSearches(user.ID, project.OrganizationID, project.ID)
+	if err != nil {
+		http.Error(w, "Failed to list saved searches", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateProjectSavedSearch saves a search of the project, personal or shared with the
+// organization
+func (h *SavedSearchHandler) CreateProjectSavedSearch(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.SavedSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.savedSearchService.CreateSavedSearch(user.ID, project.Role, project.OrganizationID, &project.ID, req)
+	if err != nil {
+		h.writeSavedSearchError(w, err, "Failed to create saved search")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// ListOrganizationSavedSearches returns the organization's saved searches of every project
+func (h *SavedSearchHandler) ListOrganizationSavedSearches(w http.ResponseWriter, r *http.Request) {
+	org, ok := middleware.GetOrganizationFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Organization not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := h.savedSearchService.ListOrganizationSavedSearches(org.ID)
+	if err != nil {
+		http.Error(w, "Failed to list saved searches", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateOrganizationSavedSearch saves a search shared with the organization for every project
+func (h *SavedSearchHandler) CreateOrganizationSavedSearch(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	org, ok := middleware.GetOrganizationFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Organization not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.SavedSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	req.Visibility = services.SavedSearchVisibilityOrganization
+
+	response, err := h.savedSearchService.CreateSavedSearch(user.ID, org.Role, org.ID, nil, req)
+	if err != nil {
+		h.writeSavedSearchError(w, err, "Failed to create saved search")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// UpdateSavedSearch replaces the name, filters and visibility of a saved search
+func (h *SavedSearchHandler) UpdateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	userID, searchID, ok := h.parseSavedSearchRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.SavedSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.savedSearchService.UpdateSavedSearch(userID, searchID, req)
+	if err != nil {
+		h.writeSavedSearchError(w, err, "Failed to update saved search")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// DeleteSavedSearch deletes a saved search
+func (h *SavedSearchHandler) DeleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	userID, searchID, ok := h.parseSavedSearchRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.savedSearchService.DeleteSavedSearch(userID, searchID); err != nil {
+		h.writeSavedSearchError(w, err, "Failed to delete saved search")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseSavedSearchRequest reads the authenticated user and the saved search ID of the route
+func (h *SavedSearchHandler) parseSavedSearchRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	searchID, err := uuid.Parse(chi.URLParam(r, "search_id"))
+	if err != nil {
+		http.Error(w, "Invalid saved search ID format", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return user.ID, searchID, true
+}
+
+// writeSavedSearchError maps saved search service errors to HTTP responses
+func (h *SavedSearchHandler) writeSavedSearchError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrSavedSearchNotFound):
+		http.Error(w, "Saved search not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrSavedSearchAccessDenied):
+		http.Error(w, "Only admins and owners manage organization saved searches", http.StatusForbidden)
+	case errors.Is(err, services.ErrSavedSearchInvalid):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
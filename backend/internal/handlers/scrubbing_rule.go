@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/middleware"
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ScrubbingRuleHandler struct {
+	scrubbingRuleService *services.ScrubbingRuleService
+	errorService         *services.ErrorService
+}
+
+// NewScrubbingRuleHandler creates a new handler for project data scrubbing rules
+func NewScrubbingRuleHandler(scrubbingRuleService *services.ScrubbingRuleService, errorService *services.ErrorService) *ScrubbingRuleHandler {
+	return &ScrubbingRuleHandler{
+		scrubbingRuleService: scrubbingRuleService,
+		errorService:         errorService,
+	}
+}
+
+// RegisterRoutes registers scrubbing rule routes
+func (h *ScrubbingRuleHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware.AuthMiddleware, projectMiddleware *middleware.ProjectMiddleware) {
+	r.Route("/projects/{id}/scrubbing-rules", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(projectMiddleware.RequireProjectAccess)
+
+		r.Get("/", h.GetScrubbingRules)
+		r.With(projectMiddleware.RequireProjectOwnerOrAdmin).Put("/", h.UpdateScrubbingRules)
+	})
+
+	r.Route("/projects/{id}/scrubbing/test", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(projectMiddleware.RequireProjectAccess)
+
+		r.Post("/", h.TestScrubbing)
+	})
+}
+
+// GetScrubbingRules returns the project's data scrubbing rules
+func (h *ScrubbingRuleHandler) GetScrubbingRules(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := h.scrubbingRuleService.GetScrubbingRules(project.ID)
+	if err != nil {
+		http.Error(w, "Failed to get scrubbing rules", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// UpdateScrubbingRules replaces the project's data scrubbing rules
+func (h *ScrubbingRuleHandler) UpdateScrubbingRules(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.ScrubbingRulesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.scrubbingRuleService.UpdateScrubbingRules(user.ID, project.ID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrScrubbingRulesInvalid):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, services.ErrProjectNotFound):
+			http.Error(w, "Project not found", http.StatusNotFound)
+		default:
+			http.Error(w, "Failed to update scrubbing rules", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// TestScrubbing returns a sample event as it would be stored after data scrubbing, with
+// the fields redacted and why, using the project's rules or the rules in the request
+func (h *ScrubbingRuleHandler) TestScrubbing(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.ScrubbingTestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := h.errorService.ValidateErrorPayload(&req.Event); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.errorService.TestScrubbing(project.ID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrScrubbingRulesInvalid):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, services.ErrProjectNotFound):
+			http.Error(w, "Project not found", http.StatusNotFound)
+		default:
+			http.Error(w, "Failed to test scrubbing", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
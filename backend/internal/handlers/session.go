@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/middleware"
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type SessionHandler struct {
+	sessionService *services.SessionService
+}
+
+// NewSessionHandler creates a new release health handler
+func NewSessionHandler(sessionService *services.SessionService) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+	}
+}
+
+// RegisterRoutes registers release health routes
+func (h *SessionHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware.AuthMiddleware, projectMiddleware *middleware.ProjectMiddleware) {
+	r.Route("/projects/{id}/release-health", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(projectMiddleware.RequireProjectAccess)
+
+		r.Get("/", h.GetReleaseHealth)
+	})
+}
+
+// GetReleaseHealth returns crash-free session and user rates per release and environment
+func (h *SessionHandler) GetReleaseHealth(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	filters := &dto.ReleaseHealthFilters{
+		Release:     r.URL.Query().Get("release"),
+		Environment: r.URL.Query().Get("environment"),
+	}
+
+	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+		since, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			http.Error(w, "Invalid since parameter, expected RFC3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		filters.Since = &since
+	}
+
+	results, err := h.sessionService.GetReleaseHealth(project.ID, filters)
+	if err != nil {
+		http.Error(w, "Failed to get release health", http.StatusInternalServerError)
+		return
+	}
+
+	if results == nil {
+		results = []dto.ReleaseHealthResponse{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.ReleaseHealthListResponse{Results: results})
+}
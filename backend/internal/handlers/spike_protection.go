@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"minisentry/internal/middleware"
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type SpikeProtectionHandler struct {
+	spikeProtectionService *services.SpikeProtectionService
+}
+
+// NewSpikeProtectionHandler creates a new handler for project spike protection stats
+func NewSpikeProtectionHandler(spikeProtectionService *services.SpikeProtectionService) *SpikeProtectionHandler {
+	return &SpikeProtectionHandler{
+		spikeProtectionService: spikeProtectionService,
+	}
+}
+
+// RegisterRoutes registers spike protection routes
+func (h *SpikeProtectionHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware.AuthMiddleware, projectMiddleware *middleware.ProjectMiddleware) {
+	r.Route("/projects/{id}/spike-protection", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(projectMiddleware.RequireProjectAccess)
+
+		r.Get("/", h.GetSpikeProtection)
+	})
+}
+
+// GetSpikeProtection returns the project's spike protection state and how many events it
+// dropped per hour since ?since= (RFC3339, default 7 days ago)
+func (h *SpikeProtectionHandler) GetSpikeProtection(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var since time.Time
+	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+		parsed, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			http.Error(w, "Invalid since parameter, expected RFC3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		since = parsed
+	}
+
+	response, err := h.spikeProtectionService.GetSpikeProtection(project.ID, since)
+	if err != nil {
+		http.Error(w, "Failed to get spike protection stats", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
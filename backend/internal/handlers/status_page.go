@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/middleware"
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type StatusPageHandler struct {
+	statusPageService *services.StatusPageService
+}
+
+// NewStatusPageHandler creates a new status page handler
+func NewStatusPageHandler(statusPageService *services.StatusPageService) *StatusPageHandler {
+	return &StatusPageHandler{
+		statusPageService: statusPageService,
+	}
+}
+
+// RegisterRoutes registers status page management and public status routes
+func (h *StatusPageHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware.AuthMiddleware, orgMiddleware *middleware.OrganizationMiddleware) {
+	// Status page management; members can read it, owners and admins change it
+	r.Route("/organizations/{org_id}/status-page", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(orgMiddleware.RequireOrganizationAccess)
+
+		r.Get("/", h.GetStatusPage)
+		r.With(orgMiddleware.RequireOwnerOrAdmin).Put("/", h.UpdateStatusPage)
+		r.Get("/components", h.ListComponents)
+		r.With(orgMiddleware.RequireOwnerOrAdmin).Post("/components", h.CreateComponent)
+		r.With(orgMiddleware.RequireOwnerOrAdmin).Put("/components/{component_id}", h.UpdateComponent)
+		r.With(orgMiddleware.RequireOwnerOrAdmin).Delete("/components/{component_id}", h.DeleteComponent)
+		r.With(orgMiddleware.RequireOwnerOrAdmin).Get("/subscribers", h.ListSubscribers)
+		r.With(orgMiddleware.RequireOwnerOrAdmin).Delete("/subscribers/{subscriber_id}", h.RemoveSubscriber)
+	})
+
+	// Public status page, no authentication
+	r.Route("/status/{org_slug}", func(r chi.Router) {
+		r.Get("/", h.GetPublicStatus)
+		r.Post("/subscribe", h.Subscribe)
+		// Linked from emails, hence GET
+		r.Get("/confirm", h.ConfirmSubscription)
+		r.Get("/unsubscribe", h.Unsubscribe)
+	})
+}
+
+// GetStatusPage returns the organization's status page configuration
+func (h *StatusPageHandler) GetStatusPage(w http.ResponseWriter, r *http.Request) {
+	org, ok := middleware.GetOrganizationFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Organization not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := h.statusPageService.GetStatusPage(org.ID)
+	if err != nil {
+		h.writeStatusPageError(w, err, "Failed to get status page")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// UpdateStatusPage enables, disables or retitles the organization's status page
+func (h *StatusPageHandler) UpdateStatusPage(w http.ResponseWriter, r *http.Request) {
+	org, ok := middleware.GetOrganizationFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Organization not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.StatusPageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Title != nil && len(*req.Title) > 255 {
+		http.Error(w, "Title is too long (max 255 characters)", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.statusPageService.UpdateStatusPage(org.ID, req)
+	if err != nil {
+		h.writeStatusPageError(w, err, "Failed to update status page")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// ListComponents lists the status page components with their current status
+func (h *StatusPageHandler) ListComponents(w http.ResponseWriter, r *http.Request) {
+	org, ok := middleware.GetOrganizationFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Organization not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	components, err := h.statusPageService.ListComponents(org.ID)
+	if err != nil {
+		h.writeStatusPageError(w, err, "Failed to list components")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"components": components})
+}
+
+// CreateComponent adds a component to the status page
+func (h *StatusPageHandler) CreateComponent(w http.ResponseWriter, r *http.Request) {
+	org, ok := middleware.GetOrganizationFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Organization not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.CreateStatusComponentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if len(req.Name) > 255 {
+		http.Error(w, "Name is too long (max 255 characters)", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.statusPageService.CreateComponent(org.ID, req)
+	if err != nil {
+		h.writeStatusPageError(w, err, "Failed to create component")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// UpdateComponent changes a status page component and its project mapping
+func (h *StatusPageHandler) UpdateComponent(w http.ResponseWriter, r *http.Request) {
+	org, ok := middleware.GetOrganizationFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Organization not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	componentID, err := uuid.Parse(chi.URLParam(r, "component_id"))
+	if err != nil {
+		http.Error(w, "Invalid component ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.UpdateStatusComponentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Name != nil && len(*req.Name) > 255 {
+		http.Error(w, "Name is too long (max 255 characters)", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.statusPageService.UpdateComponent(org.ID, componentID, req)
+	if err != nil {
+		h.writeStatusPageError(w, err, "Failed to update component")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// DeleteComponent removes a component from the status page
+func (h *StatusPageHandler) DeleteComponent(w http.ResponseWriter, r *http.Request) {
+	org, ok := middleware.GetOrganizationFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Organization not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	componentID, err := uuid.Parse(chi.URLParam(r, "component_id"))
+	if err != nil {
+		http.Error(w, "Invalid component ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.statusPageService.DeleteComponent(org.ID, componentID); err != nil {
+		h.writeStatusPageError(w, err, "Failed to delete component")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListSubscribers lists the email subscribers of the status page
+func (h *StatusPageHandler) ListSubscribers(w http.ResponseWriter, r *http.Request) {
+	org, ok := middleware.GetOrganizationFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Organization not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	subscribers, err := h.statusPageService.ListSubscribers(org.ID)
+	if err != nil {
+		h.writeStatusPageError(w, err, "Failed to list subscribers")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"subscribers": subscribers})
+}
+
+// RemoveSubscriber removes an email subscriber from the status page
+func (h *StatusPageHandler) RemoveSubscriber(w http.ResponseWriter, r *http.Request) {
+	org, ok := middleware.GetOrganizationFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Organization not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	subscriberID, err := uuid.Parse(chi.URLParam(r, "subscriber_id"))
+	if err != nil {
+		http.Error(w, "Invalid subscriber ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.statusPageService.RemoveSubscriber(org.ID, subscriberID); err != nil {
+		h.writeStatusPageError(w, err, "Failed to remove subscriber")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetPublicStatus returns the public status of an organization
+func (h *StatusPageHandler) GetPublicStatus(w http.ResponseWriter, r *http.Request) {
+	response, err := h.statusPageService.GetPublicStatus(chi.URLParam(r, "org_slug"))
+	if err != nil {
+		h.writeStatusPageError(w, err, "Failed to get status")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=30")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Subscribe registers an email address for status updates
+func (h *StatusPageHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	var req dto.StatusSubscribeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.statusPageService.Subscribe(chi.URLParam(r, "org_slug"), req.Email); err != nil {
+		h.writeStatusPageError(w, err, "Failed to subscribe")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Check your inbox to confirm the subscription"})
+}
+
+// ConfirmSubscription confirms an email subscription from the emailed link
+func (h *StatusPageHandler) ConfirmSubscription(w http.ResponseWriter, r *http.Request) {
+	if err := h.statusPageService.ConfirmSubscription(chi.URLParam(r, "org_slug"), r.URL.Query().Get("token")); err != nil {
+		h.writeStatusPageError(w, err, "Failed to confirm subscription")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Subscription confirmed"})
+}
+
+// Unsubscribe removes an email subscription from the emailed link
+func (h *StatusPageHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	if err := h.statusPageService.Unsubscribe(chi.URLParam(r, "org_slug"), r.URL.Query().Get("token")); err != nil {
+		h.writeStatusPageError(w, err, "Failed to unsubscribe")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Unsubscribed"})
+}
+
+// writeStatusPageError maps status page service errors to HTTP responses
+func (h *StatusPageHandler) writeStatusPageError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrStatusPageNotFound):
+		http.Error(w, "Status page not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrStatusComponentNotFound):
+		http.Error(w, "Component not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrStatusSubscriberNotFound):
+		http.Error(w, "Subscription not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrStatusProjectNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, services.ErrStatusPageInvalidRequest):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
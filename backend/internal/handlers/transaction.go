@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/middleware"
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type TransactionHandler struct {
+	transactionService *services.TransactionService
+}
+
+// NewTransactionHandler creates a new transaction handler
+func NewTransactionHandler(transactionService *services.TransactionService) *TransactionHandler {
+	return &TransactionHandler{
+		transactionService: transactionService,
+	}
+}
+
+// RegisterRoutes registers performance transaction routes
+func (h *TransactionHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware.AuthMiddleware, projectMiddleware *middleware.ProjectMiddleware) {
+	r.Route("/projects/{id}/transactions", func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(projectMiddleware.RequireProjectAccess)
+
+		r.Get("/", h.ListTransactions)
+		r.Get("/summary", h.GetTransactionSummary)
+		r.Get("/{transaction_id}", h.GetTransaction)
+	})
+}
+
+// ListTransactions lists the project's transactions, newest first
+func (h *TransactionHandler) ListTransactions(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := h.transactionService.ListTransactions(project.ID, h.parseTransactionFilters(r))
+	if err != nil {
+		http.Error(w, "Failed to list transactions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetTransactionSummary returns duration aggregates per transaction name
+func (h *TransactionHandler) GetTransactionSummary(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	results, err := h.transactionService.GetTransactionSummary(project.ID, h.parseTransactionFilters(r))
+	if err != nil {
+		http.Error(w, "Failed to summarize transactions", http.StatusInternalServerError)
+		return
+	}
+
+	if results == nil {
+		results = []dto.TransactionSummaryResponse{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.TransactionSummaryListResponse{Results: results})
+}
+
+// GetTransaction returns a single transaction with its spans
+func (h *TransactionHandler) GetTransaction(w http.ResponseWriter, r *http.Request) {
+	project, ok := middleware.GetProjectFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Project not found in context", http.StatusInternalServerError)
+		return
+	}
+
+	transactionID, err := uuid.Parse(chi.URLParam(r, "transaction_id"))
+	if err != nil {
+		http.Error(w, "Invalid transaction ID format", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.transactionService.GetTransaction(project.ID, transactionID)
+	if err != nil {
+		if errors.Is(err, services.ErrTransactionNotFound) {
+			http.Error(w, "Transaction not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get transaction", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TransactionHandler) parseTransactionFilters(r *http.Request) dto.TransactionFilters {
+	query := r.URL.Query()
+	filters := dto.TransactionFilters{}
+
+	if name := query.Get("name"); name != "" {
+		filters.Name = &name
+	}
+	if op := query.Get("op"); op != "" {
+		filters.Op = &op
+	}
+	if environment := query.Get("environment"); environment != "" {
+		filters.Environment = &environment
+	}
+	if page, err := strconv.Atoi(query.Get("page")); err == nil {
+		filters.Page = page
+	}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil {
+		filters.Limit = limit
+	}
+
+	return filters
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 
 	"minisentry/internal/dto"
@@ -15,6 +16,9 @@ import (
 type UserHandler struct {
 	userService *services.UserService
 	jwtService  *services.JWTService
+
+	// cookies, when set, keeps the sessions of logins in httpOnly cookies
+	cookies *middleware.SessionCookies
 }
 
 // NewUserHandler creates a new user handler
@@ -25,6 +29,12 @@ func NewUserHandler(userService *services.UserService, jwtService *services.JWTS
 	}
 }
 
+// UseSessionCookies makes logins keep their session in httpOnly cookies instead of returning
+// the tokens
+func (h *UserHandler) UseSessionCookies(cookies *middleware.SessionCookies) {
+	h.cookies = cookies
+}
+
 // RegisterRoutes registers all user-related routes
 func (h *UserHandler) RegisterRoutes(r chi.Router, authMiddleware *middleware.AuthMiddleware) {
 	// Public routes (no authentication required)
@@ -79,13 +89,7 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 	var userResponse dto.UserResponse
 	userResponse.ConvertFromModel(user.ToResponse())
 	
-	response := dto.AuthResponse{
-		AccessToken:  tokens.AccessToken,
-		RefreshToken: tokens.RefreshToken,
-		TokenType:    tokens.TokenType,
-		ExpiresIn:    tokens.ExpiresIn,
-		User:         userResponse,
-	}
+	response := h.authResponse(w, tokens, userResponse)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -127,13 +131,7 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var userResponse dto.UserResponse
 	userResponse.ConvertFromModel(user.ToResponse())
 	
-	response := dto.AuthResponse{
-		AccessToken:  tokens.AccessToken,
-		RefreshToken: tokens.RefreshToken,
-		TokenType:    tokens.TokenType,
-		ExpiresIn:    tokens.ExpiresIn,
-		User:         userResponse,
-	}
+	response := h.authResponse(w, tokens, userResponse)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -143,16 +141,26 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 // RefreshToken handles JWT token refresh
 func (h *UserHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	var req dto.RefreshTokenRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !(h.cookies != nil && errors.Is(err, io.EOF)) {
 		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON format", err)
 		return
 	}
 
+	// Cookie sessions send their refresh token as a cookie
+	fromCookie := req.RefreshToken == "" && h.cookies != nil
+	if fromCookie {
+		req.RefreshToken = h.cookies.RefreshToken(r)
+	}
+
 	// Refresh tokens
 	tokens, err := h.jwtService.RefreshToken(req.RefreshToken)
 	if err != nil {
 		switch {
 		case errors.Is(err, services.ErrInvalidToken), errors.Is(err, services.ErrTokenExpired):
+			if fromCookie {
+				// Lets the dashboard see the session is over
+				h.cookies.ClearSession(w)
+			}
 			h.writeErrorResponse(w, http.StatusUnauthorized, "Invalid or expired refresh token", nil)
 		default:
 			h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to refresh token", err)
@@ -161,6 +169,12 @@ func (h *UserHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	if fromCookie {
+		csrfToken := h.cookies.SetSession(w, tokens)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(dto.CookieSessionResponse{CSRFToken: csrfToken, ExpiresIn: tokens.ExpiresIn})
+		return
+	}
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(tokens)
 }
@@ -169,6 +183,10 @@ func (h *UserHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 func (h *UserHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	// Since JWTs are stateless, logout is handled client-side by discarding tokens
 	// In a production environment, you might want to maintain a blacklist of tokens
+	if h.cookies != nil {
+		h.cookies.ClearSession(w)
+	}
+
 	response := dto.SuccessResponse{
 		Success: true,
 		Message: "Successfully logged out",
@@ -295,6 +313,23 @@ func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// authResponse returns the tokens of a new session, or sets its cookies and returns its
+// CSRF token for cookie sessions
+func (h *UserHandler) authResponse(w http.ResponseWriter, tokens *services.TokenPair, user dto.UserResponse) dto.AuthResponse {
+	response := dto.AuthResponse{
+		TokenType: tokens.TokenType,
+		ExpiresIn: tokens.ExpiresIn,
+		User:      user,
+	}
+	if h.cookies != nil {
+		response.CSRFToken = h.cookies.SetSession(w, tokens)
+	} else {
+		response.AccessToken = tokens.AccessToken
+		response.RefreshToken = tokens.RefreshToken
+	}
+	return response
+}
+
 // writeErrorResponse writes a standardized error response
 func (h *UserHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string, err error) {
 	response := dto.ErrorResponse{
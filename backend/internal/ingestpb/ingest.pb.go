@@ -0,0 +1,1379 @@
+// gRPC ingestion of error events for internal producers. Events go through the same
+// pipeline as the store endpoint; the fields mirror the Sentry event payload.
+//
+// Regenerate the Go code with `make proto`.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: internal/ingestpb/ingest.proto
+
+package ingestpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SendEventRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Event *ErrorEvent            `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	// When the producer sent the event, used to correct its clock skew
+	SentAt        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendEventRequest) Reset() {
+	*x = SendEventRequest{}
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendEventRequest) ProtoMessage() {}
+
+func (x *SendEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendEventRequest.ProtoReflect.Descriptor instead.
+func (*SendEventRequest) Descriptor() ([]byte, []int) {
+	return file_internal_ingestpb_ingest_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SendEventRequest) GetEvent() *ErrorEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *SendEventRequest) GetSentAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+// SendEventResponse is the outcome of an event. Rejected events are answered with a
+// status and error rather than a gRPC error, so streams carry on past them.
+type SendEventResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	EventId string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// stored, sampled or queued when accepted; invalid, duplicate, filtered, dropped,
+	// rate_limited or failed otherwise
+	Status        string        `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Error         string        `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Errors        []*FieldError `protobuf:"bytes,4,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendEventResponse) Reset() {
+	*x = SendEventResponse{}
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendEventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendEventResponse) ProtoMessage() {}
+
+func (x *SendEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendEventResponse.ProtoReflect.Descriptor instead.
+func (*SendEventResponse) Descriptor() ([]byte, []int) {
+	return file_internal_ingestpb_ingest_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SendEventResponse) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *SendEventResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SendEventResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *SendEventResponse) GetErrors() []*FieldError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+// FieldError is a problem with one field of a rejected event
+type FieldError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldError) Reset() {
+	*x = FieldError{}
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldError) ProtoMessage() {}
+
+func (x *FieldError) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldError.ProtoReflect.Descriptor instead.
+func (*FieldError) Descriptor() ([]byte, []int) {
+	return file_internal_ingestpb_ingest_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FieldError) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FieldError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ErrorEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       *string                `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3,oneof" json:"event_id,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Level         *string                `protobuf:"bytes,3,opt,name=level,proto3,oneof" json:"level,omitempty"`
+	Logger        *string                `protobuf:"bytes,4,opt,name=logger,proto3,oneof" json:"logger,omitempty"`
+	Platform      *string                `protobuf:"bytes,5,opt,name=platform,proto3,oneof" json:"platform,omitempty"`
+	Release       *string                `protobuf:"bytes,6,opt,name=release,proto3,oneof" json:"release,omitempty"`
+	Environment   *string                `protobuf:"bytes,7,opt,name=environment,proto3,oneof" json:"environment,omitempty"`
+	ServerName    *string                `protobuf:"bytes,8,opt,name=server_name,json=serverName,proto3,oneof" json:"server_name,omitempty"`
+	Transaction   *string                `protobuf:"bytes,9,opt,name=transaction,proto3,oneof" json:"transaction,omitempty"`
+	Message       *Message               `protobuf:"bytes,10,opt,name=message,proto3" json:"message,omitempty"`
+	Exceptions    []*Exception           `protobuf:"bytes,11,rep,name=exceptions,proto3" json:"exceptions,omitempty"` // The exception chain, innermost last like exception.values
+	User          *User                  `protobuf:"bytes,12,opt,name=user,proto3" json:"user,omitempty"`
+	Request       *Request               `protobuf:"bytes,13,opt,name=request,proto3" json:"request,omitempty"`
+	Tags          map[string]string      `protobuf:"bytes,14,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Extra         *structpb.Struct       `protobuf:"bytes,15,opt,name=extra,proto3" json:"extra,omitempty"`
+	Breadcrumbs   []*Breadcrumb          `protobuf:"bytes,16,rep,name=breadcrumbs,proto3" json:"breadcrumbs,omitempty"`
+	Contexts      *structpb.Struct       `protobuf:"bytes,17,opt,name=contexts,proto3" json:"contexts,omitempty"`
+	Fingerprint   []string               `protobuf:"bytes,18,rep,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	Modules       map[string]string      `protobuf:"bytes,19,rep,name=modules,proto3" json:"modules,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Sdk           *SDK                   `protobuf:"bytes,20,opt,name=sdk,proto3" json:"sdk,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ErrorEvent) Reset() {
+	*x = ErrorEvent{}
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorEvent) ProtoMessage() {}
+
+func (x *ErrorEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorEvent.ProtoReflect.Descriptor instead.
+func (*ErrorEvent) Descriptor() ([]byte, []int) {
+	return file_internal_ingestpb_ingest_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ErrorEvent) GetEventId() string {
+	if x != nil && x.EventId != nil {
+		return *x.EventId
+	}
+	return ""
+}
+
+func (x *ErrorEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *ErrorEvent) GetLevel() string {
+	if x != nil && x.Level != nil {
+		return *x.Level
+	}
+	return ""
+}
+
+func (x *ErrorEvent) GetLogger() string {
+	if x != nil && x.Logger != nil {
+		return *x.Logger
+	}
+	return ""
+}
+
+func (x *ErrorEvent) GetPlatform() string {
+	if x != nil && x.Platform != nil {
+		return *x.Platform
+	}
+	return ""
+}
+
+func (x *ErrorEvent) GetRelease() string {
+	if x != nil && x.Release != nil {
+		return *x.Release
+	}
+	return ""
+}
+
+func (x *ErrorEvent) GetEnvironment() string {
+	if x != nil && x.Environment != nil {
+		return *x.Environment
+	}
+	return ""
+}
+
+func (x *ErrorEvent) GetServerName() string {
+	if x != nil && x.ServerName != nil {
+		return *x.ServerName
+	}
+	return ""
+}
+
+func (x *ErrorEvent) GetTransaction() string {
+	if x != nil && x.Transaction != nil {
+		return *x.Transaction
+	}
+	return ""
+}
+
+func (x *ErrorEvent) GetMessage() *Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *ErrorEvent) GetExceptions() []*Exception {
+	if x != nil {
+		return x.Exceptions
+	}
+	return nil
+}
+
+func (x *ErrorEvent) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *ErrorEvent) GetRequest() *Request {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *ErrorEvent) GetTags() map[string]string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ErrorEvent) GetExtra() *structpb.Struct {
+	if x != nil {
+		return x.Extra
+	}
+	return nil
+}
+
+func (x *ErrorEvent) GetBreadcrumbs() []*Breadcrumb {
+	if x != nil {
+		return x.Breadcrumbs
+	}
+	return nil
+}
+
+func (x *ErrorEvent) GetContexts() *structpb.Struct {
+	if x != nil {
+		return x.Contexts
+	}
+	return nil
+}
+
+func (x *ErrorEvent) GetFingerprint() []string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return nil
+}
+
+func (x *ErrorEvent) GetModules() map[string]string {
+	if x != nil {
+		return x.Modules
+	}
+	return nil
+}
+
+func (x *ErrorEvent) GetSdk() *SDK {
+	if x != nil {
+		return x.Sdk
+	}
+	return nil
+}
+
+type Message struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Params        []*structpb.Value      `protobuf:"bytes,2,rep,name=params,proto3" json:"params,omitempty"`
+	Formatted     *string                `protobuf:"bytes,3,opt,name=formatted,proto3,oneof" json:"formatted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_internal_ingestpb_ingest_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Message) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Message) GetParams() []*structpb.Value {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *Message) GetFormatted() string {
+	if x != nil && x.Formatted != nil {
+		return *x.Formatted
+	}
+	return ""
+}
+
+type Exception struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          *string                `protobuf:"bytes,1,opt,name=type,proto3,oneof" json:"type,omitempty"`
+	Value         *string                `protobuf:"bytes,2,opt,name=value,proto3,oneof" json:"value,omitempty"`
+	Module        *string                `protobuf:"bytes,3,opt,name=module,proto3,oneof" json:"module,omitempty"`
+	Mechanism     *Mechanism             `protobuf:"bytes,4,opt,name=mechanism,proto3" json:"mechanism,omitempty"`
+	Frames        []*Frame               `protobuf:"bytes,5,rep,name=frames,proto3" json:"frames,omitempty"` // The stack trace, oldest frame first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Exception) Reset() {
+	*x = Exception{}
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Exception) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Exception) ProtoMessage() {}
+
+func (x *Exception) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Exception.ProtoReflect.Descriptor instead.
+func (*Exception) Descriptor() ([]byte, []int) {
+	return file_internal_ingestpb_ingest_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Exception) GetType() string {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return ""
+}
+
+func (x *Exception) GetValue() string {
+	if x != nil && x.Value != nil {
+		return *x.Value
+	}
+	return ""
+}
+
+func (x *Exception) GetModule() string {
+	if x != nil && x.Module != nil {
+		return *x.Module
+	}
+	return ""
+}
+
+func (x *Exception) GetMechanism() *Mechanism {
+	if x != nil {
+		return x.Mechanism
+	}
+	return nil
+}
+
+func (x *Exception) GetFrames() []*Frame {
+	if x != nil {
+		return x.Frames
+	}
+	return nil
+}
+
+type Mechanism struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Description   *string                `protobuf:"bytes,2,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	HelpLink      *string                `protobuf:"bytes,3,opt,name=help_link,json=helpLink,proto3,oneof" json:"help_link,omitempty"`
+	Handled       *bool                  `protobuf:"varint,4,opt,name=handled,proto3,oneof" json:"handled,omitempty"`
+	Data          *structpb.Struct       `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Mechanism) Reset() {
+	*x = Mechanism{}
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Mechanism) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Mechanism) ProtoMessage() {}
+
+func (x *Mechanism) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Mechanism.ProtoReflect.Descriptor instead.
+func (*Mechanism) Descriptor() ([]byte, []int) {
+	return file_internal_ingestpb_ingest_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Mechanism) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Mechanism) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *Mechanism) GetHelpLink() string {
+	if x != nil && x.HelpLink != nil {
+		return *x.HelpLink
+	}
+	return ""
+}
+
+func (x *Mechanism) GetHandled() bool {
+	if x != nil && x.Handled != nil {
+		return *x.Handled
+	}
+	return false
+}
+
+func (x *Mechanism) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type Frame struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Filename        *string                `protobuf:"bytes,1,opt,name=filename,proto3,oneof" json:"filename,omitempty"`
+	Function        *string                `protobuf:"bytes,2,opt,name=function,proto3,oneof" json:"function,omitempty"`
+	Module          *string                `protobuf:"bytes,3,opt,name=module,proto3,oneof" json:"module,omitempty"`
+	Lineno          *int32                 `protobuf:"varint,4,opt,name=lineno,proto3,oneof" json:"lineno,omitempty"`
+	Colno           *int32                 `protobuf:"varint,5,opt,name=colno,proto3,oneof" json:"colno,omitempty"`
+	AbsPath         *string                `protobuf:"bytes,6,opt,name=abs_path,json=absPath,proto3,oneof" json:"abs_path,omitempty"`
+	ContextLine     *string                `protobuf:"bytes,7,opt,name=context_line,json=contextLine,proto3,oneof" json:"context_line,omitempty"`
+	PreContext      []string               `protobuf:"bytes,8,rep,name=pre_context,json=preContext,proto3" json:"pre_context,omitempty"`
+	PostContext     []string               `protobuf:"bytes,9,rep,name=post_context,json=postContext,proto3" json:"post_context,omitempty"`
+	InApp           *bool                  `protobuf:"varint,10,opt,name=in_app,json=inApp,proto3,oneof" json:"in_app,omitempty"`
+	Vars            *structpb.Struct       `protobuf:"bytes,11,opt,name=vars,proto3" json:"vars,omitempty"`
+	Package         *string                `protobuf:"bytes,12,opt,name=package,proto3,oneof" json:"package,omitempty"`
+	Platform        *string                `protobuf:"bytes,13,opt,name=platform,proto3,oneof" json:"platform,omitempty"`
+	InstructionAddr *string                `protobuf:"bytes,14,opt,name=instruction_addr,json=instructionAddr,proto3,oneof" json:"instruction_addr,omitempty"`
+	Symbol          *string                `protobuf:"bytes,15,opt,name=symbol,proto3,oneof" json:"symbol,omitempty"`
+	SymbolAddr      *string                `protobuf:"bytes,16,opt,name=symbol_addr,json=symbolAddr,proto3,oneof" json:"symbol_addr,omitempty"`
+	ImageAddr       *string                `protobuf:"bytes,17,opt,name=image_addr,json=imageAddr,proto3,oneof" json:"image_addr,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Frame) Reset() {
+	*x = Frame{}
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Frame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Frame) ProtoMessage() {}
+
+func (x *Frame) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Frame.ProtoReflect.Descriptor instead.
+func (*Frame) Descriptor() ([]byte, []int) {
+	return file_internal_ingestpb_ingest_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Frame) GetFilename() string {
+	if x != nil && x.Filename != nil {
+		return *x.Filename
+	}
+	return ""
+}
+
+func (x *Frame) GetFunction() string {
+	if x != nil && x.Function != nil {
+		return *x.Function
+	}
+	return ""
+}
+
+func (x *Frame) GetModule() string {
+	if x != nil && x.Module != nil {
+		return *x.Module
+	}
+	return ""
+}
+
+func (x *Frame) GetLineno() int32 {
+	if x != nil && x.Lineno != nil {
+		return *x.Lineno
+	}
+	return 0
+}
+
+func (x *Frame) GetColno() int32 {
+	if x != nil && x.Colno != nil {
+		return *x.Colno
+	}
+	return 0
+}
+
+func (x *Frame) GetAbsPath() string {
+	if x != nil && x.AbsPath != nil {
+		return *x.AbsPath
+	}
+	return ""
+}
+
+func (x *Frame) GetContextLine() string {
+	if x != nil && x.ContextLine != nil {
+		return *x.ContextLine
+	}
+	return ""
+}
+
+func (x *Frame) GetPreContext() []string {
+	if x != nil {
+		return x.PreContext
+	}
+	return nil
+}
+
+func (x *Frame) GetPostContext() []string {
+	if x != nil {
+		return x.PostContext
+	}
+	return nil
+}
+
+func (x *Frame) GetInApp() bool {
+	if x != nil && x.InApp != nil {
+		return *x.InApp
+	}
+	return false
+}
+
+func (x *Frame) GetVars() *structpb.Struct {
+	if x != nil {
+		return x.Vars
+	}
+	return nil
+}
+
+func (x *Frame) GetPackage() string {
+	if x != nil && x.Package != nil {
+		return *x.Package
+	}
+	return ""
+}
+
+func (x *Frame) GetPlatform() string {
+	if x != nil && x.Platform != nil {
+		return *x.Platform
+	}
+	return ""
+}
+
+func (x *Frame) GetInstructionAddr() string {
+	if x != nil && x.InstructionAddr != nil {
+		return *x.InstructionAddr
+	}
+	return ""
+}
+
+func (x *Frame) GetSymbol() string {
+	if x != nil && x.Symbol != nil {
+		return *x.Symbol
+	}
+	return ""
+}
+
+func (x *Frame) GetSymbolAddr() string {
+	if x != nil && x.SymbolAddr != nil {
+		return *x.SymbolAddr
+	}
+	return ""
+}
+
+func (x *Frame) GetImageAddr() string {
+	if x != nil && x.ImageAddr != nil {
+		return *x.ImageAddr
+	}
+	return ""
+}
+
+type User struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *string                `protobuf:"bytes,1,opt,name=id,proto3,oneof" json:"id,omitempty"`
+	Email         *string                `protobuf:"bytes,2,opt,name=email,proto3,oneof" json:"email,omitempty"`
+	Username      *string                `protobuf:"bytes,3,opt,name=username,proto3,oneof" json:"username,omitempty"`
+	IpAddress     *string                `protobuf:"bytes,4,opt,name=ip_address,json=ipAddress,proto3,oneof" json:"ip_address,omitempty"`
+	Name          *string                `protobuf:"bytes,5,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	Data          *structpb.Struct       `protobuf:"bytes,6,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_internal_ingestpb_ingest_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *User) GetId() string {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil && x.Email != nil {
+		return *x.Email
+	}
+	return ""
+}
+
+func (x *User) GetUsername() string {
+	if x != nil && x.Username != nil {
+		return *x.Username
+	}
+	return ""
+}
+
+func (x *User) GetIpAddress() string {
+	if x != nil && x.IpAddress != nil {
+		return *x.IpAddress
+	}
+	return ""
+}
+
+func (x *User) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *User) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type Request struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           *string                `protobuf:"bytes,1,opt,name=url,proto3,oneof" json:"url,omitempty"`
+	Method        *string                `protobuf:"bytes,2,opt,name=method,proto3,oneof" json:"method,omitempty"`
+	Data          *structpb.Value        `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	QueryString   *string                `protobuf:"bytes,4,opt,name=query_string,json=queryString,proto3,oneof" json:"query_string,omitempty"`
+	Headers       map[string]string      `protobuf:"bytes,5,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Env           map[string]string      `protobuf:"bytes,6,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Cookies       map[string]string      `protobuf:"bytes,7,rep,name=cookies,proto3" json:"cookies,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Request) Reset() {
+	*x = Request{}
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Request) ProtoMessage() {}
+
+func (x *Request) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Request.ProtoReflect.Descriptor instead.
+func (*Request) Descriptor() ([]byte, []int) {
+	return file_internal_ingestpb_ingest_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Request) GetUrl() string {
+	if x != nil && x.Url != nil {
+		return *x.Url
+	}
+	return ""
+}
+
+func (x *Request) GetMethod() string {
+	if x != nil && x.Method != nil {
+		return *x.Method
+	}
+	return ""
+}
+
+func (x *Request) GetData() *structpb.Value {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Request) GetQueryString() string {
+	if x != nil && x.QueryString != nil {
+		return *x.QueryString
+	}
+	return ""
+}
+
+func (x *Request) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *Request) GetEnv() map[string]string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *Request) GetCookies() map[string]string {
+	if x != nil {
+		return x.Cookies
+	}
+	return nil
+}
+
+type Breadcrumb struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          *string                `protobuf:"bytes,1,opt,name=type,proto3,oneof" json:"type,omitempty"`
+	Category      *string                `protobuf:"bytes,2,opt,name=category,proto3,oneof" json:"category,omitempty"`
+	Message       *string                `protobuf:"bytes,3,opt,name=message,proto3,oneof" json:"message,omitempty"`
+	Data          *structpb.Struct       `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	Level         *string                `protobuf:"bytes,5,opt,name=level,proto3,oneof" json:"level,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Breadcrumb) Reset() {
+	*x = Breadcrumb{}
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Breadcrumb) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Breadcrumb) ProtoMessage() {}
+
+func (x *Breadcrumb) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Breadcrumb.ProtoReflect.Descriptor instead.
+func (*Breadcrumb) Descriptor() ([]byte, []int) {
+	return file_internal_ingestpb_ingest_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Breadcrumb) GetType() string {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return ""
+}
+
+func (x *Breadcrumb) GetCategory() string {
+	if x != nil && x.Category != nil {
+		return *x.Category
+	}
+	return ""
+}
+
+func (x *Breadcrumb) GetMessage() string {
+	if x != nil && x.Message != nil {
+		return *x.Message
+	}
+	return ""
+}
+
+func (x *Breadcrumb) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Breadcrumb) GetLevel() string {
+	if x != nil && x.Level != nil {
+		return *x.Level
+	}
+	return ""
+}
+
+func (x *Breadcrumb) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type SDK struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SDK) Reset() {
+	*x = SDK{}
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SDK) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SDK) ProtoMessage() {}
+
+func (x *SDK) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_ingestpb_ingest_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SDK.ProtoReflect.Descriptor instead.
+func (*SDK) Descriptor() ([]byte, []int) {
+	return file_internal_ingestpb_ingest_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SDK) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SDK) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+var File_internal_ingestpb_ingest_proto protoreflect.FileDescriptor
+
+const file_internal_ingestpb_ingest_proto_rawDesc = "" +
+	"\n" +
+	"\x1einternal/ingestpb/ingest.proto\x12\x14minisentry.ingest.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x7f\n" +
+	"\x10SendEventRequest\x126\n" +
+	"\x05event\x18\x01 \x01(\v2 .minisentry.ingest.v1.ErrorEventR\x05event\x123\n" +
+	"\asent_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\"\x96\x01\n" +
+	"\x11SendEventResponse\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x128\n" +
+	"\x06errors\x18\x04 \x03(\v2 .minisentry.ingest.v1.FieldErrorR\x06errors\":\n" +
+	"\n" +
+	"FieldError\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x95\t\n" +
+	"\n" +
+	"ErrorEvent\x12\x1e\n" +
+	"\bevent_id\x18\x01 \x01(\tH\x00R\aeventId\x88\x01\x01\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x19\n" +
+	"\x05level\x18\x03 \x01(\tH\x01R\x05level\x88\x01\x01\x12\x1b\n" +
+	"\x06logger\x18\x04 \x01(\tH\x02R\x06logger\x88\x01\x01\x12\x1f\n" +
+	"\bplatform\x18\x05 \x01(\tH\x03R\bplatform\x88\x01\x01\x12\x1d\n" +
+	"\arelease\x18\x06 \x01(\tH\x04R\arelease\x88\x01\x01\x12%\n" +
+	"\venvironment\x18\a \x01(\tH\x05R\venvironment\x88\x01\x01\x12$\n" +
+	"\vserver_name\x18\b \x01(\tH\x06R\n" +
+	"serverName\x88\x01\x01\x12%\n" +
+	"\vtransaction\x18\t \x01(\tH\aR\vtransaction\x88\x01\x01\x127\n" +
+	"\amessage\x18\n" +
+	" \x01(\v2\x1d.minisentry.ingest.v1.MessageR\amessage\x12?\n" +
+	"\n" +
+	"exceptions\x18\v \x03(\v2\x1f.minisentry.ingest.v1.ExceptionR\n" +
+	"exceptions\x12.\n" +
+	"\x04user\x18\f \x01(\v2\x1a.minisentry.ingest.v1.UserR\x04user\x127\n" +
+	"\arequest\x18\r \x01(\v2\x1d.minisentry.ingest.v1.RequestR\arequest\x12>\n" +
+	"\x04tags\x18\x0e \x03(\v2*.minisentry.ingest.v1.ErrorEvent.TagsEntryR\x04tags\x12-\n" +
+	"\x05extra\x18\x0f \x01(\v2\x17.google.protobuf.StructR\x05extra\x12B\n" +
+	"\vbreadcrumbs\x18\x10 \x03(\v2 .minisentry.ingest.v1.BreadcrumbR\vbreadcrumbs\x123\n" +
+	"\bcontexts\x18\x11 \x01(\v2\x17.google.protobuf.StructR\bcontexts\x12 \n" +
+	"\vfingerprint\x18\x12 \x03(\tR\vfingerprint\x12G\n" +
+	"\amodules\x18\x13 \x03(\v2-.minisentry.ingest.v1.ErrorEvent.ModulesEntryR\amodules\x12+\n" +
+	"\x03sdk\x18\x14 \x01(\v2\x19.minisentry.ingest.v1.SDKR\x03sdk\x1a7\n" +
+	"\tTagsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a:\n" +
+	"\fModulesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\v\n" +
+	"\t_event_idB\b\n" +
+	"\x06_levelB\t\n" +
+	"\a_loggerB\v\n" +
+	"\t_platformB\n" +
+	"\n" +
+	"\b_releaseB\x0e\n" +
+	"\f_environmentB\x0e\n" +
+	"\f_server_nameB\x0e\n" +
+	"\f_transaction\"\x84\x01\n" +
+	"\aMessage\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12.\n" +
+	"\x06params\x18\x02 \x03(\v2\x16.google.protobuf.ValueR\x06params\x12!\n" +
+	"\tformatted\x18\x03 \x01(\tH\x00R\tformatted\x88\x01\x01B\f\n" +
+	"\n" +
+	"_formatted\"\xee\x01\n" +
+	"\tException\x12\x17\n" +
+	"\x04type\x18\x01 \x01(\tH\x00R\x04type\x88\x01\x01\x12\x19\n" +
+	"\x05value\x18\x02 \x01(\tH\x01R\x05value\x88\x01\x01\x12\x1b\n" +
+	"\x06module\x18\x03 \x01(\tH\x02R\x06module\x88\x01\x01\x12=\n" +
+	"\tmechanism\x18\x04 \x01(\v2\x1f.minisentry.ingest.v1.MechanismR\tmechanism\x123\n" +
+	"\x06frames\x18\x05 \x03(\v2\x1b.minisentry.ingest.v1.FrameR\x06framesB\a\n" +
+	"\x05_typeB\b\n" +
+	"\x06_valueB\t\n" +
+	"\a_module\"\xde\x01\n" +
+	"\tMechanism\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12%\n" +
+	"\vdescription\x18\x02 \x01(\tH\x00R\vdescription\x88\x01\x01\x12 \n" +
+	"\thelp_link\x18\x03 \x01(\tH\x01R\bhelpLink\x88\x01\x01\x12\x1d\n" +
+	"\ahandled\x18\x04 \x01(\bH\x02R\ahandled\x88\x01\x01\x12+\n" +
+	"\x04data\x18\x05 \x01(\v2\x17.google.protobuf.StructR\x04dataB\x0e\n" +
+	"\f_descriptionB\f\n" +
+	"\n" +
+	"_help_linkB\n" +
+	"\n" +
+	"\b_handled\"\x85\x06\n" +
+	"\x05Frame\x12\x1f\n" +
+	"\bfilename\x18\x01 \x01(\tH\x00R\bfilename\x88\x01\x01\x12\x1f\n" +
+	"\bfunction\x18\x02 \x01(\tH\x01R\bfunction\x88\x01\x01\x12\x1b\n" +
+	"\x06module\x18\x03 \x01(\tH\x02R\x06module\x88\x01\x01\x12\x1b\n" +
+	"\x06lineno\x18\x04 \x01(\x05H\x03R\x06lineno\x88\x01\x01\x12\x19\n" +
+	"\x05colno\x18\x05 \x01(\x05H\x04R\x05colno\x88\x01\x01\x12\x1e\n" +
+	"\babs_path\x18\x06 \x01(\tH\x05R\aabsPath\x88\x01\x01\x12&\n" +
+	"\fcontext_line\x18\a \x01(\tH\x06R\vcontextLine\x88\x01\x01\x12\x1f\n" +
+	"\vpre_context\x18\b \x03(\tR\n" +
+	"preContext\x12!\n" +
+	"\fpost_context\x18\t \x03(\tR\vpostContext\x12\x1a\n" +
+	"\x06in_app\x18\n" +
+	" \x01(\bH\aR\x05inApp\x88\x01\x01\x12+\n" +
+	"\x04vars\x18\v \x01(\v2\x17.google.protobuf.StructR\x04vars\x12\x1d\n" +
+	"\apackage\x18\f \x01(\tH\bR\apackage\x88\x01\x01\x12\x1f\n" +
+	"\bplatform\x18\r \x01(\tH\tR\bplatform\x88\x01\x01\x12.\n" +
+	"\x10instruction_addr\x18\x0e \x01(\tH\n" +
+	"R\x0finstructionAddr\x88\x01\x01\x12\x1b\n" +
+	"\x06symbol\x18\x0f \x01(\tH\vR\x06symbol\x88\x01\x01\x12$\n" +
+	"\vsymbol_addr\x18\x10 \x01(\tH\fR\n" +
+	"symbolAddr\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"image_addr\x18\x11 \x01(\tH\rR\timageAddr\x88\x01\x01B\v\n" +
+	"\t_filenameB\v\n" +
+	"\t_functionB\t\n" +
+	"\a_moduleB\t\n" +
+	"\a_linenoB\b\n" +
+	"\x06_colnoB\v\n" +
+	"\t_abs_pathB\x0f\n" +
+	"\r_context_lineB\t\n" +
+	"\a_in_appB\n" +
+	"\n" +
+	"\b_packageB\v\n" +
+	"\t_platformB\x13\n" +
+	"\x11_instruction_addrB\t\n" +
+	"\a_symbolB\x0e\n" +
+	"\f_symbol_addrB\r\n" +
+	"\v_image_addr\"\xf7\x01\n" +
+	"\x04User\x12\x13\n" +
+	"\x02id\x18\x01 \x01(\tH\x00R\x02id\x88\x01\x01\x12\x19\n" +
+	"\x05email\x18\x02 \x01(\tH\x01R\x05email\x88\x01\x01\x12\x1f\n" +
+	"\busername\x18\x03 \x01(\tH\x02R\busername\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"ip_address\x18\x04 \x01(\tH\x03R\tipAddress\x88\x01\x01\x12\x17\n" +
+	"\x04name\x18\x05 \x01(\tH\x04R\x04name\x88\x01\x01\x12+\n" +
+	"\x04data\x18\x06 \x01(\v2\x17.google.protobuf.StructR\x04dataB\x05\n" +
+	"\x03_idB\b\n" +
+	"\x06_emailB\v\n" +
+	"\t_usernameB\r\n" +
+	"\v_ip_addressB\a\n" +
+	"\x05_name\"\xab\x04\n" +
+	"\aRequest\x12\x15\n" +
+	"\x03url\x18\x01 \x01(\tH\x00R\x03url\x88\x01\x01\x12\x1b\n" +
+	"\x06method\x18\x02 \x01(\tH\x01R\x06method\x88\x01\x01\x12*\n" +
+	"\x04data\x18\x03 \x01(\v2\x16.google.protobuf.ValueR\x04data\x12&\n" +
+	"\fquery_string\x18\x04 \x01(\tH\x02R\vqueryString\x88\x01\x01\x12D\n" +
+	"\aheaders\x18\x05 \x03(\v2*.minisentry.ingest.v1.Request.HeadersEntryR\aheaders\x128\n" +
+	"\x03env\x18\x06 \x03(\v2&.minisentry.ingest.v1.Request.EnvEntryR\x03env\x12D\n" +
+	"\acookies\x18\a \x03(\v2*.minisentry.ingest.v1.Request.CookiesEntryR\acookies\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a6\n" +
+	"\bEnvEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a:\n" +
+	"\fCookiesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x06\n" +
+	"\x04_urlB\t\n" +
+	"\a_methodB\x0f\n" +
+	"\r_query_string\"\x93\x02\n" +
+	"\n" +
+	"Breadcrumb\x12\x17\n" +
+	"\x04type\x18\x01 \x01(\tH\x00R\x04type\x88\x01\x01\x12\x1f\n" +
+	"\bcategory\x18\x02 \x01(\tH\x01R\bcategory\x88\x01\x01\x12\x1d\n" +
+	"\amessage\x18\x03 \x01(\tH\x02R\amessage\x88\x01\x01\x12+\n" +
+	"\x04data\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x04data\x12\x19\n" +
+	"\x05level\x18\x05 \x01(\tH\x03R\x05level\x88\x01\x01\x128\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestampB\a\n" +
+	"\x05_typeB\v\n" +
+	"\t_categoryB\n" +
+	"\n" +
+	"\b_messageB\b\n" +
+	"\x06_level\"3\n" +
+	"\x03SDK\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion2\xd0\x01\n" +
+	"\vEventIngest\x12\\\n" +
+	"\tSendEvent\x12&.minisentry.ingest.v1.SendEventRequest\x1a'.minisentry.ingest.v1.SendEventResponse\x12c\n" +
+	"\fStreamEvents\x12&.minisentry.ingest.v1.SendEventRequest\x1a'.minisentry.ingest.v1.SendEventResponse(\x010\x01B\x1eZ\x1cminisentry/internal/ingestpbb\x06proto3"
+
+var (
+	file_internal_ingestpb_ingest_proto_rawDescOnce sync.Once
+	file_internal_ingestpb_ingest_proto_rawDescData []byte
+)
+
+func file_internal_ingestpb_ingest_proto_rawDescGZIP() []byte {
+	file_internal_ingestpb_ingest_proto_rawDescOnce.Do(func() {
+		file_internal_ingestpb_ingest_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_internal_ingestpb_ingest_proto_rawDesc), len(file_internal_ingestpb_ingest_proto_rawDesc)))
+	})
+	return file_internal_ingestpb_ingest_proto_rawDescData
+}
+
+var file_internal_ingestpb_ingest_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_internal_ingestpb_ingest_proto_goTypes = []any{
+	(*SendEventRequest)(nil),      // 0: minisentry.ingest.v1.SendEventRequest
+	(*SendEventResponse)(nil),     // 1: minisentry.ingest.v1.SendEventResponse
+	(*FieldError)(nil),            // 2: minisentry.ingest.v1.FieldError
+	(*ErrorEvent)(nil),            // 3: minisentry.ingest.v1.ErrorEvent
+	(*Message)(nil),               // 4: minisentry.ingest.v1.Message
+	(*Exception)(nil),             // 5: minisentry.ingest.v1.Exception
+	(*Mechanism)(nil),             // 6: minisentry.ingest.v1.Mechanism
+	(*Frame)(nil),                 // 7: minisentry.ingest.v1.Frame
+	(*User)(nil),                  // 8: minisentry.ingest.v1.User
+	(*Request)(nil),               // 9: minisentry.ingest.v1.Request
+	(*Breadcrumb)(nil),            // 10: minisentry.ingest.v1.Breadcrumb
+	(*SDK)(nil),                   // 11: minisentry.ingest.v1.SDK
+	nil,                           // 12: minisentry.ingest.v1.ErrorEvent.TagsEntry
+	nil,                           // 13: minisentry.ingest.v1.ErrorEvent.ModulesEntry
+	nil,                           // 14: minisentry.ingest.v1.Request.HeadersEntry
+	nil,                           // 15: minisentry.ingest.v1.Request.EnvEntry
+	nil,                           // 16: minisentry.ingest.v1.Request.CookiesEntry
+	(*timestamppb.Timestamp)(nil), // 17: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),       // 18: google.protobuf.Struct
+	(*structpb.Value)(nil),        // 19: google.protobuf.Value
+}
+var file_internal_ingestpb_ingest_proto_depIdxs = []int32{
+	3,  // 0: minisentry.ingest.v1.SendEventRequest.event:type_name -> minisentry.ingest.v1.ErrorEvent
+	17, // 1: minisentry.ingest.v1.SendEventRequest.sent_at:type_name -> google.protobuf.Timestamp
+	2,  // 2: minisentry.ingest.v1.SendEventResponse.errors:type_name -> minisentry.ingest.v1.FieldError
+	17, // 3: minisentry.ingest.v1.ErrorEvent.timestamp:type_name -> google.protobuf.Timestamp
+	4,  // 4: minisentry.ingest.v1.ErrorEvent.message:type_name -> minisentry.ingest.v1.Message
+	5,  // 5: minisentry.ingest.v1.ErrorEvent.exceptions:type_name -> minisentry.ingest.v1.Exception
+	8,  // 6: minisentry.ingest.v1.ErrorEvent.user:type_name -> minisentry.ingest.v1.User
+	9,  // 7: minisentry.ingest.v1.ErrorEvent.request:type_name -> minisentry.ingest.v1.Request
+	12, // 8: minisentry.ingest.v1.ErrorEvent.tags:type_name -> minisentry.ingest.v1.ErrorEvent.TagsEntry
+	18, // 9: minisentry.ingest.v1.ErrorEvent.extra:type_name -> google.protobuf.Struct
+	10, // 10: minisentry.ingest.v1.ErrorEvent.breadcrumbs:type_name -> minisentry.ingest.v1.Breadcrumb
+	18, // 11: minisentry.ingest.v1.ErrorEvent.contexts:type_name -> google.protobuf.Struct
+	13, // 12: minisentry.ingest.v1.ErrorEvent.modules:type_name -> minisentry.ingest.v1.ErrorEvent.ModulesEntry
+	11, // 13: minisentry.ingest.v1.ErrorEvent.sdk:type_name -> minisentry.ingest.v1.SDK
+	19, // 14: minisentry.ingest.v1.Message.params:type_name -> google.protobuf.Value
+	6,  // 15: minisentry.ingest.v1.Exception.mechanism:type_name -> minisentry.ingest.v1.Mechanism
+	7,  // 16: minisentry.ingest.v1.Exception.frames:type_name -> minisentry.ingest.v1.Frame
+	18, // 17: minisentry.ingest.v1.Mechanism.data:type_name -> google.protobuf.Struct
+	18, // 18: minisentry.ingest.v1.Frame.vars:type_name -> google.protobuf.Struct
+	18, // 19: minisentry.ingest.v1.User.data:type_name -> google.protobuf.Struct
+	19, // 20: minisentry.ingest.v1.Request.data:type_name -> google.protobuf.Value
+	14, // 21: minisentry.ingest.v1.Request.headers:type_name -> minisentry.ingest.v1.Request.HeadersEntry
+	15, // 22: minisentry.ingest.v1.Request.env:type_name -> minisentry.ingest.v1.Request.EnvEntry
+	16, // 23: minisentry.ingest.v1.Request.cookies:type_name -> minisentry.ingest.v1.Request.CookiesEntry
+	18, // 24: minisentry.ingest.v1.Breadcrumb.data:type_name -> google.protobuf.Struct
+	17, // 25: minisentry.ingest.v1.Breadcrumb.timestamp:type_name -> google.protobuf.Timestamp
+	0,  // 26: minisentry.ingest.v1.EventIngest.SendEvent:input_type -> minisentry.ingest.v1.SendEventRequest
+	0,  // 27: minisentry.ingest.v1.EventIngest.StreamEvents:input_type -> minisentry.ingest.v1.SendEventRequest
+	1,  // 28: minisentry.ingest.v1.EventIngest.SendEvent:output_type -> minisentry.ingest.v1.SendEventResponse
+	1,  // 29: minisentry.ingest.v1.EventIngest.StreamEvents:output_type -> minisentry.ingest.v1.SendEventResponse
+	28, // [28:30] is the sub-list for method output_type
+	26, // [26:28] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
+}
+
+func init() { file_internal_ingestpb_ingest_proto_init() }
+func file_internal_ingestpb_ingest_proto_init() {
+	if File_internal_ingestpb_ingest_proto != nil {
+		return
+	}
+	file_internal_ingestpb_ingest_proto_msgTypes[3].OneofWrappers = []any{}
+	file_internal_ingestpb_ingest_proto_msgTypes[4].OneofWrappers = []any{}
+	file_internal_ingestpb_ingest_proto_msgTypes[5].OneofWrappers = []any{}
+	file_internal_ingestpb_ingest_proto_msgTypes[6].OneofWrappers = []any{}
+	file_internal_ingestpb_ingest_proto_msgTypes[7].OneofWrappers = []any{}
+	file_internal_ingestpb_ingest_proto_msgTypes[8].OneofWrappers = []any{}
+	file_internal_ingestpb_ingest_proto_msgTypes[9].OneofWrappers = []any{}
+	file_internal_ingestpb_ingest_proto_msgTypes[10].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_internal_ingestpb_ingest_proto_rawDesc), len(file_internal_ingestpb_ingest_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_internal_ingestpb_ingest_proto_goTypes,
+		DependencyIndexes: file_internal_ingestpb_ingest_proto_depIdxs,
+		MessageInfos:      file_internal_ingestpb_ingest_proto_msgTypes,
+	}.Build()
+	File_internal_ingestpb_ingest_proto = out.File
+	file_internal_ingestpb_ingest_proto_goTypes = nil
+	file_internal_ingestpb_ingest_proto_depIdxs = nil
+}
@@ -0,0 +1,169 @@
+// gRPC ingestion of error events for internal producers. Events go through the same
+// pipeline as the store endpoint; the fields mirror the Sentry event payload.
+//
+// Regenerate the Go code with `make proto`.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: internal/ingestpb/ingest.proto
+
+package ingestpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	EventIngest_SendEvent_FullMethodName    = "/minisentry.ingest.v1.EventIngest/SendEvent"
+	EventIngest_StreamEvents_FullMethodName = "/minisentry.ingest.v1.EventIngest/StreamEvents"
+)
+
+// EventIngestClient is the client API for EventIngest service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// EventIngest accepts error events authenticated by a project DSN, sent in the
+// "authorization" metadata like the Authorization header of the HTTP endpoints.
+type EventIngestClient interface {
+	// SendEvent ingests one error event
+	SendEvent(ctx context.Context, in *SendEventRequest, opts ...grpc.CallOption) (*SendEventResponse, error)
+	// StreamEvents ingests a stream of error events, answering each in order
+	StreamEvents(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SendEventRequest, SendEventResponse], error)
+}
+
+type eventIngestClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEventIngestClient(cc grpc.ClientConnInterface) EventIngestClient {
+	return &eventIngestClient{cc}
+}
+
+func (c *eventIngestClient) SendEvent(ctx context.Context, in *SendEventRequest, opts ...grpc.CallOption) (*SendEventResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendEventResponse)
+	err := c.cc.Invoke(ctx, EventIngest_SendEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventIngestClient) StreamEvents(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SendEventRequest, SendEventResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EventIngest_ServiceDesc.Streams[0], EventIngest_StreamEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SendEventRequest, SendEventResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EventIngest_StreamEventsClient = grpc.BidiStreamingClient[SendEventRequest, SendEventResponse]
+
+// EventIngestServer is the server API for EventIngest service.
+// All implementations must embed UnimplementedEventIngestServer
+// for forward compatibility.
+//
+// EventIngest accepts error events authenticated by a project DSN, sent in the
+// "authorization" metadata like the Authorization header of the HTTP endpoints.
+type EventIngestServer interface {
+	// SendEvent ingests one error event
+	SendEvent(context.Context, *SendEventRequest) (*SendEventResponse, error)
+	// StreamEvents ingests a stream of error events, answering each in order
+	StreamEvents(grpc.BidiStreamingServer[SendEventRequest, SendEventResponse]) error
+	mustEmbedUnimplementedEventIngestServer()
+}
+
+// UnimplementedEventIngestServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedEventIngestServer struct{}
+
+func (UnimplementedEventIngestServer) SendEvent(context.Context, *SendEventRequest) (*SendEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendEvent not implemented")
+}
+func (UnimplementedEventIngestServer) StreamEvents(grpc.BidiStreamingServer[SendEventRequest, SendEventResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedEventIngestServer) mustEmbedUnimplementedEventIngestServer() {}
+func (UnimplementedEventIngestServer) testEmbeddedByValue()                     {}
+
+// UnsafeEventIngestServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EventIngestServer will
+// result in compilation errors.
+type UnsafeEventIngestServer interface {
+	mustEmbedUnimplementedEventIngestServer()
+}
+
+func RegisterEventIngestServer(s grpc.ServiceRegistrar, srv EventIngestServer) {
+	// If the following call pancis, it indicates UnimplementedEventIngestServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&EventIngest_ServiceDesc, srv)
+}
+
+func _EventIngest_SendEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventIngestServer).SendEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventIngest_SendEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventIngestServer).SendEvent(ctx, req.(*SendEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventIngest_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EventIngestServer).StreamEvents(&grpc.GenericServerStream[SendEventRequest, SendEventResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EventIngest_StreamEventsServer = grpc.BidiStreamingServer[SendEventRequest, SendEventResponse]
+
+// EventIngest_ServiceDesc is the grpc.ServiceDesc for EventIngest service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EventIngest_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "minisentry.ingest.v1.EventIngest",
+	HandlerType: (*EventIngestServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SendEvent",
+			Handler:    _EventIngest_SendEvent_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _EventIngest_StreamEvents_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "internal/ingestpb/ingest.proto",
+}
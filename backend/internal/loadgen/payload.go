@@ -0,0 +1,267 @@
+package loadgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"minisentry/internal/dto"
+
+	"github.com/google/uuid"
+)
+
+// PayloadKind selects the ingestion endpoint a payload is sent to
+type PayloadKind string
+
+const (
+	PayloadStore    PayloadKind = "store"
+	PayloadEnvelope PayloadKind = "envelope"
+)
+
+// Payload is a recorded SDK request body replayed by the generator
+type Payload struct {
+	Name string
+	Kind PayloadKind
+	Body []byte
+}
+
+// LoadPayloads reads recorded payloads from a directory.
+// Files ending in .json are sent to the store endpoint, files ending in .envelope to the envelope endpoint.
+func LoadPayloads(dir string) ([]Payload, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read payload directory: %w", err)
+	}
+
+	var payloads []Payload
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		var kind PayloadKind
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json":
+			kind = PayloadStore
+		case ".envelope":
+			kind = PayloadEnvelope
+		default:
+			continue
+		}
+
+		body, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read payload %s: %w", entry.Name(), err)
+		}
+
+		payloads = append(payloads, Payload{Name: entry.Name(), Kind: kind, Body: body})
+	}
+
+	if len(payloads) == 0 {
+		return nil, fmt.Errorf("no .json or .envelope payloads found in %s", dir)
+	}
+
+	sort.Slice(payloads, func(i, j int) bool {
+		return payloads[i].Name < payloads[j].Name
+	})
+
+	return payloads, nil
+}
+
+// DefaultPayloads returns built-in sample events used when no recorded payloads are given
+func DefaultPayloads() ([]Payload, error) {
+	events := map[string]dto.ErrorEventRequest{
+		"javascript-typeerror": {
+			Level:       stringPtr("error"),
+			Platform:    stringPtr("javascript"),
+			Environment: stringPtr("development"),
+			Message: &dto.MessageData{
+				Message: "Uncaught TypeError: Cannot read property 'foo' of undefined",
+			},
+			Exception: &dto.ExceptionData{
+				Values: []dto.ExceptionValue{
+					{
+						Type:  stringPtr("TypeError"),
+						Value: stringPtr("Cannot read property 'foo' of undefined"),
+						Stacktrace: &dto.StacktraceData{
+							Frames: []dto.StackFrame{
+								{
+									Filename:    stringPtr("http://localhost:3000/static/js/main.js"),
+									Function:    stringPtr("handleClick"),
+									Lineno:      intPtr(42),
+									Colno:       intPtr(15),
+									InApp:       boolPtr(true),
+									ContextLine: stringPtr("    obj.foo.bar()"),
+								},
+								{
+									Filename:    stringPtr("http://localhost:3000/static/js/main.js"),
+									Function:    stringPtr("onClick"),
+									Lineno:      intPtr(15),
+									Colno:       intPtr(8),
+									InApp:       boolPtr(true),
+									ContextLine: stringPtr("    handleClick(event)"),
+								},
+							},
+						},
+					},
+				},
+			},
+			User: &dto.UserContext{
+				ID:    stringPtr("12345"),
+				Email: stringPtr("test@example.com"),
+			},
+			Tags: map[string]string{
+				"browser": "Chrome",
+				"version": "91.0.4472.124",
+			},
+			Extra: map[string]interface{}{
+				"component": "UserProfile",
+				"action":    "click",
+			},
+			Breadcrumbs: []dto.BreadcrumbData{
+				{
+					Type:     stringPtr("navigation"),
+					Category: stringPtr("ui.click"),
+					Message:  stringPtr("User clicked profile button"),
+					Level:    stringPtr("info"),
+				},
+			},
+		},
+		"javascript-grouped": {
+			Level:    stringPtr("error"),
+			Platform: stringPtr("javascript"),
+			Exception: &dto.ExceptionData{
+				Values: []dto.ExceptionValue{
+					{
+						Type:  stringPtr("TypeError"),
+						Value: stringPtr("Cannot read property 'foo' of undefined"),
+						Stacktrace: &dto.StacktraceData{
+							Frames: []dto.StackFrame{
+								{
+									Filename: stringPtr("main.js"),
+									Function: stringPtr("handleClick"),
+									Lineno:   intPtr(42),
+									InApp:    boolPtr(true),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"python-message": {
+			Level:    stringPtr("warning"),
+			Platform: stringPtr("python"),
+			Message: &dto.MessageData{
+				Message: "Payment provider responded slowly",
+			},
+		},
+	}
+
+	names := make([]string, 0, len(events))
+	for name := range events {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	payloads := make([]Payload, 0, len(names))
+	for _, name := range names {
+		body, err := json.Marshal(events[name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal sample %s: %w", name, err)
+		}
+		payloads = append(payloads, Payload{Name: name, Kind: PayloadStore, Body: body})
+	}
+
+	return payloads, nil
+}
+
+// prepareBody returns the body to send for a payload.
+// Event IDs and timestamps are refreshed so replays are not rejected as duplicates or stale events.
+func prepareBody(payload Payload) ([]byte, error) {
+	switch payload.Kind {
+	case PayloadStore:
+		return refreshEvent(payload.Body, newEventID())
+	case PayloadEnvelope:
+		return refreshEnvelope(payload.Body)
+	default:
+		return nil, fmt.Errorf("unknown payload kind %q", payload.Kind)
+	}
+}
+
+func refreshEvent(body []byte, eventID string) ([]byte, error) {
+	var event map[string]json.RawMessage
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("invalid event payload: %w", err)
+	}
+
+	event["event_id"], _ = json.Marshal(eventID)
+	event["timestamp"], _ = json.Marshal(time.Now().UTC())
+
+	return json.Marshal(event)
+}
+
+func refreshEnvelope(body []byte) ([]byte, error) {
+	envelope, err := dto.ParseEnvelope(body)
+	if err != nil {
+		return nil, err
+	}
+
+	eventID := newEventID()
+	envelope.Header.EventID = &eventID
+	sentAt := time.Now().UTC()
+	envelope.Header.SentAt = &sentAt
+
+	var buf bytes.Buffer
+	header, err := json.Marshal(envelope.Header)
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(header)
+	buf.WriteByte('\n')
+
+	for _, item := range envelope.Items {
+		itemPayload := item.Payload
+		if item.Header.Type == "event" {
+			if itemPayload, err = refreshEvent(itemPayload, eventID); err != nil {
+				return nil, err
+			}
+		}
+
+		itemHeader := item.Header
+		length := len(itemPayload)
+		itemHeader.Length = &length
+
+		encoded, err := json.Marshal(itemHeader)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(encoded)
+		buf.WriteByte('\n')
+		buf.Write(itemPayload)
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), nil
+}
+
+func newEventID() string {
+	return strings.ReplaceAll(uuid.New().String(), "-", "")
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
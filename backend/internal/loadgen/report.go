@@ -0,0 +1,133 @@
+package loadgen
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// result is the outcome of a single request
+type result struct {
+	payload string
+	status  int
+	latency time.Duration
+	err     error
+}
+
+// Report aggregates acceptance and latency across a run
+type Report struct {
+	Sent     int
+	Accepted int
+	Rejected int
+	Failed   int
+	Elapsed  time.Duration
+	// StatusCounts maps HTTP status codes to the number of responses received
+	StatusCounts map[int]int
+	// Errors maps transport error messages to their number of occurrences
+	Errors map[string]int
+	// RejectedPayloads maps payload names to the number of non-2xx responses they received
+	RejectedPayloads map[string]int
+
+	mu        sync.Mutex
+	latencies []time.Duration
+}
+
+func newReport() *Report {
+	return &Report{
+		StatusCounts:     make(map[int]int),
+		Errors:           make(map[string]int),
+		RejectedPayloads: make(map[string]int),
+	}
+}
+
+func (r *Report) record(res result) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Sent++
+	if res.err != nil {
+		r.Failed++
+		r.Errors[res.err.Error()]++
+		return
+	}
+
+	r.StatusCounts[res.status]++
+	r.latencies = append(r.latencies, res.latency)
+	if res.status >= 200 && res.status < 300 {
+		r.Accepted++
+	} else {
+		r.Rejected++
+		r.RejectedPayloads[res.payload]++
+	}
+}
+
+// AcceptanceRate returns the fraction of sent requests that were accepted
+func (r *Report) AcceptanceRate() float64 {
+	if r.Sent == 0 {
+		return 0
+	}
+	return float64(r.Accepted) / float64(r.Sent)
+}
+
+// Throughput returns the achieved requests per second
+func (r *Report) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Sent) / r.Elapsed.Seconds()
+}
+
+// Percentile returns the latency at percentile p (0-100) across completed requests
+func (r *Report) Percentile(p float64) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.latencies) == 0 {
+		return 0
+	}
+
+	sorted := make([]time.Duration, len(r.latencies))
+	copy(sorted, r.latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	idx := int(p / 100 * float64(len(sorted)-1))
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// Print writes a human-readable summary of the run
+func (r *Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "Requests:    %d sent in %s (%.1f req/s)\n", r.Sent, r.Elapsed.Round(time.Millisecond), r.Throughput())
+	fmt.Fprintf(w, "Accepted:    %d (%.1f%%)\n", r.Accepted, r.AcceptanceRate()*100)
+	fmt.Fprintf(w, "Rejected:    %d\n", r.Rejected)
+	fmt.Fprintf(w, "Failed:      %d\n", r.Failed)
+	fmt.Fprintf(w, "Latency:     p50=%s p90=%s p99=%s max=%s\n",
+		r.Percentile(50).Round(time.Microsecond),
+		r.Percentile(90).Round(time.Microsecond),
+		r.Percentile(99).Round(time.Microsecond),
+		r.Percentile(100).Round(time.Microsecond))
+
+	statuses := make([]int, 0, len(r.StatusCounts))
+	for status := range r.StatusCounts {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	for _, status := range statuses {
+		fmt.Fprintf(w, "  HTTP %d:    %d\n", status, r.StatusCounts[status])
+	}
+
+	for name, count := range r.RejectedPayloads {
+		fmt.Fprintf(w, "  rejected %s: %d\n", name, count)
+	}
+
+	for msg, count := range r.Errors {
+		fmt.Fprintf(w, "  error (%dx): %s\n", count, msg)
+	}
+}
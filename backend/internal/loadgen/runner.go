@@ -0,0 +1,176 @@
+package loadgen
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config controls how payloads are replayed against the target
+type Config struct {
+	// DSN of the target project, e.g. http://<public_key>@localhost:8080/<project_id>
+	DSN string
+	// Rate is the number of requests started per second; zero sends as fast as workers allow
+	Rate float64
+	// Concurrency is the number of requests allowed in flight at once
+	Concurrency int
+	// Duration stops the run after the given time; zero means no limit
+	Duration time.Duration
+	// Requests stops the run after the given number of requests; zero means no limit
+	Requests int
+	// Timeout applies to each individual request
+	Timeout time.Duration
+}
+
+// Target holds the endpoints and credentials derived from a DSN
+type Target struct {
+	StoreURL    string
+	EnvelopeURL string
+	PublicKey   string
+}
+
+// ParseTarget derives the ingestion endpoints from a DSN.
+// Unlike dto.ParseDSN it accepts plain HTTP so local servers can be targeted.
+func ParseTarget(dsn string) (*Target, error) {
+	parsed, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DSN: %w", err)
+	}
+
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("DSN must use http or https scheme")
+	}
+
+	if parsed.User == nil || parsed.User.Username() == "" {
+		return nil, fmt.Errorf("DSN missing public key")
+	}
+
+	projectID := strings.Trim(parsed.Path, "/")
+	if projectID == "" {
+		return nil, fmt.Errorf("DSN missing project ID")
+	}
+
+	base := fmt.Sprintf("%s://%s/api/%s", parsed.Scheme, parsed.Host, projectID)
+	return &Target{
+		StoreURL:    base + "/store/",
+		EnvelopeURL: base + "/envelope/",
+		PublicKey:   parsed.User.Username(),
+	}, nil
+}
+
+// Run replays payloads in round-robin order until the request or duration limit is hit or ctx is cancelled
+func Run(ctx context.Context, cfg Config, payloads []Payload) (*Report, error) {
+	if len(payloads) == 0 {
+		return nil, fmt.Errorf("no payloads to send")
+	}
+	if cfg.Requests <= 0 && cfg.Duration <= 0 {
+		return nil, fmt.Errorf("either a request count or a duration is required")
+	}
+
+	target, err := ParseTarget(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+
+	client := &http.Client{
+		Timeout: cfg.Timeout,
+		Transport: &http.Transport{
+			MaxIdleConnsPerHost: cfg.Concurrency,
+		},
+	}
+
+	var ticker *time.Ticker
+	if cfg.Rate > 0 {
+		ticker = time.NewTicker(time.Duration(float64(time.Second) / cfg.Rate))
+		defer ticker.Stop()
+	}
+
+	report := newReport()
+	slots := make(chan struct{}, cfg.Concurrency)
+	var wg sync.WaitGroup
+
+	started := time.Now()
+	for i := 0; cfg.Requests <= 0 || i < cfg.Requests; i++ {
+		if ticker != nil {
+			select {
+			case <-ctx.Done():
+			case <-ticker.C:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+		case slots <- struct{}{}:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		payload := payloads[i%len(payloads)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			report.record(send(client, target, payload))
+		}()
+	}
+
+	wg.Wait()
+	report.Elapsed = time.Since(started)
+
+	return report, nil
+}
+
+// send performs one request and measures its latency
+func send(client *http.Client, target *Target, payload Payload) result {
+	body, err := prepareBody(payload)
+	if err != nil {
+		return result{payload: payload.Name, err: err}
+	}
+
+	endpoint := target.StoreURL
+	contentType := "application/json"
+	if payload.Kind == PayloadEnvelope {
+		endpoint = target.EnvelopeURL
+		contentType = "application/x-sentry-envelope"
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return result{payload: payload.Name, err: err}
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "minisentry-loadgen/1.0")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=minisentry-loadgen/1.0, sentry_key=%s", target.PublicKey))
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return result{payload: payload.Name, latency: time.Since(start), err: err}
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return result{payload: payload.Name, status: resp.StatusCode, latency: time.Since(start)}
+}
@@ -0,0 +1,75 @@
+package mail
+
+import (
+	"fmt"
+	"log"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Mailer sends plain-text emails
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// SMTPConfig configures the SMTP server outgoing emails are relayed through
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // Optional; PLAIN auth is used when set
+	Password string
+	From     string
+}
+
+// New returns an SMTP mailer, or a mailer that only logs emails when no SMTP host is configured
+func New(config SMTPConfig) Mailer {
+	if config.Host == "" {
+		return LogMailer{}
+	}
+	return &SMTPMailer{config: config}
+}
+
+// SMTPMailer sends emails through an SMTP server
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+func (m *SMTPMailer) Send(to, subject, body string) error {
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	addr := fmt.Sprintf("%s:%d", m.config.Host, m.config.Port)
+	if err := smtp.SendMail(addr, auth, m.config.From, []string{to}, buildMessage(m.config.From, to, subject, body)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// LogMailer writes emails to the server log instead of sending them, for development setups
+type LogMailer struct{}
+
+func (LogMailer) Send(to, subject, body string) error {
+	log.Printf("Email to %s (SMTP not configured): %s\n%s", to, subject, body)
+	return nil
+}
+
+func buildMessage(from, to, subject, body string) []byte {
+	var message strings.Builder
+	message.WriteString("From: " + headerValue(from) + "\r\n")
+	message.WriteString("To: " + headerValue(to) + "\r\n")
+	message.WriteString("Subject: " + headerValue(subject) + "\r\n")
+	message.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return []byte(message.String())
+}
+
+// headerValue strips line breaks so user-provided text cannot inject headers
+func headerValue(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
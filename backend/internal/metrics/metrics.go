@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Gauge is an integer value that can go up and down
+type Gauge struct {
+	value atomic.Int64
+}
+
+func (g *Gauge) Set(v int64) { g.value.Store(v) }
+func (g *Gauge) Add(v int64) { g.value.Add(v) }
+func (g *Gauge) Inc()        { g.value.Add(1) }
+func (g *Gauge) Dec()        { g.value.Add(-1) }
+func (g *Gauge) Value() int64 {
+	return g.value.Load()
+}
+
+// Counter is a monotonically increasing count
+type Counter struct {
+	value atomic.Uint64
+}
+
+func (c *Counter) Inc()         { c.value.Add(1) }
+func (c *Counter) Add(v uint64) { c.value.Add(v) }
+func (c *Counter) Value() uint64 {
+	return c.value.Load()
+}
+
+type family struct {
+	name   string
+	help   string
+	kind   string // gauge or counter
+	series map[string]interface{ render() string }
+}
+
+func (g *Gauge) render() string   { return fmt.Sprintf("%d", g.Value()) }
+func (c *Counter) render() string { return fmt.Sprintf("%d", c.Value()) }
+
+// Registry holds metrics and writes them in the Prometheus text exposition format
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+// Default is the registry served on the metrics endpoint
+var Default = NewRegistry()
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// Gauge returns the gauge with the given name and label pairs ("class", "ingestion"),
+// creating it on first use
+func (r *Registry) Gauge(name, help string, labels ...string) *Gauge {
+	return r.metric(name, help, "gauge", labels, func() interface{ render() string } { return &Gauge{} }).(*Gauge)
+}
+
+// Counter returns the counter with the given name and label pairs, creating it on first use
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	return r.metric(name, help, "counter", labels, func() interface{ render() string } { return &Counter{} }).(*Counter)
+}
+
+func (r *Registry) metric(name, help, kind string, labels []string, create func() interface{ render() string }) interface{ render() string } {
+	if len(labels)%2 != 0 {
+		panic("metrics: labels of " + name + " must be key/value pairs")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, ok := r.families[name]
+	if !ok {
+		f = &family{name: name, help: help, kind: kind, series: make(map[string]interface{ render() string })}
+		r.families[name] = f
+	} else if f.kind != kind {
+		panic("metrics: " + name + " registered as both " + f.kind + " and " + kind)
+	}
+
+	key := renderLabels(labels)
+	m, ok := f.series[key]
+	if !ok {
+		m = create()
+		f.series[key] = m
+	}
+	return m
+}
+
+// WriteTo writes every metric in the Prometheus text exposition format, sorted by name and labels
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		f := r.families[name]
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind)
+
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(&b, "%s%s %s\n", f.name, key, f.series[key].render())
+		}
+	}
+	r.mu.Unlock()
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// Handler serves the registry's metrics for Prometheus scraping
+func Handler(r *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteTo(w)
+	})
+}
+
+func renderLabels(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i < len(labels); i += 2 {
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(labels[i+1])
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, labels[i], value))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
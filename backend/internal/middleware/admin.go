@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"minisentry/internal/services"
+)
+
+type AdminMiddleware struct {
+	userService *services.UserService
+}
+
+func NewAdminMiddleware(userService *services.UserService) *AdminMiddleware {
+	return &AdminMiddleware{
+		userService: userService,
+	}
+}
+
+// RequireSuperuser middleware restricts a route to superusers. The flag is read from the
+// database on every request, so revoking it takes effect without waiting for tokens to expire.
+// It must run after RequireAuth.
+func (am *AdminMiddleware) RequireSuperuser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userCtx, ok := GetUserFromContext(r.Context())
+		if !ok {
+			am.writeErrorResponse(w, http.StatusUnauthorized, "user not found in context")
+			return
+		}
+
+		user, err := am.userService.GetUserByID(userCtx.ID)
+		if err != nil {
+			if errors.Is(err, services.ErrUserNotFound) {
+				am.writeErrorResponse(w, http.StatusUnauthorized, "user not found")
+				return
+			}
+			am.writeErrorResponse(w, http.StatusInternalServerError, "failed to verify superuser access")
+			return
+		}
+
+		if !user.IsSuperuser {
+			am.writeErrorResponse(w, http.StatusForbidden, "superuser access required")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeErrorResponse writes a JSON error response
+func (am *AdminMiddleware) writeErrorResponse(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := ErrorResponse{
+		Error:   http.StatusText(statusCode),
+		Message: message,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const apiCallContextKey contextKey = "api_call"
+
+// apiCall is who made an API call and what it was made against, filled in by the
+// authentication and access middlewares the call passes through
+type apiCall struct {
+	callerType     string
+	callerID       uuid.UUID
+	organizationID uuid.UUID
+	projectID      uuid.UUID
+}
+
+// APIUsageMiddleware counts the API calls of users and ingest tokens
+type APIUsageMiddleware struct {
+	usageService *services.APIUsageService
+}
+
+func NewAPIUsageMiddleware(usageService *services.APIUsageService) *APIUsageMiddleware {
+	return &APIUsageMiddleware{
+		usageService: usageService,
+	}
+}
+
+// Track counts each API call with its route and response status once it is answered. Only
+// authenticated calls against an organization or project are counted: calls rejected before
+// the caller is known, and routes such as the user's own profile, are not.
+func (um *APIUsageMiddleware) Track(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := &apiCall{}
+		ww := &responseWriter{
+			ResponseWriter: w,
+			statusCode:     http.StatusOK,
+		}
+
+		next.ServeHTTP(ww, r.WithContext(context.WithValue(r.Context(), apiCallContextKey, call)))
+
+		if call.callerID == uuid.Nil || call.organizationID == uuid.Nil && call.projectID == uuid.Nil {
+			return
+		}
+		um.usageService.Record(call.organizationID, call.projectID, call.callerType, call.callerID, apiRoute(r), ww.statusCode)
+	})
+}
+
+// apiRoute is the method and route pattern of a request, so calls to the same route with
+// different IDs are counted together
+func apiRoute(r *http.Request) string {
+	pattern := r.URL.Path
+	if routeCtx := chi.RouteContext(r.Context()); routeCtx != nil && routeCtx.RoutePattern() != "" {
+		pattern = routeCtx.RoutePattern()
+	}
+	if len(pattern) > 1 {
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+	return r.Method + " " + pattern
+}
+
+// recordAPICaller attributes the API call of a request to a user or ingest token
+func recordAPICaller(ctx context.Context, callerType string, callerID uuid.UUID) {
+	if call, ok := ctx.Value(apiCallContextKey).(*apiCall); ok {
+		call.callerType = callerType
+		call.callerID = callerID
+	}
+}
+
+// RecordAPIProject attributes the API call of a request to a project, for routes that check
+// project access themselves; organizationID may be uuid.Nil when it is not at hand
+func RecordAPIProject(ctx context.Context, organizationID, projectID uuid.UUID) {
+	if call, ok := ctx.Value(apiCallContextKey).(*apiCall); ok {
+		call.organizationID = organizationID
+		call.projectID = projectID
+	}
+}
+
+// recordAPIOrganization attributes the API call of a request to an organization
+func recordAPIOrganization(ctx context.Context, organizationID uuid.UUID) {
+	if call, ok := ctx.Value(apiCallContextKey).(*apiCall); ok && call.projectID == uuid.Nil {
+		call.organizationID = organizationID
+	}
+}
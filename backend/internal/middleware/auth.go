@@ -19,6 +19,9 @@ const (
 
 type AuthMiddleware struct {
 	jwtService *services.JWTService
+
+	// cookies, when set, also authenticates requests by session cookie
+	cookies *SessionCookies
 }
 
 type UserContext struct {
@@ -38,11 +41,27 @@ func NewAuthMiddleware(jwtService *services.JWTService) *AuthMiddleware {
 	}
 }
 
+// UseSessionCookies makes requests without an Authorization header authenticate by session
+// cookie, with a CSRF token on unsafe requests
+func (am *AuthMiddleware) UseSessionCookies(cookies *SessionCookies) {
+	am.cookies = cookies
+}
+
 // RequireAuth is a middleware that validates JWT tokens and injects user context
 func (am *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Extract token from Authorization header
+		// Extract token from Authorization header, or else the session cookie
 		authHeader := r.Header.Get("Authorization")
+		if authHeader == "" && am.cookies != nil {
+			token, err := am.cookies.SessionToken(r)
+			if err != nil {
+				am.writeErrorResponse(w, http.StatusForbidden, err.Error())
+				return
+			}
+			if token != "" {
+				authHeader = "Bearer " + token
+			}
+		}
 		if authHeader == "" {
 			am.writeErrorResponse(w, http.StatusUnauthorized, "missing authorization header")
 			return
@@ -90,6 +109,8 @@ func (am *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
 			Name:  claims.Name,
 		}
 
+		recordAPICaller(r.Context(), services.APICallerUser, userID)
+
 		// Add user to request context
 		ctx := context.WithValue(r.Context(), UserContextKey, userCtx)
 		r = r.WithContext(ctx)
@@ -102,8 +123,13 @@ func (am *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
 // OptionalAuth is a middleware that validates JWT tokens if present but doesn't require them
 func (am *AuthMiddleware) OptionalAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Extract token from Authorization header
+		// Extract token from Authorization header, or else the session cookie
 		authHeader := r.Header.Get("Authorization")
+		if authHeader == "" && am.cookies != nil {
+			if token, err := am.cookies.SessionToken(r); err == nil && token != "" {
+				authHeader = "Bearer " + token
+			}
+		}
 		if authHeader == "" {
 			// No auth header, continue without user context
 			next.ServeHTTP(w, r)
@@ -149,6 +175,8 @@ func (am *AuthMiddleware) OptionalAuth(next http.Handler) http.Handler {
 			Name:  claims.Name,
 		}
 
+		recordAPICaller(r.Context(), services.APICallerUser, userID)
+
 		// Add user to request context
 		ctx := context.WithValue(r.Context(), UserContextKey, userCtx)
 		r = r.WithContext(ctx)
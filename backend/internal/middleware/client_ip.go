@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+type clientIPContextKey string
+
+const ClientIPContextKey clientIPContextKey = "client_ip"
+
+// ClientIPMiddleware resolves the IP address of the client of each request. The
+// X-Forwarded-For and X-Real-IP headers are only believed when set by a trusted proxy, as
+// clients can send them with any address.
+type ClientIPMiddleware struct {
+	trustedProxies []netip.Prefix
+}
+
+// NewClientIPMiddleware creates the client IP middleware. trustedProxies are the IP addresses
+// or CIDR ranges of the proxies whose forwarded headers are believed.
+func NewClientIPMiddleware(trustedProxies []string) (*ClientIPMiddleware, error) {
+	prefixes, err := parseTrustedProxies(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientIPMiddleware{trustedProxies: prefixes}, nil
+}
+
+// Handler stores the client IP address in the request context for ClientIP
+func (cm *ClientIPMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), ClientIPContextKey, cm.resolve(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// resolve returns the address the request came from: the peer, unless it is a trusted
+// proxy, in which case X-Forwarded-For is followed back from the peer past the trusted
+// proxies to the first address they did not add themselves
+func (cm *ClientIPMiddleware) resolve(r *http.Request) string {
+	ip := remoteIP(r.RemoteAddr)
+	if !isTrustedProxy(cm.trustedProxies, ip) {
+		return ip
+	}
+
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if _, err := netip.ParseAddr(hop); err != nil {
+				break
+			}
+			ip = hop
+			if !isTrustedProxy(cm.trustedProxies, hop) {
+				break
+			}
+		}
+		return ip
+	}
+
+	// X-Real-IP is set by nginx
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+		if _, err := netip.ParseAddr(realIP); err == nil {
+			return realIP
+		}
+	}
+	return ip
+}
+
+// ClientIP returns the IP address of the client of the request, as resolved by
+// ClientIPMiddleware; without it, the peer address, as forwarded headers cannot be trusted
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(ClientIPContextKey).(string); ok && ip != "" {
+		return ip
+	}
+	return remoteIP(r.RemoteAddr)
+}
+
+// remoteIP strips the port from a RemoteAddr
+func remoteIP(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+// parseTrustedProxies parses IP addresses and CIDR ranges of trusted proxies
+func parseTrustedProxies(trustedProxies []string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, proxy := range trustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			addr, err := netip.ParseAddr(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// isTrustedProxy reports whether an IP address is in one of the trusted proxy ranges
+func isTrustedProxy(trustedProxies []netip.Prefix, ip string) bool {
+	if len(trustedProxies) == 0 {
+		return false
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,299 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// DefaultCompressionMinSize is the smallest response body worth compressing
+const DefaultCompressionMinSize = 1024
+
+// brotliLevel trades some compression for speed, as responses are compressed per request
+const brotliLevel = 4
+
+var (
+	gzipWriters = sync.Pool{New: func() interface{} {
+		writer, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return writer
+	}}
+	brotliWriters = sync.Pool{New: func() interface{} {
+		return brotli.NewWriterLevel(io.Discard, brotliLevel)
+	}}
+)
+
+// CompressionMiddleware compresses responses with brotli or gzip, as negotiated with the
+// Accept-Encoding header. Responses smaller than minSize bytes, already encoded ones and
+// those of already compressed content types (images, archives, ...) are sent as they are.
+func CompressionMiddleware(minSize int) func(http.Handler) http.Handler {
+	if minSize < 0 {
+		minSize = DefaultCompressionMinSize
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressResponseWriter{
+				ResponseWriter: w,
+				encoding:       encoding,
+				minSize:        minSize,
+				statusCode:     http.StatusOK,
+			}
+			defer cw.Close()
+
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding picks brotli or gzip from an Accept-Encoding header, by quality and
+// preferring brotli on ties; "" when the client accepts neither
+func negotiateEncoding(acceptEncoding string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		qualities[name] = quality
+	}
+
+	quality := func(name string) float64 {
+		if q, ok := qualities[name]; ok {
+			return q
+		}
+		if q, ok := qualities["*"]; ok {
+			return q
+		}
+		return 0
+	}
+
+	br, gz := quality("br"), quality("gzip")
+	switch {
+	case br > 0 && br >= gz:
+		return "br"
+	case gz > 0:
+		return "gzip"
+	default:
+		return ""
+	}
+}
+
+// isCompressibleContentType reports whether a response of the content type is worth compressing
+func isCompressibleContentType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+	switch {
+	case mediaType == "":
+		return true
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"),
+		mediaType == "image/svg+xml":
+		return true
+	case strings.HasPrefix(mediaType, "image/"),
+		strings.HasPrefix(mediaType, "video/"),
+		strings.HasPrefix(mediaType, "audio/"):
+		return false
+	}
+
+	switch mediaType {
+	case "application/json", "application/javascript", "application/xml",
+		"application/x-ndjson", "application/x-sentry-envelope":
+		return true
+	}
+	return false
+}
+
+// compressResponseWriter buffers the start of a response until it knows whether to
+// compress it: once minSize bytes are written, or when the handler returns or flushes
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	statusCode  int
+	wroteHeader bool // WriteHeader was called by the handler
+	decided     bool // the headers were sent to the client
+	buffer      []byte
+	encoder     io.WriteCloser
+}
+
+func (cw *compressResponseWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	cw.statusCode = code
+
+	// Informational responses are sent right away
+	if code >= 100 && code < 200 {
+		cw.wroteHeader = false
+		cw.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (cw *compressResponseWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	if !cw.decided {
+		cw.buffer = append(cw.buffer, b...)
+		if len(cw.buffer) < cw.minSize {
+			return len(b), nil
+		}
+		if err := cw.start(); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+
+	if cw.encoder != nil {
+		return cw.encoder.Write(b)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+// start sends the headers, compressed when the response qualifies, and the buffered body
+func (cw *compressResponseWriter) start() error {
+	cw.decided = true
+
+	header := cw.Header()
+	if cw.shouldCompress() {
+		header.Set("Content-Encoding", cw.encoding)
+		header.Del("Content-Length")
+		header.Del("Accept-Ranges")
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+		cw.encoder = cw.newEncoder()
+	}
+	cw.ResponseWriter.WriteHeader(cw.statusCode)
+
+	buffered := cw.buffer
+	cw.buffer = nil
+	if len(buffered) == 0 {
+		return nil
+	}
+	var err error
+	if cw.encoder != nil {
+		_, err = cw.encoder.Write(buffered)
+	} else {
+		_, err = cw.ResponseWriter.Write(buffered)
+	}
+	return err
+}
+
+func (cw *compressResponseWriter) shouldCompress() bool {
+	header := cw.Header()
+	if cw.statusCode < http.StatusOK || cw.statusCode == http.StatusNoContent ||
+		cw.statusCode == http.StatusNotModified || cw.statusCode == http.StatusPartialContent {
+		return false
+	}
+	if header.Get("Content-Encoding") != "" || len(cw.buffer) < cw.minSize {
+		return false
+	}
+	return isCompressibleContentType(header.Get("Content-Type"))
+}
+
+func (cw *compressResponseWriter) newEncoder() io.WriteCloser {
+	if cw.encoding == "br" {
+		writer := brotliWriters.Get().(*brotli.Writer)
+		writer.Reset(cw.ResponseWriter)
+		return writer
+	}
+	writer := gzipWriters.Get().(*gzip.Writer)
+	writer.Reset(cw.ResponseWriter)
+	return writer
+}
+
+// Flush sends what was written so far, deciding on compression with what is buffered
+func (cw *compressResponseWriter) Flush() {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			cw.WriteHeader(http.StatusOK)
+		}
+		// Streamed responses are compressed regardless of how little was written yet
+		cw.minSize = 0
+		cw.start()
+	}
+	switch encoder := cw.encoder.(type) {
+	case *gzip.Writer:
+		encoder.Flush()
+	case *brotli.Writer:
+		encoder.Flush()
+	}
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack lets websocket and similar handlers take over the connection
+func (cw *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := cw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	cw.decided = true
+	return hijacker.Hijack()
+}
+
+// Close finishes the response: sends a small buffered body as it is, or completes the
+// compressed stream and returns the encoder to its pool
+func (cw *compressResponseWriter) Close() error {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			// Nothing was written; leave the response to the server
+			return nil
+		}
+		if err := cw.start(); err != nil {
+			return err
+		}
+	}
+	if cw.encoder == nil {
+		return nil
+	}
+
+	err := cw.encoder.Close()
+	switch encoder := cw.encoder.(type) {
+	case *gzip.Writer:
+		encoder.Reset(io.Discard)
+		gzipWriters.Put(encoder)
+	case *brotli.Writer:
+		encoder.Reset(io.Discard)
+		brotliWriters.Put(encoder)
+	}
+	cw.encoder = nil
+	return err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (cw *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
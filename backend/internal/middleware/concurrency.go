@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"minisentry/internal/metrics"
+)
+
+// ConcurrencyLimit bounds the requests a route class serves at once. Requests beyond
+// MaxInFlight wait in a queue of up to MaxQueued requests for at most QueueTimeout;
+// the rest are rejected with 503. A MaxInFlight of 0 only tracks the requests.
+type ConcurrencyLimit struct {
+	MaxInFlight  int
+	MaxQueued    int
+	QueueTimeout time.Duration
+}
+
+// ConcurrencyLimiter enforces a ConcurrencyLimit on a class of routes, such as ingestion
+// or the dashboard API, so one class cannot take all of the server's capacity. In-flight,
+// queued and rejected requests are exported as metrics labelled with the class.
+type ConcurrencyLimiter struct {
+	limit  ConcurrencyLimit
+	slots  chan struct{}
+	queued atomic.Int64
+
+	inFlightGauge *metrics.Gauge
+	queuedGauge   *metrics.Gauge
+	rejected      *metrics.Counter
+}
+
+// NewConcurrencyLimiter creates the limiter of a route class, registering its metrics
+func NewConcurrencyLimiter(class string, limit ConcurrencyLimit, registry *metrics.Registry) *ConcurrencyLimiter {
+	cl := &ConcurrencyLimiter{
+		limit:         limit,
+		inFlightGauge: registry.Gauge("minisentry_http_in_flight_requests", "Requests being served, by route class.", "class", class),
+		queuedGauge:   registry.Gauge("minisentry_http_queued_requests", "Requests waiting for a concurrency slot, by route class.", "class", class),
+		rejected:      registry.Counter("minisentry_http_rejected_requests_total", "Requests rejected by the concurrency limit, by route class.", "class", class),
+	}
+	registry.Gauge("minisentry_http_max_in_flight_requests", "Concurrency limit of the route class; 0 is unlimited.", "class", class).Set(int64(limit.MaxInFlight))
+
+	if limit.MaxInFlight > 0 {
+		cl.slots = make(chan struct{}, limit.MaxInFlight)
+	}
+	return cl
+}
+
+// InFlight returns the number of requests being served
+func (cl *ConcurrencyLimiter) InFlight() int64 {
+	return cl.inFlightGauge.Value()
+}
+
+// Middleware applies the limit to the routes it wraps
+func (cl *ConcurrencyLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cl.slots != nil {
+			if !cl.acquire(r) {
+				cl.rejected.Inc()
+				cl.writeErrorResponse(w)
+				return
+			}
+			defer func() { <-cl.slots }()
+		}
+
+		cl.inFlightGauge.Inc()
+		defer cl.inFlightGauge.Dec()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// acquire takes a slot, queueing the request while the queue has room
+func (cl *ConcurrencyLimiter) acquire(r *http.Request) bool {
+	select {
+	case cl.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if cl.queued.Add(1) > int64(cl.limit.MaxQueued) {
+		cl.queued.Add(-1)
+		return false
+	}
+	cl.queuedGauge.Inc()
+	defer func() {
+		cl.queued.Add(-1)
+		cl.queuedGauge.Dec()
+	}()
+
+	timer := time.NewTimer(cl.limit.QueueTimeout)
+	defer timer.Stop()
+
+	select {
+	case cl.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+// writeErrorResponse rejects a request, asking the client to retry shortly
+func (cl *ConcurrencyLimiter) writeErrorResponse(w http.ResponseWriter) {
+	retryAfter := int(cl.limit.QueueTimeout/time.Second) + 1
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusServiceUnavailable)
+
+	response := ErrorResponse{
+		Error:   http.StatusText(http.StatusServiceUnavailable),
+		Message: "server is at capacity, retry later",
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"minisentry/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// IngestTokenMiddleware authenticates the release and artifact routes, the only routes
+// organization ingest tokens are accepted on. Every other route authenticates with
+// RequireAuth, which rejects ingest tokens as they are not JWTs.
+type IngestTokenMiddleware struct {
+	tokenService      *services.IngestTokenService
+	authMiddleware    *AuthMiddleware
+	projectMiddleware *ProjectMiddleware
+}
+
+func NewIngestTokenMiddleware(tokenService *services.IngestTokenService, authMiddleware *AuthMiddleware, projectMiddleware *ProjectMiddleware) *IngestTokenMiddleware {
+	return &IngestTokenMiddleware{
+		tokenService:      tokenService,
+		authMiddleware:    authMiddleware,
+		projectMiddleware: projectMiddleware,
+	}
+}
+
+// RequireReleaseAccess accepts either a user access token, requiring project membership as
+// RequireProjectAccess does, or an ingest token of the project's organization
+func (im *IngestTokenMiddleware) RequireReleaseAccess(next http.Handler) http.Handler {
+	userAccess := im.authMiddleware.RequireAuth(im.projectMiddleware.RequireProjectAccess(next))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !strings.HasPrefix(token, services.IngestTokenPrefix) {
+			userAccess.ServeHTTP(w, r)
+			return
+		}
+
+		projectID, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			im.writeErrorResponse(w, http.StatusBadRequest, "invalid project ID format")
+			return
+		}
+
+		project, ingestToken, err := im.tokenService.AuthorizeProject(token, projectID)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrIngestTokenInvalid):
+				im.writeErrorResponse(w, http.StatusUnauthorized, "invalid ingest token")
+			case errors.Is(err, services.ErrProjectNotFound):
+				im.writeErrorResponse(w, http.StatusNotFound, "project not found")
+			default:
+				im.writeErrorResponse(w, http.StatusInternalServerError, "failed to authenticate ingest token")
+			}
+			return
+		}
+		recordAPICaller(r.Context(), services.APICallerIngestToken, ingestToken.ID)
+		RecordAPIProject(r.Context(), project.OrganizationID, project.ID)
+
+		projectCtx := &ProjectContext{
+			ID:             project.ID,
+			OrganizationID: project.OrganizationID,
+			Name:           project.Name,
+			Slug:           project.Slug,
+			Platform:       project.Platform,
+			DSN:            project.DSN,
+			PublicKey:      project.PublicKey,
+			IsActive:       project.IsActive,
+			Role:           "", // No role for ingest tokens
+			MaxEventSize:   maxEventSize(project),
+		}
+
+		ctx := context.WithValue(r.Context(), ProjectContextKey, projectCtx)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// writeErrorResponse writes a JSON error response
+func (im *IngestTokenMiddleware) writeErrorResponse(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := ErrorResponse{
+		Error:   http.StatusText(statusCode),
+		Message: message,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
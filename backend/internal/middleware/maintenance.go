@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"minisentry/internal/models"
+	"minisentry/internal/services"
+
+	"github.com/google/uuid"
+)
+
+type maintenanceContextKey string
+
+const MaintenanceContextKey maintenanceContextKey = "maintenance"
+
+// MaintenanceRetryAfter is when clients refused during a maintenance window are told to retry
+const MaintenanceRetryAfter = time.Minute
+
+// maintenanceExemptPaths stay writable during maintenance so superusers can sign in and
+// end the window
+var maintenanceExemptPaths = []string{
+	"/api/v1/admin/",
+	"/api/v1/auth/login",
+	"/api/v1/auth/refresh",
+	"/api/v1/auth/logout",
+}
+
+// MaintenanceMiddleware makes the API read-only during maintenance windows
+type MaintenanceMiddleware struct {
+	maintenanceService *services.MaintenanceService
+}
+
+// NewMaintenanceMiddleware creates a new maintenance middleware
+func NewMaintenanceMiddleware(maintenanceService *services.MaintenanceService) *MaintenanceMiddleware {
+	return &MaintenanceMiddleware{
+		maintenanceService: maintenanceService,
+	}
+}
+
+// ReadOnly refuses mutations with 503 while the instance is in a maintenance window. It
+// also makes the service available to RejectDuringMaintenance, which the organization and
+// project middlewares use for organization windows.
+func (mm *MaintenanceMiddleware) ReadOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isMaintenanceMutation(r) {
+			if window := mm.maintenanceService.ActiveWindow(nil); window != nil {
+				writeMaintenanceResponse(w, window)
+				return
+			}
+		}
+
+		ctx := context.WithValue(r.Context(), MaintenanceContextKey, mm.maintenanceService)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RejectDuringMaintenance refuses a mutation with 503 while the organization is in a
+// maintenance window, reporting whether it did. Requests that did not go through ReadOnly
+// are never refused.
+func RejectDuringMaintenance(w http.ResponseWriter, r *http.Request, orgID uuid.UUID) bool {
+	maintenanceService, ok := r.Context().Value(MaintenanceContextKey).(*services.MaintenanceService)
+	if !ok || !isMaintenanceMutation(r) {
+		return false
+	}
+
+	window := maintenanceService.ActiveWindow(&orgID)
+	if window == nil {
+		return false
+	}
+	writeMaintenanceResponse(w, window)
+	return true
+}
+
+// RejectProjectDuringMaintenance is RejectDuringMaintenance for the organization of a project
+func RejectProjectDuringMaintenance(w http.ResponseWriter, r *http.Request, projectID uuid.UUID) bool {
+	maintenanceService, ok := r.Context().Value(MaintenanceContextKey).(*services.MaintenanceService)
+	if !ok || !isMaintenanceMutation(r) {
+		return false
+	}
+
+	orgID, ok := maintenanceService.ProjectOrganization(projectID)
+	if !ok {
+		return false
+	}
+	return RejectDuringMaintenance(w, r, orgID)
+}
+
+// isMaintenanceMutation reports whether a request changes data and is not exempt from
+// maintenance windows
+func isMaintenanceMutation(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	for _, path := range maintenanceExemptPaths {
+		if strings.HasPrefix(r.URL.Path, path) {
+			return false
+		}
+	}
+	return true
+}
+
+// writeMaintenanceResponse writes the 503 refusing a mutation during a maintenance window
+func writeMaintenanceResponse(w http.ResponseWriter, window *models.MaintenanceWindow) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", strconv.Itoa(int(MaintenanceRetryAfter.Seconds())))
+	w.WriteHeader(http.StatusServiceUnavailable)
+
+	json.NewEncoder(w).Encode(ErrorResponse{
+		Error:   http.StatusText(http.StatusServiceUnavailable),
+		Message: window.Message,
+	})
+}
@@ -23,10 +23,11 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 		// Process request
 		next.ServeHTTP(ww, r)
 
-		// Log request details
+		// Log request details as key=value pairs
 		duration := time.Since(start)
 		log.Printf(
-			"%s %s %s %d %v %s",
+			"request_id=%s remote=%s method=%s path=%q status=%d duration=%v user_agent=%q",
+			GetRequestID(r.Context()),
 			r.RemoteAddr,
 			r.Method,
 			r.URL.Path,
@@ -43,7 +44,7 @@ func RecoveryMiddleware(next http.Handler) http.Handler {
 		defer func() {
 			if err := recover(); err != nil {
 				// Log the panic with stack trace
-				log.Printf("Panic recovered: %v\n%s", err, debug.Stack())
+				Logf(r.Context(), "Panic recovered: %v\n%s", err, debug.Stack())
 
 				// Return 500 error
 				w.Header().Set("Content-Type", "application/json")
@@ -75,6 +76,7 @@ func CORSMiddleware(allowedOrigins []string) func(http.Handler) http.Handler {
 			"Content-Type",
 			"X-CSRF-Token",
 			"X-Requested-With",
+			"X-Request-ID",
 		},
 		ExposedHeaders: []string{
 			"Content-Length",
@@ -99,26 +101,6 @@ func SecurityMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// RequestIDMiddleware adds a unique request ID to each request
-func RequestIDMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		requestID := r.Header.Get("X-Request-ID")
-		if requestID == "" {
-			// Generate a simple request ID using timestamp
-			requestID = time.Now().Format("20060102150405.000000")
-		}
-
-		// Add request ID to response headers
-		w.Header().Set("X-Request-ID", requestID)
-
-		// Add request ID to context for logging
-		ctx := r.Context()
-		r = r.WithContext(ctx)
-
-		next.ServeHTTP(w, r)
-	})
-}
-
 // HealthCheckMiddleware provides a simple health check endpoint
 func HealthCheckMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
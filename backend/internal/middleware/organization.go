@@ -77,6 +77,11 @@ func (om *OrganizationMiddleware) RequireOrganizationAccess(next http.Handler) h
 			return
 		}
 
+		recordAPIOrganization(r.Context(), org.ID)
+		if RejectDuringMaintenance(w, r, org.ID) {
+			return
+		}
+
 		// Add organization and role to context
 		orgCtx := &OrganizationContext{
 			ID:   org.ID,
@@ -187,6 +192,7 @@ func (om *OrganizationMiddleware) RequireMemberAccess(next http.Handler) http.Ha
 			}
 			return
 		}
+		recordAPIOrganization(r.Context(), org.ID)
 
 		// Add organization, role, and target user ID to context
 		orgCtx := &OrganizationContext{
@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"minisentry/internal/metrics"
+)
+
+// Reasons ingestion requests are shed for
+const (
+	OverloadInFlight = "in_flight" // too many requests held at once
+	OverloadDatabase = "database"  // requests wait too long for a database connection
+	OverloadQueue    = "queue"     // the ingest queue backlog is too deep
+)
+
+// overloadSampleInterval is how often the database and queue are checked for saturation
+const overloadSampleInterval = time.Second
+
+// OverloadLimits configures when ingestion sheds load; a zero limit is not enforced
+type OverloadLimits struct {
+	// MaxInFlight bounds the ingestion requests held at once, including those waiting
+	// for a concurrency slot
+	MaxInFlight int
+	// MaxDBWait bounds the average wait for a database connection over the last interval
+	MaxDBWait time.Duration
+	// MaxQueueDepth bounds the events waiting in the ingest queue
+	MaxQueueDepth int64
+	// RetryAfter is when shed clients are told to retry
+	RetryAfter time.Duration
+}
+
+// OverloadController sheds ingestion requests while the server cannot keep up, answering
+// at once with 503 or 429 and retry hints instead of letting requests pile up behind a
+// saturated database or ingest queue. Saturation is sampled every second.
+type OverloadController struct {
+	limits   OverloadLimits
+	dbStats  func() sql.DBStats
+	queueLen func() (int64, error)
+
+	inFlight   atomic.Int64
+	overloaded atomic.Value // string: the reason requests are shed, "" when not overloaded
+
+	overloadedGauge *metrics.Gauge
+	shed            map[string]*metrics.Counter
+}
+
+// NewOverloadController creates the overload controller of ingestion, registering its metrics
+func NewOverloadController(limits OverloadLimits, registry *metrics.Registry) *OverloadController {
+	if limits.RetryAfter <= 0 {
+		limits.RetryAfter = time.Second
+	}
+	oc := &OverloadController{
+		limits:          limits,
+		overloadedGauge: registry.Gauge("minisentry_ingest_overloaded", "1 while ingestion sheds requests because the database or ingest queue is saturated."),
+		shed:            make(map[string]*metrics.Counter),
+	}
+	for _, reason := range []string{OverloadInFlight, OverloadDatabase, OverloadQueue} {
+		oc.shed[reason] = registry.Counter("minisentry_ingest_shed_requests_total", "Ingestion requests shed by the overload controller, by reason.", "reason", reason)
+	}
+	oc.overloaded.Store("")
+	return oc
+}
+
+// WatchDatabase makes the controller shed requests while database connections are
+// saturated. It must be called before Start.
+func (oc *OverloadController) WatchDatabase(stats func() sql.DBStats) {
+	oc.dbStats = stats
+}
+
+// WatchQueue makes the controller shed requests while the ingest queue backlog is over its
+// limit. It must be called before Start.
+func (oc *OverloadController) WatchQueue(queueLen func() (int64, error)) {
+	oc.queueLen = queueLen
+}
+
+// Start samples the database and queue until the context is cancelled
+func (oc *OverloadController) Start(ctx context.Context) {
+	if (oc.dbStats == nil || oc.limits.MaxDBWait <= 0) && (oc.queueLen == nil || oc.limits.MaxQueueDepth <= 0) {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(overloadSampleInterval)
+		defer ticker.Stop()
+
+		var last sql.DBStats
+		if oc.dbStats != nil {
+			last = oc.dbStats()
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			reason := ""
+			if oc.dbStats != nil && oc.limits.MaxDBWait > 0 {
+				stats := oc.dbStats()
+				if waits := stats.WaitCount - last.WaitCount; waits > 0 && (stats.WaitDuration-last.WaitDuration)/time.Duration(waits) >= oc.limits.MaxDBWait {
+					reason = OverloadDatabase
+				}
+				last = stats
+			}
+			if reason == "" && oc.queueLen != nil && oc.limits.MaxQueueDepth > 0 {
+				depth, err := oc.queueLen()
+				if err != nil {
+					log.Printf("Failed to check the ingest queue depth: %v", err)
+				} else if depth >= oc.limits.MaxQueueDepth {
+					reason = OverloadQueue
+				}
+			}
+			oc.setOverloaded(reason)
+		}
+	}()
+}
+
+// setOverloaded records the reason requests are shed for, logging when it changes
+func (oc *OverloadController) setOverloaded(reason string) {
+	previous := oc.overloaded.Swap(reason).(string)
+	if previous == reason {
+		return
+	}
+	if reason == "" {
+		log.Printf("Ingestion recovered from overload (%s), accepting requests again", previous)
+		oc.overloadedGauge.Set(0)
+		return
+	}
+	log.Printf("Ingestion overloaded (%s), shedding requests", reason)
+	oc.overloadedGauge.Set(1)
+}
+
+// Middleware sheds the requests of the routes it wraps while overloaded. It should run
+// before the concurrency limit so shed requests never wait for a slot.
+func (oc *OverloadController) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if reason := oc.overloaded.Load().(string); reason != "" {
+			oc.Shed(w, reason)
+			return
+		}
+
+		inFlight := oc.inFlight.Add(1)
+		defer oc.inFlight.Add(-1)
+		if oc.limits.MaxInFlight > 0 && inFlight > int64(oc.limits.MaxInFlight) {
+			oc.Shed(w, OverloadInFlight)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Shed rejects a request for the given reason: 429 while the ingest queue is backed up,
+// which SDKs back off from like a rate limit, and 503 otherwise. Both tell clients when to
+// retry through Retry-After and X-Sentry-Rate-Limits.
+func (oc *OverloadController) Shed(w http.ResponseWriter, reason string) {
+	if counter, ok := oc.shed[reason]; ok {
+		counter.Inc()
+	}
+
+	retryAfter := int(oc.limits.RetryAfter.Seconds())
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	w.Header().Set("X-Sentry-Rate-Limits", fmt.Sprintf("%d::organization:overload", retryAfter))
+
+	switch reason {
+	case OverloadQueue:
+		WriteSentryError(w, http.StatusTooManyRequests, "ingest queue is full, retry later")
+	case OverloadDatabase:
+		WriteSentryError(w, http.StatusServiceUnavailable, "server is overloaded, retry later")
+	default:
+		WriteSentryError(w, http.StatusServiceUnavailable, "server is at capacity, retry later")
+	}
+}
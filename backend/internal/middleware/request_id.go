@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/netip"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type requestIDContextKey string
+
+const RequestIDContextKey requestIDContextKey = "request_id"
+
+// maxBufferedErrorBody bounds the error response bodies held back to add the request ID;
+// larger bodies are passed through unchanged
+const maxBufferedErrorBody = 64 << 10
+
+// validRequestID restricts the incoming request IDs that are kept, so they are safe to log
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:\-]{1,128}$`)
+
+// RequestIDMiddleware gives every request an ID, sent back in the X-Request-ID header,
+// stored in the request context for logging and added to the body of error responses.
+// IDs are UUIDv7, so they sort by time; an X-Request-ID set by a trusted proxy is kept.
+type RequestIDMiddleware struct {
+	trustedProxies []netip.Prefix
+}
+
+// NewRequestIDMiddleware creates the request ID middleware. trustedProxies are the IP
+// addresses or CIDR ranges of proxies whose X-Request-ID headers are kept.
+func NewRequestIDMiddleware(trustedProxies []string) (*RequestIDMiddleware, error) {
+	prefixes, err := parseTrustedProxies(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	return &RequestIDMiddleware{trustedProxies: prefixes}, nil
+}
+
+// Handler assigns the request ID
+func (rm *RequestIDMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get("X-Request-ID")
+		if requestID == "" || !validRequestID.MatchString(requestID) || !isTrustedProxy(rm.trustedProxies, remoteIP(r.RemoteAddr)) {
+			requestID = newRequestID()
+		}
+
+		w.Header().Set("X-Request-ID", requestID)
+		r.Header.Set("X-Request-ID", requestID)
+		ctx := context.WithValue(r.Context(), RequestIDContextKey, requestID)
+
+		rw := &requestIDWriter{ResponseWriter: w, requestID: requestID}
+		defer rw.finish()
+
+		next.ServeHTTP(rw, r.WithContext(ctx))
+	})
+}
+
+// GetRequestID returns the ID of the request the context belongs to, or "" outside a request
+func GetRequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(RequestIDContextKey).(string)
+	return requestID
+}
+
+// Logf logs a message about a request, prefixed with its request ID
+func Logf(ctx context.Context, format string, args ...interface{}) {
+	if requestID := GetRequestID(ctx); requestID != "" {
+		format = "request_id=" + requestID + " " + format
+	}
+	log.Printf(format, args...)
+}
+
+func newRequestID() string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.NewString()
+	}
+	return id.String()
+}
+
+// requestIDWriter holds back JSON and plain text error responses to add the request ID to
+// their body. Plain text errors, such as those written by http.Error, are turned into the
+// JSON error format.
+type requestIDWriter struct {
+	http.ResponseWriter
+	requestID   string
+	status      int
+	wroteHeader bool
+	body        *bytes.Buffer // held back error body; nil when the response is passed through
+}
+
+func (rw *requestIDWriter) WriteHeader(code int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
+	rw.status = code
+
+	contentType := rw.Header().Get("Content-Type")
+	if code >= 400 && (strings.HasPrefix(contentType, "application/json") || strings.HasPrefix(contentType, "text/plain")) {
+		rw.body = &bytes.Buffer{}
+		return
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *requestIDWriter) Write(b []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	if rw.body == nil {
+		return rw.ResponseWriter.Write(b)
+	}
+	if rw.body.Len()+len(b) > maxBufferedErrorBody {
+		// Too large to rewrite: send what was held back and pass the rest through
+		held := rw.body.Bytes()
+		rw.body = nil
+		rw.ResponseWriter.WriteHeader(rw.status)
+		if _, err := rw.ResponseWriter.Write(held); err != nil {
+			return 0, err
+		}
+		return rw.ResponseWriter.Write(b)
+	}
+	return rw.body.Write(b)
+}
+
+// finish writes the held back error response with the request ID added
+func (rw *requestIDWriter) finish() {
+	if rw.body == nil {
+		return
+	}
+
+	body := rw.body.Bytes()
+	header := rw.Header()
+	if strings.HasPrefix(header.Get("Content-Type"), "text/plain") {
+		body, _ = json.Marshal(map[string]string{
+			"error":      http.StatusText(rw.status),
+			"message":    strings.TrimSpace(string(body)),
+			"request_id": rw.requestID,
+		})
+		header.Set("Content-Type", "application/json")
+	} else {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(body, &fields); err == nil && fields != nil {
+			if _, ok := fields["request_id"]; !ok {
+				fields["request_id"], _ = json.Marshal(rw.requestID)
+				body, _ = json.Marshal(fields)
+			}
+		}
+	}
+
+	header.Del("Content-Length")
+	rw.ResponseWriter.WriteHeader(rw.status)
+	rw.ResponseWriter.Write(body)
+}
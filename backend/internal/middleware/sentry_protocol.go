@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"minisentry/internal/dto"
+)
+
+// Sentry protocol versions accepted from SDKs, sent as sentry_version in the X-Sentry-Auth
+// header or the query string. Requests without a version are taken as the latest.
+const (
+	MinSentryProtocolVersion = 2
+	MaxSentryProtocolVersion = 7
+)
+
+// SentryErrorResponse is the error body of ingestion endpoints. SDKs log detail, which is
+// also sent in the X-Sentry-Error header; error and message match the other API errors.
+// Events rejected by validation also list the offending fields.
+type SentryErrorResponse struct {
+	Detail  string           `json:"detail"`
+	Error   string           `json:"error"`
+	Message string           `json:"message"`
+	Status  int              `json:"status"`
+	Errors  []dto.FieldError `json:"errors,omitempty"`
+}
+
+// WriteSentryError writes an ingestion error the way Sentry does, so SDK debug output
+// shows why an event was rejected
+func WriteSentryError(w http.ResponseWriter, statusCode int, detail string) {
+	WriteSentryValidationError(w, statusCode, detail, nil)
+}
+
+// WriteSentryValidationError writes an ingestion error listing the fields that made an
+// event invalid
+func WriteSentryValidationError(w http.ResponseWriter, statusCode int, detail string, fieldErrors []dto.FieldError) {
+	w.Header().Set("X-Sentry-Error", sentryErrorHeader(detail))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	json.NewEncoder(w).Encode(SentryErrorResponse{
+		Detail:  detail,
+		Error:   http.StatusText(statusCode),
+		Message: detail,
+		Status:  statusCode,
+		Errors:  fieldErrors,
+	})
+}
+
+// sentryErrorHeader keeps a message on one line so it is a valid header value
+func sentryErrorHeader(detail string) string {
+	return strings.Join(strings.Fields(detail), " ")
+}
+
+// parseSentryAuthFields returns the key=value pairs of an X-Sentry-Auth header, nil when
+// the header is not in the Sentry format
+func parseSentryAuthFields(authHeader string) map[string]string {
+	// Format: Sentry sentry_version=7, sentry_client=..., sentry_key=PUBLIC_KEY, sentry_secret=SECRET_KEY
+	// OpenTelemetry exporters are commonly configured with a lowercase "sentry"
+	if len(authHeader) < len("Sentry ") || !strings.EqualFold(authHeader[:len("Sentry ")], "Sentry ") {
+		return nil
+	}
+	authData := authHeader[len("Sentry "):]
+
+	fields := make(map[string]string)
+	for _, pair := range strings.Split(authData, ",") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(pair), "="); ok {
+			fields[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return fields
+}
+
+// SentryProtocolVersion returns the protocol version a request was sent with, the latest
+// when it sends none, or an error when the version is not supported
+func SentryProtocolVersion(r *http.Request) (int, error) {
+	version := r.URL.Query().Get("sentry_version")
+	if fields := parseSentryAuthFields(r.Header.Get("X-Sentry-Auth")); fields["sentry_version"] != "" {
+		version = fields["sentry_version"]
+	}
+	if version == "" {
+		return MaxSentryProtocolVersion, nil
+	}
+
+	// Old SDKs send versions such as "2.0"
+	major, _, _ := strings.Cut(version, ".")
+	parsed, err := strconv.Atoi(major)
+	if err != nil || parsed < MinSentryProtocolVersion || parsed > MaxSentryProtocolVersion {
+		return 0, fmt.Errorf("Client/server version mismatch: Unsupported protocol version (%s)", version)
+	}
+	return parsed, nil
+}
+
+// SentryAuthTimestamp returns when the SDK sent a request, from the sentry_timestamp of its
+// X-Sentry-Auth header or query string, or nil when it sent none
+func SentryAuthTimestamp(r *http.Request) *time.Time {
+	timestamp := r.URL.Query().Get("sentry_timestamp")
+	if fields := parseSentryAuthFields(r.Header.Get("X-Sentry-Auth")); fields["sentry_timestamp"] != "" {
+		timestamp = fields["sentry_timestamp"]
+	}
+	if timestamp == "" {
+		return nil
+	}
+
+	// Unix seconds, with a fraction from most SDKs
+	seconds, err := strconv.ParseFloat(timestamp, 64)
+	if err != nil || seconds <= 0 {
+		return nil
+	}
+	sentAt := time.Unix(0, int64(seconds*float64(time.Second))).UTC()
+	return &sentAt
+}
@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"minisentry/internal/services"
+)
+
+// Cookies of dashboard sessions kept in cookies
+const (
+	SessionCookieName = "minisentry_session"
+	RefreshCookieName = "minisentry_refresh"
+	CSRFCookieName    = "minisentry_csrf"
+
+	// CSRFHeader carries the CSRF token on unsafe requests authenticated by cookie
+	CSRFHeader = "X-CSRF-Token"
+)
+
+// refreshCookiePath keeps the refresh token from being sent anywhere but the auth endpoints
+const refreshCookiePath = "/api/v1/auth"
+
+var ErrCSRFTokenInvalid = errors.New("missing or invalid CSRF token")
+
+// SessionCookies keeps dashboard sessions in httpOnly cookies, out of reach of scripts
+// injected into the dashboard, instead of returning the tokens for browser storage.
+// Unsafe requests authenticated by cookie must echo the session's CSRF token, readable
+// from its own cookie, in the X-CSRF-Token header. The CSRF token is derived from the
+// session's access token, so it is only good for that session.
+type SessionCookies struct {
+	sameSite      http.SameSite
+	secure        bool
+	domain        string
+	csrfKey       []byte
+	accessExpiry  time.Duration
+	refreshExpiry time.Duration
+}
+
+// NewSessionCookies creates session cookies with the given SameSite mode (strict, lax or
+// none, which requires secure cookies), signing CSRF tokens with csrfKey
+func NewSessionCookies(sameSite string, secure bool, domain, csrfKey string, accessExpiry, refreshExpiry time.Duration) (*SessionCookies, error) {
+	var mode http.SameSite
+	switch strings.ToLower(sameSite) {
+	case "strict":
+		mode = http.SameSiteStrictMode
+	case "lax", "":
+		mode = http.SameSiteLaxMode
+	case "none":
+		if !secure {
+			return nil, errors.New("SameSite=None session cookies must be secure")
+		}
+		mode = http.SameSiteNoneMode
+	default:
+		return nil, fmt.Errorf("invalid SameSite mode %q, expected strict, lax or none", sameSite)
+	}
+	if csrfKey == "" {
+		return nil, errors.New("a CSRF signing key is required")
+	}
+
+	return &SessionCookies{
+		sameSite:      mode,
+		secure:        secure,
+		domain:        domain,
+		csrfKey:       []byte(csrfKey),
+		accessExpiry:  accessExpiry,
+		refreshExpiry: refreshExpiry,
+	}, nil
+}
+
+// SetSession sets the cookies of a new or refreshed session and returns its CSRF token
+func (sc *SessionCookies) SetSession(w http.ResponseWriter, tokens *services.TokenPair) string {
+	csrfToken := sc.csrfToken(tokens.AccessToken)
+	http.SetCookie(w, sc.cookie(SessionCookieName, tokens.AccessToken, "/", sc.accessExpiry, true))
+	http.SetCookie(w, sc.cookie(RefreshCookieName, tokens.RefreshToken, refreshCookiePath, sc.refreshExpiry, true))
+	http.SetCookie(w, sc.cookie(CSRFCookieName, csrfToken, "/", sc.accessExpiry, false))
+	return csrfToken
+}
+
+// ClearSession expires the session cookies
+func (sc *SessionCookies) ClearSession(w http.ResponseWriter) {
+	http.SetCookie(w, sc.cookie(SessionCookieName, "", "/", -1, true))
+	http.SetCookie(w, sc.cookie(RefreshCookieName, "", refreshCookiePath, -1, true))
+	http.SetCookie(w, sc.cookie(CSRFCookieName, "", "/", -1, false))
+}
+
+// RefreshToken returns the refresh token cookie of a request, "" without one
+func (sc *SessionCookies) RefreshToken(r *http.Request) string {
+	if cookie, err := r.Cookie(RefreshCookieName); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// SessionToken returns the access token of a request's session cookie, "" without one,
+// or ErrCSRFTokenInvalid when an unsafe request does not carry the session's CSRF token
+func (sc *SessionCookies) SessionToken(r *http.Request) (string, error) {
+	cookie, err := r.Cookie(SessionCookieName)
+	if err != nil || cookie.Value == "" {
+		return "", nil
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return cookie.Value, nil
+	}
+	expected := sc.csrfToken(cookie.Value)
+	if !hmac.Equal([]byte(r.Header.Get(CSRFHeader)), []byte(expected)) {
+		return "", ErrCSRFTokenInvalid
+	}
+	return cookie.Value, nil
+}
+
+// DeriveCSRFKey derives a CSRF signing key from the JWT secret, for deployments without a
+// CSRF secret of their own, so CSRF tokens are never signed with the token-signing key itself
+func DeriveCSRFKey(jwtSecret string) string {
+	mac := hmac.New(sha256.New, []byte(jwtSecret))
+	mac.Write([]byte("minisentry csrf"))
+	return string(mac.Sum(nil))
+}
+
+// csrfToken derives the CSRF token of a session from its access token
+func (sc *SessionCookies) csrfToken(accessToken string) string {
+	mac := hmac.New(sha256.New, sc.csrfKey)
+	mac.Write([]byte(accessToken))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (sc *SessionCookies) cookie(name, value, path string, maxAge time.Duration, httpOnly bool) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   sc.domain,
+		HttpOnly: httpOnly,
+		Secure:   sc.secure,
+		SameSite: sc.sameSite,
+	}
+	if maxAge < 0 {
+		cookie.MaxAge = -1
+	} else {
+		cookie.MaxAge = int(maxAge.Seconds())
+	}
+	return cookie
+}
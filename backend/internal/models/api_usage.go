@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIUsageStat counts the API calls a user or ingest token made against an organization on
+// a UTC day, per route and status class, so owners can find the scripts hammering the API.
+// ProjectID is uuid.Nil for calls to organization routes.
+type APIUsageStat struct {
+	BaseModel
+	OrganizationID uuid.UUID `json:"organization_id" gorm:"not null;uniqueIndex:idx_api_usage_stats_dimensions"`
+	ProjectID      uuid.UUID `json:"project_id" gorm:"not null;uniqueIndex:idx_api_usage_stats_dimensions"`
+	CallerType     string    `json:"caller_type" gorm:"not null;size:20;uniqueIndex:idx_api_usage_stats_dimensions"` // user or ingest_token
+	CallerID       uuid.UUID `json:"caller_id" gorm:"not null;uniqueIndex:idx_api_usage_stats_dimensions"`
+	Day            time.Time `json:"day" gorm:"not null;uniqueIndex:idx_api_usage_stats_dimensions"`
+	Route          string    `json:"route" gorm:"not null;size:255;uniqueIndex:idx_api_usage_stats_dimensions"`       // method and route pattern
+	StatusClass    string    `json:"status_class" gorm:"not null;size:10;uniqueIndex:idx_api_usage_stats_dimensions"` // 2xx, 3xx, 4xx, 429 or 5xx
+	Calls          int64     `json:"calls" gorm:"not null;default:0"`
+
+	// Relationships
+	Organization Organization `json:"-" gorm:"foreignKey:OrganizationID"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// Attachment types, following the Sentry attachment_type values
+const (
+	AttachmentTypeDefault  = "event.attachment"
+	AttachmentTypeMinidump = "event.minidump"
+)
+
+// Attachment is a file uploaded with an event; its content lives in blob storage
+type Attachment struct {
+	BaseModel
+	ProjectID      uuid.UUID `json:"project_id" gorm:"not null;index"`
+	EventID        uuid.UUID `json:"event_id" gorm:"not null;index"`
+	Name           string    `json:"name" gorm:"not null;size:255"`
+	ContentType    string    `json:"content_type" gorm:"size:255"`
+	AttachmentType string    `json:"attachment_type" gorm:"not null;default:'event.attachment';size:50"`
+	Size           int64     `json:"size"`
+	StorageKey     string    `json:"-" gorm:"not null;size:500"`
+
+	// Relationships
+	Event Event `json:"-" gorm:"foreignKey:EventID"`
+}
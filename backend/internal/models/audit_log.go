@@ -0,0 +1,36 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Audit log actions
+const (
+	AuditQuotaOverrideCreated = "quota_override.created"
+	AuditQuotaOverrideRevoked = "quota_override.revoked"
+	AuditQuotaOverrideExpired = "quota_override.expired"
+
+	AuditMaintenanceStarted = "maintenance.started"
+	AuditMaintenanceEnded   = "maintenance.ended"
+
+	AuditIssueDeleted = "issue.deleted"
+
+	AuditProjectSettingChanged      = "project.setting_changed"
+	AuditOrganizationSettingChanged = "organization.setting_changed"
+)
+
+// AuditLogEntry records an administrative action. ActorID is nil for actions taken by
+// the system, such as the scheduler expiring an override; Data holds action details.
+// Setting changes name the changed setting, with its before and after values in Data.
+type AuditLogEntry struct {
+	BaseModel
+	ActorID        *uuid.UUID     `json:"actor_id" gorm:"index"`
+	Action         string         `json:"action" gorm:"not null;size:100"`
+	TargetType     string         `json:"target_type" gorm:"not null;size:50;index:idx_audit_log_entries_target"`
+	TargetID       uuid.UUID      `json:"target_id" gorm:"not null;index:idx_audit_log_entries_target"`
+	OrganizationID *uuid.UUID     `json:"organization_id" gorm:"index"`
+	ProjectID      *uuid.UUID     `json:"project_id" gorm:"index"`
+	Setting        *string        `json:"setting" gorm:"size:100;index:idx_audit_log_entries_target"`
+	Data           datatypes.JSON `json:"data" gorm:"type:jsonb;not null"`
+}
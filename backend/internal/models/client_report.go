@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ClientReport counts events an SDK discarded before sending them, aggregated per hour,
+// discard reason and data category
+type ClientReport struct {
+	BaseModel
+	ProjectID uuid.UUID `json:"project_id" gorm:"not null;uniqueIndex:idx_client_reports_bucket"`
+	Bucket    time.Time `json:"bucket" gorm:"not null;uniqueIndex:idx_client_reports_bucket"`
+	Reason    string    `json:"reason" gorm:"not null;size:100;uniqueIndex:idx_client_reports_bucket"`
+	Category  string    `json:"category" gorm:"not null;size:50;uniqueIndex:idx_client_reports_bucket"`
+	Quantity  int64     `json:"quantity" gorm:"not null;default:0"`
+
+	// Relationships
+	Project Project `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// Environment is an environment, such as production or staging, that a project's events,
+// transactions or sessions were sent from. Ingestion creates it when it first sees it, so
+// CreatedAt is when it was first seen.
+type Environment struct {
+	BaseModel
+	ProjectID uuid.UUID `json:"project_id" gorm:"not null;index:idx_environments_project_name,unique"`
+	Name      string    `json:"name" gorm:"not null;size:100;index:idx_environments_project_name,unique"`
+}
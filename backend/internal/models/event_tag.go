@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventTag is a tag of an event, copied out of the event's tags at ingestion so events and
+// issues can be filtered by tag with an index rather than by reading JSON
+type EventTag struct {
+	BaseModel
+	EventID   uuid.UUID `json:"event_id" gorm:"not null;uniqueIndex:idx_event_tags_event_key"`
+	ProjectID uuid.UUID `json:"project_id" gorm:"not null;index:idx_event_tags_project_key_value"`
+	IssueID   uuid.UUID `json:"issue_id" gorm:"not null;index"`
+	Key       string    `json:"key" gorm:"not null;size:200;uniqueIndex:idx_event_tags_event_key;index:idx_event_tags_project_key_value"`
+	Value     string    `json:"value" gorm:"not null;size:200;index:idx_event_tags_project_key_value"`
+	Timestamp time.Time `json:"timestamp" gorm:"not null"` // The event's
+
+	// Relationships
+	Event Event `json:"event,omitempty" gorm:"foreignKey:EventID"`
+}
+
+// IssueTagValue counts the stored events of an issue with a tag value, with when the value
+// was first and last seen. It is kept up to date at ingestion and rebuilt from the event
+// tags when events move between issues.
+type IssueTagValue struct {
+	BaseModel
+	ProjectID uuid.UUID `json:"project_id" gorm:"not null;index"`
+	IssueID   uuid.UUID `json:"issue_id" gorm:"not null;uniqueIndex:idx_issue_tag_values_issue_key_value"`
+	Key       string    `json:"key" gorm:"not null;size:200;uniqueIndex:idx_issue_tag_values_issue_key_value"`
+	Value     string    `json:"value" gorm:"not null;size:200;uniqueIndex:idx_issue_tag_values_issue_key_value"`
+	TimesSeen int64     `json:"times_seen" gorm:"not null;default:0"`
+	FirstSeen time.Time `json:"first_seen" gorm:"not null"`
+	LastSeen  time.Time `json:"last_seen" gorm:"not null"`
+
+	// Relationships
+	Issue Issue `json:"issue,omitempty" gorm:"foreignKey:IssueID"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventVolumeStat counts the error events a project accepted for a UTC day, environment and
+// release, so error volume can be compared across releases without scanning events.
+// Release is "" for events sent without one.
+type EventVolumeStat struct {
+	BaseModel
+	ProjectID      uuid.UUID `json:"project_id" gorm:"not null;uniqueIndex:idx_event_volume_stats_dimensions"`
+	Day            time.Time `json:"day" gorm:"not null;uniqueIndex:idx_event_volume_stats_dimensions"`
+	Environment    string    `json:"environment" gorm:"not null;size:100;uniqueIndex:idx_event_volume_stats_dimensions"`
+	ReleaseVersion string    `json:"release_version" gorm:"not null;size:100;uniqueIndex:idx_event_volume_stats_dimensions"`
+	Events         int64     `json:"events" gorm:"not null;default:0"`
+
+	// Relationships
+	Project Project `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+}
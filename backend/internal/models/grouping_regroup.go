@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Regroup job statuses
+const (
+	RegroupJobPending   = "pending"
+	RegroupJobRunning   = "running"
+	RegroupJobCompleted = "completed"
+	RegroupJobFailed    = "failed"
+)
+
+// GroupingRegroupJob recomputes the fingerprints of a project's events received since Since
+// with its grouping config version GroupingVersion, moving events whose fingerprint changed
+// to the issues they now group into. Events are processed in ID order, so a job interrupted
+// midway resumes after LastEventID.
+type GroupingRegroupJob struct {
+	BaseModel
+	ProjectID       uuid.UUID  `json:"project_id" gorm:"not null;index"`
+	RequestedByID   *uuid.UUID `json:"requested_by_id"`
+	Status          string     `json:"status" gorm:"not null;size:20;default:'pending';index"`
+	GroupingVersion int        `json:"grouping_version" gorm:"not null"`
+	Since           time.Time  `json:"since" gorm:"not null"`
+	LastEventID     *uuid.UUID `json:"last_event_id"`
+	EventsChecked   int        `json:"events_checked" gorm:"not null;default:0"`
+	EventsMoved     int        `json:"events_moved" gorm:"not null;default:0"`
+	IssuesCreated   int        `json:"issues_created" gorm:"not null;default:0"`
+	Error           *string    `json:"error" gorm:"type:text"`
+	StartedAt       *time.Time `json:"started_at"`
+	FinishedAt      *time.Time `json:"finished_at"`
+
+	// Relationships
+	Project     Project `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+	RequestedBy *User   `json:"requested_by,omitempty" gorm:"foreignKey:RequestedByID"`
+}
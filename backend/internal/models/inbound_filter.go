@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Inbound filter reasons, recorded with the counts of the events each filter discarded
+const (
+	InboundFilterBrowserExtensions = "browser-extensions"
+	InboundFilterWebCrawlers       = "web-crawlers"
+	InboundFilterLocalhost         = "localhost"
+	InboundFilterLegacyBrowsers    = "legacy-browsers"
+	InboundFilterErrorMessages     = "error-messages"
+	InboundFilterExceptionTypes    = "exception-types"
+)
+
+// ProjectInboundFilters are the filters discarding a project's error events before they
+// are grouped into issues. Projects without a row filter nothing.
+type ProjectInboundFilters struct {
+	BaseModel
+	ProjectID         uuid.UUID      `json:"project_id" gorm:"not null;uniqueIndex"`
+	BrowserExtensions bool           `json:"browser_extensions" gorm:"default:false"`
+	WebCrawlers       bool           `json:"web_crawlers" gorm:"default:false"`
+	Localhost         bool           `json:"localhost" gorm:"default:false"`
+	LegacyBrowsers    bool           `json:"legacy_browsers" gorm:"default:false"`
+	ErrorMessages     datatypes.JSON `json:"error_messages" gorm:"type:jsonb"`  // Glob or /regex/ patterns matched against event messages
+	ExceptionTypes    datatypes.JSON `json:"exception_types" gorm:"type:jsonb"` // Glob or /regex/ patterns matched against exception types
+
+	// Relationships
+	Project Project `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+}
+
+// InboundFilterStat counts the events of a project an inbound filter discarded in an hour
+type InboundFilterStat struct {
+	BaseModel
+	ProjectID uuid.UUID `json:"project_id" gorm:"not null;uniqueIndex:idx_inbound_filter_stats_bucket"`
+	Bucket    time.Time `json:"bucket" gorm:"not null;uniqueIndex:idx_inbound_filter_stats_bucket"`
+	Reason    string    `json:"reason" gorm:"not null;size:50;uniqueIndex:idx_inbound_filter_stats_bucket"`
+	Filtered  int64     `json:"filtered" gorm:"not null;default:0"`
+
+	// Relationships
+	Project Project `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+type IncidentStatus string
+type IncidentSource string
+type IncidentActivityType string
+
+const (
+	IncidentOpen          IncidentStatus = "open"
+	IncidentInvestigating IncidentStatus = "investigating"
+	IncidentMitigated     IncidentStatus = "mitigated"
+	IncidentResolved      IncidentStatus = "resolved"
+)
+
+const (
+	IncidentSourceManual     IncidentSource = "manual"
+	IncidentSourceAlertStorm IncidentSource = "alert_storm"
+)
+
+const (
+	IncidentActivityCreated      IncidentActivityType = "created"
+	IncidentActivityStatusChange IncidentActivityType = "status_change"
+	IncidentActivityIssueAdded   IncidentActivityType = "issue_added"
+	IncidentActivityIssueRemoved IncidentActivityType = "issue_removed"
+	IncidentActivityNote         IncidentActivityType = "note"
+)
+
+// Incident groups the issues of an outage so responders work from one object
+type Incident struct {
+	BaseModel
+	OrganizationID uuid.UUID      `json:"organization_id" gorm:"not null;index"`
+	Title          string         `json:"title" gorm:"not null;size:255"`
+	Description    *string        `json:"description" gorm:"type:text"`
+	Status         IncidentStatus `json:"status" gorm:"not null;default:'open';size:50;index"`
+	Source         IncidentSource `json:"source" gorm:"not null;default:'manual';size:50"`
+	StartedAt      time.Time      `json:"started_at" gorm:"not null"`
+	ResolvedAt     *time.Time     `json:"resolved_at"`
+	CreatedByID    *uuid.UUID     `json:"created_by_id"`
+
+	// Relationships
+	Organization Organization       `json:"organization,omitempty" gorm:"foreignKey:OrganizationID"`
+	Issues       []IncidentIssue    `json:"issues,omitempty" gorm:"foreignKey:IncidentID"`
+	Activities   []IncidentActivity `json:"activities,omitempty" gorm:"foreignKey:IncidentID"`
+}
+
+// IncidentIssue links an issue to an incident
+type IncidentIssue struct {
+	BaseModel
+	IncidentID uuid.UUID  `json:"incident_id" gorm:"not null;index:idx_incident_issue,unique"`
+	IssueID    uuid.UUID  `json:"issue_id" gorm:"not null;index:idx_incident_issue,unique;index"`
+	AddedByID  *uuid.UUID `json:"added_by_id"`
+
+	// Relationships
+	Incident Incident `json:"incident,omitempty" gorm:"foreignKey:IncidentID"`
+	Issue    Issue    `json:"issue,omitempty" gorm:"foreignKey:IssueID"`
+}
+
+// IncidentActivity is an entry of an incident timeline; UserID is nil for automatic entries
+type IncidentActivity struct {
+	BaseModel
+	IncidentID uuid.UUID            `json:"incident_id" gorm:"not null;index"`
+	UserID     *uuid.UUID           `json:"user_id"`
+	Type       IncidentActivityType `json:"type" gorm:"not null;size:50"`
+	Data       datatypes.JSON       `json:"data" gorm:"type:jsonb"`
+
+	// Relationships
+	Incident Incident `json:"incident,omitempty" gorm:"foreignKey:IncidentID"`
+	User     *User    `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
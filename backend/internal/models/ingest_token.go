@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrgIngestToken is an organization credential for CI systems. It only grants access to
+// the release and artifact upload endpoints of the organization's projects, never to error
+// data. Only the SHA-256 hash of the token is stored.
+type OrgIngestToken struct {
+	BaseModel
+	OrganizationID uuid.UUID  `json:"organization_id" gorm:"not null;index"`
+	Name           string     `json:"name" gorm:"not null;size:255"`
+	TokenHash      string     `json:"-" gorm:"not null;uniqueIndex;size:64"`
+	TokenPrefix    string     `json:"token_prefix" gorm:"not null;size:16"` // identifies the token in listings
+	CreatedBy      *uuid.UUID `json:"created_by"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+
+	// Relationships
+	Organization Organization `json:"-" gorm:"foreignKey:OrganizationID"`
+}
@@ -10,6 +10,7 @@ import (
 type IssueStatus string
 type IssueLevel string
 type IssueType string
+type IssuePriority string
 
 const (
 	StatusUnresolved IssueStatus = "unresolved"
@@ -25,9 +26,16 @@ const (
 	LevelFatal   IssueLevel = "fatal"
 )
 
+const (
+	PriorityLow    IssuePriority = "low"
+	PriorityMedium IssuePriority = "medium"
+	PriorityHigh   IssuePriority = "high"
+)
+
 const (
 	TypeError   IssueType = "error"
 	TypeCSP     IssueType = "csp"
+	TypeNativeCrash IssueType = "native_crash"
 	TypeDefault IssueType = "default"
 )
 
@@ -44,6 +52,32 @@ type Issue struct {
 	LastSeen    time.Time    `json:"last_seen" gorm:"default:now()"`
 	TimesSeen   int          `json:"times_seen" gorm:"default:1"`
 	AssigneeID  *uuid.UUID   `json:"assignee_id"`
+	Transaction *string      `json:"transaction" gorm:"column:transaction_name;size:200;index"` // Transaction of the first event
+	Platform    string       `json:"platform" gorm:"size:64"`                                  // Platform of the first event
+
+	// IsRegression is set when a resolved issue receives new events, and cleared when it is resolved again
+	IsRegression bool `json:"is_regression" gorm:"default:false"`
+
+	// ResolvedInRelease, for issues resolved in a release, keeps events of that release and
+	// older ones from reopening the issue
+	ResolvedInRelease *string `json:"resolved_in_release" gorm:"size:100"`
+
+	// GroupingVersion is the project grouping config version the issue was grouped with
+	GroupingVersion int `json:"grouping_version" gorm:"not null;default:1"`
+
+	// Priority starts from the level of the first event and rises with the issue's events,
+	// unless PriorityLocked because a user set it
+	Priority       IssuePriority `json:"priority" gorm:"not null;default:'medium';size:20;index"`
+	PriorityLocked bool          `json:"priority_locked" gorm:"not null;default:false"`
+
+	// DeletionRequestedAt is set on issues queued for deletion, which are hidden from issue
+	// lists until a background task deletes them
+	DeletionRequestedAt   *time.Time `json:"-" gorm:"index"`
+	DeletionRequestedByID *uuid.UUID `json:"-"`
+
+	// IgnoreCondition, on ignored issues, is when events unignore the issue: a time, a count
+	// of events or of users affected
+	IgnoreCondition datatypes.JSON `json:"-" gorm:"type:jsonb"`
 	
 	// Relationships
 	Project   Project        `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
@@ -58,12 +92,13 @@ type Event struct {
 	IssueID         uuid.UUID      `json:"issue_id" gorm:"not null;index"`
 	ProjectID       uuid.UUID      `json:"project_id" gorm:"not null;index"`
 	EventID         string         `json:"event_id" gorm:"not null;size:255;index:idx_project_event_id,unique"`
-	Timestamp       time.Time      `json:"timestamp" gorm:"default:now();index"`
+	Timestamp       time.Time      `json:"timestamp" gorm:"default:now();index"` // Corrected for client clock skew
 	Level           IssueLevel     `json:"level" gorm:"not null;default:'error';size:50"`
 	Message         *string        `json:"message" gorm:"type:text"`
 	ExceptionType   *string        `json:"exception_type" gorm:"size:255"`
 	ExceptionValue  *string        `json:"exception_value" gorm:"type:text"`
 	StackTrace      datatypes.JSON `json:"stack_trace" gorm:"type:jsonb"`
+	Exceptions      datatypes.JSON `json:"exceptions,omitempty" gorm:"type:jsonb"` // The exception chain, when the exception has causes
 	RequestData     datatypes.JSON `json:"request_data" gorm:"type:jsonb"`
 	UserContext     datatypes.JSON `json:"user_context" gorm:"type:jsonb"`
 	Tags            datatypes.JSON `json:"tags" gorm:"type:jsonb"`
@@ -72,6 +107,20 @@ type Event struct {
 	ReleaseVersion  *string        `json:"release_version" gorm:"size:100"`
 	Environment     string         `json:"environment" gorm:"default:'production';size:100"`
 	ServerName      *string        `json:"server_name" gorm:"size:255"`
+	ReplayID        *string        `json:"replay_id" gorm:"size:64"`
+	Transaction     *string        `json:"transaction" gorm:"column:transaction_name;size:200;index"` // Route the error happened on, e.g. /orders/{id}
+	Platform        string         `json:"platform" gorm:"size:64"`                                  // Sent or inferred from the SDK, stack frames or exception types
+	Contexts        datatypes.JSON `json:"contexts" gorm:"type:jsonb"`
+	Meta            datatypes.JSON `json:"_meta,omitempty" gorm:"type:jsonb"` // What the event limits trimmed, like Sentry's _meta
+
+	// OriginalTimestamp is the timestamp the SDK sent, set when clock skew was corrected
+	OriginalTimestamp *time.Time `json:"original_timestamp,omitempty"`
+
+	// GroupingVersion is the project grouping config version the fingerprint was computed
+	// with. CustomFingerprint is set when the fingerprint was sent with the event or is
+	// fixed by its type, like that of CSP reports; regrouping leaves those alone.
+	GroupingVersion   int  `json:"grouping_version" gorm:"not null;default:1"`
+	CustomFingerprint bool `json:"custom_fingerprint" gorm:"not null;default:false"`
 	
 	// Relationships
 	Issue   Issue   `json:"issue,omitempty" gorm:"foreignKey:IssueID"`
@@ -80,15 +129,92 @@ type Event struct {
 
 type IssueComment struct {
 	BaseModel
-	IssueID uuid.UUID `json:"issue_id" gorm:"not null;index"`
-	UserID  uuid.UUID `json:"user_id" gorm:"not null"`
-	Content string    `json:"content" gorm:"not null;type:text"`
+	IssueID  uuid.UUID  `json:"issue_id" gorm:"not null;index"`
+	UserID   uuid.UUID  `json:"user_id" gorm:"not null"`
+	Content  string     `json:"content" gorm:"not null;type:text"`
+	EditedAt *time.Time `json:"edited_at,omitempty"` // Set when the content is edited
+	
+	// Relationships
+	Issue     Issue                  `json:"issue,omitempty" gorm:"foreignKey:IssueID"`
+	User      User                   `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Reactions []IssueCommentReaction `json:"reactions,omitempty" gorm:"foreignKey:CommentID"`
+	Mentions  []IssueCommentMention  `json:"mentions,omitempty" gorm:"foreignKey:CommentID"`
+}
+
+// IssueCommentReaction is an emoji reaction left by a user on a comment
+type IssueCommentReaction struct {
+	BaseModel
+	CommentID uuid.UUID `json:"comment_id" gorm:"not null;index:idx_comment_user_emoji,unique"`
+	UserID    uuid.UUID `json:"user_id" gorm:"not null;index:idx_comment_user_emoji,unique"`
+	Emoji     string    `json:"emoji" gorm:"not null;size:64;index:idx_comment_user_emoji,unique"`
+	
+	// Relationships
+	Comment IssueComment `json:"comment,omitempty" gorm:"foreignKey:CommentID"`
+	User    User         `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// IssueCommentMention is a member of the issue's organization mentioned in a comment as
+// @email or @name; Text is the mention as written, without the @
+type IssueCommentMention struct {
+	BaseModel
+	CommentID uuid.UUID `json:"comment_id" gorm:"not null;index:idx_comment_mention,unique"`
+	UserID    uuid.UUID `json:"user_id" gorm:"not null;index:idx_comment_mention,unique;index"`
+	Text      string    `json:"text" gorm:"not null;size:255"`
+	
+	// Relationships
+	Comment IssueComment `json:"comment,omitempty" gorm:"foreignKey:CommentID"`
+	User    User         `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+type IssueSubscriptionReason string
+
+const (
+	SubscriptionCommented  IssueSubscriptionReason = "commented"
+	SubscriptionAssigned   IssueSubscriptionReason = "assigned"
+	SubscriptionSubscribed IssueSubscriptionReason = "subscribed"
+	SubscriptionMentioned  IssueSubscriptionReason = "mentioned"
+)
+
+// IssueSubscription makes a user a participant of an issue, notified when its status
+// changes. Users take part by commenting, being mentioned, being assigned or subscribing;
+// unsubscribing keeps an inactive subscription so that taking part again does not subscribe
+// them back.
+type IssueSubscription struct {
+	BaseModel
+	IssueID  uuid.UUID               `json:"issue_id" gorm:"not null;index:idx_issue_subscription,unique"`
+	UserID   uuid.UUID               `json:"user_id" gorm:"not null;index:idx_issue_subscription,unique;index"`
+	IsActive bool                    `json:"is_active" gorm:"not null"`
+	Reason   IssueSubscriptionReason `json:"reason" gorm:"not null;size:32"` // how the user first took part
 	
 	// Relationships
 	Issue Issue `json:"issue,omitempty" gorm:"foreignKey:IssueID"`
 	User  User  `json:"user,omitempty" gorm:"foreignKey:UserID"`
 }
 
+type IssueRelationType string
+
+const (
+	RelationDuplicateOf IssueRelationType = "duplicate_of"
+	RelationBlockedBy   IssueRelationType = "blocked_by"
+	RelationRelated     IssueRelationType = "related"
+)
+
+// IssueRelation links an issue to another issue of the same organization, e.g. an
+// upstream failure blocking a downstream one. "related" is symmetric; the other types read
+// as "source <type> target".
+type IssueRelation struct {
+	BaseModel
+	SourceIssueID uuid.UUID         `json:"source_issue_id" gorm:"not null;index:idx_issue_relation,unique"`
+	TargetIssueID uuid.UUID         `json:"target_issue_id" gorm:"not null;index:idx_issue_relation,unique;index"`
+	Type          IssueRelationType `json:"type" gorm:"not null;size:50;index:idx_issue_relation,unique"`
+	CreatedByID   *uuid.UUID        `json:"created_by_id"`
+	
+	// Relationships
+	SourceIssue Issue `json:"source_issue,omitempty" gorm:"foreignKey:SourceIssueID"`
+	TargetIssue Issue `json:"target_issue,omitempty" gorm:"foreignKey:TargetIssueID"`
+	CreatedBy   *User `json:"created_by,omitempty" gorm:"foreignKey:CreatedByID"`
+}
+
 type ActivityType string
 
 const (
@@ -97,8 +223,51 @@ const (
 	ActivityComment      ActivityType = "comment"
 	ActivityResolve      ActivityType = "resolve"
 	ActivityIgnore       ActivityType = "ignore"
+	ActivityRelation     ActivityType = "relation"
+	ActivityMerge        ActivityType = "merge"
+	ActivityUnmerge      ActivityType = "unmerge"
+	ActivityRegression   ActivityType = "regression"
+	ActivityFingerprintRule ActivityType = "fingerprint_rule"
+	ActivityUnignore     ActivityType = "unignore"
+	ActivityPriority     ActivityType = "priority"
+	ActivityCommentEdited  ActivityType = "comment_edited"
+	ActivityCommentDeleted ActivityType = "comment_deleted"
 )
 
+// IssueMergedFingerprint routes the events of a fingerprint to the issue it was merged into.
+// MergedIssueID and Title are those of the issue the fingerprint had before the merge.
+type IssueMergedFingerprint struct {
+	BaseModel
+	ProjectID     uuid.UUID  `json:"project_id" gorm:"not null;index:idx_issue_merged_fingerprint,unique"`
+	Fingerprint   string     `json:"fingerprint" gorm:"not null;size:255;index:idx_issue_merged_fingerprint,unique"`
+	IssueID       uuid.UUID  `json:"issue_id" gorm:"not null;index"`
+	MergedIssueID uuid.UUID  `json:"merged_issue_id" gorm:"not null"`
+	Title         string     `json:"title" gorm:"not null;size:500"`
+	MergedByID    *uuid.UUID `json:"merged_by_id"`
+	
+	// Relationships
+	Issue    Issue `json:"issue,omitempty" gorm:"foreignKey:IssueID"`
+	MergedBy *User `json:"merged_by,omitempty" gorm:"foreignKey:MergedByID"`
+}
+
+// IssueFingerprintRule folds the future events of a project matching its patterns into an
+// issue, whatever fingerprint they would get otherwise. Key names the grouping within the
+// project; every pattern set must match, and unset ones match any event.
+type IssueFingerprintRule struct {
+	BaseModel
+	ProjectID     uuid.UUID  `json:"project_id" gorm:"not null;index:idx_issue_fingerprint_rule_key,unique"`
+	IssueID       uuid.UUID  `json:"issue_id" gorm:"not null;uniqueIndex"`
+	Key           string     `json:"key" gorm:"not null;size:200;index:idx_issue_fingerprint_rule_key,unique"`
+	ExceptionType *string    `json:"exception_type" gorm:"size:500"` // Glob or /regex/ pattern matched against the exception type
+	Message       *string    `json:"message" gorm:"size:500"`        // Glob or /regex/ pattern matched against the message or exception value
+	Transaction   *string    `json:"transaction" gorm:"column:transaction_name;size:500"` // Glob or /regex/ pattern matched against the transaction
+	CreatedByID   *uuid.UUID `json:"created_by_id"`
+
+	// Relationships
+	Issue     Issue `json:"issue,omitempty" gorm:"foreignKey:IssueID"`
+	CreatedBy *User `json:"created_by,omitempty" gorm:"foreignKey:CreatedByID"`
+}
+
 type IssueActivity struct {
 	BaseModel
 	IssueID uuid.UUID      `json:"issue_id" gorm:"not null;index"`
@@ -113,11 +282,27 @@ type IssueActivity struct {
 
 type Release struct {
 	BaseModel
-	ProjectID    uuid.UUID  `json:"project_id" gorm:"not null;index"`
+	ProjectID    uuid.UUID  `json:"project_id" gorm:"not null;index;index:idx_project_version,unique;index:idx_releases_semver,priority:1"`
 	Version      string     `json:"version" gorm:"not null;size:100;index:idx_project_version,unique"`
 	Ref          *string    `json:"ref" gorm:"size:255"`
 	URL          *string    `json:"url" gorm:"size:500"`
 	DateReleased *time.Time `json:"date_released"`
+
+	// Comparable components parsed from Version; the semver ones are only set for semver versions
+	Package          string `json:"package" gorm:"size:100;default:''"`
+	VersionFormat    string `json:"version_format" gorm:"size:20;default:''"` // semver, build, commit or other; empty until parsed
+	SemverMajor      int64  `json:"semver_major" gorm:"default:0;index:idx_releases_semver,priority:2"`
+	SemverMinor      int64  `json:"semver_minor" gorm:"default:0;index:idx_releases_semver,priority:3"`
+	SemverPatch      int64  `json:"semver_patch" gorm:"default:0;index:idx_releases_semver,priority:4"`
+	SemverRevision   int64  `json:"semver_revision" gorm:"default:0;index:idx_releases_semver,priority:5"`
+	SemverPrerelease string `json:"semver_prerelease" gorm:"size:100;default:''"`
+	SemverFinal      bool   `json:"semver_final" gorm:"default:false;index:idx_releases_semver,priority:6"` // No prerelease; sorts after the prereleases of its version
+	BuildCode        string `json:"build_code" gorm:"size:100;default:''"`
+	BuildNumber      *int64 `json:"build_number"`
+	
+	// SemverPrereleaseKey orders the prereleases of semver versions like semver when
+	// compared as bytes
+	SemverPrereleaseKey string `json:"-" gorm:"default:'';index:idx_releases_semver,priority:7"`
 	
 	// Relationships
 	Project Project `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IssueOutcomeStat counts the error events of a fingerprint that were not stored as events
+// of its issue on a UTC day, so the issue can show how much times_seen and its events
+// understate. Rate limited events are rejected before they are grouped and are counted for
+// the whole project, with an empty fingerprint.
+type IssueOutcomeStat struct {
+	BaseModel
+	ProjectID   uuid.UUID `json:"project_id" gorm:"not null;uniqueIndex:idx_issue_outcome_stats_dimensions"`
+	Fingerprint string    `json:"fingerprint" gorm:"not null;size:255;uniqueIndex:idx_issue_outcome_stats_dimensions"`
+	Outcome     string    `json:"outcome" gorm:"not null;size:50;uniqueIndex:idx_issue_outcome_stats_dimensions"` // duplicate, sampled or rate_limited
+	Day         time.Time `json:"day" gorm:"not null;uniqueIndex:idx_issue_outcome_stats_dimensions"`
+	Events      int64     `json:"events" gorm:"not null;default:0"`
+
+	// Relationships
+	Project Project `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Issue sync delta types
+const (
+	IssueSyncCreated   = "issue.created"
+	IssueSyncUpdated   = "issue.updated"
+	IssueSyncResolved  = "issue.resolved"
+	IssueSyncAssigned  = "issue.assigned"
+	IssueSyncRegressed = "issue.regressed"
+	IssueSyncDeleted   = "issue.deleted"
+)
+
+// IssueSyncIntegration streams issue state changes of a project to an external system.
+// Deltas are numbered per project; consumers pull them by cursor or receive them on URL.
+type IssueSyncIntegration struct {
+	BaseModel
+	ProjectID         uuid.UUID  `json:"project_id" gorm:"not null;uniqueIndex"`
+	URL               *string    `json:"url" gorm:"size:2048"` // nil for pull-only integrations
+	Secret            string     `json:"-" gorm:"not null;size:64"`
+	Enabled           bool       `json:"enabled" gorm:"not null"`
+	LastSequence      int64      `json:"last_sequence" gorm:"not null;default:0"`
+	DeliveredSequence int64      `json:"delivered_sequence" gorm:"not null;default:0"`
+	FailureCount      int        `json:"failure_count" gorm:"not null;default:0"`
+	LastError         *string    `json:"last_error" gorm:"type:text"`
+	LastDeliveryAt    *time.Time `json:"last_delivery_at"`
+	NextAttemptAt     *time.Time `json:"next_attempt_at"`
+
+	// Relationships
+	Project Project `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+}
+
+// IssueSyncDelta is a recorded issue state change; Payload holds the delta as sent to consumers
+type IssueSyncDelta struct {
+	BaseModel
+	ProjectID uuid.UUID      `json:"project_id" gorm:"not null;uniqueIndex:idx_issue_sync_sequence"`
+	Sequence  int64          `json:"sequence" gorm:"not null;uniqueIndex:idx_issue_sync_sequence"`
+	IssueID   uuid.UUID      `json:"issue_id" gorm:"not null;index"`
+	Type      string         `json:"type" gorm:"not null;size:50"`
+	Payload   datatypes.JSON `json:"payload" gorm:"type:jsonb;not null"`
+}
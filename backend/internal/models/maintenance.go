@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// How ingestion behaves during a maintenance window
+const (
+	MaintenanceIngestionAccept = "accept" // events are ingested as usual
+	MaintenanceIngestionBuffer = "buffer" // events are queued and processed once the window ends
+	MaintenanceIngestionReject = "reject" // events are rejected with 503 for SDKs to retry
+)
+
+// MaintenanceWindow puts the API into read-only mode for database maintenance, for one
+// organization or, without OrganizationID, the whole instance. EndedAt is set when the
+// window is ended through the admin API.
+type MaintenanceWindow struct {
+	BaseModel
+	OrganizationID *uuid.UUID `json:"organization_id" gorm:"index"`
+	Message        string     `json:"message" gorm:"type:text;not null"`
+	Ingestion      string     `json:"ingestion" gorm:"not null;size:20;default:'accept'"`
+	StartedBy      *uuid.UUID `json:"started_by"`
+	EndedAt        *time.Time `json:"ended_at"`
+	EndedBy        *uuid.UUID `json:"ended_by"`
+
+	// Relationships
+	Organization *Organization `json:"organization,omitempty" gorm:"foreignKey:OrganizationID"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Outbox message kinds
+const (
+	OutboxEmail   = "email"
+	OutboxWebhook = "webhook"
+)
+
+// Outbox message statuses
+const (
+	OutboxPending = "pending"
+	OutboxSent    = "sent"
+	OutboxFailed  = "failed" // gave up after the maximum number of attempts
+)
+
+// OutboxMessage is an external side effect waiting to be delivered. It is written in the
+// transaction of the change that causes it, so it exists exactly when the change does;
+// IdempotencyKey deduplicates retries of that change.
+type OutboxMessage struct {
+	BaseModel
+	Kind           string         `json:"kind" gorm:"not null;size:50"`
+	IdempotencyKey string         `json:"idempotency_key" gorm:"not null;size:255;uniqueIndex"`
+	Payload        datatypes.JSON `json:"payload" gorm:"type:jsonb;not null"`
+	Status         string         `json:"status" gorm:"not null;size:20;default:pending"`
+	Attempts       int            `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at" gorm:"not null;index"`
+	LockedUntil    *time.Time     `json:"locked_until"`
+	LastError      *string        `json:"last_error" gorm:"type:text"`
+	SentAt         *time.Time     `json:"sent_at"`
+}
@@ -1,22 +1,57 @@
 package models
 
 import (
+	"encoding/json"
+
 	"github.com/google/uuid"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
 type Project struct {
 	BaseModel
-	OrganizationID uuid.UUID `json:"organization_id" gorm:"not null;index"`
-	Name           string    `json:"name" gorm:"not null;size:255"`
-	Slug           string    `json:"slug" gorm:"not null;size:100;index:idx_org_project_slug,unique"`
-	Description    *string   `json:"description" gorm:"type:text"`
-	Platform       string    `json:"platform" gorm:"not null;default:'javascript';size:50"`
-	DSN            string    `json:"dsn" gorm:"uniqueIndex;not null;size:255"`
-	PublicKey      string    `json:"public_key" gorm:"not null;size:255"`
-	SecretKey      string    `json:"-" gorm:"not null;size:255"` // Hidden from JSON
-	IsActive       bool      `json:"is_active" gorm:"default:true"`
-	
+	OrganizationID   uuid.UUID `json:"organization_id" gorm:"not null;index"`
+	Name             string    `json:"name" gorm:"not null;size:255"`
+	Slug             string    `json:"slug" gorm:"not null;size:100;index:idx_org_project_slug,unique"`
+	Description      *string   `json:"description" gorm:"type:text"`
+	Platform         string    `json:"platform" gorm:"not null;default:'javascript';size:50"`
+	DSN              string    `json:"dsn" gorm:"uniqueIndex;not null;size:255"`
+	PublicKey        string    `json:"public_key" gorm:"not null;size:255"`
+	SecretKey        string    `json:"-" gorm:"not null;size:255"` // Hidden from JSON
+	IsActive         bool      `json:"is_active" gorm:"default:true"`
+	Runbook          *string   `json:"runbook" gorm:"type:text"`                // Markdown debugging notes shown with the project's issues
+	MaxEventSize     *int      `json:"max_event_size"`                          // Bytes; nil uses the server limit
+	RequireSecretKey bool      `json:"require_secret_key" gorm:"default:false"` // Reject ingestion without the secret key
+
+	// Events of an issue stored per hour before only one in EventSampleRate is stored (10
+	// when nil); issue counters still count every event. nil stores every event.
+	EventSampleThreshold *int `json:"event_sample_threshold"`
+	EventSampleRate      *int `json:"event_sample_rate"`
+
+	// CIDR ranges events may be sent from, as JSON arrays. An empty allow list allows all
+	// addresses; the deny list takes precedence over it.
+	IPAllowList datatypes.JSON `json:"ip_allow_list" gorm:"type:jsonb"`
+	IPDenyList  datatypes.JSON `json:"ip_deny_list" gorm:"type:jsonb"`
+
+	// Data scrubbing rules applied to events on top of the server defaults, as a JSON array
+	// of {selector, action}
+	ScrubbingRules datatypes.JSON `json:"scrubbing_rules" gorm:"type:jsonb"`
+
+	// Ownership rules assigning new issues to organization members, as a JSON array of
+	// {type, key, pattern, owner_id} evaluated in order
+	OwnershipRules datatypes.JSON `json:"ownership_rules" gorm:"type:jsonb"`
+
+	// Tag, extra data or user keys whose values in the latest event are shown in the issue
+	// list, as a JSON array
+	PinnedContextKeys datatypes.JSON `json:"pinned_context_keys" gorm:"type:jsonb"`
+
+	// How events are grouped into issues, as a JSON object of the grouping options; unset
+	// uses the default grouping
+	GroupingConfig datatypes.JSON `json:"grouping_config" gorm:"type:jsonb"`
+	// GroupingVersion counts the changes of GroupingConfig; events and issues record the
+	// version they were grouped with
+	GroupingVersion int `json:"grouping_version" gorm:"not null;default:1"`
+
 	// Relationships
 	Organization Organization `json:"organization,omitempty" gorm:"foreignKey:OrganizationID"`
 	Issues       []Issue      `json:"issues,omitempty" gorm:"foreignKey:ProjectID"`
@@ -24,6 +59,15 @@ type Project struct {
 	Releases     []Release    `json:"releases,omitempty" gorm:"foreignKey:ProjectID"`
 }
 
+// ProjectIPRanges decodes one of a project's IP lists
+func ProjectIPRanges(list datatypes.JSON) []string {
+	ranges := []string{}
+	if len(list) > 0 {
+		json.Unmarshal(list, &ranges)
+	}
+	return ranges
+}
+
 // BeforeCreate generates ID before creating the project (keys and DSN are handled by service)
 func (p *Project) BeforeCreate(tx *gorm.DB) error {
 	// Call parent BeforeCreate to generate ID
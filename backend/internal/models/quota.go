@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Quota override kinds
+const (
+	QuotaRateLimit  = "rate_limit"  // ingestion requests per rate limit window
+	QuotaDailyQuota = "daily_quota" // ingestion requests per UTC day
+)
+
+// QuotaOverride temporarily replaces a project's ingestion rate limit or daily quota.
+// A value of 0 lifts the limit. EndedAt is set when the override expires or is revoked.
+type QuotaOverride struct {
+	BaseModel
+	ProjectID uuid.UUID  `json:"project_id" gorm:"not null;index"`
+	Kind      string     `json:"kind" gorm:"not null;size:50"`
+	Value     int        `json:"value" gorm:"not null"`
+	Reason    *string    `json:"reason" gorm:"type:text"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	EndedAt   *time.Time `json:"ended_at"`
+	CreatedBy *uuid.UUID `json:"created_by"`
+
+	// Relationships
+	Project Project `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+}
+
+// Active reports whether the override applies at the given time
+func (o *QuotaOverride) Active(now time.Time) bool {
+	return o.EndedAt == nil && now.Before(o.ExpiresAt)
+}
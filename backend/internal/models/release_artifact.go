@@ -0,0 +1,23 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// ReleaseArtifact is a file uploaded for a release, typically a source map or minified
+// source; its content lives in blob storage. Name is the URL or ~/ path the SDK reports
+// for the file, and Dist optionally distinguishes builds of the same release.
+type ReleaseArtifact struct {
+	BaseModel
+	ReleaseID   uuid.UUID `json:"release_id" gorm:"not null;index"`
+	ProjectID   uuid.UUID `json:"project_id" gorm:"not null;index"`
+	Name        string    `json:"name" gorm:"not null;size:500"`
+	Dist        *string   `json:"dist" gorm:"size:64"`
+	ContentType string    `json:"content_type" gorm:"size:255"`
+	Size        int64     `json:"size"`
+	Checksum    string    `json:"checksum" gorm:"size:40"` // SHA-1 of the content
+	StorageKey  string    `json:"-" gorm:"not null;size:500"`
+
+	// Relationships
+	Release Release `json:"-" gorm:"foreignKey:ReleaseID"`
+}
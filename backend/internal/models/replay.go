@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Replay holds the metadata of a session replay. Recordings themselves are not
+// stored; only enough is kept to link replays with the events they captured.
+type Replay struct {
+	BaseModel
+	ProjectID      uuid.UUID      `json:"project_id" gorm:"not null;index"`
+	ReplayID       string         `json:"replay_id" gorm:"not null;size:64;index:idx_project_replay_id,unique"`
+	ReplayType     *string        `json:"replay_type" gorm:"size:50"`
+	StartedAt      *time.Time     `json:"started_at"`
+	FinishedAt     *time.Time     `json:"finished_at"`
+	SegmentCount   int            `json:"segment_count" gorm:"default:0"`
+	Environment    string         `json:"environment" gorm:"default:'production';size:100"`
+	ReleaseVersion *string        `json:"release_version" gorm:"size:100"`
+	Platform       *string        `json:"platform" gorm:"size:50"`
+	URLs           datatypes.JSON `json:"urls" gorm:"type:jsonb"`
+	TraceIDs       datatypes.JSON `json:"trace_ids" gorm:"type:jsonb"`
+
+	// Relationships
+	Project  Project         `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+	Segments []ReplaySegment `json:"segments,omitempty" gorm:"foreignKey:ReplayID"`
+}
+
+// ReplaySegment records one uploaded segment of a replay
+type ReplaySegment struct {
+	BaseModel
+	ReplayID      uuid.UUID `json:"replay_id" gorm:"not null;index:idx_replay_segment,unique"`
+	SegmentID     int       `json:"segment_id" gorm:"not null;index:idx_replay_segment,unique"`
+	Timestamp     time.Time `json:"timestamp" gorm:"default:now()"`
+	RecordingSize int       `json:"recording_size" gorm:"default:0"`
+
+	// Relationships
+	Replay Replay `json:"replay,omitempty" gorm:"foreignKey:ReplayID"`
+}
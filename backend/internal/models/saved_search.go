@@ -0,0 +1,23 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// SavedSearch is a named set of issue list filters. Personal searches belong to their owner
+// and one project; organization searches, without an owner, are shared with every member of
+// the organization, for one project or for all of them.
+type SavedSearch struct {
+	BaseModel
+	OrganizationID uuid.UUID      `json:"organization_id" gorm:"not null;index"`
+	ProjectID      *uuid.UUID     `json:"project_id" gorm:"index"` // nil for organization searches of every project
+	OwnerID        *uuid.UUID     `json:"owner_id" gorm:"index"`   // nil for organization searches
+	Name           string         `json:"name" gorm:"not null;size:100"`
+	Filters        datatypes.JSON `json:"filters" gorm:"type:jsonb"` // dto.IssueFilters
+	CreatedByID    *uuid.UUID     `json:"created_by_id"`
+
+	// Relationships
+	Organization Organization `json:"organization,omitempty" gorm:"foreignKey:OrganizationID"`
+	Project      *Project     `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SessionStatus string
+
+const (
+	SessionOk       SessionStatus = "ok"
+	SessionExited   SessionStatus = "exited"
+	SessionErrored  SessionStatus = "errored"
+	SessionCrashed  SessionStatus = "crashed"
+	SessionAbnormal SessionStatus = "abnormal"
+)
+
+// Session stores release health data reported by SDKs.
+// Individual sessions are keyed by SessionID and updated in place; pre-aggregated
+// session buckets have no SessionID and carry their count in Quantity.
+type Session struct {
+	BaseModel
+	ProjectID   uuid.UUID     `json:"project_id" gorm:"not null;index"`
+	SessionID   *string       `json:"session_id" gorm:"size:255;index"`
+	DistinctID  *string       `json:"distinct_id" gorm:"size:255"`
+	Release     string        `json:"release" gorm:"not null;size:100"`
+	Environment string        `json:"environment" gorm:"default:'production';size:100"`
+	Status      SessionStatus `json:"status" gorm:"not null;size:50"`
+	Errors      int           `json:"errors" gorm:"default:0"`
+	Sequence    int64         `json:"sequence" gorm:"default:0"`
+	Duration    *float64      `json:"duration"`
+	Quantity    int           `json:"quantity" gorm:"default:1"`
+	Started     time.Time     `json:"started"`
+	Timestamp   time.Time     `json:"timestamp" gorm:"default:now()"`
+
+	// Relationships
+	Project Project `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+}
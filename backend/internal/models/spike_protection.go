@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SpikeProtectionDrop counts the events of a project spike protection dropped in an hour
+type SpikeProtectionDrop struct {
+	BaseModel
+	ProjectID uuid.UUID `json:"project_id" gorm:"not null;uniqueIndex:idx_spike_protection_drops_bucket"`
+	Bucket    time.Time `json:"bucket" gorm:"not null;uniqueIndex:idx_spike_protection_drops_bucket"`
+	Dropped   int64     `json:"dropped" gorm:"not null;default:0"`
+
+	// Relationships
+	Project Project `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatusPage is the public status page of an organization, fed by its open incidents
+type StatusPage struct {
+	BaseModel
+	OrganizationID uuid.UUID `json:"organization_id" gorm:"not null;uniqueIndex"`
+	Enabled        bool      `json:"enabled" gorm:"not null;default:false"`
+	Title          string    `json:"title" gorm:"not null;size:255"`
+	Description    *string   `json:"description" gorm:"type:text"`
+
+	// Relationships
+	Organization Organization `json:"organization,omitempty" gorm:"foreignKey:OrganizationID"`
+}
+
+// StatusComponent is a customer-facing part of the service shown on the status page.
+// Incidents affect a component through the issues of the projects mapped to it.
+type StatusComponent struct {
+	BaseModel
+	OrganizationID uuid.UUID `json:"organization_id" gorm:"not null;index"`
+	Name           string    `json:"name" gorm:"not null;size:255"`
+	Description    *string   `json:"description" gorm:"type:text"`
+	Position       int       `json:"position" gorm:"not null;default:0"`
+
+	// Relationships
+	Organization Organization             `json:"organization,omitempty" gorm:"foreignKey:OrganizationID"`
+	Projects     []StatusComponentProject `json:"projects,omitempty" gorm:"foreignKey:ComponentID"`
+}
+
+// StatusComponentProject maps a project to the status component it is part of
+type StatusComponentProject struct {
+	BaseModel
+	ComponentID uuid.UUID `json:"component_id" gorm:"not null;index"`
+	ProjectID   uuid.UUID `json:"project_id" gorm:"not null;uniqueIndex"`
+
+	// Relationships
+	Component StatusComponent `json:"component,omitempty" gorm:"foreignKey:ComponentID"`
+	Project   Project         `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+}
+
+// StatusSubscriber receives status page updates by email once the address is confirmed
+type StatusSubscriber struct {
+	BaseModel
+	OrganizationID uuid.UUID  `json:"organization_id" gorm:"not null;uniqueIndex:idx_status_subscriber_email"`
+	Email          string     `json:"email" gorm:"not null;size:255;uniqueIndex:idx_status_subscriber_email"`
+	Token          string     `json:"-" gorm:"not null;size:64;uniqueIndex"`
+	ConfirmedAt    *time.Time `json:"confirmed_at"`
+
+	// Relationships
+	Organization Organization `json:"organization,omitempty" gorm:"foreignKey:OrganizationID"`
+}
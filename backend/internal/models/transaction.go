@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Transaction is a performance event: a named, timed operation made up of spans
+type Transaction struct {
+	BaseModel
+	ProjectID      uuid.UUID      `json:"project_id" gorm:"not null;index"`
+	EventID        string         `json:"event_id" gorm:"not null;size:255;index:idx_project_transaction_event_id,unique"`
+	Name           string         `json:"name" gorm:"not null;size:500;index"`
+	Op             *string        `json:"op" gorm:"size:255"`
+	Status         *string        `json:"status" gorm:"size:100"`
+	TraceID        string         `json:"trace_id" gorm:"not null;size:64;index"`
+	SpanID         *string        `json:"span_id" gorm:"size:32"`
+	StartTimestamp time.Time      `json:"start_timestamp"`
+	Timestamp      time.Time      `json:"timestamp" gorm:"default:now();index"`
+	DurationMs     float64        `json:"duration_ms"`
+	Environment    string         `json:"environment" gorm:"default:'production';size:100"`
+	ReleaseVersion *string        `json:"release_version" gorm:"size:100"`
+	Platform       *string        `json:"platform" gorm:"size:50"`
+	Tags           datatypes.JSON `json:"tags" gorm:"type:jsonb"`
+	Measurements   datatypes.JSON `json:"measurements" gorm:"type:jsonb"`
+
+	// Relationships
+	Project Project `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+	Spans   []Span  `json:"spans,omitempty" gorm:"foreignKey:TransactionID"`
+}
+
+// Span is a single timed operation inside a transaction
+type Span struct {
+	BaseModel
+	TransactionID  uuid.UUID      `json:"transaction_id" gorm:"not null;index"`
+	ProjectID      uuid.UUID      `json:"project_id" gorm:"not null"`
+	SpanID         string         `json:"span_id" gorm:"not null;size:32"`
+	ParentSpanID   *string        `json:"parent_span_id" gorm:"size:32"`
+	Op             *string        `json:"op" gorm:"size:255"`
+	Description    *string        `json:"description" gorm:"type:text"`
+	Status         *string        `json:"status" gorm:"size:100"`
+	StartTimestamp time.Time      `json:"start_timestamp"`
+	Timestamp      time.Time      `json:"timestamp"`
+	DurationMs     float64        `json:"duration_ms"`
+	Tags           datatypes.JSON `json:"tags" gorm:"type:jsonb"`
+	Data           datatypes.JSON `json:"data" gorm:"type:jsonb"`
+
+	// Relationships
+	Transaction Transaction `json:"transaction,omitempty" gorm:"foreignKey:TransactionID"`
+}
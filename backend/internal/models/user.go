@@ -14,6 +14,7 @@ type User struct {
 	AvatarURL     *string   `json:"avatar_url" gorm:"size:500"`
 	IsActive      bool      `json:"is_active" gorm:"default:true"`
 	EmailVerified bool      `json:"email_verified" gorm:"default:false"`
+	IsSuperuser   bool      `json:"is_superuser" gorm:"not null;default:false"`
 }
 
 // UserResponse represents user data returned to clients (without sensitive fields)
@@ -24,6 +25,7 @@ type UserResponse struct {
 	AvatarURL     *string   `json:"avatar_url"`
 	IsActive      bool      `json:"is_active"`
 	EmailVerified bool      `json:"email_verified"`
+	IsSuperuser   bool      `json:"is_superuser"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 }
@@ -37,6 +39,7 @@ func (u *User) ToResponse() UserResponse {
 		AvatarURL:     u.AvatarURL,
 		IsActive:      u.IsActive,
 		EmailVerified: u.EmailVerified,
+		IsSuperuser:   u.IsSuperuser,
 		CreatedAt:     u.CreatedAt,
 		UpdatedAt:     u.UpdatedAt,
 	}
@@ -0,0 +1,277 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultIngestQueueKey is the Redis list queued events are pushed to
+	DefaultIngestQueueKey = "minisentry:ingest"
+
+	// maxIngestAttempts bounds how often a failing event is retried before it is dropped
+	maxIngestAttempts = 3
+
+	// ingestPollTimeout is how long a worker blocks waiting for a job before checking for shutdown
+	ingestPollTimeout = 2 * time.Second
+
+	// redisConsumerHeartbeat is how often a process running Redis queue workers tells it is
+	// alive and reaps the jobs of processes that stopped; redisConsumerTTL is how long after
+	// its last heartbeat a process is taken for dead
+	redisConsumerHeartbeat = 10 * time.Second
+	redisConsumerTTL       = 30 * time.Second
+)
+
+// IngestJob is an error event accepted by the store endpoint and waiting to be processed
+type IngestJob struct {
+	ProjectID  uuid.UUID       `json:"project_id"`
+	Event      json.RawMessage `json:"event"`
+	ClientIP   string          `json:"client_ip"`
+	UserAgent  string          `json:"user_agent"`
+	EnqueuedAt time.Time       `json:"enqueued_at"`
+	Attempts   int             `json:"attempts"`
+}
+
+// IngestHandler processes a queued event; returning an error retries the job
+type IngestHandler func(job *IngestJob) error
+
+// RedisIngestQueue is a FIFO queue of ingestion jobs stored in a Redis list.
+// Jobs are pushed on the left and moved from the right by worker goroutines to
+// a processing list of their own, from which they are removed once handled.
+// Jobs left in the processing lists of a process that stopped sending
+// heartbeats, e.g. because it crashed, are put back in the queue by the other
+// processes.
+type RedisIngestQueue struct {
+	client *RedisClient
+	key    string
+	wg     sync.WaitGroup
+}
+
+// NewRedisIngestQueue creates an ingestion queue on the given Redis list
+func NewRedisIngestQueue(client *RedisClient, key string) *RedisIngestQueue {
+	if key == "" {
+		key = DefaultIngestQueueKey
+	}
+	return &RedisIngestQueue{client: client, key: key}
+}
+
+// Enqueue adds a job to the queue
+func (q *RedisIngestQueue) Enqueue(job *IngestJob) error {
+	if job.EnqueuedAt.IsZero() {
+		job.EnqueuedAt = time.Now()
+	}
+
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode ingest job: %w", err)
+	}
+	if _, err := q.client.LPush(q.key, string(payload)); err != nil {
+		return fmt.Errorf("failed to enqueue event: %w", err)
+	}
+	return nil
+}
+
+// Len returns the number of jobs waiting in the queue
+func (q *RedisIngestQueue) Len() (int64, error) {
+	return q.client.LLen(q.key)
+}
+
+// Start runs the given number of workers until the context is cancelled
+func (q *RedisIngestQueue) Start(ctx context.Context, workers int, handler IngestHandler) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	consumer := uuid.NewString()
+	q.heartbeat(consumer)
+
+	var running sync.WaitGroup
+	lists := make([]string, workers)
+	for i := range lists {
+		lists[i] = fmt.Sprintf("%s:processing:%s:%d", q.key, consumer, i)
+		if _, err := q.client.Do("HSET", q.processingKey(), lists[i], consumer); err != nil {
+			log.Printf("Ingest queue: failed to register processing list %s: %v", lists[i], err)
+		}
+		running.Add(1)
+		q.wg.Add(1)
+		go func(list string) {
+			defer running.Done()
+			q.work(ctx, list, handler)
+		}(lists[i])
+	}
+
+	q.wg.Add(1)
+	go q.maintain(ctx, consumer, lists, &running)
+}
+
+// Wait blocks until all workers have stopped
+func (q *RedisIngestQueue) Wait() {
+	q.wg.Wait()
+}
+
+// Close closes the Redis connections
+func (q *RedisIngestQueue) Close() error {
+	return q.client.Close()
+}
+
+// work handles jobs until the context is cancelled, keeping the job being handled in list
+func (q *RedisIngestQueue) work(ctx context.Context, list string, handler IngestHandler) {
+	defer q.wg.Done()
+
+	for ctx.Err() == nil {
+		payload, err := q.client.BLMove(q.key, list, ingestPollTimeout)
+		if errors.Is(err, ErrNil) {
+			continue
+		}
+		if err != nil {
+			log.Printf("Ingest queue: failed to read from Redis: %v", err)
+			// Back off so an unreachable Redis does not spin the worker
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		var job IngestJob
+		if err := json.Unmarshal([]byte(payload), &job); err != nil {
+			log.Printf("Ingest queue: dropping malformed job: %v", err)
+		} else {
+			processJob(q, &job, handler)
+		}
+
+		if _, err := q.client.Do("LREM", list, "1", payload); err != nil {
+			log.Printf("Ingest queue: failed to remove a handled job from %s, it will be handled again: %v", list, err)
+		}
+	}
+}
+
+// maintain sends the heartbeats of the process and reaps the processing lists of stopped
+// processes until the workers of the process have stopped, then unregisters its lists
+func (q *RedisIngestQueue) maintain(ctx context.Context, consumer string, lists []string, running *sync.WaitGroup) {
+	defer q.wg.Done()
+
+	stopped := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		running.Wait()
+		close(stopped)
+	}()
+
+	ticker := time.NewTicker(redisConsumerHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			q.heartbeat(consumer)
+			if ctx.Err() == nil {
+				q.reap()
+			}
+		case <-stopped:
+			args := append([]string{"HDEL", q.processingKey()}, lists...)
+			if _, err := q.client.Do(args...); err != nil {
+				log.Printf("Ingest queue: failed to unregister processing lists: %v", err)
+			}
+			q.client.Do("DEL", q.consumerKey(consumer))
+			return
+		}
+	}
+}
+
+// heartbeat marks the process alive for redisConsumerTTL
+func (q *RedisIngestQueue) heartbeat(consumer string) {
+	ttl := strconv.FormatInt(redisConsumerTTL.Milliseconds(), 10)
+	if _, err := q.client.Do("SET", q.consumerKey(consumer), "1", "PX", ttl); err != nil {
+		log.Printf("Ingest queue: failed to send heartbeat: %v", err)
+	}
+}
+
+// reap puts the jobs left in the processing lists of processes whose heartbeat expired back
+// in the queue, counting an attempt, and unregisters the lists
+func (q *RedisIngestQueue) reap() {
+	// One process reaps at a time, so jobs are not requeued twice
+	lock := strconv.FormatInt(redisConsumerHeartbeat.Milliseconds(), 10)
+	if _, err := q.client.Do("SET", q.key+":reaping", "1", "NX", "PX", lock); err != nil {
+		if !errors.Is(err, ErrNil) {
+			log.Printf("Ingest queue: failed to take the reaping lock: %v", err)
+		}
+		return
+	}
+
+	reply, err := q.client.Do("HGETALL", q.processingKey())
+	if err != nil {
+		log.Printf("Ingest queue: failed to list processing lists: %v", err)
+		return
+	}
+	fields, _ := reply.([]interface{})
+
+	alive := make(map[string]bool)
+	for i := 0; i+1 < len(fields); i += 2 {
+		list, _ := fields[i].(string)
+		consumer, _ := fields[i+1].(string)
+		live, checked := alive[consumer]
+		if !checked {
+			exists, err := q.client.Do("EXISTS", q.consumerKey(consumer))
+			if err != nil {
+				log.Printf("Ingest queue: failed to check worker %s: %v", consumer, err)
+				return
+			}
+			live = exists != int64(0)
+			alive[consumer] = live
+		}
+		if live {
+			continue
+		}
+
+		if err := q.requeueProcessing(list); err != nil {
+			log.Printf("Ingest queue: failed to requeue the jobs of %s: %v", list, err)
+			continue
+		}
+		q.client.Do("HDEL", q.processingKey(), list)
+	}
+}
+
+// requeueProcessing moves the jobs of a processing list back to the queue
+func (q *RedisIngestQueue) requeueProcessing(list string) error {
+	reply, err := q.client.Do("LRANGE", list, "0", "-1")
+	if err != nil {
+		return err
+	}
+	payloads, _ := reply.([]interface{})
+	for _, value := range payloads {
+		payload, _ := value.(string)
+
+		var job IngestJob
+		if err := json.Unmarshal([]byte(payload), &job); err != nil {
+			log.Printf("Ingest queue: dropping malformed job: %v", err)
+		} else if job.Attempts++; job.Attempts >= maxIngestAttempts {
+			// The job may be what stopped the workers handling it
+			log.Printf("Ingest queue: dropping event for project %s after %d attempts: its worker stopped", job.ProjectID, job.Attempts)
+		} else if err := q.Enqueue(&job); err != nil {
+			return err
+		}
+
+		if _, err := q.client.Do("LREM", list, "1", payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// processingKey is the hash of the processing lists of the workers, mapping each to its process
+func (q *RedisIngestQueue) processingKey() string {
+	return q.key + ":processing"
+}
+
+// consumerKey is the heartbeat key of a process running workers
+func (q *RedisIngestQueue) consumerKey(consumer string) string {
+	return q.key + ":consumer:" + consumer
+}
@@ -0,0 +1,207 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const (
+	// DefaultKafkaTopic is the topic queued events are produced to
+	DefaultKafkaTopic = "minisentry-ingest"
+
+	// DefaultKafkaGroup is the consumer group workers join to share the topic's partitions
+	DefaultKafkaGroup = "minisentry-ingest-workers"
+
+	// kafkaRequestTimeout bounds connecting, producing a job and committing offsets
+	kafkaRequestTimeout = 10 * time.Second
+
+	// kafkaPollRecords bounds the jobs a worker takes at once, so a batch is handled well
+	// within the group's rebalance timeout
+	kafkaPollRecords = 500
+)
+
+// KafkaConfig configures the Kafka ingestion queue
+type KafkaConfig struct {
+	Brokers []string
+	Topic   string
+	// Group is the consumer group of the workers; the topic's partitions are balanced
+	// across the processes consuming with the same group
+	Group string
+}
+
+// KafkaIngestQueue queues ingestion jobs in a Kafka topic. Jobs are keyed by
+// project so each project's events stay ordered within one partition. Workers
+// join a consumer group, so each partition is consumed by one process at a time
+// and partitions move between processes as they start and stop. Offsets are
+// committed after the polled jobs have been handled.
+type KafkaIngestQueue struct {
+	producer *kgo.Client
+	config   KafkaConfig
+
+	mu       sync.Mutex
+	consumer *kgo.Client // created by Start
+	wg       sync.WaitGroup
+}
+
+// NewKafkaIngestQueue connects to the brokers
+func NewKafkaIngestQueue(config KafkaConfig) (*KafkaIngestQueue, error) {
+	if len(config.Brokers) == 0 {
+		return nil, fmt.Errorf("at least one Kafka broker is required")
+	}
+	if config.Topic == "" {
+		config.Topic = DefaultKafkaTopic
+	}
+	if config.Group == "" {
+		config.Group = DefaultKafkaGroup
+	}
+
+	producer, err := kgo.NewClient(
+		kgo.SeedBrokers(config.Brokers...),
+		kgo.ClientID("minisentry"),
+		kgo.DefaultProduceTopic(config.Topic),
+		kgo.AllowAutoTopicCreation(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kafkaRequestTimeout)
+	defer cancel()
+	if err := producer.Ping(ctx); err != nil {
+		producer.Close()
+		return nil, fmt.Errorf("failed to connect to Kafka: %w", err)
+	}
+
+	return &KafkaIngestQueue{producer: producer, config: config}, nil
+}
+
+// Enqueue produces a job keyed by its project and waits for all in-sync replicas
+// to acknowledge it
+func (q *KafkaIngestQueue) Enqueue(job *IngestJob) error {
+	if job.EnqueuedAt.IsZero() {
+		job.EnqueuedAt = time.Now()
+	}
+
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode ingest job: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kafkaRequestTimeout)
+	defer cancel()
+	record := &kgo.Record{Key: job.ProjectID[:], Value: payload}
+	if err := q.producer.ProduceSync(ctx, record).FirstErr(); err != nil {
+		return fmt.Errorf("failed to enqueue event: %w", err)
+	}
+	return nil
+}
+
+// Start joins the consumer group and handles the jobs of the partitions assigned to
+// this process, one worker per partition, until the context is cancelled. Kafka
+// parallelism comes from partitions, so the worker count is unused.
+func (q *KafkaIngestQueue) Start(ctx context.Context, workers int, handler IngestHandler) {
+	consumer, err := kgo.NewClient(
+		kgo.SeedBrokers(q.config.Brokers...),
+		kgo.ClientID("minisentry"),
+		kgo.ConsumerGroup(q.config.Group),
+		kgo.ConsumeTopics(q.config.Topic),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+		kgo.DisableAutoCommit(),
+		// Partitions are not taken away while their polled jobs are being handled
+		kgo.BlockRebalanceOnPoll(),
+	)
+	if err != nil {
+		log.Printf("Ingest queue: failed to create Kafka consumer: %v", err)
+		return
+	}
+
+	q.mu.Lock()
+	q.consumer = consumer
+	q.mu.Unlock()
+
+	q.wg.Add(1)
+	go q.consume(ctx, consumer, handler)
+}
+
+// Wait blocks until all workers have stopped
+func (q *KafkaIngestQueue) Wait() {
+	q.wg.Wait()
+}
+
+// Close leaves the consumer group and closes the broker connections
+func (q *KafkaIngestQueue) Close() error {
+	q.mu.Lock()
+	consumer := q.consumer
+	q.consumer = nil
+	q.mu.Unlock()
+
+	if consumer != nil {
+		consumer.Close()
+	}
+	q.producer.Close()
+	return nil
+}
+
+func (q *KafkaIngestQueue) consume(ctx context.Context, consumer *kgo.Client, handler IngestHandler) {
+	defer q.wg.Done()
+
+	for ctx.Err() == nil {
+		fetches := consumer.PollRecords(ctx, kafkaPollRecords)
+		if fetches.IsClientClosed() || ctx.Err() != nil {
+			consumer.AllowRebalance()
+			return
+		}
+		fetches.EachError(func(topic string, partition int32, err error) {
+			log.Printf("Ingest queue: failed to fetch from Kafka partition %d: %v", partition, err)
+		})
+
+		// Each partition is handled by its own worker, in order
+		var mu sync.Mutex
+		var handled []*kgo.Record
+		var wg sync.WaitGroup
+		fetches.EachPartition(func(partition kgo.FetchTopicPartition) {
+			if len(partition.Records) == 0 {
+				return
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var last *kgo.Record
+				for _, record := range partition.Records {
+					if ctx.Err() != nil {
+						break
+					}
+					var job IngestJob
+					if err := json.Unmarshal(record.Value, &job); err != nil {
+						log.Printf("Ingest queue: dropping malformed job at offset %d of partition %d: %v", record.Offset, record.Partition, err)
+					} else {
+						processJob(q, &job, handler)
+					}
+					last = record
+				}
+				if last != nil {
+					mu.Lock()
+					handled = append(handled, last)
+					mu.Unlock()
+				}
+			}()
+		})
+		wg.Wait()
+
+		// Jobs left unhandled at shutdown are consumed again by the process taking the partition
+		if len(handled) > 0 {
+			commitCtx, cancel := context.WithTimeout(context.Background(), kafkaRequestTimeout)
+			if err := consumer.CommitRecords(commitCtx, handled...); err != nil {
+				log.Printf("Ingest queue: failed to commit Kafka offsets: %v", err)
+			}
+			cancel()
+		}
+		consumer.AllowRebalance()
+	}
+}
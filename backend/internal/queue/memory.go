@@ -0,0 +1,78 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultMemoryQueueSize bounds the in-memory queue when no size is configured
+const DefaultMemoryQueueSize = 10000
+
+// MemoryIngestQueue is a bounded in-process queue of ingestion jobs. Jobs are
+// lost on restart and can only be consumed by workers in the same process.
+type MemoryIngestQueue struct {
+	jobs chan *IngestJob
+	wg   sync.WaitGroup
+}
+
+// NewMemoryIngestQueue creates an in-memory queue holding up to size jobs
+func NewMemoryIngestQueue(size int) *MemoryIngestQueue {
+	if size < 1 {
+		size = DefaultMemoryQueueSize
+	}
+	return &MemoryIngestQueue{jobs: make(chan *IngestJob, size)}
+}
+
+// Enqueue adds a job to the queue, failing with ErrQueueFull rather than blocking the request
+func (q *MemoryIngestQueue) Enqueue(job *IngestJob) error {
+	if job.EnqueuedAt.IsZero() {
+		job.EnqueuedAt = time.Now()
+	}
+
+	select {
+	case q.jobs <- job:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Len returns the number of jobs waiting in the queue
+func (q *MemoryIngestQueue) Len() (int64, error) {
+	return int64(len(q.jobs)), nil
+}
+
+// Start runs the given number of workers until the context is cancelled
+func (q *MemoryIngestQueue) Start(ctx context.Context, workers int, handler IngestHandler) {
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		q.wg.Add(1)
+		go q.work(ctx, handler)
+	}
+}
+
+// Wait blocks until all workers have stopped
+func (q *MemoryIngestQueue) Wait() {
+	q.wg.Wait()
+}
+
+// Close is a no-op; jobs still queued when the process exits are lost
+func (q *MemoryIngestQueue) Close() error {
+	return nil
+}
+
+func (q *MemoryIngestQueue) work(ctx context.Context, handler IngestHandler) {
+	defer q.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-q.jobs:
+			processJob(q, job, handler)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ErrQueueFull is returned when a bounded queue cannot take more jobs
+var ErrQueueFull = errors.New("ingest queue is full")
+
+// ErrJobDeferred is returned by handlers for jobs that cannot be processed yet, such as
+// during a maintenance window. They are requeued after deferredJobDelay without using up
+// an attempt.
+var ErrJobDeferred = errors.New("ingest job deferred")
+
+// deferredJobDelay keeps workers from spinning on deferred jobs
+const deferredJobDelay = 5 * time.Second
+
+// EventQueue carries ingestion jobs from the API server to ingestion workers.
+// Workers may run in the API server process or, for the Redis and Kafka
+// backends, in separate worker processes consuming the same queue.
+type EventQueue interface {
+	// Enqueue adds a job to the queue
+	Enqueue(job *IngestJob) error
+	// Start runs workers until the context is cancelled
+	Start(ctx context.Context, workers int, handler IngestHandler)
+	// Wait blocks until all workers have stopped
+	Wait()
+	// Close releases the queue's connections
+	Close() error
+}
+
+// Options configures the queue backends; only the fields of the selected backend are used
+type Options struct {
+	// Memory
+	MemorySize int
+
+	// Redis
+	RedisURL string
+	RedisKey string
+
+	// Kafka
+	KafkaBrokers []string
+	KafkaTopic   string
+	KafkaGroup   string
+}
+
+// Open creates the event queue for the given backend: memory, redis or kafka
+func Open(backend string, options Options) (EventQueue, error) {
+	switch backend {
+	case "memory":
+		return NewMemoryIngestQueue(options.MemorySize), nil
+	case "redis":
+		client, err := NewRedisClient(options.RedisURL)
+		if err != nil {
+			return nil, err
+		}
+		if err := client.Ping(); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+		}
+		return NewRedisIngestQueue(client, options.RedisKey), nil
+	case "kafka":
+		return NewKafkaIngestQueue(KafkaConfig{
+			Brokers: options.KafkaBrokers,
+			Topic:   options.KafkaTopic,
+			Group:   options.KafkaGroup,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported ingest queue backend %q", backend)
+	}
+}
+
+// processJob runs the handler for a job, requeueing it on failure until
+// maxIngestAttempts is reached, and without limit when it is deferred
+func processJob(q EventQueue, job *IngestJob, handler IngestHandler) {
+	err := handler(job)
+	if err == nil {
+		return
+	}
+
+	if errors.Is(err, ErrJobDeferred) {
+		time.Sleep(deferredJobDelay)
+		if err := q.Enqueue(job); err != nil {
+			log.Printf("Ingest queue: failed to requeue deferred event for project %s: %v", job.ProjectID, err)
+		}
+		return
+	}
+
+	job.Attempts++
+	if job.Attempts >= maxIngestAttempts {
+		log.Printf("Ingest queue: dropping event for project %s after %d attempts: %v", job.ProjectID, job.Attempts, err)
+		return
+	}
+
+	log.Printf("Ingest queue: retrying event for project %s (attempt %d): %v", job.ProjectID, job.Attempts, err)
+	if err := q.Enqueue(job); err != nil {
+		log.Printf("Ingest queue: failed to requeue event for project %s: %v", job.ProjectID, err)
+	}
+}
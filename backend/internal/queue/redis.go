@@ -0,0 +1,263 @@
+package queue
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNil is returned for a nil reply, e.g. when a blocking pop times out
+var ErrNil = errors.New("redis: nil reply")
+
+const redisDialTimeout = 5 * time.Second
+
+// RedisError is an error reply sent by the server
+type RedisError string
+
+func (e RedisError) Error() string { return "redis: " + string(e) }
+
+// RedisClient is a minimal Redis client speaking RESP2 over a small connection pool.
+// It only implements the commands the queue needs.
+type RedisClient struct {
+	addr     string
+	password string
+	username string
+	db       int
+
+	mu   sync.Mutex
+	idle []*redisConn
+}
+
+// NewRedisClient creates a client from a redis:// URL, e.g. redis://:password@localhost:6379/0
+func NewRedisClient(redisURL string) (*RedisClient, error) {
+	parsed, err := url.Parse(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	if parsed.Scheme != "redis" {
+		// rediss:// (TLS) is not supported
+		return nil, fmt.Errorf("unsupported Redis URL scheme %q", parsed.Scheme)
+	}
+
+	client := &RedisClient{addr: parsed.Host}
+	if parsed.Port() == "" {
+		client.addr = net.JoinHostPort(parsed.Hostname(), "6379")
+	}
+	if parsed.User != nil {
+		client.username = parsed.User.Username()
+		client.password, _ = parsed.User.Password()
+	}
+	if db := strings.TrimPrefix(parsed.Path, "/"); db != "" {
+		if client.db, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid Redis database %q", db)
+		}
+	}
+	return client, nil
+}
+
+// Ping checks the server is reachable
+func (c *RedisClient) Ping() error {
+	_, err := c.Do("PING")
+	return err
+}
+
+// LPush prepends values to a list
+func (c *RedisClient) LPush(key string, values ...string) (int64, error) {
+	reply, err := c.Do(append([]string{"LPUSH", key}, values...)...)
+	if err != nil {
+		return 0, err
+	}
+	length, _ := reply.(int64)
+	return length, nil
+}
+
+// BLMove moves the last element of a list to the head of another, waiting up to timeout
+// for one to arrive, and returns it. It returns ErrNil when the timeout expires.
+func (c *RedisClient) BLMove(source, destination string, timeout time.Duration) (string, error) {
+	seconds := strconv.FormatFloat(timeout.Seconds(), 'f', 3, 64)
+	reply, err := c.doWithDeadline(timeout+redisDialTimeout, "BLMOVE", source, destination, "RIGHT", "LEFT", seconds)
+	if err != nil {
+		return "", err
+	}
+	value, ok := reply.(string)
+	if !ok {
+		return "", fmt.Errorf("redis: unexpected BLMOVE reply %v", reply)
+	}
+	return value, nil
+}
+
+// LLen returns the length of a list
+func (c *RedisClient) LLen(key string) (int64, error) {
+	reply, err := c.Do("LLEN", key)
+	if err != nil {
+		return 0, err
+	}
+	length, _ := reply.(int64)
+	return length, nil
+}
+
+// Do sends a command and returns its reply: a string, an int64, a []interface{} or nil
+func (c *RedisClient) Do(args ...string) (interface{}, error) {
+	return c.doWithDeadline(redisDialTimeout, args...)
+}
+
+// Close closes the idle connections of the pool
+func (c *RedisClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, conn := range c.idle {
+		conn.conn.Close()
+	}
+	c.idle = nil
+	return nil
+}
+
+func (c *RedisClient) doWithDeadline(timeout time.Duration, args ...string) (interface{}, error) {
+	conn, err := c.get()
+	if err != nil {
+		return nil, err
+	}
+
+	conn.conn.SetDeadline(time.Now().Add(timeout))
+	reply, err := conn.do(args...)
+	if err != nil && !errors.Is(err, ErrNil) && !isRedisError(err) {
+		// The connection state is unknown after an I/O error
+		conn.conn.Close()
+		return nil, err
+	}
+
+	c.put(conn)
+	return reply, err
+}
+
+func (c *RedisClient) get() (*redisConn, error) {
+	c.mu.Lock()
+	if n := len(c.idle); n > 0 {
+		conn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return conn, nil
+	}
+	c.mu.Unlock()
+
+	return c.dial()
+}
+
+func (c *RedisClient) put(conn *redisConn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.idle = append(c.idle, conn)
+}
+
+func (c *RedisClient) dial() (*redisConn, error) {
+	netConn, err := net.DialTimeout("tcp", c.addr, redisDialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	conn := &redisConn{
+		conn:   netConn,
+		reader: bufio.NewReader(netConn),
+		writer: bufio.NewWriter(netConn),
+	}
+	netConn.SetDeadline(time.Now().Add(redisDialTimeout))
+
+	if c.password != "" {
+		args := []string{"AUTH", c.password}
+		if c.username != "" {
+			args = []string{"AUTH", c.username, c.password}
+		}
+		if _, err := conn.do(args...); err != nil {
+			netConn.Close()
+			return nil, fmt.Errorf("Redis authentication failed: %w", err)
+		}
+	}
+	if c.db != 0 {
+		if _, err := conn.do("SELECT", strconv.Itoa(c.db)); err != nil {
+			netConn.Close()
+			return nil, fmt.Errorf("failed to select Redis database: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+}
+
+func (rc *redisConn) do(args ...string) (interface{}, error) {
+	fmt.Fprintf(rc.writer, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(rc.writer, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if err := rc.writer.Flush(); err != nil {
+		return nil, err
+	}
+	return rc.readReply()
+}
+
+func (rc *redisConn) readReply() (interface{}, error) {
+	line, err := rc.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, RedisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid bulk length %q", line)
+		}
+		if size < 0 {
+			return nil, ErrNil
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(rc.reader, data); err != nil {
+			return nil, err
+		}
+		return string(data[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid array length %q", line)
+		}
+		if count < 0 {
+			return nil, ErrNil
+		}
+		values := make([]interface{}, count)
+		for i := range values {
+			value, err := rc.readReply()
+			if err != nil && !errors.Is(err, ErrNil) {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
+
+func isRedisError(err error) bool {
+	var redisErr RedisError
+	return errors.As(err, &redisErr)
+}
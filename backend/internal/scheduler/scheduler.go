@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is a periodic maintenance task; a returned error is logged and the job runs again on its next tick
+type Job func(ctx context.Context) error
+
+type registeredJob struct {
+	name     string
+	interval time.Duration
+	run      Job
+}
+
+// Scheduler runs registered jobs at fixed intervals in the background. Each job runs
+// in its own goroutine and never overlaps with itself.
+type Scheduler struct {
+	mu      sync.Mutex
+	jobs    []registeredJob
+	started bool
+	wg      sync.WaitGroup
+}
+
+// New creates an empty scheduler
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every registers a job to run once per interval after the scheduler starts.
+// Jobs must be registered before Start.
+func (s *Scheduler) Every(name string, interval time.Duration, job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		panic("scheduler: job " + name + " registered after Start")
+	}
+	s.jobs = append(s.jobs, registeredJob{name: name, interval: interval, run: job})
+}
+
+// Start runs the registered jobs until the context is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.started = true
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+}
+
+// Wait blocks until all jobs have stopped
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job registeredJob) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := job.run(ctx); err != nil {
+			log.Printf("Scheduler: job %s failed: %v", job.name, err)
+		}
+	}
+}
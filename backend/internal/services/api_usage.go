@@ -0,0 +1,366 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+// Callers of the API whose usage is tracked
+const (
+	APICallerUser        = "user"
+	APICallerIngestToken = "ingest_token"
+)
+
+const (
+	// defaultAPIUsageDays is how many days API usage covers when no since is given
+	defaultAPIUsageDays = 7
+
+	// maxAPIUsageDays bounds the days a single API usage query covers
+	maxAPIUsageDays = 90
+)
+
+var (
+	ErrInvalidAPIUsageWindow = errors.New("API usage window must end after it starts")
+	ErrAPIUsageWindowTooLong = fmt.Errorf("API usage window cannot exceed %d days", maxAPIUsageDays)
+)
+
+// apiUsageKey is a caller's calls to a route of an organization or project on a UTC day,
+// with a status class. The organization of calls recorded with only their project is
+// uuid.Nil until it is looked up.
+type apiUsageKey struct {
+	organizationID uuid.UUID
+	projectID      uuid.UUID
+	callerType     string
+	callerID       uuid.UUID
+	day            time.Time
+	route          string
+	statusClass    string
+}
+
+// APIUsageService counts the API calls of users and ingest tokens per organization, project,
+// day, route and status class, so organization owners can spot runaway scripts and decide
+// which tokens to throttle. Counts are buffered and written to the database periodically.
+type APIUsageService struct {
+	db *database.DB
+
+	mu      sync.Mutex
+	pending map[apiUsageKey]int64 // calls not yet written
+}
+
+// NewAPIUsageService creates a new API usage service
+func NewAPIUsageService(db *database.DB) *APIUsageService {
+	return &APIUsageService{
+		db:      db,
+		pending: make(map[apiUsageKey]int64),
+	}
+}
+
+// Record buffers an API call of a caller to a route, made against a project (uuid.Nil for
+// organization routes) of an organization (uuid.Nil when only the project is known)
+func (us *APIUsageService) Record(organizationID, projectID uuid.UUID, callerType string, callerID uuid.UUID, route string, status int) {
+	key := apiUsageKey{
+		organizationID: organizationID,
+		projectID:      projectID,
+		callerType:     callerType,
+		callerID:       callerID,
+		day:            eventVolumeDay(time.Now()),
+		route:          route,
+		statusClass:    apiStatusClass(status),
+	}
+
+	us.mu.Lock()
+	us.pending[key]++
+	us.mu.Unlock()
+}
+
+// apiStatusClass groups response statuses; 429 is kept apart to show throttled callers
+func apiStatusClass(status int) string {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return "429"
+	case status >= 500:
+		return "5xx"
+	case status >= 400:
+		return "4xx"
+	case status >= 300:
+		return "3xx"
+	}
+	return "2xx"
+}
+
+// FlushStats adds the buffered call counts to the daily totals in the database.
+// It is run periodically by the scheduler and on shutdown.
+func (us *APIUsageService) FlushStats(ctx context.Context) error {
+	pending := takeCounts(&us.mu, &us.pending)
+
+	organizations, err := us.projectOrganizations(ctx, pending)
+	if err != nil {
+		requeueCounts(&us.mu, &us.pending, pending)
+		return err
+	}
+
+	err = flushCounts(&us.mu, &us.pending, pending, func(key apiUsageKey, calls int64) error {
+		organizationID := key.organizationID
+		if organizationID == uuid.Nil {
+			organizationID = organizations[key.projectID]
+		}
+		if organizationID == uuid.Nil {
+			// The project was deleted since the call
+			return nil
+		}
+
+		row := models.APIUsageStat{
+			OrganizationID: organizationID,
+			ProjectID:      key.projectID,
+			CallerType:     key.callerType,
+			CallerID:       key.callerID,
+			Day:            key.day,
+			Route:          key.route,
+			StatusClass:    key.statusClass,
+			Calls:          calls,
+		}
+		return us.db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns: []clause.Column{
+				{Name: "organization_id"}, {Name: "project_id"}, {Name: "caller_type"}, {Name: "caller_id"},
+				{Name: "day"}, {Name: "route"}, {Name: "status_class"},
+			},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"calls":      clause.Expr{SQL: "api_usage_stats.calls + excluded.calls"},
+				"updated_at": time.Now(),
+			}),
+		}).Create(&row).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record API usage: %w", err)
+	}
+	return nil
+}
+
+// projectOrganizations looks up the organizations of the projects of calls recorded without one
+func (us *APIUsageService) projectOrganizations(ctx context.Context, calls map[apiUsageKey]int64) (map[uuid.UUID]uuid.UUID, error) {
+	var projectIDs []uuid.UUID
+	for key := range calls {
+		if key.organizationID == uuid.Nil && key.projectID != uuid.Nil {
+			projectIDs = append(projectIDs, key.projectID)
+		}
+	}
+	organizations := make(map[uuid.UUID]uuid.UUID)
+	if len(projectIDs) == 0 {
+		return organizations, nil
+	}
+
+	var projects []models.Project
+	if err := us.db.WithContext(ctx).Select("id, organization_id").Where("id IN ?", projectIDs).Find(&projects).Error; err != nil {
+		return nil, fmt.Errorf("failed to get organizations of API calls: %w", err)
+	}
+	for _, project := range projects {
+		organizations[project.ID] = project.OrganizationID
+	}
+	return organizations, nil
+}
+
+// GetOrganizationUsage returns the API calls made against an organization and its projects
+// between two days, per caller
+func (us *APIUsageService) GetOrganizationUsage(organizationID uuid.UUID, filters *dto.APIUsageFilters) (*dto.APIUsageResponse, error) {
+	return us.getUsage(organizationID, nil, filters)
+}
+
+// GetProjectUsage returns the API calls made against a project between two days, per caller
+func (us *APIUsageService) GetProjectUsage(organizationID, projectID uuid.UUID, filters *dto.APIUsageFilters) (*dto.APIUsageResponse, error) {
+	return us.getUsage(organizationID, &projectID, filters)
+}
+
+func (us *APIUsageService) getUsage(organizationID uuid.UUID, projectID *uuid.UUID, filters *dto.APIUsageFilters) (*dto.APIUsageResponse, error) {
+	until := filters.Until
+	if until.IsZero() {
+		until = time.Now()
+	}
+	until = eventVolumeDay(until)
+	since := filters.Since
+	if since.IsZero() {
+		since = until.AddDate(0, 0, 1-defaultAPIUsageDays)
+	}
+	since = eventVolumeDay(since)
+	if until.Before(since) {
+		return nil, ErrInvalidAPIUsageWindow
+	}
+	if until.Sub(since) >= maxAPIUsageDays*24*time.Hour {
+		return nil, ErrAPIUsageWindowTooLong
+	}
+
+	query := us.db.Model(&models.APIUsageStat{}).
+		Where("organization_id = ? AND day >= ? AND day <= ?", organizationID, since, until)
+	if projectID != nil {
+		query = query.Where("project_id = ?", *projectID)
+	}
+	var rows []struct {
+		CallerType  string
+		CallerID    uuid.UUID
+		Day         time.Time
+		Route       string
+		StatusClass string
+		Calls       int64
+	}
+	err := query.Select("caller_type, caller_id, day, route, status_class, SUM(calls) AS calls").
+		Group("caller_type, caller_id, day, route, status_class").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate API usage: %w", err)
+	}
+
+	usage := make(map[apiUsageKey]int64, len(rows))
+	for _, row := range rows {
+		key := apiUsageKey{callerType: row.CallerType, callerID: row.CallerID, day: row.Day.UTC(), route: row.Route, statusClass: row.StatusClass}
+		usage[key] += row.Calls
+	}
+
+	us.mu.Lock()
+	pending := make(map[apiUsageKey]int64)
+	for key, calls := range us.pending {
+		if key.day.Before(since) || key.day.After(until) {
+			continue
+		}
+		if projectID != nil && key.projectID != *projectID {
+			continue
+		}
+		if key.organizationID != organizationID && key.organizationID != uuid.Nil {
+			continue
+		}
+		pending[key] = calls
+	}
+	us.mu.Unlock()
+
+	// Calls to a project recorded without its organization are only known to be the
+	// organization's once the project is looked up
+	organizations, err := us.projectOrganizations(context.Background(), pending)
+	if err != nil {
+		return nil, err
+	}
+	for key, calls := range pending {
+		if key.organizationID == uuid.Nil && organizations[key.projectID] != organizationID {
+			continue
+		}
+		key.organizationID, key.projectID = uuid.Nil, uuid.Nil
+		usage[key] += calls
+	}
+
+	response := &dto.APIUsageResponse{
+		OrganizationID: organizationID,
+		ProjectID:      projectID,
+		Since:          since,
+		Until:          until,
+		Callers:        []dto.APIUsageCaller{},
+	}
+
+	type callerKey struct {
+		callerType string
+		callerID   uuid.UUID
+	}
+	callers := make(map[callerKey]*dto.APIUsageCaller)
+	routes := make(map[callerKey]map[string]*dto.APIUsageRoute)
+	days := make(map[callerKey]map[time.Time]int64)
+	for key, calls := range usage {
+		ck := callerKey{callerType: key.callerType, callerID: key.callerID}
+		caller, ok := callers[ck]
+		if !ok {
+			caller = &dto.APIUsageCaller{Type: key.callerType, ID: key.callerID, Statuses: make(map[string]int64)}
+			callers[ck] = caller
+			routes[ck] = make(map[string]*dto.APIUsageRoute)
+			days[ck] = make(map[time.Time]int64)
+		}
+		caller.Calls += calls
+		caller.Statuses[key.statusClass] += calls
+
+		route, ok := routes[ck][key.route]
+		if !ok {
+			route = &dto.APIUsageRoute{Route: key.route, Statuses: make(map[string]int64)}
+			routes[ck][key.route] = route
+		}
+		route.Calls += calls
+		route.Statuses[key.statusClass] += calls
+
+		days[ck][key.day] += calls
+		response.TotalCalls += calls
+	}
+
+	for ck, caller := range callers {
+		for _, route := range routes[ck] {
+			caller.Routes = append(caller.Routes, *route)
+		}
+		sort.Slice(caller.Routes, func(i, j int) bool {
+			if caller.Routes[i].Calls != caller.Routes[j].Calls {
+				return caller.Routes[i].Calls > caller.Routes[j].Calls
+			}
+			return caller.Routes[i].Route < caller.Routes[j].Route
+		})
+		for day, calls := range days[ck] {
+			caller.Days = append(caller.Days, dto.APIUsageDay{Day: day, Calls: calls})
+		}
+		sort.Slice(caller.Days, func(i, j int) bool { return caller.Days[i].Day.Before(caller.Days[j].Day) })
+		response.Callers = append(response.Callers, *caller)
+	}
+	names, err := us.callerNames(response.Callers)
+	if err != nil {
+		return nil, err
+	}
+	for i := range response.Callers {
+		response.Callers[i].Name = names[response.Callers[i].ID]
+	}
+	sort.Slice(response.Callers, func(i, j int) bool {
+		a, b := response.Callers[i], response.Callers[j]
+		if a.Calls != b.Calls {
+			return a.Calls > b.Calls
+		}
+		return a.ID.String() < b.ID.String()
+	})
+	return response, nil
+}
+
+// callerNames looks up the names of the users and ingest tokens that made calls, by ID
+func (us *APIUsageService) callerNames(callers []dto.APIUsageCaller) (map[uuid.UUID]string, error) {
+	var userIDs, tokenIDs []uuid.UUID
+	for _, caller := range callers {
+		switch caller.Type {
+		case APICallerUser:
+			userIDs = append(userIDs, caller.ID)
+		case APICallerIngestToken:
+			tokenIDs = append(tokenIDs, caller.ID)
+		}
+	}
+
+	names := make(map[uuid.UUID]string, len(callers))
+	if len(userIDs) > 0 {
+		var users []models.User
+		if err := us.db.Select("id, name, email").Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+			return nil, fmt.Errorf("failed to get users of API calls: %w", err)
+		}
+		for _, user := range users {
+			names[user.ID] = user.Name
+			if user.Name == "" {
+				names[user.ID] = user.Email
+			}
+		}
+	}
+	if len(tokenIDs) > 0 {
+		var tokens []models.OrgIngestToken
+		if err := us.db.Select("id, name").Where("id IN ?", tokenIDs).Find(&tokens).Error; err != nil {
+			return nil, fmt.Errorf("failed to get ingest tokens of API calls: %w", err)
+		}
+		for _, token := range tokens {
+			names[token.ID] = token.Name
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+	"minisentry/internal/storage"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrEventNotFound      = errors.New("event not found")
+)
+
+type AttachmentService struct {
+	db    *database.DB
+	blobs storage.BlobStore
+}
+
+// NewAttachmentService creates a service storing attachment contents in the given blob store
+func NewAttachmentService(db *database.DB, blobs storage.BlobStore) *AttachmentService {
+	return &AttachmentService{db: db, blobs: blobs}
+}
+
+// StoreAttachment writes the attachment content to blob storage and records it for the event
+func (as *AttachmentService) StoreAttachment(projectID, eventID uuid.UUID, name, contentType, attachmentType string, content io.Reader) (*models.Attachment, error) {
+	attachment := &models.Attachment{
+		ProjectID:      projectID,
+		EventID:        eventID,
+		Name:           name,
+		ContentType:    contentType,
+		AttachmentType: attachmentType,
+	}
+	attachment.ID = uuid.New()
+	attachment.StorageKey = fmt.Sprintf("%s/%s/%s", projectID, eventID, attachment.ID)
+
+	size, err := as.blobs.Put(attachment.StorageKey, content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store attachment content: %w", err)
+	}
+	attachment.Size = size
+
+	if err := as.db.Create(attachment).Error; err != nil {
+		if deleteErr := as.blobs.Delete(attachment.StorageKey); deleteErr != nil {
+			log.Printf("Failed to clean up attachment blob %s: %v", attachment.StorageKey, deleteErr)
+		}
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// ResolveEventID returns the internal ID of an event from its SDK event ID, for attachments
+// uploaded in a separate envelope from their event
+func (as *AttachmentService) ResolveEventID(projectID uuid.UUID, eventID string) (uuid.UUID, error) {
+	var event models.Event
+	err := as.db.Select("id").Where("project_id = ? AND event_id = ?", projectID, eventID).First(&event).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return uuid.Nil, ErrEventNotFound
+		}
+		return uuid.Nil, fmt.Errorf("failed to query event: %w", err)
+	}
+	return event.ID, nil
+}
+
+// ListEventAttachments lists the attachments of an event belonging to the issue
+func (as *AttachmentService) ListEventAttachments(issueID, eventID uuid.UUID) ([]dto.AttachmentResponse, error) {
+	var attachments []models.Attachment
+	if err := as.db.Joins("JOIN events ON events.id = attachments.event_id").
+		Where("events.issue_id = ? AND attachments.event_id = ?", issueID, eventID).
+		Order("attachments.created_at ASC").
+		Find(&attachments).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve attachments: %w", err)
+	}
+
+	responses := make([]dto.AttachmentResponse, len(attachments))
+	for i, attachment := range attachments {
+		responses[i] = dto.AttachmentResponse{
+			ID:             attachment.ID,
+			EventID:        attachment.EventID,
+			Name:           attachment.Name,
+			ContentType:    attachment.ContentType,
+			AttachmentType: attachment.AttachmentType,
+			Size:           attachment.Size,
+			CreatedAt:      attachment.CreatedAt,
+		}
+	}
+	return responses, nil
+}
+
+// OpenAttachment returns an attachment of an event belonging to the issue together with its content
+func (as *AttachmentService) OpenAttachment(issueID, eventID, attachmentID uuid.UUID) (*models.Attachment, io.ReadCloser, error) {
+	var attachment models.Attachment
+	err := as.db.Joins("JOIN events ON events.id = attachments.event_id").
+		Where("events.issue_id = ? AND attachments.event_id = ? AND attachments.id = ?", issueID, eventID, attachmentID).
+		First(&attachment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrAttachmentNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to query attachment: %w", err)
+	}
+
+	content, err := as.blobs.Get(attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrBlobNotFound) {
+			return nil, nil, ErrAttachmentNotFound
+		}
+		return nil, nil, err
+	}
+	return &attachment, content, nil
+}
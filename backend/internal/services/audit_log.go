@@ -0,0 +1,115 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type AuditLogService struct {
+	db *database.DB
+}
+
+// NewAuditLogService creates a new audit log service
+func NewAuditLogService(db *database.DB) *AuditLogService {
+	return &AuditLogService{db: db}
+}
+
+// recordAudit writes an audit log entry within the transaction of the audited change.
+// A nil actor records an action taken by the system.
+func recordAudit(tx *gorm.DB, actorID *uuid.UUID, action, targetType string, targetID uuid.UUID, projectID *uuid.UUID, data interface{}) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit log data: %w", err)
+	}
+
+	entry := models.AuditLogEntry{
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		ProjectID:  projectID,
+		Data:       datatypes.JSON(encoded),
+	}
+	if projectID != nil {
+		var project models.Project
+		if err := tx.Select("organization_id").First(&project, "id = ?", *projectID).Error; err == nil {
+			entry.OrganizationID = &project.OrganizationID
+		}
+	}
+
+	if err := tx.Create(&entry).Error; err != nil {
+		return fmt.Errorf("failed to record audit log entry: %w", err)
+	}
+	return nil
+}
+
+// ListEntries returns audit log entries matching the filters, newest first
+func (as *AuditLogService) ListEntries(filters dto.AuditLogFilters) (*dto.AuditLogListResponse, error) {
+	if filters.Page < 1 {
+		filters.Page = 1
+	}
+	if filters.Limit < 1 || filters.Limit > 100 {
+		filters.Limit = 25
+	}
+
+	query := as.db.Model(&models.AuditLogEntry{})
+	if filters.ProjectID != nil {
+		query = query.Where("project_id = ?", *filters.ProjectID)
+	}
+	if filters.TargetType != "" {
+		query = query.Where("target_type = ?", filters.TargetType)
+	}
+	if filters.TargetID != nil {
+		query = query.Where("target_id = ?", *filters.TargetID)
+	}
+	if filters.Action != "" {
+		query = query.Where("action = ?", filters.Action)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count audit log entries: %w", err)
+	}
+
+	var entries []models.AuditLogEntry
+	if err := query.Order("created_at DESC").
+		Offset((filters.Page - 1) * filters.Limit).Limit(filters.Limit).
+		Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list audit log entries: %w", err)
+	}
+
+	responses := make([]dto.AuditLogEntryResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = convertAuditLogEntryToResponse(entry)
+	}
+
+	return &dto.AuditLogListResponse{
+		Entries:    responses,
+		Total:      total,
+		Page:       filters.Page,
+		Limit:      filters.Limit,
+		TotalPages: dto.CalculateTotalPages(total, filters.Limit),
+	}, nil
+}
+
+func convertAuditLogEntryToResponse(entry models.AuditLogEntry) dto.AuditLogEntryResponse {
+	return dto.AuditLogEntryResponse{
+		ID:             entry.ID,
+		ActorID:        entry.ActorID,
+		Action:         entry.Action,
+		TargetType:     entry.TargetType,
+		TargetID:       entry.TargetID,
+		OrganizationID: entry.OrganizationID,
+		ProjectID:      entry.ProjectID,
+		Data:           json.RawMessage(entry.Data),
+		CreatedAt:      entry.CreatedAt,
+	}
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidClientReport = errors.New("invalid client report")
+
+// defaultClientReportWindow is how far back client report stats look when no since is given
+const defaultClientReportWindow = 30 * 24 * time.Hour
+
+type ClientReportService struct {
+	db *database.DB
+}
+
+// NewClientReportService creates a new service for SDK client reports
+func NewClientReportService(db *database.DB) *ClientReportService {
+	return &ClientReportService{db: db}
+}
+
+// ProcessClientReport adds the discarded event counts of a client report to the
+// hourly totals of the project
+func (cs *ClientReportService) ProcessClientReport(projectID uuid.UUID, report *dto.ClientReport) error {
+	if report == nil {
+		return fmt.Errorf("%w: client report is nil", ErrInvalidClientReport)
+	}
+
+	timestamp := time.Now()
+	if report.Timestamp != nil {
+		timestamp = *report.Timestamp
+	}
+	bucket := timestamp.UTC().Truncate(time.Hour)
+
+	return cs.db.Transaction(func(tx *gorm.DB) error {
+		for _, discarded := range report.DiscardedEvents {
+			if discarded.Reason == "" || discarded.Category == "" {
+				return fmt.Errorf("%w: discarded events need a reason and a category", ErrInvalidClientReport)
+			}
+			if discarded.Quantity <= 0 {
+				continue
+			}
+
+			var existing models.ClientReport
+			err := tx.Where("project_id = ? AND bucket = ? AND reason = ? AND category = ?",
+				projectID, bucket, discarded.Reason, discarded.Category).First(&existing).Error
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				row := models.ClientReport{
+					ProjectID: projectID,
+					Bucket:    bucket,
+					Reason:    discarded.Reason,
+					Category:  discarded.Category,
+					Quantity:  discarded.Quantity,
+				}
+				if err := tx.Create(&row).Error; err != nil {
+					return fmt.Errorf("failed to create client report: %w", err)
+				}
+			case err != nil:
+				return fmt.Errorf("failed to query client report: %w", err)
+			default:
+				if err := tx.Model(&existing).Update("quantity", gorm.Expr("quantity + ?", discarded.Quantity)).Error; err != nil {
+					return fmt.Errorf("failed to update client report: %w", err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// GetClientReportStats sums the events the project's SDKs discarded, per reason and category
+func (cs *ClientReportService) GetClientReportStats(projectID uuid.UUID, filters *dto.ClientReportFilters) (*dto.ClientReportStatsResponse, error) {
+	since := filters.Since
+	if since.IsZero() {
+		since = time.Now().Add(-defaultClientReportWindow)
+	}
+
+	query := cs.db.Model(&models.ClientReport{}).
+		Where("project_id = ? AND bucket >= ?", projectID, since.UTC().Truncate(time.Hour))
+	if filters.Category != "" {
+		query = query.Where("category = ?", filters.Category)
+	}
+
+	var outcomes []dto.DiscardedEvent
+	if err := query.Select("reason, category, SUM(quantity) AS quantity").
+		Group("reason, category").
+		Order("quantity DESC").
+		Scan(&outcomes).Error; err != nil {
+		return nil, fmt.Errorf("failed to aggregate client reports: %w", err)
+	}
+
+	response := &dto.ClientReportStatsResponse{
+		Since:      since,
+		ByReason:   []dto.ClientReportReasonStats{},
+		ByCategory: []dto.ClientReportCategoryStats{},
+		Outcomes:   outcomes,
+	}
+	if response.Outcomes == nil {
+		response.Outcomes = []dto.DiscardedEvent{}
+	}
+
+	reasonIndex := make(map[string]int)
+	categoryIndex := make(map[string]int)
+	for _, outcome := range outcomes {
+		response.TotalDiscarded += outcome.Quantity
+
+		if i, ok := reasonIndex[outcome.Reason]; ok {
+			response.ByReason[i].Quantity += outcome.Quantity
+		} else {
+			reasonIndex[outcome.Reason] = len(response.ByReason)
+			response.ByReason = append(response.ByReason, dto.ClientReportReasonStats{Reason: outcome.Reason, Quantity: outcome.Quantity})
+		}
+
+		if i, ok := categoryIndex[outcome.Category]; ok {
+			response.ByCategory[i].Quantity += outcome.Quantity
+		} else {
+			categoryIndex[outcome.Category] = len(response.ByCategory)
+			response.ByCategory = append(response.ByCategory, dto.ClientReportCategoryStats{Category: outcome.Category, Quantity: outcome.Quantity})
+		}
+	}
+
+	return response, nil
+}
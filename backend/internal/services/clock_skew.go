@@ -0,0 +1,43 @@
+package services
+
+import (
+	"time"
+
+	"minisentry/internal/dto"
+)
+
+// SetClockSkewThreshold makes ingestion correct the timestamps of events from clients whose
+// clock is off by more than threshold; 0 disables it. It must be called before the server starts.
+func (es *ErrorService) SetClockSkewThreshold(threshold time.Duration) {
+	es.clockSkewThreshold = threshold
+}
+
+// CorrectClockSkew corrects the timestamp of an event received at receivedAt for the skew
+// of the client's clock, keeping the timestamp it sent as OriginalTimestamp. The skew is
+// the difference between receivedAt and sentAt, when the SDK reported when it sent the
+// event; without sentAt only timestamps in the future are corrected, to receivedAt.
+// Corrected events never end up after their receipt.
+func (es *ErrorService) CorrectClockSkew(eventData *dto.ErrorEventRequest, sentAt *time.Time, receivedAt time.Time) {
+	eventData.OriginalTimestamp = nil
+	threshold := es.clockSkewThreshold
+	if threshold <= 0 || eventData.Timestamp == nil {
+		return
+	}
+
+	original := *eventData.Timestamp
+	var adjusted time.Time
+	switch {
+	case sentAt != nil && !sentAt.IsZero() && receivedAt.Sub(*sentAt).Abs() > threshold:
+		adjusted = original.Add(receivedAt.Sub(*sentAt))
+		if adjusted.After(receivedAt) {
+			adjusted = receivedAt
+		}
+	case original.After(receivedAt.Add(threshold)):
+		adjusted = receivedAt
+	default:
+		return
+	}
+
+	eventData.Timestamp = &adjusted
+	eventData.OriginalTimestamp = &original
+}
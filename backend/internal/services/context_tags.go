@@ -0,0 +1,94 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxContextTagLength bounds the characters of tags derived from contexts
+const maxContextTagLength = 200
+
+// contextTagSources are the contexts tags are derived from, with the field naming each
+// context and the fields appended to it for the summary tag named after the context
+var contextTagSources = []struct {
+	context string
+	name    string
+	details []string
+}{
+	{"os", "name", []string{"version"}},
+	{"browser", "name", []string{"version"}},
+	{"device", "family", []string{"model"}},
+	{"runtime", "name", []string{"version"}},
+}
+
+// contextTagKeys are the tags derived from contexts, user agents and IP addresses, which
+// issues can be searched by
+var contextTagKeys = map[string]bool{
+	"os": true, "os.name": true,
+	"browser": true, "browser.name": true, "browser.version": true,
+	"device": true, "device.family": true,
+	"runtime": true, "runtime.name": true,
+	"geo.country_code": true, "geo.region": true,
+}
+
+// IsContextTagKey reports whether a tag is derived from the contexts, user agent or IP address
+// of events, such as os.name, browser or geo.country_code
+func IsContextTagKey(key string) bool {
+	return contextTagKeys[key]
+}
+
+// contextTags derives tags from the os, browser, device and runtime contexts of an event:
+// the context's name, such as os.name "Windows", and a summary such as os "Windows 11".
+// Contexts are found by their key or, like Sentry, by their type.
+func contextTags(contexts map[string]interface{}) map[string]string {
+	if len(contexts) == 0 {
+		return nil
+	}
+
+	tags := make(map[string]string)
+	for _, source := range contextTagSources {
+		context := findContext(contexts, source.context)
+		if context == nil {
+			continue
+		}
+		name := contextString(context, source.name)
+		if name == "" {
+			continue
+		}
+
+		summary := []string{name}
+		for _, field := range source.details {
+			if detail := contextString(context, field); detail != "" {
+				summary = append(summary, detail)
+			}
+		}
+		tags[source.context+"."+source.name] = *truncateStringPtr(&name, maxContextTagLength)
+		joined := strings.Join(summary, " ")
+		tags[source.context] = *truncateStringPtr(&joined, maxContextTagLength)
+	}
+	return tags
+}
+
+// findContext returns the context with the given key, or else one of that type
+func findContext(contexts map[string]interface{}, contextType string) map[string]interface{} {
+	if context, ok := contexts[contextType].(map[string]interface{}); ok {
+		return context
+	}
+	for _, value := range contexts {
+		if context, ok := value.(map[string]interface{}); ok && context["type"] == contextType {
+			return context
+		}
+	}
+	return nil
+}
+
+// contextString returns a context field as text, "" when missing or not a scalar
+func contextString(context map[string]interface{}, field string) string {
+	switch value := context[field].(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case float64, bool:
+		return fmt.Sprint(value)
+	}
+	return ""
+}
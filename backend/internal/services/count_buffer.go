@@ -0,0 +1,35 @@
+package services
+
+import "sync"
+
+// takeCounts takes the counts buffered in pending, guarded by mu, leaving it empty for the
+// counts of the next flush
+func takeCounts[K comparable](mu *sync.Mutex, pending *map[K]int64) map[K]int64 {
+	mu.Lock()
+	defer mu.Unlock()
+	counts := *pending
+	*pending = make(map[K]int64)
+	return counts
+}
+
+// requeueCounts adds counts that were not written back to pending, for the next flush
+func requeueCounts[K comparable](mu *sync.Mutex, pending *map[K]int64, counts map[K]int64) {
+	mu.Lock()
+	defer mu.Unlock()
+	for key, count := range counts {
+		(*pending)[key] += count
+	}
+}
+
+// flushCounts writes counts taken from pending with upsert, one key at a time. When a write
+// fails, the counts not written yet are put back in pending and the error is returned.
+func flushCounts[K comparable](mu *sync.Mutex, pending *map[K]int64, counts map[K]int64, upsert func(key K, count int64) error) error {
+	for key, count := range counts {
+		if err := upsert(key, count); err != nil {
+			requeueCounts(mu, pending, counts)
+			return err
+		}
+		delete(counts, key)
+	}
+	return nil
+}
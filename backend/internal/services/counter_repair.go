@@ -0,0 +1,133 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// defaultCounterRepairBatchSize is the number of issues repaired per batch by default
+const defaultCounterRepairBatchSize = 200
+
+// CounterRepairBatch reports one batch of a counter repair. Issues are repaired in ID order,
+// so a repair stopped midway resumes after LastIssueID.
+type CounterRepairBatch struct {
+	Checked     int        // Issues checked
+	Repaired    int        // Issues whose counters were corrected
+	Changed     int        // Issues left alone because events arrived during the repair
+	LastIssueID *uuid.UUID // Last issue of the batch, nil when there were none left
+}
+
+// CounterRepairService recomputes the counters of issues (times_seen, first_seen and
+// last_seen) from their stored events, to repair drift after bugs or partial failures.
+// Comment counts and issue list statistics are computed from their rows when read, so
+// they need no repair.
+type CounterRepairService struct {
+	db *database.DB
+}
+
+// NewCounterRepairService creates a new counter repair service
+func NewCounterRepairService(db *database.DB) *CounterRepairService {
+	return &CounterRepairService{db: db}
+}
+
+// RepairBatch repairs the counters of up to batchSize issues of a project whose ID follows
+// after (all issues when nil). Projects that sample events store only some of the events
+// counted, so their counters are only ever raised to what the stored events show.
+func (s *CounterRepairService) RepairBatch(projectID uuid.UUID, after *uuid.UUID, batchSize int) (*CounterRepairBatch, error) {
+	if batchSize <= 0 {
+		batchSize = defaultCounterRepairBatchSize
+	}
+
+	var project models.Project
+	if err := s.db.Select("id", "event_sample_threshold").Where("id = ?", projectID).First(&project).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("failed to load project: %w", err)
+	}
+	sampled := project.EventSampleThreshold != nil && *project.EventSampleThreshold > 0
+
+	query := s.db.Select("id", "times_seen", "first_seen", "last_seen").
+		Where("project_id = ?", projectID)
+	if after != nil {
+		query = query.Where("id > ?", *after)
+	}
+	var issues []models.Issue
+	if err := query.Order("id").Limit(batchSize).Find(&issues).Error; err != nil {
+		return nil, fmt.Errorf("failed to load issues: %w", err)
+	}
+
+	batch := &CounterRepairBatch{}
+	for i := range issues {
+		repaired, changed, err := s.repairIssue(&issues[i], sampled)
+		if err != nil {
+			return nil, fmt.Errorf("failed to repair issue %s: %w", issues[i].ID, err)
+		}
+		batch.Checked++
+		if repaired {
+			batch.Repaired++
+		}
+		if changed {
+			batch.Changed++
+		}
+		batch.LastIssueID = &issues[i].ID
+	}
+	return batch, nil
+}
+
+// repairIssue recomputes the counters of an issue from its events. The counters are only
+// updated when they are still the ones read, so events ingested meanwhile are not lost;
+// changed reports such issues.
+func (s *CounterRepairService) repairIssue(issue *models.Issue, sampled bool) (repaired, changed bool, err error) {
+	events := s.db.Model(&models.Event{}).Where("issue_id = ?", issue.ID)
+
+	var count int64
+	if err := events.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+		return false, false, err
+	}
+	if count == 0 {
+		// Nothing to recompute from, e.g. all events were sampled out
+		return false, false, nil
+	}
+
+	var first, last []time.Time
+	if err := events.Session(&gorm.Session{}).Order("timestamp").Limit(1).Pluck("timestamp", &first).Error; err != nil {
+		return false, false, err
+	}
+	if err := events.Session(&gorm.Session{}).Order("timestamp DESC").Limit(1).Pluck("timestamp", &last).Error; err != nil {
+		return false, false, err
+	}
+
+	timesSeen, firstSeen, lastSeen := int(count), first[0], last[0]
+	if sampled {
+		timesSeen = max(timesSeen, issue.TimesSeen)
+		if issue.LastSeen.After(lastSeen) {
+			lastSeen = issue.LastSeen
+		}
+	}
+	if timesSeen == issue.TimesSeen && firstSeen.Equal(issue.FirstSeen) && lastSeen.Equal(issue.LastSeen) {
+		return false, false, nil
+	}
+
+	result := s.db.Model(&models.Issue{}).
+		Where("id = ? AND times_seen = ? AND last_seen = ?", issue.ID, issue.TimesSeen, issue.LastSeen).
+		Updates(map[string]interface{}{
+			"times_seen": timesSeen,
+			"first_seen": firstSeen,
+			"last_seen":  lastSeen,
+		})
+	if result.Error != nil {
+		return false, false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, true, nil
+	}
+	return true, false, nil
+}
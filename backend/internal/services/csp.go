@@ -0,0 +1,184 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"minisentry/internal/dto"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCSPReport = errors.New("invalid CSP report")
+
+// cspPlatform marks events created from CSP violation reports
+const cspPlatform = "csp"
+
+// cspDirectiveKinds names the resource kind behind each fetch directive, as used in issue titles
+var cspDirectiveKinds = map[string]string{
+	"child-src":       "child",
+	"connect-src":     "connect",
+	"default-src":     "default",
+	"font-src":        "font",
+	"frame-src":       "frame",
+	"img-src":         "image",
+	"manifest-src":    "manifest",
+	"media-src":       "media",
+	"object-src":      "object",
+	"prefetch-src":    "prefetch",
+	"script-src":      "script",
+	"script-src-attr": "script",
+	"script-src-elem": "script",
+	"style-src":       "style",
+	"style-src-attr":  "style",
+	"style-src-elem":  "style",
+	"worker-src":      "worker",
+}
+
+// ProcessCSPReport stores a browser CSP violation report as an event of a CSP issue.
+// Reports are grouped by effective directive and blocked source rather than by page.
+func (es *ErrorService) ProcessCSPReport(projectID uuid.UUID, report *dto.CSPReport, environment string, release *string, clientIP, userAgent string) (*dto.ErrorEventResponse, error) {
+	if report == nil {
+		return nil, fmt.Errorf("%w: report is nil", ErrInvalidCSPReport)
+	}
+
+	directive := cspEffectiveDirective(report)
+	if directive == "" {
+		return nil, fmt.Errorf("%w: violated-directive or effective-directive is required", ErrInvalidCSPReport)
+	}
+	blockedSource := cspBlockedSource(report.BlockedURI)
+
+	normalized := &dto.NormalizedErrorData{
+		ProjectID:   projectID,
+		EventID:     uuid.New().String(),
+		Timestamp:   time.Now(),
+		Level:       "error",
+		Platform:    cspPlatform,
+		Environment: "production",
+		Release:     release,
+		Tags: map[string]string{
+			"effective-directive": directive,
+			"blocked-uri":         blockedSource,
+		},
+		ExtraData: map[string]interface{}{
+			"csp": report,
+		},
+	}
+	if environment != "" {
+		normalized.Environment = environment
+	}
+
+	title := cspTitle(directive, blockedSource)
+	normalized.Message = &title
+
+	culprit := directive
+	if report.ViolatedDirective != "" {
+		culprit = report.ViolatedDirective
+	}
+	normalized.Culprit = &culprit
+
+	headers := make(map[string]string)
+	if userAgent != "" {
+		headers["User-Agent"] = userAgent
+		normalized.Tags["user_agent"] = userAgent
+	}
+	if report.Referrer != "" {
+		headers["Referer"] = report.Referrer
+	}
+	if clientIP != "" {
+		normalized.Tags["client_ip"] = clientIP
+	}
+	if report.DocumentURI != "" {
+		documentURI := report.DocumentURI
+		normalized.RequestData = &dto.RequestData{URL: &documentURI, Headers: headers}
+	}
+	addUserAgentTags(normalized, userAgent)
+
+	normalized.Fingerprint = es.fingerprintService.GenerateCSPFingerprint(directive, blockedSource)
+	normalized.CustomFingerprint = true
+
+	return es.persistEvent(projectID, normalized)
+}
+
+// cspEffectiveDirective returns the directive name the report was enforced under
+func cspEffectiveDirective(report *dto.CSPReport) string {
+	if report.EffectiveDirective != "" {
+		return strings.ToLower(report.EffectiveDirective)
+	}
+	// violated-directive may carry the policy's source list, e.g. "script-src 'self'"
+	fields := strings.Fields(report.ViolatedDirective)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(fields[0])
+}
+
+// cspSourceSchemes are the schemes of blocked URIs grouped on their scheme alone: their
+// paths, and the IDs of extensions, differ for every resource or install
+var cspSourceSchemes = []string{
+	"data", "blob", "filesystem", "about", "chrome-extension", "moz-extension",
+	"safari-extension", "safari-web-extension", "ms-browser-extension",
+}
+
+// cspDefaultPorts are the ports left out of blocked hosts for the schemes they default to
+var cspDefaultPorts = map[string]string{"http": "80", "https": "443", "ws": "80", "wss": "443"}
+
+// cspBlockedSource reduces a blocked-uri to what should be grouped on: the keyword for
+// inline code and special schemes, otherwise the host the resource came from, lowercased
+// and without its default port, so every URL of a host lands in one issue
+func cspBlockedSource(blockedURI string) string {
+	blockedURI = strings.TrimSpace(blockedURI)
+	switch strings.ToLower(blockedURI) {
+	case "", "inline":
+		return "'unsafe-inline'"
+	case "eval":
+		return "'unsafe-eval'"
+	case "wasm-eval":
+		return "'wasm-unsafe-eval'"
+	case "self":
+		return "'self'"
+	case "trusted-types-policy", "trusted-types-sink":
+		return strings.ToLower(blockedURI)
+	}
+
+	lower := strings.ToLower(blockedURI)
+	for _, scheme := range cspSourceSchemes {
+		if lower == scheme || strings.HasPrefix(lower, scheme+":") {
+			return scheme + ":"
+		}
+	}
+
+	// Some browsers leave the scheme out, e.g. cdn.example.com/app.js
+	parsed, err := url.Parse(blockedURI)
+	if err == nil && parsed.Host == "" && !strings.Contains(blockedURI, "://") {
+		parsed, err = url.Parse("//" + blockedURI)
+	}
+	if err != nil || parsed.Host == "" {
+		return blockedURI
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	if port := parsed.Port(); port != "" && port != cspDefaultPorts[strings.ToLower(parsed.Scheme)] {
+		host += ":" + port
+	}
+	return host
+}
+
+// cspTitle builds a readable issue title, e.g. "Blocked 'script' from 'cdn.example.com'"
+func cspTitle(directive, blockedSource string) string {
+	kind, ok := cspDirectiveKinds[directive]
+	if !ok {
+		kind = directive
+	}
+
+	switch blockedSource {
+	case "'unsafe-inline'":
+		return fmt.Sprintf("Blocked unsafe inline '%s'", kind)
+	case "'unsafe-eval'":
+		return fmt.Sprintf("Blocked unsafe eval() '%s'", kind)
+	}
+	return fmt.Sprintf("Blocked '%s' from '%s'", kind, blockedSource)
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+
+	"minisentry/internal/dto"
+)
+
+// filteredValue replaces the values removed by data scrubbing
+const filteredValue = "[Filtered]"
+
+// defaultSensitiveFields are always scrubbed. Field names match when they contain one of
+// these, ignoring case and separators, so "X-Api-Key" and "user_password" match too.
+var defaultSensitiveFields = []string{
+	"password", "passwd", "secret", "apikey", "auth", "credentials", "privatekey",
+	"token", "cookie", "sessionid", "creditcard", "cardnumber", "ccnumber", "cvv",
+}
+
+// cardNumberPattern finds candidate payment card numbers: 13 to 19 digits, optionally
+// grouped with spaces or dashes. Candidates are confirmed with the Luhn checksum.
+var cardNumberPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+
+// DataScrubber removes personal and secret data from ingested events before they are
+// stored: values of sensitive fields, payment card numbers, auth headers and cookies
+type DataScrubber struct {
+	sensitiveFields []string
+}
+
+// NewDataScrubber creates a scrubber for the default sensitive fields plus the given ones
+func NewDataScrubber(sensitiveFields []string) *DataScrubber {
+	fields := append([]string(nil), defaultSensitiveFields...)
+	for _, field := range sensitiveFields {
+		if field = normalizeFieldName(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return &DataScrubber{sensitiveFields: fields}
+}
+
+// SetDataScrubber enables data scrubbing of ingested error events. It must be called
+// before the server starts.
+func (es *ErrorService) SetDataScrubber(scrubber *DataScrubber) {
+	es.scrubber = scrubber
+}
+
+// scrubEventData removes sensitive data from a normalized event: the defaults first, then
+// the project's own scrubbing rules
+func (es *ErrorService) scrubEventData(normalized *dto.NormalizedErrorData) {
+	if es.scrubber != nil {
+		es.scrubber.scrub(normalized)
+	}
+	if es.scrubbingRules != nil {
+		es.scrubbingRules.Apply(normalized)
+	}
+}
+
+// scrub scrubs the request data, user data, extra data, stack frame variables, chained
+// exceptions and breadcrumbs of a normalized event, as well as card numbers in its message
+func (s *DataScrubber) scrub(normalized *dto.NormalizedErrorData) {
+	normalized.Message = s.scrubStringPtr(normalized.Message)
+	normalized.ExceptionValue = s.scrubStringPtr(normalized.ExceptionValue)
+
+	if request := normalized.RequestData; request != nil {
+		scrubbed := *request
+		scrubbed.URL = s.scrubURL(request.URL)
+		if request.QueryString != nil {
+			query := s.scrubQueryString(*request.QueryString)
+			scrubbed.QueryString = &query
+		}
+		if data, ok := request.Data.(string); ok {
+			scrubbed.Data = s.scrubQueryString(data)
+		} else {
+			scrubbed.Data = s.scrubValue(request.Data)
+		}
+		scrubbed.Headers = s.scrubStringMap(request.Headers)
+		scrubbed.Env = s.scrubStringMap(request.Env)
+		if len(request.Cookies) > 0 {
+			scrubbed.Cookies = make(map[string]string, len(request.Cookies))
+			for name := range request.Cookies {
+				scrubbed.Cookies[name] = filteredValue
+			}
+		}
+		normalized.RequestData = &scrubbed
+	}
+
+	if user := normalized.UserContext; user != nil && user.Data != nil {
+		scrubbed := *user
+		scrubbed.Data = s.scrubMap(user.Data)
+		normalized.UserContext = &scrubbed
+	}
+
+	if normalized.ExtraData != nil {
+		normalized.ExtraData = s.scrubMap(normalized.ExtraData)
+	}
+
+	if normalized.Contexts != nil {
+		normalized.Contexts = s.scrubMap(normalized.Contexts)
+	}
+
+	normalized.StackTrace = s.scrubFrames(normalized.StackTrace)
+
+	if len(normalized.Exceptions) > 0 {
+		exceptions := make([]dto.ExceptionValue, len(normalized.Exceptions))
+		for i, exception := range normalized.Exceptions {
+			exception.Value = s.scrubStringPtr(exception.Value)
+			if exception.Stacktrace != nil {
+				stacktrace := *exception.Stacktrace
+				stacktrace.Frames = s.scrubFrames(stacktrace.Frames)
+				exception.Stacktrace = &stacktrace
+			}
+			exceptions[i] = exception
+		}
+		normalized.Exceptions = exceptions
+	}
+
+	if len(normalized.Breadcrumbs) > 0 {
+		breadcrumbs := make([]dto.BreadcrumbData, len(normalized.Breadcrumbs))
+		for i, breadcrumb := range normalized.Breadcrumbs {
+			breadcrumb.Message = s.scrubStringPtr(breadcrumb.Message)
+			if breadcrumb.Data != nil {
+				breadcrumb.Data = s.scrubMap(breadcrumb.Data)
+			}
+			breadcrumbs[i] = breadcrumb
+		}
+		normalized.Breadcrumbs = breadcrumbs
+	}
+}
+
+// scrubFrames scrubs the variables of stack frames, returning a copy of the frames
+func (s *DataScrubber) scrubFrames(frames []dto.StackFrame) []dto.StackFrame {
+	if len(frames) == 0 {
+		return frames
+	}
+	scrubbed := make([]dto.StackFrame, len(frames))
+	for i, frame := range frames {
+		if frame.Vars != nil {
+			frame.Vars = s.scrubMap(frame.Vars)
+		}
+		scrubbed[i] = frame
+	}
+	return scrubbed
+}
+
+// isSensitiveField reports whether the values of a field must be scrubbed
+func (s *DataScrubber) isSensitiveField(name string) bool {
+	name = normalizeFieldName(name)
+	if name == "" {
+		return false
+	}
+	for _, field := range s.sensitiveFields {
+		if strings.Contains(name, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeFieldName lowercases a field name and drops its separators
+func normalizeFieldName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', ' ', '.':
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(name)))
+}
+
+// scrubMap returns a copy of data with sensitive fields filtered, recursively
+func (s *DataScrubber) scrubMap(data map[string]interface{}) map[string]interface{} {
+	scrubbed := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		if s.isSensitiveField(key) && value != nil {
+			scrubbed[key] = filteredValue
+			continue
+		}
+		scrubbed[key] = s.scrubValue(value)
+	}
+	return scrubbed
+}
+
+func (s *DataScrubber) scrubValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return s.scrubMap(v)
+	case []interface{}:
+		scrubbed := make([]interface{}, len(v))
+		for i, item := range v {
+			scrubbed[i] = s.scrubValue(item)
+		}
+		return scrubbed
+	case string:
+		return scrubCardNumbers(v)
+	}
+	return value
+}
+
+func (s *DataScrubber) scrubStringMap(data map[string]string) map[string]string {
+	if data == nil {
+		return nil
+	}
+	scrubbed := make(map[string]string, len(data))
+	for key, value := range data {
+		if s.isSensitiveField(key) {
+			scrubbed[key] = filteredValue
+			continue
+		}
+		scrubbed[key] = scrubCardNumbers(value)
+	}
+	return scrubbed
+}
+
+func (s *DataScrubber) scrubStringPtr(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	scrubbed := scrubCardNumbers(*value)
+	return &scrubbed
+}
+
+// scrubQueryString filters the values of sensitive parameters of a query string or form
+// body, keeping its encoding and parameter order
+func (s *DataScrubber) scrubQueryString(query string) string {
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		key, _, hasValue := strings.Cut(param, "=")
+		if hasValue && s.isSensitiveField(key) {
+			params[i] = key + "=" + filteredValue
+			continue
+		}
+		params[i] = scrubCardNumbers(param)
+	}
+	return strings.Join(params, "&")
+}
+
+// scrubURL scrubs the query string of a URL
+func (s *DataScrubber) scrubURL(rawURL *string) *string {
+	if rawURL == nil {
+		return nil
+	}
+	base, query, hasQuery := strings.Cut(*rawURL, "?")
+	if !hasQuery {
+		return s.scrubStringPtr(rawURL)
+	}
+	fragment := ""
+	if hash := strings.Index(query, "#"); hash >= 0 {
+		query, fragment = query[:hash], query[hash:]
+	}
+	scrubbed := scrubCardNumbers(base) + "?" + s.scrubQueryString(query) + fragment
+	return &scrubbed
+}
+
+// scrubCardNumbers replaces the payment card numbers in text
+func scrubCardNumbers(text string) string {
+	if len(text) < 13 {
+		return text
+	}
+	return cardNumberPattern.ReplaceAllStringFunc(text, func(match string) string {
+		if isLuhnValid(match) {
+			return filteredValue
+		}
+		return match
+	})
+}
+
+// isLuhnValid reports whether the digits of a candidate card number pass the Luhn checksum
+func isLuhnValid(candidate string) bool {
+	sum, digits := 0, 0
+	double := false
+	for i := len(candidate) - 1; i >= 0; i-- {
+		c := candidate[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		digit := int(c - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		digits++
+		double = !double
+	}
+	return digits >= 13 && digits <= 19 && sum%10 == 0
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+// maxEnvironmentNameLength matches the environment columns of events, transactions and sessions
+const maxEnvironmentNameLength = 100
+
+// environmentRecorder creates the environments ingested events, transactions and sessions
+// report, so projects can list them without scanning their events
+type environmentRecorder struct {
+	db *database.DB
+
+	// known caches the environments already created, keyed by project@name
+	known sync.Map
+}
+
+func newEnvironmentRecorder(db *database.DB) *environmentRecorder {
+	return &environmentRecorder{db: db}
+}
+
+// record creates an environment of a project unless it exists. Failures are only logged,
+// since they must not reject the data reporting the environment.
+func (er *environmentRecorder) record(projectID uuid.UUID, name string) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxEnvironmentNameLength {
+		return
+	}
+	key := projectID.String() + "@" + name
+	if _, known := er.known.Load(key); known {
+		return
+	}
+
+	environment := models.Environment{ProjectID: projectID, Name: name}
+	err := er.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}, {Name: "name"}},
+		DoNothing: true,
+	}).Create(&environment).Error
+	if err != nil {
+		log.Printf("Failed to record environment %s of project %s: %v", name, projectID, err)
+		return
+	}
+	er.known.Store(key, true)
+}
+
+// ListEnvironments returns the environments a project's data was sent from, by name
+func (s *ProjectService) ListEnvironments(projectID uuid.UUID) (*dto.EnvironmentListResponse, error) {
+	var environments []models.Environment
+	if err := s.db.DB.Where("project_id = ?", projectID).Order("name").Find(&environments).Error; err != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+
+	response := &dto.EnvironmentListResponse{Environments: make([]dto.EnvironmentResponse, len(environments))}
+	for i, environment := range environments {
+		response.Environments[i] = dto.EnvironmentResponse{
+			Name:      environment.Name,
+			FirstSeen: environment.CreatedAt,
+		}
+	}
+	return response, nil
+}
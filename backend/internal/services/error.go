@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
@@ -13,27 +14,108 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/datatypes"
-	"gorm.io/gorm"
 )
 
 var (
 	ErrInvalidEventData = errors.New("invalid event data")
 	ErrEventExists      = errors.New("event already exists")
+	ErrEventFiltered    = errors.New("event discarded by inbound filter")
 )
 
 type ErrorService struct {
 	db                 *database.DB
+	store              EventStore
 	fingerprintService *FingerprintService
+
+	// batcher, when set, buffers queued events for multi-row inserts
+	batcher *eventBatcher
+
+	// limits bounds the size of ingested events
+	limits EventLimits
+
+	// scrubber, when set, removes sensitive data from events before they are stored
+	scrubber *DataScrubber
+
+	// scrubbingRules, when set, applies the projects' own data scrubbing rules
+	scrubbingRules *ScrubbingRuleService
+
+	// groupingConfigs, when set, fingerprints events as their project's grouping config says
+	groupingConfigs *GroupingConfigService
+
+	// ownership, when set, assigns new issues by their project's ownership rules
+	ownership *OwnershipService
+
+	// dedup, when set, rejects retried events without a database lookup
+	dedup EventDedupCache
+
+	// inboundFilters, when set, discards events matching their project's inbound filters
+	inboundFilters *InboundFilterService
+
+	// sampler, when set, stores only some of the events of hot issues
+	sampler *EventSampler
+
+	// clockSkewThreshold, when set, is the clock skew beyond which event timestamps are corrected
+	clockSkewThreshold time.Duration
+
+	// geoIP, when set, tags events with the country and region of their IP address
+	geoIP *GeoIP
+
+	// maintenance, when set, holds back queued events during maintenance windows
+	maintenance *MaintenanceService
+
+	// statuses, when set, records what becomes of events accepted asynchronously
+	statuses EventStatusTracker
+
+	// volume, when set, counts accepted events per day, environment and release
+	volume *EventVolumeService
+
+	// outcomes, when set, counts events deduplicated, sampled out or rate limited
+	outcomes *IssueOutcomeService
+
+	// releases creates the releases events report
+	releases *releaseRecorder
+
+	// environments creates the environments events report
+	environments *environmentRecorder
+
+	// issueCreatedListeners are notified of every issue created by ingestion
+	issueCreatedListeners []func(issue *models.Issue)
+
+	// issueRegressedListeners are notified of every resolved issue reopened by ingestion
+	issueRegressedListeners []func(issue *models.Issue)
+
+	// processors are the custom ingestion steps compiled into the server
+	processors []EventProcessor
 }
 
 // NewErrorService creates a new error processing service
 func NewErrorService(db *database.DB) *ErrorService {
+	return NewErrorServiceWithStore(db, NewGormEventStore(db.DB))
+}
+
+// NewErrorServiceWithStore creates an error processing service persisting through the given store
+func NewErrorServiceWithStore(db *database.DB, store EventStore) *ErrorService {
 	return &ErrorService{
 		db:                 db,
+		store:              store,
 		fingerprintService: NewFingerprintService(),
+		releases:           newReleaseRecorder(db),
+		environments:       newEnvironmentRecorder(db),
 	}
 }
 
+// OnIssueCreated registers a listener called after ingestion creates a new issue.
+// Listeners run synchronously and must not be registered after the server has started.
+func (es *ErrorService) OnIssueCreated(listener func(issue *models.Issue)) {
+	es.issueCreatedListeners = append(es.issueCreatedListeners, listener)
+}
+
+// OnIssueRegressed registers a listener called after an event reopens a resolved issue.
+// Listeners run synchronously and must not be registered after the server has started.
+func (es *ErrorService) OnIssueRegressed(listener func(issue *models.Issue)) {
+	es.issueRegressedListeners = append(es.issueRegressedListeners, listener)
+}
+
 // ProcessErrorEvent is the main entry point for error processing
 func (es *ErrorService) ProcessErrorEvent(projectID uuid.UUID, eventData *dto.ErrorEventRequest, clientIP, userAgent string) (*dto.ErrorEventResponse, error) {
 	// Validate the error payload
@@ -46,22 +128,59 @@ func (es *ErrorService) ProcessErrorEvent(projectID uuid.UUID, eventData *dto.Er
 	if err != nil {
 		return nil, fmt.Errorf("normalization failed: %w", err)
 	}
+	if err := es.runProcessors(normalizedData); err != nil {
+		return nil, err
+	}
 
 	// Generate or use custom fingerprint
 	fingerprint := es.generateFingerprint(normalizedData, eventData.Fingerprint)
 	normalizedData.Fingerprint = fingerprint
 
+	if es.isFiltered(projectID, normalizedData, userAgent, clientIP) {
+		return nil, ErrEventFiltered
+	}
+
+	return es.persistEvent(projectID, normalizedData)
+}
+
+// persistEvent groups a normalized, fingerprinted event into its issue and stores it
+func (es *ErrorService) persistEvent(projectID uuid.UUID, normalizedData *dto.NormalizedErrorData) (*dto.ErrorEventResponse, error) {
+	if es.isCachedDuplicate(projectID, normalizedData.EventID) {
+		es.countOutcome(projectID, normalizedData.Fingerprint, IssueOutcomeDuplicate)
+		return nil, ErrEventExists
+	}
+
 	// Find or create issue
 	issue, err := es.FindOrCreateIssue(projectID, normalizedData)
 	if err != nil {
 		return nil, fmt.Errorf("issue management failed: %w", err)
 	}
+	es.releases.record(projectID, normalizedData.Release)
+	es.environments.record(projectID, normalizedData.Environment)
+
+	// Count events sampled out without storing them; retries of them are rejected as duplicates
+	if es.isSampledOut(projectID, issue.ID) {
+		es.rememberEvent(projectID, normalizedData.EventID)
+		es.countVolume(projectID, normalizedData)
+		es.countOutcome(projectID, normalizedData.Fingerprint, IssueOutcomeSampled)
+		if err := es.updateIssueStats(issue); err != nil {
+			return nil, fmt.Errorf("issue stats update failed: %w", err)
+		}
+		return &dto.ErrorEventResponse{
+			EventID:   normalizedData.EventID,
+			ProjectID: projectID,
+			IssueID:   issue.ID,
+			CreatedAt: time.Now(),
+			Sampled:   true,
+		}, nil
+	}
 
 	// Create error event
 	event, err := es.CreateErrorEvent(issue.ID, normalizedData)
 	if err != nil {
 		return nil, fmt.Errorf("event creation failed: %w", err)
 	}
+	es.countVolume(projectID, normalizedData)
 
 	// Update issue statistics
 	if err := es.updateIssueStats(issue); err != nil {
@@ -77,25 +196,52 @@ func (es *ErrorService) ProcessErrorEvent(projectID uuid.UUID, eventData *dto.Er
 	}, nil
 }
 
-// ValidateErrorPayload validates the incoming error payload
+// validLevels are the event levels SDKs may send
+var validLevels = []string{"debug", "info", "warning", "error", "fatal"}
+
+// ValidationError lists every field that made an event invalid. It matches
+// ErrInvalidEventData with errors.Is.
+type ValidationError struct {
+	Fields []dto.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		if field.Path == "" {
+			problems = append(problems, field.Message)
+		} else {
+			problems = append(problems, field.Path+": "+field.Message)
+		}
+	}
+	return fmt.Sprintf("%v: %s", ErrInvalidEventData, strings.Join(problems, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidEventData
+}
+
+// ValidateErrorPayload validates the incoming error payload, returning a *ValidationError
+// with every offending field
 func (es *ErrorService) ValidateErrorPayload(eventData *dto.ErrorEventRequest) error {
 	if eventData == nil {
-		return fmt.Errorf("%w: event data is nil", ErrInvalidEventData)
+		return &ValidationError{Fields: []dto.FieldError{{Message: "event data is nil"}}}
 	}
 
+	var fields []dto.FieldError
+
 	// At least one of message or exception should be present
 	if eventData.Message == nil && eventData.Exception == nil {
-		return fmt.Errorf("%w: neither message nor exception provided", ErrInvalidEventData)
+		fields = append(fields, dto.FieldError{Path: "exception", Message: "neither message nor exception provided"})
 	}
 
 	// If exception is provided, it should have values
 	if eventData.Exception != nil && len(eventData.Exception.Values) == 0 {
-		return fmt.Errorf("%w: exception provided but no exception values", ErrInvalidEventData)
+		fields = append(fields, dto.FieldError{Path: "exception.values", Message: "exception provided but no exception values"})
 	}
 
 	// Validate level if provided
 	if eventData.Level != nil {
-		validLevels := []string{"debug", "info", "warning", "error", "fatal"}
 		isValid := false
 		for _, level := range validLevels {
 			if strings.ToLower(*eventData.Level) == level {
@@ -104,18 +250,34 @@ func (es *ErrorService) ValidateErrorPayload(eventData *dto.ErrorEventRequest) e
 			}
 		}
 		if !isValid {
-			return fmt.Errorf("%w: invalid level '%s'", ErrInvalidEventData, *eventData.Level)
+			fields = append(fields, dto.FieldError{
+				Path:    "level",
+				Message: fmt.Sprintf("invalid level '%s', expected one of %s", *eventData.Level, strings.Join(validLevels, ", ")),
+			})
 		}
 	}
 
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
 	return nil
 }
 
-// NormalizeErrorData cleans and standardizes error data
+// NormalizeErrorData cleans and standardizes error data, marking its frames in-app by the
+// project's rules, scrubbing it and applying the event limits
 func (es *ErrorService) NormalizeErrorData(projectID uuid.UUID, eventData *dto.ErrorEventRequest, clientIP, userAgent string) (*dto.NormalizedErrorData, error) {
+	normalized := es.normalizeErrorData(projectID, eventData, clientIP, userAgent)
+	es.applyInAppRules(normalized)
+	es.scrubEventData(normalized)
+	es.applyEventLimits(normalized)
+	return normalized, nil
+}
+
+// normalizeErrorData cleans and standardizes error data as sent, before scrubbing
+func (es *ErrorService) normalizeErrorData(projectID uuid.UUID, eventData *dto.ErrorEventRequest, clientIP, userAgent string) *dto.NormalizedErrorData {
 	normalized := &dto.NormalizedErrorData{
 		ProjectID: projectID,
-		Platform:  "javascript", // Default platform
+		Platform:  resolvePlatform(eventData),
 	}
 
 	// Generate or use provided event ID
@@ -131,6 +293,7 @@ func (es *ErrorService) NormalizeErrorData(projectID uuid.UUID, eventData *dto.E
 	} else {
 		normalized.Timestamp = time.Now()
 	}
+	normalized.OriginalTimestamp = eventData.OriginalTimestamp
 
 	// Set level
 	if eventData.Level != nil {
@@ -139,11 +302,6 @@ func (es *ErrorService) NormalizeErrorData(projectID uuid.UUID, eventData *dto.E
 		normalized.Level = "error"
 	}
 
-	// Set platform
-	if eventData.Platform != nil {
-		normalized.Platform = *eventData.Platform
-	}
-
 	// Set environment
 	if eventData.Environment != nil {
 		normalized.Environment = *eventData.Environment
@@ -172,7 +330,9 @@ func (es *ErrorService) NormalizeErrorData(projectID uuid.UUID, eventData *dto.E
 
 	// Extract exception data
 	if eventData.Exception != nil && len(eventData.Exception.Values) > 0 {
-		mainException := eventData.Exception.Values[0] // Use the first exception
+		// Exceptions are sent oldest first, so the exception thrown is the last one
+		values := eventData.Exception.Values
+		mainException := values[len(values)-1]
 		normalized.ExceptionType = mainException.Type
 		normalized.ExceptionValue = mainException.Value
 
@@ -180,6 +340,11 @@ func (es *ErrorService) NormalizeErrorData(projectID uuid.UUID, eventData *dto.E
 		if mainException.Stacktrace != nil {
 			normalized.StackTrace = mainException.Stacktrace.Frames
 		}
+
+		// Keep chained exceptions (causes) with their own stack traces
+		if len(eventData.Exception.Values) > 1 {
+			normalized.Exceptions = eventData.Exception.Values
+		}
 	}
 
 	// Set user context
@@ -219,55 +384,198 @@ func (es *ErrorService) NormalizeErrorData(projectID uuid.UUID, eventData *dto.E
 		normalized.Breadcrumbs = eventData.Breadcrumbs
 	}
 
-	return normalized, nil
+	// Set contexts (device, os, runtime...), tagging the event with their names unless the
+	// SDK already did
+	if eventData.Contexts != nil {
+		normalized.Contexts = eventData.Contexts
+		for key, value := range contextTags(eventData.Contexts) {
+			if _, ok := normalized.Tags[key]; !ok {
+				normalized.Tags[key] = value
+			}
+		}
+	}
+
+	// Tag browser events with their browser, OS and device
+	addUserAgentTags(normalized, userAgent)
+	es.addGeoTags(normalized, clientIP)
+
+	// Link the event to the session replay that captured it, if any
+	normalized.ReplayID = extractReplayID(eventData)
+
+	// Record the route so errors can be filtered by transaction
+	normalized.Transaction = extractTransaction(eventData)
+
+	return normalized
 }
 
-// generateFingerprint creates a fingerprint for the error
+// generateFingerprint creates a fingerprint for the error and records the grouping config
+// version it follows. An issue fingerprint rule matching the event takes precedence over
+// the fingerprint sent with it, which takes precedence over the fingerprint template of its
+// project.
 func (es *ErrorService) generateFingerprint(normalizedData *dto.NormalizedErrorData, customFingerprint []string) string {
+	config := storedGroupingConfig{}.resolve()
+	config.Version = 1
+	fingerprints := es.fingerprintService.WithGrouping(config)
+	if es.groupingConfigs != nil {
+		config, fingerprints = es.groupingConfigs.projectFingerprints(normalizedData.ProjectID)
+	}
+
+	normalizedData.GroupingVersion = config.Version
+	if es.groupingConfigs != nil {
+		if fingerprint, ok := es.groupingConfigs.ruleFingerprint(normalizedData); ok {
+			normalizedData.CustomFingerprint = true
+			return fingerprint
+		}
+	}
 	if len(customFingerprint) > 0 {
-		return es.fingerprintService.CustomFingerprint(normalizedData, customFingerprint)
+		normalizedData.CustomFingerprint = true
+		return fingerprints.CustomFingerprint(normalizedData, customFingerprint)
 	}
-	return es.fingerprintService.GenerateErrorFingerprint(normalizedData)
+	return fingerprints.GroupingFingerprint(normalizedData)
 }
 
-// FindOrCreateIssue finds an existing issue or creates a new one
+// FindOrCreateIssue finds an existing issue or creates a new one. A resolved issue is
+// reopened as a regression, unless it was resolved in a release the event's release is not
+// newer than.
 func (es *ErrorService) FindOrCreateIssue(projectID uuid.UUID, normalizedData *dto.NormalizedErrorData) (*models.Issue, error) {
-	var issue models.Issue
-
 	// Try to find existing issue by fingerprint
-	result := es.db.DB.Where("project_id = ? AND fingerprint = ?", projectID, normalizedData.Fingerprint).First(&issue)
-	
-	if result.Error == nil {
-		// Issue exists, return it
-		return &issue, nil
+	existing, err := es.store.FindIssueByFingerprint(projectID, normalizedData.Fingerprint)
+	if err == nil {
+		if existing.Status == models.StatusResolved && !resolvedForRelease(existing, normalizedData.Release) {
+			if err := es.regressIssue(existing, normalizedData); err != nil {
+				return nil, err
+			}
+		}
+		if existing.Status == models.StatusIgnored {
+			if err := es.checkIgnoreCondition(existing, normalizedData); err != nil {
+				return nil, err
+			}
+		}
+		return existing, nil
 	}
 
-	if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+	if !errors.Is(err, ErrIssueNotFound) {
 		// Database error
-		return nil, fmt.Errorf("failed to query issue: %w", result.Error)
+		return nil, err
 	}
 
 	// Create new issue
-	issue = models.Issue{
-		ProjectID:   projectID,
-		Fingerprint: normalizedData.Fingerprint,
-		Title:       es.generateIssueTitle(normalizedData),
-		Culprit:     es.generateCulprit(normalizedData),
-		Type:        es.determineIssueType(normalizedData),
-		Level:       models.IssueLevel(normalizedData.Level),
-		Status:      models.StatusUnresolved,
-		FirstSeen:   normalizedData.Timestamp,
-		LastSeen:    normalizedData.Timestamp,
-		TimesSeen:   1,
+	issue := es.newIssue(projectID, normalizedData)
+	var ownershipRule *dto.OwnershipRule
+	if es.ownership != nil {
+		if rule, ok := es.ownership.owner(normalizedData, issue.Culprit); ok {
+			issue.AssigneeID = &rule.OwnerID
+			ownershipRule = &rule
+		}
+	}
+	if err := es.store.CreateIssue(&issue); err != nil {
+		return nil, err
+	}
+	if ownershipRule != nil {
+		if err := es.logAutoAssignment(&issue, ownershipRule); err != nil {
+			log.Printf("Failed to log the assignment of issue %s: %v", issue.ID, err)
+		}
+		if err := es.store.AddIssueParticipant(issue.ID, ownershipRule.OwnerID, models.SubscriptionAssigned); err != nil {
+			log.Printf("Failed to add the assignee of issue %s as a participant: %v", issue.ID, err)
+		}
 	}
 
-	if err := es.db.DB.Create(&issue).Error; err != nil {
-		return nil, fmt.Errorf("failed to create issue: %w", err)
+	for _, listener := range es.issueCreatedListeners {
+		listener(&issue)
 	}
 
 	return &issue, nil
 }
 
+// newIssue builds the issue a normalized event is the first event of
+func (es *ErrorService) newIssue(projectID uuid.UUID, normalizedData *dto.NormalizedErrorData) models.Issue {
+	return models.Issue{
+		ProjectID:       projectID,
+		Fingerprint:     normalizedData.Fingerprint,
+		Title:           es.generateIssueTitle(normalizedData),
+		Culprit:         es.generateCulprit(normalizedData),
+		Type:            es.determineIssueType(normalizedData),
+		Level:           models.IssueLevel(normalizedData.Level),
+		Status:          models.StatusUnresolved,
+		FirstSeen:       normalizedData.Timestamp,
+		LastSeen:        normalizedData.Timestamp,
+		TimesSeen:       1,
+		Transaction:     normalizedData.Transaction,
+		Platform:        normalizedData.Platform,
+		GroupingVersion: normalizedData.GroupingVersion,
+		Priority:        initialIssuePriority(models.IssueLevel(normalizedData.Level), 1),
+	}
+}
+
+// logAutoAssignment logs in a new issue's activity the ownership rule it was assigned by
+func (es *ErrorService) logAutoAssignment(issue *models.Issue, rule *dto.OwnershipRule) error {
+	data := map[string]interface{}{
+		"assignee_id": rule.OwnerID,
+		"ownership_rule": map[string]interface{}{
+			"type":    rule.Type,
+			"key":     rule.Key,
+			"pattern": rule.Pattern,
+		},
+	}
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal activity data: %w", err)
+	}
+	return es.store.CreateIssueActivity(&models.IssueActivity{
+		IssueID: issue.ID,
+		Type:    models.ActivityAssignment,
+		Data:    dataJSON,
+	})
+}
+
+// regressIssue reopens a resolved issue that received an event
+func (es *ErrorService) regressIssue(issue *models.Issue, normalizedData *dto.NormalizedErrorData) error {
+	data := map[string]interface{}{
+		"previous_status": string(models.StatusResolved),
+		"new_status":      string(models.StatusUnresolved),
+		"event_id":        normalizedData.EventID,
+		"environment":     normalizedData.Environment,
+	}
+	if normalizedData.Release != nil {
+		data["release"] = *normalizedData.Release
+	}
+	if issue.ResolvedInRelease != nil {
+		data["resolved_in_release"] = *issue.ResolvedInRelease
+	}
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal activity data: %w", err)
+	}
+
+	regressed, err := es.store.RegressIssue(issue, dataJSON)
+	if err != nil {
+		return err
+	}
+	if !regressed {
+		// A concurrent event regressed it, or a user changed its status
+		return nil
+	}
+	issue.Status = models.StatusUnresolved
+	issue.IsRegression = true
+	issue.ResolvedInRelease = nil
+
+	for _, listener := range es.issueRegressedListeners {
+		listener(issue)
+	}
+	return nil
+}
+
+// resolvedForRelease tells whether a resolved issue stays resolved for events of release.
+// Issues resolved in a release only do for that release and older ones; events without a
+// release, or of one that cannot be compared, reopen them.
+func resolvedForRelease(issue *models.Issue, release *string) bool {
+	if issue.ResolvedInRelease == nil || release == nil {
+		return false
+	}
+	order, ok := compareReleaseVersions(*release, *issue.ResolvedInRelease)
+	return ok && order <= 0
+}
+
 // generateIssueTitle creates a descriptive title for the issue
 func (es *ErrorService) generateIssueTitle(normalizedData *dto.NormalizedErrorData) string {
 	if normalizedData.ExceptionType != nil && normalizedData.ExceptionValue != nil {
@@ -287,12 +595,17 @@ func (es *ErrorService) generateIssueTitle(normalizedData *dto.NormalizedErrorDa
 
 // generateCulprit identifies the likely source of the error
 func (es *ErrorService) generateCulprit(normalizedData *dto.NormalizedErrorData) *string {
+	if normalizedData.Culprit != nil {
+		return normalizedData.Culprit
+	}
+
 	if len(normalizedData.StackTrace) == 0 {
 		return nil
 	}
 
-	// Find the first in-app frame
-	for _, frame := range normalizedData.StackTrace {
+	// Frames are ordered oldest call first, so find the innermost in-app frame
+	for i := len(normalizedData.StackTrace) - 1; i >= 0; i-- {
+		frame := normalizedData.StackTrace[i]
 		if frame.InApp != nil && *frame.InApp {
 			culprit := es.buildCulpritString(frame)
 			if culprit != "" {
@@ -301,8 +614,8 @@ func (es *ErrorService) generateCulprit(normalizedData *dto.NormalizedErrorData)
 		}
 	}
 
-	// If no in-app frame found, use the first frame
-	culprit := es.buildCulpritString(normalizedData.StackTrace[0])
+	// If no in-app frame found, use the innermost frame
+	culprit := es.buildCulpritString(normalizedData.StackTrace[len(normalizedData.StackTrace)-1])
 	if culprit != "" {
 		return &culprit
 	}
@@ -337,6 +650,15 @@ func (es *ErrorService) buildCulpritString(frame dto.StackFrame) string {
 
 // determineIssueType determines the type of issue based on the error data
 func (es *ErrorService) determineIssueType(normalizedData *dto.NormalizedErrorData) models.IssueType {
+	if normalizedData.Platform == cspPlatform {
+		return models.TypeCSP
+	}
+
+	// Fatal native events are process crashes, such as minidump uploads
+	if normalizedData.Platform == minidumpPlatform && normalizedData.Level == string(models.LevelFatal) {
+		return models.TypeNativeCrash
+	}
+
 	if normalizedData.ExceptionType != nil {
 		exceptionType := strings.ToLower(*normalizedData.ExceptionType)
 		if strings.Contains(exceptionType, "csp") {
@@ -350,12 +672,31 @@ func (es *ErrorService) determineIssueType(normalizedData *dto.NormalizedErrorDa
 // CreateErrorEvent creates a new error event
 func (es *ErrorService) CreateErrorEvent(issueID uuid.UUID, normalizedData *dto.NormalizedErrorData) (*models.Event, error) {
 	// Check if event already exists
-	var existingEvent models.Event
-	result := es.db.DB.Where("project_id = ? AND event_id = ?", normalizedData.ProjectID, normalizedData.EventID).First(&existingEvent)
-	if result.Error == nil {
+	exists, err := es.store.EventExists(normalizedData.ProjectID, normalizedData.EventID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		es.rememberEvent(normalizedData.ProjectID, normalizedData.EventID)
+		es.countOutcome(normalizedData.ProjectID, normalizedData.Fingerprint, IssueOutcomeDuplicate)
 		return nil, ErrEventExists
 	}
 
+	event, err := newErrorEvent(issueID, normalizedData)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := es.store.CreateEvent(event); err != nil {
+		return nil, err
+	}
+	es.rememberEvent(event.ProjectID, event.EventID)
+
+	return event, nil
+}
+
+// newErrorEvent builds the event row of a normalized error
+func newErrorEvent(issueID uuid.UUID, normalizedData *dto.NormalizedErrorData) (*models.Event, error) {
 	// Serialize complex data to JSON
 	stackTraceJSON, err := json.Marshal(normalizedData.StackTrace)
 	if err != nil {
@@ -382,29 +723,49 @@ func (es *ErrorService) CreateErrorEvent(issueID uuid.UUID, normalizedData *dto.
 		return nil, fmt.Errorf("failed to marshal extra data: %w", err)
 	}
 
-	// Create event
-	event := models.Event{
-		IssueID:         issueID,
-		ProjectID:       normalizedData.ProjectID,
-		EventID:         normalizedData.EventID,
-		Timestamp:       normalizedData.Timestamp,
-		Level:           models.IssueLevel(normalizedData.Level),
-		Message:         normalizedData.Message,
-		ExceptionType:   normalizedData.ExceptionType,
-		ExceptionValue:  normalizedData.ExceptionValue,
-		StackTrace:      datatypes.JSON(stackTraceJSON),
-		RequestData:     datatypes.JSON(requestDataJSON),
-		UserContext:     datatypes.JSON(userContextJSON),
-		Tags:            datatypes.JSON(tagsJSON),
-		ExtraData:       datatypes.JSON(extraDataJSON),
-		Fingerprint:     normalizedData.Fingerprint,
-		ReleaseVersion:  normalizedData.Release,
-		Environment:     normalizedData.Environment,
-		ServerName:      normalizedData.ServerName,
+	contextsJSON, err := marshalJSONField(normalizedData.Contexts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal contexts: %w", err)
+	}
+
+	metaJSON, err := marshalJSONField(normalizedData.Meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event meta: %w", err)
 	}
 
-	if err := es.db.DB.Create(&event).Error; err != nil {
-		return nil, fmt.Errorf("failed to create event: %w", err)
+	exceptionsJSON, err := marshalJSONField(normalizedData.Exceptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal exception chain: %w", err)
+	}
+
+	// Create event
+	event := models.Event{
+		IssueID:           issueID,
+		ProjectID:         normalizedData.ProjectID,
+		EventID:           normalizedData.EventID,
+		Timestamp:         normalizedData.Timestamp,
+		OriginalTimestamp: normalizedData.OriginalTimestamp,
+		Level:             models.IssueLevel(normalizedData.Level),
+		Message:           normalizedData.Message,
+		ExceptionType:     normalizedData.ExceptionType,
+		ExceptionValue:    normalizedData.ExceptionValue,
+		StackTrace:        datatypes.JSON(stackTraceJSON),
+		Exceptions:        exceptionsJSON,
+		RequestData:       datatypes.JSON(requestDataJSON),
+		UserContext:       datatypes.JSON(userContextJSON),
+		Tags:              datatypes.JSON(tagsJSON),
+		ExtraData:         datatypes.JSON(extraDataJSON),
+		Contexts:          contextsJSON,
+		Meta:              metaJSON,
+		Fingerprint:       normalizedData.Fingerprint,
+		GroupingVersion:   normalizedData.GroupingVersion,
+		CustomFingerprint: normalizedData.CustomFingerprint,
+		ReleaseVersion:    normalizedData.Release,
+		Environment:       normalizedData.Environment,
+		ServerName:        normalizedData.ServerName,
+		ReplayID:          normalizedData.ReplayID,
+		Transaction:       normalizedData.Transaction,
+		Platform:          normalizedData.Platform,
 	}
 
 	return &event, nil
@@ -412,30 +773,14 @@ func (es *ErrorService) CreateErrorEvent(issueID uuid.UUID, normalizedData *dto.
 
 // updateIssueStats updates issue statistics
 func (es *ErrorService) updateIssueStats(issue *models.Issue) error {
-	updates := map[string]interface{}{
-		"last_seen":   time.Now(),
-		"times_seen":  gorm.Expr("times_seen + ?", 1),
-		"updated_at":  time.Now(),
-	}
-
-	if err := es.db.DB.Model(issue).Updates(updates).Error; err != nil {
-		return fmt.Errorf("failed to update issue stats: %w", err)
-	}
-
-	return nil
+	return es.store.IncrementIssueStats(issue.ID, 1, time.Now())
 }
 
 // GetIssueStats retrieves issue statistics for a project
 func (es *ErrorService) GetIssueStats(projectID uuid.UUID, limit int, offset int) ([]dto.IssueListItemResponse, error) {
-	var issues []models.Issue
-
-	query := es.db.DB.Where("project_id = ?", projectID).
-		Order("last_seen DESC").
-		Limit(limit).
-		Offset(offset)
-
-	if err := query.Find(&issues).Error; err != nil {
-		return nil, fmt.Errorf("failed to get issues: %w", err)
+	issues, err := es.store.ListIssues(projectID, limit, offset)
+	if err != nil {
+		return nil, err
 	}
 
 	var stats []dto.IssueListItemResponse
@@ -452,6 +797,8 @@ func (es *ErrorService) GetIssueStats(projectID uuid.UUID, limit int, offset int
 			FirstSeen:   issue.FirstSeen,
 			LastSeen:    issue.LastSeen,
 			TimesSeen:   issue.TimesSeen,
+			Transaction: issue.Transaction,
+			IsRegression: issue.IsRegression,
 			CreatedAt:   issue.CreatedAt,
 			UpdatedAt:   issue.UpdatedAt,
 		})
@@ -460,18 +807,7 @@ func (es *ErrorService) GetIssueStats(projectID uuid.UUID, limit int, offset int
 	return stats, nil
 }
 
-// GetIssueEvents retrieves events for a specific issue
-func (es *ErrorService) GetIssueEvents(issueID uuid.UUID, limit int, offset int) ([]models.Event, error) {
-	var events []models.Event
-
-	query := es.db.DB.Where("issue_id = ?", issueID).
-		Order("timestamp DESC").
-		Limit(limit).
-		Offset(offset)
-
-	if err := query.Find(&events).Error; err != nil {
-		return nil, fmt.Errorf("failed to get events: %w", err)
-	}
-
-	return events, nil
+// GetIssueEvents retrieves events for a specific issue of a project
+func (es *ErrorService) GetIssueEvents(projectID, issueID uuid.UUID, limit int, offset int) ([]models.Event, error) {
+	return es.store.ListIssueEvents(projectID, issueID, limit, offset)
 }
\ No newline at end of file
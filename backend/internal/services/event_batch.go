@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// DefaultEventBatchFlushInterval bounds how long a queued event waits for its batch to fill
+const DefaultEventBatchFlushInterval = 500 * time.Millisecond
+
+// EventBatchConfig configures buffered event inserts for queued ingestion
+type EventBatchConfig struct {
+	// Size is the number of events written per multi-row INSERT
+	Size int
+	// FlushInterval is the longest an event stays buffered when the batch does not fill
+	FlushInterval time.Duration
+}
+
+// eventBatcher buffers events taken from the ingest queue and writes them with
+// multi-row INSERTs. A batch is flushed by the worker that fills it, so a busy
+// pipeline writes full batches, and by a ticker so a quiet one is not delayed
+// by more than the flush interval.
+type eventBatcher struct {
+	store  EventStore
+	config EventBatchConfig
+	dedup  EventDedupCache // remembers the events of written batches
+
+	mu      sync.Mutex
+	pending []models.Event
+	seen    map[string]bool // project/event IDs buffered, to drop duplicates before the INSERT
+	stats   map[uuid.UUID]*pendingIssueStats
+
+	// flushMu keeps batches in order so issue stats are never applied before their events
+	flushMu sync.Mutex
+}
+
+type pendingIssueStats struct {
+	count  int
+	seenAt time.Time
+}
+
+// StartEventBatching makes queued events be inserted in batches until the context is
+// cancelled, when the buffer is flushed a last time. It must be called before the
+// ingest queue is started; a batch size below 2 leaves queued events unbatched.
+func (es *ErrorService) StartEventBatching(ctx context.Context, config EventBatchConfig) {
+	if config.Size < 2 {
+		return
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DefaultEventBatchFlushInterval
+	}
+
+	batcher := &eventBatcher{
+		store:  es.store,
+		config: config,
+		dedup:  es.dedup,
+		seen:   make(map[string]bool),
+		stats:  make(map[uuid.UUID]*pendingIssueStats),
+	}
+	es.batcher = batcher
+
+	go func() {
+		ticker := time.NewTicker(config.FlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				batcher.flush()
+				return
+			case <-ticker.C:
+				batcher.flush()
+			}
+		}
+	}()
+}
+
+// FlushEvents writes the events still buffered for batching, such as before the process exits
+func (es *ErrorService) FlushEvents() {
+	if es.batcher != nil {
+		es.batcher.flush()
+	}
+}
+
+// processBatchedErrorEvent groups a queued event into its issue like ProcessErrorEvent,
+// but buffers the event and its issue stats for the next batch instead of writing them
+func (es *ErrorService) processBatchedErrorEvent(projectID uuid.UUID, eventData *dto.ErrorEventRequest, clientIP, userAgent string) error {
+	if err := es.ValidateErrorPayload(eventData); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	normalizedData, err := es.NormalizeErrorData(projectID, eventData, clientIP, userAgent)
+	if err != nil {
+		return fmt.Errorf("normalization failed: %w", err)
+	}
+	if err := es.runProcessors(normalizedData); err != nil {
+		return err
+	}
+	normalizedData.Fingerprint = es.generateFingerprint(normalizedData, eventData.Fingerprint)
+
+	if es.isFiltered(projectID, normalizedData, userAgent, clientIP) {
+		return ErrEventFiltered
+	}
+
+	if es.isCachedDuplicate(projectID, normalizedData.EventID) {
+		es.countOutcome(projectID, normalizedData.Fingerprint, IssueOutcomeDuplicate)
+		return ErrEventExists
+	}
+
+	issue, err := es.FindOrCreateIssue(projectID, normalizedData)
+	if err != nil {
+		return fmt.Errorf("issue management failed: %w", err)
+	}
+	es.releases.record(projectID, normalizedData.Release)
+	es.environments.record(projectID, normalizedData.Environment)
+
+	if es.isSampledOut(projectID, issue.ID) {
+		es.rememberEvent(projectID, normalizedData.EventID)
+		es.countVolume(projectID, normalizedData)
+		es.countOutcome(projectID, normalizedData.Fingerprint, IssueOutcomeSampled)
+		es.batcher.count(issue.ID)
+		return nil
+	}
+
+	exists, err := es.store.EventExists(projectID, normalizedData.EventID)
+	if err != nil {
+		return fmt.Errorf("event creation failed: %w", err)
+	}
+	if exists {
+		es.rememberEvent(projectID, normalizedData.EventID)
+		es.countOutcome(projectID, normalizedData.Fingerprint, IssueOutcomeDuplicate)
+		return ErrEventExists
+	}
+
+	event, err := newErrorEvent(issue.ID, normalizedData)
+	if err != nil {
+		return fmt.Errorf("event creation failed: %w", err)
+	}
+
+	if err := es.batcher.add(event); err != nil {
+		if errors.Is(err, ErrEventExists) {
+			es.countOutcome(projectID, normalizedData.Fingerprint, IssueOutcomeDuplicate)
+		}
+		return err
+	}
+	es.countVolume(projectID, normalizedData)
+	return nil
+}
+
+// add buffers an event, flushing the batch when it is full
+func (b *eventBatcher) add(event *models.Event) error {
+	key := event.ProjectID.String() + "/" + event.EventID
+
+	b.mu.Lock()
+	if b.seen[key] {
+		b.mu.Unlock()
+		return ErrEventExists
+	}
+	b.seen[key] = true
+	b.pending = append(b.pending, *event)
+	b.countLocked(event.IssueID)
+
+	full := len(b.pending) >= b.config.Size
+	b.mu.Unlock()
+
+	if full {
+		b.flush()
+	}
+	return nil
+}
+
+// count buffers the stats of an event that is not stored, such as a sampled out one
+func (b *eventBatcher) count(issueID uuid.UUID) {
+	b.mu.Lock()
+	b.countLocked(issueID)
+	b.mu.Unlock()
+}
+
+func (b *eventBatcher) countLocked(issueID uuid.UUID) {
+	stats, ok := b.stats[issueID]
+	if !ok {
+		stats = &pendingIssueStats{}
+		b.stats[issueID] = stats
+	}
+	stats.count++
+	stats.seenAt = time.Now()
+}
+
+// flush writes the buffered events and then their issues' stats. Events that fail
+// to insert as a batch are retried one by one so a single bad row cannot drop the batch.
+func (b *eventBatcher) flush() {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	events, stats := b.pending, b.stats
+	b.pending = nil
+	b.seen = make(map[string]bool)
+	b.stats = make(map[uuid.UUID]*pendingIssueStats)
+	b.mu.Unlock()
+
+	// Batches of sampled out events only have stats to apply
+	if len(events) > 0 {
+		if err := b.store.CreateEvents(events, b.config.Size); err != nil {
+			log.Printf("Event batch of %d events failed, inserting them one by one: %v", len(events), err)
+			for i := range events {
+				event := events[i]
+				if err := b.store.CreateEvent(&event); err != nil {
+					log.Printf("Dropping queued event %s for project %s: %v", event.EventID, event.ProjectID, err)
+					stats[event.IssueID].count--
+					continue
+				}
+				b.remember(&event)
+			}
+		} else {
+			for i := range events {
+				b.remember(&events[i])
+			}
+		}
+	}
+
+	for issueID, issueStats := range stats {
+		if issueStats.count == 0 {
+			continue
+		}
+		if err := b.store.IncrementIssueStats(issueID, issueStats.count, issueStats.seenAt); err != nil {
+			log.Printf("Failed to update stats of issue %s after an event batch: %v", issueID, err)
+		}
+	}
+}
+
+// remember records a written event in the dedup cache, if any
+func (b *eventBatcher) remember(event *models.Event) {
+	if b.dedup != nil {
+		b.dedup.Add(event.ProjectID, event.EventID)
+	}
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"container/list"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"minisentry/internal/queue"
+
+	"github.com/google/uuid"
+)
+
+// DefaultEventDedupTTL is how long the Redis cache remembers a stored event
+const DefaultEventDedupTTL = time.Hour
+
+// EventDedupCache remembers recently stored events so SDK retries of an event are rejected
+// without a database lookup. It is only a fast path: events missing from the cache are
+// still checked against the database.
+type EventDedupCache interface {
+	Contains(projectID uuid.UUID, eventID string) bool
+	Add(projectID uuid.UUID, eventID string)
+}
+
+func eventDedupKey(projectID uuid.UUID, eventID string) string {
+	return projectID.String() + "/" + eventID
+}
+
+// MemoryEventDedupCache is an LRU cache of the events stored by this process
+type MemoryEventDedupCache struct {
+	size int
+
+	mu    sync.Mutex
+	order *list.List // most recently used first
+	items map[string]*list.Element
+}
+
+// NewMemoryEventDedupCache creates an in-memory cache of up to size events
+func NewMemoryEventDedupCache(size int) *MemoryEventDedupCache {
+	if size < 1 {
+		size = 1
+	}
+	return &MemoryEventDedupCache{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element, size),
+	}
+}
+
+// Contains reports whether the event was stored recently
+func (c *MemoryEventDedupCache) Contains(projectID uuid.UUID, eventID string) bool {
+	key := eventDedupKey(projectID, eventID)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if ok {
+		c.order.MoveToFront(element)
+	}
+	return ok
+}
+
+// Add records a stored event, evicting the least recently used one when the cache is full
+func (c *MemoryEventDedupCache) Add(projectID uuid.UUID, eventID string) {
+	key := eventDedupKey(projectID, eventID)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.order.MoveToFront(element)
+		return
+	}
+	c.items[key] = c.order.PushFront(key)
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(string))
+	}
+}
+
+// RedisEventDedupCache shares recently stored events between API servers and ingestion
+// workers. Keys expire after the TTL; Redis errors are treated as cache misses so
+// ingestion falls back to the database.
+type RedisEventDedupCache struct {
+	client *queue.RedisClient
+	prefix string
+	ttl    time.Duration
+}
+
+// NewRedisEventDedupCache creates a cache storing keys under prefix for ttl
+func NewRedisEventDedupCache(client *queue.RedisClient, prefix string, ttl time.Duration) *RedisEventDedupCache {
+	if ttl < time.Second {
+		ttl = DefaultEventDedupTTL
+	}
+	return &RedisEventDedupCache{client: client, prefix: prefix, ttl: ttl}
+}
+
+// Contains reports whether the event was stored within the TTL
+func (c *RedisEventDedupCache) Contains(projectID uuid.UUID, eventID string) bool {
+	reply, err := c.client.Do("EXISTS", c.prefix+eventDedupKey(projectID, eventID))
+	if err != nil {
+		return false
+	}
+	count, _ := reply.(int64)
+	return count > 0
+}
+
+// Add records a stored event for the TTL
+func (c *RedisEventDedupCache) Add(projectID uuid.UUID, eventID string) {
+	seconds := strconv.Itoa(int(c.ttl / time.Second))
+	c.client.Do("SET", c.prefix+eventDedupKey(projectID, eventID), "1", "EX", seconds)
+}
+
+// SetEventDedupCache makes ingestion reject events found in the cache as duplicates before
+// any database lookup. It must be called before the server starts ingesting.
+func (es *ErrorService) SetEventDedupCache(cache EventDedupCache) {
+	es.dedup = cache
+	if es.batcher != nil {
+		es.batcher.dedup = cache
+	}
+}
+
+// isCachedDuplicate reports whether the dedup cache knows the event was already stored
+func (es *ErrorService) isCachedDuplicate(projectID uuid.UUID, eventID string) bool {
+	return es.dedup != nil && eventID != "" && es.dedup.Contains(projectID, eventID)
+}
+
+// rememberEvent records a stored event in the dedup cache
+func (es *ErrorService) rememberEvent(projectID uuid.UUID, eventID string) {
+	if es.dedup != nil {
+		es.dedup.Add(projectID, eventID)
+	}
+}
+
+// OpenEventDedupCache creates the event dedup cache for the given backend: memory (an LRU of
+// size events) or redis (keys under keyPrefix expiring after ttl). "none" disables it.
+func OpenEventDedupCache(backend string, size int, redisURL, keyPrefix string, ttl time.Duration) (EventDedupCache, error) {
+	switch backend {
+	case "none":
+		return nil, nil
+	case "memory":
+		return NewMemoryEventDedupCache(size), nil
+	case "redis":
+		client, err := queue.NewRedisClient(redisURL)
+		if err != nil {
+			return nil, err
+		}
+		if err := client.Ping(); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+		}
+		return NewRedisEventDedupCache(client, keyPrefix, ttl), nil
+	default:
+		return nil, fmt.Errorf("unsupported event dedup cache backend %q", backend)
+	}
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"minisentry/internal/dto"
+)
+
+// truncationMarker ends values shortened to fit the event limits
+const truncationMarker = "…"
+
+// EventLimits bounds the size of ingested error events; a limit of 0 is unlimited.
+// Events over MaxEventSize are rejected, the other limits truncate the event instead.
+type EventLimits struct {
+	MaxEventSize     int // bytes of an event payload, after decompression
+	MaxMessageLength int // characters of the message and exception value
+	MaxBreadcrumbs   int // most recent breadcrumbs kept
+	MaxExtraSize     int // bytes of encoded extra data; keys that do not fit are dropped
+	MaxContextsSize  int // bytes of encoded contexts; contexts that do not fit are dropped
+}
+
+// SetEventLimits sets the limits applied to ingested error events. It must be called
+// before the server starts.
+func (es *ErrorService) SetEventLimits(limits EventLimits) {
+	es.limits = limits
+}
+
+// MaxEventSize returns the largest event payload accepted for a project: the smaller of
+// the server limit and the project's own limit, where 0 is unlimited
+func (es *ErrorService) MaxEventSize(projectLimit int) int {
+	limit := es.limits.MaxEventSize
+	if projectLimit > 0 && (limit == 0 || projectLimit < limit) {
+		limit = projectLimit
+	}
+	return limit
+}
+
+// applyEventLimits truncates the fields of a normalized event that exceed the limits,
+// describing what was trimmed in the event's meta
+func (es *ErrorService) applyEventLimits(normalized *dto.NormalizedErrorData) {
+	if limit := es.limits.MaxMessageLength; limit > 0 {
+		normalized.Message = truncateField(normalized, []string{"message"}, normalized.Message, limit)
+		normalized.ExceptionValue = truncateField(normalized, []string{"exception_value"}, normalized.ExceptionValue, limit)
+		if len(normalized.Exceptions) > 0 {
+			exceptions := make([]dto.ExceptionValue, len(normalized.Exceptions))
+			for i, exception := range normalized.Exceptions {
+				exception.Value = truncateField(normalized, []string{"exceptions", strconv.Itoa(i), "value"}, exception.Value, limit)
+				exceptions[i] = exception
+			}
+			normalized.Exceptions = exceptions
+		}
+	}
+
+	if limit := es.limits.MaxBreadcrumbs; limit > 0 && len(normalized.Breadcrumbs) > limit {
+		annotateEventMeta(normalized, []string{"breadcrumbs"}, map[string]interface{}{"len": len(normalized.Breadcrumbs)})
+		normalized.Breadcrumbs = normalized.Breadcrumbs[len(normalized.Breadcrumbs)-limit:]
+	}
+
+	if limit := es.limits.MaxExtraSize; limit > 0 && len(normalized.ExtraData) > 0 {
+		normalized.ExtraData = truncateMapField(normalized, "extra_data", normalized.ExtraData, limit)
+	}
+
+	if limit := es.limits.MaxContextsSize; limit > 0 && len(normalized.Contexts) > 0 {
+		normalized.Contexts = truncateMapField(normalized, "contexts", normalized.Contexts, limit)
+	}
+}
+
+// truncateField truncates the string field at path to limit characters, annotating the
+// event meta with its original length when it did not fit
+func truncateField(normalized *dto.NormalizedErrorData, path []string, value *string, limit int) *string {
+	truncated := truncateStringPtr(value, limit)
+	if truncated != value {
+		annotateEventMeta(normalized, path, map[string]interface{}{
+			"len": utf8.RuneCountInString(*value),
+			"rem": [][]interface{}{{"!limit", "s"}},
+		})
+	}
+	return truncated
+}
+
+// truncateMapField keeps the keys of an object field that fit in limit bytes, annotating
+// the event meta with the keys removed
+func truncateMapField(normalized *dto.NormalizedErrorData, field string, data map[string]interface{}, limit int) map[string]interface{} {
+	kept := truncateExtra(data, limit)
+	if len(kept) == len(data) {
+		return kept
+	}
+
+	annotateEventMeta(normalized, []string{field}, map[string]interface{}{"len": len(data)})
+	for key := range data {
+		if _, ok := kept[key]; !ok {
+			annotateEventMeta(normalized, []string{field, key}, map[string]interface{}{
+				"rem": [][]interface{}{{"!limit", "x"}},
+			})
+		}
+	}
+	return kept
+}
+
+// annotateEventMeta records an annotation of the value at path in the event meta, under the
+// "" key of the path's node as in Sentry's _meta. The "!limit" remarks tell the value was
+// substituted ("s") by a truncated one or removed ("x"); "len" is its original length.
+func annotateEventMeta(normalized *dto.NormalizedErrorData, path []string, annotation map[string]interface{}) {
+	if normalized.Meta == nil {
+		normalized.Meta = make(map[string]interface{})
+	}
+	node := normalized.Meta
+	for _, key := range path {
+		child, ok := node[key].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			node[key] = child
+		}
+		node = child
+	}
+	node[""] = annotation
+}
+
+// truncateStringPtr shortens a string to limit characters, marker included
+func truncateStringPtr(value *string, limit int) *string {
+	if value == nil || utf8.RuneCountInString(*value) <= limit {
+		return value
+	}
+	runes := []rune(*value)
+	keep := limit - utf8.RuneCountInString(truncationMarker)
+	if keep < 0 {
+		keep = 0
+	}
+	truncated := string(runes[:keep]) + truncationMarker
+	return &truncated
+}
+
+// truncateExtra keeps the keys of extra data or contexts, in sorted order, whose encoding
+// fits in limit bytes
+func truncateExtra(extra map[string]interface{}, limit int) map[string]interface{} {
+	keys := make([]string, 0, len(extra))
+	for key := range extra {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	kept := make(map[string]interface{}, len(extra))
+	size := 2 // braces
+	for _, key := range keys {
+		encoded, err := json.Marshal(map[string]interface{}{key: extra[key]})
+		if err != nil {
+			continue
+		}
+		// The entry without its braces, plus a separating comma
+		entrySize := len(encoded) - 2 + 1
+		if size+entrySize > limit {
+			continue
+		}
+		size += entrySize
+		kept[key] = extra[key]
+	}
+	return kept
+}
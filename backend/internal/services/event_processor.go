@@ -0,0 +1,52 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"minisentry/internal/dto"
+)
+
+// ErrEventDropped is returned by event processors to drop an event
+var ErrEventDropped = errors.New("event dropped by event processor")
+
+// EventProcessor is a custom ingestion step compiled into the server, such as enriching
+// events with tags or dropping unwanted ones. It may change the event in place and returns
+// ErrEventDropped, possibly wrapped, to drop it.
+type EventProcessor func(event *dto.NormalizedErrorData) error
+
+// RegisterProcessor adds a custom event processor. Processors run in registration order on
+// every error event once it is normalized and scrubbed, before it is grouped and filtered.
+// A processor failing with any other error than ErrEventDropped is logged and skipped, so
+// a faulty processor does not lose events. Processors must be registered before the server
+// starts, in cmd/worker as well when events are processed by workers.
+func (es *ErrorService) RegisterProcessor(processor EventProcessor) {
+	es.processors = append(es.processors, processor)
+}
+
+// runProcessors runs the registered event processors, returning ErrEventDropped when one
+// of them drops the event
+func (es *ErrorService) runProcessors(event *dto.NormalizedErrorData) error {
+	for i, processor := range es.processors {
+		err := callProcessor(processor, event)
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrEventDropped):
+			return ErrEventDropped
+		default:
+			log.Printf("Event processor %d failed on event %s, skipping it: %v", i, event.EventID, err)
+		}
+	}
+	return nil
+}
+
+// callProcessor runs an event processor, turning its panics into errors
+func callProcessor(processor EventProcessor, event *dto.NormalizedErrorData) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return processor(event)
+}
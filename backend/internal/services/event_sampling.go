@@ -0,0 +1,137 @@
+package services
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultEventSampleRate is the share of events stored beyond a project's threshold when
+	// it sets no rate: one event in this many
+	DefaultEventSampleRate = 10
+
+	// eventSamplingCacheTTL bounds how long ingestion uses a project's sampling settings
+	// before reloading them
+	eventSamplingCacheTTL = 30 * time.Second
+)
+
+type eventSamplingSettings struct {
+	threshold int // events of an issue stored per hour before sampling; 0 stores all
+	rate      int
+	expires   time.Time
+}
+
+// issueHourCount counts the events an issue received in the current hour
+type issueHourCount struct {
+	hour  time.Time
+	count int
+}
+
+// EventSampler keeps hot issues from flooding the events table: once an issue received a
+// project's threshold of events in an hour, only every Nth further event is stored. Issue
+// counters still count every event. Counts are kept per process, so each server and worker
+// applies the threshold on its own.
+type EventSampler struct {
+	db *database.DB
+
+	mu       sync.Mutex
+	settings map[uuid.UUID]*eventSamplingSettings
+	counts   map[uuid.UUID]*issueHourCount
+}
+
+// NewEventSampler creates a new event sampler
+func NewEventSampler(db *database.DB) *EventSampler {
+	return &EventSampler{
+		db:       db,
+		settings: make(map[uuid.UUID]*eventSamplingSettings),
+		counts:   make(map[uuid.UUID]*issueHourCount),
+	}
+}
+
+// SetEventSampler makes ingestion sample the events of issues over their project's
+// hourly threshold. It must be called before the server starts.
+func (es *ErrorService) SetEventSampler(sampler *EventSampler) {
+	es.sampler = sampler
+}
+
+// isSampledOut reports whether an event of the issue is counted without being stored
+func (es *ErrorService) isSampledOut(projectID, issueID uuid.UUID) bool {
+	return es.sampler != nil && !es.sampler.Keep(projectID, issueID)
+}
+
+// Keep counts an event of an issue and reports whether to store it
+func (s *EventSampler) Keep(projectID, issueID uuid.UUID) bool {
+	settings := s.getSettings(projectID)
+	if settings.threshold <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	hour := now.UTC().Truncate(time.Hour)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count, ok := s.counts[issueID]
+	if !ok || !count.hour.Equal(hour) {
+		if len(s.counts) >= projectAccessCacheSweepSize {
+			for id, stale := range s.counts {
+				if !stale.hour.Equal(hour) {
+					delete(s.counts, id)
+				}
+			}
+		}
+		count = &issueHourCount{hour: hour}
+		s.counts[issueID] = count
+	}
+	count.count++
+
+	if count.count <= settings.threshold {
+		return true
+	}
+	return (count.count-settings.threshold)%settings.rate == 0
+}
+
+// getSettings returns a project's sampling settings from the cache, loading them when
+// missing or expired. Projects whose settings cannot be loaded are not sampled.
+func (s *EventSampler) getSettings(projectID uuid.UUID) *eventSamplingSettings {
+	now := time.Now()
+
+	s.mu.Lock()
+	settings, ok := s.settings[projectID]
+	s.mu.Unlock()
+	if ok && now.Before(settings.expires) {
+		return settings
+	}
+
+	settings = &eventSamplingSettings{rate: DefaultEventSampleRate, expires: now.Add(eventSamplingCacheTTL)}
+	var project models.Project
+	if err := s.db.Select("id", "event_sample_threshold", "event_sample_rate").Where("id = ?", projectID).First(&project).Error; err != nil {
+		log.Printf("Failed to load event sampling settings of project %s: %v", projectID, err)
+	} else {
+		if project.EventSampleThreshold != nil {
+			settings.threshold = *project.EventSampleThreshold
+		}
+		if project.EventSampleRate != nil && *project.EventSampleRate > 0 {
+			settings.rate = *project.EventSampleRate
+		}
+	}
+
+	s.mu.Lock()
+	if len(s.settings) >= projectAccessCacheSweepSize {
+		for id, cached := range s.settings {
+			if now.After(cached.expires) {
+				delete(s.settings, id)
+			}
+		}
+	}
+	s.settings[projectID] = settings
+	s.mu.Unlock()
+	return settings
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"container/list"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+	"minisentry/internal/queue"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultEventStatusTTL is how long the Redis tracker remembers the status of an event
+const DefaultEventStatusTTL = 24 * time.Hour
+
+// Statuses of events accepted by asynchronous ingestion
+const (
+	EventStatusQueued      = "queued"       // accepted and waiting for a worker
+	EventStatusStored      = "stored"       // stored in its issue
+	EventStatusSampled     = "sampled"      // counted in its issue but not stored
+	EventStatusDuplicate   = "duplicate"    // discarded as a retry of a stored event
+	EventStatusFiltered    = "filtered"     // discarded by an inbound filter
+	EventStatusDropped     = "dropped"      // dropped by an event processor
+	EventStatusInvalid     = "invalid"      // rejected by a worker, for instance for a deleted project
+	EventStatusRateLimited = "rate_limited" // rejected by spike protection, a rate limit or a quota
+)
+
+var ErrEventStatusUnknown = errors.New("event status unknown")
+
+// EventStatus is what became of an event sent to a project
+type EventStatus struct {
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// EventStatusTracker remembers the status of recently accepted events, so clients of
+// asynchronous ingestion can tell whether an event answered with 202 was stored
+type EventStatusTracker interface {
+	Set(projectID uuid.UUID, eventID string, status EventStatus)
+	Get(projectID uuid.UUID, eventID string) (EventStatus, bool)
+}
+
+// MemoryEventStatusTracker keeps the status of the latest events seen by this process
+type MemoryEventStatusTracker struct {
+	size int
+
+	mu    sync.Mutex
+	order *list.List // most recently set first
+	items map[string]*list.Element
+}
+
+type memoryEventStatus struct {
+	key    string
+	status EventStatus
+}
+
+// NewMemoryEventStatusTracker creates an in-memory tracker of up to size events
+func NewMemoryEventStatusTracker(size int) *MemoryEventStatusTracker {
+	if size < 1 {
+		size = 1
+	}
+	return &MemoryEventStatusTracker{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element, size),
+	}
+}
+
+// Set records the status of an event, forgetting the oldest event when the tracker is full
+func (t *MemoryEventStatusTracker) Set(projectID uuid.UUID, eventID string, status EventStatus) {
+	key := eventDedupKey(projectID, eventID)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if element, ok := t.items[key]; ok {
+		element.Value.(*memoryEventStatus).status = status
+		t.order.MoveToFront(element)
+		return
+	}
+	t.items[key] = t.order.PushFront(&memoryEventStatus{key: key, status: status})
+
+	for t.order.Len() > t.size {
+		oldest := t.order.Back()
+		t.order.Remove(oldest)
+		delete(t.items, oldest.Value.(*memoryEventStatus).key)
+	}
+}
+
+// Get returns the status of an event, if it is still remembered
+func (t *MemoryEventStatusTracker) Get(projectID uuid.UUID, eventID string) (EventStatus, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	element, ok := t.items[eventDedupKey(projectID, eventID)]
+	if !ok {
+		return EventStatus{}, false
+	}
+	return element.Value.(*memoryEventStatus).status, true
+}
+
+// RedisEventStatusTracker shares event statuses between API servers and ingestion workers.
+// Keys expire after the TTL; Redis errors lose the status, never the event.
+type RedisEventStatusTracker struct {
+	client *queue.RedisClient
+	prefix string
+	ttl    time.Duration
+}
+
+// NewRedisEventStatusTracker creates a tracker storing keys under prefix for ttl
+func NewRedisEventStatusTracker(client *queue.RedisClient, prefix string, ttl time.Duration) *RedisEventStatusTracker {
+	if ttl < time.Second {
+		ttl = DefaultEventStatusTTL
+	}
+	return &RedisEventStatusTracker{client: client, prefix: prefix, ttl: ttl}
+}
+
+// Set records the status of an event for the TTL
+func (t *RedisEventStatusTracker) Set(projectID uuid.UUID, eventID string, status EventStatus) {
+	value, err := json.Marshal(status)
+	if err != nil {
+		return
+	}
+	seconds := strconv.Itoa(int(t.ttl / time.Second))
+	t.client.Do("SET", t.prefix+eventDedupKey(projectID, eventID), string(value), "EX", seconds)
+}
+
+// Get returns the status of an event set within the TTL
+func (t *RedisEventStatusTracker) Get(projectID uuid.UUID, eventID string) (EventStatus, bool) {
+	reply, err := t.client.Do("GET", t.prefix+eventDedupKey(projectID, eventID))
+	if err != nil {
+		return EventStatus{}, false
+	}
+	value, ok := reply.(string)
+	if !ok {
+		return EventStatus{}, false
+	}
+	var status EventStatus
+	if err := json.Unmarshal([]byte(value), &status); err != nil {
+		return EventStatus{}, false
+	}
+	return status, true
+}
+
+// OpenEventStatusTracker creates the event status tracker for the given backend: memory (the
+// latest size events) or redis (keys under keyPrefix expiring after ttl). "none" disables it.
+func OpenEventStatusTracker(backend string, size int, redisURL, keyPrefix string, ttl time.Duration) (EventStatusTracker, error) {
+	switch backend {
+	case "none":
+		return nil, nil
+	case "memory":
+		return NewMemoryEventStatusTracker(size), nil
+	case "redis":
+		client, err := queue.NewRedisClient(redisURL)
+		if err != nil {
+			return nil, err
+		}
+		if err := client.Ping(); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+		}
+		return NewRedisEventStatusTracker(client, keyPrefix, ttl), nil
+	default:
+		return nil, fmt.Errorf("unsupported event status tracker backend %q", backend)
+	}
+}
+
+// SetEventStatusTracker makes ingestion record what becomes of the events it accepts
+// asynchronously. It must be called before the server starts ingesting.
+func (es *ErrorService) SetEventStatusTracker(tracker EventStatusTracker) {
+	es.statuses = tracker
+}
+
+// TracksEventStatus reports whether the status of events is recorded
+func (es *ErrorService) TracksEventStatus() bool {
+	return es.statuses != nil
+}
+
+// RecordEventStatus records the status of an event, when event statuses are tracked
+func (es *ErrorService) RecordEventStatus(projectID uuid.UUID, eventID, status, reason string) {
+	if es.statuses == nil || eventID == "" {
+		return
+	}
+	es.statuses.Set(projectID, eventID, EventStatus{
+		Status:    status,
+		Reason:    reason,
+		UpdatedAt: time.Now().UTC(),
+	})
+}
+
+// GetEventStatus returns what became of an event sent to a project: its tracked status or,
+// once that is forgotten or for events ingested synchronously, whether it is stored
+func (es *ErrorService) GetEventStatus(projectID uuid.UUID, eventID string) (*dto.EventStatusResponse, error) {
+	response := &dto.EventStatusResponse{EventID: eventID}
+	if es.statuses != nil {
+		if status, ok := es.statuses.Get(projectID, eventID); ok {
+			response.Status = status.Status
+			response.Reason = status.Reason
+			response.UpdatedAt = &status.UpdatedAt
+			if status.Status != EventStatusStored {
+				return response, nil
+			}
+		}
+	}
+
+	var event models.Event
+	err := es.db.Select("id", "issue_id", "created_at").
+		Where("project_id = ? AND event_id = ?", projectID, eventID).
+		First(&event).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if response.Status != "" {
+			// Stored events may still be buffered for a batched insert
+			return response, nil
+		}
+		return nil, ErrEventStatusUnknown
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up event: %w", err)
+	}
+
+	response.Status = EventStatusStored
+	response.IssueID = &event.IssueID
+	if response.UpdatedAt == nil {
+		response.UpdatedAt = &event.CreatedAt
+	}
+	return response, nil
+}
+
+// RecordEventOutcome records the status of an event from the outcome of processing it;
+// failures that are retried leave its status as it was
+func (es *ErrorService) RecordEventOutcome(projectID uuid.UUID, eventID string, sampled bool, err error) {
+	status := EventOutcomeStatus(sampled, err)
+	if status == "" {
+		return
+	}
+	reason := ""
+	if err != nil {
+		reason = err.Error()
+	}
+	es.RecordEventStatus(projectID, eventID, status, reason)
+}
+
+// EventOutcomeStatus returns the status of an event from the outcome of processing it, ""
+// for failures that are retried
+func EventOutcomeStatus(sampled bool, err error) string {
+	switch {
+	case err == nil && sampled:
+		return EventStatusSampled
+	case err == nil:
+		return EventStatusStored
+	case errors.Is(err, ErrEventExists):
+		return EventStatusDuplicate
+	case errors.Is(err, ErrEventFiltered):
+		return EventStatusFiltered
+	case errors.Is(err, ErrEventDropped):
+		return EventStatusDropped
+	case errors.Is(err, ErrInvalidEventData),
+		strings.Contains(err.Error(), "project not found"),
+		strings.Contains(err.Error(), "project is inactive"):
+		return EventStatusInvalid
+	}
+	return ""
+}
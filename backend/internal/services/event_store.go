@@ -0,0 +1,255 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrIssueNotFound = errors.New("issue not found")
+)
+
+// EventStore abstracts persistence of issues and events for the ingestion pipeline
+type EventStore interface {
+	// FindIssueByFingerprint returns ErrIssueNotFound when no issue matches. A fingerprint
+	// merged into another issue matches that issue.
+	FindIssueByFingerprint(projectID uuid.UUID, fingerprint string) (*models.Issue, error)
+	CreateIssue(issue *models.Issue) error
+	// CreateIssueActivity logs an entry in an issue's activity made by ingestion
+	CreateIssueActivity(activity *models.IssueActivity) error
+	// AddIssueParticipant makes a user a participant of an issue, unless they already are or
+	// unsubscribed from it
+	AddIssueParticipant(issueID, userID uuid.UUID, reason models.IssueSubscriptionReason) error
+	EventExists(projectID uuid.UUID, eventID string) (bool, error)
+	// CreateEvent stores an event and indexes its tags
+	CreateEvent(event *models.Event) error
+	// CreateEvents inserts events with multi-row INSERTs of up to batchSize rows and
+	// indexes their tags
+	CreateEvents(events []models.Event, batchSize int) error
+	IncrementIssueStats(issueID uuid.UUID, count int, seenAt time.Time) error
+	// RegressIssue reopens a resolved issue as a regression and logs it in the issue's
+	// activity. It returns false when the issue was no longer resolved.
+	RegressIssue(issue *models.Issue, activityData []byte) (bool, error)
+	// UnignoreIssue reopens an ignored issue whose ignore condition was reached and logs it
+	// in the issue's activity. It returns false when the issue was no longer ignored.
+	UnignoreIssue(issue *models.Issue, activityData []byte) (bool, error)
+	// GetIgnoreCondition returns the stored ignore condition of an ignored issue; nil when
+	// the issue is no longer ignored or has no condition
+	GetIgnoreCondition(issueID uuid.UUID) ([]byte, error)
+	// UpdateIgnoreCondition stores the progress of an ignored issue's ignore condition if it
+	// still has the previous condition. It returns false when the condition changed meanwhile.
+	UpdateIgnoreCondition(issueID uuid.UUID, previous, condition []byte) (bool, error)
+	ListIssues(projectID uuid.UUID, limit, offset int) ([]models.Issue, error)
+	ListIssueEvents(projectID, issueID uuid.UUID, limit, offset int) ([]models.Event, error)
+}
+
+// GormEventStore is the EventStore backed by GORM; it serves both Postgres and SQLite
+type GormEventStore struct {
+	db *gorm.DB
+}
+
+// NewGormEventStore creates a GORM-backed event store
+func NewGormEventStore(db *gorm.DB) *GormEventStore {
+	return &GormEventStore{db: db}
+}
+
+func (s *GormEventStore) FindIssueByFingerprint(projectID uuid.UUID, fingerprint string) (*models.Issue, error) {
+	var issue models.Issue
+	err := s.db.Where("project_id = ? AND fingerprint = ?", projectID, fingerprint).First(&issue).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = s.db.Where("id = (?)", s.db.Model(&models.IssueMergedFingerprint{}).Select("issue_id").
+			Where("project_id = ? AND fingerprint = ?", projectID, fingerprint)).First(&issue).Error
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrIssueNotFound
+		}
+		return nil, fmt.Errorf("failed to query issue: %w", err)
+	}
+	return &issue, nil
+}
+
+func (s *GormEventStore) CreateIssue(issue *models.Issue) error {
+	if err := s.db.Create(issue).Error; err != nil {
+		return fmt.Errorf("failed to create issue: %w", err)
+	}
+	return nil
+}
+
+func (s *GormEventStore) CreateIssueActivity(activity *models.IssueActivity) error {
+	activity.ID = uuid.New()
+	if err := s.db.Create(activity).Error; err != nil {
+		return fmt.Errorf("failed to create issue activity: %w", err)
+	}
+	return nil
+}
+
+func (s *GormEventStore) AddIssueParticipant(issueID, userID uuid.UUID, reason models.IssueSubscriptionReason) error {
+	return addIssueParticipant(s.db, issueID, userID, reason)
+}
+
+func (s *GormEventStore) EventExists(projectID uuid.UUID, eventID string) (bool, error) {
+	var count int64
+	if err := s.db.Model(&models.Event{}).Where("project_id = ? AND event_id = ?", projectID, eventID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check event: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (s *GormEventStore) CreateEvent(event *models.Event) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(event).Error; err != nil {
+			return fmt.Errorf("failed to create event: %w", err)
+		}
+		return indexEventTags(tx, []models.Event{*event})
+	})
+}
+
+func (s *GormEventStore) CreateEvents(events []models.Event, batchSize int) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(events, batchSize).Error; err != nil {
+			return fmt.Errorf("failed to create events: %w", err)
+		}
+		return indexEventTags(tx, events)
+	})
+}
+
+func (s *GormEventStore) IncrementIssueStats(issueID uuid.UUID, count int, seenAt time.Time) error {
+	updates := map[string]interface{}{
+		"last_seen":  seenAt,
+		"times_seen": gorm.Expr("times_seen + ?", count),
+		"priority":   risenIssuePriority(count),
+		"updated_at": time.Now(),
+	}
+
+	if err := s.db.Model(&models.Issue{}).Where("id = ?", issueID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update issue stats: %w", err)
+	}
+	return nil
+}
+
+func (s *GormEventStore) RegressIssue(issue *models.Issue, activityData []byte) (bool, error) {
+	regressed := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Only the first event of concurrent ones finds the issue still resolved
+		result := tx.Model(&models.Issue{}).Where("id = ? AND status = ?", issue.ID, models.StatusResolved).
+			Updates(map[string]interface{}{
+				"status":              models.StatusUnresolved,
+				"is_regression":       true,
+				"resolved_in_release": nil,
+				"updated_at":          time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		activity := models.IssueActivity{
+			IssueID: issue.ID,
+			Type:    models.ActivityRegression,
+			Data:    activityData,
+		}
+		activity.ID = uuid.New()
+		if err := tx.Create(&activity).Error; err != nil {
+			return err
+		}
+		regressed = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to regress issue: %w", err)
+	}
+	return regressed, nil
+}
+
+func (s *GormEventStore) UnignoreIssue(issue *models.Issue, activityData []byte) (bool, error) {
+	unignored := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Only the first event of concurrent ones finds the issue still ignored
+		result := tx.Model(&models.Issue{}).Where("id = ? AND status = ?", issue.ID, models.StatusIgnored).
+			Updates(map[string]interface{}{
+				"status":           models.StatusUnresolved,
+				"ignore_condition": nil,
+				"updated_at":       time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		activity := models.IssueActivity{
+			IssueID: issue.ID,
+			Type:    models.ActivityUnignore,
+			Data:    activityData,
+		}
+		activity.ID = uuid.New()
+		if err := tx.Create(&activity).Error; err != nil {
+			return err
+		}
+		unignored = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to unignore issue: %w", err)
+	}
+	return unignored, nil
+}
+
+func (s *GormEventStore) GetIgnoreCondition(issueID uuid.UUID) ([]byte, error) {
+	var conditions []datatypes.JSON
+	if err := s.db.Model(&models.Issue{}).Where("id = ? AND status = ?", issueID, models.StatusIgnored).
+		Pluck("ignore_condition", &conditions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get ignore condition: %w", err)
+	}
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+	return conditions[0], nil
+}
+
+func (s *GormEventStore) UpdateIgnoreCondition(issueID uuid.UUID, previous, condition []byte) (bool, error) {
+	result := s.db.Model(&models.Issue{}).
+		Where("id = ? AND status = ?", issueID, models.StatusIgnored).
+		Where(database.JSONEquals(s.db, "ignore_condition"), string(previous)).
+		Update("ignore_condition", datatypes.JSON(condition))
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update ignore condition: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (s *GormEventStore) ListIssues(projectID uuid.UUID, limit, offset int) ([]models.Issue, error) {
+	var issues []models.Issue
+	if err := s.db.Where("project_id = ?", projectID).
+		Order("last_seen DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&issues).Error; err != nil {
+		return nil, fmt.Errorf("failed to get issues: %w", err)
+	}
+	return issues, nil
+}
+
+func (s *GormEventStore) ListIssueEvents(projectID, issueID uuid.UUID, limit, offset int) ([]models.Event, error) {
+	var events []models.Event
+	if err := s.db.Where("project_id = ? AND issue_id = ?", projectID, issueID).
+		Order("timestamp DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+	return events, nil
+}
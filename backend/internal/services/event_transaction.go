@@ -0,0 +1,81 @@
+package services
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"minisentry/internal/dto"
+)
+
+// maxTransactionLength matches the size of the transaction columns
+const maxTransactionLength = 200
+
+var (
+	numericSegment = regexp.MustCompile(`^\d+$`)
+	uuidSegment    = regexp.MustCompile(`^(?i)[0-9a-f]{8}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{12}$`)
+	hexSegment     = regexp.MustCompile(`^(?i)[0-9a-f]{16,}$`)
+	tokenSegment   = regexp.MustCompile(`^[A-Za-z0-9_\-]{20,}$`)
+	digitPattern   = regexp.MustCompile(`\d`)
+)
+
+// extractTransaction returns the route an error event happened on: the transaction sent by
+// the SDK or, for web errors, the path of the request URL with its IDs templated, so events
+// of /orders/1 and /orders/2 share the /orders/{id} transaction
+func extractTransaction(eventData *dto.ErrorEventRequest) *string {
+	transaction := ""
+	if eventData.Transaction != nil {
+		transaction = strings.TrimSpace(*eventData.Transaction)
+	}
+	if transaction == "" && eventData.Request != nil && eventData.Request.URL != nil {
+		transaction = templateURLPath(*eventData.Request.URL)
+	}
+	if transaction == "" {
+		return nil
+	}
+
+	return truncateStringPtr(&transaction, maxTransactionLength)
+}
+
+// templateURLPath returns the path of a URL with segments that look like IDs (numbers, UUIDs,
+// hashes and tokens) replaced by {id}, without query string, fragment or trailing slash
+func templateURLPath(rawURL string) string {
+	path := rawURL
+	if parsed, err := url.Parse(rawURL); err == nil {
+		path = parsed.Path
+	} else if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	if path == "" {
+		return ""
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if isIDSegment(segment) {
+			segments[i] = "{id}"
+		}
+	}
+
+	templated := strings.Join(segments, "/")
+	if len(templated) > 1 {
+		templated = strings.TrimSuffix(templated, "/")
+	}
+	if !strings.HasPrefix(templated, "/") {
+		templated = "/" + templated
+	}
+	return templated
+}
+
+func isIDSegment(segment string) bool {
+	switch {
+	case segment == "":
+		return false
+	case numericSegment.MatchString(segment), uuidSegment.MatchString(segment), hexSegment.MatchString(segment):
+		return true
+	case tokenSegment.MatchString(segment):
+		// Long slugs are words; long tokens mix in digits
+		return digitPattern.MatchString(segment)
+	}
+	return false
+}
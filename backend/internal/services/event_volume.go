@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// defaultEventVolumeDays is how many days the event volume covers when no since is given
+	defaultEventVolumeDays = 30
+
+	// maxEventVolumeDays bounds the days a single event volume query covers
+	maxEventVolumeDays = 366
+)
+
+var (
+	ErrInvalidEventVolumeGroup  = errors.New("event volume can only be grouped by environment and release")
+	ErrInvalidEventVolumeWindow = errors.New("event volume window must end after it starts")
+	ErrEventVolumeWindowTooLong = fmt.Errorf("event volume window cannot exceed %d days", maxEventVolumeDays)
+)
+
+// eventVolumeKey is a project's day, environment and release. Keys aggregated for a query
+// leave out the project and the dimensions the volume is not grouped by.
+type eventVolumeKey struct {
+	projectID   uuid.UUID
+	day         time.Time
+	environment string
+	release     string
+}
+
+// EventVolumeService rolls up the error events projects accept per UTC day, environment and
+// release. Counts are buffered and written to the database periodically.
+type EventVolumeService struct {
+	db *database.DB
+
+	mu      sync.Mutex
+	pending map[eventVolumeKey]int64 // events not yet written
+}
+
+// NewEventVolumeService creates a new event volume service
+func NewEventVolumeService(db *database.DB) *EventVolumeService {
+	return &EventVolumeService{
+		db:      db,
+		pending: make(map[eventVolumeKey]int64),
+	}
+}
+
+// SetEventVolume makes ingestion count the error events it accepts per day, environment and release
+func (es *ErrorService) SetEventVolume(volume *EventVolumeService) {
+	es.volume = volume
+}
+
+// countVolume counts an accepted event, stored or sampled out, in its project's event volume
+func (es *ErrorService) countVolume(projectID uuid.UUID, normalizedData *dto.NormalizedErrorData) {
+	if es.volume != nil {
+		es.volume.Count(projectID, normalizedData.Timestamp, normalizedData.Environment, normalizedData.Release)
+	}
+}
+
+// Count buffers an event of a project in the volume of the day of its timestamp
+func (vs *EventVolumeService) Count(projectID uuid.UUID, timestamp time.Time, environment string, release *string) {
+	key := eventVolumeKey{projectID: projectID, day: eventVolumeDay(timestamp), environment: environment}
+	if release != nil {
+		key.release = *release
+	}
+
+	vs.mu.Lock()
+	vs.pending[key]++
+	vs.mu.Unlock()
+}
+
+// FlushStats adds the buffered event counts to the daily totals in the database.
+// It is run periodically by the scheduler and on shutdown.
+func (vs *EventVolumeService) FlushStats(ctx context.Context) error {
+	pending := takeCounts(&vs.mu, &vs.pending)
+	err := flushCounts(&vs.mu, &vs.pending, pending, func(key eventVolumeKey, events int64) error {
+		row := models.EventVolumeStat{
+			ProjectID:      key.projectID,
+			Day:            key.day,
+			Environment:    key.environment,
+			ReleaseVersion: key.release,
+			Events:         events,
+		}
+		return vs.db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "project_id"}, {Name: "day"}, {Name: "environment"}, {Name: "release_version"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"events":     clause.Expr{SQL: "event_volume_stats.events + excluded.events"},
+				"updated_at": time.Now(),
+			}),
+		}).Create(&row).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record event volume: %w", err)
+	}
+	return nil
+}
+
+// GetEventVolume returns a project's daily error event volume between two days, for the
+// given environments and releases, grouped by environment and/or release
+func (vs *EventVolumeService) GetEventVolume(projectID uuid.UUID, filters *dto.EventVolumeFilters) (*dto.EventVolumeResponse, error) {
+	var byEnvironment, byRelease bool
+	for _, group := range filters.GroupBy {
+		switch group {
+		case "environment":
+			byEnvironment = true
+		case "release":
+			byRelease = true
+		default:
+			return nil, ErrInvalidEventVolumeGroup
+		}
+	}
+
+	until := filters.Until
+	if until.IsZero() {
+		until = time.Now()
+	}
+	until = eventVolumeDay(until)
+	since := filters.Since
+	if since.IsZero() {
+		since = until.AddDate(0, 0, 1-defaultEventVolumeDays)
+	}
+	since = eventVolumeDay(since)
+	if until.Before(since) {
+		return nil, ErrInvalidEventVolumeWindow
+	}
+	if until.Sub(since) >= maxEventVolumeDays*24*time.Hour {
+		return nil, ErrEventVolumeWindowTooLong
+	}
+
+	columns := []string{"day"}
+	if byEnvironment {
+		columns = append(columns, "environment")
+	}
+	if byRelease {
+		columns = append(columns, "release_version")
+	}
+	group := strings.Join(columns, ", ")
+
+	query := vs.db.Model(&models.EventVolumeStat{}).
+		Where("project_id = ? AND day >= ? AND day <= ?", projectID, since, until)
+	if len(filters.Environments) > 0 {
+		query = query.Where("environment IN ?", filters.Environments)
+	}
+	if len(filters.Releases) > 0 {
+		query = query.Where("release_version IN ?", filters.Releases)
+	}
+
+	var rows []struct {
+		Day            time.Time
+		Environment    string
+		ReleaseVersion string
+		Events         int64
+	}
+	if err := query.Select(group + ", SUM(events) AS events").Group(group).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to aggregate event volume: %w", err)
+	}
+
+	volume := make(map[eventVolumeKey]int64, len(rows))
+	for _, row := range rows {
+		volume[eventVolumeKey{day: row.Day.UTC(), environment: row.Environment, release: row.ReleaseVersion}] += row.Events
+	}
+	vs.mu.Lock()
+	for key, events := range vs.pending {
+		if key.projectID != projectID || key.day.Before(since) || key.day.After(until) {
+			continue
+		}
+		if len(filters.Environments) > 0 && !slices.Contains(filters.Environments, key.environment) {
+			continue
+		}
+		if len(filters.Releases) > 0 && !slices.Contains(filters.Releases, key.release) {
+			continue
+		}
+		key.projectID = uuid.Nil
+		if !byEnvironment {
+			key.environment = ""
+		}
+		if !byRelease {
+			key.release = ""
+		}
+		volume[key] += events
+	}
+	vs.mu.Unlock()
+
+	response := &dto.EventVolumeResponse{
+		ProjectID: projectID,
+		Since:     since,
+		Until:     until,
+		GroupBy:   []string{},
+		Series:    make([]dto.EventVolumePoint, 0, len(volume)),
+	}
+	if byEnvironment {
+		response.GroupBy = append(response.GroupBy, "environment")
+	}
+	if byRelease {
+		response.GroupBy = append(response.GroupBy, "release")
+	}
+	for key, events := range volume {
+		point := dto.EventVolumePoint{Day: key.day, Events: events}
+		if byEnvironment {
+			point.Environment = &key.environment
+		}
+		if byRelease {
+			point.Release = &key.release
+		}
+		response.Series = append(response.Series, point)
+		response.TotalEvents += events
+	}
+	sort.Slice(response.Series, func(i, j int) bool {
+		a, b := response.Series[i], response.Series[j]
+		if !a.Day.Equal(b.Day) {
+			return a.Day.Before(b.Day)
+		}
+		if a.Environment != nil && *a.Environment != *b.Environment {
+			return *a.Environment < *b.Environment
+		}
+		return a.Release != nil && *a.Release < *b.Release
+	})
+	return response, nil
+}
+
+// eventVolumeDay is the UTC day of a time, as midnight
+func eventVolumeDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"crypto/sha256"
 	"fmt"
+	"hash/fnv"
 	"regexp"
 	"sort"
 	"strings"
@@ -10,9 +11,22 @@ import (
 	"minisentry/internal/dto"
 )
 
+// Default grouping of projects that do not configure their own
+const (
+	DefaultGroupingStackFrames        = 5 // Use top 5 stack frames for fingerprinting
+	DefaultGroupingIncludeMessage     = true
+	DefaultGroupingIncludeTransaction = false
+	DefaultGroupingRootCause          = false
+)
+
 type FingerprintService struct {
 	// Configuration for fingerprinting
 	maxStackFrames       int
+	includeMessage       bool
+	includeTransaction   bool
+	groupByRootCause     bool
+	template             []string         // Fingerprint template; empty groups by default
+	ignoreFrames         []*regexp.Regexp // Functions and modules of frames left out
 	normalizeURLs        bool
 	normalizeFilePaths   bool
 	ignoreLocalVariables bool
@@ -21,13 +35,28 @@ type FingerprintService struct {
 // NewFingerprintService creates a new fingerprint service
 func NewFingerprintService() *FingerprintService {
 	return &FingerprintService{
-		maxStackFrames:       5,  // Use top 5 stack frames for fingerprinting
+		maxStackFrames:       DefaultGroupingStackFrames,
+		includeMessage:       DefaultGroupingIncludeMessage,
+		includeTransaction:   DefaultGroupingIncludeTransaction,
+		groupByRootCause:     DefaultGroupingRootCause,
 		normalizeURLs:        true,
 		normalizeFilePaths:   true,
 		ignoreLocalVariables: true,
 	}
 }
 
+// WithGrouping returns a fingerprint service grouping events as a project's grouping config says
+func (fs *FingerprintService) WithGrouping(config dto.GroupingConfig) *FingerprintService {
+	configured := *fs
+	configured.maxStackFrames = config.StackFrames
+	configured.includeMessage = config.IncludeMessage
+	configured.includeTransaction = config.IncludeTransaction
+	configured.groupByRootCause = config.GroupByRootCause
+	configured.template = config.Fingerprint
+	configured.ignoreFrames = compileFrameIgnorePatterns(config.IgnoreFrames)
+	return &configured
+}
+
 // GenerateErrorFingerprint creates a fingerprint for error grouping
 func (fs *FingerprintService) GenerateErrorFingerprint(errorData *dto.NormalizedErrorData) string {
 	components := fs.extractFingerprintComponents(errorData)
@@ -35,34 +64,105 @@ func (fs *FingerprintService) GenerateErrorFingerprint(errorData *dto.Normalized
 	return fs.hashFingerprint(fingerprintString)
 }
 
+// ConfiguredFingerprint fingerprints an event the way a project's grouping config says:
+// with its fingerprint template when it has one, by default otherwise
+func (fs *FingerprintService) ConfiguredFingerprint(errorData *dto.NormalizedErrorData, config dto.GroupingConfig) string {
+	return fs.WithGrouping(config).GroupingFingerprint(errorData)
+}
+
+// GroupingFingerprint fingerprints an event with the fingerprint template of the grouping
+// the service was configured with, by default when it has none
+func (fs *FingerprintService) GroupingFingerprint(errorData *dto.NormalizedErrorData) string {
+	if len(fs.template) > 0 {
+		return fs.CustomFingerprint(errorData, fs.template)
+	}
+	return fs.GenerateErrorFingerprint(errorData)
+}
+
 // extractFingerprintComponents extracts the key components for fingerprinting
 func (fs *FingerprintService) extractFingerprintComponents(errorData *dto.NormalizedErrorData) *dto.FingerprintComponents {
 	components := &dto.FingerprintComponents{
 		Platform: errorData.Platform,
 	}
 
+	exceptionType, exceptionValue, stackTrace := errorData.ExceptionType, errorData.ExceptionValue, errorData.StackTrace
+	if fs.groupByRootCause && len(errorData.Exceptions) > 1 {
+		// Group by the root cause, so wrappers of the same failure land in one issue
+		root := rootCauseException(errorData.Exceptions)
+		exceptionType, exceptionValue = root.Type, root.Value
+		if root.Stacktrace != nil && len(root.Stacktrace.Frames) > 0 {
+			stackTrace = root.Stacktrace.Frames
+		}
+	}
+
 	// Extract error type and message
-	if errorData.ExceptionType != nil {
-		components.ErrorType = *errorData.ExceptionType
+	if exceptionType != nil {
+		components.ErrorType = *exceptionType
 	}
 	
-	if errorData.ExceptionValue != nil {
-		components.ErrorMessage = fs.normalizeErrorMessage(*errorData.ExceptionValue)
+	if exceptionValue != nil {
+		components.ErrorMessage = fs.normalizeErrorMessage(*exceptionValue)
 	} else if errorData.Message != nil {
 		components.ErrorMessage = fs.normalizeErrorMessage(*errorData.Message)
 	}
 
 	// Extract stack frame information
-	components.StackFrames = fs.extractStackFrameSignatures(errorData.StackTrace)
+	components.StackFrames = fs.extractStackFrameSignatures(stackTrace)
+
+	// The message is left out when configured, unless the event has nothing else to group by
+	if !fs.includeMessage && (components.ErrorType != "" || len(components.StackFrames) > 0) {
+		components.ErrorMessage = ""
+	}
+	
+	// Group events of different routes separately when configured; the culprit stands in for
+	// the transaction of events sent without one
+	if fs.includeTransaction {
+		if errorData.Transaction != nil && *errorData.Transaction != "" {
+			components.Transaction = *errorData.Transaction
+		} else if errorData.Culprit != nil {
+			components.Transaction = *errorData.Culprit
+		}
+	}
 	
 	// Extract primary filename
-	if len(errorData.StackTrace) > 0 && errorData.StackTrace[0].Filename != nil {
-		components.Filename = fs.normalizeFilename(*errorData.StackTrace[0].Filename)
+	if len(stackTrace) > 0 && stackTrace[0].Filename != nil {
+		components.Filename = fs.normalizeFilename(*stackTrace[0].Filename)
 	}
 
 	return components
 }
 
+// rootCauseException returns the innermost exception of a chain. When the mechanisms link
+// the exceptions, it is the cause furthest from the exception thrown; otherwise the chain is
+// taken in the order Sentry sends it, oldest first, so the root cause is the first exception.
+func rootCauseException(chain []dto.ExceptionValue) dto.ExceptionValue {
+	parents := make(map[int]int)
+	for _, exception := range chain {
+		if exception.Mechanism != nil && exception.Mechanism.ExceptionID != nil && exception.Mechanism.ParentID != nil {
+			parents[*exception.Mechanism.ExceptionID] = *exception.Mechanism.ParentID
+		}
+	}
+	if len(parents) == 0 {
+		return chain[0]
+	}
+
+	root, rootDepth := chain[0], -1
+	for _, exception := range chain {
+		if exception.Mechanism == nil || exception.Mechanism.ExceptionID == nil {
+			continue
+		}
+		depth := 0
+		// Bounded by the chain length, in case the parents form a cycle
+		for id, ok := parents[*exception.Mechanism.ExceptionID]; ok && depth < len(chain); id, ok = parents[id] {
+			depth++
+		}
+		if depth > rootDepth {
+			root, rootDepth = exception, depth
+		}
+	}
+	return root
+}
+
 // normalizeErrorMessage removes dynamic parts from error messages
 func (fs *FingerprintService) normalizeErrorMessage(message string) string {
 	// Remove common dynamic patterns
@@ -159,6 +259,11 @@ func (fs *FingerprintService) extractStackFrameSignatures(stackTrace []dto.Stack
 			continue
 		}
 
+		// Skip frames of framework internals and polyfills the project ignores
+		if fs.ignoresFrame(frame) {
+			continue
+		}
+
 		// Stop if we've collected enough frames
 		if frameCount >= fs.maxStackFrames {
 			break
@@ -174,6 +279,35 @@ func (fs *FingerprintService) extractStackFrameSignatures(stackTrace []dto.Stack
 	return signatures
 }
 
+// ignoresFrame tells whether one of the ignore_frames patterns of the project's grouping
+// matches the frame's function or module
+func (fs *FingerprintService) ignoresFrame(frame dto.StackFrame) bool {
+	for _, re := range fs.ignoreFrames {
+		if frame.Function != nil && *frame.Function != "" && re.MatchString(*frame.Function) {
+			return true
+		}
+		if frame.Module != nil && *frame.Module != "" && re.MatchString(*frame.Module) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileFrameIgnorePatterns compiles the ignore_frames patterns of a grouping config, which
+// were validated when saved
+func compileFrameIgnorePatterns(patterns []string) []*regexp.Regexp {
+	if len(patterns) == 0 {
+		return nil
+	}
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		if re, err := compileInboundFilterPattern(pattern); err == nil {
+			compiled = append(compiled, re)
+		}
+	}
+	return compiled
+}
+
 // buildFrameSignature creates a signature for a single stack frame
 func (fs *FingerprintService) buildFrameSignature(frame dto.StackFrame) string {
 	var parts []string
@@ -260,6 +394,11 @@ func (fs *FingerprintService) buildFingerprintString(components *dto.Fingerprint
 		parts = append(parts, fmt.Sprintf("message:%s", components.ErrorMessage))
 	}
 
+	// Add transaction
+	if components.Transaction != "" {
+		parts = append(parts, fmt.Sprintf("transaction:%s", components.Transaction))
+	}
+
 	// Add primary filename
 	if components.Filename != "" {
 		parts = append(parts, fmt.Sprintf("file:%s", components.Filename))
@@ -278,6 +417,12 @@ func (fs *FingerprintService) buildFingerprintString(components *dto.Fingerprint
 	return strings.Join(parts, "||")
 }
 
+// GenerateCSPFingerprint groups CSP violations by the directive that was violated and the
+// blocked source, so every page hitting the same rule lands in one issue
+func (fs *FingerprintService) GenerateCSPFingerprint(directive, blockedSource string) string {
+	return fs.hashFingerprint(fmt.Sprintf("platform:csp||directive:%s||blocked:%s", directive, blockedSource))
+}
+
 // hashFingerprint creates a SHA256 hash of the fingerprint string
 func (fs *FingerprintService) hashFingerprint(fingerprintString string) string {
 	hash := sha256.Sum256([]byte(fingerprintString))
@@ -286,9 +431,19 @@ func (fs *FingerprintService) hashFingerprint(fingerprintString string) string {
 
 // CustomFingerprint allows for custom fingerprinting rules
 func (fs *FingerprintService) CustomFingerprint(errorData *dto.NormalizedErrorData, customRules []string) string {
-	if len(customRules) == 0 {
+	customString, ok := fs.customFingerprintString(errorData, customRules)
+	if !ok {
 		return fs.GenerateErrorFingerprint(errorData)
 	}
+	return fs.hashFingerprint(customString)
+}
+
+// customFingerprintString builds the string custom fingerprinting rules hash; false when the
+// rules fall back to the default fingerprint
+func (fs *FingerprintService) customFingerprintString(errorData *dto.NormalizedErrorData, customRules []string) (string, bool) {
+	if len(customRules) == 0 {
+		return "", false
+	}
 
 	// Apply custom fingerprinting rules
 	var parts []string
@@ -296,7 +451,7 @@ func (fs *FingerprintService) CustomFingerprint(errorData *dto.NormalizedErrorDa
 		switch rule {
 		case "{{ default }}":
 			// Use default fingerprinting
-			return fs.GenerateErrorFingerprint(errorData)
+			return "", false
 		case "{{ error.type }}":
 			if errorData.ExceptionType != nil {
 				parts = append(parts, *errorData.ExceptionType)
@@ -316,17 +471,84 @@ func (fs *FingerprintService) CustomFingerprint(errorData *dto.NormalizedErrorDa
 		}
 	}
 
-	customString := strings.Join(parts, "||")
-	return fs.hashFingerprint(customString)
+	return strings.Join(parts, "||"), true
+}
+
+// SimilaritySignature is what SimilarityScore compares issues by
+type SimilaritySignature struct {
+	Title  string   // normalized like grouped messages
+	Frames []string // signatures of the innermost in-app frames
+}
+
+// SimilaritySignature builds the signature of an issue from its title and the stack trace of
+// one of its events
+func (fs *FingerprintService) SimilaritySignature(title string, stackTrace []dto.StackFrame) SimilaritySignature {
+	// Frames are ordered oldest call first; the innermost ones tell issues apart
+	innermost := make([]dto.StackFrame, len(stackTrace))
+	for i, frame := range stackTrace {
+		innermost[len(stackTrace)-1-i] = frame
+	}
+
+	return SimilaritySignature{
+		Title:  strings.ToLower(fs.normalizeErrorMessage(title)),
+		Frames: fs.extractStackFrameSignatures(innermost),
+	}
 }
 
-// SimilarityScore calculates similarity between two fingerprints
-func (fs *FingerprintService) SimilarityScore(fingerprint1, fingerprint2 string) float64 {
-	if fingerprint1 == fingerprint2 {
-		return 1.0
+// SimilarityScore scores from 0 to 1 how alike two issues are: the overlap of the shingles
+// of their titles, averaged with the overlap of their frames when both have some
+func (fs *FingerprintService) SimilarityScore(a, b SimilaritySignature) float64 {
+	score := jaccardSimilarity(titleShingles(a.Title), titleShingles(b.Title))
+	if len(a.Frames) == 0 || len(b.Frames) == 0 {
+		return score
+	}
+
+	framesA := make(map[uint64]struct{}, len(a.Frames))
+	for _, frame := range a.Frames {
+		framesA[shingleHash(frame)] = struct{}{}
 	}
+	framesB := make(map[uint64]struct{}, len(b.Frames))
+	for _, frame := range b.Frames {
+		framesB[shingleHash(frame)] = struct{}{}
+	}
+	return (score + jaccardSimilarity(framesA, framesB)) / 2
+}
+
+// titleShingleSize is the length in characters of the shingles titles are compared by
+const titleShingleSize = 3
 
-	// For now, exact match only
-	// In the future, we could implement fuzzy matching for similar errors
-	return 0.0
-}
\ No newline at end of file
+// titleShingles hashes the overlapping character shingles of a title
+func titleShingles(title string) map[uint64]struct{} {
+	runes := []rune(title)
+	shingles := make(map[uint64]struct{})
+	if len(runes) <= titleShingleSize {
+		if len(runes) > 0 {
+			shingles[shingleHash(title)] = struct{}{}
+		}
+		return shingles
+	}
+	for i := 0; i+titleShingleSize <= len(runes); i++ {
+		shingles[shingleHash(string(runes[i:i+titleShingleSize]))] = struct{}{}
+	}
+	return shingles
+}
+
+func shingleHash(shingle string) uint64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(shingle))
+	return hash.Sum64()
+}
+
+// jaccardSimilarity is the size of the intersection of two sets over that of their union
+func jaccardSimilarity(a, b map[uint64]struct{}) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for hash := range a {
+		if _, ok := b[hash]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"minisentry/internal/dto"
+
+	"github.com/google/uuid"
+)
+
+// What decided the fingerprint of a previewed event
+const (
+	FingerprintSourceDefault  = "default"
+	FingerprintSourceTemplate = "grouping_template"
+	FingerprintSourceEvent    = "event"
+	FingerprintSourceRule     = "fingerprint_rule"
+)
+
+// PreviewFingerprint fingerprints an event payload the way ingestion would, without storing
+// it, and explains the result: the components of the default grouping, the normalized string
+// hashed and the issue the event would be grouped into. It follows generateFingerprint.
+func (es *ErrorService) PreviewFingerprint(projectID uuid.UUID, eventData *dto.ErrorEventRequest) (*dto.FingerprintPreviewResponse, error) {
+	if err := es.ValidateErrorPayload(eventData); err != nil {
+		return nil, err
+	}
+	normalizedData, err := es.NormalizeErrorData(projectID, eventData, "", "")
+	if err != nil {
+		return nil, err
+	}
+	if err := es.runProcessors(normalizedData); err != nil {
+		return nil, err
+	}
+
+	config := storedGroupingConfig{}.resolve()
+	config.Version = 1
+	fingerprints := es.fingerprintService.WithGrouping(config)
+	if es.groupingConfigs != nil {
+		config, fingerprints = es.groupingConfigs.projectFingerprints(projectID)
+	}
+
+	components := fingerprints.extractFingerprintComponents(normalizedData)
+	preview := &dto.FingerprintPreviewResponse{
+		Source:            FingerprintSourceDefault,
+		FingerprintString: fingerprints.buildFingerprintString(components),
+		Components:        components,
+		GroupingVersion:   config.Version,
+	}
+
+	rules := eventData.Fingerprint
+	if len(rules) > 0 {
+		preview.Source = FingerprintSourceEvent
+	} else if len(config.Fingerprint) > 0 {
+		rules = config.Fingerprint
+		preview.Source = FingerprintSourceTemplate
+	}
+	if fingerprint, ok := es.previewRuleFingerprint(normalizedData); ok {
+		preview.Source = FingerprintSourceRule
+		preview.Fingerprint = fingerprint
+		preview.FingerprintString = ""
+	} else {
+		// Templates using {{ default }} fall back to the default grouping
+		if customString, ok := fingerprints.customFingerprintString(normalizedData, rules); ok {
+			preview.FingerprintString = customString
+		} else {
+			preview.Source = FingerprintSourceDefault
+		}
+		preview.Fingerprint = fingerprints.hashFingerprint(preview.FingerprintString)
+	}
+
+	issue, err := es.store.FindIssueByFingerprint(projectID, preview.Fingerprint)
+	switch {
+	case err == nil:
+		preview.IssueID = &issue.ID
+	case !errors.Is(err, ErrIssueNotFound):
+		return nil, fmt.Errorf("failed to get issue: %w", err)
+	}
+	return preview, nil
+}
+
+func (es *ErrorService) previewRuleFingerprint(normalizedData *dto.NormalizedErrorData) (string, bool) {
+	if es.groupingConfigs == nil {
+		return "", false
+	}
+	return es.groupingConfigs.ruleFingerprint(normalizedData)
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"minisentry/internal/dto"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// geoIPRecord is the part of a GeoIP2 or GeoLite2 Country or City record events are tagged with
+type geoIPRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	Subdivisions []struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"subdivisions"`
+}
+
+// GeoIP looks up the country and region of IP addresses in a MaxMind database
+type GeoIP struct {
+	reader *maxminddb.Reader
+}
+
+// OpenGeoIP opens a MaxMind GeoIP2 or GeoLite2 Country or City database (.mmdb)
+func OpenGeoIP(path string) (*GeoIP, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GeoIP database: %w", err)
+	}
+	return &GeoIP{reader: reader}, nil
+}
+
+// Close closes the database
+func (g *GeoIP) Close() error {
+	return g.reader.Close()
+}
+
+// Lookup returns the ISO country code and the region (the top subdivision's English name,
+// only in City databases) of an IP address, "" when unknown
+func (g *GeoIP) Lookup(address string) (countryCode, region string) {
+	ip := net.ParseIP(address)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() {
+		return "", ""
+	}
+	var record geoIPRecord
+	if err := g.reader.Lookup(ip, &record); err != nil {
+		return "", ""
+	}
+	if len(record.Subdivisions) > 0 {
+		region = record.Subdivisions[0].Names["en"]
+	}
+	return record.Country.ISOCode, region
+}
+
+// SetGeoIP makes ingestion tag events with the country and region of the user's IP
+// address. It must be called before the server starts.
+func (es *ErrorService) SetGeoIP(geoIP *GeoIP) {
+	es.geoIP = geoIP
+}
+
+// addGeoTags tags an event with the geo.country_code and geo.region of the IP address of
+// its user, or else of the client that sent it, keeping the tags it already has
+func (es *ErrorService) addGeoTags(normalized *dto.NormalizedErrorData, clientIP string) {
+	if es.geoIP == nil {
+		return
+	}
+	address := clientIP
+	if user := normalized.UserContext; user != nil && user.IPAddress != nil && *user.IPAddress != "{{auto}}" {
+		address = *user.IPAddress
+	}
+
+	countryCode, region := es.geoIP.Lookup(strings.TrimSpace(address))
+	for key, value := range map[string]string{"geo.country_code": countryCode, "geo.region": region} {
+		if _, ok := normalized.Tags[key]; !ok && value != "" {
+			normalized.Tags[key] = value
+		}
+	}
+}
@@ -0,0 +1,363 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+var ErrGroupingConfigInvalid = errors.New("invalid grouping config")
+
+const (
+	// groupingConfigCacheTTL bounds how long ingestion uses a project's grouping config
+	// before reloading it, so changes made through another server apply within this time
+	groupingConfigCacheTTL = 30 * time.Second
+
+	maxGroupingStackFrames        = 50
+	maxGroupingFingerprintParts   = 20
+	maxGroupingFingerprintPartLen = 200
+	maxIgnoreFramePatterns        = 50
+	maxIgnoreFramePatternLength   = 200
+)
+
+// storedGroupingConfig is a project's grouping config as stored; unset fields use the defaults
+type storedGroupingConfig struct {
+	StackFrames        *int     `json:"stack_frames,omitempty"`
+	IncludeMessage     *bool    `json:"include_message,omitempty"`
+	IncludeTransaction *bool    `json:"include_transaction,omitempty"`
+	GroupByRootCause   *bool    `json:"group_by_root_cause,omitempty"`
+	RefreshIssueTitles *bool    `json:"refresh_issue_titles,omitempty"`
+	InAppInclude       []string `json:"in_app_include,omitempty"`
+	InAppExclude       []string `json:"in_app_exclude,omitempty"`
+	IgnoreFrames       []string `json:"ignore_frames,omitempty"`
+	Fingerprint        []string `json:"fingerprint,omitempty"`
+}
+
+type cachedGroupingConfig struct {
+	config       dto.GroupingConfig
+	inApp        inAppRules
+	fingerprints *FingerprintService // configured with config
+	expires      time.Time
+}
+
+// GroupingConfigService manages how projects group error events into issues, which the
+// fingerprinting of ingested events follows, along with the fingerprint rules of their
+// issues. Configs and rules are cached briefly per project.
+type GroupingConfigService struct {
+	db *database.DB
+
+	mu    sync.Mutex
+	cache map[uuid.UUID]*cachedGroupingConfig
+	rules map[uuid.UUID]*cachedFingerprintRules
+}
+
+// NewGroupingConfigService creates a new grouping config service
+func NewGroupingConfigService(db *database.DB) *GroupingConfigService {
+	return &GroupingConfigService{
+		db:    db,
+		cache: make(map[uuid.UUID]*cachedGroupingConfig),
+		rules: make(map[uuid.UUID]*cachedFingerprintRules),
+	}
+}
+
+// SetGroupingConfigs makes ingestion fingerprint events with their project's grouping
+// config. It must be called before the server starts.
+func (es *ErrorService) SetGroupingConfigs(groupingConfigs *GroupingConfigService) {
+	es.groupingConfigs = groupingConfigs
+}
+
+// GetGroupingConfig returns a project's grouping config, defaults included
+func (gcs *GroupingConfigService) GetGroupingConfig(projectID uuid.UUID) (*dto.GroupingConfigResponse, error) {
+	stored, version, err := gcs.load(projectID)
+	if err != nil {
+		return nil, err
+	}
+	config := stored.resolve()
+	config.Version = version
+	return &dto.GroupingConfigResponse{ProjectID: projectID, GroupingConfig: config}, nil
+}
+
+// UpdateGroupingConfig changes a project's grouping config, recording the change in the
+// project's setting history and counting it in the config version. Events already grouped
+// keep their issues until regrouped.
+func (gcs *GroupingConfigService) UpdateGroupingConfig(userID, projectID uuid.UUID, request dto.GroupingConfigRequest) (*dto.GroupingConfigResponse, error) {
+	var project models.Project
+	if err := gcs.db.Where("id = ?", projectID).First(&project).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	current := decodeGroupingConfig(project.GroupingConfig)
+	updated := current
+	if request.StackFrames != nil {
+		switch {
+		case *request.StackFrames < 0 || *request.StackFrames > maxGroupingStackFrames:
+			return nil, fmt.Errorf("%w: stack_frames must be between 1 and %d, or 0 for the default", ErrGroupingConfigInvalid, maxGroupingStackFrames)
+		case *request.StackFrames == 0:
+			updated.StackFrames = nil
+		default:
+			updated.StackFrames = request.StackFrames
+		}
+	}
+	if request.IncludeMessage != nil {
+		updated.IncludeMessage = request.IncludeMessage
+	}
+	if request.IncludeTransaction != nil {
+		updated.IncludeTransaction = request.IncludeTransaction
+	}
+	if request.GroupByRootCause != nil {
+		updated.GroupByRootCause = request.GroupByRootCause
+	}
+	if request.RefreshIssueTitles != nil {
+		updated.RefreshIssueTitles = request.RefreshIssueTitles
+	}
+	if request.InAppInclude != nil {
+		patterns, err := normalizeInAppPatterns("in_app_include", *request.InAppInclude)
+		if err != nil {
+			return nil, err
+		}
+		updated.InAppInclude = patterns
+	}
+	if request.InAppExclude != nil {
+		patterns, err := normalizeInAppPatterns("in_app_exclude", *request.InAppExclude)
+		if err != nil {
+			return nil, err
+		}
+		updated.InAppExclude = patterns
+	}
+	if request.IgnoreFrames != nil {
+		patterns, err := normalizeFrameIgnorePatterns(*request.IgnoreFrames)
+		if err != nil {
+			return nil, err
+		}
+		updated.IgnoreFrames = patterns
+	}
+	if request.Fingerprint != nil {
+		fingerprint, err := normalizeGroupingFingerprint(*request.Fingerprint)
+		if err != nil {
+			return nil, err
+		}
+		updated.Fingerprint = fingerprint
+	}
+
+	encoded, err := json.Marshal(updated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode grouping config: %w", err)
+	}
+
+	var diff settingDiff
+	diff.add("grouping_config", current.resolve(), updated.resolve())
+
+	// Only changes of how events are fingerprinted make a new version
+	var fingerprinting settingDiff
+	fingerprinting.add("grouping_config", current.fingerprinting(), updated.fingerprinting())
+
+	version := project.GroupingVersion
+	updates := map[string]interface{}{"grouping_config": datatypes.JSON(encoded)}
+	if len(fingerprinting) > 0 {
+		version++
+		updates["grouping_version"] = version
+	}
+
+	err = gcs.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&project).Updates(updates).Error; err != nil {
+			return err
+		}
+		return recordSettingChanges(tx, userID, models.AuditProjectSettingChanged, settingTargetProject, projectID, project.OrganizationID, &projectID, diff)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update grouping config: %w", err)
+	}
+
+	gcs.mu.Lock()
+	delete(gcs.cache, projectID)
+	gcs.mu.Unlock()
+
+	config := updated.resolve()
+	config.Version = version
+	return &dto.GroupingConfigResponse{ProjectID: projectID, GroupingConfig: config}, nil
+}
+
+// normalizeGroupingFingerprint trims the parts of a fingerprint template and checks its size
+func normalizeGroupingFingerprint(parts []string) ([]string, error) {
+	if len(parts) > maxGroupingFingerprintParts {
+		return nil, fmt.Errorf("%w: fingerprint cannot have more than %d parts", ErrGroupingConfigInvalid, maxGroupingFingerprintParts)
+	}
+	normalized := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("%w: fingerprint parts cannot be empty", ErrGroupingConfigInvalid)
+		}
+		if len(part) > maxGroupingFingerprintPartLen {
+			return nil, fmt.Errorf("%w: fingerprint parts cannot exceed %d characters", ErrGroupingConfigInvalid, maxGroupingFingerprintPartLen)
+		}
+		normalized = append(normalized, part)
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return normalized, nil
+}
+
+// normalizeFrameIgnorePatterns trims the ignore_frames patterns, drops empty and repeated
+// ones and checks their count, length and that they compile
+func normalizeFrameIgnorePatterns(patterns []string) ([]string, error) {
+	normalized := make([]string, 0, len(patterns))
+	seen := make(map[string]bool, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" || seen[pattern] {
+			continue
+		}
+		if len(pattern) > maxIgnoreFramePatternLength {
+			return nil, fmt.Errorf("%w: ignore_frames patterns must be at most %d characters", ErrGroupingConfigInvalid, maxIgnoreFramePatternLength)
+		}
+		if _, err := compileInboundFilterPattern(pattern); err != nil {
+			return nil, fmt.Errorf("%w: invalid ignore_frames pattern %s", ErrGroupingConfigInvalid, pattern)
+		}
+		seen[pattern] = true
+		normalized = append(normalized, pattern)
+	}
+	if len(normalized) > maxIgnoreFramePatterns {
+		return nil, fmt.Errorf("%w: ignore_frames cannot have more than %d patterns", ErrGroupingConfigInvalid, maxIgnoreFramePatterns)
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return normalized, nil
+}
+
+// resolve fills in the defaults of the fields a stored config leaves unset
+func (c storedGroupingConfig) resolve() dto.GroupingConfig {
+	config := dto.GroupingConfig{
+		StackFrames:        DefaultGroupingStackFrames,
+		IncludeMessage:     DefaultGroupingIncludeMessage,
+		IncludeTransaction: DefaultGroupingIncludeTransaction,
+		GroupByRootCause:   DefaultGroupingRootCause,
+		InAppInclude:       []string{},
+		InAppExclude:       []string{},
+		IgnoreFrames:       []string{},
+		Fingerprint:        []string{},
+	}
+	if c.StackFrames != nil {
+		config.StackFrames = *c.StackFrames
+	}
+	if c.IncludeMessage != nil {
+		config.IncludeMessage = *c.IncludeMessage
+	}
+	if c.IncludeTransaction != nil {
+		config.IncludeTransaction = *c.IncludeTransaction
+	}
+	if c.GroupByRootCause != nil {
+		config.GroupByRootCause = *c.GroupByRootCause
+	}
+	if c.RefreshIssueTitles != nil {
+		config.RefreshIssueTitles = *c.RefreshIssueTitles
+	}
+	if len(c.InAppInclude) > 0 {
+		config.InAppInclude = c.InAppInclude
+	}
+	if len(c.InAppExclude) > 0 {
+		config.InAppExclude = c.InAppExclude
+	}
+	if len(c.IgnoreFrames) > 0 {
+		config.IgnoreFrames = c.IgnoreFrames
+	}
+	if len(c.Fingerprint) > 0 {
+		config.Fingerprint = c.Fingerprint
+	}
+	return config
+}
+
+// fingerprinting is the part of a config the fingerprints of events follow
+func (c storedGroupingConfig) fingerprinting() dto.GroupingConfig {
+	config := c.resolve()
+	config.RefreshIssueTitles = false
+	return config
+}
+
+// decodeGroupingConfig returns the grouping config stored with a project; the defaults when
+// unset or unreadable
+func decodeGroupingConfig(stored datatypes.JSON) storedGroupingConfig {
+	var config storedGroupingConfig
+	if len(stored) > 0 {
+		json.Unmarshal(stored, &config)
+	}
+	return config
+}
+
+// load returns a project's stored grouping config and its version
+func (gcs *GroupingConfigService) load(projectID uuid.UUID) (storedGroupingConfig, int, error) {
+	var project models.Project
+	if err := gcs.db.Select("id", "grouping_config", "grouping_version").Where("id = ?", projectID).First(&project).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return storedGroupingConfig{}, 0, ErrProjectNotFound
+		}
+		return storedGroupingConfig{}, 0, fmt.Errorf("failed to get grouping config: %w", err)
+	}
+	return decodeGroupingConfig(project.GroupingConfig), project.GroupingVersion, nil
+}
+
+// projectFingerprints returns a project's grouping config from the cache, loading it when
+// missing or expired, along with a fingerprint service configured with it so ingestion does
+// not compile its patterns for every event. Events are grouped by default when the config
+// cannot be loaded.
+func (gcs *GroupingConfigService) projectFingerprints(projectID uuid.UUID) (dto.GroupingConfig, *FingerprintService) {
+	cached := gcs.cached(projectID)
+	return cached.config, cached.fingerprints
+}
+
+// projectInAppRules returns the compiled in-app rules of a project's grouping config
+func (gcs *GroupingConfigService) projectInAppRules(projectID uuid.UUID) inAppRules {
+	return gcs.cached(projectID).inApp
+}
+
+func (gcs *GroupingConfigService) cached(projectID uuid.UUID) *cachedGroupingConfig {
+	now := time.Now()
+
+	gcs.mu.Lock()
+	cached, ok := gcs.cache[projectID]
+	gcs.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached
+	}
+
+	stored, version, err := gcs.load(projectID)
+	if err != nil && !errors.Is(err, ErrProjectNotFound) {
+		log.Printf("Failed to load grouping config of project %s: %v", projectID, err)
+		config := storedGroupingConfig{}.resolve()
+		config.Version = 1 // Projects that never changed their grouping use the default
+		return &cachedGroupingConfig{config: config, fingerprints: NewFingerprintService().WithGrouping(config)}
+	}
+	cached = &cachedGroupingConfig{config: stored.resolve(), expires: now.Add(groupingConfigCacheTTL)}
+	cached.config.Version = version
+	cached.inApp = compileInAppRules(cached.config)
+	cached.fingerprints = NewFingerprintService().WithGrouping(cached.config)
+
+	gcs.mu.Lock()
+	if len(gcs.cache) >= projectAccessCacheSweepSize {
+		for id, entry := range gcs.cache {
+			if now.After(entry.expires) {
+				delete(gcs.cache, id)
+			}
+		}
+	}
+	gcs.cache[projectID] = cached
+	gcs.mu.Unlock()
+	return cached
+}
@@ -0,0 +1,461 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrRegroupInvalid     = errors.New("invalid regroup request")
+	ErrRegroupInProgress  = errors.New("a regroup job is already pending or running for the project")
+	ErrRegroupJobNotFound = errors.New("regroup job not found")
+)
+
+const (
+	// DefaultRegroupDays and MaxRegroupDays bound how far back events are regrouped
+	DefaultRegroupDays = 7
+	MaxRegroupDays     = 90
+
+	// regroupBatchSize bounds the events regrouped per transaction
+	regroupBatchSize = 500
+
+	// regroupLease is how long a running job is reserved for the server running it. A job
+	// whose server stopped midway is resumed by another once its lease runs out.
+	regroupLease = 2 * time.Minute
+)
+
+// RegroupService recomputes the fingerprints of a project's recent events with its current
+// grouping config, moving the events whose fingerprint changed into the issues they group
+// into now. Jobs are requested by project admins and run in the background, one per project
+// at a time. Events sent with their own fingerprint keep their issue.
+type RegroupService struct {
+	db              *database.DB
+	errorService    *ErrorService
+	groupingConfigs *GroupingConfigService
+}
+
+// NewRegroupService creates a new regroup service creating issues the way ingestion does
+func NewRegroupService(db *database.DB, errorService *ErrorService, groupingConfigs *GroupingConfigService) *RegroupService {
+	return &RegroupService{
+		db:              db,
+		errorService:    errorService,
+		groupingConfigs: groupingConfigs,
+	}
+}
+
+// StartRegroup requests the regrouping of the events a project received in the last days
+func (rs *RegroupService) StartRegroup(userID, projectID uuid.UUID, request dto.RegroupRequest) (*dto.RegroupJobResponse, error) {
+	days := request.Days
+	switch {
+	case days == 0:
+		days = DefaultRegroupDays
+	case days < 0 || days > MaxRegroupDays:
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", ErrRegroupInvalid, MaxRegroupDays)
+	}
+
+	_, version, err := rs.groupingConfigs.load(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	job := models.GroupingRegroupJob{
+		ProjectID:       projectID,
+		RequestedByID:   &userID,
+		Status:          models.RegroupJobPending,
+		GroupingVersion: version,
+		Since:           time.Now().AddDate(0, 0, -days),
+	}
+	err = rs.db.Transaction(func(tx *gorm.DB) error {
+		var active int64
+		if err := tx.Model(&models.GroupingRegroupJob{}).
+			Where("project_id = ? AND status IN ?", projectID, []string{models.RegroupJobPending, models.RegroupJobRunning}).
+			Count(&active).Error; err != nil {
+			return fmt.Errorf("failed to get regroup jobs: %w", err)
+		}
+		if active > 0 {
+			return ErrRegroupInProgress
+		}
+		if err := tx.Create(&job).Error; err != nil {
+			return fmt.Errorf("failed to create regroup job: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return regroupJobResponse(&job), nil
+}
+
+// GetLatestRegroupJob returns the progress of the project's most recently requested regroup job
+func (rs *RegroupService) GetLatestRegroupJob(projectID uuid.UUID) (*dto.RegroupJobResponse, error) {
+	var job models.GroupingRegroupJob
+	if err := rs.db.Where("project_id = ?", projectID).Order("created_at DESC").First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRegroupJobNotFound
+		}
+		return nil, fmt.Errorf("failed to get regroup job: %w", err)
+	}
+	return regroupJobResponse(&job), nil
+}
+
+// RunPending runs the requested regroup jobs, and resumes those whose server stopped, until
+// none are left or the context is cancelled
+func (rs *RegroupService) RunPending(ctx context.Context) error {
+	for ctx.Err() == nil {
+		job, err := rs.claim()
+		if err != nil {
+			return err
+		}
+		if job == nil {
+			return nil
+		}
+		rs.run(ctx, job)
+	}
+	return nil
+}
+
+// claim reserves the oldest job that is pending or whose lease ran out, nil when there is none.
+// The reservation is a conditional update, so a job another server claimed first is skipped.
+func (rs *RegroupService) claim() (*models.GroupingRegroupJob, error) {
+	now := time.Now()
+	stale := now.Add(-regroupLease)
+
+	var candidates []models.GroupingRegroupJob
+	if err := rs.db.Where("status = ? OR (status = ? AND updated_at < ?)", models.RegroupJobPending, models.RegroupJobRunning, stale).
+		Order("created_at ASC").
+		Limit(10).
+		Find(&candidates).Error; err != nil {
+		return nil, fmt.Errorf("failed to load regroup jobs: %w", err)
+	}
+
+	for i := range candidates {
+		job := &candidates[i]
+		result := rs.db.Model(&models.GroupingRegroupJob{}).
+			Where("id = ? AND status = ? AND updated_at = ?", job.ID, job.Status, job.UpdatedAt).
+			Updates(map[string]interface{}{"status": models.RegroupJobRunning, "updated_at": now})
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to claim regroup job: %w", result.Error)
+		}
+		if result.RowsAffected == 1 {
+			if job.StartedAt == nil {
+				if err := rs.db.Model(job).Update("started_at", now).Error; err != nil {
+					return nil, fmt.Errorf("failed to claim regroup job: %w", err)
+				}
+				job.StartedAt = &now
+			}
+			job.Status = models.RegroupJobRunning
+			return job, nil
+		}
+	}
+	return nil, nil
+}
+
+// run regroups the job's events a batch at a time and records how the job ended. A job
+// interrupted by the context stays running and is resumed once its lease runs out.
+func (rs *RegroupService) run(ctx context.Context, job *models.GroupingRegroupJob) {
+	stored, version, err := rs.groupingConfigs.load(job.ProjectID)
+	if err != nil {
+		rs.finish(job, err)
+		return
+	}
+	config := stored.resolve()
+	config.Version = version
+	if err := rs.db.Model(job).Update("grouping_version", version).Error; err != nil {
+		rs.finish(job, fmt.Errorf("failed to update regroup job: %w", err))
+		return
+	}
+
+	for ctx.Err() == nil {
+		query := rs.db.Where("project_id = ? AND timestamp >= ? AND custom_fingerprint = ? AND grouping_version <> ?",
+			job.ProjectID, job.Since, false, config.Version)
+		if job.LastEventID != nil {
+			query = query.Where("id > ?", *job.LastEventID)
+		}
+		var events []models.Event
+		if err := query.Order("id ASC").Limit(regroupBatchSize).Find(&events).Error; err != nil {
+			rs.finish(job, fmt.Errorf("failed to get events: %w", err))
+			return
+		}
+		if len(events) > 0 {
+			if err := rs.regroupBatch(job, events, config); err != nil {
+				rs.finish(job, err)
+				return
+			}
+		}
+		if len(events) < regroupBatchSize {
+			rs.finish(job, nil)
+			return
+		}
+	}
+}
+
+// regroupSpan is how many events an issue gains or loses and the span of their timestamps
+type regroupSpan struct {
+	count       int
+	first, last time.Time
+}
+
+func (s *regroupSpan) add(timestamp time.Time) {
+	if s.count == 0 || timestamp.Before(s.first) {
+		s.first = timestamp
+	}
+	if s.count == 0 || timestamp.After(s.last) {
+		s.last = timestamp
+	}
+	s.count++
+}
+
+// regroupBatch fingerprints a batch of events with the config and moves those whose issue
+// changed, updating the counters of the issues involved and the progress of the job
+func (rs *RegroupService) regroupBatch(job *models.GroupingRegroupJob, events []models.Event, config dto.GroupingConfig) error {
+	fingerprints := rs.errorService.fingerprintService.WithGrouping(config)
+
+	return rs.db.Transaction(func(tx *gorm.DB) error {
+		store := NewGormEventStore(tx)
+		targets := make(map[string]*models.Issue)
+		created := make(map[uuid.UUID]bool)
+		gained := make(map[uuid.UUID]*regroupSpan)
+		lost := make(map[uuid.UUID]*regroupSpan)
+		moves := make(map[string][]uuid.UUID) // event IDs by their new fingerprint
+		var kept []uuid.UUID
+
+		for i := range events {
+			event := &events[i]
+			data := storedEventData(event)
+			data.Fingerprint = fingerprints.GroupingFingerprint(data)
+			data.GroupingVersion = config.Version
+
+			issue, ok := targets[data.Fingerprint]
+			if !ok {
+				found, err := store.FindIssueByFingerprint(job.ProjectID, data.Fingerprint)
+				switch {
+				case err == nil:
+					issue = found
+				case errors.Is(err, ErrIssueNotFound):
+					var isNew bool
+					if issue, isNew, err = rs.createIssue(tx, store, job.ProjectID, data); err != nil {
+						return err
+					}
+					created[issue.ID] = isNew
+				default:
+					return err
+				}
+				targets[data.Fingerprint] = issue
+			}
+
+			if issue.ID == event.IssueID {
+				kept = append(kept, event.ID)
+				continue
+			}
+			moves[data.Fingerprint] = append(moves[data.Fingerprint], event.ID)
+			if gained[issue.ID] == nil {
+				gained[issue.ID] = &regroupSpan{}
+			}
+			gained[issue.ID].add(event.Timestamp)
+			if lost[event.IssueID] == nil {
+				lost[event.IssueID] = &regroupSpan{}
+			}
+			lost[event.IssueID].add(event.Timestamp)
+		}
+
+		if len(kept) > 0 {
+			if err := tx.Model(&models.Event{}).Where("id IN ?", kept).
+				Update("grouping_version", config.Version).Error; err != nil {
+				return fmt.Errorf("failed to update events: %w", err)
+			}
+		}
+		moved := 0
+		for fingerprint, eventIDs := range moves {
+			if err := tx.Model(&models.Event{}).Where("id IN ?", eventIDs).Updates(map[string]interface{}{
+				"issue_id":         targets[fingerprint].ID,
+				"fingerprint":      fingerprint,
+				"grouping_version": config.Version,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to move events: %w", err)
+			}
+			moved += len(eventIDs)
+		}
+		if moved > 0 {
+			// The tags of the events follow them
+			affected := make([]uuid.UUID, 0, len(lost)+len(gained))
+			for issueID := range lost {
+				affected = append(affected, issueID)
+			}
+			for issueID := range gained {
+				affected = append(affected, issueID)
+			}
+			if err := reindexIssueTags(tx, affected); err != nil {
+				return err
+			}
+		}
+
+		for issueID, span := range lost {
+			if err := regroupSourceIssue(tx, issueID, span); err != nil {
+				return err
+			}
+		}
+		for _, issue := range targets {
+			updates := map[string]interface{}{"grouping_version": config.Version}
+			if span, ok := gained[issue.ID]; ok {
+				switch {
+				case created[issue.ID]:
+					updates["times_seen"] = span.count
+					updates["first_seen"] = span.first
+					updates["last_seen"] = span.last
+				default:
+					updates["times_seen"] = gorm.Expr("times_seen + ?", span.count)
+					if span.first.Before(issue.FirstSeen) {
+						updates["first_seen"] = span.first
+					}
+					if span.last.After(issue.LastSeen) {
+						updates["last_seen"] = span.last
+					}
+				}
+			}
+			if err := tx.Model(&models.Issue{}).Where("id = ?", issue.ID).Updates(updates).Error; err != nil {
+				return fmt.Errorf("failed to update issue: %w", err)
+			}
+		}
+
+		issuesCreated := 0
+		for _, isNew := range created {
+			if isNew {
+				issuesCreated++
+			}
+		}
+		lastEventID := events[len(events)-1].ID
+		if err := tx.Model(&models.GroupingRegroupJob{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+			"last_event_id":  lastEventID,
+			"events_checked": gorm.Expr("events_checked + ?", len(events)),
+			"events_moved":   gorm.Expr("events_moved + ?", moved),
+			"issues_created": gorm.Expr("issues_created + ?", issuesCreated),
+			"updated_at":     time.Now(),
+		}).Error; err != nil {
+			return fmt.Errorf("failed to update regroup job: %w", err)
+		}
+		job.LastEventID = &lastEventID
+		return nil
+	})
+}
+
+// createIssue creates the issue an event now groups into, like ingestion does. An issue an
+// ingested event created with the same fingerprint in the meantime is used instead; the
+// returned flag tells whether the issue was created.
+func (rs *RegroupService) createIssue(tx *gorm.DB, store *GormEventStore, projectID uuid.UUID, data *dto.NormalizedErrorData) (*models.Issue, bool, error) {
+	issue := rs.errorService.newIssue(projectID, data)
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&issue)
+	if result.Error != nil {
+		return nil, false, fmt.Errorf("failed to create issue: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		existing, err := store.FindIssueByFingerprint(projectID, data.Fingerprint)
+		return existing, false, err
+	}
+	return &issue, true, nil
+}
+
+// regroupSourceIssue updates the counters of an issue that events were moved out of, the
+// way unmerging does
+func regroupSourceIssue(tx *gorm.DB, issueID uuid.UUID, span *regroupSpan) error {
+	var issue models.Issue
+	if err := tx.Where("id = ?", issueID).First(&issue).Error; err != nil {
+		return fmt.Errorf("failed to get issue: %w", err)
+	}
+	remaining, remainingFirst, remainingLast, err := eventSpan(tx.Model(&models.Event{}).Where("issue_id = ?", issueID))
+	if err != nil {
+		return err
+	}
+
+	// Events sampled out are counted but not stored, so the issue keeps those it had
+	issue.TimesSeen -= span.count
+	if issue.TimesSeen < int(remaining) {
+		issue.TimesSeen = int(remaining)
+	}
+	// first_seen and last_seen only change when the events moved set them
+	if remainingFirst != nil && !issue.FirstSeen.After(span.first) {
+		issue.FirstSeen = remainingFirst.Timestamp
+	}
+	if remainingLast != nil && !issue.LastSeen.Before(span.last) {
+		issue.LastSeen = remainingLast.Timestamp
+	}
+	if err := tx.Model(&issue).Updates(map[string]interface{}{
+		"times_seen": issue.TimesSeen,
+		"first_seen": issue.FirstSeen,
+		"last_seen":  issue.LastSeen,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to update issue: %w", err)
+	}
+	return nil
+}
+
+// finish records that a job completed, or failed with err
+func (rs *RegroupService) finish(job *models.GroupingRegroupJob, err error) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":      models.RegroupJobCompleted,
+		"finished_at": now,
+		"updated_at":  now,
+	}
+	if err != nil {
+		log.Printf("Regroup job %s of project %s failed: %v", job.ID, job.ProjectID, err)
+		updates["status"] = models.RegroupJobFailed
+		updates["error"] = err.Error()
+	}
+	if err := rs.db.Model(&models.GroupingRegroupJob{}).Where("id = ?", job.ID).Updates(updates).Error; err != nil {
+		log.Printf("Failed to record the end of regroup job %s: %v", job.ID, err)
+	}
+}
+
+// storedEventData rebuilds what fingerprinting and issue titling read of a stored event
+func storedEventData(event *models.Event) *dto.NormalizedErrorData {
+	data := &dto.NormalizedErrorData{
+		EventID:        event.EventID,
+		ProjectID:      event.ProjectID,
+		Timestamp:      event.Timestamp,
+		Level:          string(event.Level),
+		Message:        event.Message,
+		ExceptionType:  event.ExceptionType,
+		ExceptionValue: event.ExceptionValue,
+		Environment:    event.Environment,
+		Release:        event.ReleaseVersion,
+		Transaction:    event.Transaction,
+		Platform:       event.Platform,
+	}
+	if len(event.StackTrace) > 0 {
+		json.Unmarshal(event.StackTrace, &data.StackTrace)
+	}
+	if len(event.Exceptions) > 0 {
+		json.Unmarshal(event.Exceptions, &data.Exceptions)
+	}
+	return data
+}
+
+func regroupJobResponse(job *models.GroupingRegroupJob) *dto.RegroupJobResponse {
+	return &dto.RegroupJobResponse{
+		ID:              job.ID,
+		ProjectID:       job.ProjectID,
+		RequestedByID:   job.RequestedByID,
+		Status:          job.Status,
+		GroupingVersion: job.GroupingVersion,
+		Since:           job.Since,
+		EventsChecked:   job.EventsChecked,
+		EventsMoved:     job.EventsMoved,
+		IssuesCreated:   job.IssuesCreated,
+		Error:           job.Error,
+		CreatedAt:       job.CreatedAt,
+		StartedAt:       job.StartedAt,
+		FinishedAt:      job.FinishedAt,
+	}
+}
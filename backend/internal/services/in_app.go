@@ -0,0 +1,153 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"minisentry/internal/dto"
+)
+
+const (
+	maxInAppPatterns      = 50
+	maxInAppPatternLength = 200
+)
+
+// inAppRules mark the frames of events as in-app, or not, by their path when the SDK did
+// not say. Exclusions take precedence over inclusions.
+type inAppRules struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// compileInAppRules compiles the in-app patterns of a grouping config, which were validated
+// when saved
+func compileInAppRules(config dto.GroupingConfig) inAppRules {
+	var rules inAppRules
+	for _, pattern := range config.InAppInclude {
+		if re, err := compileInAppPattern(pattern); err == nil {
+			rules.include = append(rules.include, re)
+		}
+	}
+	for _, pattern := range config.InAppExclude {
+		if re, err := compileInAppPattern(pattern); err == nil {
+			rules.exclude = append(rules.exclude, re)
+		}
+	}
+	return rules
+}
+
+// compileInAppPattern compiles a path pattern where ** matches any part of a path, * any
+// part of a directory or file name and ? one character. A pattern matches a path it is a
+// prefix of, up to a slash, so /src matches /src/app.js. Patterns starting with a slash
+// match from the start of the path, others from any of its directories.
+func compileInAppPattern(pattern string) (*regexp.Regexp, error) {
+	var expr strings.Builder
+	if strings.HasPrefix(pattern, "/") {
+		expr.WriteString(`^`)
+	} else {
+		expr.WriteString(`(?:^|/)`)
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(`.*`)
+			i++
+		case pattern[i] == '*':
+			expr.WriteString(`[^/]*`)
+		case pattern[i] == '?':
+			expr.WriteString(`[^/]`)
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	if !strings.HasSuffix(pattern, "/") {
+		expr.WriteString(`(?:/|$)`)
+	}
+	return regexp.Compile(expr.String())
+}
+
+// normalizeInAppPatterns trims the patterns of an in-app list, drops empty and repeated ones
+// and checks their count and length
+func normalizeInAppPatterns(name string, patterns []string) ([]string, error) {
+	normalized := make([]string, 0, len(patterns))
+	seen := make(map[string]bool, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" || seen[pattern] {
+			continue
+		}
+		if len(pattern) > maxInAppPatternLength {
+			return nil, fmt.Errorf("%w: %s patterns must be at most %d characters", ErrGroupingConfigInvalid, name, maxInAppPatternLength)
+		}
+		if _, err := compileInAppPattern(pattern); err != nil {
+			return nil, fmt.Errorf("%w: invalid %s pattern %s", ErrGroupingConfigInvalid, name, pattern)
+		}
+		seen[pattern] = true
+		normalized = append(normalized, pattern)
+	}
+	if len(normalized) > maxInAppPatterns {
+		return nil, fmt.Errorf("%w: %s cannot have more than %d patterns", ErrGroupingConfigInvalid, name, maxInAppPatterns)
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return normalized, nil
+}
+
+// applyInAppRules marks the frames of an event the SDK left undecided as in-app, or not, by
+// the in-app rules of its project, so culprits and fingerprints skip library frames
+func (es *ErrorService) applyInAppRules(normalizedData *dto.NormalizedErrorData) {
+	if es.groupingConfigs == nil {
+		return
+	}
+	rules := es.groupingConfigs.projectInAppRules(normalizedData.ProjectID)
+	if len(rules.include) == 0 && len(rules.exclude) == 0 {
+		return
+	}
+
+	rules.apply(normalizedData.StackTrace)
+	for _, exception := range normalizedData.Exceptions {
+		if exception.Stacktrace != nil {
+			rules.apply(exception.Stacktrace.Frames)
+		}
+	}
+}
+
+// apply marks the frames without in_app that a rule matches
+func (r inAppRules) apply(frames []dto.StackFrame) {
+	for i := range frames {
+		if frames[i].InApp != nil {
+			continue
+		}
+		if inApp, ok := r.match(frames[i]); ok {
+			frames[i].InApp = &inApp
+		}
+	}
+}
+
+// match tells whether a frame is in-app by the rules matching its absolute path or file
+// name; false when none does
+func (r inAppRules) match(frame dto.StackFrame) (inApp bool, matched bool) {
+	var paths []string
+	for _, path := range []*string{frame.AbsPath, frame.Filename} {
+		if path != nil && *path != "" {
+			paths = append(paths, *path)
+		}
+	}
+	for _, path := range paths {
+		for _, re := range r.exclude {
+			if re.MatchString(path) {
+				return false, true
+			}
+		}
+	}
+	for _, path := range paths {
+		for _, re := range r.include {
+			if re.MatchString(path) {
+				return true, true
+			}
+		}
+	}
+	return false, false
+}
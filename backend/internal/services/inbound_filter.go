@@ -0,0 +1,655 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInboundFiltersInvalid = errors.New("invalid inbound filters")
+
+// inboundFilterIPAddress is reported by filter tests for events from addresses the
+// project's IP lists reject
+const inboundFilterIPAddress = "ip-address"
+
+const (
+	// inboundFiltersCacheTTL bounds how long ingestion uses a project's filters before
+	// reloading them, so changes made through another server apply within this time
+	inboundFiltersCacheTTL = 30 * time.Second
+
+	// defaultInboundFilterStatsWindow is how far back filter stats look when no since is given
+	defaultInboundFilterStatsWindow = 30 * 24 * time.Hour
+
+	// maxInboundFilterPatterns bounds each of the pattern lists of a project
+	maxInboundFilterPatterns = 100
+
+	// maxInboundFilterPatternLength bounds the length of a pattern
+	maxInboundFilterPatternLength = 500
+)
+
+var (
+	// browserExtensionSchemes are the URL schemes of scripts injected by browser extensions
+	browserExtensionSchemes = []string{
+		"chrome-extension://", "moz-extension://", "safari-extension://", "safari-web-extension://",
+		"ms-browser-extension://", "chrome://", "resource://", "webkit-masked-url://",
+	}
+
+	// browserExtensionErrors matches errors that extensions and injected toolbars are known to cause
+	browserExtensionErrors = regexp.MustCompile(`(?i)top\.GLOBALS|originalCreateNotification|canvas\.contentDocument|` +
+		`MyApp_RemoveAllHighlights|http://tt\.epicplay\.com|Can't find variable: ZiteReader|jigsaw is not defined|` +
+		`ComboSearch is not defined|http://loading\.retry\.widdit\.com/|atomicFindClose|fb_xd_fragment|` +
+		`bmi_SafeAddOnload|EBCallBackMessageReceived|conduitPage|__gCrWeb|instantSearchSDKJSBridgeClearHighlight`)
+
+	// webCrawlerUserAgents matches the user agents of search engines, link previews and uptime checkers
+	webCrawlerUserAgents = regexp.MustCompile(`(?i)bot\b|bot/|crawler|spider|slurp|googlebot|bingbot|` +
+		`baiduspider|yandex|facebookexternalhit|facebot|ia_archiver|mediapartners-google|adsbot-google|` +
+		`applebot|duckduckbot|petalbot|ahrefs|semrush|pingdom|uptimerobot|statuscake|lighthouse|headlesschrome`)
+
+	// legacyBrowserVersions are the last versions of each browser considered legacy: those
+	// without ES2017 support. Internet Explorer and Opera Presto are always legacy.
+	legacyBrowserVersions = []struct {
+		pattern *regexp.Regexp
+		last    int
+	}{
+		{regexp.MustCompile(`Edge/(\d+)`), 18},
+		{regexp.MustCompile(`(?:Chrome|CriOS)/(\d+)`), 57},
+		{regexp.MustCompile(`(?:Firefox|FxiOS)/(\d+)`), 51},
+		{regexp.MustCompile(`Version/(\d+)[.\d]* (?:Mobile/\w+ )?Safari/`), 10},
+		{regexp.MustCompile(`Android (\d+)[.\d]*;.*Version/[.\d]+ (?:Mobile )?Safari/`), 4},
+	}
+	internetExplorer = regexp.MustCompile(`MSIE |Trident/|Presto/`)
+)
+
+// compiledInboundFilters are a project's filters ready to be matched against events
+type compiledInboundFilters struct {
+	settings       models.ProjectInboundFilters
+	errorMessages  []inboundFilterPattern
+	exceptionTypes []inboundFilterPattern
+	expires        time.Time
+}
+
+// inboundFilterPattern is a compiled glob or /regex/ pattern and the pattern as written
+type inboundFilterPattern struct {
+	source string
+	re     *regexp.Regexp
+}
+
+type inboundFilterStatKey struct {
+	projectID uuid.UUID
+	bucket    time.Time
+	reason    string
+}
+
+// InboundFilterService discards error events matching a project's inbound filters before
+// they are grouped into issues. Filters are cached briefly per project; the counts of
+// discarded events are buffered and written to the database periodically.
+type InboundFilterService struct {
+	db *database.DB
+
+	mu      sync.Mutex
+	cache   map[uuid.UUID]*compiledInboundFilters
+	pending map[inboundFilterStatKey]int64 // filtered events not yet written
+}
+
+// NewInboundFilterService creates a new inbound filter service
+func NewInboundFilterService(db *database.DB) *InboundFilterService {
+	return &InboundFilterService{
+		db:      db,
+		cache:   make(map[uuid.UUID]*compiledInboundFilters),
+		pending: make(map[inboundFilterStatKey]int64),
+	}
+}
+
+// SetInboundFilters makes ingestion discard events matching their project's inbound filters
+func (es *ErrorService) SetInboundFilters(inboundFilters *InboundFilterService) {
+	es.inboundFilters = inboundFilters
+}
+
+// isFiltered reports whether the project's inbound filters discard the event
+func (es *ErrorService) isFiltered(projectID uuid.UUID, normalizedData *dto.NormalizedErrorData, userAgent, clientIP string) bool {
+	return es.inboundFilters != nil && es.inboundFilters.Filter(projectID, normalizedData, userAgent, clientIP) != ""
+}
+
+// Filter returns the reason the project's filters discard an event, or "" to keep it.
+// Discarded events are counted. userAgent and clientIP are those of the ingestion request,
+// used when the event does not carry its own.
+func (ifs *InboundFilterService) Filter(projectID uuid.UUID, data *dto.NormalizedErrorData, userAgent, clientIP string) string {
+	filters, err := ifs.getCompiled(projectID)
+	if err != nil {
+		// Keep events rather than lose them while the filters cannot be loaded
+		return ""
+	}
+
+	reason := filters.match(data, userAgent, clientIP)
+	if reason != "" {
+		key := inboundFilterStatKey{projectID: projectID, bucket: time.Now().UTC().Truncate(time.Hour), reason: reason}
+		ifs.mu.Lock()
+		ifs.pending[key]++
+		ifs.mu.Unlock()
+	}
+	return reason
+}
+
+// GetInboundFilters returns a project's inbound filters and the events each discarded
+// since the given time
+func (ifs *InboundFilterService) GetInboundFilters(projectID uuid.UUID, since time.Time) (*dto.InboundFiltersResponse, error) {
+	settings, err := ifs.load(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if since.IsZero() {
+		since = time.Now().Add(-defaultInboundFilterStatsWindow)
+	}
+	since = since.UTC().Truncate(time.Hour)
+
+	var rows []models.InboundFilterStat
+	if err := ifs.db.Where("project_id = ? AND bucket >= ?", projectID, since).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve inbound filter stats: %w", err)
+	}
+
+	filtered := make(map[string]int64)
+	for _, row := range rows {
+		filtered[row.Reason] += row.Filtered
+	}
+	ifs.mu.Lock()
+	for key, count := range ifs.pending {
+		if key.projectID == projectID && !key.bucket.Before(since) {
+			filtered[key.reason] += count
+		}
+	}
+	ifs.mu.Unlock()
+
+	response := convertInboundFiltersToResponse(projectID, settings)
+	response.Since = since
+	response.Stats = []dto.InboundFilterStats{}
+	for reason, count := range filtered {
+		response.Stats = append(response.Stats, dto.InboundFilterStats{Reason: reason, Filtered: count})
+		response.TotalFiltered += count
+	}
+	sort.Slice(response.Stats, func(i, j int) bool {
+		return response.Stats[i].Reason < response.Stats[j].Reason
+	})
+	return response, nil
+}
+
+// UpdateInboundFilters changes a project's inbound filters, recording each changed filter
+// in the project's setting history
+func (ifs *InboundFilterService) UpdateInboundFilters(userID, projectID uuid.UUID, request dto.InboundFiltersRequest) (*dto.InboundFiltersResponse, error) {
+	var project models.Project
+	if err := ifs.db.Where("id = ?", projectID).First(&project).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	settings, err := ifs.load(projectID)
+	if err != nil {
+		return nil, err
+	}
+	var diff settingDiff
+	if err := applyInboundFiltersRequest(settings, request, &diff); err != nil {
+		return nil, err
+	}
+
+	settings.ProjectID = projectID
+	err = ifs.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(settings).Error; err != nil {
+			return err
+		}
+		return recordSettingChanges(tx, userID, models.AuditProjectSettingChanged, settingTargetProject, projectID, project.OrganizationID, &projectID, diff)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update inbound filters: %w", err)
+	}
+
+	ifs.mu.Lock()
+	delete(ifs.cache, projectID)
+	ifs.mu.Unlock()
+
+	return ifs.GetInboundFilters(projectID, time.Time{})
+}
+
+// applyInboundFiltersRequest changes the filters set in a request, recording each changed
+// filter in diff
+func applyInboundFiltersRequest(settings *models.ProjectInboundFilters, request dto.InboundFiltersRequest, diff *settingDiff) error {
+	if request.BrowserExtensions != nil {
+		diff.add("inbound_filters.browser_extensions", settings.BrowserExtensions, *request.BrowserExtensions)
+		settings.BrowserExtensions = *request.BrowserExtensions
+	}
+	if request.WebCrawlers != nil {
+		diff.add("inbound_filters.web_crawlers", settings.WebCrawlers, *request.WebCrawlers)
+		settings.WebCrawlers = *request.WebCrawlers
+	}
+	if request.Localhost != nil {
+		diff.add("inbound_filters.localhost", settings.Localhost, *request.Localhost)
+		settings.Localhost = *request.Localhost
+	}
+	if request.LegacyBrowsers != nil {
+		diff.add("inbound_filters.legacy_browsers", settings.LegacyBrowsers, *request.LegacyBrowsers)
+		settings.LegacyBrowsers = *request.LegacyBrowsers
+	}
+	if request.ErrorMessages != nil {
+		if err := updateInboundFilterPatterns(diff, "inbound_filters.error_messages", &settings.ErrorMessages, *request.ErrorMessages); err != nil {
+			return err
+		}
+	}
+	if request.ExceptionTypes != nil {
+		if err := updateInboundFilterPatterns(diff, "inbound_filters.exception_types", &settings.ExceptionTypes, *request.ExceptionTypes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetErrorBlocklist returns the message and exception type patterns of a project's inbound filters
+func (ifs *InboundFilterService) GetErrorBlocklist(projectID uuid.UUID) (*dto.ErrorBlocklistResponse, error) {
+	settings, err := ifs.load(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return convertErrorBlocklistToResponse(projectID, settings), nil
+}
+
+// UpdateErrorBlocklist replaces the message or exception type patterns of a project's
+// inbound filters, leaving its other filters unchanged
+func (ifs *InboundFilterService) UpdateErrorBlocklist(userID, projectID uuid.UUID, request dto.ErrorBlocklistRequest) (*dto.ErrorBlocklistResponse, error) {
+	response, err := ifs.UpdateInboundFilters(userID, projectID, dto.InboundFiltersRequest{
+		ErrorMessages:  request.Messages,
+		ExceptionTypes: request.ExceptionTypes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ErrorBlocklistResponse{
+		ProjectID:      projectID,
+		Messages:       response.ErrorMessages,
+		ExceptionTypes: response.ExceptionTypes,
+	}, nil
+}
+
+// TestFilters reports whether a project's inbound filters and IP lists would discard an
+// event, and every filter matching it, without counting it. Filters set in the request are
+// tested instead of the saved ones, which are left unchanged.
+func (ifs *InboundFilterService) TestFilters(projectID uuid.UUID, data *dto.NormalizedErrorData, request dto.InboundFilterTestRequest) (*dto.InboundFilterTestResponse, error) {
+	var project models.Project
+	if err := ifs.db.Where("id = ?", projectID).First(&project).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	settings, err := ifs.load(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Filters != nil {
+		var diff settingDiff
+		if err := applyInboundFiltersRequest(settings, *request.Filters, &diff); err != nil {
+			return nil, err
+		}
+	}
+
+	response := &dto.InboundFilterTestResponse{Matches: []dto.InboundFilterMatch{}}
+	// IP lists are enforced on authentication, before the filters
+	if request.ClientIP != "" && !IsIPAllowed(&project, request.ClientIP) {
+		response.Matches = append(response.Matches, dto.InboundFilterMatch{
+			Filter: inboundFilterIPAddress,
+			Detail: "client IP address " + request.ClientIP + " is not allowed by the project's IP lists",
+		})
+	}
+	filters := compileInboundFilters(settings, time.Time{})
+	response.Matches = append(response.Matches, filters.matches(data, request.UserAgent, request.ClientIP, true)...)
+
+	if len(response.Matches) > 0 {
+		response.Dropped = true
+		response.Reason = response.Matches[0].Filter
+	}
+	return response, nil
+}
+
+// FlushStats adds the buffered filtered event counts to the hourly totals in the database.
+// It is run periodically by the scheduler and on shutdown.
+func (ifs *InboundFilterService) FlushStats(ctx context.Context) error {
+	pending := takeCounts(&ifs.mu, &ifs.pending)
+	err := flushCounts(&ifs.mu, &ifs.pending, pending, func(key inboundFilterStatKey, filtered int64) error {
+		row := models.InboundFilterStat{ProjectID: key.projectID, Bucket: key.bucket, Reason: key.reason, Filtered: filtered}
+		return ifs.db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "project_id"}, {Name: "bucket"}, {Name: "reason"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"filtered":   clause.Expr{SQL: "inbound_filter_stats.filtered + excluded.filtered"},
+				"updated_at": time.Now(),
+			}),
+		}).Create(&row).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record inbound filter stats: %w", err)
+	}
+	return nil
+}
+
+// load returns a project's filters, all disabled when it has none yet
+func (ifs *InboundFilterService) load(projectID uuid.UUID) (*models.ProjectInboundFilters, error) {
+	var settings models.ProjectInboundFilters
+	if err := ifs.db.Where("project_id = ?", projectID).First(&settings).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &models.ProjectInboundFilters{ProjectID: projectID}, nil
+		}
+		return nil, fmt.Errorf("failed to get inbound filters: %w", err)
+	}
+	return &settings, nil
+}
+
+// getCompiled returns a project's filters from the cache, loading them when missing or expired
+func (ifs *InboundFilterService) getCompiled(projectID uuid.UUID) (*compiledInboundFilters, error) {
+	now := time.Now()
+
+	ifs.mu.Lock()
+	filters, ok := ifs.cache[projectID]
+	ifs.mu.Unlock()
+	if ok && now.Before(filters.expires) {
+		return filters, nil
+	}
+
+	settings, err := ifs.load(projectID)
+	if err != nil {
+		return nil, err
+	}
+	filters = compileInboundFilters(settings, now.Add(inboundFiltersCacheTTL))
+
+	ifs.mu.Lock()
+	if len(ifs.cache) >= projectAccessCacheSweepSize {
+		for id, cached := range ifs.cache {
+			if now.After(cached.expires) {
+				delete(ifs.cache, id)
+			}
+		}
+	}
+	ifs.cache[projectID] = filters
+	ifs.mu.Unlock()
+	return filters, nil
+}
+
+// compileInboundFilters prepares a project's filters for matching
+func compileInboundFilters(settings *models.ProjectInboundFilters, expires time.Time) *compiledInboundFilters {
+	return &compiledInboundFilters{
+		settings:       *settings,
+		errorMessages:  compileInboundFilterPatterns(inboundFilterPatterns(settings.ErrorMessages)),
+		exceptionTypes: compileInboundFilterPatterns(inboundFilterPatterns(settings.ExceptionTypes)),
+		expires:        expires,
+	}
+}
+
+// match returns the first filter discarding the event, or ""
+func (f *compiledInboundFilters) match(data *dto.NormalizedErrorData, userAgent, clientIP string) string {
+	if matches := f.matches(data, userAgent, clientIP, false); len(matches) > 0 {
+		return matches[0].Filter
+	}
+	return ""
+}
+
+// matches returns the filters discarding the event in the order they apply, stopping at the
+// first unless all is set
+func (f *compiledInboundFilters) matches(data *dto.NormalizedErrorData, userAgent, clientIP string, all bool) []dto.InboundFilterMatch {
+	settings := &f.settings
+	if eventUserAgent := requestHeader(data.RequestData, "User-Agent"); eventUserAgent != "" {
+		userAgent = eventUserAgent
+	}
+
+	var matches []dto.InboundFilterMatch
+	found := func(filter, detail string) bool {
+		matches = append(matches, dto.InboundFilterMatch{Filter: filter, Detail: detail})
+		return !all
+	}
+
+	if settings.Localhost && isLocalhostEvent(data, clientIP) &&
+		found(models.InboundFilterLocalhost, "the event was sent from or about localhost") {
+		return matches
+	}
+	if settings.WebCrawlers && userAgent != "" && webCrawlerUserAgents.MatchString(userAgent) &&
+		found(models.InboundFilterWebCrawlers, "user agent of a web crawler: "+userAgent) {
+		return matches
+	}
+	if settings.LegacyBrowsers && isLegacyBrowser(userAgent) &&
+		found(models.InboundFilterLegacyBrowsers, "user agent of a legacy browser: "+userAgent) {
+		return matches
+	}
+	if settings.BrowserExtensions && isBrowserExtensionError(data) &&
+		found(models.InboundFilterBrowserExtensions, "error caused by a browser extension") {
+		return matches
+	}
+	if data.ExceptionType != nil {
+		if pattern := matchingPattern(f.exceptionTypes, []string{*data.ExceptionType}); pattern != "" &&
+			found(models.InboundFilterExceptionTypes, "exception type matches "+pattern) {
+			return matches
+		}
+	}
+	if pattern := matchingPattern(f.errorMessages, eventMessages(data)); pattern != "" &&
+		found(models.InboundFilterErrorMessages, "message matches "+pattern) {
+		return matches
+	}
+	return matches
+}
+
+// eventMessages are the texts error message filters are matched against: the message, the
+// exception value and "Type: value"
+func eventMessages(data *dto.NormalizedErrorData) []string {
+	var messages []string
+	if data.Message != nil && *data.Message != "" {
+		messages = append(messages, *data.Message)
+	}
+	if data.ExceptionValue != nil && *data.ExceptionValue != "" {
+		messages = append(messages, *data.ExceptionValue)
+	}
+	if data.ExceptionType != nil && *data.ExceptionType != "" {
+		value := ""
+		if data.ExceptionValue != nil {
+			value = *data.ExceptionValue
+		}
+		messages = append(messages, *data.ExceptionType+": "+value)
+	}
+	return messages
+}
+
+// matchingPattern returns the first pattern matching one of the texts, or ""
+func matchingPattern(patterns []inboundFilterPattern, texts []string) string {
+	for _, pattern := range patterns {
+		for _, text := range texts {
+			if pattern.re.MatchString(text) {
+				return pattern.source
+			}
+		}
+	}
+	return ""
+}
+
+func isLocalhostEvent(data *dto.NormalizedErrorData, clientIP string) bool {
+	ips := []string{clientIP}
+	if data.UserContext != nil && data.UserContext.IPAddress != nil {
+		ips = append(ips, *data.UserContext.IPAddress)
+	}
+	for _, value := range ips {
+		if ip := net.ParseIP(strings.TrimSpace(value)); ip != nil && (ip.IsLoopback() || ip.IsUnspecified()) {
+			return true
+		}
+	}
+
+	if data.RequestData != nil && data.RequestData.URL != nil {
+		if parsed, err := url.Parse(*data.RequestData.URL); err == nil {
+			host := strings.ToLower(parsed.Hostname())
+			if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+				return true
+			}
+			if ip := net.ParseIP(host); ip != nil && (ip.IsLoopback() || ip.IsUnspecified()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isBrowserExtensionError(data *dto.NormalizedErrorData) bool {
+	for _, message := range eventMessages(data) {
+		if browserExtensionErrors.MatchString(message) {
+			return true
+		}
+	}
+	for _, frame := range data.StackTrace {
+		for _, path := range []*string{frame.AbsPath, frame.Filename} {
+			if path == nil {
+				continue
+			}
+			for _, scheme := range browserExtensionSchemes {
+				if strings.HasPrefix(*path, scheme) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func isLegacyBrowser(userAgent string) bool {
+	if userAgent == "" {
+		return false
+	}
+	if internetExplorer.MatchString(userAgent) {
+		return true
+	}
+	for _, browser := range legacyBrowserVersions {
+		match := browser.pattern.FindStringSubmatch(userAgent)
+		if match == nil {
+			continue
+		}
+		version, err := strconv.Atoi(match[1])
+		return err == nil && version <= browser.last
+	}
+	return false
+}
+
+// requestHeader returns a header of the event's request, matching the name case-insensitively
+func requestHeader(request *dto.RequestData, name string) string {
+	if request == nil {
+		return ""
+	}
+	for key, value := range request.Headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+// compileInboundFilterPattern compiles a case-insensitive pattern matching a whole text: a
+// regular expression when written between slashes (/^Script error\.?$/), otherwise a glob
+// where * matches any text and ? one character
+func compileInboundFilterPattern(pattern string) (*regexp.Regexp, error) {
+	if len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		return regexp.Compile(`(?is)` + pattern[1:len(pattern)-1])
+	}
+
+	quoted := regexp.QuoteMeta(pattern)
+	quoted = strings.ReplaceAll(quoted, `\*`, `.*`)
+	quoted = strings.ReplaceAll(quoted, `\?`, `.`)
+	return regexp.Compile(`(?is)^` + quoted + `$`)
+}
+
+// compileInboundFilterPatterns compiles stored patterns, which were validated when saved
+func compileInboundFilterPatterns(patterns []string) []inboundFilterPattern {
+	compiled := make([]inboundFilterPattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		if re, err := compileInboundFilterPattern(pattern); err == nil {
+			compiled = append(compiled, inboundFilterPattern{source: pattern, re: re})
+		}
+	}
+	return compiled
+}
+
+func inboundFilterPatterns(list datatypes.JSON) []string {
+	patterns := []string{}
+	if len(list) > 0 {
+		json.Unmarshal(list, &patterns)
+	}
+	return patterns
+}
+
+// updateInboundFilterPatterns replaces a stored pattern list, recording the change in diff
+func updateInboundFilterPatterns(diff *settingDiff, name string, list *datatypes.JSON, patterns []string) error {
+	normalized, err := normalizeInboundFilterPatterns(patterns)
+	if err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(normalized)
+	if err != nil {
+		return fmt.Errorf("failed to encode filter patterns: %w", err)
+	}
+
+	diff.add(name, inboundFilterPatterns(*list), normalized)
+	*list = datatypes.JSON(encoded)
+	return nil
+}
+
+// normalizeInboundFilterPatterns trims the patterns, drops empty and repeated ones and
+// checks that they compile
+func normalizeInboundFilterPatterns(patterns []string) ([]string, error) {
+	normalized := make([]string, 0, len(patterns))
+	seen := make(map[string]bool, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" || seen[pattern] {
+			continue
+		}
+		if len(pattern) > maxInboundFilterPatternLength {
+			return nil, fmt.Errorf("%w: patterns must be at most %d characters", ErrInboundFiltersInvalid, maxInboundFilterPatternLength)
+		}
+		if _, err := compileInboundFilterPattern(pattern); err != nil {
+			return nil, fmt.Errorf("%w: invalid regular expression %s", ErrInboundFiltersInvalid, pattern)
+		}
+		seen[pattern] = true
+		normalized = append(normalized, pattern)
+	}
+	if len(normalized) > maxInboundFilterPatterns {
+		return nil, fmt.Errorf("%w: at most %d patterns are allowed", ErrInboundFiltersInvalid, maxInboundFilterPatterns)
+	}
+	return normalized, nil
+}
+
+func convertInboundFiltersToResponse(projectID uuid.UUID, settings *models.ProjectInboundFilters) *dto.InboundFiltersResponse {
+	return &dto.InboundFiltersResponse{
+		ProjectID:         projectID,
+		BrowserExtensions: settings.BrowserExtensions,
+		WebCrawlers:       settings.WebCrawlers,
+		Localhost:         settings.Localhost,
+		LegacyBrowsers:    settings.LegacyBrowsers,
+		ErrorMessages:     inboundFilterPatterns(settings.ErrorMessages),
+		ExceptionTypes:    inboundFilterPatterns(settings.ExceptionTypes),
+	}
+}
+
+func convertErrorBlocklistToResponse(projectID uuid.UUID, settings *models.ProjectInboundFilters) *dto.ErrorBlocklistResponse {
+	return &dto.ErrorBlocklistResponse{
+		ProjectID:      projectID,
+		Messages:       inboundFilterPatterns(settings.ErrorMessages),
+		ExceptionTypes: inboundFilterPatterns(settings.ExceptionTypes),
+	}
+}
@@ -0,0 +1,665 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrIncidentNotFound       = errors.New("incident not found")
+	ErrIncidentAccessDenied   = errors.New("access denied to incident")
+	ErrIncidentInvalidStatus  = errors.New("invalid incident status")
+	ErrIncidentIssueNotFound  = errors.New("issue not found in organization")
+	ErrIncidentInvalidRequest = errors.New("invalid incident request")
+)
+
+// Incident event rate charts are capped so a long incident cannot load unbounded buckets
+const maxIncidentRatePoints = 1000
+
+var incidentRateIntervals = map[string]time.Duration{
+	"minute": time.Minute,
+	"hour":   time.Hour,
+	"day":    24 * time.Hour,
+}
+
+// AlertStormConfig configures automatic incident creation when many new issues appear at once
+type AlertStormConfig struct {
+	Threshold int           // New issues within Window that open an incident; 0 disables detection
+	Window    time.Duration // Sliding window new issues are counted in
+}
+
+type IncidentService struct {
+	db    *database.DB
+	storm AlertStormConfig
+
+	// stormMu serializes storm detection so concurrent ingestion opens one incident per storm
+	stormMu sync.Mutex
+
+	// changeListeners are notified when an incident opens (old status "") or changes status
+	changeListeners []IncidentChangeListener
+}
+
+// IncidentChangeListener is called inside the transaction that opens an incident (old status "")
+// or changes its status. Returning an error rolls the change back.
+type IncidentChangeListener func(tx *gorm.DB, incident *models.Incident, oldStatus models.IncidentStatus) error
+
+// NewIncidentService creates a new incident service
+func NewIncidentService(db *database.DB, storm AlertStormConfig) *IncidentService {
+	return &IncidentService{db: db, storm: storm}
+}
+
+// OnIncidentChange registers a listener for incidents opening or changing status.
+// Listeners must not be registered after the server has started.
+func (is *IncidentService) OnIncidentChange(listener IncidentChangeListener) {
+	is.changeListeners = append(is.changeListeners, listener)
+}
+
+func (is *IncidentService) notifyChange(tx *gorm.DB, incident *models.Incident, oldStatus models.IncidentStatus) error {
+	for _, listener := range is.changeListeners {
+		if err := listener(tx, incident, oldStatus); err != nil {
+			return fmt.Errorf("incident change listener failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// CreateIncident opens a manual incident, optionally grouping issues right away
+func (is *IncidentService) CreateIncident(userID, orgID uuid.UUID, request dto.CreateIncidentRequest) (*dto.IncidentResponse, error) {
+	title := strings.TrimSpace(request.Title)
+	if title == "" {
+		return nil, fmt.Errorf("%w: title is required", ErrIncidentInvalidRequest)
+	}
+
+	incident := models.Incident{
+		OrganizationID: orgID,
+		Title:          title,
+		Description:    request.Description,
+		Status:         models.IncidentOpen,
+		Source:         models.IncidentSourceManual,
+		StartedAt:      time.Now(),
+		CreatedByID:    &userID,
+	}
+	incident.ID = uuid.New()
+
+	err := is.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&incident).Error; err != nil {
+			return fmt.Errorf("failed to create incident: %w", err)
+		}
+		if err := is.createActivity(tx, incident.ID, &userID, models.IncidentActivityCreated, map[string]interface{}{
+			"source": incident.Source,
+		}); err != nil {
+			return err
+		}
+		if err := is.addIssues(tx, &incident, &userID, request.IssueIDs); err != nil {
+			return err
+		}
+		return is.notifyChange(tx, &incident, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return is.GetIncident(userID, incident.ID)
+}
+
+// ListIncidents lists the incidents of an organization, most recent first
+func (is *IncidentService) ListIncidents(orgID uuid.UUID, filters dto.IncidentFilters) (*dto.IncidentListResponse, error) {
+	if filters.Page < 1 {
+		filters.Page = 1
+	}
+	if filters.Limit < 1 || filters.Limit > 100 {
+		filters.Limit = 25
+	}
+
+	query := is.db.Model(&models.Incident{}).Where("organization_id = ?", orgID)
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count incidents: %w", err)
+	}
+
+	var incidents []models.Incident
+	if err := query.Order("started_at DESC").
+		Offset((filters.Page - 1) * filters.Limit).Limit(filters.Limit).
+		Find(&incidents).Error; err != nil {
+		return nil, fmt.Errorf("failed to list incidents: %w", err)
+	}
+
+	incidentIDs := make([]uuid.UUID, len(incidents))
+	for i, incident := range incidents {
+		incidentIDs[i] = incident.ID
+	}
+	issueCounts, err := is.countIssues(incidentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.IncidentResponse, len(incidents))
+	for i, incident := range incidents {
+		responses[i] = convertIncidentToResponse(incident)
+		responses[i].IssueCount = issueCounts[incident.ID]
+	}
+
+	return &dto.IncidentListResponse{
+		Incidents:  responses,
+		Total:      total,
+		Page:       filters.Page,
+		Limit:      filters.Limit,
+		TotalPages: dto.CalculateTotalPages(total, filters.Limit),
+	}, nil
+}
+
+// GetIncident returns an incident with its issues
+func (is *IncidentService) GetIncident(userID, incidentID uuid.UUID) (*dto.IncidentResponse, error) {
+	incident, err := is.getIncidentForUser(userID, incidentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []models.IncidentIssue
+	if err := is.db.Preload("Issue").Preload("Issue.Project").
+		Where("incident_id = ?", incident.ID).
+		Order("created_at ASC").
+		Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve incident issues: %w", err)
+	}
+
+	response := convertIncidentToResponse(*incident)
+	response.IssueCount = len(links)
+	response.Issues = make([]dto.IncidentIssueResponse, len(links))
+	for i, link := range links {
+		response.Issues[i] = dto.IncidentIssueResponse{
+			IssueID:     link.IssueID,
+			ProjectID:   link.Issue.ProjectID,
+			ProjectSlug: link.Issue.Project.Slug,
+			Title:       link.Issue.Title,
+			Status:      string(link.Issue.Status),
+			Level:       string(link.Issue.Level),
+			TimesSeen:   link.Issue.TimesSeen,
+			LastSeen:    link.Issue.LastSeen,
+			AddedAt:     link.CreatedAt,
+		}
+	}
+	return &response, nil
+}
+
+// UpdateIncident changes the title, description or status of an incident
+func (is *IncidentService) UpdateIncident(userID, incidentID uuid.UUID, request dto.UpdateIncidentRequest) (*dto.IncidentResponse, error) {
+	incident, err := is.getIncidentForUser(userID, incidentID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if request.Title != nil {
+		title := strings.TrimSpace(*request.Title)
+		if title == "" {
+			return nil, fmt.Errorf("%w: title cannot be empty", ErrIncidentInvalidRequest)
+		}
+		updates["title"] = title
+	}
+	if request.Description != nil {
+		updates["description"] = *request.Description
+	}
+
+	oldStatus := incident.Status
+	var newStatus models.IncidentStatus
+	if request.Status != nil && models.IncidentStatus(*request.Status) != incident.Status {
+		newStatus = models.IncidentStatus(*request.Status)
+		if !IsValidIncidentStatus(*request.Status) {
+			return nil, ErrIncidentInvalidStatus
+		}
+		updates["status"] = newStatus
+		if newStatus == models.IncidentResolved {
+			updates["resolved_at"] = time.Now()
+		} else {
+			updates["resolved_at"] = nil
+		}
+	}
+
+	if len(updates) == 0 {
+		return is.GetIncident(userID, incidentID)
+	}
+
+	err = is.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(incident).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update incident: %w", err)
+		}
+		if newStatus == "" {
+			return nil
+		}
+		if err := is.createActivity(tx, incident.ID, &userID, models.IncidentActivityStatusChange, map[string]interface{}{
+			"old_status": oldStatus,
+			"new_status": newStatus,
+		}); err != nil {
+			return err
+		}
+		return is.notifyChange(tx, incident, oldStatus)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return is.GetIncident(userID, incidentID)
+}
+
+// AddIncidentIssues groups issues of the incident's organization into the incident
+func (is *IncidentService) AddIncidentIssues(userID, incidentID uuid.UUID, issueIDs []uuid.UUID) (*dto.IncidentResponse, error) {
+	incident, err := is.getIncidentForUser(userID, incidentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(issueIDs) == 0 {
+		return nil, fmt.Errorf("%w: issue_ids is required", ErrIncidentInvalidRequest)
+	}
+
+	if err := is.db.Transaction(func(tx *gorm.DB) error {
+		return is.addIssues(tx, incident, &userID, issueIDs)
+	}); err != nil {
+		return nil, err
+	}
+
+	return is.GetIncident(userID, incidentID)
+}
+
+// RemoveIncidentIssue ungroups an issue from the incident
+func (is *IncidentService) RemoveIncidentIssue(userID, incidentID, issueID uuid.UUID) error {
+	incident, err := is.getIncidentForUser(userID, incidentID)
+	if err != nil {
+		return err
+	}
+
+	return is.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("incident_id = ? AND issue_id = ?", incident.ID, issueID).Delete(&models.IncidentIssue{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to remove issue from incident: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrIncidentIssueNotFound
+		}
+		return is.createActivity(tx, incident.ID, &userID, models.IncidentActivityIssueRemoved, map[string]interface{}{
+			"issue_id": issueID,
+		})
+	})
+}
+
+// AddIncidentNote posts a free-form note to the incident timeline
+func (is *IncidentService) AddIncidentNote(userID, incidentID uuid.UUID, content string) (*dto.IncidentActivityResponse, error) {
+	incident, err := is.getIncidentForUser(userID, incidentID)
+	if err != nil {
+		return nil, err
+	}
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, fmt.Errorf("%w: note content is required", ErrIncidentInvalidRequest)
+	}
+
+	data, err := json.Marshal(map[string]interface{}{"content": content})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal note: %w", err)
+	}
+	activity := models.IncidentActivity{
+		IncidentID: incident.ID,
+		UserID:     &userID,
+		Type:       models.IncidentActivityNote,
+		Data:       data,
+	}
+	if err := is.db.Create(&activity).Error; err != nil {
+		return nil, fmt.Errorf("failed to add note: %w", err)
+	}
+
+	response := convertIncidentActivityToResponse(activity)
+	return &response, nil
+}
+
+// GetIncidentTimeline returns the timeline of an incident, oldest entry first
+func (is *IncidentService) GetIncidentTimeline(userID, incidentID uuid.UUID) (*dto.IncidentTimelineResponse, error) {
+	incident, err := is.getIncidentForUser(userID, incidentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var activities []models.IncidentActivity
+	if err := is.db.Where("incident_id = ?", incident.ID).Order("created_at ASC").Find(&activities).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve incident timeline: %w", err)
+	}
+
+	responses := make([]dto.IncidentActivityResponse, len(activities))
+	for i, activity := range activities {
+		responses[i] = convertIncidentActivityToResponse(activity)
+	}
+	return &dto.IncidentTimelineResponse{Activities: responses}, nil
+}
+
+// GetIncidentEventRate returns the combined event rate of the incident's issues. The range
+// defaults to an hour before the incident started until it was resolved (or now), and the
+// interval to minute, hour or day buckets depending on the range.
+func (is *IncidentService) GetIncidentEventRate(userID, incidentID uuid.UUID, since, until *time.Time, interval string) (*dto.IncidentEventRateResponse, error) {
+	incident, err := is.getIncidentForUser(userID, incidentID)
+	if err != nil {
+		return nil, err
+	}
+
+	start := incident.StartedAt.Add(-time.Hour)
+	if since != nil {
+		start = *since
+	}
+	end := time.Now()
+	if incident.ResolvedAt != nil {
+		end = incident.ResolvedAt.Add(time.Hour)
+	}
+	if until != nil {
+		end = *until
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("%w: until must be after since", ErrIncidentInvalidRequest)
+	}
+
+	if interval == "" {
+		switch span := end.Sub(start); {
+		case span <= 6*time.Hour:
+			interval = "minute"
+		case span <= 14*24*time.Hour:
+			interval = "hour"
+		default:
+			interval = "day"
+		}
+	}
+	step, ok := incidentRateIntervals[interval]
+	if !ok {
+		return nil, fmt.Errorf("%w: interval must be minute, hour or day", ErrIncidentInvalidRequest)
+	}
+	start = start.UTC().Truncate(step)
+	end = end.UTC()
+	if end.Sub(start)/step >= maxIncidentRatePoints {
+		return nil, fmt.Errorf("%w: range is too long for a %s interval", ErrIncidentInvalidRequest, interval)
+	}
+
+	var issueIDs []uuid.UUID
+	if err := is.db.Model(&models.IncidentIssue{}).Where("incident_id = ?", incident.ID).
+		Pluck("issue_id", &issueIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve incident issues: %w", err)
+	}
+
+	response := &dto.IncidentEventRateResponse{
+		Interval: interval,
+		Since:    start,
+		Until:    end,
+		Points:   []dto.IncidentEventRatePoint{},
+		ByIssue:  []dto.IncidentIssueEventCount{},
+	}
+	for bucket := start; bucket.Before(end); bucket = bucket.Add(step) {
+		response.Points = append(response.Points, dto.IncidentEventRatePoint{Timestamp: bucket})
+	}
+	if len(issueIDs) == 0 {
+		return response, nil
+	}
+
+	// Buckets are computed here rather than in SQL so the query is the same on Postgres and SQLite
+	var events []struct {
+		IssueID   uuid.UUID
+		Timestamp time.Time
+	}
+	if err := is.db.Model(&models.Event{}).Select("issue_id, timestamp").
+		Where("issue_id IN ? AND timestamp >= ? AND timestamp < ?", issueIDs, start, end).
+		Scan(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve incident events: %w", err)
+	}
+
+	perIssue := make(map[uuid.UUID]int64)
+	for _, event := range events {
+		index := int(event.Timestamp.UTC().Sub(start) / step)
+		if index < 0 || index >= len(response.Points) {
+			continue
+		}
+		response.Points[index].Count++
+		response.Total++
+		perIssue[event.IssueID]++
+	}
+	for _, issueID := range issueIDs {
+		response.ByIssue = append(response.ByIssue, dto.IncidentIssueEventCount{IssueID: issueID, Count: perIssue[issueID]})
+	}
+
+	return response, nil
+}
+
+// DetectAlertStorm is called for every newly created issue. When the issue's organization
+// sees at least the configured number of new issues within the window, an alert storm
+// incident is opened with those issues; new issues keep joining it while the storm lasts.
+func (is *IncidentService) DetectAlertStorm(issue *models.Issue) {
+	if is.storm.Threshold <= 0 || is.storm.Window <= 0 {
+		return
+	}
+
+	is.stormMu.Lock()
+	defer is.stormMu.Unlock()
+
+	if err := is.detectAlertStorm(issue); err != nil {
+		log.Printf("Alert storm detection failed for issue %s: %v", issue.ID, err)
+	}
+}
+
+func (is *IncidentService) detectAlertStorm(issue *models.Issue) error {
+	var project models.Project
+	if err := is.db.Select("id, organization_id").First(&project, issue.ProjectID).Error; err != nil {
+		return fmt.Errorf("failed to retrieve project: %w", err)
+	}
+	since := time.Now().Add(-is.storm.Window)
+
+	// An unresolved storm incident that grew within the window absorbs the new issue
+	var incident models.Incident
+	err := is.db.Where("organization_id = ? AND source = ? AND status <> ? AND updated_at >= ?",
+		project.OrganizationID, models.IncidentSourceAlertStorm, models.IncidentResolved, since).
+		Order("started_at DESC").
+		First(&incident).Error
+	if err == nil {
+		return is.db.Transaction(func(tx *gorm.DB) error {
+			if err := is.addIssues(tx, &incident, nil, []uuid.UUID{issue.ID}); err != nil {
+				return err
+			}
+			return tx.Model(&incident).Update("updated_at", time.Now()).Error
+		})
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to query storm incidents: %w", err)
+	}
+
+	var newIssues []models.Issue
+	if err := is.db.Select("issues.id, issues.created_at").
+		Joins("JOIN projects ON projects.id = issues.project_id").
+		Where("projects.organization_id = ? AND issues.created_at >= ?", project.OrganizationID, since).
+		Order("issues.created_at ASC").
+		Find(&newIssues).Error; err != nil {
+		return fmt.Errorf("failed to count new issues: %w", err)
+	}
+	if len(newIssues) < is.storm.Threshold {
+		return nil
+	}
+
+	newIssueIDs := make([]uuid.UUID, len(newIssues))
+	for i, newIssue := range newIssues {
+		newIssueIDs[i] = newIssue.ID
+	}
+
+	incident = models.Incident{
+		OrganizationID: project.OrganizationID,
+		Title:          fmt.Sprintf("Alert storm: %d new issues within %s", len(newIssueIDs), is.storm.Window),
+		Status:         models.IncidentOpen,
+		Source:         models.IncidentSourceAlertStorm,
+		StartedAt:      newIssues[0].CreatedAt,
+	}
+	incident.ID = uuid.New()
+
+	err = is.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&incident).Error; err != nil {
+			return fmt.Errorf("failed to create incident: %w", err)
+		}
+		if err := is.createActivity(tx, incident.ID, nil, models.IncidentActivityCreated, map[string]interface{}{
+			"source":      incident.Source,
+			"issue_count": len(newIssueIDs),
+			"window":      is.storm.Window.String(),
+		}); err != nil {
+			return err
+		}
+		if err := is.addIssues(tx, &incident, nil, newIssueIDs); err != nil {
+			return err
+		}
+		return is.notifyChange(tx, &incident, "")
+	})
+	return err
+}
+
+// IsValidIncidentStatus reports whether the status is a known incident status
+func IsValidIncidentStatus(status string) bool {
+	switch models.IncidentStatus(status) {
+	case models.IncidentOpen, models.IncidentInvestigating, models.IncidentMitigated, models.IncidentResolved:
+		return true
+	}
+	return false
+}
+
+// getIncidentForUser loads an incident, checking the user is a member of its organization
+func (is *IncidentService) getIncidentForUser(userID, incidentID uuid.UUID) (*models.Incident, error) {
+	var incident models.Incident
+	if err := is.db.First(&incident, incidentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrIncidentNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve incident: %w", err)
+	}
+
+	var count int64
+	if err := is.db.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", incident.OrganizationID, userID).
+		Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to check organization membership: %w", err)
+	}
+	if count == 0 {
+		return nil, ErrIncidentAccessDenied
+	}
+
+	return &incident, nil
+}
+
+// addIssues links issues of the incident's organization, skipping ones already linked
+func (is *IncidentService) addIssues(tx *gorm.DB, incident *models.Incident, userID *uuid.UUID, issueIDs []uuid.UUID) error {
+	if len(issueIDs) == 0 {
+		return nil
+	}
+
+	var validIDs []uuid.UUID
+	if err := tx.Model(&models.Issue{}).
+		Joins("JOIN projects ON projects.id = issues.project_id").
+		Where("issues.id IN ? AND projects.organization_id = ?", issueIDs, incident.OrganizationID).
+		Pluck("issues.id", &validIDs).Error; err != nil {
+		return fmt.Errorf("failed to verify issues: %w", err)
+	}
+	valid := make(map[uuid.UUID]bool, len(validIDs))
+	for _, id := range validIDs {
+		valid[id] = true
+	}
+	for _, id := range issueIDs {
+		if !valid[id] {
+			return fmt.Errorf("%w: %s", ErrIncidentIssueNotFound, id)
+		}
+	}
+
+	var linkedIDs []uuid.UUID
+	if err := tx.Model(&models.IncidentIssue{}).Where("incident_id = ? AND issue_id IN ?", incident.ID, issueIDs).
+		Pluck("issue_id", &linkedIDs).Error; err != nil {
+		return fmt.Errorf("failed to check incident issues: %w", err)
+	}
+	linked := make(map[uuid.UUID]bool, len(linkedIDs))
+	for _, id := range linkedIDs {
+		linked[id] = true
+	}
+
+	for _, id := range issueIDs {
+		if linked[id] {
+			continue
+		}
+		linked[id] = true
+
+		link := models.IncidentIssue{IncidentID: incident.ID, IssueID: id, AddedByID: userID}
+		if err := tx.Create(&link).Error; err != nil {
+			return fmt.Errorf("failed to add issue to incident: %w", err)
+		}
+		if err := is.createActivity(tx, incident.ID, userID, models.IncidentActivityIssueAdded, map[string]interface{}{
+			"issue_id": id,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (is *IncidentService) countIssues(incidentIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	rows, err := countByKey(is.db.DB, &models.IncidentIssue{}, "incident_id", incidentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count incident issues: %w", err)
+	}
+	counts := make(map[uuid.UUID]int, len(rows))
+	for incidentID, count := range rows {
+		counts[incidentID] = int(count)
+	}
+	return counts, nil
+}
+
+func (is *IncidentService) createActivity(tx *gorm.DB, incidentID uuid.UUID, userID *uuid.UUID, activityType models.IncidentActivityType, data map[string]interface{}) error {
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal incident activity: %w", err)
+	}
+
+	activity := models.IncidentActivity{
+		IncidentID: incidentID,
+		UserID:     userID,
+		Type:       activityType,
+		Data:       dataJSON,
+	}
+	if err := tx.Create(&activity).Error; err != nil {
+		return fmt.Errorf("failed to create incident activity: %w", err)
+	}
+	return nil
+}
+
+func convertIncidentToResponse(incident models.Incident) dto.IncidentResponse {
+	return dto.IncidentResponse{
+		ID:             incident.ID,
+		OrganizationID: incident.OrganizationID,
+		Title:          incident.Title,
+		Description:    incident.Description,
+		Status:         string(incident.Status),
+		Source:         string(incident.Source),
+		StartedAt:      incident.StartedAt,
+		ResolvedAt:     incident.ResolvedAt,
+		CreatedByID:    incident.CreatedByID,
+		CreatedAt:      incident.CreatedAt,
+		UpdatedAt:      incident.UpdatedAt,
+	}
+}
+
+func convertIncidentActivityToResponse(activity models.IncidentActivity) dto.IncidentActivityResponse {
+	return dto.IncidentActivityResponse{
+		ID:        activity.ID,
+		UserID:    activity.UserID,
+		Type:      string(activity.Type),
+		Data:      activity.Data,
+		CreatedAt: activity.CreatedAt,
+	}
+}
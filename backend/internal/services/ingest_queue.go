@@ -0,0 +1,55 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/queue"
+)
+
+// ProcessQueuedEvent stores an error event taken from the ingest queue. Events that can
+// never succeed (invalid, duplicate, or for a deleted project) are dropped instead of retried.
+// It is the queue handler of both the API server and standalone ingestion workers, and
+// buffers events for batched inserts when event batching is started. Events are deferred
+// while a maintenance window holds back their organization's ingestion.
+func (es *ErrorService) ProcessQueuedEvent(job *queue.IngestJob) error {
+	if es.maintenance != nil && es.maintenance.DefersIngestion(job.ProjectID) {
+		return queue.ErrJobDeferred
+	}
+
+	var eventData dto.ErrorEventRequest
+	if err := json.Unmarshal(job.Event, &eventData); err != nil {
+		log.Printf("Dropping queued event for project %s: invalid JSON payload: %v", job.ProjectID, err)
+		return nil
+	}
+
+	var sampled bool
+	var err error
+	if es.batcher != nil {
+		err = es.processBatchedErrorEvent(job.ProjectID, &eventData, job.ClientIP, job.UserAgent)
+	} else {
+		var response *dto.ErrorEventResponse
+		if response, err = es.ProcessErrorEvent(job.ProjectID, &eventData, job.ClientIP, job.UserAgent); err == nil {
+			sampled = response.Sampled
+		}
+	}
+	if eventData.EventID != nil {
+		es.RecordEventOutcome(job.ProjectID, *eventData.EventID, sampled, err)
+	}
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrEventExists), errors.Is(err, ErrEventFiltered), errors.Is(err, ErrEventDropped):
+		return nil
+	case errors.Is(err, ErrInvalidEventData),
+		strings.Contains(err.Error(), "project not found"),
+		strings.Contains(err.Error(), "project is inactive"):
+		log.Printf("Dropping queued event for project %s: %v", job.ProjectID, err)
+		return nil
+	default:
+		return err
+	}
+}
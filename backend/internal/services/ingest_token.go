@@ -0,0 +1,164 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrIngestTokenNotFound = errors.New("ingest token not found")
+	ErrIngestTokenInvalid  = errors.New("invalid ingest token")
+)
+
+const (
+	// IngestTokenPrefix starts every organization ingest token, telling them apart from JWTs
+	IngestTokenPrefix = "msi_"
+
+	// IngestTokenScopeReleases is the only scope of ingest tokens: creating releases and
+	// uploading their artifacts
+	IngestTokenScopeReleases = "project:releases"
+
+	// ingestTokenUsageInterval bounds how often a token's last use is written
+	ingestTokenUsageInterval = time.Minute
+)
+
+type IngestTokenService struct {
+	db *database.DB
+}
+
+// NewIngestTokenService creates a new organization ingest token service
+func NewIngestTokenService(db *database.DB) *IngestTokenService {
+	return &IngestTokenService{db: db}
+}
+
+// CreateToken creates an ingest token for an organization. The token is only returned now.
+func (its *IngestTokenService) CreateToken(orgID, userID uuid.UUID, request dto.IngestTokenRequest) (*dto.IngestTokenResponse, error) {
+	secret, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+	token := IngestTokenPrefix + secret
+
+	ingestToken := &models.OrgIngestToken{
+		OrganizationID: orgID,
+		Name:           strings.TrimSpace(request.Name),
+		TokenHash:      hashIngestToken(token),
+		TokenPrefix:    token[:len(IngestTokenPrefix)+8],
+		CreatedBy:      &userID,
+	}
+	if err := its.db.Create(ingestToken).Error; err != nil {
+		return nil, fmt.Errorf("failed to create ingest token: %w", err)
+	}
+
+	response := convertIngestTokenToResponse(ingestToken)
+	response.Token = token
+	return &response, nil
+}
+
+// ListTokens lists the ingest tokens of an organization, newest first
+func (its *IngestTokenService) ListTokens(orgID uuid.UUID) (*dto.IngestTokenListResponse, error) {
+	var tokens []models.OrgIngestToken
+	if err := its.db.Where("organization_id = ?", orgID).Order("created_at DESC").Find(&tokens).Error; err != nil {
+		return nil, fmt.Errorf("failed to list ingest tokens: %w", err)
+	}
+
+	responses := make([]dto.IngestTokenResponse, len(tokens))
+	for i := range tokens {
+		responses[i] = convertIngestTokenToResponse(&tokens[i])
+	}
+	return &dto.IngestTokenListResponse{Tokens: responses}, nil
+}
+
+// RevokeToken revokes an ingest token of an organization; revoking it again has no effect
+func (its *IngestTokenService) RevokeToken(orgID, tokenID uuid.UUID) (*dto.IngestTokenResponse, error) {
+	var token models.OrgIngestToken
+	if err := its.db.Where("id = ? AND organization_id = ?", tokenID, orgID).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrIngestTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to get ingest token: %w", err)
+	}
+
+	if token.RevokedAt == nil {
+		now := time.Now().UTC()
+		if err := its.db.Model(&token).Update("revoked_at", now).Error; err != nil {
+			return nil, fmt.Errorf("failed to revoke ingest token: %w", err)
+		}
+		token.RevokedAt = &now
+	}
+
+	response := convertIngestTokenToResponse(&token)
+	return &response, nil
+}
+
+// AuthorizeProject checks that an ingest token is valid and belongs to the organization of
+// the project, and returns the project and the token. Unknown, revoked and foreign tokens
+// are all ErrIngestTokenInvalid; a missing project is ErrProjectNotFound.
+func (its *IngestTokenService) AuthorizeProject(token string, projectID uuid.UUID) (*models.Project, *models.OrgIngestToken, error) {
+	if !strings.HasPrefix(token, IngestTokenPrefix) {
+		return nil, nil, ErrIngestTokenInvalid
+	}
+
+	var ingestToken models.OrgIngestToken
+	if err := its.db.Where("token_hash = ? AND revoked_at IS NULL", hashIngestToken(token)).First(&ingestToken).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrIngestTokenInvalid
+		}
+		return nil, nil, fmt.Errorf("failed to get ingest token: %w", err)
+	}
+
+	var project models.Project
+	if err := its.db.Where("id = ?", projectID).First(&project).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrProjectNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	if project.OrganizationID != ingestToken.OrganizationID {
+		return nil, nil, ErrIngestTokenInvalid
+	}
+
+	its.recordUsage(&ingestToken)
+	return &project, &ingestToken, nil
+}
+
+// recordUsage updates when the token was last used, at most once per ingestTokenUsageInterval
+func (its *IngestTokenService) recordUsage(token *models.OrgIngestToken) {
+	now := time.Now().UTC()
+	if token.LastUsedAt != nil && now.Sub(*token.LastUsedAt) < ingestTokenUsageInterval {
+		return
+	}
+	if err := its.db.Model(token).Update("last_used_at", now).Error; err == nil {
+		token.LastUsedAt = &now
+	}
+}
+
+func hashIngestToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func convertIngestTokenToResponse(token *models.OrgIngestToken) dto.IngestTokenResponse {
+	return dto.IngestTokenResponse{
+		ID:             token.ID,
+		OrganizationID: token.OrganizationID,
+		Name:           token.Name,
+		TokenPrefix:    token.TokenPrefix,
+		Scopes:         []string{IngestTokenScopeReleases},
+		CreatedBy:      token.CreatedBy,
+		LastUsedAt:     token.LastUsedAt,
+		RevokedAt:      token.RevokedAt,
+		CreatedAt:      token.CreatedAt,
+	}
+}
@@ -2,29 +2,60 @@ package services
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 	"time"
 
+	"minisentry/internal/database"
 	"minisentry/internal/dto"
 	"minisentry/internal/models"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrNoReleaseToResolveIn is returned when resolving an issue in the latest release of a
+// project that has none
+var ErrNoReleaseToResolveIn = errors.New("project has no release to resolve the issue in")
+
+// IssueChangeListener is called inside the transaction that changes an issue's status or
+// assignee, with the issue as updated; returning an error rolls the change back
+type IssueChangeListener func(tx *gorm.DB, issue *models.Issue, actorID *uuid.UUID, changeType string, changes map[string]dto.IssueSyncFieldChange) error
+
 type IssueService struct {
 	db *gorm.DB
+	
+	changeListeners  []IssueChangeListener
+	mentionListeners []IssueMentionListener
+
+	// outcomes, when set, adds the issue's events that were not stored to its details
+	outcomes *IssueOutcomeService
+
+	// groupingConfigs, when set, has its cached fingerprint rules dropped when they change
+	groupingConfigs *GroupingConfigService
+
+	// attachments, when set, has the blobs of deleted issues' attachments deleted
+	attachments *AttachmentService
 }
 
 func NewIssueService(db *gorm.DB) *IssueService {
 	return &IssueService{db: db}
 }
 
+// OnIssueChange registers a listener for issue status and assignee changes.
+// Listeners must not be registered after the server has started.
+func (s *IssueService) OnIssueChange(listener IssueChangeListener) {
+	s.changeListeners = append(s.changeListeners, listener)
+}
+
 // GetProjectIssues retrieves issues for a project with filtering, sorting, and pagination
 func (s *IssueService) GetProjectIssues(projectID uuid.UUID, filters dto.IssueFilters) (*dto.IssueListResponse, error) {
-	query := s.db.Model(&models.Issue{}).Where("project_id = ?", projectID)
+	// Issues queued for deletion are left out
+	query := s.db.Model(&models.Issue{}).Where("project_id = ? AND deletion_requested_at IS NULL", projectID)
 	
 	// Apply filters
 	query = s.applyIssueFilters(query, filters)
@@ -52,14 +83,9 @@ func (s *IssueService) GetProjectIssues(projectID uuid.UUID, filters dto.IssueFi
 	}
 	
 	// Convert to response DTOs
-	issueResponses := make([]dto.IssueResponse, len(issues))
-	for i, issue := range issues {
-		issueResponse, err := s.convertIssueToResponse(issue, true)
-		if err != nil {
-			log.Printf("Error converting issue %s to response: %v", issue.ID, err)
-			continue
-		}
-		issueResponses[i] = *issueResponse
+	issueResponses, err := s.convertIssuesToResponses(issues, true)
+	if err != nil {
+		return nil, err
 	}
 	
 	totalPages := dto.CalculateTotalPages(total, limit)
@@ -84,13 +110,65 @@ func (s *IssueService) GetIssue(issueID uuid.UUID) (*dto.IssueResponse, error) {
 		return nil, fmt.Errorf("failed to retrieve issue: %w", err)
 	}
 	
-	return s.convertIssueToResponse(issue, true)
+	response, err := s.convertIssueToResponse(issue, true)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Surface the project's runbook so responders see how to debug the service next to the error
+	if response.Project != nil {
+		response.Project.Runbook = issue.Project.Runbook
+	}
+	
+	relations, err := s.GetIssueRelations(issueID, 1, "")
+	if err != nil {
+		return nil, err
+	}
+	response.Relations = relations
+	
+	if s.outcomes != nil {
+		outcomes, err := s.outcomes.GetIssueOutcomes(issue.ProjectID, issue.Fingerprint, issue.FirstSeen)
+		if err != nil {
+			return nil, err
+		}
+		response.Outcomes = outcomes
+	}
+	
+	return response, nil
+}
+
+// GetIssueProjectID returns the project an issue belongs to, ErrIssueNotFound if there is no such issue
+func (s *IssueService) GetIssueProjectID(issueID uuid.UUID) (uuid.UUID, error) {
+	projectIDs, err := s.GetIssueProjectIDs([]uuid.UUID{issueID})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	projectID, ok := projectIDs[issueID]
+	if !ok {
+		return uuid.Nil, ErrIssueNotFound
+	}
+	return projectID, nil
+}
+
+// GetIssueProjectIDs returns the project of each existing issue, keyed by issue ID. Issues
+// queued for deletion are left out, so they are not found through the API.
+func (s *IssueService) GetIssueProjectIDs(issueIDs []uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	var issues []models.Issue
+	if err := s.db.Select("id", "project_id").Where("id IN ? AND deletion_requested_at IS NULL", issueIDs).Find(&issues).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve issue projects: %w", err)
+	}
+
+	projectIDs := make(map[uuid.UUID]uuid.UUID, len(issues))
+	for _, issue := range issues {
+		projectIDs[issue.ID] = issue.ProjectID
+	}
+	return projectIDs, nil
 }
 
 // UpdateIssueStatus updates the status or assignment of an issue
 func (s *IssueService) UpdateIssueStatus(issueID uuid.UUID, userID uuid.UUID, request dto.IssueUpdateRequest) (*dto.IssueResponse, error) {
 	var issue models.Issue
-	if err := s.db.First(&issue, issueID).Error; err != nil {
+	if err := s.db.Where("deletion_requested_at IS NULL").First(&issue, issueID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("issue not found")
 		}
@@ -116,6 +194,34 @@ func (s *IssueService) UpdateIssueStatus(issueID uuid.UUID, userID uuid.UUID, re
 		if s.isValidStatusTransition(issue.Status, status) {
 			updates["status"] = status
 			issue.Status = status
+			if status == models.StatusResolved {
+				// Resolving a regression ends it
+				updates["is_regression"] = false
+				issue.IsRegression = false
+			}
+			issue.ResolvedInRelease = nil
+			issue.IgnoreCondition = nil
+			if status == models.StatusIgnored {
+				condition, err := newIgnoreCondition(&issue, request)
+				if err != nil {
+					tx.Rollback()
+					return nil, err
+				}
+				if issue.IgnoreCondition, err = condition.encode(); err != nil {
+					tx.Rollback()
+					return nil, err
+				}
+			}
+			updates["ignore_condition"] = issue.IgnoreCondition
+			if status == models.StatusResolved && request.ResolvedInRelease != nil {
+				version, err := s.resolutionRelease(tx, issue.ProjectID, *request.ResolvedInRelease)
+				if err != nil {
+					tx.Rollback()
+					return nil, err
+				}
+				issue.ResolvedInRelease = &version
+			}
+			updates["resolved_in_release"] = issue.ResolvedInRelease
 		} else {
 			tx.Rollback()
 			return nil, fmt.Errorf("invalid status transition from %s to %s", issue.Status, status)
@@ -127,6 +233,19 @@ func (s *IssueService) UpdateIssueStatus(issueID uuid.UUID, userID uuid.UUID, re
 		issue.AssigneeID = request.AssigneeID
 	}
 	
+	// A priority set by a user is kept as the issue's events are counted
+	oldPriority := issue.Priority
+	if request.Priority != nil {
+		if !isValidIssuePriority(*request.Priority) {
+			tx.Rollback()
+			return nil, fmt.Errorf("%w: %s", ErrInvalidIssuePriority, *request.Priority)
+		}
+		issue.Priority = models.IssuePriority(*request.Priority)
+		issue.PriorityLocked = true
+		updates["priority"] = issue.Priority
+		updates["priority_locked"] = true
+	}
+	
 	// Update issue
 	if len(updates) > 0 {
 		if err := tx.Model(&issue).Updates(updates).Error; err != nil {
@@ -137,10 +256,24 @@ func (s *IssueService) UpdateIssueStatus(issueID uuid.UUID, userID uuid.UUID, re
 	
 	// Log activities
 	if request.Status != nil && string(oldStatus) != *request.Status {
-		if err := s.logStatusChangeActivity(tx, issueID, userID, string(oldStatus), *request.Status, request.Resolution); err != nil {
+		if err := s.logStatusChangeActivity(tx, issueID, userID, string(oldStatus), *request.Status, request.Resolution, issue.ResolvedInRelease, decodeIgnoreCondition(issue.IgnoreCondition)); err != nil {
 			tx.Rollback()
 			return nil, fmt.Errorf("failed to log status change activity: %w", err)
 		}
+		if err := s.notifyStatusChange(tx, &issue, &userID, oldStatus); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	
+	if request.Priority != nil && issue.Priority != oldPriority {
+		if err := s.createActivity(tx, issueID, userID, models.ActivityPriority, map[string]interface{}{
+			"previous_priority": string(oldPriority),
+			"new_priority":      string(issue.Priority),
+		}); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to log priority activity: %w", err)
+		}
 	}
 	
 	if request.AssigneeID != nil && !s.uuidPtrEqual(oldAssigneeID, request.AssigneeID) {
@@ -148,6 +281,10 @@ func (s *IssueService) UpdateIssueStatus(issueID uuid.UUID, userID uuid.UUID, re
 			tx.Rollback()
 			return nil, fmt.Errorf("failed to log assignment activity: %w", err)
 		}
+		if err := s.notifyAssignment(tx, &issue, &userID, oldAssigneeID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
 	}
 	
 	if err := tx.Commit().Error; err != nil {
@@ -199,17 +336,28 @@ func (s *IssueService) AddIssueComment(issueID uuid.UUID, userID uuid.UUID, requ
 	}
 	
 	// Log comment activity
-	if err := s.logCommentActivity(tx, issueID, userID); err != nil {
+	if err := s.logCommentActivity(tx, issueID, userID, comment.ID); err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("failed to log comment activity: %w", err)
 	}
 	
+	// Commenters take part in the issue
+	if err := addIssueParticipant(tx, issueID, userID, models.SubscriptionCommented); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	
+	if err := s.recordCommentMentions(tx, &issue, &comment); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	
 	// Return comment with user info
-	if err := s.db.Preload("User").First(&comment, comment.ID).Error; err != nil {
+	if err := s.db.Preload("User").Preload("Mentions.User").First(&comment, comment.ID).Error; err != nil {
 		return nil, fmt.Errorf("failed to retrieve comment with user: %w", err)
 	}
 	
@@ -231,16 +379,30 @@ func (s *IssueService) GetIssueComments(issueID uuid.UUID, page, limit int) (*dt
 	var comments []models.IssueComment
 	if err := s.db.Where("issue_id = ?", issueID).
 		Preload("User").
+		Preload("Mentions.User").
 		Order("created_at DESC").
 		Offset(offset).Limit(limit).
 		Find(&comments).Error; err != nil {
 		return nil, fmt.Errorf("failed to retrieve comments: %w", err)
 	}
 	
+	// Load reaction counts for the page in one query
+	commentIDs := make([]uuid.UUID, len(comments))
+	for i, comment := range comments {
+		commentIDs[i] = comment.ID
+	}
+	reactions, err := s.getCommentReactions(commentIDs)
+	if err != nil {
+		return nil, err
+	}
+	
 	// Convert to response DTOs
 	commentResponses := make([]dto.IssueCommentResponse, len(comments))
 	for i, comment := range comments {
 		commentResponses[i] = *s.convertCommentToResponse(comment)
+		if commentReactions, ok := reactions[comment.ID]; ok {
+			commentResponses[i].Reactions = commentReactions
+		}
 	}
 	
 	totalPages := dto.CalculateTotalPages(total, limit)
@@ -254,6 +416,106 @@ func (s *IssueService) GetIssueComments(issueID uuid.UUID, page, limit int) (*dt
 	}, nil
 }
 
+// AddCommentReaction adds the user's emoji reaction to a comment; reacting twice with the same emoji is a no-op
+func (s *IssueService) AddCommentReaction(issueID, commentID, userID uuid.UUID, emoji string) ([]dto.IssueCommentReactionResponse, error) {
+	if err := s.verifyComment(issueID, commentID); err != nil {
+		return nil, err
+	}
+	
+	reaction := models.IssueCommentReaction{
+		CommentID: commentID,
+		UserID:    userID,
+		Emoji:     emoji,
+	}
+	if err := s.db.Where("comment_id = ? AND user_id = ? AND emoji = ?", commentID, userID, emoji).
+		FirstOrCreate(&reaction).Error; err != nil {
+		return nil, fmt.Errorf("failed to add reaction: %w", err)
+	}
+	
+	return s.getReactionsForComment(commentID)
+}
+
+// RemoveCommentReaction removes the user's emoji reaction from a comment
+func (s *IssueService) RemoveCommentReaction(issueID, commentID, userID uuid.UUID, emoji string) ([]dto.IssueCommentReactionResponse, error) {
+	if err := s.verifyComment(issueID, commentID); err != nil {
+		return nil, err
+	}
+	
+	result := s.db.Where("comment_id = ? AND user_id = ? AND emoji = ?", commentID, userID, emoji).
+		Delete(&models.IssueCommentReaction{})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to remove reaction: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("reaction not found")
+	}
+	
+	return s.getReactionsForComment(commentID)
+}
+
+// verifyComment checks that the comment exists and belongs to the issue
+func (s *IssueService) verifyComment(issueID, commentID uuid.UUID) error {
+	var count int64
+	if err := s.db.Model(&models.IssueComment{}).
+		Where("id = ? AND issue_id = ?", commentID, issueID).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to verify comment: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("comment not found")
+	}
+	return nil
+}
+
+func (s *IssueService) getReactionsForComment(commentID uuid.UUID) ([]dto.IssueCommentReactionResponse, error) {
+	reactions, err := s.getCommentReactions([]uuid.UUID{commentID})
+	if err != nil {
+		return nil, err
+	}
+	if commentReactions, ok := reactions[commentID]; ok {
+		return commentReactions, nil
+	}
+	return []dto.IssueCommentReactionResponse{}, nil
+}
+
+// getCommentReactions groups the reactions of the given comments by emoji, in the order each emoji was first used
+func (s *IssueService) getCommentReactions(commentIDs []uuid.UUID) (map[uuid.UUID][]dto.IssueCommentReactionResponse, error) {
+	grouped := make(map[uuid.UUID][]dto.IssueCommentReactionResponse)
+	if len(commentIDs) == 0 {
+		return grouped, nil
+	}
+	
+	var reactions []models.IssueCommentReaction
+	if err := s.db.Where("comment_id IN ?", commentIDs).
+		Order("created_at ASC").
+		Find(&reactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve reactions: %w", err)
+	}
+	
+	for _, reaction := range reactions {
+		commentReactions := grouped[reaction.CommentID]
+		found := false
+		for i := range commentReactions {
+			if commentReactions[i].Emoji == reaction.Emoji {
+				commentReactions[i].Count++
+				commentReactions[i].UserIDs = append(commentReactions[i].UserIDs, reaction.UserID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			commentReactions = append(commentReactions, dto.IssueCommentReactionResponse{
+				Emoji:   reaction.Emoji,
+				Count:   1,
+				UserIDs: []uuid.UUID{reaction.UserID},
+			})
+		}
+		grouped[reaction.CommentID] = commentReactions
+	}
+	
+	return grouped, nil
+}
+
 // GetIssueActivity retrieves paginated activity timeline for an issue
 func (s *IssueService) GetIssueActivity(issueID uuid.UUID, page, limit int) (*dto.IssueActivitiesResponse, error) {
 	page, limit = s.getPaginationDefaults(page, limit)
@@ -317,6 +579,7 @@ func (s *IssueService) GetIssueEvents(issueID uuid.UUID, page, limit int) (*dto.
 	for i, event := range events {
 		eventResponses[i] = s.convertEventToResponse(event)
 	}
+	s.attachReplayReferences(eventResponses, events)
 	
 	totalPages := dto.CalculateTotalPages(total, limit)
 	
@@ -329,11 +592,13 @@ func (s *IssueService) GetIssueEvents(issueID uuid.UUID, page, limit int) (*dto.
 	}, nil
 }
 
-// GetIssueStats retrieves dashboard statistics for issues in a project
-func (s *IssueService) GetIssueStats(projectID uuid.UUID) (*dto.IssueStatsResponse, error) {
+// GetIssueStats retrieves dashboard statistics for issues in a project; issues assigned to
+// userID are counted as assigned to me
+func (s *IssueService) GetIssueStats(projectID, userID uuid.UUID) (*dto.IssueStatsResponse, error) {
 	stats := &dto.IssueStatsResponse{
 		ByLevel:       make(map[string]int64),
 		ByEnvironment: make(map[string]int64),
+		ByCountry:     make(map[string]int64),
 		Timeline:      make([]dto.IssueTimelineEntry, 0),
 	}
 	
@@ -343,7 +608,7 @@ func (s *IssueService) GetIssueStats(projectID uuid.UUID) (*dto.IssueStatsRespon
 		Count  int64
 	}
 	if err := s.db.Model(&models.Issue{}).
-		Where("project_id = ?", projectID).
+		Where("project_id = ? AND deletion_requested_at IS NULL", projectID).
 		Select("status, count(*) as count").
 		Group("status").
 		Scan(&counts).Error; err != nil {
@@ -362,13 +627,28 @@ func (s *IssueService) GetIssueStats(projectID uuid.UUID) (*dto.IssueStatsRespon
 		}
 	}
 	
+	// Get assigned, unassigned and assigned to me counts
+	var assignment struct {
+		Assigned     int64
+		AssignedToMe int64
+	}
+	if err := s.db.Model(&models.Issue{}).
+		Where("project_id = ? AND deletion_requested_at IS NULL", projectID).
+		Select("COUNT(assignee_id) as assigned, COALESCE(SUM(CASE WHEN assignee_id = ? THEN 1 ELSE 0 END), 0) as assigned_to_me", userID).
+		Scan(&assignment).Error; err != nil {
+		return nil, fmt.Errorf("failed to get assignment counts: %w", err)
+	}
+	stats.Assigned = assignment.Assigned
+	stats.Unassigned = stats.Total - assignment.Assigned
+	stats.AssignedToMe = assignment.AssignedToMe
+	
 	// Get counts by level
 	var levelCounts []struct {
 		Level string
 		Count int64
 	}
 	if err := s.db.Model(&models.Issue{}).
-		Where("project_id = ?", projectID).
+		Where("project_id = ? AND deletion_requested_at IS NULL", projectID).
 		Select("level, count(*) as count").
 		Group("level").
 		Scan(&levelCounts).Error; err != nil {
@@ -388,7 +668,7 @@ func (s *IssueService) GetIssueStats(projectID uuid.UUID) (*dto.IssueStatsRespon
 		SELECT e.environment, COUNT(DISTINCT i.id) as count
 		FROM issues i
 		INNER JOIN events e ON e.issue_id = i.id
-		WHERE i.project_id = ?
+		WHERE i.project_id = ? AND i.deletion_requested_at IS NULL
 		GROUP BY e.environment
 	`, projectID).Scan(&envCounts).Error; err != nil {
 		return nil, fmt.Errorf("failed to get environment counts: %w", err)
@@ -398,26 +678,46 @@ func (s *IssueService) GetIssueStats(projectID uuid.UUID) (*dto.IssueStatsRespon
 		stats.ByEnvironment[count.Environment] = count.Count
 	}
 	
+	// Get counts by country, from the geo.country_code tag of events
+	var countryCounts []struct {
+		Country string
+		Count   int64
+	}
+	country, path := database.JSONText(s.db, "e.tags", "geo.country_code")
+	if err := s.db.Raw(`
+		SELECT `+country+` as country, COUNT(DISTINCT i.id) as count
+		FROM issues i
+		INNER JOIN events e ON e.issue_id = i.id
+		WHERE i.project_id = ? AND i.deletion_requested_at IS NULL AND `+country+` IS NOT NULL
+		GROUP BY 1
+	`, path, projectID, path).Scan(&countryCounts).Error; err != nil {
+		return nil, fmt.Errorf("failed to get country counts: %w", err)
+	}
+	
+	for _, count := range countryCounts {
+		stats.ByCountry[count.Country] = count.Count
+	}
+	
 	// Get new issues today and this week
 	now := time.Now()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	startOfWeek := startOfDay.AddDate(0, 0, -int(startOfDay.Weekday()))
 	
 	if err := s.db.Model(&models.Issue{}).
-		Where("project_id = ? AND first_seen >= ?", projectID, startOfDay).
+		Where("project_id = ? AND deletion_requested_at IS NULL AND first_seen >= ?", projectID, startOfDay).
 		Count(&stats.NewToday).Error; err != nil {
 		return nil, fmt.Errorf("failed to count new issues today: %w", err)
 	}
 	
 	if err := s.db.Model(&models.Issue{}).
-		Where("project_id = ? AND first_seen >= ?", projectID, startOfWeek).
+		Where("project_id = ? AND deletion_requested_at IS NULL AND first_seen >= ?", projectID, startOfWeek).
 		Count(&stats.NewThisWeek).Error; err != nil {
 		return nil, fmt.Errorf("failed to count new issues this week: %w", err)
 	}
 	
 	// Get top issues by frequency
 	var topIssues []models.Issue
-	if err := s.db.Where("project_id = ?", projectID).
+	if err := s.db.Where("project_id = ? AND deletion_requested_at IS NULL", projectID).
 		Preload("Assignee").Preload("Project").
 		Order("times_seen DESC").
 		Limit(10).
@@ -425,15 +725,11 @@ func (s *IssueService) GetIssueStats(projectID uuid.UUID) (*dto.IssueStatsRespon
 		return nil, fmt.Errorf("failed to get top issues: %w", err)
 	}
 	
-	stats.TopIssues = make([]dto.IssueResponse, len(topIssues))
-	for i, issue := range topIssues {
-		issueResponse, err := s.convertIssueToResponse(issue, false)
-		if err != nil {
-			log.Printf("Error converting top issue %s to response: %v", issue.ID, err)
-			continue
-		}
-		stats.TopIssues[i] = *issueResponse
+	topIssueResponses, err := s.convertIssuesToResponses(topIssues, false)
+	if err != nil {
+		return nil, err
 	}
+	stats.TopIssues = topIssueResponses
 	
 	// Get timeline data (last 30 days)
 	var timelineCounts []struct {
@@ -443,7 +739,7 @@ func (s *IssueService) GetIssueStats(projectID uuid.UUID) (*dto.IssueStatsRespon
 	if err := s.db.Raw(`
 		SELECT DATE(first_seen) as date, COUNT(*) as count
 		FROM issues
-		WHERE project_id = ? AND first_seen >= ?
+		WHERE project_id = ? AND deletion_requested_at IS NULL AND first_seen >= ?
 		GROUP BY DATE(first_seen)
 		ORDER BY date DESC
 		LIMIT 30
@@ -466,6 +762,9 @@ func (s *IssueService) BulkUpdateIssues(userID uuid.UUID, request dto.BulkUpdate
 	if len(request.IssueIDs) == 0 {
 		return nil, fmt.Errorf("no issues specified")
 	}
+	if request.Action == "delete" {
+		return s.queueIssueDeletions(userID, request.IssueIDs)
+	}
 	
 	// Start transaction
 	tx := s.db.Begin()
@@ -482,7 +781,7 @@ func (s *IssueService) BulkUpdateIssues(userID uuid.UUID, request dto.BulkUpdate
 	
 	for _, issueID := range request.IssueIDs {
 		var issue models.Issue
-		if err := tx.First(&issue, issueID).Error; err != nil {
+		if err := tx.Where("deletion_requested_at IS NULL").First(&issue, issueID).Error; err != nil {
 			response.FailedCount++
 			response.Errors = append(response.Errors, fmt.Sprintf("Issue %s not found", issueID))
 			continue
@@ -496,6 +795,9 @@ func (s *IssueService) BulkUpdateIssues(userID uuid.UUID, request dto.BulkUpdate
 		case "resolve":
 			if issue.Status != models.StatusResolved {
 				updates["status"] = models.StatusResolved
+				updates["is_regression"] = false
+				updates["resolved_in_release"] = nil
+				updates["ignore_condition"] = nil
 				activityType = models.ActivityResolve
 				activityData = map[string]interface{}{
 					"previous_status": string(issue.Status),
@@ -504,10 +806,22 @@ func (s *IssueService) BulkUpdateIssues(userID uuid.UUID, request dto.BulkUpdate
 				if request.Resolution != nil {
 					activityData["resolution"] = *request.Resolution
 				}
+				if request.ResolvedInRelease != nil {
+					version, err := s.resolutionRelease(tx, issue.ProjectID, *request.ResolvedInRelease)
+					if err != nil {
+						response.FailedCount++
+						response.Errors = append(response.Errors, fmt.Sprintf("Failed to resolve issue %s: %v", issueID, err))
+						continue
+					}
+					updates["resolved_in_release"] = version
+					activityData["resolved_in_release"] = version
+				}
 			}
 		case "ignore":
 			if issue.Status != models.StatusIgnored {
 				updates["status"] = models.StatusIgnored
+				updates["resolved_in_release"] = nil
+				updates["ignore_condition"] = nil
 				activityType = models.ActivityIgnore
 				activityData = map[string]interface{}{
 					"previous_status": string(issue.Status),
@@ -517,6 +831,8 @@ func (s *IssueService) BulkUpdateIssues(userID uuid.UUID, request dto.BulkUpdate
 		case "unresolve":
 			if issue.Status != models.StatusUnresolved {
 				updates["status"] = models.StatusUnresolved
+				updates["resolved_in_release"] = nil
+				updates["ignore_condition"] = nil
 				activityType = models.ActivityStatusChange
 				activityData = map[string]interface{}{
 					"previous_status": string(issue.Status),
@@ -541,12 +857,25 @@ func (s *IssueService) BulkUpdateIssues(userID uuid.UUID, request dto.BulkUpdate
 		}
 		
 		if len(updates) > 0 {
+			oldStatus := issue.Status
+			oldAssigneeID := issue.AssigneeID
 			if err := tx.Model(&issue).Updates(updates).Error; err != nil {
 				response.FailedCount++
 				response.Errors = append(response.Errors, fmt.Sprintf("Failed to update issue %s: %v", issueID, err))
 				continue
 			}
 			
+			var notifyErr error
+			if activityType == models.ActivityAssignment {
+				notifyErr = s.notifyAssignment(tx, &issue, &userID, oldAssigneeID)
+			} else {
+				notifyErr = s.notifyStatusChange(tx, &issue, &userID, oldStatus)
+			}
+			if notifyErr != nil {
+				tx.Rollback()
+				return nil, notifyErr
+			}
+			
 			// Log activity
 			activityDataJSON, _ := json.Marshal(activityData)
 			activity := models.IssueActivity{
@@ -586,10 +915,22 @@ func (s *IssueService) applyIssueFilters(query *gorm.DB, filters dto.IssueFilter
 		query = query.Where("level IN ?", filters.Level)
 	}
 	
-	// Assignee filter
+	// Priority filter
+	if len(filters.Priority) > 0 {
+		query = query.Where("issues.priority IN ?", filters.Priority)
+	}
+	
+	// Regression filter
+	if filters.Regression != nil {
+		query = query.Where("issues.is_regression = ?", *filters.Regression)
+	}
+	
+	// Assignee filter; "me" is resolved to the user's ID by the handler
 	if filters.AssignedTo != nil {
-		if *filters.AssignedTo == "unassigned" {
+		if *filters.AssignedTo == "unassigned" || *filters.AssignedTo == "none" {
 			query = query.Where("assignee_id IS NULL")
+		} else if *filters.AssignedTo == "any" {
+			query = query.Where("assignee_id IS NOT NULL")
 		} else {
 			assigneeID, err := uuid.Parse(*filters.AssignedTo)
 			if err == nil {
@@ -610,17 +951,63 @@ func (s *IssueService) applyIssueFilters(query *gorm.DB, filters dto.IssueFilter
 		}
 	}
 	
-	// Environment filter (join with events)
+	// Environment filter, matching issues with events in the environment
 	if filters.Environment != nil {
-		query = query.Joins("JOIN events ON events.issue_id = issues.id").
-			Where("events.environment = ?", *filters.Environment).
-			Distinct()
+		query = query.Where("EXISTS (SELECT 1 FROM events WHERE events.issue_id = issues.id AND events.environment = ?)", *filters.Environment)
+	}
+	
+	// Transaction filter, exact or by prefix with a trailing *
+	if filters.Transaction != nil && *filters.Transaction != "" {
+		if prefix, ok := strings.CutSuffix(*filters.Transaction, "*"); ok {
+			query = query.Where("issues.transaction_name LIKE ?", prefix+"%")
+		} else {
+			query = query.Where("issues.transaction_name = ?", *filters.Transaction)
+		}
+	}
+	
+	// Release filter, matching issues with events in the selected releases
+	if filters.Release != nil && *filters.Release != "" {
+		condition, args, err := releaseFilterCondition(*filters.Release)
+		if err != nil {
+			// A version that cannot be compared matches no release
+			condition, args = "1 = 0", nil
+		}
+		query = query.Where("EXISTS (SELECT 1 FROM events JOIN releases ON releases.project_id = events.project_id AND releases.version = events.release_version "+
+			"WHERE events.issue_id = issues.id AND "+condition+")", args...)
+	}
+	
+	// Context tag filters such as os.name:Windows, and tag filters of queries and of
+	// tags[key]=value, exact or by prefix with a trailing *, matching issues with events so
+	// tagged by their tag values
+	tags := make(map[string]string, len(filters.ContextTags)+len(filters.Tags))
+	for key, value := range filters.Tags {
+		if issueQueryKey.MatchString(key) {
+			tags[key] = value
+		}
+	}
+	for key, value := range filters.ContextTags {
+		if IsContextTagKey(key) {
+			tags[key] = value
+		}
+	}
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := strings.ToLower(tags[key])
+		condition := "LOWER(issue_tag_values.value) = ?"
+		if prefix, ok := strings.CutSuffix(value, "*"); ok {
+			condition, value = "LOWER(issue_tag_values.value) LIKE ?", prefix+"%"
+		}
+		query = query.Where("EXISTS (SELECT 1 FROM issue_tag_values WHERE issue_tag_values.issue_id = issues.id AND issue_tag_values.key = ? AND "+condition+")",
+			key, indexedTagValue(value))
 	}
 	
-	// Text search
+	// Text search over titles, culprits and recent event messages
 	if filters.Search != nil && *filters.Search != "" {
-		searchTerm := "%" + strings.ToLower(*filters.Search) + "%"
-		query = query.Where("LOWER(title) LIKE ? OR LOWER(culprit) LIKE ?", searchTerm, searchTerm)
+		query = s.applyIssueSearch(query, *filters.Search)
 	}
 	
 	return query
@@ -637,11 +1024,28 @@ func (s *IssueService) applyIssueSorting(query *gorm.DB, filters dto.IssueFilter
 		sortField = "first_seen"
 	case "last_seen":
 		sortField = "last_seen"
+	case "transaction":
+		sortField = "issues.transaction_name"
+	case "priority":
+		sortField = issuePriorityOrder()
 	}
 	
 	if filters.Order == "asc" {
 		sortOrder = "ASC"
 	}
+	if filters.Sort == "relevance" && filters.Search != nil && *filters.Search != "" {
+		// Issues as relevant are listed most recently seen first
+		rank := s.issueSearchRank(*filters.Search)
+		return query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "(" + rank.SQL + ") " + sortOrder + ", last_seen DESC",
+			Vars:               rank.Vars,
+			WithoutParentheses: true,
+		}})
+	}
+	if filters.Sort == "priority" {
+		// Issues of the same priority are listed most recently seen first
+		return query.Order(fmt.Sprintf("%s %s, last_seen DESC", sortField, sortOrder))
+	}
 	
 	return query.Order(fmt.Sprintf("%s %s", sortField, sortOrder))
 }
@@ -657,7 +1061,61 @@ func (s *IssueService) getPaginationDefaults(page, limit int) (int, int) {
 }
 
 func (s *IssueService) convertIssueToResponse(issue models.Issue, includeLatestEvent bool) (*dto.IssueResponse, error) {
-	response := &dto.IssueResponse{
+	responses, err := s.convertIssuesToResponses([]models.Issue{issue}, includeLatestEvent)
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// convertIssuesToResponses converts issues, loading the comment counts and, if requested,
+// the latest events of all of them at once
+func (s *IssueService) convertIssuesToResponses(issues []models.Issue, includeLatestEvent bool) ([]dto.IssueResponse, error) {
+	issueIDs := collectIDs(issues, func(issue *models.Issue) uuid.UUID { return issue.ID })
+
+	commentCounts, err := countByKey(s.db, &models.IssueComment{}, "issue_id", issueIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var latestEvents map[uuid.UUID]models.Event
+	if includeLatestEvent {
+		latestEvents, err = latestByKey(s.db, &models.Event{}, "issue_id", "timestamp", issueIDs,
+			func(event *models.Event) uuid.UUID { return event.IssueID })
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	responses := make([]dto.IssueResponse, len(issues))
+	events := make([]models.Event, 0, len(latestEvents))
+	eventIssues := make([]int, 0, len(latestEvents))
+	for i := range issues {
+		responses[i] = newIssueResponse(&issues[i])
+		responses[i].CommentCount = int(commentCounts[issues[i].ID])
+		if event, ok := latestEvents[issues[i].ID]; ok {
+			events = append(events, event)
+			eventIssues = append(eventIssues, i)
+			responses[i].Pinned = pinnedContextValues(&event, ProjectPinnedContextKeys(issues[i].Project.PinnedContextKeys))
+		}
+	}
+
+	// Attach the latest events, loading their replays together
+	eventResponses := make([]dto.IssueEventResponse, len(events))
+	for i, event := range events {
+		eventResponses[i] = s.convertEventToResponse(event)
+	}
+	s.attachReplayReferences(eventResponses, events)
+	for i, issueIndex := range eventIssues {
+		responses[issueIndex].LatestEvent = &eventResponses[i]
+	}
+
+	return responses, nil
+}
+
+// newIssueResponse converts an issue and its preloaded assignee and project
+func newIssueResponse(issue *models.Issue) dto.IssueResponse {
+	response := dto.IssueResponse{
 		ID:          issue.ID,
 		ProjectID:   issue.ProjectID,
 		Fingerprint: issue.Fingerprint,
@@ -670,9 +1128,19 @@ func (s *IssueService) convertIssueToResponse(issue models.Issue, includeLatestE
 		LastSeen:    issue.LastSeen,
 		TimesSeen:   issue.TimesSeen,
 		AssigneeID:  issue.AssigneeID,
+		Transaction: issue.Transaction,
+		Platform:    issue.Platform,
+		IsRegression: issue.IsRegression,
+		ResolvedInRelease: issue.ResolvedInRelease,
+		GroupingVersion: issue.GroupingVersion,
+		Priority:    string(issue.Priority),
+		PriorityLocked: issue.PriorityLocked,
 		CreatedAt:   issue.CreatedAt,
 		UpdatedAt:   issue.UpdatedAt,
 	}
+	if condition := decodeIgnoreCondition(issue.IgnoreCondition); condition != nil && issue.Status == models.StatusIgnored {
+		response.IgnoreCondition = condition.response(issue)
+	}
 	
 	// Add assignee info
 	if issue.Assignee != nil {
@@ -693,34 +1161,7 @@ func (s *IssueService) convertIssueToResponse(issue models.Issue, includeLatestE
 		}
 	}
 	
-	// Get comment count
-	var commentCount int64
-	if err := s.db.Model(&models.IssueComment{}).Where("issue_id = ?", issue.ID).Count(&commentCount).Error; err == nil {
-		response.CommentCount = int(commentCount)
-	}
-	
-	// Get latest event if requested
-	if includeLatestEvent {
-		var latestEvent models.Event
-		if err := s.db.Where("issue_id = ?", issue.ID).Order("timestamp DESC").First(&latestEvent).Error; err == nil {
-			response.LatestEvent = &dto.IssueEventResponse{
-				ID:             latestEvent.ID,
-				EventID:        latestEvent.EventID,
-				Timestamp:      latestEvent.Timestamp,
-				Level:          string(latestEvent.Level),
-				Message:        latestEvent.Message,
-				ExceptionType:  latestEvent.ExceptionType,
-				ExceptionValue: latestEvent.ExceptionValue,
-				Environment:    latestEvent.Environment,
-				ReleaseVersion: latestEvent.ReleaseVersion,
-				ServerName:     latestEvent.ServerName,
-				UserContext:    latestEvent.UserContext,
-				Tags:           latestEvent.Tags,
-			}
-		}
-	}
-	
-	return response, nil
+	return response
 }
 
 func (s *IssueService) convertCommentToResponse(comment models.IssueComment) *dto.IssueCommentResponse {
@@ -731,6 +1172,17 @@ func (s *IssueService) convertCommentToResponse(comment models.IssueComment) *dt
 		Content:   comment.Content,
 		CreatedAt: comment.CreatedAt,
 		UpdatedAt: comment.UpdatedAt,
+		EditedAt:  comment.EditedAt,
+		Reactions: []dto.IssueCommentReactionResponse{},
+		Mentions:  make([]dto.IssueCommentMentionResponse, len(comment.Mentions)),
+	}
+	for i, mention := range comment.Mentions {
+		response.Mentions[i] = dto.IssueCommentMentionResponse{
+			UserID: mention.UserID,
+			Name:   mention.User.Name,
+			Email:  mention.User.Email,
+			Text:   mention.Text,
+		}
 	}
 	
 	if comment.User.ID != uuid.Nil {
@@ -769,18 +1221,69 @@ func (s *IssueService) convertActivityToResponse(activity models.IssueActivity)
 
 func (s *IssueService) convertEventToResponse(event models.Event) dto.IssueEventResponse {
 	return dto.IssueEventResponse{
-		ID:             event.ID,
-		EventID:        event.EventID,
-		Timestamp:      event.Timestamp,
-		Level:          string(event.Level),
-		Message:        event.Message,
-		ExceptionType:  event.ExceptionType,
-		ExceptionValue: event.ExceptionValue,
-		Environment:    event.Environment,
-		ReleaseVersion: event.ReleaseVersion,
-		ServerName:     event.ServerName,
-		UserContext:    event.UserContext,
-		Tags:           event.Tags,
+		ID:                event.ID,
+		EventID:           event.EventID,
+		Timestamp:         event.Timestamp,
+		OriginalTimestamp: event.OriginalTimestamp,
+		Level:             string(event.Level),
+		Platform:          event.Platform,
+		Message:           event.Message,
+		ExceptionType:     event.ExceptionType,
+		ExceptionValue:    event.ExceptionValue,
+		Exceptions:        eventExceptionChain(event),
+		Environment:       event.Environment,
+		ReleaseVersion:    event.ReleaseVersion,
+		ServerName:        event.ServerName,
+		UserContext:       event.UserContext,
+		Tags:              event.Tags,
+		Contexts:          event.Contexts,
+		Meta:              event.Meta,
+		ReplayID:          event.ReplayID,
+		Transaction:       event.Transaction,
+	}
+}
+
+// eventExceptionChain returns the exceptions of an event with their stack traces: its stored
+// exception chain, or its only exception when it has no causes
+func eventExceptionChain(event models.Event) []dto.ExceptionValue {
+	if len(event.Exceptions) > 0 {
+		var chain []dto.ExceptionValue
+		if err := json.Unmarshal(event.Exceptions, &chain); err == nil {
+			return chain
+		}
+	}
+	if event.ExceptionType == nil && event.ExceptionValue == nil {
+		return nil
+	}
+
+	exception := dto.ExceptionValue{Type: event.ExceptionType, Value: event.ExceptionValue}
+	var frames []dto.StackFrame
+	if len(event.StackTrace) > 0 && json.Unmarshal(event.StackTrace, &frames) == nil && len(frames) > 0 {
+		exception.Stacktrace = &dto.StacktraceData{Frames: frames}
+	}
+	return []dto.ExceptionValue{exception}
+}
+
+// attachReplayReferences adds the replay metadata of linked replays to event responses
+func (s *IssueService) attachReplayReferences(responses []dto.IssueEventResponse, events []models.Event) {
+	replayIDsByProject := make(map[uuid.UUID][]string)
+	for _, event := range events {
+		if event.ReplayID != nil {
+			replayIDsByProject[event.ProjectID] = append(replayIDsByProject[event.ProjectID], *event.ReplayID)
+		}
+	}
+
+	for projectID, replayIDs := range replayIDsByProject {
+		references, err := loadReplayReferences(s.db, projectID, replayIDs)
+		if err != nil {
+			log.Printf("Failed to load replay references: %v", err)
+			continue
+		}
+		for i, event := range events {
+			if event.ProjectID == projectID && event.ReplayID != nil {
+				responses[i].Replay = references[*event.ReplayID]
+			}
+		}
 	}
 }
 
@@ -805,14 +1308,76 @@ func (s *IssueService) isValidStatusTransition(from, to models.IssueStatus) bool
 	return false
 }
 
-func (s *IssueService) logStatusChangeActivity(tx *gorm.DB, issueID, userID uuid.UUID, oldStatus, newStatus string, resolution *string) error {
+// notifyStatusChange tells change listeners about a status change; issue holds the new status
+func (s *IssueService) notifyStatusChange(tx *gorm.DB, issue *models.Issue, actorID *uuid.UUID, oldStatus models.IssueStatus) error {
+	if issue.Status == oldStatus {
+		return nil
+	}
+	changeType := models.IssueSyncUpdated
+	if issue.Status == models.StatusResolved {
+		changeType = models.IssueSyncResolved
+	}
+	return s.notifyChange(tx, issue, actorID, changeType, map[string]dto.IssueSyncFieldChange{
+		"status": {From: oldStatus, To: issue.Status},
+	})
+}
+
+// notifyAssignment tells change listeners about an assignee change; issue holds the new assignee
+func (s *IssueService) notifyAssignment(tx *gorm.DB, issue *models.Issue, actorID *uuid.UUID, oldAssigneeID *uuid.UUID) error {
+	if s.uuidPtrEqual(oldAssigneeID, issue.AssigneeID) {
+		return nil
+	}
+	return s.notifyChange(tx, issue, actorID, models.IssueSyncAssigned, map[string]dto.IssueSyncFieldChange{
+		"assignee_id": {From: oldAssigneeID, To: issue.AssigneeID},
+	})
+}
+
+func (s *IssueService) notifyChange(tx *gorm.DB, issue *models.Issue, actorID *uuid.UUID, changeType string, changes map[string]dto.IssueSyncFieldChange) error {
+	for _, listener := range s.changeListeners {
+		if err := listener(tx, issue, actorID, changeType, changes); err != nil {
+			return fmt.Errorf("issue change listener failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// resolutionRelease returns the version an issue of a project is resolved in; "latest" is
+// the project's latest release
+func (s *IssueService) resolutionRelease(tx *gorm.DB, projectID uuid.UUID, release string) (string, error) {
+	release = strings.TrimSpace(release)
+	if release != "latest" {
+		return release, nil
+	}
+
+	var versions []string
+	if err := tx.Model(&models.Release{}).Where("project_id = ?", projectID).
+		Order(releaseOrder("releases")).Limit(1).Pluck("version", &versions).Error; err != nil {
+		return "", fmt.Errorf("failed to find the latest release: %w", err)
+	}
+	if len(versions) == 0 {
+		return "", ErrNoReleaseToResolveIn
+	}
+	return versions[0], nil
+}
+
+func (s *IssueService) logStatusChangeActivity(tx *gorm.DB, issueID, userID uuid.UUID, oldStatus, newStatus string, resolution, resolvedInRelease *string, ignoreCondition *ignoreCondition) error {
 	data := map[string]interface{}{
 		"previous_status": oldStatus,
 		"new_status":      newStatus,
 	}
+	if ignoreCondition != nil {
+		data["ignore_condition"] = map[string]interface{}{
+			"until":      ignoreCondition.Until,
+			"count":      ignoreCondition.Count,
+			"user_count": ignoreCondition.UserCount,
+		}
+	}
 	if resolution != nil {
 		data["resolution"] = *resolution
 	}
+	if resolvedInRelease != nil {
+		data["resolved_in_release"] = *resolvedInRelease
+	}
 	
 	var activityType models.ActivityType
 	switch newStatus {
@@ -839,9 +1404,10 @@ func (s *IssueService) logAssignmentActivity(tx *gorm.DB, issueID, userID uuid.U
 	return s.createActivity(tx, issueID, userID, models.ActivityAssignment, data)
 }
 
-func (s *IssueService) logCommentActivity(tx *gorm.DB, issueID, userID uuid.UUID) error {
+func (s *IssueService) logCommentActivity(tx *gorm.DB, issueID, userID, commentID uuid.UUID) error {
 	data := map[string]interface{}{
-		"action": "comment_added",
+		"action":     "comment_added",
+		"comment_id": commentID,
 	}
 	
 	return s.createActivity(tx, issueID, userID, models.ActivityComment, data)
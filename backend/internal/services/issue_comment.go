@@ -0,0 +1,122 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCommentNotFound     = errors.New("comment not found")
+	ErrCommentAccessDenied = errors.New("only the author and organization admins can change a comment")
+)
+
+// UpdateIssueComment replaces the content of a comment, marking it edited and keeping its
+// previous content in a comment_edited activity entry. Only the author may edit a comment,
+// unless moderator is set for organization owners and admins. Mentions are resolved again;
+// members already mentioned are not emailed again.
+func (s *IssueService) UpdateIssueComment(issueID, commentID, userID uuid.UUID, moderator bool, request dto.IssueCommentRequest) (*dto.IssueCommentResponse, error) {
+	comment, err := s.getEditableComment(issueID, commentID, userID, moderator)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.Content != request.Content {
+		var issue models.Issue
+		if err := s.db.First(&issue, issueID).Error; err != nil {
+			return nil, fmt.Errorf("failed to retrieve issue: %w", err)
+		}
+
+		previous := comment.Content
+		now := time.Now()
+		comment.Content = request.Content
+		comment.EditedAt = &now
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(comment).Updates(map[string]interface{}{
+				"content":   comment.Content,
+				"edited_at": now,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to update comment: %w", err)
+			}
+			if err := s.createActivity(tx, issueID, userID, models.ActivityCommentEdited, map[string]interface{}{
+				"comment_id":       commentID,
+				"author_id":        comment.UserID,
+				"previous_content": previous,
+			}); err != nil {
+				return fmt.Errorf("failed to log comment activity: %w", err)
+			}
+
+			if err := tx.Where("comment_id = ?", commentID).Delete(&models.IssueCommentMention{}).Error; err != nil {
+				return fmt.Errorf("failed to delete mentions: %w", err)
+			}
+			return s.recordCommentMentions(tx, &issue, comment)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.db.Preload("User").Preload("Mentions.User").First(comment, commentID).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve comment with user: %w", err)
+	}
+	response := s.convertCommentToResponse(*comment)
+	reactions, err := s.getReactionsForComment(commentID)
+	if err != nil {
+		return nil, err
+	}
+	response.Reactions = reactions
+	return response, nil
+}
+
+// DeleteIssueComment deletes a comment with its reactions and mentions, keeping its content in
+// a comment_deleted activity entry. Only the author may delete a comment, unless moderator is
+// set for organization owners and admins.
+func (s *IssueService) DeleteIssueComment(issueID, commentID, userID uuid.UUID, moderator bool) error {
+	comment, err := s.getEditableComment(issueID, commentID, userID, moderator)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("comment_id = ?", commentID).Delete(&models.IssueCommentReaction{}).Error; err != nil {
+			return fmt.Errorf("failed to delete reactions: %w", err)
+		}
+		if err := tx.Where("comment_id = ?", commentID).Delete(&models.IssueCommentMention{}).Error; err != nil {
+			return fmt.Errorf("failed to delete mentions: %w", err)
+		}
+		if err := tx.Delete(comment).Error; err != nil {
+			return fmt.Errorf("failed to delete comment: %w", err)
+		}
+		if err := s.createActivity(tx, issueID, userID, models.ActivityCommentDeleted, map[string]interface{}{
+			"comment_id": commentID,
+			"author_id":  comment.UserID,
+			"content":    comment.Content,
+			"created_at": comment.CreatedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to log comment activity: %w", err)
+		}
+		return nil
+	})
+}
+
+// getEditableComment returns a comment of the issue the user may change: their own, or any
+// when moderator is set
+func (s *IssueService) getEditableComment(issueID, commentID, userID uuid.UUID, moderator bool) (*models.IssueComment, error) {
+	var comment models.IssueComment
+	if err := s.db.Where("id = ? AND issue_id = ?", commentID, issueID).First(&comment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve comment: %w", err)
+	}
+	if comment.UserID != userID && !moderator {
+		return nil, ErrCommentAccessDenied
+	}
+	return &comment, nil
+}
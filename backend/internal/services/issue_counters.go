@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"minisentry/internal/queue"
+
+	"github.com/google/uuid"
+)
+
+// issueCounterFlushBatch is the number of issues whose counters are taken from Redis at once
+const issueCounterFlushBatch = 500
+
+// incrementIssueCountersScript adds to the buffered counters of an issue, keeping the latest
+// last_seen (in Unix milliseconds), and marks the issue as pending a flush
+const incrementIssueCountersScript = `
+redis.call('HINCRBY', KEYS[1], 'times_seen', ARGV[1])
+local last = redis.call('HGET', KEYS[1], 'last_seen')
+if not last or tonumber(last) < tonumber(ARGV[2]) then
+	redis.call('HSET', KEYS[1], 'last_seen', ARGV[2])
+end
+redis.call('SADD', KEYS[2], ARGV[3])
+return 1`
+
+// takeIssueCountersScript returns and removes the buffered counters of an issue
+const takeIssueCountersScript = `
+local counters = redis.call('HMGET', KEYS[1], 'times_seen', 'last_seen')
+redis.call('DEL', KEYS[1])
+return counters`
+
+// IssueCounterBuffer keeps the times_seen and last_seen increments of ingested events in
+// Redis and flushes them to the database periodically, taking a per-event UPDATE off the
+// ingestion path at the cost of a few seconds of staleness. Events whose increment cannot
+// reach Redis update the database directly; increments taken from Redis are lost if the
+// process dies before writing them.
+type IssueCounterBuffer struct {
+	client *queue.RedisClient
+	prefix string
+
+	// store is where counters are flushed to
+	store EventStore
+}
+
+// NewIssueCounterBuffer creates a buffer storing its keys under prefix
+func NewIssueCounterBuffer(client *queue.RedisClient, prefix string) *IssueCounterBuffer {
+	return &IssueCounterBuffer{client: client, prefix: prefix}
+}
+
+// OpenIssueCounterBuffer creates the issue counter buffer for the given backend: redis (keys
+// under keyPrefix) or "none", which updates issues as events arrive
+func OpenIssueCounterBuffer(backend, redisURL, keyPrefix string) (*IssueCounterBuffer, error) {
+	switch backend {
+	case "none", "":
+		return nil, nil
+	case "redis":
+		client, err := queue.NewRedisClient(redisURL)
+		if err != nil {
+			return nil, err
+		}
+		if err := client.Ping(); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+		}
+		return NewIssueCounterBuffer(client, keyPrefix), nil
+	default:
+		return nil, fmt.Errorf("unsupported issue counter buffer backend %q", backend)
+	}
+}
+
+// SetIssueCounterBuffer makes ingestion buffer issue counters in Redis instead of updating
+// issues for each event. It must be called before the server starts, and Flush must then
+// run periodically in the server or workers.
+func (es *ErrorService) SetIssueCounterBuffer(buffer *IssueCounterBuffer) {
+	buffer.store = es.store
+	es.store = &bufferedCounterStore{EventStore: es.store, buffer: buffer}
+	if es.batcher != nil {
+		es.batcher.store = es.store
+	}
+}
+
+// bufferedCounterStore is an event store whose issue counter increments go to Redis
+type bufferedCounterStore struct {
+	EventStore
+	buffer *IssueCounterBuffer
+}
+
+func (s *bufferedCounterStore) IncrementIssueStats(issueID uuid.UUID, count int, seenAt time.Time) error {
+	if err := s.buffer.increment(issueID, count, seenAt); err != nil {
+		log.Printf("Failed to buffer the counters of issue %s, updating it directly: %v", issueID, err)
+		return s.EventStore.IncrementIssueStats(issueID, count, seenAt)
+	}
+	return nil
+}
+
+// increment adds events seen at seenAt to the buffered counters of an issue
+func (b *IssueCounterBuffer) increment(issueID uuid.UUID, count int, seenAt time.Time) error {
+	_, err := b.client.Do("EVAL", incrementIssueCountersScript, "2",
+		b.prefix+"issue:"+issueID.String(), b.prefix+"pending",
+		strconv.Itoa(count), strconv.FormatInt(seenAt.UnixMilli(), 10), issueID.String())
+	return err
+}
+
+// Flush writes the buffered counters of the issues pending when it starts to the database.
+// Issues marked pending again during the flush wait for the next one. The flush stops at the
+// first error; counters that could not be written are put back for the next flush.
+func (b *IssueCounterBuffer) Flush(ctx context.Context) error {
+	reply, err := b.client.Do("SCARD", b.prefix+"pending")
+	if err != nil {
+		return fmt.Errorf("failed to count pending issues: %w", err)
+	}
+	remaining, _ := reply.(int64)
+
+	for remaining > 0 && ctx.Err() == nil {
+		batch := min(remaining, issueCounterFlushBatch)
+		reply, err := b.client.Do("SPOP", b.prefix+"pending", strconv.FormatInt(batch, 10))
+		if err != nil {
+			return fmt.Errorf("failed to take pending issues: %w", err)
+		}
+		pending, _ := reply.([]interface{})
+		if len(pending) == 0 {
+			break
+		}
+		remaining -= int64(len(pending))
+
+		for i, member := range pending {
+			id, _ := member.(string)
+			issueID, err := uuid.Parse(id)
+			if err != nil {
+				continue
+			}
+			if err := b.flushIssue(issueID); err != nil {
+				b.requeue(pending[i+1:])
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// requeue marks issues taken from the pending set but not flushed as pending again
+func (b *IssueCounterBuffer) requeue(members []interface{}) {
+	if len(members) == 0 {
+		return
+	}
+	args := []string{"SADD", b.prefix + "pending"}
+	for _, member := range members {
+		id, _ := member.(string)
+		args = append(args, id)
+	}
+	if _, err := b.client.Do(args...); err != nil {
+		log.Printf("Failed to requeue %d pending issue(s), their counters wait for new events: %v", len(members), err)
+	}
+}
+
+// flushIssue writes the buffered counters of an issue to the database
+func (b *IssueCounterBuffer) flushIssue(issueID uuid.UUID) error {
+	reply, err := b.client.Do("EVAL", takeIssueCountersScript, "1", b.prefix+"issue:"+issueID.String())
+	if err != nil {
+		// The issue is no longer pending, but its counters are still in Redis
+		b.client.Do("SADD", b.prefix+"pending", issueID.String())
+		return fmt.Errorf("failed to take the counters of issue %s: %w", issueID, err)
+	}
+	values, _ := reply.([]interface{})
+	if len(values) != 2 {
+		return nil
+	}
+	timesSeen, _ := values[0].(string)
+	lastSeen, _ := values[1].(string)
+	count, _ := strconv.Atoi(timesSeen)
+	millis, _ := strconv.ParseInt(lastSeen, 10, 64)
+	if count <= 0 {
+		return nil
+	}
+	seenAt := time.UnixMilli(millis).UTC()
+
+	if err := b.store.IncrementIssueStats(issueID, count, seenAt); err != nil {
+		if restoreErr := b.increment(issueID, count, seenAt); restoreErr != nil {
+			log.Printf("Lost %d event(s) of the counters of issue %s: %v", count, issueID, restoreErr)
+		}
+		return fmt.Errorf("failed to flush the counters of issue %s: %w", issueID, err)
+	}
+	return nil
+}
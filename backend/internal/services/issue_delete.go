@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+	"minisentry/internal/storage"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// issueDeletionBatchSize bounds how many queued issues are deleted per run, oldest request first
+const issueDeletionBatchSize = 100
+
+// SetAttachments makes deleting issues delete the blobs of their events' attachments
+func (s *IssueService) SetAttachments(attachments *AttachmentService) {
+	s.attachments = attachments
+}
+
+// DeleteIssue deletes an issue along with its events and their attachments, comments,
+// activity, relations, incident links, merged fingerprints and fingerprint rule, recording
+// the deletion in the audit log and an issue.deleted sync delta. The issue's earlier sync
+// deltas are kept for consumers still following the feed. Later events with the issue's
+// fingerprint open a new issue.
+func (s *IssueService) DeleteIssue(issueID, userID uuid.UUID) error {
+	return s.deleteIssue(issueID, &userID)
+}
+
+// deleteIssue deletes an issue as DeleteIssue does; a nil actor is recorded for issues whose
+// deletion was requested by a user who has since been removed
+func (s *IssueService) deleteIssue(issueID uuid.UUID, actorID *uuid.UUID) error {
+	var issue models.Issue
+	var storageKeys []string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", issueID).First(&issue).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrIssueNotFound
+			}
+			return fmt.Errorf("failed to get issue: %w", err)
+		}
+
+		events := tx.Model(&models.Event{}).Select("id").Where("issue_id = ?", issueID)
+		if err := tx.Model(&models.Attachment{}).Where("event_id IN (?)", events).
+			Pluck("storage_key", &storageKeys).Error; err != nil {
+			return fmt.Errorf("failed to get attachments: %w", err)
+		}
+		if err := tx.Where("event_id IN (?)", events).Delete(&models.Attachment{}).Error; err != nil {
+			return fmt.Errorf("failed to delete attachments: %w", err)
+		}
+		if err := tx.Where("issue_id = ?", issueID).Delete(&models.EventTag{}).Error; err != nil {
+			return fmt.Errorf("failed to delete event tags: %w", err)
+		}
+		result := tx.Where("issue_id = ?", issueID).Delete(&models.Event{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete events: %w", result.Error)
+		}
+		deletedEvents := result.RowsAffected
+
+		comments := tx.Model(&models.IssueComment{}).Select("id").Where("issue_id = ?", issueID)
+		if err := tx.Where("comment_id IN (?)", comments).Delete(&models.IssueCommentReaction{}).Error; err != nil {
+			return fmt.Errorf("failed to delete comment reactions: %w", err)
+		}
+		if err := tx.Where("comment_id IN (?)", comments).Delete(&models.IssueCommentMention{}).Error; err != nil {
+			return fmt.Errorf("failed to delete comment mentions: %w", err)
+		}
+		for _, dependent := range []struct {
+			model interface{}
+			name  string
+		}{
+			{&models.IssueComment{}, "comments"},
+			{&models.IssueActivity{}, "activity"},
+			{&models.IncidentIssue{}, "incident links"},
+			{&models.IssueMergedFingerprint{}, "merged fingerprints"},
+			{&models.IssueFingerprintRule{}, "fingerprint rule"},
+			{&models.IssueTagValue{}, "tag values"},
+			{&models.IssueSubscription{}, "subscriptions"},
+		} {
+			if err := tx.Where("issue_id = ?", issueID).Delete(dependent.model).Error; err != nil {
+				return fmt.Errorf("failed to delete %s: %w", dependent.name, err)
+			}
+		}
+		if err := tx.Where("source_issue_id = ? OR target_issue_id = ?", issueID, issueID).
+			Delete(&models.IssueRelation{}).Error; err != nil {
+			return fmt.Errorf("failed to delete relations: %w", err)
+		}
+		if err := tx.Delete(&issue).Error; err != nil {
+			return fmt.Errorf("failed to delete issue: %w", err)
+		}
+		if err := s.notifyChange(tx, &issue, actorID, models.IssueSyncDeleted, nil); err != nil {
+			return err
+		}
+
+		return recordAudit(tx, actorID, models.AuditIssueDeleted, "issue", issue.ID, &issue.ProjectID, map[string]interface{}{
+			"title":       issue.Title,
+			"fingerprint": issue.Fingerprint,
+			"times_seen":  issue.TimesSeen,
+			"events":      deletedEvents,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.forgetFingerprintRules(issue.ProjectID)
+	if s.attachments != nil {
+		s.attachments.deleteBlobs(storageKeys)
+	}
+	return nil
+}
+
+// queueIssueDeletions queues issues for deletion by DeleteQueuedIssues, for the delete bulk
+// action. Issues already queued keep their place.
+func (s *IssueService) queueIssueDeletions(userID uuid.UUID, issueIDs []uuid.UUID) (*dto.BulkUpdateIssuesResponse, error) {
+	response := &dto.BulkUpdateIssuesResponse{
+		UpdatedIDs: make([]uuid.UUID, 0),
+		Errors:     make([]string, 0),
+	}
+
+	var found []uuid.UUID
+	if err := s.db.Model(&models.Issue{}).Where("id IN ?", issueIDs).Pluck("id", &found).Error; err != nil {
+		return nil, fmt.Errorf("failed to get issues: %w", err)
+	}
+	exists := make(map[uuid.UUID]bool, len(found))
+	for _, id := range found {
+		exists[id] = true
+	}
+	for _, issueID := range issueIDs {
+		if !exists[issueID] {
+			response.FailedCount++
+			response.Errors = append(response.Errors, fmt.Sprintf("Issue %s not found", issueID))
+			continue
+		}
+		response.UpdatedCount++
+		response.UpdatedIDs = append(response.UpdatedIDs, issueID)
+	}
+	if len(found) == 0 {
+		return response, nil
+	}
+
+	if err := s.db.Model(&models.Issue{}).
+		Where("id IN ? AND deletion_requested_at IS NULL", found).
+		Updates(map[string]interface{}{
+			"deletion_requested_at":    time.Now(),
+			"deletion_requested_by_id": userID,
+		}).Error; err != nil {
+		return nil, fmt.Errorf("failed to queue issue deletions: %w", err)
+	}
+	return response, nil
+}
+
+// liveIssueIDs selects the IDs of the issues not queued for deletion, for queries that must
+// leave out queued issues as issue lists do
+func liveIssueIDs(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.Issue{}).Select("id").Where("deletion_requested_at IS NULL")
+}
+
+// DeleteQueuedIssues deletes the issues queued by the delete bulk action, each in its own
+// transaction. Issues that fail to be deleted are logged and retried on the next run.
+func (s *IssueService) DeleteQueuedIssues(ctx context.Context) error {
+	var issues []models.Issue
+	if err := s.db.WithContext(ctx).Select("id", "deletion_requested_by_id").
+		Where("deletion_requested_at IS NOT NULL").
+		Order("deletion_requested_at ASC").
+		Limit(issueDeletionBatchSize).
+		Find(&issues).Error; err != nil {
+		return fmt.Errorf("failed to get queued issue deletions: %w", err)
+	}
+
+	for _, issue := range issues {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err := s.deleteIssue(issue.ID, issue.DeletionRequestedByID); err != nil && !errors.Is(err, ErrIssueNotFound) {
+			log.Printf("Failed to delete queued issue %s: %v", issue.ID, err)
+		}
+	}
+	return nil
+}
+
+// deleteBlobs deletes the contents of deleted attachments. Failures are logged: the blobs
+// are no longer reachable either way.
+func (as *AttachmentService) deleteBlobs(keys []string) {
+	for _, key := range keys {
+		if err := as.blobs.Delete(key); err != nil && !errors.Is(err, storage.ErrBlobNotFound) {
+			log.Printf("Failed to delete attachment blob %s: %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrIssueFingerprintRuleInvalid  = errors.New("invalid fingerprint rule")
+	ErrIssueFingerprintRuleNotFound = errors.New("fingerprint rule not found")
+	ErrIssueFingerprintRuleKeyTaken = errors.New("fingerprint rule key already used by another issue of the project")
+)
+
+const (
+	maxFingerprintRuleKeyLength     = 200
+	maxFingerprintRulePatternLength = 500
+)
+
+// compiledFingerprintRule is a fingerprint rule as ingestion matches it; nil patterns match
+// any event
+type compiledFingerprintRule struct {
+	fingerprint   string // of the rule's issue
+	exceptionType *regexp.Regexp
+	message       *regexp.Regexp
+	transaction   *regexp.Regexp
+}
+
+type cachedFingerprintRules struct {
+	rules   []compiledFingerprintRule
+	expires time.Time
+}
+
+// SetGroupingConfigs makes fingerprint rule changes apply to the events this server ingests
+// without waiting for its cached rules to expire
+func (s *IssueService) SetGroupingConfigs(groupingConfigs *GroupingConfigService) {
+	s.groupingConfigs = groupingConfigs
+}
+
+// GetIssueFingerprintRule returns the rule folding matching future events into the issue
+func (s *IssueService) GetIssueFingerprintRule(issueID uuid.UUID) (*dto.IssueFingerprintRuleResponse, error) {
+	var rule models.IssueFingerprintRule
+	if err := s.db.Where("issue_id = ?", issueID).First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrIssueFingerprintRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to get fingerprint rule: %w", err)
+	}
+	return convertFingerprintRuleToResponse(&rule), nil
+}
+
+// SetIssueFingerprintRule makes the future events of the issue's project that match the
+// request's patterns group into the issue, replacing the issue's previous rule
+func (s *IssueService) SetIssueFingerprintRule(issueID, userID uuid.UUID, request dto.IssueFingerprintRuleRequest) (*dto.IssueFingerprintRuleResponse, error) {
+	key := strings.TrimSpace(request.Key)
+	switch {
+	case key == "":
+		return nil, fmt.Errorf("%w: key is required", ErrIssueFingerprintRuleInvalid)
+	case len(key) > maxFingerprintRuleKeyLength:
+		return nil, fmt.Errorf("%w: key must be at most %d characters", ErrIssueFingerprintRuleInvalid, maxFingerprintRuleKeyLength)
+	}
+	patterns := map[string]*string{
+		"exception_type": request.ExceptionType,
+		"message":        request.Message,
+		"transaction":    request.Transaction,
+	}
+	set := 0
+	for name, pattern := range patterns {
+		if pattern == nil {
+			continue
+		}
+		trimmed := strings.TrimSpace(*pattern)
+		switch {
+		case trimmed == "":
+			patterns[name] = nil
+			continue
+		case len(trimmed) > maxFingerprintRulePatternLength:
+			return nil, fmt.Errorf("%w: %s must be at most %d characters", ErrIssueFingerprintRuleInvalid, name, maxFingerprintRulePatternLength)
+		}
+		if _, err := compileInboundFilterPattern(trimmed); err != nil {
+			return nil, fmt.Errorf("%w: invalid regular expression %s", ErrIssueFingerprintRuleInvalid, trimmed)
+		}
+		patterns[name] = &trimmed
+		set++
+	}
+	if set == 0 {
+		return nil, fmt.Errorf("%w: at least one of exception_type, message and transaction is required", ErrIssueFingerprintRuleInvalid)
+	}
+
+	var rule models.IssueFingerprintRule
+	var projectID uuid.UUID
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var issue models.Issue
+		if err := tx.Where("id = ?", issueID).First(&issue).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrIssueNotFound
+			}
+			return fmt.Errorf("failed to get issue: %w", err)
+		}
+		projectID = issue.ProjectID
+
+		var taken int64
+		if err := tx.Model(&models.IssueFingerprintRule{}).
+			Where("project_id = ? AND key = ? AND issue_id <> ?", issue.ProjectID, key, issueID).
+			Count(&taken).Error; err != nil {
+			return fmt.Errorf("failed to get fingerprint rules: %w", err)
+		}
+		if taken > 0 {
+			return ErrIssueFingerprintRuleKeyTaken
+		}
+
+		err := tx.Where("issue_id = ?", issueID).First(&rule).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			rule = models.IssueFingerprintRule{ProjectID: issue.ProjectID, IssueID: issueID, CreatedByID: &userID}
+		case err != nil:
+			return fmt.Errorf("failed to get fingerprint rule: %w", err)
+		}
+		rule.Key = key
+		rule.ExceptionType = patterns["exception_type"]
+		rule.Message = patterns["message"]
+		rule.Transaction = patterns["transaction"]
+		if err := tx.Save(&rule).Error; err != nil {
+			return fmt.Errorf("failed to save fingerprint rule: %w", err)
+		}
+
+		return s.createActivity(tx, issueID, userID, models.ActivityFingerprintRule, map[string]interface{}{
+			"action":         "set",
+			"key":            rule.Key,
+			"exception_type": rule.ExceptionType,
+			"message":        rule.Message,
+			"transaction":    rule.Transaction,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.forgetFingerprintRules(projectID)
+	return convertFingerprintRuleToResponse(&rule), nil
+}
+
+// DeleteIssueFingerprintRule stops folding matching future events into the issue; events
+// already folded stay in it
+func (s *IssueService) DeleteIssueFingerprintRule(issueID, userID uuid.UUID) error {
+	var rule models.IssueFingerprintRule
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("issue_id = ?", issueID).First(&rule).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrIssueFingerprintRuleNotFound
+			}
+			return fmt.Errorf("failed to get fingerprint rule: %w", err)
+		}
+		if err := tx.Delete(&rule).Error; err != nil {
+			return fmt.Errorf("failed to delete fingerprint rule: %w", err)
+		}
+		return s.createActivity(tx, issueID, userID, models.ActivityFingerprintRule, map[string]interface{}{
+			"action": "removed",
+			"key":    rule.Key,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.forgetFingerprintRules(rule.ProjectID)
+	return nil
+}
+
+// moveFingerprintRules hands the rule of one of the issues merged into the primary issue to
+// it, unless it has its own; the other rules of the merged issues are deleted
+func moveFingerprintRules(tx *gorm.DB, primaryID uuid.UUID, mergedIDs []uuid.UUID) error {
+	var own int64
+	if err := tx.Model(&models.IssueFingerprintRule{}).Where("issue_id = ?", primaryID).Count(&own).Error; err != nil {
+		return fmt.Errorf("failed to get fingerprint rules: %w", err)
+	}
+	if own == 0 {
+		var rules []models.IssueFingerprintRule
+		if err := tx.Where("issue_id IN ?", mergedIDs).Order("created_at ASC").Limit(1).Find(&rules).Error; err != nil {
+			return fmt.Errorf("failed to get fingerprint rules: %w", err)
+		}
+		if len(rules) > 0 {
+			if err := tx.Model(&rules[0]).Update("issue_id", primaryID).Error; err != nil {
+				return fmt.Errorf("failed to move fingerprint rule: %w", err)
+			}
+		}
+	}
+	if err := tx.Where("issue_id IN ?", mergedIDs).Delete(&models.IssueFingerprintRule{}).Error; err != nil {
+		return fmt.Errorf("failed to delete fingerprint rules: %w", err)
+	}
+	return nil
+}
+
+func (s *IssueService) forgetFingerprintRules(projectID uuid.UUID) {
+	if s.groupingConfigs != nil {
+		s.groupingConfigs.forgetRules(projectID)
+	}
+}
+
+// forgetRules drops the cached fingerprint rules of a project, so the next event reloads them
+func (gcs *GroupingConfigService) forgetRules(projectID uuid.UUID) {
+	gcs.mu.Lock()
+	delete(gcs.rules, projectID)
+	gcs.mu.Unlock()
+}
+
+// ruleFingerprint returns the fingerprint of the issue whose fingerprint rule the event
+// matches, the oldest rule first; false when it matches none
+func (gcs *GroupingConfigService) ruleFingerprint(data *dto.NormalizedErrorData) (string, bool) {
+	for _, rule := range gcs.projectRules(data.ProjectID) {
+		if rule.matches(data) {
+			return rule.fingerprint, true
+		}
+	}
+	return "", false
+}
+
+func (r *compiledFingerprintRule) matches(data *dto.NormalizedErrorData) bool {
+	if r.exceptionType != nil && (data.ExceptionType == nil || !r.exceptionType.MatchString(*data.ExceptionType)) {
+		return false
+	}
+	if r.transaction != nil && (data.Transaction == nil || !r.transaction.MatchString(*data.Transaction)) {
+		return false
+	}
+	if r.message != nil {
+		for _, message := range eventMessages(data) {
+			if r.message.MatchString(message) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// projectRules returns a project's fingerprint rules from the cache, loading them when
+// missing or expired. No rules apply when they cannot be loaded.
+func (gcs *GroupingConfigService) projectRules(projectID uuid.UUID) []compiledFingerprintRule {
+	now := time.Now()
+
+	gcs.mu.Lock()
+	cached, ok := gcs.rules[projectID]
+	gcs.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.rules
+	}
+
+	var rules []models.IssueFingerprintRule
+	if err := gcs.db.Preload("Issue").Where("project_id = ?", projectID).Order("created_at ASC").Find(&rules).Error; err != nil {
+		log.Printf("Failed to load fingerprint rules of project %s: %v", projectID, err)
+		return nil
+	}
+	cached = &cachedFingerprintRules{expires: now.Add(groupingConfigCacheTTL)}
+	for i := range rules {
+		if rule, ok := compileFingerprintRule(&rules[i]); ok {
+			cached.rules = append(cached.rules, rule)
+		}
+	}
+
+	gcs.mu.Lock()
+	if len(gcs.rules) >= projectAccessCacheSweepSize {
+		for id, entry := range gcs.rules {
+			if now.After(entry.expires) {
+				delete(gcs.rules, id)
+			}
+		}
+	}
+	gcs.rules[projectID] = cached
+	gcs.mu.Unlock()
+	return cached.rules
+}
+
+// compileFingerprintRule compiles the patterns of a stored rule, which were validated when
+// saved; false if one no longer compiles
+func compileFingerprintRule(rule *models.IssueFingerprintRule) (compiledFingerprintRule, bool) {
+	compiled := compiledFingerprintRule{fingerprint: rule.Issue.Fingerprint}
+	for _, pattern := range []struct {
+		source *string
+		re     **regexp.Regexp
+	}{
+		{rule.ExceptionType, &compiled.exceptionType},
+		{rule.Message, &compiled.message},
+		{rule.Transaction, &compiled.transaction},
+	} {
+		if pattern.source == nil {
+			continue
+		}
+		re, err := compileInboundFilterPattern(*pattern.source)
+		if err != nil {
+			return compiledFingerprintRule{}, false
+		}
+		*pattern.re = re
+	}
+	return compiled, true
+}
+
+func convertFingerprintRuleToResponse(rule *models.IssueFingerprintRule) *dto.IssueFingerprintRuleResponse {
+	return &dto.IssueFingerprintRuleResponse{
+		IssueID:       rule.IssueID,
+		Key:           rule.Key,
+		ExceptionType: rule.ExceptionType,
+		Message:       rule.Message,
+		Transaction:   rule.Transaction,
+		CreatedByID:   rule.CreatedByID,
+		CreatedAt:     rule.CreatedAt,
+		UpdatedAt:     rule.UpdatedAt,
+	}
+}
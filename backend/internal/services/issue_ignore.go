@@ -0,0 +1,198 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"gorm.io/datatypes"
+)
+
+var ErrIgnoreConditionInvalid = errors.New("invalid ignore condition")
+
+const (
+	maxIgnoreCount     = 1000000
+	maxIgnoreUserCount = 1000 // bounds the users an ignored issue remembers
+
+	// maxIgnoreConditionUpdates bounds the attempts at recording a user in an ignore condition
+	// changed meanwhile by concurrent events
+	maxIgnoreConditionUpdates = 10
+)
+
+// ignoreCondition is when an ignored issue is unignored by the events it receives, as stored
+// with the issue; whichever of its limits is reached first unignores it
+type ignoreCondition struct {
+	Until     *time.Time `json:"until,omitempty"`
+	Count     *int       `json:"count,omitempty"`      // Events received since ignored
+	UserCount *int       `json:"user_count,omitempty"` // Users affected since ignored
+	TimesSeen int        `json:"times_seen"`           // Of the issue when ignored
+	Users     []string   `json:"users,omitempty"`      // Hashes of the users affected since ignored
+}
+
+// newIgnoreCondition builds the condition an update ignoring an issue asks for; nil when it
+// asks for none, so the issue stays ignored until changed by hand
+func newIgnoreCondition(issue *models.Issue, request dto.IssueUpdateRequest) (*ignoreCondition, error) {
+	if request.IgnoreUntil == nil && request.IgnoreCount == nil && request.IgnoreUserCount == nil {
+		return nil, nil
+	}
+	switch {
+	case request.IgnoreUntil != nil && !request.IgnoreUntil.After(time.Now()):
+		return nil, fmt.Errorf("%w: ignore_until must be in the future", ErrIgnoreConditionInvalid)
+	case request.IgnoreCount != nil && (*request.IgnoreCount < 1 || *request.IgnoreCount > maxIgnoreCount):
+		return nil, fmt.Errorf("%w: ignore_count must be between 1 and %d", ErrIgnoreConditionInvalid, maxIgnoreCount)
+	case request.IgnoreUserCount != nil && (*request.IgnoreUserCount < 1 || *request.IgnoreUserCount > maxIgnoreUserCount):
+		return nil, fmt.Errorf("%w: ignore_user_count must be between 1 and %d", ErrIgnoreConditionInvalid, maxIgnoreUserCount)
+	}
+	return &ignoreCondition{
+		Until:     request.IgnoreUntil,
+		Count:     request.IgnoreCount,
+		UserCount: request.IgnoreUserCount,
+		TimesSeen: issue.TimesSeen,
+	}, nil
+}
+
+// decodeIgnoreCondition returns the condition stored with an ignored issue; nil when unset
+// or unreadable
+func decodeIgnoreCondition(stored datatypes.JSON) *ignoreCondition {
+	if len(stored) == 0 || string(stored) == "null" {
+		return nil
+	}
+	var condition ignoreCondition
+	if err := json.Unmarshal(stored, &condition); err != nil {
+		return nil
+	}
+	return &condition
+}
+
+func (c *ignoreCondition) encode() (datatypes.JSON, error) {
+	if c == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode ignore condition: %w", err)
+	}
+	return datatypes.JSON(encoded), nil
+}
+
+func (c *ignoreCondition) response(issue *models.Issue) *dto.IgnoreConditionResponse {
+	return &dto.IgnoreConditionResponse{
+		Until:         c.Until,
+		Count:         c.Count,
+		UserCount:     c.UserCount,
+		EventsSeen:    issue.TimesSeen - c.TimesSeen,
+		UsersAffected: len(c.Users),
+	}
+}
+
+// eventUserKey identifies the user an event affected by the first of the user's id, email,
+// username and IP address it has, hashed so ignored issues do not keep them; empty when the
+// event has none
+func eventUserKey(user *dto.UserContext) string {
+	if user == nil {
+		return ""
+	}
+	for _, field := range []struct {
+		name  string
+		value *string
+	}{
+		{"id", user.ID}, {"email", user.Email}, {"username", user.Username}, {"ip", user.IPAddress},
+	} {
+		if field.value != nil && *field.value != "" {
+			hash := sha256.Sum256([]byte(field.name + ":" + *field.value))
+			return hex.EncodeToString(hash[:16])
+		}
+	}
+	return ""
+}
+
+// checkIgnoreCondition unignores an ignored issue receiving an event when the event reaches
+// the limit of its ignore condition, otherwise records the user the event affected when the
+// condition counts them. Counts of events flushed late by the issue counter buffer are only
+// seen once flushed.
+func (es *ErrorService) checkIgnoreCondition(issue *models.Issue, normalizedData *dto.NormalizedErrorData) error {
+	condition := decodeIgnoreCondition(issue.IgnoreCondition)
+	if condition == nil {
+		return nil
+	}
+
+	reason := ""
+	switch {
+	case condition.Until != nil && !time.Now().Before(*condition.Until):
+		reason = "ignore_until"
+	case condition.Count != nil && issue.TimesSeen+1-condition.TimesSeen >= *condition.Count:
+		reason = "ignore_count"
+	case condition.UserCount != nil:
+		key := eventUserKey(normalizedData.UserContext)
+		if key == "" {
+			return nil
+		}
+		reached, err := es.recordIgnoredIssueUser(issue, key)
+		if err != nil || !reached {
+			return err
+		}
+		reason = "ignore_user_count"
+	default:
+		return nil
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"previous_status": string(models.StatusIgnored),
+		"new_status":      string(models.StatusUnresolved),
+		"reason":          reason,
+		"event_id":        normalizedData.EventID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal activity data: %w", err)
+	}
+	unignored, err := es.store.UnignoreIssue(issue, data)
+	if err != nil || !unignored {
+		return err
+	}
+	issue.Status = models.StatusUnresolved
+	issue.IgnoreCondition = nil
+	return nil
+}
+
+// recordIgnoredIssueUser adds the user an event affected to the users counted by the ignore
+// condition of an ignored issue, reporting whether the condition's user count is reached.
+// The condition is swapped only if unchanged since read, and read again when concurrent
+// events changed it, so none of their users is lost.
+func (es *ErrorService) recordIgnoredIssueUser(issue *models.Issue, key string) (bool, error) {
+	stored := []byte(issue.IgnoreCondition)
+	for attempt := 0; attempt < maxIgnoreConditionUpdates; attempt++ {
+		condition := decodeIgnoreCondition(stored)
+		if condition == nil || condition.UserCount == nil || slices.Contains(condition.Users, key) {
+			return false, nil
+		}
+		condition.Users = append(condition.Users, key)
+		if len(condition.Users) >= *condition.UserCount {
+			return true, nil
+		}
+
+		encoded, err := condition.encode()
+		if err != nil {
+			return false, err
+		}
+		updated, err := es.store.UpdateIgnoreCondition(issue.ID, stored, encoded)
+		if err != nil {
+			return false, err
+		}
+		if updated {
+			issue.IgnoreCondition = encoded
+			return false, nil
+		}
+
+		if stored, err = es.store.GetIgnoreCondition(issue.ID); err != nil {
+			return false, err
+		}
+	}
+	return false, fmt.Errorf("ignore condition of issue %s kept changing", issue.ID)
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxCommentMentions bounds the mentions of a comment that are resolved
+const maxCommentMentions = 20
+
+// commentMentionPattern matches @email and @name mentions, such as @jane@example.com or
+// @JaneDoe, that do not follow a word character, as in email addresses written in comments
+var commentMentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w.+\-]+(?:@[\w\-]+(?:\.[\w\-]+)+)?)`)
+
+// IssueMentionListener is called inside the transaction adding a comment, with the members
+// the comment mentions; returning an error rolls the comment back
+type IssueMentionListener func(tx *gorm.DB, issue *models.Issue, comment *models.IssueComment, mentioned []models.User) error
+
+// OnIssueMention registers a listener for members mentioned in comments.
+// Listeners must not be registered after the server has started.
+func (s *IssueService) OnIssueMention(listener IssueMentionListener) {
+	s.mentionListeners = append(s.mentionListeners, listener)
+}
+
+// parseCommentMentions returns the distinct mentions of a comment's content as written,
+// without the @ and trailing punctuation
+func parseCommentMentions(content string) []string {
+	var mentions []string
+	seen := make(map[string]bool)
+	for _, match := range commentMentionPattern.FindAllStringSubmatch(content, -1) {
+		mention := strings.TrimRight(match[1], ".-+")
+		key := strings.ToLower(mention)
+		if mention == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		mentions = append(mentions, mention)
+		if len(mentions) == maxCommentMentions {
+			break
+		}
+	}
+	return mentions
+}
+
+// mentionName is how a name is written in mentions: lowercase, without spaces, so Jane Doe
+// is @JaneDoe or @janedoe
+func mentionName(name string) string {
+	return strings.ToLower(strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, name))
+}
+
+// recordCommentMentions stores the members of the issue's organization a comment mentions by
+// email or by name, and tells mention listeners about them. Mentions of other users, and of
+// names several members share, are left as plain text.
+func (s *IssueService) recordCommentMentions(tx *gorm.DB, issue *models.Issue, comment *models.IssueComment) error {
+	mentions := parseCommentMentions(comment.Content)
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	var members []models.User
+	if err := tx.Model(&models.User{}).
+		Joins("JOIN organization_members ON organization_members.user_id = users.id").
+		Joins("JOIN projects ON projects.organization_id = organization_members.organization_id").
+		Where("projects.id = ? AND users.is_active = ?", issue.ProjectID, true).
+		Find(&members).Error; err != nil {
+		return fmt.Errorf("failed to retrieve organization members: %w", err)
+	}
+	byEmail := make(map[string]*models.User, len(members))
+	byName := make(map[string][]*models.User, len(members))
+	for i := range members {
+		member := &members[i]
+		byEmail[strings.ToLower(member.Email)] = member
+		if name := mentionName(member.Name); name != "" {
+			byName[name] = append(byName[name], member)
+		}
+	}
+
+	var mentioned []models.User
+	records := make([]models.IssueCommentMention, 0, len(mentions))
+	seen := make(map[uuid.UUID]bool)
+	for _, mention := range mentions {
+		member, ok := byEmail[strings.ToLower(mention)]
+		if !ok && len(byName[mentionName(mention)]) == 1 {
+			member, ok = byName[mentionName(mention)][0], true
+		}
+		if !ok || seen[member.ID] {
+			continue
+		}
+		seen[member.ID] = true
+		mentioned = append(mentioned, *member)
+		records = append(records, models.IssueCommentMention{
+			CommentID: comment.ID,
+			UserID:    member.ID,
+			Text:      mention,
+		})
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	if err := tx.Create(&records).Error; err != nil {
+		return fmt.Errorf("failed to record mentions: %w", err)
+	}
+	for _, listener := range s.mentionListeners {
+		if err := listener(tx, issue, comment, mentioned); err != nil {
+			return fmt.Errorf("issue mention listener failed: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,443 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrIssueMergeInvalid   = errors.New("invalid issue merge")
+	ErrIssueUnmergeInvalid = errors.New("invalid issue unmerge")
+)
+
+const (
+	// MaxMergedIssues bounds how many issues one merge folds into the primary issue, and how
+	// many merged fingerprints one unmerge splits off
+	MaxMergedIssues = 100
+	// MaxUnmergedEvents bounds how many events one unmerge splits off
+	MaxUnmergedEvents = 1000
+)
+
+// MergeIssues folds issues of the primary issue's project into it: their events, comments,
+// incident links and fingerprint rule move to the primary issue, their counts add up to its own, and they are
+// deleted. Their fingerprints are remembered so later events with them are grouped into the
+// primary issue.
+func (s *IssueService) MergeIssues(userID uuid.UUID, request dto.MergeIssuesRequest) (*dto.MergeIssuesResponse, error) {
+	mergedIDs := make([]uuid.UUID, 0, len(request.IssueIDs))
+	seen := map[uuid.UUID]bool{request.PrimaryIssueID: true}
+	for _, issueID := range request.IssueIDs {
+		if !seen[issueID] {
+			seen[issueID] = true
+			mergedIDs = append(mergedIDs, issueID)
+		}
+	}
+	if len(mergedIDs) == 0 {
+		return nil, fmt.Errorf("%w: at least one issue other than the primary issue is required", ErrIssueMergeInvalid)
+	}
+	if len(mergedIDs) > MaxMergedIssues {
+		return nil, fmt.Errorf("%w: at most %d issues can be merged at once", ErrIssueMergeInvalid, MaxMergedIssues)
+	}
+
+	var fingerprints []string
+	var projectID uuid.UUID
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var primary models.Issue
+		if err := tx.Where("id = ?", request.PrimaryIssueID).First(&primary).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrIssueNotFound
+			}
+			return fmt.Errorf("failed to get primary issue: %w", err)
+		}
+		if primary.DeletionRequestedAt != nil {
+			return fmt.Errorf("%w: issue %s is queued for deletion", ErrIssueMergeInvalid, primary.ID)
+		}
+
+		var merged []models.Issue
+		if err := tx.Where("id IN ?", mergedIDs).Find(&merged).Error; err != nil {
+			return fmt.Errorf("failed to get merged issues: %w", err)
+		}
+		if len(merged) != len(mergedIDs) {
+			return ErrIssueNotFound
+		}
+
+		oldTimesSeen := primary.TimesSeen
+		mappings := make([]models.IssueMergedFingerprint, 0, len(merged))
+		for _, issue := range merged {
+			if issue.ProjectID != primary.ProjectID {
+				return fmt.Errorf("%w: issues of different projects cannot be merged", ErrIssueMergeInvalid)
+			}
+			if issue.DeletionRequestedAt != nil {
+				return fmt.Errorf("%w: issue %s is queued for deletion", ErrIssueMergeInvalid, issue.ID)
+			}
+			primary.TimesSeen += issue.TimesSeen
+			if issue.FirstSeen.Before(primary.FirstSeen) {
+				primary.FirstSeen = issue.FirstSeen
+			}
+			if issue.LastSeen.After(primary.LastSeen) {
+				primary.LastSeen = issue.LastSeen
+			}
+			mappings = append(mappings, models.IssueMergedFingerprint{
+				ProjectID:     primary.ProjectID,
+				Fingerprint:   issue.Fingerprint,
+				IssueID:       primary.ID,
+				MergedIssueID: issue.ID,
+				Title:         issue.Title,
+				MergedByID:    &userID,
+			})
+			fingerprints = append(fingerprints, issue.Fingerprint)
+		}
+
+		// Fingerprints merged into the merged issues earlier follow them into the primary issue
+		var inherited []string
+		if err := tx.Model(&models.IssueMergedFingerprint{}).Where("issue_id IN ?", mergedIDs).
+			Pluck("fingerprint", &inherited).Error; err != nil {
+			return fmt.Errorf("failed to get merged fingerprints: %w", err)
+		}
+		fingerprints = append(fingerprints, inherited...)
+		if err := tx.Model(&models.IssueMergedFingerprint{}).Where("issue_id IN ?", mergedIDs).
+			Update("issue_id", primary.ID).Error; err != nil {
+			return fmt.Errorf("failed to move merged fingerprints: %w", err)
+		}
+		if err := tx.Create(&mappings).Error; err != nil {
+			return fmt.Errorf("failed to record merged fingerprints: %w", err)
+		}
+
+		if err := tx.Model(&models.Event{}).Where("issue_id IN ?", mergedIDs).
+			Update("issue_id", primary.ID).Error; err != nil {
+			return fmt.Errorf("failed to move events: %w", err)
+		}
+		if err := reindexIssueTags(tx, append([]uuid.UUID{primary.ID}, mergedIDs...)); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.IssueComment{}).Where("issue_id IN ?", mergedIDs).
+			Update("issue_id", primary.ID).Error; err != nil {
+			return fmt.Errorf("failed to move comments: %w", err)
+		}
+		if err := moveIncidentLinks(tx, primary.ID, mergedIDs); err != nil {
+			return err
+		}
+		if err := moveFingerprintRules(tx, primary.ID, mergedIDs); err != nil {
+			return err
+		}
+		if err := moveIssueSubscriptions(tx, primary.ID, mergedIDs); err != nil {
+			return err
+		}
+		projectID = primary.ProjectID
+
+		// The relations, activity and sync deltas of the merged issues go with them
+		if err := tx.Where("source_issue_id IN ? OR target_issue_id IN ?", mergedIDs, mergedIDs).
+			Delete(&models.IssueRelation{}).Error; err != nil {
+			return fmt.Errorf("failed to delete relations: %w", err)
+		}
+		if err := tx.Where("issue_id IN ?", mergedIDs).Delete(&models.IssueActivity{}).Error; err != nil {
+			return fmt.Errorf("failed to delete activity: %w", err)
+		}
+		if err := tx.Where("issue_id IN ?", mergedIDs).Delete(&models.IssueSyncDelta{}).Error; err != nil {
+			return fmt.Errorf("failed to delete sync deltas: %w", err)
+		}
+		if err := tx.Where("id IN ?", mergedIDs).Delete(&models.Issue{}).Error; err != nil {
+			return fmt.Errorf("failed to delete merged issues: %w", err)
+		}
+
+		if err := tx.Model(&primary).Updates(map[string]interface{}{
+			"times_seen": primary.TimesSeen,
+			"first_seen": primary.FirstSeen,
+			"last_seen":  primary.LastSeen,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to update primary issue: %w", err)
+		}
+
+		if err := s.createActivity(tx, primary.ID, userID, models.ActivityMerge, map[string]interface{}{
+			"merged_issue_ids": mergedIDs,
+			"fingerprints":     fingerprints,
+		}); err != nil {
+			return fmt.Errorf("failed to log merge activity: %w", err)
+		}
+		return s.notifyChange(tx, &primary, &userID, models.IssueSyncUpdated, map[string]dto.IssueSyncFieldChange{
+			"times_seen": {From: oldTimesSeen, To: primary.TimesSeen},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.forgetFingerprintRules(projectID)
+
+	issue, err := s.GetIssue(request.PrimaryIssueID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.MergeIssuesResponse{Issue: issue, MergedIssueIDs: mergedIDs, Fingerprints: fingerprints}, nil
+}
+
+// moveIncidentLinks links the incidents of the merged issues to the primary issue, dropping
+// the links to incidents it is already part of
+func moveIncidentLinks(tx *gorm.DB, primaryID uuid.UUID, mergedIDs []uuid.UUID) error {
+	var linkedIncidentIDs []uuid.UUID
+	if err := tx.Model(&models.IncidentIssue{}).Where("issue_id = ?", primaryID).
+		Pluck("incident_id", &linkedIncidentIDs).Error; err != nil {
+		return fmt.Errorf("failed to get incident links: %w", err)
+	}
+	linked := make(map[uuid.UUID]bool, len(linkedIncidentIDs))
+	for _, incidentID := range linkedIncidentIDs {
+		linked[incidentID] = true
+	}
+
+	var links []models.IncidentIssue
+	if err := tx.Where("issue_id IN ?", mergedIDs).Find(&links).Error; err != nil {
+		return fmt.Errorf("failed to get incident links: %w", err)
+	}
+	for _, link := range links {
+		if linked[link.IncidentID] {
+			if err := tx.Delete(&link).Error; err != nil {
+				return fmt.Errorf("failed to delete incident link: %w", err)
+			}
+			continue
+		}
+		linked[link.IncidentID] = true
+		if err := tx.Model(&link).Update("issue_id", primaryID).Error; err != nil {
+			return fmt.Errorf("failed to move incident link: %w", err)
+		}
+	}
+	return nil
+}
+
+// UnmergeIssue splits fingerprints merged into an issue, or some of its events, into a new
+// issue, the inverse of MergeIssues. Split fingerprints take their events along and group
+// later events into the new issue. Events split on their own get a fingerprint no event has,
+// so later events still go to the issue. The counts of both issues are recalculated from the
+// events moved.
+func (s *IssueService) UnmergeIssue(issueID, userID uuid.UUID, request dto.UnmergeIssueRequest) (*dto.UnmergeIssueResponse, error) {
+	fingerprints := make([]string, 0, len(request.Fingerprints))
+	seenFingerprints := make(map[string]bool)
+	for _, fingerprint := range request.Fingerprints {
+		if !seenFingerprints[fingerprint] {
+			seenFingerprints[fingerprint] = true
+			fingerprints = append(fingerprints, fingerprint)
+		}
+	}
+	eventIDs := make([]uuid.UUID, 0, len(request.EventIDs))
+	seenEvents := make(map[uuid.UUID]bool)
+	for _, eventID := range request.EventIDs {
+		if !seenEvents[eventID] {
+			seenEvents[eventID] = true
+			eventIDs = append(eventIDs, eventID)
+		}
+	}
+	switch {
+	case len(fingerprints) == 0 && len(eventIDs) == 0:
+		return nil, fmt.Errorf("%w: fingerprints or event_ids are required", ErrIssueUnmergeInvalid)
+	case len(fingerprints) > 0 && len(eventIDs) > 0:
+		return nil, fmt.Errorf("%w: fingerprints and event_ids cannot be combined", ErrIssueUnmergeInvalid)
+	case len(fingerprints) > MaxMergedIssues:
+		return nil, fmt.Errorf("%w: at most %d fingerprints can be unmerged at once", ErrIssueUnmergeInvalid, MaxMergedIssues)
+	case len(eventIDs) > MaxUnmergedEvents:
+		return nil, fmt.Errorf("%w: at most %d events can be unmerged at once", ErrIssueUnmergeInvalid, MaxUnmergedEvents)
+	}
+
+	var newIssue models.Issue
+	var eventCount int64
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var source models.Issue
+		if err := tx.Where("id = ? AND deletion_requested_at IS NULL", issueID).First(&source).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrIssueNotFound
+			}
+			return fmt.Errorf("failed to get issue: %w", err)
+		}
+
+		newIssue = models.Issue{
+			ProjectID:   source.ProjectID,
+			Title:       source.Title,
+			Culprit:     source.Culprit,
+			Type:        source.Type,
+			Level:       source.Level,
+			Status:      models.StatusUnresolved,
+			Transaction: source.Transaction,
+			Platform:    source.Platform,
+			Priority:    source.Priority,
+
+			GroupingVersion: source.GroupingVersion,
+		}
+		newIssue.ID = uuid.New()
+
+		moved := func() *gorm.DB {
+			query := tx.Model(&models.Event{}).Where("issue_id = ?", issueID)
+			if len(fingerprints) > 0 {
+				return query.Where("fingerprint IN ?", fingerprints)
+			}
+			return query.Where("id IN ?", eventIDs)
+		}
+
+		var mappings []models.IssueMergedFingerprint
+		if len(fingerprints) > 0 {
+			if err := tx.Where("issue_id = ? AND fingerprint IN ?", issueID, fingerprints).Find(&mappings).Error; err != nil {
+				return fmt.Errorf("failed to get merged fingerprints: %w", err)
+			}
+			if len(mappings) != len(fingerprints) {
+				return fmt.Errorf("%w: only fingerprints merged into the issue can be unmerged", ErrIssueUnmergeInvalid)
+			}
+			newIssue.Fingerprint = fingerprints[0]
+			for _, mapping := range mappings {
+				if mapping.Fingerprint == fingerprints[0] {
+					newIssue.Title = mapping.Title
+				}
+			}
+		} else {
+			var found int64
+			if err := moved().Count(&found).Error; err != nil {
+				return fmt.Errorf("failed to get events: %w", err)
+			}
+			if found != int64(len(eventIDs)) {
+				return fmt.Errorf("%w: only events of the issue can be unmerged", ErrIssueUnmergeInvalid)
+			}
+			newIssue.Fingerprint = "split:" + newIssue.ID.String()
+		}
+
+		count, first, last, err := eventSpan(moved())
+		if err != nil {
+			return err
+		}
+		eventCount = count
+		now := time.Now()
+		newIssue.TimesSeen = int(count)
+		newIssue.FirstSeen, newIssue.LastSeen = now, now
+		if last != nil {
+			newIssue.FirstSeen, newIssue.LastSeen = first.Timestamp, last.Timestamp
+			newIssue.Level = last.Level
+			if len(fingerprints) == 0 {
+				newIssue.Title = eventIssueTitle(*last)
+			}
+		}
+
+		if err := tx.Create(&newIssue).Error; err != nil {
+			return fmt.Errorf("failed to create issue: %w", err)
+		}
+		if count == 0 {
+			// Create leaves out zero values, which would count the issue as seen once
+			if err := tx.Model(&newIssue).Update("times_seen", 0).Error; err != nil {
+				return fmt.Errorf("failed to create issue: %w", err)
+			}
+		}
+
+		// The first fingerprint is the new issue's own; the others stay merged into it
+		if len(fingerprints) > 0 {
+			if err := tx.Where("issue_id = ? AND fingerprint = ?", issueID, fingerprints[0]).
+				Delete(&models.IssueMergedFingerprint{}).Error; err != nil {
+				return fmt.Errorf("failed to move merged fingerprints: %w", err)
+			}
+			if err := tx.Model(&models.IssueMergedFingerprint{}).Where("issue_id = ? AND fingerprint IN ?", issueID, fingerprints[1:]).
+				Update("issue_id", newIssue.ID).Error; err != nil {
+				return fmt.Errorf("failed to move merged fingerprints: %w", err)
+			}
+		}
+		if err := moved().Update("issue_id", newIssue.ID).Error; err != nil {
+			return fmt.Errorf("failed to move events: %w", err)
+		}
+		if err := reindexIssueTags(tx, []uuid.UUID{issueID, newIssue.ID}); err != nil {
+			return err
+		}
+
+		remaining, remainingFirst, remainingLast, err := eventSpan(tx.Model(&models.Event{}).Where("issue_id = ?", issueID))
+		if err != nil {
+			return err
+		}
+		if remaining == 0 && len(fingerprints) == 0 {
+			return fmt.Errorf("%w: the issue would have no events left", ErrIssueUnmergeInvalid)
+		}
+
+		// Events sampled out are counted but not stored, so the issue keeps those it had
+		oldTimesSeen := source.TimesSeen
+		source.TimesSeen -= int(count)
+		if source.TimesSeen < int(remaining) {
+			source.TimesSeen = int(remaining)
+		}
+		// first_seen and last_seen only change when the events moved set them
+		if first != nil && remainingFirst != nil && !source.FirstSeen.After(first.Timestamp) {
+			source.FirstSeen = remainingFirst.Timestamp
+		}
+		if last != nil && remainingLast != nil && !source.LastSeen.Before(last.Timestamp) {
+			source.LastSeen = remainingLast.Timestamp
+		}
+		if err := tx.Model(&source).Updates(map[string]interface{}{
+			"times_seen": source.TimesSeen,
+			"first_seen": source.FirstSeen,
+			"last_seen":  source.LastSeen,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to update issue: %w", err)
+		}
+
+		sourceData := map[string]interface{}{"new_issue_id": newIssue.ID, "event_count": count}
+		newData := map[string]interface{}{"source_issue_id": issueID, "event_count": count}
+		if len(fingerprints) > 0 {
+			sourceData["fingerprints"] = fingerprints
+			newData["fingerprints"] = fingerprints
+		}
+		if err := s.createActivity(tx, issueID, userID, models.ActivityUnmerge, sourceData); err != nil {
+			return fmt.Errorf("failed to log unmerge activity: %w", err)
+		}
+		if err := s.createActivity(tx, newIssue.ID, userID, models.ActivityUnmerge, newData); err != nil {
+			return fmt.Errorf("failed to log unmerge activity: %w", err)
+		}
+
+		if err := s.notifyChange(tx, &newIssue, &userID, models.IssueSyncCreated, nil); err != nil {
+			return err
+		}
+		return s.notifyChange(tx, &source, &userID, models.IssueSyncUpdated, map[string]dto.IssueSyncFieldChange{
+			"times_seen": {From: oldTimesSeen, To: source.TimesSeen},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	issue, err := s.GetIssue(issueID)
+	if err != nil {
+		return nil, err
+	}
+	created, err := s.GetIssue(newIssue.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UnmergeIssueResponse{Issue: issue, NewIssue: created, EventCount: int(eventCount)}, nil
+}
+
+// eventSpan counts the events a query matches and returns the earliest and latest of them,
+// nil when there are none
+func eventSpan(query *gorm.DB) (int64, *models.Event, *models.Event, error) {
+	var count int64
+	if err := query.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+		return 0, nil, nil, fmt.Errorf("failed to count events: %w", err)
+	}
+	if count == 0 {
+		return 0, nil, nil, nil
+	}
+
+	var first, last models.Event
+	if err := query.Session(&gorm.Session{}).Order("timestamp ASC").Limit(1).Find(&first).Error; err != nil {
+		return 0, nil, nil, fmt.Errorf("failed to get events: %w", err)
+	}
+	if err := query.Session(&gorm.Session{}).Order("timestamp DESC").Limit(1).Find(&last).Error; err != nil {
+		return 0, nil, nil, fmt.Errorf("failed to get events: %w", err)
+	}
+	return count, &first, &last, nil
+}
+
+// eventIssueTitle titles an issue after one of its events, the way ingestion titles new issues
+func eventIssueTitle(event models.Event) string {
+	switch {
+	case event.ExceptionType != nil && event.ExceptionValue != nil:
+		return fmt.Sprintf("%s: %s", *event.ExceptionType, *event.ExceptionValue)
+	case event.Message != nil:
+		return *event.Message
+	case event.ExceptionType != nil:
+		return *event.ExceptionType
+	}
+	return "Unknown Error"
+}
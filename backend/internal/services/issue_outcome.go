@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+// Outcomes of received error events that are not stored as events of their issue
+const (
+	IssueOutcomeDuplicate   = EventStatusDuplicate
+	IssueOutcomeSampled     = EventStatusSampled
+	IssueOutcomeRateLimited = EventStatusRateLimited
+)
+
+// issueOutcomeKey is a fingerprint's outcome on a UTC day; the fingerprint of rate limited
+// events is ""
+type issueOutcomeKey struct {
+	projectID   uuid.UUID
+	fingerprint string
+	outcome     string
+	day         time.Time
+}
+
+// IssueOutcomeService counts the error events that are deduplicated, sampled out or rate
+// limited, per fingerprint where it is known, so issues can show how much of their real
+// volume times_seen leaves out. Counts are buffered and written to the database periodically.
+type IssueOutcomeService struct {
+	db *database.DB
+
+	mu      sync.Mutex
+	pending map[issueOutcomeKey]int64 // events not yet written
+}
+
+// NewIssueOutcomeService creates a new issue outcome service
+func NewIssueOutcomeService(db *database.DB) *IssueOutcomeService {
+	return &IssueOutcomeService{
+		db:      db,
+		pending: make(map[issueOutcomeKey]int64),
+	}
+}
+
+// SetIssueOutcomes makes ingestion count the error events it deduplicates, samples out or
+// rate limits
+func (es *ErrorService) SetIssueOutcomes(outcomes *IssueOutcomeService) {
+	es.outcomes = outcomes
+}
+
+// countOutcome counts an event of a fingerprint that was not stored
+func (es *ErrorService) countOutcome(projectID uuid.UUID, fingerprint, outcome string) {
+	if es.outcomes != nil {
+		es.outcomes.Count(projectID, fingerprint, outcome)
+	}
+}
+
+// CountRateLimited counts an event of a project rejected by its rate limits, quotas or spike
+// protection. Its fingerprint is unknown, as events are rate limited before they are parsed.
+func (es *ErrorService) CountRateLimited(projectID uuid.UUID) {
+	es.countOutcome(projectID, "", IssueOutcomeRateLimited)
+}
+
+// Count buffers an event of a fingerprint with the given outcome, on the current day
+func (ios *IssueOutcomeService) Count(projectID uuid.UUID, fingerprint, outcome string) {
+	key := issueOutcomeKey{projectID: projectID, fingerprint: fingerprint, outcome: outcome, day: eventVolumeDay(time.Now())}
+
+	ios.mu.Lock()
+	ios.pending[key]++
+	ios.mu.Unlock()
+}
+
+// FlushStats adds the buffered outcome counts to the daily totals in the database.
+// It is run periodically by the scheduler and on shutdown.
+func (ios *IssueOutcomeService) FlushStats(ctx context.Context) error {
+	pending := takeCounts(&ios.mu, &ios.pending)
+	err := flushCounts(&ios.mu, &ios.pending, pending, func(key issueOutcomeKey, events int64) error {
+		row := models.IssueOutcomeStat{
+			ProjectID:   key.projectID,
+			Fingerprint: key.fingerprint,
+			Outcome:     key.outcome,
+			Day:         key.day,
+			Events:      events,
+		}
+		return ios.db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "project_id"}, {Name: "fingerprint"}, {Name: "outcome"}, {Name: "day"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"events":     clause.Expr{SQL: "issue_outcome_stats.events + excluded.events"},
+				"updated_at": time.Now(),
+			}),
+		}).Create(&row).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record issue outcomes: %w", err)
+	}
+	return nil
+}
+
+// GetIssueOutcomes returns the events of a fingerprint that were deduplicated or sampled out,
+// and the events of its project rate limited since the day firstSeen
+func (ios *IssueOutcomeService) GetIssueOutcomes(projectID uuid.UUID, fingerprint string, firstSeen time.Time) (*dto.IssueOutcomesResponse, error) {
+	since := eventVolumeDay(firstSeen)
+
+	var rows []struct {
+		Outcome string
+		Events  int64
+	}
+	err := ios.db.Model(&models.IssueOutcomeStat{}).
+		Select("outcome, SUM(events) AS events").
+		Where("project_id = ?", projectID).
+		Where("(fingerprint = ? AND outcome IN ?) OR (fingerprint = '' AND outcome = ? AND day >= ?)",
+			fingerprint, []string{IssueOutcomeDuplicate, IssueOutcomeSampled}, IssueOutcomeRateLimited, since).
+		Group("outcome").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate issue outcomes: %w", err)
+	}
+
+	response := &dto.IssueOutcomesResponse{}
+	add := func(outcome string, events int64) {
+		switch outcome {
+		case IssueOutcomeDuplicate:
+			response.Duplicate += events
+		case IssueOutcomeSampled:
+			response.Sampled += events
+		case IssueOutcomeRateLimited:
+			response.ProjectRateLimited += events
+		}
+	}
+	for _, row := range rows {
+		add(row.Outcome, row.Events)
+	}
+
+	ios.mu.Lock()
+	for key, events := range ios.pending {
+		if key.projectID != projectID {
+			continue
+		}
+		if key.fingerprint == fingerprint && key.outcome != IssueOutcomeRateLimited ||
+			key.fingerprint == "" && key.outcome == IssueOutcomeRateLimited && !key.day.Before(since) {
+			add(key.outcome, events)
+		}
+	}
+	ios.mu.Unlock()
+
+	return response, nil
+}
+
+// SetIssueOutcomes makes issue details include the events of the issue that were not stored
+func (s *IssueService) SetIssueOutcomes(outcomes *IssueOutcomeService) {
+	s.outcomes = outcomes
+}
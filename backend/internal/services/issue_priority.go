@@ -0,0 +1,65 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"minisentry/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidIssuePriority = errors.New("invalid priority, must be low, medium or high")
+
+// Event counts from which issues whose priority was not set by a user rise to medium and
+// high priority
+const (
+	issuePriorityMediumEvents = 100
+	issuePriorityHighEvents   = 1000
+)
+
+var issuePriorities = []models.IssuePriority{models.PriorityLow, models.PriorityMedium, models.PriorityHigh}
+
+// initialIssuePriority is the priority of a new issue: high for fatal events, medium for
+// errors and low for warnings and below, raised by the events the issue already counts
+func initialIssuePriority(level models.IssueLevel, timesSeen int) models.IssuePriority {
+	priority := models.PriorityLow
+	switch level {
+	case models.LevelFatal:
+		priority = models.PriorityHigh
+	case models.LevelError:
+		priority = models.PriorityMedium
+	}
+	switch {
+	case timesSeen >= issuePriorityHighEvents:
+		priority = models.PriorityHigh
+	case timesSeen >= issuePriorityMediumEvents && priority == models.PriorityLow:
+		priority = models.PriorityMedium
+	}
+	return priority
+}
+
+// isValidIssuePriority tells whether a priority is one users can set
+func isValidIssuePriority(priority string) bool {
+	for _, valid := range issuePriorities {
+		if priority == string(valid) {
+			return true
+		}
+	}
+	return false
+}
+
+// risenIssuePriority is the SQL expression of an issue's priority once count more events are
+// counted: raised when they take times_seen past a threshold, unless a user set it
+func risenIssuePriority(count int) interface{} {
+	return gorm.Expr(fmt.Sprintf(
+		"CASE WHEN priority_locked THEN priority WHEN times_seen + ? >= %d THEN '%s' WHEN times_seen + ? >= %d AND priority = '%s' THEN '%s' ELSE priority END",
+		issuePriorityHighEvents, models.PriorityHigh, issuePriorityMediumEvents, models.PriorityLow, models.PriorityMedium,
+	), count, count)
+}
+
+// issuePriorityOrder is the SQL expression sorting issues by priority, lowest first
+func issuePriorityOrder() string {
+	return fmt.Sprintf("CASE issues.priority WHEN '%s' THEN 1 WHEN '%s' THEN 2 WHEN '%s' THEN 3 ELSE 0 END",
+		models.PriorityLow, models.PriorityMedium, models.PriorityHigh)
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"minisentry/internal/dto"
+)
+
+var ErrIssueQueryInvalid = errors.New("invalid issue query")
+
+// issueQueryKey is the syntax of the keys of key:value terms, which must be usable as tag
+// keys in JSON paths
+var issueQueryKey = regexp.MustCompile(`^[A-Za-z0-9_.\-]{1,64}$`)
+
+// IsValidTagKey reports whether a tag key can be filtered by, as in tags[key]=value
+func IsValidTagKey(key string) bool {
+	return issueQueryKey.MatchString(key)
+}
+
+// issueQueryTerm is a key:value term of an issue query, or free text when key is empty. Keys
+// keep their case, as tag keys are case-sensitive.
+type issueQueryTerm struct {
+	key   string
+	value string
+}
+
+// ApplyIssueQuery sets the filters a Sentry-style issue query selects, such as
+// is:unresolved level:error assigned:me release:1.2.* browser:Chrome. Keys other than is,
+// level, priority, assigned, release, environment and transaction are tags of events, and
+// free text is searched. Values may be quoted to contain spaces, and a trailing * matches a
+// prefix of transactions, releases and tags.
+func ApplyIssueQuery(filters *dto.IssueFilters, query string) error {
+	terms, err := tokenizeIssueQuery(query)
+	if err != nil {
+		return err
+	}
+
+	var text []string
+	for _, term := range terms {
+		value := term.value
+		key := strings.ToLower(term.key)
+		switch key {
+		case "":
+			text = append(text, value)
+		case "is":
+			switch strings.ToLower(value) {
+			case "unresolved", "resolved", "ignored":
+				filters.Status = append(filters.Status, strings.ToLower(value))
+			case "assigned", "unassigned":
+				assignee := "any"
+				if strings.EqualFold(value, "unassigned") {
+					assignee = "none"
+				}
+				filters.AssignedTo = &assignee
+			case "regression":
+				regression := true
+				filters.Regression = &regression
+			default:
+				return fmt.Errorf("%w: unknown is:%s, expected unresolved, resolved, ignored, assigned, unassigned or regression", ErrIssueQueryInvalid, value)
+			}
+		case "level":
+			for _, level := range strings.Split(strings.ToLower(value), ",") {
+				if !slices.Contains([]string{"debug", "info", "warning", "error", "fatal"}, level) {
+					return fmt.Errorf("%w: unknown level %q", ErrIssueQueryInvalid, level)
+				}
+				filters.Level = append(filters.Level, level)
+			}
+		case "priority":
+			for _, priority := range strings.Split(strings.ToLower(value), ",") {
+				if !isValidIssuePriority(priority) {
+					return fmt.Errorf("%w: unknown priority %q", ErrIssueQueryInvalid, priority)
+				}
+				filters.Priority = append(filters.Priority, priority)
+			}
+		case "assigned":
+			assignee := strings.ToLower(value)
+			filters.AssignedTo = &assignee
+		case "release":
+			filters.Release = &value
+		case "environment", "env":
+			filters.Environment = &value
+		case "transaction":
+			filters.Transaction = &value
+		default:
+			if IsContextTagKey(key) {
+				if filters.ContextTags == nil {
+					filters.ContextTags = make(map[string]string)
+				}
+				filters.ContextTags[key] = value
+				continue
+			}
+			if filters.Tags == nil {
+				filters.Tags = make(map[string]string)
+			}
+			filters.Tags[term.key] = value
+		}
+	}
+
+	if len(text) > 0 {
+		search := strings.Join(text, " ")
+		if filters.Search != nil && *filters.Search != "" {
+			search = *filters.Search + " " + search
+		}
+		filters.Search = &search
+	}
+	return nil
+}
+
+// tokenizeIssueQuery splits a query into key:value terms and free text. Double quotes group
+// words with spaces, as in message:"connection reset" or "out of memory"; a term with a key
+// that is not a valid tag key, or a URL, is free text.
+func tokenizeIssueQuery(query string) ([]issueQueryTerm, error) {
+	var raw []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case !quoted && (r == ' ' || r == '\t' || r == '\n'):
+			if current.Len() > 0 {
+				raw = append(raw, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("%w: unterminated quote", ErrIssueQueryInvalid)
+	}
+	if current.Len() > 0 {
+		raw = append(raw, current.String())
+	}
+
+	terms := make([]issueQueryTerm, 0, len(raw))
+	for _, token := range raw {
+		key, value, ok := strings.Cut(token, ":")
+		if !ok || !issueQueryKey.MatchString(key) || strings.HasPrefix(value, "//") {
+			if text := strings.ReplaceAll(token, `"`, ""); text != "" {
+				terms = append(terms, issueQueryTerm{value: text})
+			}
+			continue
+		}
+		value = strings.ReplaceAll(value, `"`, "")
+		if value == "" {
+			return nil, fmt.Errorf("%w: %s: has no value", ErrIssueQueryInvalid, key)
+		}
+		terms = append(terms, issueQueryTerm{key: key, value: value})
+	}
+	return terms, nil
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"fmt"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxIssueRelationDepth bounds how far relation traversal follows links
+const maxIssueRelationDepth = 5
+
+// IsValidIssueRelationType reports whether the relation type is supported
+func IsValidIssueRelationType(relationType string) bool {
+	switch models.IssueRelationType(relationType) {
+	case models.RelationDuplicateOf, models.RelationBlockedBy, models.RelationRelated:
+		return true
+	}
+	return false
+}
+
+// AddIssueRelation links the issue to a target issue of the same organization
+func (s *IssueService) AddIssueRelation(issueID, userID uuid.UUID, request dto.IssueRelationRequest) (*dto.IssueRelationResponse, error) {
+	if !IsValidIssueRelationType(request.RelationType) {
+		return nil, fmt.Errorf("invalid relation type")
+	}
+	if request.TargetIssueID == issueID {
+		return nil, fmt.Errorf("an issue cannot be related to itself")
+	}
+
+	var issues []models.Issue
+	if err := s.db.Preload("Project").Where("id IN ?", []uuid.UUID{issueID, request.TargetIssueID}).Find(&issues).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve issues: %w", err)
+	}
+	var source, target *models.Issue
+	for i := range issues {
+		if issues[i].ID == issueID {
+			source = &issues[i]
+		} else {
+			target = &issues[i]
+		}
+	}
+	if source == nil {
+		return nil, fmt.Errorf("issue not found")
+	}
+	if target == nil {
+		return nil, fmt.Errorf("target issue not found")
+	}
+	if source.Project.OrganizationID != target.Project.OrganizationID {
+		return nil, fmt.Errorf("related issues must belong to the same organization")
+	}
+
+	relationType := models.IssueRelationType(request.RelationType)
+	query := s.db.Model(&models.IssueRelation{}).Where("type = ?", relationType)
+	if relationType == models.RelationRelated {
+		// "related" is symmetric, so the reverse link counts as the same relation
+		query = query.Where("(source_issue_id = ? AND target_issue_id = ?) OR (source_issue_id = ? AND target_issue_id = ?)",
+			issueID, target.ID, target.ID, issueID)
+	} else {
+		query = query.Where("source_issue_id = ? AND target_issue_id = ?", issueID, target.ID)
+	}
+	var existing int64
+	if err := query.Count(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to check existing relations: %w", err)
+	}
+	if existing > 0 {
+		return nil, fmt.Errorf("relation already exists")
+	}
+
+	relation := models.IssueRelation{
+		SourceIssueID: issueID,
+		TargetIssueID: target.ID,
+		Type:          relationType,
+		CreatedByID:   &userID,
+	}
+	relation.ID = uuid.New()
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&relation).Error; err != nil {
+			return fmt.Errorf("failed to create relation: %w", err)
+		}
+		return s.createActivity(tx, issueID, userID, models.ActivityRelation, map[string]interface{}{
+			"action":          "relation_added",
+			"relation_type":   relationType,
+			"target_issue_id": target.ID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := s.convertRelationToResponse(relation, map[uuid.UUID]*models.Issue{source.ID: source, target.ID: target}, 1)
+	return &response, nil
+}
+
+// RemoveIssueRelation deletes a relation the issue is part of, on either end
+func (s *IssueService) RemoveIssueRelation(issueID, relationID, userID uuid.UUID) error {
+	var relation models.IssueRelation
+	if err := s.db.Where("id = ? AND (source_issue_id = ? OR target_issue_id = ?)", relationID, issueID, issueID).
+		First(&relation).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("relation not found")
+		}
+		return fmt.Errorf("failed to retrieve relation: %w", err)
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&relation).Error; err != nil {
+			return fmt.Errorf("failed to delete relation: %w", err)
+		}
+		return s.createActivity(tx, issueID, userID, models.ActivityRelation, map[string]interface{}{
+			"action":          "relation_removed",
+			"relation_type":   relation.Type,
+			"source_issue_id": relation.SourceIssueID,
+			"target_issue_id": relation.TargetIssueID,
+		})
+	})
+}
+
+// GetIssueRelations returns the relations of an issue. With a depth above 1 the relations of
+// related issues are followed too, so a chain of upstream and downstream failures can be
+// walked in one request. An optional relation type restricts which links are followed.
+func (s *IssueService) GetIssueRelations(issueID uuid.UUID, depth int, relationType string) ([]dto.IssueRelationResponse, error) {
+	if depth < 1 {
+		depth = 1
+	}
+	if depth > maxIssueRelationDepth {
+		depth = maxIssueRelationDepth
+	}
+
+	visited := map[uuid.UUID]bool{issueID: true}
+	seenRelations := make(map[uuid.UUID]bool)
+	frontier := []uuid.UUID{issueID}
+
+	var relations []models.IssueRelation
+	var depths []int
+	for level := 1; level <= depth && len(frontier) > 0; level++ {
+		query := s.db.Where("source_issue_id IN ? OR target_issue_id IN ?", frontier, frontier)
+		if relationType != "" {
+			query = query.Where("type = ?", relationType)
+		}
+		var found []models.IssueRelation
+		if err := query.Order("created_at ASC").Find(&found).Error; err != nil {
+			return nil, fmt.Errorf("failed to retrieve relations: %w", err)
+		}
+
+		var next []uuid.UUID
+		for _, relation := range found {
+			if seenRelations[relation.ID] {
+				continue
+			}
+			seenRelations[relation.ID] = true
+			relations = append(relations, relation)
+			depths = append(depths, level)
+
+			for _, id := range []uuid.UUID{relation.SourceIssueID, relation.TargetIssueID} {
+				if !visited[id] {
+					visited[id] = true
+					next = append(next, id)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	issueIDs := make([]uuid.UUID, 0, len(visited))
+	for id := range visited {
+		issueIDs = append(issueIDs, id)
+	}
+	var issues []models.Issue
+	if err := s.db.Preload("Project").Where("id IN ?", issueIDs).Find(&issues).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve related issues: %w", err)
+	}
+	issuesByID := make(map[uuid.UUID]*models.Issue, len(issues))
+	for i := range issues {
+		issuesByID[issues[i].ID] = &issues[i]
+	}
+
+	responses := make([]dto.IssueRelationResponse, len(relations))
+	for i, relation := range relations {
+		responses[i] = s.convertRelationToResponse(relation, issuesByID, depths[i])
+	}
+	return responses, nil
+}
+
+func (s *IssueService) convertRelationToResponse(relation models.IssueRelation, issues map[uuid.UUID]*models.Issue, depth int) dto.IssueRelationResponse {
+	return dto.IssueRelationResponse{
+		ID:           relation.ID,
+		RelationType: string(relation.Type),
+		SourceIssue:  relatedIssueSummary(relation.SourceIssueID, issues),
+		TargetIssue:  relatedIssueSummary(relation.TargetIssueID, issues),
+		Depth:        depth,
+		CreatedByID:  relation.CreatedByID,
+		CreatedAt:    relation.CreatedAt,
+	}
+}
+
+func relatedIssueSummary(issueID uuid.UUID, issues map[uuid.UUID]*models.Issue) dto.IssueRelatedIssueResponse {
+	issue, ok := issues[issueID]
+	if !ok {
+		return dto.IssueRelatedIssueResponse{ID: issueID}
+	}
+	return dto.IssueRelatedIssueResponse{
+		ID:          issue.ID,
+		ProjectID:   issue.ProjectID,
+		ProjectSlug: issue.Project.Slug,
+		Title:       issue.Title,
+		Status:      string(issue.Status),
+		Level:       string(issue.Level),
+	}
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"minisentry/internal/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// issueSearchEventWindow bounds how far back the events whose messages a search matches go
+const issueSearchEventWindow = 30 * 24 * time.Hour
+
+// Full-text documents of issues and of event messages; migration 049 indexes them on Postgres
+const (
+	issueSearchDocument = "to_tsvector('simple', issues.title || ' ' || COALESCE(issues.culprit, ''))"
+	eventSearchDocument = "to_tsvector('simple', COALESCE(events.message, '') || ' ' || COALESCE(events.exception_type, '') || ' ' || COALESCE(events.exception_value, ''))"
+	issueSearchQuery    = "websearch_to_tsquery('simple', ?)"
+)
+
+// applyIssueSearch keeps the issues whose title or culprit contains the search text, or
+// whose title, culprit or recent event messages match its words. On SQLite, without full-text
+// search, event messages must contain the text.
+func (s *IssueService) applyIssueSearch(query *gorm.DB, search string) *gorm.DB {
+	term := "%" + strings.ToLower(search) + "%"
+	since := time.Now().Add(-issueSearchEventWindow)
+	if s.db.Dialector.Name() == database.DriverSQLite {
+		return query.Where("LOWER(issues.title) LIKE ? OR LOWER(issues.culprit) LIKE ? OR "+
+			"EXISTS (SELECT 1 FROM events WHERE events.issue_id = issues.id AND events.timestamp >= ? AND "+
+			"(LOWER(events.message) LIKE ? OR LOWER(events.exception_value) LIKE ?))",
+			term, term, since, term, term)
+	}
+	return query.Where("LOWER(issues.title) LIKE ? OR LOWER(issues.culprit) LIKE ? OR "+
+		issueSearchDocument+" @@ "+issueSearchQuery+" OR "+
+		"EXISTS (SELECT 1 FROM events WHERE events.issue_id = issues.id AND events.timestamp >= ? AND "+
+		eventSearchDocument+" @@ "+issueSearchQuery+")",
+		term, term, search, since, search)
+}
+
+// issueSearchRank is the relevance of issues to a search, highest first: how well their
+// title and culprit match, counting twice, plus how well their best matching recent event
+// does. Titles containing the search text rank first.
+func (s *IssueService) issueSearchRank(search string) clause.Expr {
+	term := "%" + strings.ToLower(search) + "%"
+	if s.db.Dialector.Name() == database.DriverSQLite {
+		return clause.Expr{
+			SQL:  "CASE WHEN LOWER(issues.title) LIKE ? THEN 2 WHEN LOWER(issues.culprit) LIKE ? THEN 1 ELSE 0 END",
+			Vars: []interface{}{term, term},
+		}
+	}
+	since := time.Now().Add(-issueSearchEventWindow)
+	return clause.Expr{
+		SQL: "CASE WHEN LOWER(issues.title) LIKE ? THEN 1 ELSE 0 END + " +
+			"2 * ts_rank(" + issueSearchDocument + ", " + issueSearchQuery + ") + " +
+			"COALESCE((SELECT MAX(ts_rank(" + eventSearchDocument + ", " + issueSearchQuery + ")) FROM events " +
+			"WHERE events.issue_id = issues.id AND events.timestamp >= ? AND " + eventSearchDocument + " @@ " + issueSearchQuery + "), 0)",
+		Vars: []interface{}{term, search, search, since, search},
+	}
+}
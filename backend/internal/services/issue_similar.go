@@ -0,0 +1,97 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// similarIssueCandidates bounds how many of the project's most recently seen issues are
+	// compared with the issue
+	similarIssueCandidates = 200
+	// MinSimilarityScore is the score from which an issue is suggested as similar
+	MinSimilarityScore = 0.5
+	// MaxSimilarIssues bounds how many similar issues are returned
+	MaxSimilarIssues = 50
+)
+
+// GetSimilarIssues suggests issues of the issue's project that are likely duplicates of it,
+// most similar first, by comparing their titles and the top frames of their latest events
+func (s *IssueService) GetSimilarIssues(issueID uuid.UUID, limit int) ([]dto.SimilarIssueResponse, error) {
+	if limit <= 0 || limit > MaxSimilarIssues {
+		limit = 10
+	}
+
+	var issue models.Issue
+	if err := s.db.Where("id = ? AND deletion_requested_at IS NULL", issueID).First(&issue).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrIssueNotFound
+		}
+		return nil, fmt.Errorf("failed to get issue: %w", err)
+	}
+
+	var candidates []models.Issue
+	if err := s.db.Where("project_id = ? AND id <> ? AND deletion_requested_at IS NULL", issue.ProjectID, issue.ID).
+		Order("last_seen DESC").
+		Limit(similarIssueCandidates).
+		Find(&candidates).Error; err != nil {
+		return nil, fmt.Errorf("failed to get candidate issues: %w", err)
+	}
+	if len(candidates) == 0 {
+		return []dto.SimilarIssueResponse{}, nil
+	}
+
+	issueIDs := append(collectIDs(candidates, func(issue *models.Issue) uuid.UUID { return issue.ID }), issue.ID)
+	latestEvents, err := latestByKey(s.db, &models.Event{}, "issue_id", "timestamp", issueIDs,
+		func(event *models.Event) uuid.UUID { return event.IssueID })
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprints := NewFingerprintService()
+	signature := func(issue *models.Issue) SimilaritySignature {
+		var frames []dto.StackFrame
+		if event, ok := latestEvents[issue.ID]; ok && len(event.StackTrace) > 0 {
+			json.Unmarshal(event.StackTrace, &frames)
+		}
+		return fingerprints.SimilaritySignature(issue.Title, frames)
+	}
+
+	target := signature(&issue)
+	var similar []models.Issue
+	scores := make(map[uuid.UUID]float64)
+	for i := range candidates {
+		score := fingerprints.SimilarityScore(target, signature(&candidates[i]))
+		if score >= MinSimilarityScore {
+			similar = append(similar, candidates[i])
+			scores[candidates[i].ID] = score
+		}
+	}
+	sort.SliceStable(similar, func(i, j int) bool {
+		return scores[similar[i].ID] > scores[similar[j].ID]
+	})
+	if len(similar) > limit {
+		similar = similar[:limit]
+	}
+	if len(similar) == 0 {
+		return []dto.SimilarIssueResponse{}, nil
+	}
+
+	issues, err := s.convertIssuesToResponses(similar, false)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]dto.SimilarIssueResponse, len(issues))
+	for i := range issues {
+		responses[i] = dto.SimilarIssueResponse{Issue: issues[i], Score: scores[issues[i].ID]}
+	}
+	return responses, nil
+}
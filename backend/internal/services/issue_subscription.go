@@ -0,0 +1,258 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IssueSubscriptionService manages the participants of issues and emails them when the
+// status of their issues changes
+type IssueSubscriptionService struct {
+	db        *database.DB
+	outbox    *OutboxService
+	publicURL string
+}
+
+// NewIssueSubscriptionService creates a new issue subscription service queuing emails in the
+// outbox, with links to the API at publicURL
+func NewIssueSubscriptionService(db *database.DB, outbox *OutboxService, publicURL string) *IssueSubscriptionService {
+	return &IssueSubscriptionService{
+		db:        db,
+		outbox:    outbox,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+// GetParticipants returns the participants of an issue, earliest first, and whether the user
+// is one of them
+func (ss *IssueSubscriptionService) GetParticipants(issueID, userID uuid.UUID) (*dto.IssueParticipantsResponse, error) {
+	participants, err := issueParticipants(ss.db.DB, issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.IssueParticipantsResponse{Participants: make([]dto.IssueParticipantResponse, len(participants))}
+	for i, participant := range participants {
+		response.Participants[i] = dto.IssueParticipantResponse{
+			UserID:    participant.UserID,
+			Name:      participant.User.Name,
+			Email:     participant.User.Email,
+			Reason:    string(participant.Reason),
+			CreatedAt: participant.CreatedAt,
+		}
+		if participant.UserID == userID {
+			response.IsSubscribed = true
+		}
+	}
+	return response, nil
+}
+
+// Subscribe makes the user a participant of the issue, also when they unsubscribed before
+func (ss *IssueSubscriptionService) Subscribe(issueID, userID uuid.UUID) (*dto.IssueParticipantsResponse, error) {
+	if err := ss.setSubscription(issueID, userID, true); err != nil {
+		return nil, err
+	}
+	return ss.GetParticipants(issueID, userID)
+}
+
+// Unsubscribe stops the user taking part in the issue, including when they comment on it or
+// are assigned to it later
+func (ss *IssueSubscriptionService) Unsubscribe(issueID, userID uuid.UUID) (*dto.IssueParticipantsResponse, error) {
+	if err := ss.setSubscription(issueID, userID, false); err != nil {
+		return nil, err
+	}
+	return ss.GetParticipants(issueID, userID)
+}
+
+func (ss *IssueSubscriptionService) setSubscription(issueID, userID uuid.UUID, active bool) error {
+	subscription := models.IssueSubscription{
+		IssueID:  issueID,
+		UserID:   userID,
+		IsActive: active,
+		Reason:   models.SubscriptionSubscribed,
+	}
+	err := ss.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "issue_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"is_active", "updated_at"}),
+	}).Create(&subscription).Error
+	if err != nil {
+		return fmt.Errorf("failed to update subscription: %w", err)
+	}
+	return nil
+}
+
+// NotifyIssueChange is an IssueChangeListener: assignees take part in their issues, and the
+// participants of an issue other than the user who changed its status are emailed the change
+func (ss *IssueSubscriptionService) NotifyIssueChange(tx *gorm.DB, issue *models.Issue, actorID *uuid.UUID, changeType string, changes map[string]dto.IssueSyncFieldChange) error {
+	if changeType == models.IssueSyncAssigned && issue.AssigneeID != nil && *issue.AssigneeID != uuid.Nil {
+		if err := addIssueParticipant(tx, issue.ID, *issue.AssigneeID, models.SubscriptionAssigned); err != nil {
+			return err
+		}
+	}
+
+	status, ok := changes["status"]
+	if !ok {
+		return nil
+	}
+	actor := "Someone"
+	if actorID != nil {
+		var user models.User
+		if err := tx.Select("id", "name", "email").Where("id = ?", *actorID).First(&user).Error; err == nil {
+			actor = user.Name
+			if actor == "" {
+				actor = user.Email
+			}
+		}
+	}
+	return ss.notifyStatusChange(tx, issue, actorID, fmt.Sprintf("%s changed the status from %v to %v.", actor, status.From, status.To))
+}
+
+// NotifyMentions is an IssueMentionListener: members mentioned in a comment take part in its
+// issue, and those other than its author are emailed the comment
+func (ss *IssueSubscriptionService) NotifyMentions(tx *gorm.DB, issue *models.Issue, comment *models.IssueComment, mentioned []models.User) error {
+	var author models.User
+	if err := tx.Select("id", "name", "email").Where("id = ?", comment.UserID).First(&author).Error; err != nil {
+		return fmt.Errorf("failed to retrieve comment author: %w", err)
+	}
+	name := author.Name
+	if name == "" {
+		name = author.Email
+	}
+	subject := fmt.Sprintf("%s mentioned you on %s", name, issue.Title)
+	body := fmt.Sprintf("%s mentioned you in a comment on %s:\n\n%s\n\nIssue: %s/api/v1/issues/%s\n",
+		name, issue.Title, comment.Content, ss.publicURL, issue.ID)
+
+	for _, user := range mentioned {
+		if err := addIssueParticipant(tx, issue.ID, user.ID, models.SubscriptionMentioned); err != nil {
+			return err
+		}
+		if user.ID == comment.UserID {
+			continue
+		}
+		key := fmt.Sprintf("issue-mention:%s:%s", comment.ID, user.ID)
+		if err := ss.outbox.EnqueueEmail(tx, key, user.Email, subject, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NotifyIssueRegressed emails the participants of an issue that ingestion reopened. It is
+// meant to be registered with ErrorService.OnIssueRegressed.
+func (ss *IssueSubscriptionService) NotifyIssueRegressed(issue *models.Issue) {
+	err := ss.db.Transaction(func(tx *gorm.DB) error {
+		return ss.notifyStatusChange(tx, issue, nil, "A new event reopened the resolved issue as a regression.")
+	})
+	if err != nil {
+		log.Printf("Failed to notify the participants of issue %s: %v", issue.ID, err)
+	}
+}
+
+// notifyStatusChange queues an email about the status change of an issue for each of its
+// participants but the actor
+func (ss *IssueSubscriptionService) notifyStatusChange(tx *gorm.DB, issue *models.Issue, actorID *uuid.UUID, change string) error {
+	participants, err := issueParticipants(tx, issue.ID)
+	if err != nil {
+		return err
+	}
+
+	var project models.Project
+	if err := tx.Select("id", "name").Where("id = ?", issue.ProjectID).First(&project).Error; err != nil {
+		return fmt.Errorf("failed to retrieve project: %w", err)
+	}
+	subject := fmt.Sprintf("[%s] %s: %s", project.Name, issueStatusLabel(issue.Status), issue.Title)
+	body := fmt.Sprintf("%s\n\n%s\n\nIssue: %s/api/v1/issues/%s\n\nYou receive this email as a participant of the issue; unsubscribe from it to stop.\n",
+		issue.Title, change, ss.publicURL, issue.ID)
+
+	// One message per participant and change; the transaction already guarantees a change
+	// is only queued once, so the key just has to be unique
+	changeID := uuid.New()
+	for _, participant := range participants {
+		if actorID != nil && participant.UserID == *actorID {
+			continue
+		}
+		key := fmt.Sprintf("issue-status:%s:%s", changeID, participant.UserID)
+		if err := ss.outbox.EnqueueEmail(tx, key, participant.User.Email, subject, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func issueStatusLabel(status models.IssueStatus) string {
+	switch status {
+	case models.StatusResolved:
+		return "Resolved"
+	case models.StatusIgnored:
+		return "Ignored"
+	}
+	return "Unresolved"
+}
+
+// issueParticipants returns the active subscriptions of an issue, earliest first, of users
+// who are active and still members of the issue's organization
+func issueParticipants(db *gorm.DB, issueID uuid.UUID) ([]models.IssueSubscription, error) {
+	members := db.Model(&models.OrganizationMember{}).Select("organization_members.user_id").
+		Joins("JOIN projects ON projects.organization_id = organization_members.organization_id").
+		Joins("JOIN issues ON issues.project_id = projects.id").
+		Where("issues.id = ?", issueID)
+
+	var subscriptions []models.IssueSubscription
+	if err := db.Joins("User").
+		Where("issue_subscriptions.issue_id = ? AND issue_subscriptions.is_active = ?", issueID, true).
+		Where(`"User".is_active = ?`, true).
+		Where("issue_subscriptions.user_id IN (?)", members).
+		Order("issue_subscriptions.created_at ASC").
+		Find(&subscriptions).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve participants: %w", err)
+	}
+	return subscriptions, nil
+}
+
+// addIssueParticipant makes a user who took part in an issue a participant of it, unless
+// they already are or unsubscribed from it
+func addIssueParticipant(tx *gorm.DB, issueID, userID uuid.UUID, reason models.IssueSubscriptionReason) error {
+	subscription := models.IssueSubscription{
+		IssueID:  issueID,
+		UserID:   userID,
+		IsActive: true,
+		Reason:   reason,
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&subscription).Error; err != nil {
+		return fmt.Errorf("failed to add participant: %w", err)
+	}
+	return nil
+}
+
+// moveIssueSubscriptions hands the subscriptions of the issues merged into the primary issue
+// to it, keeping the primary issue's own for users subscribed to both
+func moveIssueSubscriptions(tx *gorm.DB, primaryID uuid.UUID, mergedIDs []uuid.UUID) error {
+	var subscriptions []models.IssueSubscription
+	if err := tx.Where("issue_id IN ?", mergedIDs).Order("created_at ASC").Find(&subscriptions).Error; err != nil {
+		return fmt.Errorf("failed to get subscriptions: %w", err)
+	}
+	for _, subscription := range subscriptions {
+		moved := models.IssueSubscription{
+			IssueID:  primaryID,
+			UserID:   subscription.UserID,
+			IsActive: subscription.IsActive,
+			Reason:   subscription.Reason,
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&moved).Error; err != nil {
+			return fmt.Errorf("failed to move subscriptions: %w", err)
+		}
+	}
+	if err := tx.Where("issue_id IN ?", mergedIDs).Delete(&models.IssueSubscription{}).Error; err != nil {
+		return fmt.Errorf("failed to delete subscriptions: %w", err)
+	}
+	return nil
+}
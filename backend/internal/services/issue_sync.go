@@ -0,0 +1,415 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrIssueSyncNotFound       = errors.New("issue sync is not configured for this project")
+	ErrIssueSyncInvalidRequest = errors.New("invalid issue sync request")
+)
+
+const (
+	// issueSyncBatchSize bounds the deltas per webhook delivery and per pulled page
+	issueSyncBatchSize = 100
+
+	// issueSyncPollInterval is how often pending webhook deliveries are checked
+	issueSyncPollInterval = 5 * time.Second
+
+	// issueSyncMaxBackoff caps the delay between retries of a failing webhook
+	issueSyncMaxBackoff = time.Hour
+
+	// issueSyncRetention is how long deltas are kept for consumers to pull
+	issueSyncRetention = 30 * 24 * time.Hour
+)
+
+type IssueSyncService struct {
+	db     *database.DB
+	client *http.Client
+}
+
+// NewIssueSyncService creates a new issue sync service
+func NewIssueSyncService(db *database.DB) *IssueSyncService {
+	return &IssueSyncService{
+		db:     db,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// GetIssueSync returns the issue sync configuration of a project
+func (ss *IssueSyncService) GetIssueSync(projectID uuid.UUID) (*dto.IssueSyncResponse, error) {
+	integration, err := ss.getIntegration(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return convertIssueSyncToResponse(integration, false), nil
+}
+
+// ConfigureIssueSync creates or updates the issue sync of a project. A signing secret is
+// generated on creation and on rotation, and only returned then.
+func (ss *IssueSyncService) ConfigureIssueSync(projectID uuid.UUID, request dto.IssueSyncRequest) (*dto.IssueSyncResponse, error) {
+	integration, err := ss.getIntegration(projectID)
+	created := errors.Is(err, ErrIssueSyncNotFound)
+	if err != nil && !created {
+		return nil, err
+	}
+	if created {
+		integration = &models.IssueSyncIntegration{ProjectID: projectID, Enabled: true}
+	}
+
+	if request.URL != nil {
+		webhookURL := strings.TrimSpace(*request.URL)
+		if webhookURL == "" {
+			integration.URL = nil
+		} else {
+			parsed, err := url.Parse(webhookURL)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				return nil, fmt.Errorf("%w: url must be an absolute http or https URL", ErrIssueSyncInvalidRequest)
+			}
+			integration.URL = &webhookURL
+		}
+	}
+	if request.Enabled != nil {
+		integration.Enabled = *request.Enabled
+	}
+	if request.Cursor != nil {
+		if *request.Cursor < 0 || *request.Cursor > integration.LastSequence {
+			return nil, fmt.Errorf("%w: cursor must be between 0 and %d", ErrIssueSyncInvalidRequest, integration.LastSequence)
+		}
+		integration.DeliveredSequence = *request.Cursor
+	}
+
+	revealSecret := created || request.RotateSecret
+	if revealSecret {
+		secret, err := generateToken()
+		if err != nil {
+			return nil, err
+		}
+		integration.Secret = secret
+	}
+
+	// A configuration change retries a failing webhook right away
+	integration.FailureCount = 0
+	integration.NextAttemptAt = nil
+
+	if created {
+		err = ss.db.Create(integration).Error
+	} else {
+		err = ss.db.Model(integration).Select("url", "enabled", "delivered_sequence", "secret", "failure_count", "next_attempt_at").
+			Updates(integration).Error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save issue sync: %w", err)
+	}
+
+	return convertIssueSyncToResponse(integration, revealSecret), nil
+}
+
+// DeleteIssueSync removes the issue sync of a project and its recorded deltas
+func (ss *IssueSyncService) DeleteIssueSync(projectID uuid.UUID) error {
+	return ss.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("project_id = ?", projectID).Delete(&models.IssueSyncIntegration{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete issue sync: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrIssueSyncNotFound
+		}
+		if err := tx.Where("project_id = ?", projectID).Delete(&models.IssueSyncDelta{}).Error; err != nil {
+			return fmt.Errorf("failed to delete issue sync deltas: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListDeltas returns the deltas recorded after the cursor, oldest first
+func (ss *IssueSyncService) ListDeltas(projectID uuid.UUID, cursor int64, limit int) (*dto.IssueSyncDeltasResponse, error) {
+	integration, err := ss.getIntegration(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if limit < 1 || limit > issueSyncBatchSize {
+		limit = issueSyncBatchSize
+	}
+	return ss.loadDeltas(integration, cursor, limit)
+}
+
+// RecordIssueChange records a delta for an issue change inside the transaction making the
+// change, so deltas are never lost or emitted for rolled back changes. Projects without an
+// issue sync record nothing.
+func (ss *IssueSyncService) RecordIssueChange(tx *gorm.DB, issue *models.Issue, actorID *uuid.UUID, deltaType string, changes map[string]dto.IssueSyncFieldChange) error {
+	// Incrementing the counter locks the integration row, serializing sequence numbers per project
+	result := tx.Model(&models.IssueSyncIntegration{}).Where("project_id = ?", issue.ProjectID).
+		UpdateColumn("last_sequence", gorm.Expr("last_sequence + 1"))
+	if result.Error != nil {
+		return fmt.Errorf("failed to allocate issue sync sequence: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	var sequence int64
+	if err := tx.Model(&models.IssueSyncIntegration{}).Where("project_id = ?", issue.ProjectID).
+		Pluck("last_sequence", &sequence).Error; err != nil {
+		return fmt.Errorf("failed to allocate issue sync sequence: %w", err)
+	}
+
+	if changes == nil {
+		changes = map[string]dto.IssueSyncFieldChange{}
+	}
+	payload, err := json.Marshal(dto.IssueSyncDelta{
+		SchemaVersion: dto.IssueSyncSchemaVersion,
+		Sequence:      sequence,
+		Type:          deltaType,
+		ProjectID:     issue.ProjectID,
+		IssueID:       issue.ID,
+		OccurredAt:    time.Now().UTC(),
+		ActorID:       actorID,
+		Changes:       changes,
+		Issue: dto.IssueSyncSnapshot{
+			ID:           issue.ID,
+			ProjectID:    issue.ProjectID,
+			Title:        issue.Title,
+			Culprit:      issue.Culprit,
+			Type:         string(issue.Type),
+			Level:        string(issue.Level),
+			Status:       string(issue.Status),
+			AssigneeID:   issue.AssigneeID,
+			FirstSeen:    issue.FirstSeen,
+			LastSeen:     issue.LastSeen,
+			TimesSeen:    issue.TimesSeen,
+			IsRegression: issue.IsRegression,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal issue sync delta: %w", err)
+	}
+
+	delta := models.IssueSyncDelta{
+		ProjectID: issue.ProjectID,
+		Sequence:  sequence,
+		IssueID:   issue.ID,
+		Type:      deltaType,
+		Payload:   payload,
+	}
+	if err := tx.Create(&delta).Error; err != nil {
+		return fmt.Errorf("failed to record issue sync delta: %w", err)
+	}
+	return nil
+}
+
+// RecordIssueCreated records the delta of an issue created by ingestion
+func (ss *IssueSyncService) RecordIssueCreated(issue *models.Issue) {
+	err := ss.db.Transaction(func(tx *gorm.DB) error {
+		return ss.RecordIssueChange(tx, issue, nil, models.IssueSyncCreated, nil)
+	})
+	if err != nil {
+		log.Printf("Failed to record issue sync delta for issue %s: %v", issue.ID, err)
+	}
+}
+
+// RecordIssueRegressed records the delta of a resolved issue reopened by ingestion
+func (ss *IssueSyncService) RecordIssueRegressed(issue *models.Issue) {
+	err := ss.db.Transaction(func(tx *gorm.DB) error {
+		return ss.RecordIssueChange(tx, issue, nil, models.IssueSyncRegressed, map[string]dto.IssueSyncFieldChange{
+			"status": {From: models.StatusResolved, To: issue.Status},
+		})
+	})
+	if err != nil {
+		log.Printf("Failed to record issue sync delta for issue %s: %v", issue.ID, err)
+	}
+}
+
+// Start delivers pending deltas to webhooks in the background until the context is cancelled
+func (ss *IssueSyncService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(issueSyncPollInterval)
+		defer ticker.Stop()
+
+		var lastPrune time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			ss.deliverPending()
+			if time.Since(lastPrune) >= time.Hour {
+				ss.pruneDeltas()
+				lastPrune = time.Now()
+			}
+		}
+	}()
+}
+
+func (ss *IssueSyncService) deliverPending() {
+	var integrations []models.IssueSyncIntegration
+	if err := ss.db.Where("enabled = ? AND url IS NOT NULL AND last_sequence > delivered_sequence AND (next_attempt_at IS NULL OR next_attempt_at <= ?)",
+		true, time.Now()).Find(&integrations).Error; err != nil {
+		log.Printf("Failed to load pending issue sync deliveries: %v", err)
+		return
+	}
+
+	for i := range integrations {
+		integration := &integrations[i]
+		// Catch up in batches, leaving the rest for the next tick if the backlog is large
+		for batch := 0; batch < 10 && integration.DeliveredSequence < integration.LastSequence; batch++ {
+			if err := ss.deliverBatch(integration); err != nil {
+				log.Printf("Issue sync delivery for project %s failed: %v", integration.ProjectID, err)
+				break
+			}
+		}
+	}
+}
+
+// deliverBatch posts the next batch of deltas to the integration's webhook and advances
+// its cursor on a 2xx response, or schedules a retry with exponential backoff
+func (ss *IssueSyncService) deliverBatch(integration *models.IssueSyncIntegration) error {
+	page, err := ss.loadDeltas(integration, integration.DeliveredSequence, issueSyncBatchSize)
+	if err != nil {
+		return err
+	}
+	if len(page.Deltas) == 0 {
+		// The remaining deltas were pruned before they could be delivered
+		integration.DeliveredSequence = integration.LastSequence
+		return ss.db.Model(integration).Update("delivered_sequence", integration.DeliveredSequence).Error
+	}
+
+	body, err := json.Marshal(page)
+	if err != nil {
+		return fmt.Errorf("failed to marshal deltas: %w", err)
+	}
+
+	now := time.Now()
+	if deliveryErr := ss.post(integration, body, now); deliveryErr != nil {
+		integration.FailureCount++
+		backoff := issueSyncPollInterval << min(integration.FailureCount, 10)
+		if backoff > issueSyncMaxBackoff {
+			backoff = issueSyncMaxBackoff
+		}
+		nextAttempt := now.Add(backoff)
+		message := deliveryErr.Error()
+		if err := ss.db.Model(integration).Updates(map[string]interface{}{
+			"failure_count":    integration.FailureCount,
+			"last_error":       message,
+			"last_delivery_at": now,
+			"next_attempt_at":  nextAttempt,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to record delivery failure: %w", err)
+		}
+		return deliveryErr
+	}
+
+	integration.DeliveredSequence = page.NextCursor
+	integration.FailureCount = 0
+	return ss.db.Model(integration).Updates(map[string]interface{}{
+		"delivered_sequence": page.NextCursor,
+		"failure_count":      0,
+		"last_error":         nil,
+		"last_delivery_at":   now,
+		"next_attempt_at":    nil,
+	}).Error
+}
+
+// post sends a webhook body signed with the integration's secret. The idempotency key
+// identifies the batch, so receivers can drop a batch redelivered after a lost response.
+func (ss *IssueSyncService) post(integration *models.IssueSyncIntegration, body []byte, now time.Time) error {
+	req, err := http.NewRequest(http.MethodPost, *integration.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "minisentry-issue-sync/1")
+	req.Header.Set("X-Minisentry-Event", "issue-sync")
+	req.Header.Set("Idempotency-Key", fmt.Sprintf("issue-sync:%s:%d", integration.ProjectID, integration.DeliveredSequence))
+	signWebhook(req, integration.Secret, body, now)
+
+	resp, err := ss.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (ss *IssueSyncService) pruneDeltas() {
+	if err := ss.db.Where("created_at < ?", time.Now().Add(-issueSyncRetention)).
+		Delete(&models.IssueSyncDelta{}).Error; err != nil {
+		log.Printf("Failed to prune issue sync deltas: %v", err)
+	}
+}
+
+func (ss *IssueSyncService) loadDeltas(integration *models.IssueSyncIntegration, cursor int64, limit int) (*dto.IssueSyncDeltasResponse, error) {
+	var deltas []models.IssueSyncDelta
+	if err := ss.db.Where("project_id = ? AND sequence > ?", integration.ProjectID, cursor).
+		Order("sequence ASC").
+		Limit(limit).
+		Find(&deltas).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve issue sync deltas: %w", err)
+	}
+
+	response := &dto.IssueSyncDeltasResponse{
+		ProjectID:  integration.ProjectID,
+		Deltas:     make([]json.RawMessage, len(deltas)),
+		NextCursor: cursor,
+	}
+	for i, delta := range deltas {
+		response.Deltas[i] = json.RawMessage(delta.Payload)
+		response.NextCursor = delta.Sequence
+	}
+	response.HasMore = response.NextCursor < integration.LastSequence
+	return response, nil
+}
+
+func (ss *IssueSyncService) getIntegration(projectID uuid.UUID) (*models.IssueSyncIntegration, error) {
+	var integration models.IssueSyncIntegration
+	if err := ss.db.Where("project_id = ?", projectID).First(&integration).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrIssueSyncNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve issue sync: %w", err)
+	}
+	return &integration, nil
+}
+
+func convertIssueSyncToResponse(integration *models.IssueSyncIntegration, revealSecret bool) *dto.IssueSyncResponse {
+	response := &dto.IssueSyncResponse{
+		ProjectID:         integration.ProjectID,
+		URL:               integration.URL,
+		Enabled:           integration.Enabled,
+		LastSequence:      integration.LastSequence,
+		DeliveredSequence: integration.DeliveredSequence,
+		FailureCount:      integration.FailureCount,
+		LastError:         integration.LastError,
+		LastDeliveryAt:    integration.LastDeliveryAt,
+		NextAttemptAt:     integration.NextAttemptAt,
+		CreatedAt:         integration.CreatedAt,
+	}
+	if revealSecret {
+		response.Secret = integration.Secret
+	}
+	return response
+}
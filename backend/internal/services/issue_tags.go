@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var ErrIssueTagNotFound = errors.New("issue tag not found")
+
+const (
+	// DefaultIssueTagTopValues is how many values of each tag the tags of an issue show
+	DefaultIssueTagTopValues = 5
+	// MaxIssueTagTopValues bounds how many values of each tag the tags of an issue show
+	MaxIssueTagTopValues = 20
+)
+
+// issueTagSummary is a tag of an issue's events, with how many events have it and how many
+// values it has
+type issueTagSummary struct {
+	Key          string
+	Count        int64
+	UniqueValues int64
+}
+
+// GetIssueTags returns the tags of an issue's stored events, those on the most events first,
+// each with its top most common values, from the tag values of the issue
+func (s *IssueService) GetIssueTags(issueID uuid.UUID, top int) (*dto.IssueTagsResponse, error) {
+	if top <= 0 || top > MaxIssueTagTopValues {
+		top = DefaultIssueTagTopValues
+	}
+
+	var summaries []issueTagSummary
+	if err := s.db.Model(&models.IssueTagValue{}).
+		Select("key, SUM(times_seen) AS count, COUNT(*) AS unique_values").
+		Where("issue_id = ? AND issue_id IN (?)", issueID, liveIssueIDs(s.db)).
+		Group("key").
+		Order("count DESC, key ASC").
+		Scan(&summaries).Error; err != nil {
+		return nil, fmt.Errorf("failed to get issue tags: %w", err)
+	}
+
+	var values []models.IssueTagValue
+	if len(summaries) > 0 {
+		ranked := s.db.Model(&models.IssueTagValue{}).
+			Select("issue_tag_values.*, ROW_NUMBER() OVER (PARTITION BY key ORDER BY times_seen DESC, value ASC) AS position").
+			Where("issue_id = ?", issueID)
+		if err := s.db.Table("(?) AS ranked", ranked).
+			Where("position <= ?", top).
+			Order("times_seen DESC, value ASC").
+			Find(&values).Error; err != nil {
+			return nil, fmt.Errorf("failed to get issue tag values: %w", err)
+		}
+	}
+
+	topValues := make(map[string][]models.IssueTagValue, len(summaries))
+	for _, value := range values {
+		topValues[value.Key] = append(topValues[value.Key], value)
+	}
+	tags := make([]dto.IssueTagResponse, len(summaries))
+	for i, summary := range summaries {
+		tags[i] = dto.IssueTagResponse{
+			Key:          summary.Key,
+			Count:        summary.Count,
+			UniqueValues: summary.UniqueValues,
+			TopValues:    convertIssueTagValues(topValues[summary.Key], summary.Count),
+		}
+	}
+	return &dto.IssueTagsResponse{Tags: tags}, nil
+}
+
+// GetIssueTagValues returns a page of the values of a tag of an issue's stored events, the
+// most common first. ErrIssueTagNotFound is returned when no event of the issue has the tag.
+func (s *IssueService) GetIssueTagValues(issueID uuid.UUID, key string, page, limit int) (*dto.IssueTagValuesResponse, error) {
+	page, limit = s.getPaginationDefaults(page, limit)
+	offset := (page - 1) * limit
+
+	var summary issueTagSummary
+	if err := s.db.Model(&models.IssueTagValue{}).
+		Select("key, SUM(times_seen) AS count, COUNT(*) AS unique_values").
+		Where("issue_id = ? AND key = ? AND issue_id IN (?)", issueID, key, liveIssueIDs(s.db)).
+		Group("key").
+		Scan(&summary).Error; err != nil {
+		return nil, fmt.Errorf("failed to get issue tag: %w", err)
+	}
+	if summary.UniqueValues == 0 {
+		return nil, ErrIssueTagNotFound
+	}
+
+	var values []models.IssueTagValue
+	if err := s.db.Where("issue_id = ? AND key = ?", issueID, key).
+		Order("times_seen DESC, value ASC").
+		Offset(offset).Limit(limit).
+		Find(&values).Error; err != nil {
+		return nil, fmt.Errorf("failed to get issue tag values: %w", err)
+	}
+
+	return &dto.IssueTagValuesResponse{
+		Key:        key,
+		Count:      summary.Count,
+		Values:     convertIssueTagValues(values, summary.Count),
+		Total:      summary.UniqueValues,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: dto.CalculateTotalPages(summary.UniqueValues, limit),
+	}, nil
+}
+
+// convertIssueTagValues converts the values of a tag found on count events, with their share
+// of those events rounded to a hundredth of a percent
+func convertIssueTagValues(values []models.IssueTagValue, count int64) []dto.IssueTagValueResponse {
+	responses := make([]dto.IssueTagValueResponse, len(values))
+	for i, value := range values {
+		var percentage float64
+		if count > 0 {
+			percentage = math.Round(float64(value.TimesSeen)*10000/float64(count)) / 100
+		}
+		responses[i] = dto.IssueTagValueResponse{
+			Value:      value.Value,
+			Count:      value.TimesSeen,
+			Percentage: percentage,
+			FirstSeen:  value.FirstSeen,
+			LastSeen:   value.LastSeen,
+		}
+	}
+	return responses
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// issueTitleRefreshWindow is how recently issues must have been seen to be retitled. It
+	// spans several runs, so issues whose counters were flushed late are still caught.
+	issueTitleRefreshWindow = 15 * time.Minute
+
+	// issueTitleRefreshBatchSize bounds the issues of a project retitled per run, most
+	// recently seen first
+	issueTitleRefreshBatchSize = 500
+)
+
+// IssueTitleService retitles the issues of projects with the refresh_issue_titles grouping
+// option after their latest event, so titles and culprits follow messages whose wording
+// changes between releases. Issues are otherwise titled after their first event.
+type IssueTitleService struct {
+	db           *database.DB
+	errorService *ErrorService
+}
+
+// NewIssueTitleService creates a new issue title service titling issues the way ingestion does
+func NewIssueTitleService(db *database.DB, errorService *ErrorService) *IssueTitleService {
+	return &IssueTitleService{
+		db:           db,
+		errorService: errorService,
+	}
+}
+
+// RefreshTitles retitles the recently seen issues of the projects that opted in
+func (s *IssueTitleService) RefreshTitles(ctx context.Context) error {
+	var projects []models.Project
+	if err := s.db.WithContext(ctx).Select("id", "grouping_config").
+		Where("grouping_config IS NOT NULL").
+		Find(&projects).Error; err != nil {
+		return fmt.Errorf("failed to get projects: %w", err)
+	}
+
+	since := time.Now().Add(-issueTitleRefreshWindow)
+	for _, project := range projects {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !decodeGroupingConfig(project.GroupingConfig).resolve().RefreshIssueTitles {
+			continue
+		}
+		if err := s.refreshProject(project.ID, since); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshProject retitles the project's issues seen since, leaving those whose latest event
+// gives the title and culprit they have
+func (s *IssueTitleService) refreshProject(projectID uuid.UUID, since time.Time) error {
+	// CSP issues are titled after the violated directive, not after their events' messages
+	var issues []models.Issue
+	if err := s.db.Where("project_id = ? AND last_seen >= ? AND type <> ?", projectID, since, models.TypeCSP).
+		Order("last_seen DESC").
+		Limit(issueTitleRefreshBatchSize).
+		Find(&issues).Error; err != nil {
+		return fmt.Errorf("failed to get issues: %w", err)
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+
+	latestEvents, err := latestByKey(s.db.DB, &models.Event{}, "issue_id", "timestamp",
+		collectIDs(issues, func(issue *models.Issue) uuid.UUID { return issue.ID }),
+		func(event *models.Event) uuid.UUID { return event.IssueID })
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range issues {
+		event, ok := latestEvents[issue.ID]
+		if !ok {
+			continue
+		}
+		data := storedEventData(&event)
+		title := s.errorService.generateIssueTitle(data)
+		culprit := s.errorService.generateCulprit(data)
+		sameCulprit := culprit == nil && issue.Culprit == nil ||
+			culprit != nil && issue.Culprit != nil && *culprit == *issue.Culprit
+		if title == issue.Title && sameCulprit {
+			continue
+		}
+		if err := s.db.Model(&models.Issue{}).Where("id = ?", issue.ID).Updates(map[string]interface{}{
+			"title":   title,
+			"culprit": culprit,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to retitle issue: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"minisentry/internal/dto"
+)
+
+// Fields of structured log entries read by LogEntryEvent, in order of preference. Nested
+// objects are read as dotted keys, so {"error": {"type": ...}} is error.type as in ECS.
+var (
+	logMessageFields     = []string{"message", "msg", "@message", "short_message", "log"}
+	logLevelFields       = []string{"level", "log.level", "severity", "levelname", "@level"}
+	logLoggerFields      = []string{"logger", "log.logger", "logger_name", "logger.name"}
+	logTimestampFields   = []string{"@timestamp", "timestamp", "time", "ts"}
+	logEnvironmentFields = []string{"environment", "env", "service.environment"}
+	logReleaseFields     = []string{"release", "service.version"}
+	logHostFields        = []string{"host.name", "hostname", "host"}
+
+	// Errors logged with the entry, as ECS error.* fields or the err object of pino and bunyan
+	logErrorPrefixes = []string{"error.", "err.", "exception."}
+)
+
+// Bounds of the tags created from the custom fields of log entries; fields that do not fit
+// are kept in the event's extra data
+const (
+	maxLogTagKeyLength   = 32
+	maxLogTagValueLength = 200
+)
+
+// LogEntryEvent maps a structured log entry, as sent by Logstash, Fluentd or Vector, to an
+// error event: the message, logger and level are read from their usual fields and the
+// other scalar fields become tags. It returns nil for entries below the error level.
+func LogEntryEvent(entry map[string]interface{}) *dto.ErrorEventRequest {
+	fields := make(map[string]interface{}, len(entry))
+	flattenLogFields("", entry, fields)
+
+	level, ok := logEntryLevel(takeLogField(fields, logLevelFields))
+	if !ok {
+		return nil
+	}
+	event := &dto.ErrorEventRequest{
+		Level: &level,
+		Tags:  make(map[string]string),
+		Extra: make(map[string]interface{}),
+	}
+
+	if message := logString(takeLogField(fields, logMessageFields)); message != "" {
+		event.Message = &dto.MessageData{Message: message}
+	}
+	if logger := logString(takeLogField(fields, logLoggerFields)); logger != "" {
+		event.Logger = &logger
+	}
+	if timestamp, ok := logTimestamp(takeLogField(fields, logTimestampFields)); ok {
+		event.Timestamp = &timestamp
+	}
+	if environment := logString(takeLogField(fields, logEnvironmentFields)); environment != "" {
+		event.Environment = &environment
+	}
+	if release := logString(takeLogField(fields, logReleaseFields)); release != "" {
+		event.Release = &release
+	}
+	if host := logString(takeLogField(fields, logHostFields)); host != "" {
+		event.ServerName = &host
+	}
+	if exception := takeLogException(fields); exception != nil {
+		event.Exception = &dto.ExceptionData{Values: []dto.ExceptionValue{*exception}}
+	}
+
+	for key, value := range fields {
+		tag := logString(value)
+		if tag == "" || len(key) > maxLogTagKeyLength || utf8.RuneCountInString(tag) > maxLogTagValueLength {
+			if value != nil {
+				event.Extra[key] = value
+			}
+			continue
+		}
+		event.Tags[key] = tag
+	}
+
+	return event
+}
+
+// logEntryLevel maps the level of a log entry to an event level, reporting whether it is
+// an error. Levels may be names, syslog severities (0-7) or pino and bunyan levels (10-60).
+func logEntryLevel(value interface{}) (string, bool) {
+	switch level := value.(type) {
+	case string:
+		switch strings.ToLower(strings.TrimSpace(level)) {
+		case "error", "err", "severe":
+			return "error", true
+		case "fatal", "critical", "crit", "alert", "emergency", "emerg", "panic":
+			return "fatal", true
+		}
+		if number, err := strconv.ParseFloat(level, 64); err == nil {
+			return logEntryLevel(number)
+		}
+	case float64:
+		switch {
+		case level >= 60:
+			return "fatal", true
+		case level >= 50:
+			return "error", true
+		case level <= 2:
+			return "fatal", true
+		case level == 3:
+			return "error", true
+		}
+	}
+	return "", false
+}
+
+// takeLogException reads and removes the error logged with an entry, nil without one
+func takeLogException(fields map[string]interface{}) *dto.ExceptionValue {
+	for _, prefix := range logErrorPrefixes {
+		exceptionType := logString(takeLogField(fields, []string{prefix + "type", prefix + "name", prefix + "class"}))
+		message := logString(takeLogField(fields, []string{prefix + "message", prefix + "msg"}))
+		stacktrace := logString(takeLogField(fields, []string{prefix + "stack_trace", prefix + "stack", prefix + "stacktrace"}))
+		if exceptionType == "" && message == "" {
+			continue
+		}
+
+		exception := &dto.ExceptionValue{Mechanism: &dto.MechanismData{Type: "log"}}
+		if exceptionType != "" {
+			exception.Type = &exceptionType
+		}
+		if message != "" {
+			exception.Value = &message
+		}
+		if frames := parseStacktraceText(stacktrace); len(frames) > 0 {
+			exception.Stacktrace = &dto.StacktraceData{Frames: frames}
+		}
+		return exception
+	}
+	return nil
+}
+
+// takeLogField returns and removes the first of keys set in fields, nil when none is
+func takeLogField(fields map[string]interface{}, keys []string) interface{} {
+	for _, key := range keys {
+		if value, ok := fields[key]; ok && value != nil {
+			delete(fields, key)
+			return value
+		}
+	}
+	return nil
+}
+
+// flattenLogFields adds the fields of nested objects of a log entry under dotted keys
+func flattenLogFields(prefix string, entry map[string]interface{}, fields map[string]interface{}) {
+	keys := make([]string, 0, len(entry))
+	for key := range entry {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if nested, ok := entry[key].(map[string]interface{}); ok && len(nested) > 0 {
+			flattenLogFields(prefix+key+".", nested, fields)
+			continue
+		}
+		fields[prefix+key] = entry[key]
+	}
+}
+
+// logTimestamp reads an RFC 3339 timestamp or Unix time in seconds or milliseconds
+func logTimestamp(value interface{}) (time.Time, bool) {
+	switch timestamp := value.(type) {
+	case string:
+		if parsed, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
+			return parsed, true
+		}
+		if number, err := strconv.ParseFloat(timestamp, 64); err == nil {
+			return logTimestamp(number)
+		}
+	case float64:
+		if timestamp <= 0 || math.IsInf(timestamp, 0) {
+			return time.Time{}, false
+		}
+		if timestamp > 1e11 {
+			timestamp /= 1000
+		}
+		return time.Unix(0, int64(timestamp*float64(time.Second))).UTC(), true
+	}
+	return time.Time{}, false
+}
+
+// logString formats a scalar field of a log entry, "" for objects and arrays
+func logString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case nil, map[string]interface{}, []interface{}:
+		return ""
+	}
+	return fmt.Sprint(value)
+}
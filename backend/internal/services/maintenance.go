@@ -0,0 +1,285 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrMaintenanceWindowNotFound  = errors.New("maintenance window not found")
+	ErrMaintenanceWindowInvalid   = errors.New("invalid maintenance window request")
+	ErrMaintenanceWindowNotActive = errors.New("maintenance window has already ended")
+)
+
+const (
+	// maintenanceCacheTTL bounds how long a server takes to notice a window started or
+	// ended through another server
+	maintenanceCacheTTL = 5 * time.Second
+
+	// DefaultMaintenanceMessage is shown to clients when a window has no message
+	DefaultMaintenanceMessage = "minisentry is under maintenance and read-only, retry later"
+
+	maxMaintenanceMessageLength = 1000
+)
+
+// MaintenanceService manages maintenance windows, which make the API read-only for an
+// organization or the whole instance during database maintenance. Active windows are
+// cached briefly and kept when the database cannot be reached, as it may be the one
+// under maintenance.
+type MaintenanceService struct {
+	db *database.DB
+
+	mu        sync.Mutex
+	instance  *models.MaintenanceWindow
+	orgs      map[uuid.UUID]*models.MaintenanceWindow
+	expires   time.Time
+	reloading bool
+	changes   int // counts windows started or ended here, to discard reloads racing them
+
+	// projectOrgs caches the organization of projects checked for maintenance
+	projectOrgs sync.Map
+}
+
+// NewMaintenanceService creates a new maintenance service
+func NewMaintenanceService(db *database.DB) *MaintenanceService {
+	return &MaintenanceService{
+		db:   db,
+		orgs: make(map[uuid.UUID]*models.MaintenanceWindow),
+	}
+}
+
+// SetMaintenance makes queued events wait while their organization is in a maintenance
+// window buffering ingestion. It must be called before the workers start.
+func (es *ErrorService) SetMaintenance(maintenance *MaintenanceService) {
+	es.maintenance = maintenance
+}
+
+// ListWindows returns the active maintenance windows and the ones ended in the last day
+func (ms *MaintenanceService) ListWindows() ([]dto.MaintenanceWindowResponse, error) {
+	var windows []models.MaintenanceWindow
+	err := ms.db.Where("ended_at IS NULL OR ended_at > ?", time.Now().Add(-24*time.Hour)).
+		Order("created_at DESC").Find(&windows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintenance windows: %w", err)
+	}
+
+	responses := make([]dto.MaintenanceWindowResponse, len(windows))
+	for i := range windows {
+		responses[i] = convertMaintenanceWindowToResponse(&windows[i])
+	}
+	return responses, nil
+}
+
+// StartWindow starts a maintenance window, ending the active window of the same scope
+func (ms *MaintenanceService) StartWindow(actorID uuid.UUID, request dto.MaintenanceWindowRequest) (*dto.MaintenanceWindowResponse, error) {
+	window := models.MaintenanceWindow{
+		OrganizationID: request.OrganizationID,
+		Message:        strings.TrimSpace(request.Message),
+		Ingestion:      strings.ToLower(strings.TrimSpace(request.Ingestion)),
+		StartedBy:      &actorID,
+	}
+	if window.Message == "" {
+		window.Message = DefaultMaintenanceMessage
+	}
+	if len(window.Message) > maxMaintenanceMessageLength {
+		return nil, fmt.Errorf("%w: message is longer than %d characters", ErrMaintenanceWindowInvalid, maxMaintenanceMessageLength)
+	}
+	switch window.Ingestion {
+	case "":
+		window.Ingestion = models.MaintenanceIngestionAccept
+	case models.MaintenanceIngestionAccept, models.MaintenanceIngestionBuffer, models.MaintenanceIngestionReject:
+	default:
+		return nil, fmt.Errorf("%w: ingestion must be accept, buffer or reject", ErrMaintenanceWindowInvalid)
+	}
+	if window.OrganizationID != nil {
+		var org models.Organization
+		if err := ms.db.Select("id").Where("id = ?", *window.OrganizationID).First(&org).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrOrganizationNotFound
+			}
+			return nil, fmt.Errorf("failed to get organization: %w", err)
+		}
+	}
+
+	err := ms.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		scope := tx.Model(&models.MaintenanceWindow{}).Where("ended_at IS NULL")
+		if window.OrganizationID != nil {
+			scope = scope.Where("organization_id = ?", *window.OrganizationID)
+		} else {
+			scope = scope.Where("organization_id IS NULL")
+		}
+		if err := scope.Updates(map[string]interface{}{"ended_at": now, "ended_by": actorID}).Error; err != nil {
+			return fmt.Errorf("failed to end the previous maintenance window: %w", err)
+		}
+
+		if err := tx.Create(&window).Error; err != nil {
+			return fmt.Errorf("failed to start maintenance window: %w", err)
+		}
+		return recordAudit(tx, &actorID, models.AuditMaintenanceStarted, "maintenance_window", window.ID, nil, map[string]interface{}{
+			"organization_id": window.OrganizationID,
+			"message":         window.Message,
+			"ingestion":       window.Ingestion,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ms.invalidate()
+	response := convertMaintenanceWindowToResponse(&window)
+	return &response, nil
+}
+
+// EndWindow ends a maintenance window, making its scope writable again
+func (ms *MaintenanceService) EndWindow(windowID, actorID uuid.UUID) (*dto.MaintenanceWindowResponse, error) {
+	var window models.MaintenanceWindow
+	err := ms.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", windowID).First(&window).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrMaintenanceWindowNotFound
+			}
+			return fmt.Errorf("failed to get maintenance window: %w", err)
+		}
+		if window.EndedAt != nil {
+			return ErrMaintenanceWindowNotActive
+		}
+
+		now := time.Now()
+		window.EndedAt = &now
+		window.EndedBy = &actorID
+		if err := tx.Model(&window).Updates(map[string]interface{}{"ended_at": now, "ended_by": actorID}).Error; err != nil {
+			return fmt.Errorf("failed to end maintenance window: %w", err)
+		}
+		return recordAudit(tx, &actorID, models.AuditMaintenanceEnded, "maintenance_window", window.ID, nil, map[string]interface{}{
+			"organization_id": window.OrganizationID,
+			"duration":        now.Sub(window.CreatedAt).Round(time.Second).String(),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ms.invalidate()
+	response := convertMaintenanceWindowToResponse(&window)
+	return &response, nil
+}
+
+// ActiveWindow returns the maintenance window applying to an organization, the instance's
+// first, or nil when there is none. A nil organization only checks the instance.
+func (ms *MaintenanceService) ActiveWindow(orgID *uuid.UUID) *models.MaintenanceWindow {
+	ms.mu.Lock()
+	reload := !ms.reloading && !time.Now().Before(ms.expires)
+	if reload {
+		ms.reloading = true
+	}
+	ms.mu.Unlock()
+	// One request reloads while the others keep using the previous windows, so a slow
+	// database does not hold up every request
+	if reload {
+		ms.reload()
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ms.instance != nil || orgID == nil {
+		return ms.instance
+	}
+	return ms.orgs[*orgID]
+}
+
+// DefersIngestion reports whether the queued events of a project must wait for the end of
+// a maintenance window: windows rejecting ingestion hold back queued events too
+func (ms *MaintenanceService) DefersIngestion(projectID uuid.UUID) bool {
+	if window := ms.ActiveWindow(nil); window != nil {
+		return window.Ingestion != models.MaintenanceIngestionAccept
+	}
+
+	orgID, ok := ms.ProjectOrganization(projectID)
+	if !ok {
+		return false
+	}
+	window := ms.ActiveWindow(&orgID)
+	return window != nil && window.Ingestion != models.MaintenanceIngestionAccept
+}
+
+// ProjectOrganization returns the organization of a project, caching it as projects never
+// move between organizations
+func (ms *MaintenanceService) ProjectOrganization(projectID uuid.UUID) (uuid.UUID, bool) {
+	if cached, ok := ms.projectOrgs.Load(projectID); ok {
+		return cached.(uuid.UUID), true
+	}
+
+	var project models.Project
+	if err := ms.db.Select("organization_id").Where("id = ?", projectID).First(&project).Error; err != nil {
+		return uuid.Nil, false
+	}
+	ms.projectOrgs.Store(projectID, project.OrganizationID)
+	return project.OrganizationID, true
+}
+
+// reload loads the active windows, keeping the previous ones when the database cannot be
+// reached
+func (ms *MaintenanceService) reload() {
+	ms.mu.Lock()
+	changes := ms.changes
+	ms.mu.Unlock()
+
+	var windows []models.MaintenanceWindow
+	err := ms.db.Where("ended_at IS NULL").Order("created_at").Find(&windows).Error
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.reloading = false
+	if changes != ms.changes {
+		return
+	}
+	ms.expires = time.Now().Add(maintenanceCacheTTL)
+	if err != nil {
+		log.Printf("Failed to load maintenance windows, keeping the previous ones: %v", err)
+		return
+	}
+
+	ms.instance = nil
+	ms.orgs = make(map[uuid.UUID]*models.MaintenanceWindow, len(windows))
+	for i := range windows {
+		if windows[i].OrganizationID == nil {
+			ms.instance = &windows[i]
+		} else {
+			ms.orgs[*windows[i].OrganizationID] = &windows[i]
+		}
+	}
+}
+
+// invalidate makes the next check reload the active windows
+func (ms *MaintenanceService) invalidate() {
+	ms.mu.Lock()
+	ms.changes++
+	ms.expires = time.Time{}
+	ms.mu.Unlock()
+}
+
+func convertMaintenanceWindowToResponse(window *models.MaintenanceWindow) dto.MaintenanceWindowResponse {
+	return dto.MaintenanceWindowResponse{
+		ID:             window.ID,
+		OrganizationID: window.OrganizationID,
+		Message:        window.Message,
+		Ingestion:      window.Ingestion,
+		Active:         window.EndedAt == nil,
+		StartedBy:      window.StartedBy,
+		StartedAt:      window.CreatedAt,
+		EndedAt:        window.EndedAt,
+		EndedBy:        window.EndedBy,
+	}
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidMinidump = errors.New("invalid minidump")
+
+const (
+	minidumpSignature = 0x504d444d // "MDMP"
+	minidumpPlatform  = "native"
+
+	minidumpModuleListStream = 4
+	minidumpExceptionStream  = 6
+	minidumpSystemInfoStream = 7
+
+	minidumpModuleSize = 108
+)
+
+// MinidumpInfo is the crash metadata that can be read from a minidump without symbolication
+type MinidumpInfo struct {
+	Timestamp        time.Time
+	OS               string
+	Arch             string
+	HasException     bool
+	CrashedThreadID  uint32
+	ExceptionCode    uint32
+	ExceptionName    string
+	ExceptionAddress uint64
+	CrashingModule   string
+}
+
+// minidumpModule is an entry of the module list stream
+type minidumpModule struct {
+	base uint64
+	size uint32
+	name string
+}
+
+var minidumpOSNames = map[uint32]string{
+	2:      "Windows",
+	0x8101: "macOS",
+	0x8102: "iOS",
+	0x8201: "Linux",
+	0x8202: "Solaris",
+	0x8203: "Android",
+	0x8206: "Fuchsia",
+}
+
+var minidumpArchNames = map[uint16]string{
+	0:      "x86",
+	5:      "arm",
+	6:      "ia64",
+	9:      "x86_64",
+	12:     "arm64",
+	0x8003: "arm64",
+}
+
+var windowsExceptionNames = map[uint32]string{
+	0x80000002: "EXCEPTION_DATATYPE_MISALIGNMENT",
+	0x80000003: "EXCEPTION_BREAKPOINT",
+	0xc0000005: "EXCEPTION_ACCESS_VIOLATION",
+	0xc0000006: "EXCEPTION_IN_PAGE_ERROR",
+	0xc0000008: "EXCEPTION_INVALID_HANDLE",
+	0xc000001d: "EXCEPTION_ILLEGAL_INSTRUCTION",
+	0xc000008c: "EXCEPTION_ARRAY_BOUNDS_EXCEEDED",
+	0xc0000094: "EXCEPTION_INT_DIVIDE_BY_ZERO",
+	0xc0000096: "EXCEPTION_PRIV_INSTRUCTION",
+	0xc00000fd: "EXCEPTION_STACK_OVERFLOW",
+	0xc0000374: "STATUS_HEAP_CORRUPTION",
+	0xc0000409: "STATUS_STACK_BUFFER_OVERRUN",
+	0xe06d7363: "Microsoft C++ Exception",
+}
+
+// Breakpad and Crashpad store the signal number as the exception code on POSIX systems
+var posixSignalNames = map[uint32]string{
+	4:  "SIGILL",
+	5:  "SIGTRAP",
+	6:  "SIGABRT",
+	7:  "SIGBUS",
+	8:  "SIGFPE",
+	11: "SIGSEGV",
+}
+
+// On Apple platforms the exception code is the Mach exception type
+var machExceptionNames = map[uint32]string{
+	1:  "EXC_BAD_ACCESS",
+	2:  "EXC_BAD_INSTRUCTION",
+	3:  "EXC_ARITHMETIC",
+	5:  "EXC_SOFTWARE",
+	6:  "EXC_BREAKPOINT",
+	10: "EXC_CRASH",
+	11: "EXC_RESOURCE",
+	12: "EXC_GUARD",
+}
+
+// ParseMinidump reads the header, system info, exception and module list streams of a minidump
+func ParseMinidump(data []byte) (*MinidumpInfo, error) {
+	if len(data) < 32 || binary.LittleEndian.Uint32(data) != minidumpSignature {
+		return nil, fmt.Errorf("%w: missing MDMP signature", ErrInvalidMinidump)
+	}
+
+	streamCount := binary.LittleEndian.Uint32(data[8:])
+	directoryRVA := binary.LittleEndian.Uint32(data[12:])
+	info := &MinidumpInfo{
+		Timestamp: time.Unix(int64(binary.LittleEndian.Uint32(data[20:])), 0).UTC(),
+	}
+
+	var modules []minidumpModule
+	var platformID uint32
+	for i := uint32(0); i < streamCount; i++ {
+		entry, ok := minidumpSlice(data, uint64(directoryRVA)+uint64(i)*12, 12)
+		if !ok {
+			return nil, fmt.Errorf("%w: stream directory is truncated", ErrInvalidMinidump)
+		}
+		streamType := binary.LittleEndian.Uint32(entry)
+		stream, ok := minidumpSlice(data, uint64(binary.LittleEndian.Uint32(entry[8:])), uint64(binary.LittleEndian.Uint32(entry[4:])))
+		if !ok {
+			// Skip streams pointing outside the file rather than rejecting the whole dump
+			continue
+		}
+
+		switch streamType {
+		case minidumpSystemInfoStream:
+			if len(stream) >= 24 {
+				info.Arch = minidumpArchNames[binary.LittleEndian.Uint16(stream)]
+				platformID = binary.LittleEndian.Uint32(stream[20:])
+				info.OS = minidumpOSNames[platformID]
+			}
+		case minidumpExceptionStream:
+			// ThreadId, alignment, then the exception record: code, flags, nested record, address
+			if len(stream) >= 32 {
+				info.HasException = true
+				info.CrashedThreadID = binary.LittleEndian.Uint32(stream)
+				info.ExceptionCode = binary.LittleEndian.Uint32(stream[8:])
+				info.ExceptionAddress = binary.LittleEndian.Uint64(stream[24:])
+			}
+		case minidumpModuleListStream:
+			modules = parseMinidumpModules(data, stream)
+		}
+	}
+
+	if info.HasException {
+		info.ExceptionName = minidumpExceptionName(platformID, info.ExceptionCode)
+		for _, module := range modules {
+			if info.ExceptionAddress >= module.base && info.ExceptionAddress < module.base+uint64(module.size) {
+				info.CrashingModule = module.name
+				break
+			}
+		}
+	}
+
+	return info, nil
+}
+
+func parseMinidumpModules(data, stream []byte) []minidumpModule {
+	if len(stream) < 4 {
+		return nil
+	}
+	count := binary.LittleEndian.Uint32(stream)
+
+	var modules []minidumpModule
+	for i := uint32(0); i < count; i++ {
+		offset := 4 + uint64(i)*minidumpModuleSize
+		if offset+minidumpModuleSize > uint64(len(stream)) {
+			break
+		}
+		entry := stream[offset:]
+		modules = append(modules, minidumpModule{
+			base: binary.LittleEndian.Uint64(entry),
+			size: binary.LittleEndian.Uint32(entry[8:]),
+			name: readMinidumpString(data, binary.LittleEndian.Uint32(entry[20:])),
+		})
+	}
+	return modules
+}
+
+// readMinidumpString decodes a MINIDUMP_STRING: a byte length followed by UTF-16LE text
+func readMinidumpString(data []byte, rva uint32) string {
+	header, ok := minidumpSlice(data, uint64(rva), 4)
+	if !ok {
+		return ""
+	}
+	raw, ok := minidumpSlice(data, uint64(rva)+4, uint64(binary.LittleEndian.Uint32(header)))
+	if !ok {
+		return ""
+	}
+
+	units := make([]uint16, len(raw)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(raw[i*2:])
+	}
+	return string(utf16.Decode(units))
+}
+
+func minidumpSlice(data []byte, offset, size uint64) ([]byte, bool) {
+	if offset > uint64(len(data)) || size > uint64(len(data))-offset {
+		return nil, false
+	}
+	return data[offset : offset+size], true
+}
+
+func minidumpExceptionName(platformID, code uint32) string {
+	var name string
+	switch platformID {
+	case 2:
+		name = windowsExceptionNames[code]
+	case 0x8101, 0x8102:
+		name = machExceptionNames[code]
+	default:
+		name = posixSignalNames[code]
+	}
+	if name == "" {
+		return fmt.Sprintf("0x%x", code)
+	}
+	return name
+}
+
+// ProcessMinidump creates a native crash event from a minidump upload. Event data sent
+// alongside the dump (release, tags, user...) is kept; the exception is derived from the dump.
+func (es *ErrorService) ProcessMinidump(projectID uuid.UUID, eventData *dto.ErrorEventRequest, dump []byte, clientIP, userAgent string) (*dto.ErrorEventResponse, error) {
+	info, err := ParseMinidump(dump)
+	if err != nil {
+		return nil, err
+	}
+
+	exceptionType := "Minidump"
+	exceptionValue := "Fatal Error: crash without exception record"
+	if info.HasException {
+		exceptionType = info.ExceptionName
+		exceptionValue = fmt.Sprintf("Fatal Error: %s", info.ExceptionName)
+	}
+
+	handled := false
+	exception := dto.ExceptionValue{
+		Type:      &exceptionType,
+		Value:     &exceptionValue,
+		Mechanism: &dto.MechanismData{Type: "minidump", Handled: &handled},
+	}
+	if info.CrashingModule != "" {
+		// A synthetic frame for the crashing module gives the issue a culprit and groups crashes by module
+		module := info.CrashingModule
+		filename := path.Base(strings.ReplaceAll(module, `\`, "/"))
+		address := fmt.Sprintf("0x%x", info.ExceptionAddress)
+		inApp := true
+		exception.Stacktrace = &dto.StacktraceData{Frames: []dto.StackFrame{{
+			Filename:        &filename,
+			Package:         &module,
+			InstructionAddr: &address,
+			InApp:           &inApp,
+		}}}
+	}
+	eventData.Exception = &dto.ExceptionData{Values: []dto.ExceptionValue{exception}}
+
+	level := string(models.LevelFatal)
+	eventData.Level = &level
+	platform := minidumpPlatform
+	eventData.Platform = &platform
+	if eventData.Timestamp == nil && info.Timestamp.Unix() > 0 {
+		eventData.Timestamp = &info.Timestamp
+	}
+
+	if eventData.Tags == nil {
+		eventData.Tags = make(map[string]string)
+	}
+	if info.OS != "" {
+		eventData.Tags["os.name"] = info.OS
+	}
+	if info.Arch != "" {
+		eventData.Tags["arch"] = info.Arch
+	}
+
+	if eventData.Extra == nil {
+		eventData.Extra = make(map[string]interface{})
+	}
+	eventData.Extra["minidump"] = map[string]interface{}{
+		"crashed_thread":    info.CrashedThreadID,
+		"exception_code":    fmt.Sprintf("0x%x", info.ExceptionCode),
+		"exception_address": fmt.Sprintf("0x%x", info.ExceptionAddress),
+		"crashing_module":   info.CrashingModule,
+	}
+
+	return es.ProcessErrorEvent(projectID, eventData, clientIP, userAgent)
+}
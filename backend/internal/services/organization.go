@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"minisentry/internal/database"
+	"minisentry/internal/dto"
 	"minisentry/internal/models"
 
 	"github.com/google/uuid"
@@ -155,15 +156,24 @@ func (s *OrganizationService) UpdateOrganization(userID, orgID uuid.UUID, name *
 
 	// Update fields
 	updates := make(map[string]interface{})
+	var diff settingDiff
 	if name != nil {
 		updates["name"] = *name
+		diff.add("name", org.Name, *name)
 	}
 	if description != nil {
 		updates["description"] = *description
+		diff.add("description", org.Description, *description)
 	}
 
 	if len(updates) > 0 {
-		if err := s.db.DB.Model(&org).Updates(updates).Error; err != nil {
+		err := s.db.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&org).Updates(updates).Error; err != nil {
+				return err
+			}
+			return recordSettingChanges(tx, userID, models.AuditOrganizationSettingChanged, settingTargetOrganization, org.ID, org.ID, nil, diff)
+		})
+		if err != nil {
 			return nil, fmt.Errorf("failed to update organization: %w", err)
 		}
 	}
@@ -171,6 +181,12 @@ func (s *OrganizationService) UpdateOrganization(userID, orgID uuid.UUID, name *
 	return &org, nil
 }
 
+// GetSettingHistory returns the changes of an organization's settings, newest first. An
+// empty setting returns the changes of every setting.
+func (s *OrganizationService) GetSettingHistory(orgID uuid.UUID, setting string, page, limit int) (*dto.SettingHistoryResponse, error) {
+	return listSettingChanges(s.db.DB, settingTargetOrganization, orgID, setting, page, limit)
+}
+
 // DeleteOrganization soft deletes organization (owner only)
 func (s *OrganizationService) DeleteOrganization(userID, orgID uuid.UUID) error {
 	// Check permissions (owner only)
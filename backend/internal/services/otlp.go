@@ -0,0 +1,291 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"minisentry/internal/dto"
+
+	"github.com/google/uuid"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// otlpEventNamespace derives the IDs of events created from OTLP log records, so an exporter
+// retrying a batch sends the same event IDs and retried records are rejected as duplicates
+var otlpEventNamespace = uuid.MustParse("6f1f4e0c-3d2b-5a8e-9c47-0a5b8e2d7f31")
+
+// otlpPlatforms maps the telemetry.sdk.language resource attribute to event platforms
+var otlpPlatforms = map[string]string{
+	"cpp":    "native",
+	"dotnet": "csharp",
+	"erlang": "elixir",
+	"go":     "go",
+	"java":   "java",
+	"nodejs": "node",
+	"php":    "php",
+	"python": "python",
+	"ruby":   "ruby",
+	"rust":   "rust",
+	"swift":  "swift",
+	"webjs":  "javascript",
+}
+
+// Frames of the stack traces OTel SDKs record in exception.stacktrace, which is the
+// language's own stack trace text
+var (
+	pythonFramePattern = regexp.MustCompile(`^\s*File "([^"]+)", line (\d+), in (.+)$`)
+	javaFramePattern   = regexp.MustCompile(`^\s*at ([\w$.<>/]+)\.([\w$<>\-]+)\(([^:)]*)(?::(\d+))?\)$`)
+	dotnetFramePattern = regexp.MustCompile(`^\s*at (.+?) in (.+):line (\d+)$`)
+	jsFramePattern     = regexp.MustCompile(`^\s*at (?:(.+?) \()?(.+?):(\d+):(\d+)\)?$`)
+	goFilePattern      = regexp.MustCompile(`^\s+(\S+\.go):(\d+)(?: \+0x[0-9a-f]+)?$`)
+)
+
+// OTLPLogException maps an OTLP log record carrying an exception, as described by the
+// exception.* semantic convention attributes, to an error event. It returns nil for records
+// without an exception.
+func OTLPLogException(resource *resourcepb.Resource, scope *commonpb.InstrumentationScope, record *logspb.LogRecord) *dto.ErrorEventRequest {
+	attributes := otlpAttributes(record.GetAttributes())
+	exceptionType, _ := attributes["exception.type"].(string)
+	exceptionMessage, _ := attributes["exception.message"].(string)
+	if exceptionType == "" && exceptionMessage == "" {
+		return nil
+	}
+	resourceAttributes := otlpAttributes(resource.GetAttributes())
+
+	eventID := otlpEventID(record)
+	event := &dto.ErrorEventRequest{
+		EventID: &eventID,
+		Tags:    make(map[string]string),
+		Extra:   make(map[string]interface{}),
+	}
+
+	timestamp := record.GetTimeUnixNano()
+	if timestamp == 0 {
+		timestamp = record.GetObservedTimeUnixNano()
+	}
+	if timestamp > 0 {
+		t := time.Unix(0, int64(timestamp)).UTC()
+		event.Timestamp = &t
+	}
+	level := otlpLevel(record.GetSeverityNumber())
+	event.Level = &level
+
+	exception := dto.ExceptionValue{Mechanism: &dto.MechanismData{Type: "otlp"}}
+	if exceptionType != "" {
+		exception.Type = &exceptionType
+	}
+	if exceptionMessage != "" {
+		exception.Value = &exceptionMessage
+	}
+	if stacktrace, _ := attributes["exception.stacktrace"].(string); stacktrace != "" {
+		if frames := parseStacktraceText(stacktrace); len(frames) > 0 {
+			exception.Stacktrace = &dto.StacktraceData{Frames: frames}
+		}
+		event.Extra["exception.stacktrace"] = stacktrace
+	}
+	event.Exception = &dto.ExceptionData{Values: []dto.ExceptionValue{exception}}
+
+	if body, ok := otlpValue(record.GetBody()).(string); ok && body != "" && body != exceptionMessage {
+		event.Message = &dto.MessageData{Message: body}
+	}
+
+	if language, _ := resourceAttributes["telemetry.sdk.language"].(string); otlpPlatforms[language] != "" {
+		platform := otlpPlatforms[language]
+		event.Platform = &platform
+	}
+	if name, _ := resourceAttributes["telemetry.sdk.name"].(string); name != "" {
+		version, _ := resourceAttributes["telemetry.sdk.version"].(string)
+		event.SDK = &dto.SDKInfo{Name: name, Version: version}
+	}
+	if version, _ := resourceAttributes["service.version"].(string); version != "" {
+		event.Release = &version
+	}
+	for _, key := range []string{"deployment.environment.name", "deployment.environment"} {
+		if environment, _ := resourceAttributes[key].(string); environment != "" {
+			event.Environment = &environment
+			break
+		}
+	}
+	if host, _ := resourceAttributes["host.name"].(string); host != "" {
+		event.ServerName = &host
+	}
+	if service, _ := resourceAttributes["service.name"].(string); service != "" {
+		event.Tags["service.name"] = service
+	}
+	if name := scope.GetName(); name != "" {
+		event.Logger = &name
+	}
+
+	// The remaining attributes are kept as they were sent
+	for key, value := range attributes {
+		if !strings.HasPrefix(key, "exception.") {
+			event.Extra[key] = value
+		}
+	}
+	event.Contexts = map[string]interface{}{
+		"otel": map[string]interface{}{"resource": resourceAttributes},
+	}
+	if traceID := otlpID(record.GetTraceId(), 16); traceID != "" {
+		trace := map[string]interface{}{"trace_id": traceID}
+		if spanID := otlpID(record.GetSpanId(), 8); spanID != "" {
+			trace["span_id"] = spanID
+		}
+		event.Contexts["trace"] = trace
+	}
+
+	return event
+}
+
+// otlpEventID derives the ID of the event of a log record from its content
+func otlpEventID(record *logspb.LogRecord) string {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(record)
+	if err != nil {
+		return uuid.New().String()
+	}
+	return uuid.NewSHA1(otlpEventNamespace, data).String()
+}
+
+// otlpLevel maps an OTLP severity number to an event level, error when it is unspecified
+func otlpLevel(severity logspb.SeverityNumber) string {
+	switch {
+	case severity == logspb.SeverityNumber_SEVERITY_NUMBER_UNSPECIFIED:
+		return "error"
+	case severity < logspb.SeverityNumber_SEVERITY_NUMBER_INFO:
+		return "debug"
+	case severity < logspb.SeverityNumber_SEVERITY_NUMBER_WARN:
+		return "info"
+	case severity < logspb.SeverityNumber_SEVERITY_NUMBER_ERROR:
+		return "warning"
+	case severity < logspb.SeverityNumber_SEVERITY_NUMBER_FATAL:
+		return "error"
+	}
+	return "fatal"
+}
+
+// otlpID returns a trace or span ID of size bytes as hex, "" when it is unset. OTLP/JSON
+// sends IDs as hex, which the protobuf JSON decoding reads as base64: encoding those bytes
+// back to base64 gives the hex that was sent.
+func otlpID(id []byte, size int) string {
+	switch len(id) {
+	case size:
+		if slices.ContainsFunc(id, func(b byte) bool { return b != 0 }) {
+			return hex.EncodeToString(id)
+		}
+	case size * 3 / 2:
+		if text := base64.StdEncoding.EncodeToString(id); isHex(text) {
+			return strings.ToLower(text)
+		}
+	}
+	return ""
+}
+
+func isHex(text string) bool {
+	_, err := hex.DecodeString(text)
+	return err == nil
+}
+
+// otlpAttributes converts OTLP attributes to a map
+func otlpAttributes(attributes []*commonpb.KeyValue) map[string]interface{} {
+	values := make(map[string]interface{}, len(attributes))
+	for _, attribute := range attributes {
+		values[attribute.GetKey()] = otlpValue(attribute.GetValue())
+	}
+	return values
+}
+
+// otlpValue converts an OTLP attribute value to its JSON equivalent
+func otlpValue(value *commonpb.AnyValue) interface{} {
+	switch v := value.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return v.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return v.BoolValue
+	case *commonpb.AnyValue_IntValue:
+		return v.IntValue
+	case *commonpb.AnyValue_DoubleValue:
+		return v.DoubleValue
+	case *commonpb.AnyValue_BytesValue:
+		return base64.StdEncoding.EncodeToString(v.BytesValue)
+	case *commonpb.AnyValue_ArrayValue:
+		values := make([]interface{}, 0, len(v.ArrayValue.GetValues()))
+		for _, item := range v.ArrayValue.GetValues() {
+			values = append(values, otlpValue(item))
+		}
+		return values
+	case *commonpb.AnyValue_KvlistValue:
+		return otlpAttributes(v.KvlistValue.GetValues())
+	}
+	return nil
+}
+
+// parseStacktraceText reads the frames of a stack trace printed by Python, Java, .NET,
+// JavaScript (V8) or Go, oldest call first. Only the frames of the outermost exception
+// are read from traces that print its causes.
+func parseStacktraceText(stacktrace string) []dto.StackFrame {
+	var frames []dto.StackFrame
+	oldestFirst := false
+	goFunction := ""
+	for _, line := range strings.Split(strings.ReplaceAll(stacktrace, "\r\n", "\n"), "\n") {
+		// Python prints the causes first, Java after the exception
+		if strings.HasPrefix(line, "The above exception was the direct cause") ||
+			strings.HasPrefix(line, "During handling of the above exception") {
+			frames = nil
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "Caused by:") {
+			break
+		}
+
+		if match := pythonFramePattern.FindStringSubmatch(line); match != nil {
+			oldestFirst = true
+			frames = append(frames, stackFrame(match[1], match[3], "", match[2], ""))
+		} else if match := javaFramePattern.FindStringSubmatch(line); match != nil {
+			frames = append(frames, stackFrame(match[3], match[2], match[1], match[4], ""))
+		} else if match := dotnetFramePattern.FindStringSubmatch(line); match != nil {
+			frames = append(frames, stackFrame(match[2], match[1], "", match[3], ""))
+		} else if match := jsFramePattern.FindStringSubmatch(line); match != nil {
+			frames = append(frames, stackFrame(match[2], match[1], "", match[3], match[4]))
+		} else if match := goFilePattern.FindStringSubmatch(line); match != nil && goFunction != "" {
+			frames = append(frames, stackFrame(match[1], goFunction, "", match[2], ""))
+			goFunction = ""
+		} else if open := strings.LastIndex(line, "("); open > 0 && strings.HasSuffix(line, ")") && line == strings.TrimSpace(line) {
+			// Go prints the function, then its file indented on the next line
+			goFunction = line[:open]
+		} else {
+			goFunction = ""
+		}
+	}
+
+	if !oldestFirst {
+		slices.Reverse(frames)
+	}
+	return frames
+}
+
+func stackFrame(filename, function, module, lineno, colno string) dto.StackFrame {
+	frame := dto.StackFrame{}
+	if filename != "" {
+		frame.Filename = &filename
+	}
+	if function != "" {
+		frame.Function = &function
+	}
+	if module != "" {
+		frame.Module = &module
+	}
+	if n, err := strconv.Atoi(lineno); err == nil {
+		frame.Lineno = &n
+	}
+	if n, err := strconv.Atoi(colno); err == nil {
+		frame.Colno = &n
+	}
+	return frame
+}
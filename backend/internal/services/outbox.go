@@ -0,0 +1,270 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/mail"
+	"minisentry/internal/models"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// outboxBatchSize bounds the messages claimed per drain round
+	outboxBatchSize = 50
+
+	// outboxLease is how long a claimed message is reserved for the drainer delivering it.
+	// A drainer that crashes mid-delivery releases its messages when the lease runs out.
+	outboxLease = 2 * time.Minute
+
+	// outboxBaseBackoff and outboxMaxBackoff bound the delay between retries of a failing message
+	outboxBaseBackoff = 10 * time.Second
+	outboxMaxBackoff  = time.Hour
+
+	// outboxRetention is how long delivered and failed messages are kept for inspection
+	outboxRetention = 7 * 24 * time.Hour
+)
+
+// outboxEmail is the payload of an email message
+type outboxEmail struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// outboxWebhook is the payload of a webhook message
+type outboxWebhook struct {
+	URL    string          `json:"url"`
+	Event  string          `json:"event"`
+	Secret string          `json:"secret,omitempty"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// OutboxService delivers external side effects recorded in the outbox table. Messages are
+// enqueued inside the transaction of the change that triggers them, so a rolled back change
+// sends nothing and a committed one is delivered even if the process crashes right after.
+// Delivery is at least once: a drainer that dies between sending and recording the result
+// sends the message again once its lease expires. Webhook receivers can deduplicate those
+// retries with the Idempotency-Key header.
+type OutboxService struct {
+	db          *database.DB
+	mailer      mail.Mailer
+	client      *http.Client
+	maxAttempts int
+}
+
+// NewOutboxService creates a new outbox service delivering emails through the mailer.
+// Messages are given up on after maxAttempts failed deliveries.
+func NewOutboxService(db *database.DB, mailer mail.Mailer, maxAttempts int) *OutboxService {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &OutboxService{
+		db:          db,
+		mailer:      mailer,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		maxAttempts: maxAttempts,
+	}
+}
+
+// EnqueueEmail records an email to send once tx commits. Enqueueing an idempotency key
+// that is already in the outbox is a no-op.
+func (ob *OutboxService) EnqueueEmail(tx *gorm.DB, key, to, subject, body string) error {
+	return ob.enqueue(tx, models.OutboxEmail, key, outboxEmail{To: to, Subject: subject, Body: body})
+}
+
+// EnqueueWebhook records a JSON webhook to post once tx commits, signed with the secret
+// when one is given. Enqueueing an idempotency key that is already in the outbox is a no-op.
+func (ob *OutboxService) EnqueueWebhook(tx *gorm.DB, key, url, event, secret string, body interface{}) error {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook body: %w", err)
+	}
+	return ob.enqueue(tx, models.OutboxWebhook, key, outboxWebhook{URL: url, Event: event, Secret: secret, Body: encoded})
+}
+
+func (ob *OutboxService) enqueue(tx *gorm.DB, kind, key string, payload interface{}) error {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox payload: %w", err)
+	}
+
+	message := models.OutboxMessage{
+		Kind:           kind,
+		IdempotencyKey: key,
+		Payload:        datatypes.JSON(encoded),
+		Status:         models.OutboxPending,
+		NextAttemptAt:  time.Now(),
+	}
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "idempotency_key"}},
+		DoNothing: true,
+	}).Create(&message).Error; err != nil {
+		return fmt.Errorf("failed to enqueue outbox message: %w", err)
+	}
+	return nil
+}
+
+// Drain delivers the messages that are due, a batch at a time, until none are left or the
+// context is cancelled. Several drainers (API servers and workers) can run at once; each
+// message is claimed by one of them at a time.
+func (ob *OutboxService) Drain(ctx context.Context) error {
+	for ctx.Err() == nil {
+		messages, err := ob.claim()
+		if err != nil {
+			return err
+		}
+		for i := range messages {
+			ob.deliver(ctx, &messages[i])
+		}
+		if len(messages) < outboxBatchSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+// Prune deletes delivered and failed messages past the retention period
+func (ob *OutboxService) Prune(ctx context.Context) error {
+	if err := ob.db.WithContext(ctx).
+		Where("status <> ? AND updated_at < ?", models.OutboxPending, time.Now().Add(-outboxRetention)).
+		Delete(&models.OutboxMessage{}).Error; err != nil {
+		return fmt.Errorf("failed to prune outbox: %w", err)
+	}
+	return nil
+}
+
+// claim leases due messages to this drainer. The lease is taken with a conditional update
+// per message, so a message another drainer claimed in the meantime is skipped.
+func (ob *OutboxService) claim() ([]models.OutboxMessage, error) {
+	now := time.Now()
+	var candidates []models.OutboxMessage
+	if err := ob.db.Where("status = ? AND next_attempt_at <= ? AND (locked_until IS NULL OR locked_until < ?)",
+		models.OutboxPending, now, now).
+		Order("next_attempt_at ASC").
+		Limit(outboxBatchSize).
+		Find(&candidates).Error; err != nil {
+		return nil, fmt.Errorf("failed to load pending outbox messages: %w", err)
+	}
+
+	lockedUntil := now.Add(outboxLease)
+	claimed := candidates[:0]
+	for _, message := range candidates {
+		result := ob.db.Model(&models.OutboxMessage{}).
+			Where("id = ? AND status = ? AND (locked_until IS NULL OR locked_until < ?)", message.ID, models.OutboxPending, now).
+			Update("locked_until", lockedUntil)
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to claim outbox message: %w", result.Error)
+		}
+		if result.RowsAffected == 1 {
+			claimed = append(claimed, message)
+		}
+	}
+	return claimed, nil
+}
+
+// deliver sends a claimed message and records the outcome, scheduling a retry with
+// exponential backoff on failure
+func (ob *OutboxService) deliver(ctx context.Context, message *models.OutboxMessage) {
+	deliveryErr := ob.send(ctx, message)
+
+	now := time.Now()
+	attempts := message.Attempts + 1
+	updates := map[string]interface{}{
+		"attempts":     attempts,
+		"locked_until": nil,
+		"updated_at":   now,
+	}
+	switch {
+	case deliveryErr == nil:
+		updates["status"] = models.OutboxSent
+		updates["sent_at"] = now
+		updates["last_error"] = nil
+	case attempts >= ob.maxAttempts:
+		log.Printf("Giving up on outbox message %s (%s) after %d attempts: %v", message.ID, message.Kind, attempts, deliveryErr)
+		updates["status"] = models.OutboxFailed
+		updates["last_error"] = deliveryErr.Error()
+	default:
+		backoff := outboxBaseBackoff << min(attempts-1, 10)
+		if backoff > outboxMaxBackoff {
+			backoff = outboxMaxBackoff
+		}
+		updates["next_attempt_at"] = now.Add(backoff)
+		updates["last_error"] = deliveryErr.Error()
+	}
+
+	if err := ob.db.Model(&models.OutboxMessage{}).Where("id = ?", message.ID).Updates(updates).Error; err != nil {
+		log.Printf("Failed to record delivery of outbox message %s: %v", message.ID, err)
+	}
+}
+
+func (ob *OutboxService) send(ctx context.Context, message *models.OutboxMessage) error {
+	switch message.Kind {
+	case models.OutboxEmail:
+		var email outboxEmail
+		if err := json.Unmarshal(message.Payload, &email); err != nil {
+			return fmt.Errorf("invalid email payload: %w", err)
+		}
+		return ob.mailer.Send(email.To, email.Subject, email.Body)
+	case models.OutboxWebhook:
+		var webhook outboxWebhook
+		if err := json.Unmarshal(message.Payload, &webhook); err != nil {
+			return fmt.Errorf("invalid webhook payload: %w", err)
+		}
+		return ob.postWebhook(ctx, message.IdempotencyKey, webhook)
+	default:
+		return fmt.Errorf("unknown outbox message kind %q", message.Kind)
+	}
+}
+
+func (ob *OutboxService) postWebhook(ctx context.Context, key string, webhook outboxWebhook) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(webhook.Body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "minisentry-webhooks/1")
+	req.Header.Set("X-Minisentry-Event", webhook.Event)
+	req.Header.Set("Idempotency-Key", key)
+	if webhook.Secret != "" {
+		signWebhook(req, webhook.Secret, webhook.Body, time.Now())
+	}
+
+	resp, err := ob.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// signWebhook sets the timestamp and HMAC-SHA256 signature over "<timestamp>.<body>" headers
+// receivers verify webhooks with
+func signWebhook(req *http.Request, secret string, body []byte, now time.Time) {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+
+	req.Header.Set("X-Minisentry-Timestamp", timestamp)
+	req.Header.Set("X-Minisentry-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+}
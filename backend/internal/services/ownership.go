@@ -0,0 +1,308 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+var ErrOwnershipRulesInvalid = errors.New("invalid ownership rules")
+
+// Ownership rule types
+const (
+	OwnershipRulePath   = "path"   // matches the paths of stack frames
+	OwnershipRuleModule = "module" // matches the modules of stack frames and the culprit
+	OwnershipRuleTag    = "tag"    // matches the value of a tag
+)
+
+const (
+	// ownershipRulesCacheTTL bounds how long ingestion uses a project's rules before
+	// reloading them, so changes made through another server apply within this time
+	ownershipRulesCacheTTL = 30 * time.Second
+
+	maxOwnershipRules             = 100
+	maxOwnershipRulePatternLength = 200
+	maxOwnershipRuleKeyLength     = 200
+)
+
+type compiledOwnershipRules struct {
+	rules   []compiledOwnershipRule
+	expires time.Time
+}
+
+type compiledOwnershipRule struct {
+	rule dto.OwnershipRule
+	re   *regexp.Regexp
+}
+
+// OwnershipService manages the ownership rules of projects and finds the owners of the
+// issues ingestion creates. Rules are cached briefly per project.
+type OwnershipService struct {
+	db *database.DB
+
+	mu    sync.Mutex
+	cache map[uuid.UUID]*compiledOwnershipRules
+}
+
+// NewOwnershipService creates a new ownership service
+func NewOwnershipService(db *database.DB) *OwnershipService {
+	return &OwnershipService{
+		db:    db,
+		cache: make(map[uuid.UUID]*compiledOwnershipRules),
+	}
+}
+
+// SetOwnership makes ingestion assign new issues by their project's ownership rules. It
+// must be called before the server starts.
+func (es *ErrorService) SetOwnership(ownership *OwnershipService) {
+	es.ownership = ownership
+}
+
+// GetOwnershipRules returns a project's ownership rules
+func (ows *OwnershipService) GetOwnershipRules(projectID uuid.UUID) (*dto.OwnershipRulesResponse, error) {
+	var project models.Project
+	if err := ows.db.Select("id", "ownership_rules").Where("id = ?", projectID).First(&project).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("failed to get ownership rules: %w", err)
+	}
+	return &dto.OwnershipRulesResponse{ProjectID: projectID, Rules: decodeOwnershipRules(project.OwnershipRules)}, nil
+}
+
+// UpdateOwnershipRules replaces a project's ownership rules, recording the change in the
+// project's setting history. Owners must be members of the project's organization.
+func (ows *OwnershipService) UpdateOwnershipRules(userID, projectID uuid.UUID, request dto.OwnershipRulesRequest) (*dto.OwnershipRulesResponse, error) {
+	var project models.Project
+	if err := ows.db.Where("id = ?", projectID).First(&project).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	rules, err := normalizeOwnershipRules(request.Rules)
+	if err != nil {
+		return nil, err
+	}
+	members, err := ows.organizationMembers(project.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if !members[rule.OwnerID] {
+			return nil, fmt.Errorf("%w: owner %s is not a member of the project's organization", ErrOwnershipRulesInvalid, rule.OwnerID)
+		}
+	}
+	encoded, err := json.Marshal(rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode ownership rules: %w", err)
+	}
+
+	var diff settingDiff
+	diff.add("ownership_rules", decodeOwnershipRules(project.OwnershipRules), rules)
+
+	err = ows.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&project).Update("ownership_rules", datatypes.JSON(encoded)).Error; err != nil {
+			return err
+		}
+		return recordSettingChanges(tx, userID, models.AuditProjectSettingChanged, settingTargetProject, projectID, project.OrganizationID, &projectID, diff)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update ownership rules: %w", err)
+	}
+
+	ows.mu.Lock()
+	delete(ows.cache, projectID)
+	ows.mu.Unlock()
+
+	return &dto.OwnershipRulesResponse{ProjectID: projectID, Rules: rules}, nil
+}
+
+// owner returns the first of a project's rules matching an event and the culprit of its
+// new issue; false when none does. No rules apply when they cannot be loaded.
+func (ows *OwnershipService) owner(data *dto.NormalizedErrorData, culprit *string) (dto.OwnershipRule, bool) {
+	rules, err := ows.getCompiled(data.ProjectID)
+	if err != nil {
+		log.Printf("Failed to load ownership rules of project %s: %v", data.ProjectID, err)
+		return dto.OwnershipRule{}, false
+	}
+	for _, rule := range rules.rules {
+		if rule.matches(data, culprit) {
+			return rule.rule, true
+		}
+	}
+	return dto.OwnershipRule{}, false
+}
+
+func (r *compiledOwnershipRule) matches(data *dto.NormalizedErrorData, culprit *string) bool {
+	switch r.rule.Type {
+	case OwnershipRuleTag:
+		value, ok := data.Tags[r.rule.Key]
+		return ok && r.re.MatchString(value)
+	case OwnershipRuleModule:
+		if culprit != nil && r.re.MatchString(*culprit) {
+			return true
+		}
+	}
+
+	frames := data.StackTrace
+	for _, exception := range data.Exceptions {
+		if exception.Stacktrace != nil {
+			frames = append(frames[:len(frames):len(frames)], exception.Stacktrace.Frames...)
+		}
+	}
+	for _, frame := range frames {
+		var texts []*string
+		if r.rule.Type == OwnershipRulePath {
+			texts = []*string{frame.AbsPath, frame.Filename}
+		} else {
+			texts = []*string{frame.Module}
+		}
+		for _, text := range texts {
+			if text != nil && *text != "" && r.re.MatchString(*text) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compileOwnershipPattern compiles the pattern of a rule: an in-app path pattern for path
+// rules, else an inbound filter pattern
+func compileOwnershipPattern(rule dto.OwnershipRule) (*regexp.Regexp, error) {
+	if rule.Type == OwnershipRulePath {
+		return compileInAppPattern(rule.Pattern)
+	}
+	return compileInboundFilterPattern(rule.Pattern)
+}
+
+// normalizeOwnershipRules trims and checks the rules, dropping repeated ones
+func normalizeOwnershipRules(rules []dto.OwnershipRule) ([]dto.OwnershipRule, error) {
+	if len(rules) > maxOwnershipRules {
+		return nil, fmt.Errorf("%w: at most %d rules are allowed", ErrOwnershipRulesInvalid, maxOwnershipRules)
+	}
+
+	normalized := make([]dto.OwnershipRule, 0, len(rules))
+	seen := make(map[dto.OwnershipRule]bool, len(rules))
+	for _, rule := range rules {
+		rule.Type = strings.ToLower(strings.TrimSpace(rule.Type))
+		rule.Key = strings.TrimSpace(rule.Key)
+		rule.Pattern = strings.TrimSpace(rule.Pattern)
+
+		switch rule.Type {
+		case OwnershipRulePath, OwnershipRuleModule:
+			rule.Key = ""
+		case OwnershipRuleTag:
+			if rule.Key == "" {
+				return nil, fmt.Errorf("%w: tag rules require a key", ErrOwnershipRulesInvalid)
+			}
+			if len(rule.Key) > maxOwnershipRuleKeyLength {
+				return nil, fmt.Errorf("%w: key %q is longer than %d characters", ErrOwnershipRulesInvalid, rule.Key, maxOwnershipRuleKeyLength)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unknown type %q, expected path, module or tag", ErrOwnershipRulesInvalid, rule.Type)
+		}
+		if rule.Pattern == "" {
+			return nil, fmt.Errorf("%w: pattern is required", ErrOwnershipRulesInvalid)
+		}
+		if len(rule.Pattern) > maxOwnershipRulePatternLength {
+			return nil, fmt.Errorf("%w: pattern %q is longer than %d characters", ErrOwnershipRulesInvalid, rule.Pattern, maxOwnershipRulePatternLength)
+		}
+		if _, err := compileOwnershipPattern(rule); err != nil {
+			return nil, fmt.Errorf("%w: invalid pattern %q", ErrOwnershipRulesInvalid, rule.Pattern)
+		}
+		if rule.OwnerID == uuid.Nil {
+			return nil, fmt.Errorf("%w: owner_id is required", ErrOwnershipRulesInvalid)
+		}
+
+		if seen[rule] {
+			continue
+		}
+		seen[rule] = true
+		normalized = append(normalized, rule)
+	}
+	return normalized, nil
+}
+
+// decodeOwnershipRules returns the rules stored with a project; none when unset or unreadable
+func decodeOwnershipRules(stored datatypes.JSON) []dto.OwnershipRule {
+	rules := []dto.OwnershipRule{}
+	if len(stored) > 0 {
+		json.Unmarshal(stored, &rules)
+	}
+	return rules
+}
+
+// organizationMembers returns the users who are members of an organization
+func (ows *OwnershipService) organizationMembers(organizationID uuid.UUID) (map[uuid.UUID]bool, error) {
+	var userIDs []uuid.UUID
+	if err := ows.db.Model(&models.OrganizationMember{}).Where("organization_id = ?", organizationID).
+		Pluck("user_id", &userIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to get organization members: %w", err)
+	}
+	members := make(map[uuid.UUID]bool, len(userIDs))
+	for _, id := range userIDs {
+		members[id] = true
+	}
+	return members, nil
+}
+
+// getCompiled returns a project's rules from the cache, loading them when missing or
+// expired. Rules whose owner left the organization are skipped.
+func (ows *OwnershipService) getCompiled(projectID uuid.UUID) (*compiledOwnershipRules, error) {
+	now := time.Now()
+
+	ows.mu.Lock()
+	rules, ok := ows.cache[projectID]
+	ows.mu.Unlock()
+	if ok && now.Before(rules.expires) {
+		return rules, nil
+	}
+
+	rules = &compiledOwnershipRules{expires: now.Add(ownershipRulesCacheTTL)}
+	var project models.Project
+	err := ows.db.Select("id", "organization_id", "ownership_rules").Where("id = ?", projectID).First(&project).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get ownership rules: %w", err)
+	}
+	if stored := decodeOwnershipRules(project.OwnershipRules); len(stored) > 0 {
+		members, err := ows.organizationMembers(project.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range stored {
+			if !members[rule.OwnerID] {
+				continue
+			}
+			if re, err := compileOwnershipPattern(rule); err == nil {
+				rules.rules = append(rules.rules, compiledOwnershipRule{rule: rule, re: re})
+			}
+		}
+	}
+
+	ows.mu.Lock()
+	if len(ows.cache) >= projectAccessCacheSweepSize {
+		for id, cached := range ows.cache {
+			if now.After(cached.expires) {
+				delete(ows.cache, id)
+			}
+		}
+	}
+	ows.cache[projectID] = rules
+	ows.mu.Unlock()
+	return rules, nil
+}
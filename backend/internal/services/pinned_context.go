@@ -0,0 +1,136 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"minisentry/internal/models"
+
+	"gorm.io/datatypes"
+)
+
+const (
+	// maxPinnedContextKeys bounds the keys a project pins to its issue list
+	maxPinnedContextKeys = 10
+
+	maxPinnedContextKeyLength = 200
+
+	// maxPinnedValueLength bounds the characters of each pinned value copied onto the issue list
+	maxPinnedValueLength = 200
+)
+
+// NormalizePinnedContextKeys validates the keys a project pins to its issue list and
+// returns them trimmed, without duplicates and in the given order
+func NormalizePinnedContextKeys(values []string) ([]string, error) {
+	keys := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		key := strings.TrimSpace(value)
+		if key == "" {
+			continue
+		}
+		if len(key) > maxPinnedContextKeyLength || strings.HasPrefix(key, ".") || strings.HasSuffix(key, ".") || strings.Contains(key, "..") {
+			return nil, fmt.Errorf("%w: %q", ErrProjectInvalidPinnedKey, value)
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	if len(keys) > maxPinnedContextKeys {
+		return nil, fmt.Errorf("%w: at most %d keys can be pinned", ErrProjectInvalidPinnedKey, maxPinnedContextKeys)
+	}
+	return keys, nil
+}
+
+// ProjectPinnedContextKeys decodes the keys a project pins to its issue list
+func ProjectPinnedContextKeys(list datatypes.JSON) []string {
+	keys := []string{}
+	if len(list) > 0 {
+		json.Unmarshal(list, &keys)
+	}
+	return keys
+}
+
+// encodePinnedContextKeys encodes pinned keys for storage, nil when there are none
+func encodePinnedContextKeys(keys []string) interface{} {
+	if len(keys) == 0 {
+		return nil
+	}
+	encoded, _ := json.Marshal(keys)
+	return datatypes.JSON(encoded)
+}
+
+// pinnedContextValues returns the values of the pinned keys found in an event. A key is
+// looked up in the event's tags, then in its extra data, where dots separate the keys of
+// nested objects; "user." keys are read from the user context instead.
+func pinnedContextValues(event *models.Event, keys []string) map[string]string {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	var tags map[string]string
+	var extra, user map[string]interface{}
+	if len(event.Tags) > 0 {
+		json.Unmarshal(event.Tags, &tags)
+	}
+	if len(event.ExtraData) > 0 {
+		json.Unmarshal(event.ExtraData, &extra)
+	}
+	if len(event.UserContext) > 0 {
+		json.Unmarshal(event.UserContext, &user)
+	}
+
+	values := make(map[string]string, len(keys))
+	for _, key := range keys {
+		var value interface{}
+		var found bool
+		if field, ok := strings.CutPrefix(key, "user."); ok {
+			value, found = lookupPinnedPath(user, field)
+		} else if tag, ok := tags[key]; ok {
+			value, found = tag, true
+		} else {
+			value, found = lookupPinnedPath(extra, key)
+		}
+		if !found || value == nil {
+			continue
+		}
+
+		text, ok := value.(string)
+		if !ok {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				continue
+			}
+			text = string(encoded)
+		}
+		values[key] = *truncateStringPtr(&text, maxPinnedValueLength)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+// lookupPinnedPath finds a key in decoded JSON data, trying it whole before splitting it on
+// dots into the keys of nested objects
+func lookupPinnedPath(data map[string]interface{}, key string) (interface{}, bool) {
+	if data == nil {
+		return nil, false
+	}
+	if value, ok := data[key]; ok {
+		return value, true
+	}
+
+	head, rest, ok := strings.Cut(key, ".")
+	if !ok {
+		return nil, false
+	}
+	nested, ok := data[head].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return lookupPinnedPath(nested, rest)
+}
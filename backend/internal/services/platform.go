@@ -0,0 +1,166 @@
+package services
+
+import (
+	"path"
+	"strings"
+
+	"minisentry/internal/dto"
+)
+
+// defaultPlatform is the platform of events whose platform cannot be inferred
+const defaultPlatform = "javascript"
+
+// sdkPlatforms maps SDK name prefixes to platforms, more specific prefixes first
+var sdkPlatforms = []struct {
+	prefix   string
+	platform string
+}{
+	{"sentry.javascript.node", "node"},
+	{"sentry.javascript.bun", "node"},
+	{"sentry.javascript.deno", "node"},
+	{"sentry.javascript.react-native", "javascript"},
+	{"sentry.javascript", "javascript"},
+	{"sentry.python", "python"},
+	{"sentry.go", "go"},
+	{"sentry.java", "java"},
+	{"sentry.kotlin", "java"},
+	{"sentry.dotnet", "csharp"},
+	{"sentry.php", "php"},
+	{"sentry.ruby", "ruby"},
+	{"sentry.cocoa", "cocoa"},
+	{"sentry.native", "native"},
+	{"sentry.rust", "native"},
+	{"sentry.elixir", "elixir"},
+	{"sentry.dart", "dart"},
+	{"sentry.flutter", "dart"},
+}
+
+// extensionPlatforms maps the file extensions of stack frames to platforms
+var extensionPlatforms = map[string]string{
+	".js":     "javascript",
+	".mjs":    "javascript",
+	".cjs":    "javascript",
+	".jsx":    "javascript",
+	".ts":     "javascript",
+	".tsx":    "javascript",
+	".vue":    "javascript",
+	".py":     "python",
+	".go":     "go",
+	".java":   "java",
+	".kt":     "java",
+	".scala":  "java",
+	".groovy": "java",
+	".cs":     "csharp",
+	".php":    "php",
+	".rb":     "ruby",
+	".swift":  "cocoa",
+	".m":      "cocoa",
+	".c":      "native",
+	".cc":     "native",
+	".cpp":    "native",
+	".h":      "native",
+	".rs":     "native",
+	".ex":     "elixir",
+	".exs":    "elixir",
+	".dart":   "dart",
+}
+
+// exceptionPlatforms maps exception types only one platform raises to it. Types shared by
+// several platforms, like TypeError, are left out.
+var exceptionPlatforms = map[string]string{
+	"ReferenceError":      "javascript",
+	"RangeError":          "javascript",
+	"URIError":            "javascript",
+	"EvalError":           "javascript",
+	"AggregateError":      "javascript",
+	"ValueError":          "python",
+	"KeyError":            "python",
+	"AttributeError":      "python",
+	"IndexError":          "python",
+	"ZeroDivisionError":   "python",
+	"ModuleNotFoundError": "python",
+	"ImportError":         "python",
+	"NoMethodError":       "ruby",
+	"ArgumentError":       "ruby",
+}
+
+// resolvePlatform returns the platform an event was sent from: the one it names, otherwise
+// the one inferred from its SDK, then its stack frames, then its exception types
+func resolvePlatform(eventData *dto.ErrorEventRequest) string {
+	if eventData.Platform != nil {
+		if platform := strings.ToLower(strings.TrimSpace(*eventData.Platform)); platform != "" && platform != "other" {
+			return platform
+		}
+	}
+
+	if eventData.SDK != nil {
+		name := strings.ToLower(eventData.SDK.Name)
+		for _, sdk := range sdkPlatforms {
+			if strings.HasPrefix(name, sdk.prefix) {
+				return sdk.platform
+			}
+		}
+	}
+
+	if eventData.Exception != nil {
+		// Frames are checked from the crashing one, the last of each stack trace
+		for _, exception := range eventData.Exception.Values {
+			if exception.Stacktrace == nil {
+				continue
+			}
+			frames := exception.Stacktrace.Frames
+			for i := len(frames) - 1; i >= 0; i-- {
+				if platform := framePlatform(&frames[i]); platform != "" {
+					return platform
+				}
+			}
+		}
+
+		for _, exception := range eventData.Exception.Values {
+			if platform := exceptionTypePlatform(exception.Type); platform != "" {
+				return platform
+			}
+		}
+	}
+
+	return defaultPlatform
+}
+
+// framePlatform infers the platform of a stack frame from the platform it names or the
+// extension of its file, "" when neither is known
+func framePlatform(frame *dto.StackFrame) string {
+	if frame.Platform != nil && *frame.Platform != "" && *frame.Platform != "other" {
+		return strings.ToLower(*frame.Platform)
+	}
+
+	for _, file := range []*string{frame.Filename, frame.AbsPath} {
+		if file == nil {
+			continue
+		}
+		// Drop query strings and fragments of script URLs
+		name, _, _ := strings.Cut(*file, "?")
+		name, _, _ = strings.Cut(name, "#")
+		if platform, ok := extensionPlatforms[strings.ToLower(path.Ext(name))]; ok {
+			return platform
+		}
+	}
+	return ""
+}
+
+// exceptionTypePlatform infers the platform of an exception type, "" when it is ambiguous
+func exceptionTypePlatform(exceptionType *string) string {
+	if exceptionType == nil {
+		return ""
+	}
+	name := *exceptionType
+	switch {
+	case strings.HasPrefix(name, "java.") || strings.HasPrefix(name, "javax.") || strings.HasPrefix(name, "kotlin."):
+		return "java"
+	case strings.HasPrefix(name, "System."):
+		return "csharp"
+	case strings.HasPrefix(name, "*") || strings.HasPrefix(name, "runtime."):
+		// Go reports the dynamic type of errors, such as *errors.errorString
+		return "go"
+	}
+	return exceptionPlatforms[name]
+}
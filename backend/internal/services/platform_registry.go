@@ -0,0 +1,114 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"minisentry/internal/dto"
+)
+
+// platformPattern is the shape of platform identifiers, known or not
+var platformPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,49}$`)
+
+// defaultProjectPlatforms are the platforms known out of the box, with their aliases
+var defaultProjectPlatforms = []dto.PlatformResponse{
+	{ID: "javascript", Aliases: []string{"js", "browser"}},
+	{ID: "node", Aliases: []string{"nodejs", "node.js"}},
+	{ID: "react-native", Aliases: []string{"reactnative"}},
+	{ID: "python", Aliases: []string{"py"}},
+	{ID: "go", Aliases: []string{"golang"}},
+	{ID: "java", Aliases: []string{"jvm", "kotlin"}},
+	{ID: "android"},
+	{ID: "dotnet", Aliases: []string{"csharp", "c#", ".net"}},
+	{ID: "php"},
+	{ID: "ruby", Aliases: []string{"rb", "rails"}},
+	{ID: "rust", Aliases: []string{"rs"}},
+	{ID: "elixir", Aliases: []string{"ex", "phoenix"}},
+	{ID: "flutter"},
+	{ID: "dart"},
+	{ID: "apple", Aliases: []string{"ios", "macos", "cocoa", "swift"}},
+	{ID: "native", Aliases: []string{"c", "cpp", "c++"}},
+}
+
+// PlatformRegistry holds the platforms projects can be created for and the aliases they
+// are also known by. Unknown platforms are accepted when they look like platform
+// identifiers, unless the registry is strict.
+type PlatformRegistry struct {
+	platforms    []dto.PlatformResponse
+	aliases      map[string]string // alias or ID -> ID
+	allowUnknown bool
+}
+
+// NewPlatformRegistry creates a registry of the default platforms and the custom ones, each
+// given as "id" or "id=alias|alias" to add a platform or aliases of a known one
+func NewPlatformRegistry(custom []string, allowUnknown bool) (*PlatformRegistry, error) {
+	pr := &PlatformRegistry{
+		aliases:      make(map[string]string),
+		allowUnknown: allowUnknown,
+	}
+	for _, platform := range defaultProjectPlatforms {
+		pr.register(platform.ID, platform.Aliases...)
+	}
+
+	for _, entry := range custom {
+		id, aliasList, _ := strings.Cut(entry, "=")
+		id = strings.ToLower(strings.TrimSpace(id))
+		if !platformPattern.MatchString(id) {
+			return nil, fmt.Errorf("invalid platform %q", entry)
+		}
+		var aliases []string
+		for _, alias := range strings.Split(aliasList, "|") {
+			if alias = strings.ToLower(strings.TrimSpace(alias)); alias != "" {
+				aliases = append(aliases, alias)
+			}
+		}
+		pr.register(id, aliases...)
+	}
+	return pr, nil
+}
+
+// register adds a platform, or aliases to a known one
+func (pr *PlatformRegistry) register(id string, aliases ...string) {
+	index := -1
+	for i := range pr.platforms {
+		if pr.platforms[i].ID == id {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		pr.platforms = append(pr.platforms, dto.PlatformResponse{ID: id})
+		index = len(pr.platforms) - 1
+		pr.aliases[id] = id
+	}
+
+	for _, alias := range aliases {
+		if _, taken := pr.aliases[alias]; taken {
+			continue
+		}
+		pr.aliases[alias] = id
+		pr.platforms[index].Aliases = append(pr.platforms[index].Aliases, alias)
+	}
+}
+
+// Normalize returns the platform identifier a project platform is stored as: the ID of a
+// known platform or alias, or the platform itself, lowercased, when unknown platforms are
+// accepted. It returns ErrProjectInvalidPlatform for anything else.
+func (pr *PlatformRegistry) Normalize(platform string) (string, error) {
+	platform = strings.ToLower(strings.TrimSpace(platform))
+	if id, ok := pr.aliases[platform]; ok {
+		return id, nil
+	}
+	if pr.allowUnknown && platformPattern.MatchString(platform) {
+		return platform, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrProjectInvalidPlatform, platform)
+}
+
+// List returns the known platforms in registration order
+func (pr *PlatformRegistry) List() []dto.PlatformResponse {
+	platforms := make([]dto.PlatformResponse, len(pr.platforms))
+	copy(platforms, pr.platforms)
+	return platforms
+}
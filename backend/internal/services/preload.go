@@ -0,0 +1,116 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Batch loaders for list responses. Each runs a single IN query for all the rows of a page
+// instead of one query per row, so a list costs the same number of queries whatever its length.
+
+// collectIDs returns the distinct IDs of items, in order of first appearance
+func collectIDs[T any](items []T, idOf func(*T) uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(items))
+	ids := make([]uuid.UUID, 0, len(items))
+	for i := range items {
+		id := idOf(&items[i])
+		if id == uuid.Nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// countByKey counts the rows of model per value of column among keys; keys without rows
+// are missing from the map
+func countByKey(db *gorm.DB, model interface{}, column string, keys []uuid.UUID) (map[uuid.UUID]int64, error) {
+	counts := make(map[uuid.UUID]int64, len(keys))
+	if len(keys) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		GroupKey uuid.UUID
+		Count    int64
+	}
+	if err := db.Model(model).
+		Select(column+" AS group_key, COUNT(*) AS count").
+		Where(column+" IN ?", keys).
+		Group(column).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count by %s: %w", column, err)
+	}
+	for _, row := range rows {
+		counts[row.GroupKey] = row.Count
+	}
+	return counts, nil
+}
+
+// preloadByKey loads the rows whose column is one of keys, grouped by keyOf and kept in
+// the given order (e.g. "created_at ASC"; empty for none)
+func preloadByKey[T any](db *gorm.DB, column string, keys []uuid.UUID, keyOf func(*T) uuid.UUID, order string) (map[uuid.UUID][]T, error) {
+	grouped := make(map[uuid.UUID][]T, len(keys))
+	if len(keys) == 0 {
+		return grouped, nil
+	}
+
+	query := db.Where(column+" IN ?", keys)
+	if order != "" {
+		query = query.Order(order)
+	}
+	var rows []T
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to preload by %s: %w", column, err)
+	}
+	for i := range rows {
+		key := keyOf(&rows[i])
+		grouped[key] = append(grouped[key], rows[i])
+	}
+	return grouped, nil
+}
+
+// loadByID loads the rows with the given IDs, keyed by ID
+func loadByID[T any](db *gorm.DB, ids []uuid.UUID, idOf func(*T) uuid.UUID) (map[uuid.UUID]*T, error) {
+	grouped, err := preloadByKey(db, "id", ids, idOf, "")
+	if err != nil {
+		return nil, err
+	}
+	loaded := make(map[uuid.UUID]*T, len(grouped))
+	for id, rows := range grouped {
+		loaded[id] = &rows[0]
+	}
+	return loaded, nil
+}
+
+// latestByKey loads, for each of keys, the row with the greatest orderColumn among those
+// whose column is the key
+func latestByKey[T any](db *gorm.DB, model interface{}, column, orderColumn string, keys []uuid.UUID, keyOf func(*T) uuid.UUID) (map[uuid.UUID]T, error) {
+	latest := make(map[uuid.UUID]T, len(keys))
+	if len(keys) == 0 {
+		return latest, nil
+	}
+
+	newest := db.Model(model).
+		Select(column+", MAX("+orderColumn+")").
+		Where(column+" IN ?", keys).
+		Group(column)
+
+	var rows []T
+	if err := db.Where("("+column+", "+orderColumn+") IN (?)", newest).
+		Order(orderColumn + " DESC").
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load latest by %s: %w", column, err)
+	}
+	for i := range rows {
+		// Rows tied on orderColumn are all returned; keep one
+		key := keyOf(&rows[i])
+		if _, ok := latest[key]; !ok {
+			latest[key] = rows[i]
+		}
+	}
+	return latest, nil
+}
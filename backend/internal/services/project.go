@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"minisentry/internal/database"
 	"minisentry/internal/dto"
@@ -19,21 +20,43 @@ var (
 	ErrProjectInvalidPlatform   = errors.New("invalid project platform")
 	ErrProjectDSNInvalid        = errors.New("invalid project DSN")
 	ErrProjectInactive          = errors.New("project is inactive")
+	ErrProjectInvalidIPRange    = errors.New("invalid IP range")
+	ErrProjectInvalidPinnedKey  = errors.New("invalid pinned context key")
 )
 
 type ProjectService struct {
 	db      *database.DB
 	dsnHost string
+
+	// accessCache holds recent project access checks, see CheckProjectAccess
+	accessCache *projectAccessCache
+
+	// platforms validates and normalizes project platforms
+	platforms *PlatformRegistry
 }
 
 // NewProjectService creates a new project service
 func NewProjectService(db *database.DB, dsnHost string) *ProjectService {
+	platforms, _ := NewPlatformRegistry(nil, true)
 	return &ProjectService{
-		db:      db,
-		dsnHost: dsnHost,
+		db:          db,
+		dsnHost:     dsnHost,
+		accessCache: newProjectAccessCache(),
+		platforms:   platforms,
 	}
 }
 
+// SetPlatformRegistry replaces the default platforms projects can be created for. It must
+// be called before the server starts.
+func (s *ProjectService) SetPlatformRegistry(platforms *PlatformRegistry) {
+	s.platforms = platforms
+}
+
+// Platforms returns the known project platforms
+func (s *ProjectService) Platforms() []dto.PlatformResponse {
+	return s.platforms.List()
+}
+
 // CreateProject creates a new project within an organization
 func (s *ProjectService) CreateProject(userID, orgID uuid.UUID, name, slug, platform string, description *string) (*models.Project, error) {
 	// Normalize and validate slug
@@ -42,9 +65,10 @@ func (s *ProjectService) CreateProject(userID, orgID uuid.UUID, name, slug, plat
 		return nil, fmt.Errorf("invalid slug: %w", err)
 	}
 
-	// Validate platform
-	if !dto.IsPlatformSupported(platform) {
-		return nil, ErrProjectInvalidPlatform
+	// Validate platform, resolving aliases
+	platform, err = s.platforms.Normalize(platform)
+	if err != nil {
+		return nil, err
 	}
 
 	// Check if user has permission to create projects (owner or admin)
@@ -177,32 +201,58 @@ func (s *ProjectService) UpdateProject(userID, projectID uuid.UUID, name, platfo
 		return nil, ErrInsufficientPermissions
 	}
 
-	// Validate platform if provided
-	if platform != nil && !dto.IsPlatformSupported(*platform) {
-		return nil, ErrProjectInvalidPlatform
+	// Validate platform if provided, resolving aliases
+	if platform != nil {
+		normalized, err := s.platforms.Normalize(*platform)
+		if err != nil {
+			return nil, err
+		}
+		platform = &normalized
 	}
 
 	// Update fields
 	updates := make(map[string]interface{})
+	var diff settingDiff
 	if name != nil {
 		updates["name"] = *name
+		diff.add("name", project.Name, *name)
 	}
 	if platform != nil {
 		updates["platform"] = *platform
+		diff.add("platform", project.Platform, *platform)
 	}
 	if description != nil {
 		updates["description"] = *description
+		diff.add("description", project.Description, *description)
 	}
 
-	if len(updates) > 0 {
-		if err := s.db.DB.Model(project).Updates(updates).Error; err != nil {
-			return nil, fmt.Errorf("failed to update project: %w", err)
-		}
+	if err := s.updateProjectSettings(userID, project, updates, diff); err != nil {
+		return nil, fmt.Errorf("failed to update project: %w", err)
 	}
 
 	return project, nil
 }
 
+// updateProjectSettings applies project updates and records the changed settings in the audit log
+func (s *ProjectService) updateProjectSettings(userID uuid.UUID, project *models.Project, updates map[string]interface{}, diff settingDiff) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	return s.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(project).Updates(updates).Error; err != nil {
+			return err
+		}
+		return recordSettingChanges(tx, userID, models.AuditProjectSettingChanged, settingTargetProject, project.ID, project.OrganizationID, &project.ID, diff)
+	})
+}
+
+// GetSettingHistory returns the changes of a project's settings, newest first. An empty
+// setting returns the changes of every setting.
+func (s *ProjectService) GetSettingHistory(projectID uuid.UUID, setting string, page, limit int) (*dto.SettingHistoryResponse, error) {
+	return listSettingChanges(s.db.DB, settingTargetProject, projectID, setting, page, limit)
+}
+
 // DeleteProject soft deletes a project
 func (s *ProjectService) DeleteProject(userID, projectID uuid.UUID) error {
 	// Get project with organization access check
@@ -347,7 +397,7 @@ func (s *ProjectService) GetProjectByPublicKey(publicKey string) (*models.Projec
 }
 
 // UpdateProjectConfiguration updates project settings
-func (s *ProjectService) UpdateProjectConfiguration(userID, projectID uuid.UUID, isActive *bool, platform *string) (*models.Project, error) {
+func (s *ProjectService) UpdateProjectConfiguration(userID, projectID uuid.UUID, isActive *bool, platform *string, runbook *string, maxEventSize *int, requireSecretKey *bool, ipAllowList, ipDenyList *[]string, sampleThreshold, sampleRate *int, pinnedKeys *[]string) (*models.Project, error) {
 	// Get project with organization access check
 	project, err := s.GetProject(userID, projectID)
 	if err != nil {
@@ -364,25 +414,98 @@ func (s *ProjectService) UpdateProjectConfiguration(userID, projectID uuid.UUID,
 		return nil, ErrInsufficientPermissions
 	}
 
-	// Validate platform if provided
-	if platform != nil && !dto.IsPlatformSupported(*platform) {
-		return nil, ErrProjectInvalidPlatform
+	// Validate platform if provided, resolving aliases
+	if platform != nil {
+		normalized, err := s.platforms.Normalize(*platform)
+		if err != nil {
+			return nil, err
+		}
+		platform = &normalized
 	}
 
 	// Update configuration
 	updates := make(map[string]interface{})
+	var diff settingDiff
 	if isActive != nil {
 		updates["is_active"] = *isActive
+		diff.add("is_active", project.IsActive, *isActive)
 	}
 	if platform != nil {
 		updates["platform"] = *platform
+		diff.add("platform", project.Platform, *platform)
+	}
+	if runbook != nil {
+		// An empty runbook removes it
+		if strings.TrimSpace(*runbook) == "" {
+			updates["runbook"] = nil
+			diff.add("runbook", project.Runbook, nil)
+		} else {
+			updates["runbook"] = *runbook
+			diff.add("runbook", project.Runbook, *runbook)
+		}
 	}
-
-	if len(updates) > 0 {
-		if err := s.db.DB.Model(project).Updates(updates).Error; err != nil {
-			return nil, fmt.Errorf("failed to update project configuration: %w", err)
+	if maxEventSize != nil {
+		// 0 removes the project limit, leaving the server limit
+		if *maxEventSize <= 0 {
+			updates["max_event_size"] = nil
+			diff.add("max_event_size", project.MaxEventSize, nil)
+		} else {
+			updates["max_event_size"] = *maxEventSize
+			diff.add("max_event_size", project.MaxEventSize, *maxEventSize)
 		}
 	}
+	if requireSecretKey != nil {
+		updates["require_secret_key"] = *requireSecretKey
+		diff.add("require_secret_key", project.RequireSecretKey, *requireSecretKey)
+	}
+	if ipAllowList != nil {
+		ranges, err := NormalizeIPRanges(*ipAllowList)
+		if err != nil {
+			return nil, err
+		}
+		updates["ip_allow_list"] = encodeIPRanges(ranges)
+		diff.add("ip_allow_list", models.ProjectIPRanges(project.IPAllowList), ranges)
+	}
+	if ipDenyList != nil {
+		ranges, err := NormalizeIPRanges(*ipDenyList)
+		if err != nil {
+			return nil, err
+		}
+		updates["ip_deny_list"] = encodeIPRanges(ranges)
+		diff.add("ip_deny_list", models.ProjectIPRanges(project.IPDenyList), ranges)
+	}
+	if sampleThreshold != nil {
+		// 0 stores every event
+		if *sampleThreshold <= 0 {
+			updates["event_sample_threshold"] = nil
+			diff.add("event_sample_threshold", project.EventSampleThreshold, nil)
+		} else {
+			updates["event_sample_threshold"] = *sampleThreshold
+			diff.add("event_sample_threshold", project.EventSampleThreshold, *sampleThreshold)
+		}
+	}
+	if sampleRate != nil {
+		// 0 uses the default rate
+		if *sampleRate <= 0 {
+			updates["event_sample_rate"] = nil
+			diff.add("event_sample_rate", project.EventSampleRate, nil)
+		} else {
+			updates["event_sample_rate"] = *sampleRate
+			diff.add("event_sample_rate", project.EventSampleRate, *sampleRate)
+		}
+	}
+	if pinnedKeys != nil {
+		keys, err := NormalizePinnedContextKeys(*pinnedKeys)
+		if err != nil {
+			return nil, err
+		}
+		updates["pinned_context_keys"] = encodePinnedContextKeys(keys)
+		diff.add("pinned_context_keys", ProjectPinnedContextKeys(project.PinnedContextKeys), keys)
+	}
+
+	if err := s.updateProjectSettings(userID, project, updates, diff); err != nil {
+		return nil, fmt.Errorf("failed to update project configuration: %w", err)
+	}
 
 	return project, nil
 }
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// projectAccessCacheTTL bounds how long a membership check is reused, so a member removed
+	// from an organization keeps access to its issues for at most this long
+	projectAccessCacheTTL = 30 * time.Second
+
+	// projectAccessCacheSweepSize is the cache size above which expired entries are dropped
+	projectAccessCacheSweepSize = 10000
+)
+
+type projectAccessKey struct {
+	userID    uuid.UUID
+	projectID uuid.UUID
+}
+
+type projectAccessEntry struct {
+	role    models.OrganizationRole
+	err     error // ErrProjectAccessDenied or ErrProjectNotFound
+	expires time.Time
+}
+
+// projectAccessCache remembers recent ValidateProjectAccess outcomes per user and project
+type projectAccessCache struct {
+	mu      sync.Mutex
+	entries map[projectAccessKey]projectAccessEntry
+}
+
+func newProjectAccessCache() *projectAccessCache {
+	return &projectAccessCache{entries: make(map[projectAccessKey]projectAccessEntry)}
+}
+
+func (c *projectAccessCache) get(key projectAccessKey, now time.Time) (projectAccessEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || now.After(entry.expires) {
+		return projectAccessEntry{}, false
+	}
+	return entry, true
+}
+
+func (c *projectAccessCache) put(key projectAccessKey, entry projectAccessEntry, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= projectAccessCacheSweepSize {
+		for k, e := range c.entries {
+			if now.After(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	c.entries[key] = entry
+}
+
+// CheckProjectAccess is ValidateProjectAccess with its outcome cached briefly per user and
+// project, for routes that check access on every request such as the issue endpoints
+func (s *ProjectService) CheckProjectAccess(userID, projectID uuid.UUID) (models.OrganizationRole, error) {
+	key := projectAccessKey{userID: userID, projectID: projectID}
+	now := time.Now()
+	if entry, ok := s.accessCache.get(key, now); ok {
+		return entry.role, entry.err
+	}
+
+	role, err := s.ValidateProjectAccess(userID, projectID)
+	if err != nil && !errors.Is(err, ErrProjectAccessDenied) && !errors.Is(err, ErrProjectNotFound) {
+		// Database errors are not cached
+		return "", err
+	}
+
+	s.accessCache.put(key, projectAccessEntry{role: role, err: err, expires: now.Add(projectAccessCacheTTL)}, now)
+	return role, err
+}
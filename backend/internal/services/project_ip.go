@@ -0,0 +1,109 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"minisentry/internal/models"
+
+	"gorm.io/datatypes"
+)
+
+// maxProjectIPRanges bounds the ranges of each of a project's IP lists
+const maxProjectIPRanges = 500
+
+// NormalizeIPRanges validates CIDR ranges, accepting single addresses as /32 or /128 ranges,
+// and returns them in canonical form without duplicates
+func NormalizeIPRanges(values []string) ([]string, error) {
+	ranges := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		prefix, err := parseIPRange(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrProjectInvalidIPRange, value)
+		}
+		normalized := prefix.String()
+		if seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		ranges = append(ranges, normalized)
+	}
+	if len(ranges) > maxProjectIPRanges {
+		return nil, fmt.Errorf("%w: at most %d ranges are allowed", ErrProjectInvalidIPRange, maxProjectIPRanges)
+	}
+	return ranges, nil
+}
+
+// IsIPAllowed reports whether the project accepts events sent from clientIP: it must not be
+// in a denied range, and must be in an allowed range when the project has an allow list.
+// An address that cannot be parsed is only accepted without an allow list.
+func IsIPAllowed(project *models.Project, clientIP string) bool {
+	allowed := models.ProjectIPRanges(project.IPAllowList)
+	denied := models.ProjectIPRanges(project.IPDenyList)
+	if len(allowed) == 0 && len(denied) == 0 {
+		return true
+	}
+
+	// RemoteAddr keeps the brackets of IPv6 addresses
+	addr, err := netip.ParseAddr(strings.Trim(strings.TrimSpace(clientIP), "[]"))
+	if err != nil {
+		return len(allowed) == 0
+	}
+	addr = addr.Unmap().WithZone("")
+
+	if containsIP(denied, addr) {
+		return false
+	}
+	return len(allowed) == 0 || containsIP(allowed, addr)
+}
+
+func containsIP(ranges []string, addr netip.Addr) bool {
+	for _, value := range ranges {
+		if prefix, err := parseIPRange(value); err == nil && prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIPRange parses a CIDR range or a single address, masking the range to its network
+func parseIPRange(value string) (netip.Prefix, error) {
+	if !strings.Contains(value, "/") {
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		addr = addr.Unmap()
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+
+	prefix, err := netip.ParsePrefix(value)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	if prefix.Addr().Is4In6() {
+		bits := prefix.Bits() - 96
+		if bits < 0 {
+			return netip.Prefix{}, fmt.Errorf("invalid IPv4-mapped range %q", value)
+		}
+		prefix = netip.PrefixFrom(prefix.Addr().Unmap(), bits)
+	}
+	return prefix.Masked(), nil
+}
+
+// encodeIPRanges stores an IP list, clearing it when empty
+func encodeIPRanges(ranges []string) interface{} {
+	if len(ranges) == 0 {
+		return nil
+	}
+	encoded, _ := json.Marshal(ranges)
+	return datatypes.JSON(encoded)
+}
@@ -0,0 +1,344 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrQuotaOverrideNotFound  = errors.New("quota override not found")
+	ErrQuotaOverrideInvalid   = errors.New("invalid quota override request")
+	ErrQuotaOverrideNotActive = errors.New("quota override has already ended")
+)
+
+const (
+	// maxQuotaOverrideDuration bounds how long a temporary override may last
+	maxQuotaOverrideDuration = 30 * 24 * time.Hour
+
+	// quotaOverrideHistoryLimit bounds the overrides listed with a project's quota
+	quotaOverrideHistoryLimit = 50
+)
+
+// QuotaConfig holds the default ingestion limits of every project; 0 is unlimited
+type QuotaConfig struct {
+	RateLimit       int // requests per RateLimitWindow
+	RateLimitWindow time.Duration
+	DailyQuota      int // requests per UTC day
+}
+
+// QuotaCheck is the outcome of counting an ingestion request against a project's limits
+type QuotaCheck struct {
+	Allowed    bool
+	Kind       string // the limit that was exceeded: models.QuotaRateLimit or models.QuotaDailyQuota
+	RetryAfter time.Duration
+}
+
+// QuotaService enforces per-project ingestion rate limits and daily quotas, which
+// superusers can temporarily override. Counters are kept in memory, so with several
+// API servers each enforces the limits separately.
+type QuotaService struct {
+	db     *database.DB
+	config QuotaConfig
+
+	mu        sync.Mutex
+	overrides map[uuid.UUID][]models.QuotaOverride // unended overrides per project, newest first
+	windows   map[uuid.UUID]*quotaCounter
+	days      map[uuid.UUID]*quotaCounter
+}
+
+type quotaCounter struct {
+	start time.Time
+	count int
+}
+
+// NewQuotaService creates a quota service and loads the unended overrides
+func NewQuotaService(db *database.DB, config QuotaConfig) *QuotaService {
+	if config.RateLimitWindow <= 0 {
+		config.RateLimitWindow = time.Minute
+	}
+
+	qs := &QuotaService{
+		db:        db,
+		config:    config,
+		overrides: make(map[uuid.UUID][]models.QuotaOverride),
+		windows:   make(map[uuid.UUID]*quotaCounter),
+		days:      make(map[uuid.UUID]*quotaCounter),
+	}
+	if err := qs.reloadOverrides(); err != nil {
+		log.Printf("Failed to load quota overrides: %v", err)
+	}
+	return qs
+}
+
+// Check counts an ingestion request against the project's rate limit and daily quota.
+// Rejected requests are not counted.
+func (qs *QuotaService) Check(projectID uuid.UUID) QuotaCheck {
+	now := time.Now()
+
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+
+	rateLimit := qs.limitLocked(projectID, models.QuotaRateLimit, now)
+	dailyQuota := qs.limitLocked(projectID, models.QuotaDailyQuota, now)
+
+	windowStart := now.Truncate(qs.config.RateLimitWindow)
+	window := qs.counterLocked(qs.windows, projectID, windowStart)
+	if rateLimit > 0 && window.count >= rateLimit {
+		return QuotaCheck{Kind: models.QuotaRateLimit, RetryAfter: windowStart.Add(qs.config.RateLimitWindow).Sub(now)}
+	}
+
+	dayStart := now.UTC().Truncate(24 * time.Hour)
+	day := qs.counterLocked(qs.days, projectID, dayStart)
+	if dailyQuota > 0 && day.count >= dailyQuota {
+		return QuotaCheck{Kind: models.QuotaDailyQuota, RetryAfter: dayStart.Add(24 * time.Hour).Sub(now)}
+	}
+
+	window.count++
+	day.count++
+	return QuotaCheck{Allowed: true}
+}
+
+// GetProjectQuota returns the limits applied to a project and its override history
+func (qs *QuotaService) GetProjectQuota(projectID uuid.UUID) (*dto.ProjectQuotaResponse, error) {
+	if err := qs.requireProject(projectID); err != nil {
+		return nil, err
+	}
+
+	var overrides []models.QuotaOverride
+	if err := qs.db.Where("project_id = ?", projectID).
+		Order("created_at DESC").Limit(quotaOverrideHistoryLimit).
+		Find(&overrides).Error; err != nil {
+		return nil, fmt.Errorf("failed to list quota overrides: %w", err)
+	}
+
+	now := time.Now()
+	qs.mu.Lock()
+	response := &dto.ProjectQuotaResponse{
+		ProjectID:         projectID,
+		RateLimit:         qs.limitLocked(projectID, models.QuotaRateLimit, now),
+		RateLimitWindow:   int(qs.config.RateLimitWindow / time.Second),
+		DailyQuota:        qs.limitLocked(projectID, models.QuotaDailyQuota, now),
+		DefaultRateLimit:  qs.config.RateLimit,
+		DefaultDailyQuota: qs.config.DailyQuota,
+		Overrides:         make([]dto.QuotaOverrideResponse, len(overrides)),
+	}
+	qs.mu.Unlock()
+
+	for i := range overrides {
+		response.Overrides[i] = convertQuotaOverrideToResponse(&overrides[i], now)
+	}
+	return response, nil
+}
+
+// CreateOverride sets a temporary limit for a project. The newest active override of a
+// kind applies; when it ends, an older one still active or the default applies again.
+func (qs *QuotaService) CreateOverride(projectID, actorID uuid.UUID, request dto.QuotaOverrideRequest) (*dto.QuotaOverrideResponse, error) {
+	if request.Kind != models.QuotaRateLimit && request.Kind != models.QuotaDailyQuota {
+		return nil, fmt.Errorf("%w: kind must be %s or %s", ErrQuotaOverrideInvalid, models.QuotaRateLimit, models.QuotaDailyQuota)
+	}
+	if request.Value == nil || *request.Value < 0 {
+		return nil, fmt.Errorf("%w: value must be 0 (unlimited) or more", ErrQuotaOverrideInvalid)
+	}
+	duration, err := time.ParseDuration(request.Duration)
+	if err != nil || duration <= 0 || duration > maxQuotaOverrideDuration {
+		return nil, fmt.Errorf("%w: duration must be a positive duration such as 24h, up to %s", ErrQuotaOverrideInvalid, maxQuotaOverrideDuration)
+	}
+	if request.Reason != nil {
+		reason := strings.TrimSpace(*request.Reason)
+		request.Reason = &reason
+		if reason == "" {
+			request.Reason = nil
+		}
+	}
+	if err := qs.requireProject(projectID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	qs.mu.Lock()
+	previous := qs.limitLocked(projectID, request.Kind, now)
+	qs.mu.Unlock()
+
+	override := models.QuotaOverride{
+		ProjectID: projectID,
+		Kind:      request.Kind,
+		Value:     *request.Value,
+		Reason:    request.Reason,
+		ExpiresAt: now.Add(duration),
+		CreatedBy: &actorID,
+	}
+
+	err = qs.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&override).Error; err != nil {
+			return fmt.Errorf("failed to create quota override: %w", err)
+		}
+		return recordAudit(tx, &actorID, models.AuditQuotaOverrideCreated, "quota_override", override.ID, &projectID, map[string]interface{}{
+			"kind":       override.Kind,
+			"value":      override.Value,
+			"previous":   previous,
+			"expires_at": override.ExpiresAt,
+			"reason":     override.Reason,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	qs.refreshOverrides()
+	response := convertQuotaOverrideToResponse(&override, now)
+	return &response, nil
+}
+
+// RevokeOverride ends an override before it expires
+func (qs *QuotaService) RevokeOverride(overrideID, actorID uuid.UUID) (*dto.QuotaOverrideResponse, error) {
+	var override models.QuotaOverride
+	err := qs.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&override, "id = ?", overrideID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrQuotaOverrideNotFound
+			}
+			return fmt.Errorf("failed to get quota override: %w", err)
+		}
+		now := time.Now()
+		if !override.Active(now) {
+			return ErrQuotaOverrideNotActive
+		}
+
+		override.EndedAt = &now
+		if err := tx.Model(&override).Update("ended_at", now).Error; err != nil {
+			return fmt.Errorf("failed to revoke quota override: %w", err)
+		}
+		return recordAudit(tx, &actorID, models.AuditQuotaOverrideRevoked, "quota_override", override.ID, &override.ProjectID, map[string]interface{}{
+			"kind":       override.Kind,
+			"value":      override.Value,
+			"expires_at": override.ExpiresAt,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	qs.refreshOverrides()
+	response := convertQuotaOverrideToResponse(&override, time.Now())
+	return &response, nil
+}
+
+// ExpireOverrides ends the overrides past their expiry, recording each in the audit log,
+// and reloads the overrides so changes made through other servers are picked up.
+// It is run periodically by the scheduler.
+func (qs *QuotaService) ExpireOverrides(ctx context.Context) error {
+	now := time.Now()
+
+	var expired []models.QuotaOverride
+	if err := qs.db.WithContext(ctx).Where("ended_at IS NULL AND expires_at <= ?", now).Find(&expired).Error; err != nil {
+		return fmt.Errorf("failed to find expired quota overrides: %w", err)
+	}
+
+	for _, override := range expired {
+		err := qs.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			// Another server may have expired the override concurrently
+			result := tx.Model(&models.QuotaOverride{}).Where("id = ? AND ended_at IS NULL", override.ID).Update("ended_at", override.ExpiresAt)
+			if result.Error != nil {
+				return fmt.Errorf("failed to expire quota override: %w", result.Error)
+			}
+			if result.RowsAffected == 0 {
+				return nil
+			}
+			return recordAudit(tx, nil, models.AuditQuotaOverrideExpired, "quota_override", override.ID, &override.ProjectID, map[string]interface{}{
+				"kind":       override.Kind,
+				"value":      override.Value,
+				"expires_at": override.ExpiresAt,
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return qs.reloadOverrides()
+}
+
+// limitLocked returns the limit of a kind applied to a project; qs.mu must be held
+func (qs *QuotaService) limitLocked(projectID uuid.UUID, kind string, now time.Time) int {
+	for _, override := range qs.overrides[projectID] {
+		if override.Kind == kind && override.Active(now) {
+			return override.Value
+		}
+	}
+	if kind == models.QuotaRateLimit {
+		return qs.config.RateLimit
+	}
+	return qs.config.DailyQuota
+}
+
+// counterLocked returns the project's counter for the period starting at start; qs.mu must be held
+func (qs *QuotaService) counterLocked(counters map[uuid.UUID]*quotaCounter, projectID uuid.UUID, start time.Time) *quotaCounter {
+	counter, ok := counters[projectID]
+	if !ok || !counter.start.Equal(start) {
+		counter = &quotaCounter{start: start}
+		counters[projectID] = counter
+	}
+	return counter
+}
+
+func (qs *QuotaService) refreshOverrides() {
+	if err := qs.reloadOverrides(); err != nil {
+		log.Printf("Failed to reload quota overrides: %v", err)
+	}
+}
+
+func (qs *QuotaService) reloadOverrides() error {
+	var overrides []models.QuotaOverride
+	if err := qs.db.Where("ended_at IS NULL AND expires_at > ?", time.Now()).
+		Order("created_at DESC").Find(&overrides).Error; err != nil {
+		return fmt.Errorf("failed to load quota overrides: %w", err)
+	}
+
+	byProject := make(map[uuid.UUID][]models.QuotaOverride)
+	for _, override := range overrides {
+		byProject[override.ProjectID] = append(byProject[override.ProjectID], override)
+	}
+
+	qs.mu.Lock()
+	qs.overrides = byProject
+	qs.mu.Unlock()
+	return nil
+}
+
+func (qs *QuotaService) requireProject(projectID uuid.UUID) error {
+	var count int64
+	if err := qs.db.Model(&models.Project{}).Where("id = ?", projectID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+	if count == 0 {
+		return ErrProjectNotFound
+	}
+	return nil
+}
+
+func convertQuotaOverrideToResponse(override *models.QuotaOverride, now time.Time) dto.QuotaOverrideResponse {
+	return dto.QuotaOverrideResponse{
+		ID:        override.ID,
+		ProjectID: override.ProjectID,
+		Kind:      override.Kind,
+		Value:     override.Value,
+		Reason:    override.Reason,
+		ExpiresAt: override.ExpiresAt,
+		EndedAt:   override.EndedAt,
+		Active:    override.Active(now),
+		CreatedBy: override.CreatedBy,
+		CreatedAt: override.CreatedAt,
+	}
+}
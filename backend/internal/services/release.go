@@ -0,0 +1,301 @@
+package services
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+	"minisentry/internal/storage"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrReleaseNotFound        = errors.New("release not found")
+	ErrReleaseInvalidVersion  = errors.New("invalid release version")
+	ErrReleaseArtifactInvalid = errors.New("invalid release artifact")
+)
+
+type ReleaseService struct {
+	db    *database.DB
+	blobs storage.BlobStore
+}
+
+// NewReleaseService creates a service storing release artifacts in the given blob store
+func NewReleaseService(db *database.DB, blobs storage.BlobStore) *ReleaseService {
+	return &ReleaseService{db: db, blobs: blobs}
+}
+
+// CreateRelease creates a release of a project, or updates the ref, URL and release date
+// of an existing version so CI pipelines can call it on every run
+func (rs *ReleaseService) CreateRelease(projectID uuid.UUID, request dto.ReleaseRequest) (*dto.ReleaseResponse, bool, error) {
+	version := strings.TrimSpace(request.Version)
+	if !isValidReleaseVersion(version) {
+		return nil, false, ErrReleaseInvalidVersion
+	}
+
+	release, err := rs.getRelease(projectID, version)
+	created := errors.Is(err, ErrReleaseNotFound)
+	if err != nil && !created {
+		return nil, false, err
+	}
+
+	if created {
+		release = &models.Release{
+			ProjectID:    projectID,
+			Version:      version,
+			Ref:          request.Ref,
+			URL:          request.URL,
+			DateReleased: request.DateReleased,
+		}
+		parseReleaseVersion(release)
+		if err := rs.db.Create(release).Error; err != nil {
+			return nil, false, fmt.Errorf("failed to create release: %w", err)
+		}
+	} else {
+		updates := make(map[string]interface{})
+		if request.Ref != nil {
+			updates["ref"] = *request.Ref
+		}
+		if request.URL != nil {
+			updates["url"] = *request.URL
+		}
+		if request.DateReleased != nil {
+			updates["date_released"] = *request.DateReleased
+		}
+		if len(updates) > 0 {
+			if err := rs.db.Model(release).Updates(updates).Error; err != nil {
+				return nil, false, fmt.Errorf("failed to update release: %w", err)
+			}
+		}
+	}
+
+	response, err := rs.convertReleaseToResponse(release)
+	if err != nil {
+		return nil, false, err
+	}
+	return response, created, nil
+}
+
+// ListReleases returns the releases of a project, newest first, or from the latest version
+// when sorted by "version"
+func (rs *ReleaseService) ListReleases(projectID uuid.UUID, page, limit int, sort string) (*dto.ReleaseListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 25
+	}
+
+	query := rs.db.Model(&models.Release{}).Where("project_id = ?", projectID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count releases: %w", err)
+	}
+
+	order := "created_at DESC"
+	if sort == "version" {
+		order = releaseOrder("releases")
+	}
+
+	var releases []models.Release
+	if err := query.Order(order).
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&releases).Error; err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	// Count the artifacts of every listed release in one query
+	releaseIDs := collectIDs(releases, func(release *models.Release) uuid.UUID { return release.ID })
+	artifactCounts, err := countByKey(rs.db.DB, &models.ReleaseArtifact{}, "release_id", releaseIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count release artifacts: %w", err)
+	}
+
+	responses := make([]dto.ReleaseResponse, len(releases))
+	for i, release := range releases {
+		responses[i] = releaseResponse(&release, artifactCounts[release.ID])
+	}
+
+	return &dto.ReleaseListResponse{
+		Releases:   responses,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}, nil
+}
+
+// UploadArtifact stores a file for a release. A file uploaded again under the same name
+// and dist replaces the previous one.
+func (rs *ReleaseService) UploadArtifact(projectID uuid.UUID, version, name string, dist *string, contentType string, content io.Reader) (*dto.ReleaseArtifactResponse, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 500 {
+		return nil, fmt.Errorf("%w: name must be between 1 and 500 characters", ErrReleaseArtifactInvalid)
+	}
+	if dist != nil {
+		trimmed := strings.TrimSpace(*dist)
+		if len(trimmed) > 64 {
+			return nil, fmt.Errorf("%w: dist must be at most 64 characters", ErrReleaseArtifactInvalid)
+		}
+		dist = &trimmed
+		if trimmed == "" {
+			dist = nil
+		}
+	}
+
+	release, err := rs.getRelease(projectID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	artifact := &models.ReleaseArtifact{
+		ReleaseID:   release.ID,
+		ProjectID:   projectID,
+		Name:        name,
+		Dist:        dist,
+		ContentType: contentType,
+	}
+	artifact.ID = uuid.New()
+	artifact.StorageKey = fmt.Sprintf("releases/%s/%s/%s", projectID, release.ID, artifact.ID)
+
+	hash := sha1.New()
+	size, err := rs.blobs.Put(artifact.StorageKey, io.TeeReader(content, hash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to store release artifact content: %w", err)
+	}
+	artifact.Size = size
+	artifact.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	var replaced []models.ReleaseArtifact
+	err = rs.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("release_id = ? AND name = ?", release.ID, name)
+		if dist != nil {
+			query = query.Where("dist = ?", *dist)
+		} else {
+			query = query.Where("dist IS NULL")
+		}
+		if err := query.Find(&replaced).Error; err != nil {
+			return err
+		}
+		if len(replaced) > 0 {
+			if err := tx.Delete(&replaced).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(artifact).Error
+	})
+	if err != nil {
+		rs.deleteBlob(artifact.StorageKey)
+		return nil, fmt.Errorf("failed to create release artifact: %w", err)
+	}
+	for _, old := range replaced {
+		rs.deleteBlob(old.StorageKey)
+	}
+
+	response := releaseArtifactResponse(artifact)
+	return &response, nil
+}
+
+// ListArtifacts lists the files of a release by name
+func (rs *ReleaseService) ListArtifacts(projectID uuid.UUID, version string) (*dto.ReleaseArtifactListResponse, error) {
+	release, err := rs.getRelease(projectID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	var artifacts []models.ReleaseArtifact
+	if err := rs.db.Where("release_id = ?", release.ID).Order("name ASC").Find(&artifacts).Error; err != nil {
+		return nil, fmt.Errorf("failed to list release artifacts: %w", err)
+	}
+
+	responses := make([]dto.ReleaseArtifactResponse, len(artifacts))
+	for i := range artifacts {
+		responses[i] = releaseArtifactResponse(&artifacts[i])
+	}
+	return &dto.ReleaseArtifactListResponse{Artifacts: responses}, nil
+}
+
+// GetLatestRelease returns the latest release of a project: the highest semantic version,
+// or the most recent release when the project has none
+func (rs *ReleaseService) GetLatestRelease(projectID uuid.UUID) (*dto.ReleaseResponse, error) {
+	var release models.Release
+	if err := rs.db.Where("project_id = ?", projectID).Order(releaseOrder("releases")).First(&release).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReleaseNotFound
+		}
+		return nil, fmt.Errorf("failed to get latest release: %w", err)
+	}
+	return rs.convertReleaseToResponse(&release)
+}
+
+func (rs *ReleaseService) getRelease(projectID uuid.UUID, version string) (*models.Release, error) {
+	var release models.Release
+	if err := rs.db.Where("project_id = ? AND version = ?", projectID, version).First(&release).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReleaseNotFound
+		}
+		return nil, fmt.Errorf("failed to get release: %w", err)
+	}
+	return &release, nil
+}
+
+func (rs *ReleaseService) deleteBlob(key string) {
+	if err := rs.blobs.Delete(key); err != nil && !errors.Is(err, storage.ErrBlobNotFound) {
+		log.Printf("Failed to clean up release artifact blob %s: %v", key, err)
+	}
+}
+
+func (rs *ReleaseService) convertReleaseToResponse(release *models.Release) (*dto.ReleaseResponse, error) {
+	var count int64
+	if err := rs.db.Model(&models.ReleaseArtifact{}).Where("release_id = ?", release.ID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to count release artifacts: %w", err)
+	}
+	response := releaseResponse(release, count)
+	return &response, nil
+}
+
+// isValidReleaseVersion rejects versions Sentry SDKs and CLIs cannot report: empty, too
+// long, path-like or containing whitespace
+func isValidReleaseVersion(version string) bool {
+	if version == "" || len(version) > 100 || version == "." || version == ".." || version == "latest" {
+		return false
+	}
+	return !strings.ContainsAny(version, "/\\\t\n\r ")
+}
+
+func releaseResponse(release *models.Release, artifactCount int64) dto.ReleaseResponse {
+	return dto.ReleaseResponse{
+		ID:            release.ID,
+		ProjectID:     release.ProjectID,
+		Version:       release.Version,
+		Ref:           release.Ref,
+		URL:           release.URL,
+		DateReleased:  release.DateReleased,
+		Package:       release.Package,
+		VersionFormat: release.VersionFormat,
+		ArtifactCount: artifactCount,
+		CreatedAt:     release.CreatedAt,
+	}
+}
+
+func releaseArtifactResponse(artifact *models.ReleaseArtifact) dto.ReleaseArtifactResponse {
+	return dto.ReleaseArtifactResponse{
+		ID:          artifact.ID,
+		Name:        artifact.Name,
+		Dist:        artifact.Dist,
+		ContentType: artifact.ContentType,
+		Size:        artifact.Size,
+		Checksum:    artifact.Checksum,
+		CreatedAt:   artifact.CreatedAt,
+	}
+}
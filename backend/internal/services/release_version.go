@@ -0,0 +1,303 @@
+package services
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrReleaseFilterInvalid = errors.New("invalid release filter")
+
+// Release version formats, telling which parsed components of a version are meaningful
+const (
+	ReleaseFormatSemver = "semver" // 1.2.3, v2.0.0-rc.1+456, my-app@1.4
+	ReleaseFormatBuild  = "build"  // a build number such as 1234
+	ReleaseFormatCommit = "commit" // a commit SHA; only ordered by date
+	ReleaseFormatOther  = "other"
+)
+
+var (
+	semverPattern    = regexp.MustCompile(`^[vV]?(\d+)\.(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z.\-]+))?(?:\+([0-9A-Za-z.\-]+))?$`)
+	buildPattern     = regexp.MustCompile(`^\d+$`)
+	commitPattern    = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+	releaseFilterOps = regexp.MustCompile(`^(>=|<=|>|<|=)?\s*(.+)$`)
+)
+
+// parseReleaseVersion fills the comparable components of a release from its version. A
+// package prefix (package@version) is split off, as SDKs send in their default releases.
+func parseReleaseVersion(release *models.Release) {
+	version := release.Version
+	release.Package = ""
+	if at := strings.LastIndex(version, "@"); at > 0 && at < len(version)-1 {
+		release.Package = version[:at]
+		version = version[at+1:]
+	}
+
+	release.VersionFormat = ReleaseFormatOther
+	release.SemverMajor, release.SemverMinor, release.SemverPatch, release.SemverRevision = 0, 0, 0, 0
+	release.SemverPrerelease = ""
+	release.SemverPrereleaseKey = ""
+	release.SemverFinal = false
+	release.BuildCode = ""
+	release.BuildNumber = nil
+
+	switch {
+	case semverPattern.MatchString(version):
+		match := semverPattern.FindStringSubmatch(version)
+		release.VersionFormat = ReleaseFormatSemver
+		release.SemverMajor = parseVersionNumber(match[1])
+		release.SemverMinor = parseVersionNumber(match[2])
+		release.SemverPatch = parseVersionNumber(match[3])
+		release.SemverRevision = parseVersionNumber(match[4])
+		release.SemverPrerelease = match[5]
+		release.SemverPrereleaseKey = prereleaseKey(match[5])
+		release.SemverFinal = match[5] == ""
+		release.BuildCode = match[6]
+		if number, err := strconv.ParseInt(match[6], 10, 64); err == nil {
+			release.BuildNumber = &number
+		}
+	case buildPattern.MatchString(version):
+		release.VersionFormat = ReleaseFormatBuild
+		release.BuildCode = version
+		if number, err := strconv.ParseInt(version, 10, 64); err == nil {
+			release.BuildNumber = &number
+		}
+	case commitPattern.MatchString(version):
+		release.VersionFormat = ReleaseFormatCommit
+	}
+}
+
+func parseVersionNumber(value string) int64 {
+	number, _ := strconv.ParseInt(value, 10, 64)
+	return number
+}
+
+// compareReleaseVersions orders two versions like releaseOrder, returning -1, 0 or 1. Only
+// versions of the same format and package compare; ok is false for others unless they are equal.
+func compareReleaseVersions(a, b string) (result int, ok bool) {
+	if a == b {
+		return 0, true
+	}
+	ra, rb := models.Release{Version: a}, models.Release{Version: b}
+	parseReleaseVersion(&ra)
+	parseReleaseVersion(&rb)
+	if ra.VersionFormat != rb.VersionFormat || ra.Package != rb.Package {
+		return 0, false
+	}
+
+	switch ra.VersionFormat {
+	case ReleaseFormatSemver:
+		for _, pair := range [][2]int64{
+			{ra.SemverMajor, rb.SemverMajor}, {ra.SemverMinor, rb.SemverMinor},
+			{ra.SemverPatch, rb.SemverPatch}, {ra.SemverRevision, rb.SemverRevision},
+		} {
+			if pair[0] != pair[1] {
+				return cmp.Compare(pair[0], pair[1]), true
+			}
+		}
+		// A final version sorts after its prereleases
+		if ra.SemverFinal != rb.SemverFinal {
+			if ra.SemverFinal {
+				return 1, true
+			}
+			return -1, true
+		}
+		return comparePrereleases(ra.SemverPrerelease, rb.SemverPrerelease), true
+	case ReleaseFormatBuild:
+		if ra.BuildNumber != nil && rb.BuildNumber != nil {
+			return cmp.Compare(*ra.BuildNumber, *rb.BuildNumber), true
+		}
+	}
+	return 0, false
+}
+
+// comparePrereleases orders prerelease versions like semver: identifier by identifier,
+// numeric identifiers as numbers and before alphanumeric ones, so rc.2 comes before rc.10,
+// and a prerelease before the longer ones it starts
+func comparePrereleases(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		na, errA := strconv.ParseUint(as[i], 10, 64)
+		nb, errB := strconv.ParseUint(bs[i], 10, 64)
+		switch {
+		case errA == nil && errB == nil:
+			if na != nb {
+				return cmp.Compare(na, nb)
+			}
+		case errA == nil:
+			return -1
+		case errB == nil:
+			return 1
+		default:
+			if result := strings.Compare(as[i], bs[i]); result != 0 {
+				return result
+			}
+		}
+	}
+	return cmp.Compare(len(as), len(bs))
+}
+
+// prereleaseKey encodes a prerelease so that comparing keys as bytes orders prereleases like
+// comparePrereleases: numeric identifiers are zero-padded behind a 0 so they sort as numbers
+// and before alphanumeric ones, which get a 1, and identifiers are separated by a space,
+// which sorts before every character identifiers may hold
+func prereleaseKey(prerelease string) string {
+	if prerelease == "" {
+		return ""
+	}
+	identifiers := strings.Split(prerelease, ".")
+	for i, identifier := range identifiers {
+		if number, err := strconv.ParseUint(identifier, 10, 64); err == nil {
+			identifiers[i] = fmt.Sprintf("0%020d", number)
+		} else {
+			identifiers[i] = "1" + identifier
+		}
+	}
+	return strings.Join(identifiers, " ")
+}
+
+// releaseOrder orders releases of table (a name or alias) from the latest: semantic versions
+// first by version, then the other formats by build number and creation date
+func releaseOrder(table string) string {
+	return fmt.Sprintf("CASE WHEN %[1]s.version_format = 'semver' THEN 1 ELSE 0 END DESC, "+
+		"%[1]s.semver_major DESC, %[1]s.semver_minor DESC, %[1]s.semver_patch DESC, %[1]s.semver_revision DESC, "+
+		"%[1]s.semver_final DESC, %[1]s.semver_prerelease_key DESC, COALESCE(%[1]s.build_number, 0) DESC, %[1]s.created_at DESC", table)
+}
+
+// releaseFilterCondition turns a release filter into a condition on the releases table:
+// "latest", an exact version, versions starting with a prefix such as "1.2.*", or a
+// comparison such as ">=2.3.0" or "<1200" for build numbers. Only releases of the same
+// format as the compared version match comparisons.
+func releaseFilterCondition(filter string) (string, []interface{}, error) {
+	filter = strings.TrimSpace(filter)
+	if filter == "latest" {
+		return "releases.id = (SELECT latest.id FROM releases latest WHERE latest.project_id = releases.project_id ORDER BY " +
+			releaseOrder("latest") + " LIMIT 1)", nil, nil
+	}
+
+	if prefix, ok := strings.CutSuffix(filter, "*"); ok && !strings.ContainsAny(prefix, "<>=*") {
+		return "releases.version LIKE ?", []interface{}{prefix + "%"}, nil
+	}
+
+	match := releaseFilterOps.FindStringSubmatch(filter)
+	if match == nil {
+		return "", nil, ErrReleaseFilterInvalid
+	}
+	op, operand := match[1], strings.TrimSpace(match[2])
+	if op == "" {
+		return "releases.version = ?", []interface{}{operand}, nil
+	}
+
+	compared := models.Release{Version: operand}
+	parseReleaseVersion(&compared)
+	switch compared.VersionFormat {
+	case ReleaseFormatSemver:
+		condition := "releases.version_format = 'semver' AND " +
+			"(releases.semver_major, releases.semver_minor, releases.semver_patch, releases.semver_revision, releases.semver_final, releases.semver_prerelease_key) " +
+			op + " (?, ?, ?, ?, ?, ?)"
+		args := []interface{}{compared.SemverMajor, compared.SemverMinor, compared.SemverPatch, compared.SemverRevision, compared.SemverFinal, compared.SemverPrereleaseKey}
+		if compared.Package != "" {
+			condition += " AND releases.package = ?"
+			args = append(args, compared.Package)
+		}
+		return condition, args, nil
+	case ReleaseFormatBuild:
+		return "releases.version_format = 'build' AND releases.build_number " + op + " ?", []interface{}{*compared.BuildNumber}, nil
+	}
+	if op == "=" {
+		return "releases.version = ?", []interface{}{operand}, nil
+	}
+	return "", nil, fmt.Errorf("%w: %s cannot be compared", ErrReleaseFilterInvalid, operand)
+}
+
+// ParseVersions fills the version components of releases created before they were parsed,
+// or before their prerelease was given a sort key
+func (rs *ReleaseService) ParseVersions() error {
+	var releases []models.Release
+	return rs.db.Where("version_format = ? OR version_format IS NULL OR (semver_prerelease <> ? AND semver_prerelease_key = ?)", "", "", "").
+		FindInBatches(&releases, 500, func(tx *gorm.DB, batch int) error {
+			for i := range releases {
+				parseReleaseVersion(&releases[i])
+				if err := rs.db.Model(&releases[i]).Select(releaseVersionColumns).Updates(&releases[i]).Error; err != nil {
+					return fmt.Errorf("failed to parse release versions: %w", err)
+				}
+			}
+			return nil
+		}).Error
+}
+
+// releaseVersionColumns are the columns parseReleaseVersion fills
+var releaseVersionColumns = []string{
+	"package", "version_format", "semver_major", "semver_minor", "semver_patch", "semver_revision",
+	"semver_prerelease", "semver_prerelease_key", "semver_final", "build_code", "build_number",
+}
+
+// releaseRecorderCacheTTL bounds how long a release is remembered as created, so releases
+// deleted meanwhile are created again by later data reporting them
+const releaseRecorderCacheTTL = 10 * time.Minute
+
+// releaseRecorder creates the releases ingested events, transactions and sessions report,
+// so they can be filtered and compared like releases created through the API
+type releaseRecorder struct {
+	db *database.DB
+
+	// known remembers the releases already created until they expire, keyed by
+	// project@version
+	mu    sync.Mutex
+	known map[string]time.Time
+}
+
+func newReleaseRecorder(db *database.DB) *releaseRecorder {
+	return &releaseRecorder{db: db, known: make(map[string]time.Time)}
+}
+
+// record creates a release of a project unless it exists. Failures are only logged, since
+// they must not reject the data reporting the release.
+func (rr *releaseRecorder) record(projectID uuid.UUID, version *string) {
+	if version == nil || !isValidReleaseVersion(*version) {
+		return
+	}
+	key := projectID.String() + "@" + *version
+	now := time.Now()
+	rr.mu.Lock()
+	expires, known := rr.known[key]
+	rr.mu.Unlock()
+	if known && now.Before(expires) {
+		return
+	}
+
+	release := models.Release{ProjectID: projectID, Version: *version}
+	parseReleaseVersion(&release)
+	err := rr.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}, {Name: "version"}},
+		DoNothing: true,
+	}).Create(&release).Error
+	if err != nil {
+		log.Printf("Failed to record release %s of project %s: %v", *version, projectID, err)
+		return
+	}
+
+	rr.mu.Lock()
+	if len(rr.known) >= projectAccessCacheSweepSize {
+		for key, expires := range rr.known {
+			if now.After(expires) {
+				delete(rr.known, key)
+			}
+		}
+	}
+	rr.known[key] = now.Add(releaseRecorderCacheTTL)
+	rr.mu.Unlock()
+}
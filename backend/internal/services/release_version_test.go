@@ -0,0 +1,71 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComparePrereleases(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"rc.2", "rc.10", -1},
+		{"rc.10", "rc.2", 1},
+		{"rc.1", "rc.1", 0},
+		{"alpha", "beta", -1},
+		{"alpha", "alpha.1", -1},
+		{"alpha.1", "alpha.beta", -1},
+		{"alpha.beta", "beta", -1},
+		{"beta.2", "beta.11", -1},
+		{"1", "alpha", -1},
+		{"a", "a-b", -1},
+		{"a.x", "a-b.x", -1},
+		{"01", "1", 0},
+		{"99999999999999999999999", "1", 1}, // beyond uint64, compared as alphanumeric
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			if got := comparePrereleases(tt.a, tt.b); got != tt.want {
+				t.Errorf("comparePrereleases(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			// The SQL ordering must agree
+			if got := strings.Compare(prereleaseKey(tt.a), prereleaseKey(tt.b)); got != tt.want {
+				t.Errorf("prereleaseKey(%q) vs prereleaseKey(%q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareReleaseVersions(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		want   int
+		wantOK bool
+	}{
+		{"1.0.0", "1.0.0", 0, true},
+		{"1.0.0", "1.0.1", -1, true},
+		{"1.10.0", "1.9.0", 1, true},
+		{"2.0", "2.0.0", 0, true},
+		{"1.2.3.4", "1.2.3", 1, true},
+		{"v1.2.3", "1.2.3", 0, true},
+		{"2.0.0-rc.2", "2.0.0-rc.10", -1, true},
+		{"2.0.0-rc.10", "2.0.0", -1, true},
+		{"2.0.0", "2.0.0-rc.1", 1, true},
+		{"2.0.0-alpha", "2.0.0-beta", -1, true},
+		{"1.0.0+100", "1.0.0+200", 0, true},
+		{"app@1.2.0", "app@1.10.0", -1, true},
+		{"app@1.2.0", "other@1.10.0", 0, false},
+		{"1200", "999", 1, true},
+		{"1.0.0", "1200", 0, false},
+		{"abc1234", "def5678", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			got, ok := compareReleaseVersions(tt.a, tt.b)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("compareReleaseVersions(%q, %q) = %d, %v, want %d, %v", tt.a, tt.b, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidReplayData = errors.New("invalid replay data")
+
+type ReplayService struct {
+	db *database.DB
+}
+
+// NewReplayService creates a new session replay metadata service
+func NewReplayService(db *database.DB) *ReplayService {
+	return &ReplayService{db: db}
+}
+
+// ProcessReplayEvent records the metadata of an uploaded replay segment and links
+// the events it lists in error_ids to the replay
+func (rs *ReplayService) ProcessReplayEvent(projectID uuid.UUID, data *dto.ReplayEventRequest) error {
+	if data == nil {
+		return fmt.Errorf("%w: replay event is nil", ErrInvalidReplayData)
+	}
+	replayID := normalizeReplayID(data.ReplayID)
+	if replayID == "" {
+		return fmt.Errorf("%w: replay_id is required", ErrInvalidReplayData)
+	}
+	if data.SegmentID < 0 {
+		return fmt.Errorf("%w: segment_id must not be negative", ErrInvalidReplayData)
+	}
+
+	segmentTime := time.Now()
+	if data.Timestamp != nil {
+		segmentTime = *data.Timestamp
+	}
+
+	return rs.db.Transaction(func(tx *gorm.DB) error {
+		replay, err := rs.findOrCreateReplay(tx, projectID, replayID)
+		if err != nil {
+			return err
+		}
+
+		if data.ReplayType != nil {
+			replay.ReplayType = data.ReplayType
+		}
+		if data.ReplayStartTimestamp != nil && (replay.StartedAt == nil || data.ReplayStartTimestamp.Before(*replay.StartedAt)) {
+			replay.StartedAt = data.ReplayStartTimestamp
+		}
+		if replay.FinishedAt == nil || segmentTime.After(*replay.FinishedAt) {
+			replay.FinishedAt = &segmentTime
+		}
+		if data.Environment != nil && *data.Environment != "" {
+			replay.Environment = *data.Environment
+		}
+		if data.Release != nil {
+			replay.ReleaseVersion = data.Release
+		}
+		if data.Platform != nil {
+			replay.Platform = data.Platform
+		}
+		if replay.URLs, err = mergeJSONStringList(replay.URLs, data.URLs); err != nil {
+			return fmt.Errorf("failed to marshal replay urls: %w", err)
+		}
+		if replay.TraceIDs, err = mergeJSONStringList(replay.TraceIDs, data.TraceIDs); err != nil {
+			return fmt.Errorf("failed to marshal replay trace ids: %w", err)
+		}
+
+		if err := rs.recordSegment(tx, replay, data.SegmentID, segmentTime, nil); err != nil {
+			return err
+		}
+
+		if len(data.ErrorIDs) > 0 {
+			if err := tx.Model(&models.Event{}).
+				Where("project_id = ? AND event_id IN ? AND replay_id IS NULL", projectID, data.ErrorIDs).
+				Update("replay_id", replayID).Error; err != nil {
+				return fmt.Errorf("failed to link events to replay: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// ProcessReplayRecording records the size of an uploaded recording segment. The
+// recording itself is dropped, since replay playback is not supported.
+func (rs *ReplayService) ProcessReplayRecording(projectID uuid.UUID, replayID string, payload []byte) error {
+	replayID = normalizeReplayID(replayID)
+	if replayID == "" {
+		return fmt.Errorf("%w: envelope event_id is required for replay recordings", ErrInvalidReplayData)
+	}
+
+	headerLine, recording, found := bytes.Cut(payload, []byte("\n"))
+	if !found {
+		return fmt.Errorf("%w: replay recording is missing its header", ErrInvalidReplayData)
+	}
+	var header dto.ReplayRecordingHeader
+	if err := json.Unmarshal(headerLine, &header); err != nil {
+		return fmt.Errorf("%w: invalid replay recording header: %v", ErrInvalidReplayData, err)
+	}
+
+	size := len(recording)
+	return rs.db.Transaction(func(tx *gorm.DB) error {
+		replay, err := rs.findOrCreateReplay(tx, projectID, replayID)
+		if err != nil {
+			return err
+		}
+		return rs.recordSegment(tx, replay, header.SegmentID, time.Now(), &size)
+	})
+}
+
+func (rs *ReplayService) findOrCreateReplay(tx *gorm.DB, projectID uuid.UUID, replayID string) (*models.Replay, error) {
+	var replay models.Replay
+	err := tx.Where("project_id = ? AND replay_id = ?", projectID, replayID).First(&replay).Error
+	if err == nil {
+		return &replay, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to query replay: %w", err)
+	}
+
+	replay = models.Replay{
+		ProjectID:   projectID,
+		ReplayID:    replayID,
+		Environment: "production",
+	}
+	if err := tx.Create(&replay).Error; err != nil {
+		return nil, fmt.Errorf("failed to create replay: %w", err)
+	}
+	return &replay, nil
+}
+
+// recordSegment upserts a segment row and refreshes the replay's segment count.
+// The replay event and recording of a segment arrive as separate items, so either may come first.
+func (rs *ReplayService) recordSegment(tx *gorm.DB, replay *models.Replay, segmentID int, timestamp time.Time, recordingSize *int) error {
+	var segment models.ReplaySegment
+	err := tx.Where("replay_id = ? AND segment_id = ?", replay.ID, segmentID).First(&segment).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		segment = models.ReplaySegment{
+			ReplayID:  replay.ID,
+			SegmentID: segmentID,
+			Timestamp: timestamp,
+		}
+		if recordingSize != nil {
+			segment.RecordingSize = *recordingSize
+		}
+		if err := tx.Create(&segment).Error; err != nil {
+			return fmt.Errorf("failed to create replay segment: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("failed to query replay segment: %w", err)
+	case recordingSize != nil:
+		if err := tx.Model(&segment).Update("recording_size", *recordingSize).Error; err != nil {
+			return fmt.Errorf("failed to update replay segment: %w", err)
+		}
+	}
+
+	var count int64
+	if err := tx.Model(&models.ReplaySegment{}).Where("replay_id = ?", replay.ID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to count replay segments: %w", err)
+	}
+	replay.SegmentCount = int(count)
+
+	if err := tx.Save(replay).Error; err != nil {
+		return fmt.Errorf("failed to update replay: %w", err)
+	}
+	return nil
+}
+
+// loadReplayReferences returns the replays of a project keyed by replay ID
+func loadReplayReferences(db *gorm.DB, projectID uuid.UUID, replayIDs []string) (map[string]*dto.EventReplayResponse, error) {
+	references := make(map[string]*dto.EventReplayResponse)
+	if len(replayIDs) == 0 {
+		return references, nil
+	}
+
+	var replays []models.Replay
+	if err := db.Where("project_id = ? AND replay_id IN ?", projectID, replayIDs).Find(&replays).Error; err != nil {
+		return nil, fmt.Errorf("failed to load replays: %w", err)
+	}
+
+	for _, replay := range replays {
+		reference := &dto.EventReplayResponse{
+			ReplayID:     replay.ReplayID,
+			ReplayType:   replay.ReplayType,
+			StartedAt:    replay.StartedAt,
+			FinishedAt:   replay.FinishedAt,
+			SegmentCount: replay.SegmentCount,
+		}
+		if len(replay.URLs) > 0 {
+			json.Unmarshal(replay.URLs, &reference.URLs)
+		}
+		references[replay.ReplayID] = reference
+	}
+	return references, nil
+}
+
+// extractReplayID reads the replay an error event belongs to from the replay context,
+// falling back to the replayId tag older JavaScript SDKs set
+func extractReplayID(eventData *dto.ErrorEventRequest) *string {
+	var replayID string
+	if replayContext, ok := eventData.Contexts["replay"].(map[string]interface{}); ok {
+		replayID, _ = replayContext["replay_id"].(string)
+	}
+	if replayID == "" {
+		replayID = eventData.Tags["replayId"]
+	}
+
+	replayID = normalizeReplayID(replayID)
+	if replayID == "" {
+		return nil
+	}
+	return &replayID
+}
+
+// normalizeReplayID lowercases a replay ID and strips UUID dashes, matching the SDK's hex form
+func normalizeReplayID(replayID string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(replayID)), "-", "")
+}
+
+// mergeJSONStringList appends the values missing from a JSON string array column
+func mergeJSONStringList(existing []byte, values []string) ([]byte, error) {
+	var merged []string
+	if len(existing) > 0 {
+		if err := json.Unmarshal(existing, &merged); err != nil {
+			merged = nil
+		}
+	}
+	if len(values) == 0 {
+		return existing, nil
+	}
+
+	seen := make(map[string]bool, len(merged))
+	for _, value := range merged {
+		seen[value] = true
+	}
+	for _, value := range values {
+		if value != "" && !seen[value] {
+			seen[value] = true
+			merged = append(merged, value)
+		}
+	}
+	return json.Marshal(merged)
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSavedSearchNotFound     = errors.New("saved search not found")
+	ErrSavedSearchAccessDenied = errors.New("access denied to saved search")
+	ErrSavedSearchInvalid      = errors.New("invalid saved search")
+)
+
+// Saved search visibilities
+const (
+	SavedSearchVisibilityOwner        = "owner"        // seen by its owner only
+	SavedSearchVisibilityOrganization = "organization" // seen by every member of the organization
+)
+
+const (
+	maxSavedSearchNameLength = 100
+	// maxSavedSearchesPerOwner bounds the personal searches of a user in a project
+	maxSavedSearchesPerOwner = 50
+)
+
+// SavedSearchService manages the saved issue searches of users and organizations
+type SavedSearchService struct {
+	db *database.DB
+}
+
+// NewSavedSearchService creates a new saved search service
+func NewSavedSearchService(db *database.DB) *SavedSearchService {
+	return &SavedSearchService{db: db}
+}
+
+// ListProjectSavedSearches returns the user's searches of the project, then the searches the
+// organization shares for the project or all its projects, each by name
+func (ss *SavedSearchService) ListProjectSavedSearches(userID, organizationID, projectID uuid.UUID) ([]dto.SavedSearchResponse, error) {
+	var searches []models.SavedSearch
+	if err := ss.db.Where("project_id = ? AND owner_id = ?", projectID, userID).
+		Or("organization_id = ? AND owner_id IS NULL AND (project_id = ? OR project_id IS NULL)", organizationID, projectID).
+		Order("owner_id IS NULL, name ASC").
+		Find(&searches).Error; err != nil {
+		return nil, fmt.Errorf("failed to get saved searches: %w", err)
+	}
+	return convertSavedSearchesToResponses(searches), nil
+}
+
+// ListOrganizationSavedSearches returns the searches the organization shares for all its
+// projects, by name
+func (ss *SavedSearchService) ListOrganizationSavedSearches(organizationID uuid.UUID) ([]dto.SavedSearchResponse, error) {
+	var searches []models.SavedSearch
+	if err := ss.db.Where("organization_id = ? AND owner_id IS NULL AND project_id IS NULL", organizationID).
+		Order("name ASC").
+		Find(&searches).Error; err != nil {
+		return nil, fmt.Errorf("failed to get saved searches: %w", err)
+	}
+	return convertSavedSearchesToResponses(searches), nil
+}
+
+// CreateSavedSearch saves a search of the organization, for one of its projects or, for
+// organization searches only, all of them when projectID is nil. role is the user's role in
+// the organization; organization searches require admins or owners.
+func (ss *SavedSearchService) CreateSavedSearch(userID uuid.UUID, role models.OrganizationRole, organizationID uuid.UUID, projectID *uuid.UUID, request dto.SavedSearchRequest) (*dto.SavedSearchResponse, error) {
+	name, filters, visibility, err := normalizeSavedSearchRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	if projectID == nil && visibility == SavedSearchVisibilityOwner {
+		visibility = SavedSearchVisibilityOrganization
+	}
+	if visibility == SavedSearchVisibilityOrganization && role != models.RoleOwner && role != models.RoleAdmin {
+		return nil, ErrSavedSearchAccessDenied
+	}
+
+	search := models.SavedSearch{
+		OrganizationID: organizationID,
+		ProjectID:      projectID,
+		Name:           name,
+		Filters:        filters,
+		CreatedByID:    &userID,
+	}
+	if visibility == SavedSearchVisibilityOwner {
+		search.OwnerID = &userID
+
+		var count int64
+		if err := ss.db.Model(&models.SavedSearch{}).Where("project_id = ? AND owner_id = ?", projectID, userID).
+			Count(&count).Error; err != nil {
+			return nil, fmt.Errorf("failed to count saved searches: %w", err)
+		}
+		if count >= maxSavedSearchesPerOwner {
+			return nil, fmt.Errorf("%w: at most %d saved searches per project", ErrSavedSearchInvalid, maxSavedSearchesPerOwner)
+		}
+	}
+
+	if err := ss.db.Create(&search).Error; err != nil {
+		return nil, fmt.Errorf("failed to create saved search: %w", err)
+	}
+	response := convertSavedSearchToResponse(&search)
+	return &response, nil
+}
+
+// UpdateSavedSearch replaces the name, filters and visibility of a saved search the user
+// manages. Making a search of every project personal is not possible.
+func (ss *SavedSearchService) UpdateSavedSearch(userID, searchID uuid.UUID, request dto.SavedSearchRequest) (*dto.SavedSearchResponse, error) {
+	search, role, err := ss.getSavedSearchForUser(userID, searchID)
+	if err != nil {
+		return nil, err
+	}
+	name, filters, visibility, err := normalizeSavedSearchRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	if request.Visibility == "" {
+		visibility = savedSearchVisibility(search)
+	}
+	if visibility == SavedSearchVisibilityOwner && search.ProjectID == nil {
+		return nil, fmt.Errorf("%w: searches of every project cannot be personal", ErrSavedSearchInvalid)
+	}
+	if visibility == SavedSearchVisibilityOrganization && role != models.RoleOwner && role != models.RoleAdmin {
+		return nil, ErrSavedSearchAccessDenied
+	}
+
+	search.Name = name
+	search.Filters = filters
+	search.OwnerID = nil
+	if visibility == SavedSearchVisibilityOwner {
+		search.OwnerID = &userID
+	}
+	if err := ss.db.Model(search).Select("name", "filters", "owner_id").Updates(search).Error; err != nil {
+		return nil, fmt.Errorf("failed to update saved search: %w", err)
+	}
+	response := convertSavedSearchToResponse(search)
+	return &response, nil
+}
+
+// DeleteSavedSearch deletes a saved search the user manages
+func (ss *SavedSearchService) DeleteSavedSearch(userID, searchID uuid.UUID) error {
+	search, _, err := ss.getSavedSearchForUser(userID, searchID)
+	if err != nil {
+		return err
+	}
+	if err := ss.db.Delete(search).Error; err != nil {
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+	return nil
+}
+
+// getSavedSearchForUser returns a saved search the user manages, with the user's role in its
+// organization: their own searches, or organization searches for admins and owners. Searches
+// the user cannot see are not found.
+func (ss *SavedSearchService) getSavedSearchForUser(userID, searchID uuid.UUID) (*models.SavedSearch, models.OrganizationRole, error) {
+	var search models.SavedSearch
+	if err := ss.db.First(&search, searchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrSavedSearchNotFound
+		}
+		return nil, "", fmt.Errorf("failed to get saved search: %w", err)
+	}
+
+	var member models.OrganizationMember
+	if err := ss.db.Where("organization_id = ? AND user_id = ?", search.OrganizationID, userID).First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrSavedSearchNotFound
+		}
+		return nil, "", fmt.Errorf("failed to check organization membership: %w", err)
+	}
+
+	switch {
+	case search.OwnerID != nil && *search.OwnerID != userID:
+		return nil, "", ErrSavedSearchNotFound
+	case search.OwnerID == nil && member.Role != models.RoleOwner && member.Role != models.RoleAdmin:
+		return nil, "", ErrSavedSearchAccessDenied
+	}
+	return &search, member.Role, nil
+}
+
+// normalizeSavedSearchRequest checks a saved search and encodes its filters, leaving out the
+// page browsed
+func normalizeSavedSearchRequest(request dto.SavedSearchRequest) (string, []byte, string, error) {
+	name := strings.TrimSpace(request.Name)
+	switch {
+	case name == "":
+		return "", nil, "", fmt.Errorf("%w: name is required", ErrSavedSearchInvalid)
+	case len(name) > maxSavedSearchNameLength:
+		return "", nil, "", fmt.Errorf("%w: name must be at most %d characters", ErrSavedSearchInvalid, maxSavedSearchNameLength)
+	}
+
+	visibility := strings.TrimSpace(request.Visibility)
+	switch visibility {
+	case "":
+		visibility = SavedSearchVisibilityOwner
+	case SavedSearchVisibilityOwner, SavedSearchVisibilityOrganization:
+	default:
+		return "", nil, "", fmt.Errorf("%w: unknown visibility %q, expected owner or organization", ErrSavedSearchInvalid, visibility)
+	}
+
+	filters := request.Filters
+	for _, status := range filters.Status {
+		if !slices.Contains([]string{"unresolved", "resolved", "ignored"}, status) {
+			return "", nil, "", fmt.Errorf("%w: invalid status %q", ErrSavedSearchInvalid, status)
+		}
+	}
+	if filters.Sort != "" && !slices.Contains([]string{"frequency", "first_seen", "last_seen", "transaction", "priority", "relevance"}, filters.Sort) {
+		return "", nil, "", fmt.Errorf("%w: invalid sort %q", ErrSavedSearchInvalid, filters.Sort)
+	}
+	if filters.Order != "" && filters.Order != "asc" && filters.Order != "desc" {
+		return "", nil, "", fmt.Errorf("%w: invalid order %q", ErrSavedSearchInvalid, filters.Order)
+	}
+	filters.Page = 0
+
+	encoded, err := json.Marshal(filters)
+	if err != nil {
+		return "", nil, "", fmt.Errorf("failed to encode filters: %w", err)
+	}
+	return name, encoded, visibility, nil
+}
+
+func savedSearchVisibility(search *models.SavedSearch) string {
+	if search.OwnerID == nil {
+		return SavedSearchVisibilityOrganization
+	}
+	return SavedSearchVisibilityOwner
+}
+
+func convertSavedSearchesToResponses(searches []models.SavedSearch) []dto.SavedSearchResponse {
+	responses := make([]dto.SavedSearchResponse, len(searches))
+	for i := range searches {
+		responses[i] = convertSavedSearchToResponse(&searches[i])
+	}
+	return responses
+}
+
+func convertSavedSearchToResponse(search *models.SavedSearch) dto.SavedSearchResponse {
+	response := dto.SavedSearchResponse{
+		ID:             search.ID,
+		OrganizationID: search.OrganizationID,
+		ProjectID:      search.ProjectID,
+		OwnerID:        search.OwnerID,
+		Visibility:     savedSearchVisibility(search),
+		Name:           search.Name,
+		CreatedByID:    search.CreatedByID,
+		CreatedAt:      search.CreatedAt,
+		UpdatedAt:      search.UpdatedAt,
+	}
+	if len(search.Filters) > 0 {
+		json.Unmarshal(search.Filters, &response.Filters)
+	}
+	return response
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"minisentry/internal/dto"
+
+	"github.com/google/uuid"
+)
+
+// scrubbingActionFilter is reported for the fields the default scrubbing filters
+const scrubbingActionFilter = "filter"
+
+// TestScrubbing scrubs a sample event the way ingestion would, without storing it, and
+// reports each field the default scrubbing and the project's rules change. Rules set in
+// the request are tested instead of the saved ones.
+func (es *ErrorService) TestScrubbing(projectID uuid.UUID, request dto.ScrubbingTestRequest) (*dto.ScrubbingTestResponse, error) {
+	var rules []dto.ScrubbingRule
+	switch {
+	case request.Rules != nil:
+		normalizedRules, err := normalizeScrubbingRules(*request.Rules)
+		if err != nil {
+			return nil, err
+		}
+		rules = normalizedRules
+	case es.scrubbingRules != nil:
+		stored, err := es.scrubbingRules.load(projectID)
+		if err != nil {
+			return nil, err
+		}
+		rules = stored
+	}
+
+	normalized := es.normalizeErrorData(projectID, &request.Event, "", "")
+	tree, err := scrubbableTree(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrub event: %w", err)
+	}
+	response := &dto.ScrubbingTestResponse{Changes: []dto.ScrubbingChange{}}
+
+	if es.scrubber != nil {
+		es.scrubber.scrub(normalized)
+		scrubbed, err := scrubbableTree(normalized)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scrub event: %w", err)
+		}
+		diffScrubbedValues(tree, scrubbed, nil, func(at []string, before, after interface{}) {
+			response.Changes = append(response.Changes, dto.ScrubbingChange{
+				Path:   strings.Join(at, "."),
+				Action: scrubbingActionFilter,
+				Reason: es.scrubber.reason(at, before, after),
+			})
+		})
+		tree = scrubbed
+	}
+
+	for _, rule := range rules {
+		compiled := compiledScrubbingRule{segments: strings.Split(rule.Selector, "."), action: rule.Action}
+		before := cloneScrubbableValue(tree).(map[string]interface{})
+		compiled.applyToMap(tree, nil)
+		diffScrubbedValues(before, tree, nil, func(at []string, _, _ interface{}) {
+			response.Changes = append(response.Changes, dto.ScrubbingChange{
+				Path:   strings.Join(at, "."),
+				Action: rule.Action,
+				Reason: "matches rule " + rule.Selector,
+			})
+		})
+	}
+
+	response.Event = tree
+	return response, nil
+}
+
+// reason tells why the default scrubbing changed a field from before to after
+func (s *DataScrubber) reason(at []string, before, after interface{}) string {
+	if len(at) >= 2 && at[0] == "request" && at[1] == "cookies" {
+		return "cookies are always filtered"
+	}
+	if after == filteredValue && s.isSensitiveField(at[len(at)-1]) {
+		return "field name matches a sensitive field"
+	}
+	if text, ok := before.(string); ok && scrubCardNumbers(text) == after {
+		return "contains a payment card number"
+	}
+	return "contains a sensitive parameter"
+}
+
+// diffScrubbedValues calls changed for each field of before that differs in after, with
+// a nil after when it was removed; at is the path of before
+func diffScrubbedValues(before, after interface{}, at []string, changed func(at []string, before, after interface{})) {
+	switch b := before.(type) {
+	case map[string]interface{}:
+		if a, ok := after.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(b))
+			for key := range b {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				keyPath := append(at[:len(at):len(at)], key)
+				value, ok := a[key]
+				if !ok {
+					changed(keyPath, b[key], nil)
+					continue
+				}
+				diffScrubbedValues(b[key], value, keyPath, changed)
+			}
+			return
+		}
+	case []interface{}:
+		if a, ok := after.([]interface{}); ok && len(a) == len(b) {
+			for i := range b {
+				diffScrubbedValues(b[i], a[i], append(at[:len(at):len(at)], strconv.Itoa(i)), changed)
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(before, after) {
+		changed(at, before, after)
+	}
+}
+
+// cloneScrubbableValue deep copies a decoded JSON value
+func cloneScrubbableValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		cloned := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			cloned[key] = cloneScrubbableValue(item)
+		}
+		return cloned
+	case []interface{}:
+		cloned := make([]interface{}, len(v))
+		for i, item := range v {
+			cloned[i] = cloneScrubbableValue(item)
+		}
+		return cloned
+	}
+	return value
+}
@@ -0,0 +1,408 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+var ErrScrubbingRulesInvalid = errors.New("invalid scrubbing rules")
+
+// Scrubbing rule actions
+const (
+	ScrubbingActionMask   = "mask"   // replaces each character of strings with *
+	ScrubbingActionHash   = "hash"   // replaces strings with their SHA-256, so equal values stay comparable
+	ScrubbingActionRemove = "remove" // removes the field
+)
+
+const (
+	// scrubbingRulesCacheTTL bounds how long ingestion uses a project's rules before
+	// reloading them, so changes made through another server apply within this time
+	scrubbingRulesCacheTTL = 30 * time.Second
+
+	maxScrubbingRules          = 50
+	maxScrubbingSelectorLength = 200
+)
+
+// scrubbingRoots are the first keys of selectors, each an event field rules can reach
+var scrubbingRoots = []string{"message", "exception", "request", "user", "extra", "tags", "breadcrumbs", "frames", "contexts"}
+
+// scrubbableEvent is the part of a normalized event scrubbing rules apply to, with the
+// roots selectors start from
+type scrubbableEvent struct {
+	Message     *string                `json:"message,omitempty"`
+	Exception   scrubbableException    `json:"exception"`
+	Request     *dto.RequestData       `json:"request,omitempty"`
+	User        *dto.UserContext       `json:"user,omitempty"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	Breadcrumbs []dto.BreadcrumbData   `json:"breadcrumbs,omitempty"`
+	Frames      []dto.StackFrame       `json:"frames,omitempty"`
+	Contexts    map[string]interface{} `json:"contexts,omitempty"`
+}
+
+type scrubbableException struct {
+	Value  *string              `json:"value,omitempty"`
+	Values []dto.ExceptionValue `json:"values,omitempty"` // The exception chain, when there are causes
+}
+
+type compiledScrubbingRules struct {
+	rules   []compiledScrubbingRule
+	expires time.Time
+}
+
+type compiledScrubbingRule struct {
+	segments []string
+	action   string
+}
+
+// ScrubbingRuleService manages the data scrubbing rules of projects and applies them to
+// ingested events. Rules are cached briefly per project.
+type ScrubbingRuleService struct {
+	db *database.DB
+
+	mu    sync.Mutex
+	cache map[uuid.UUID]*compiledScrubbingRules
+}
+
+// NewScrubbingRuleService creates a new scrubbing rule service
+func NewScrubbingRuleService(db *database.DB) *ScrubbingRuleService {
+	return &ScrubbingRuleService{
+		db:    db,
+		cache: make(map[uuid.UUID]*compiledScrubbingRules),
+	}
+}
+
+// SetScrubbingRules makes ingestion apply the projects' scrubbing rules. It must be called
+// before the server starts.
+func (es *ErrorService) SetScrubbingRules(scrubbingRules *ScrubbingRuleService) {
+	es.scrubbingRules = scrubbingRules
+}
+
+// GetScrubbingRules returns a project's data scrubbing rules
+func (srs *ScrubbingRuleService) GetScrubbingRules(projectID uuid.UUID) (*dto.ScrubbingRulesResponse, error) {
+	rules, err := srs.load(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ScrubbingRulesResponse{ProjectID: projectID, Rules: rules}, nil
+}
+
+// UpdateScrubbingRules replaces a project's data scrubbing rules, recording the change in
+// the project's setting history
+func (srs *ScrubbingRuleService) UpdateScrubbingRules(userID, projectID uuid.UUID, request dto.ScrubbingRulesRequest) (*dto.ScrubbingRulesResponse, error) {
+	var project models.Project
+	if err := srs.db.Where("id = ?", projectID).First(&project).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	rules, err := normalizeScrubbingRules(request.Rules)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode scrubbing rules: %w", err)
+	}
+
+	var diff settingDiff
+	diff.add("scrubbing_rules", decodeScrubbingRules(project.ScrubbingRules), rules)
+
+	err = srs.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&project).Update("scrubbing_rules", datatypes.JSON(encoded)).Error; err != nil {
+			return err
+		}
+		return recordSettingChanges(tx, userID, models.AuditProjectSettingChanged, settingTargetProject, projectID, project.OrganizationID, &projectID, diff)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update scrubbing rules: %w", err)
+	}
+
+	srs.mu.Lock()
+	delete(srs.cache, projectID)
+	srs.mu.Unlock()
+
+	return &dto.ScrubbingRulesResponse{ProjectID: projectID, Rules: rules}, nil
+}
+
+// Apply scrubs a normalized event with its project's rules. Events are kept unscrubbed by
+// the rules when they cannot be loaded, as the default scrubbing still applies.
+func (srs *ScrubbingRuleService) Apply(normalized *dto.NormalizedErrorData) {
+	rules, err := srs.getCompiled(normalized.ProjectID)
+	if err != nil {
+		log.Printf("Failed to load scrubbing rules of project %s: %v", normalized.ProjectID, err)
+		return
+	}
+	if len(rules.rules) == 0 {
+		return
+	}
+
+	tree, err := scrubbableTree(normalized)
+	if err != nil {
+		log.Printf("Failed to scrub event %s: %v", normalized.EventID, err)
+		return
+	}
+	for _, rule := range rules.rules {
+		rule.applyToMap(tree, nil)
+	}
+	if err := restoreScrubbableTree(normalized, tree); err != nil {
+		log.Printf("Failed to scrub event %s: %v", normalized.EventID, err)
+	}
+}
+
+// scrubbableTree returns the fields of a normalized event rules apply to, as decoded JSON
+func scrubbableTree(normalized *dto.NormalizedErrorData) (map[string]interface{}, error) {
+	event := scrubbableEvent{
+		Message:     normalized.Message,
+		Exception:   scrubbableException{Value: normalized.ExceptionValue, Values: normalized.Exceptions},
+		Request:     normalized.RequestData,
+		User:        normalized.UserContext,
+		Extra:       normalized.ExtraData,
+		Tags:        normalized.Tags,
+		Breadcrumbs: normalized.Breadcrumbs,
+		Frames:      normalized.StackTrace,
+		Contexts:    normalized.Contexts,
+	}
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(encoded, &tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// restoreScrubbableTree sets the fields of a normalized event from a scrubbed tree
+func restoreScrubbableTree(normalized *dto.NormalizedErrorData, tree map[string]interface{}) error {
+	var scrubbed scrubbableEvent
+	encoded, err := json.Marshal(tree)
+	if err == nil {
+		err = json.Unmarshal(encoded, &scrubbed)
+	}
+	if err != nil {
+		return err
+	}
+
+	normalized.Message = scrubbed.Message
+	normalized.ExceptionValue = scrubbed.Exception.Value
+	normalized.Exceptions = scrubbed.Exception.Values
+	normalized.RequestData = scrubbed.Request
+	normalized.UserContext = scrubbed.User
+	normalized.ExtraData = scrubbed.Extra
+	if normalized.ExtraData == nil {
+		normalized.ExtraData = make(map[string]interface{})
+	}
+	normalized.Tags = scrubbed.Tags
+	if normalized.Tags == nil {
+		normalized.Tags = make(map[string]string)
+	}
+	normalized.Breadcrumbs = scrubbed.Breadcrumbs
+	normalized.StackTrace = scrubbed.Frames
+	normalized.Contexts = scrubbed.Contexts
+	return nil
+}
+
+// applyToMap scrubs the fields of data the rule selects; at is the path of data
+func (r *compiledScrubbingRule) applyToMap(data map[string]interface{}, at []string) {
+	for key, value := range data {
+		keyPath := append(at[:len(at):len(at)], strings.ToLower(key))
+		if matchScrubbingSelector(r.segments, keyPath) {
+			if r.action == ScrubbingActionRemove {
+				delete(data, key)
+			} else {
+				data[key] = r.transform(value)
+			}
+			continue
+		}
+		r.applyToValue(value, keyPath)
+	}
+}
+
+// applyToValue scrubs the selected fields within a value; list items share the list's path
+func (r *compiledScrubbingRule) applyToValue(value interface{}, at []string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		r.applyToMap(v, at)
+	case []interface{}:
+		for _, item := range v {
+			r.applyToValue(item, at)
+		}
+	}
+}
+
+// transform masks or hashes the strings of a selected value; other values are kept
+func (r *compiledScrubbingRule) transform(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if r.action == ScrubbingActionHash {
+			sum := sha256.Sum256([]byte(v))
+			return hex.EncodeToString(sum[:])
+		}
+		return strings.Repeat("*", utf8.RuneCountInString(v))
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = r.transform(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.transform(item)
+		}
+	}
+	return value
+}
+
+// matchScrubbingSelector reports whether a selector matches the path of a field
+func matchScrubbingSelector(segments, keyPath []string) bool {
+	if len(segments) == 0 {
+		return len(keyPath) == 0
+	}
+	if segments[0] == "**" {
+		for skip := 0; skip <= len(keyPath); skip++ {
+			if matchScrubbingSelector(segments[1:], keyPath[skip:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(keyPath) == 0 {
+		return false
+	}
+	if matched, _ := path.Match(segments[0], keyPath[0]); !matched {
+		return false
+	}
+	return matchScrubbingSelector(segments[1:], keyPath[1:])
+}
+
+// normalizeScrubbingRules trims and lowercases selectors, drops repeated rules and checks
+// that selectors start from a known root and actions are known
+func normalizeScrubbingRules(rules []dto.ScrubbingRule) ([]dto.ScrubbingRule, error) {
+	if len(rules) > maxScrubbingRules {
+		return nil, fmt.Errorf("%w: at most %d rules are allowed", ErrScrubbingRulesInvalid, maxScrubbingRules)
+	}
+
+	normalized := make([]dto.ScrubbingRule, 0, len(rules))
+	seen := make(map[dto.ScrubbingRule]bool, len(rules))
+	for _, rule := range rules {
+		rule.Selector = strings.ToLower(strings.TrimSpace(rule.Selector))
+		rule.Action = strings.ToLower(strings.TrimSpace(rule.Action))
+
+		switch rule.Action {
+		case ScrubbingActionMask, ScrubbingActionHash, ScrubbingActionRemove:
+		default:
+			return nil, fmt.Errorf("%w: unknown action %q, expected mask, hash or remove", ErrScrubbingRulesInvalid, rule.Action)
+		}
+		if rule.Selector == "" {
+			return nil, fmt.Errorf("%w: selector is required", ErrScrubbingRulesInvalid)
+		}
+		if len(rule.Selector) > maxScrubbingSelectorLength {
+			return nil, fmt.Errorf("%w: selector %q is longer than %d characters", ErrScrubbingRulesInvalid, rule.Selector, maxScrubbingSelectorLength)
+		}
+		if err := validateScrubbingSelector(rule.Selector); err != nil {
+			return nil, err
+		}
+
+		if seen[rule] {
+			continue
+		}
+		seen[rule] = true
+		normalized = append(normalized, rule)
+	}
+	return normalized, nil
+}
+
+func validateScrubbingSelector(selector string) error {
+	segments := strings.Split(selector, ".")
+	for _, segment := range segments {
+		if segment == "" {
+			return fmt.Errorf("%w: selector %q has an empty key", ErrScrubbingRulesInvalid, selector)
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("%w: selector %q has an invalid pattern", ErrScrubbingRulesInvalid, selector)
+		}
+	}
+
+	if segments[0] == "**" {
+		return nil
+	}
+	for _, root := range scrubbingRoots {
+		if matched, _ := path.Match(segments[0], root); matched {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: selector %q must start with one of %s or **", ErrScrubbingRulesInvalid, selector, strings.Join(scrubbingRoots, ", "))
+}
+
+// decodeScrubbingRules returns the rules stored with a project; none when unset or unreadable
+func decodeScrubbingRules(stored datatypes.JSON) []dto.ScrubbingRule {
+	rules := []dto.ScrubbingRule{}
+	if len(stored) > 0 {
+		json.Unmarshal(stored, &rules)
+	}
+	return rules
+}
+
+// load returns a project's scrubbing rules
+func (srs *ScrubbingRuleService) load(projectID uuid.UUID) ([]dto.ScrubbingRule, error) {
+	var project models.Project
+	if err := srs.db.Select("id", "scrubbing_rules").Where("id = ?", projectID).First(&project).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("failed to get scrubbing rules: %w", err)
+	}
+	return decodeScrubbingRules(project.ScrubbingRules), nil
+}
+
+// getCompiled returns a project's rules from the cache, loading them when missing or expired
+func (srs *ScrubbingRuleService) getCompiled(projectID uuid.UUID) (*compiledScrubbingRules, error) {
+	now := time.Now()
+
+	srs.mu.Lock()
+	rules, ok := srs.cache[projectID]
+	srs.mu.Unlock()
+	if ok && now.Before(rules.expires) {
+		return rules, nil
+	}
+
+	stored, err := srs.load(projectID)
+	if err != nil && !errors.Is(err, ErrProjectNotFound) {
+		return nil, err
+	}
+	rules = &compiledScrubbingRules{expires: now.Add(scrubbingRulesCacheTTL)}
+	for _, rule := range stored {
+		rules.rules = append(rules.rules, compiledScrubbingRule{segments: strings.Split(rule.Selector, "."), action: rule.Action})
+	}
+
+	srs.mu.Lock()
+	if len(srs.cache) >= projectAccessCacheSweepSize {
+		for id, cached := range srs.cache {
+			if now.After(cached.expires) {
+				delete(srs.cache, id)
+			}
+		}
+	}
+	srs.cache[projectID] = rules
+	srs.mu.Unlock()
+	return rules, nil
+}
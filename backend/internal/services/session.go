@@ -0,0 +1,236 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidSessionData = errors.New("invalid session data")
+)
+
+const defaultSessionEnvironment = "production"
+
+type SessionService struct {
+	db           *database.DB
+	releases     *releaseRecorder
+	environments *environmentRecorder
+}
+
+// NewSessionService creates a new release health session service
+func NewSessionService(db *database.DB) *SessionService {
+	return &SessionService{db: db, releases: newReleaseRecorder(db), environments: newEnvironmentRecorder(db)}
+}
+
+// ProcessSession stores a single session update, creating the session on first sight.
+// Updates with a lower sequence number than the stored one arrive out of order and are ignored.
+func (ss *SessionService) ProcessSession(projectID uuid.UUID, update *dto.SessionUpdate) error {
+	if update == nil {
+		return fmt.Errorf("%w: session is nil", ErrInvalidSessionData)
+	}
+	if update.SessionID == "" {
+		return fmt.Errorf("%w: missing sid", ErrInvalidSessionData)
+	}
+	if update.Attrs.Release == "" {
+		return fmt.Errorf("%w: missing release", ErrInvalidSessionData)
+	}
+
+	status := models.SessionOk
+	if update.Status != nil {
+		status = models.SessionStatus(*update.Status)
+		if !isValidSessionStatus(status) {
+			return fmt.Errorf("%w: invalid status '%s'", ErrInvalidSessionData, *update.Status)
+		}
+	}
+
+	now := time.Now()
+	timestamp := now
+	if update.Timestamp != nil {
+		timestamp = *update.Timestamp
+	}
+	started := timestamp
+	if update.Started != nil {
+		started = *update.Started
+	}
+	var sequence int64
+	if update.Sequence != nil {
+		sequence = *update.Sequence
+	}
+
+	ss.releases.record(projectID, &update.Attrs.Release)
+	ss.environments.record(projectID, sessionEnvironment(update.Attrs.Environment))
+
+	return ss.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.Session
+		err := tx.Where("project_id = ? AND session_id = ?", projectID, update.SessionID).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			session := models.Session{
+				ProjectID:   projectID,
+				SessionID:   &update.SessionID,
+				DistinctID:  update.DistinctID,
+				Release:     update.Attrs.Release,
+				Environment: sessionEnvironment(update.Attrs.Environment),
+				Status:      status,
+				Errors:      update.Errors,
+				Sequence:    sequence,
+				Duration:    update.Duration,
+				Quantity:    1,
+				Started:     started,
+				Timestamp:   timestamp,
+			}
+			if err := tx.Create(&session).Error; err != nil {
+				return fmt.Errorf("failed to create session: %w", err)
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to query session: %w", err)
+		}
+
+		if sequence < existing.Sequence {
+			return nil
+		}
+
+		updates := map[string]interface{}{
+			"status":     status,
+			"errors":     update.Errors,
+			"sequence":   sequence,
+			"duration":   update.Duration,
+			"timestamp":  timestamp,
+			"updated_at": now,
+		}
+		if update.DistinctID != nil {
+			updates["distinct_id"] = *update.DistinctID
+		}
+
+		if err := tx.Model(&existing).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update session: %w", err)
+		}
+		return nil
+	})
+}
+
+// ProcessSessionAggregates stores pre-aggregated session counts, one row per non-empty status
+func (ss *SessionService) ProcessSessionAggregates(projectID uuid.UUID, aggregates *dto.SessionAggregates) error {
+	if aggregates == nil {
+		return fmt.Errorf("%w: session aggregates are nil", ErrInvalidSessionData)
+	}
+	if aggregates.Attrs.Release == "" {
+		return fmt.Errorf("%w: missing release", ErrInvalidSessionData)
+	}
+
+	environment := sessionEnvironment(aggregates.Attrs.Environment)
+	now := time.Now()
+
+	var sessions []models.Session
+	for _, bucket := range aggregates.Aggregates {
+		counts := []struct {
+			status   models.SessionStatus
+			quantity int
+		}{
+			{models.SessionExited, bucket.Exited},
+			{models.SessionErrored, bucket.Errored},
+			{models.SessionAbnormal, bucket.Abnormal},
+			{models.SessionCrashed, bucket.Crashed},
+		}
+
+		for _, count := range counts {
+			if count.quantity <= 0 {
+				continue
+			}
+			sessions = append(sessions, models.Session{
+				ProjectID:   projectID,
+				DistinctID:  bucket.DistinctID,
+				Release:     aggregates.Attrs.Release,
+				Environment: environment,
+				Status:      count.status,
+				Quantity:    count.quantity,
+				Started:     bucket.Started,
+				Timestamp:   now,
+			})
+		}
+	}
+
+	if len(sessions) == 0 {
+		return nil
+	}
+	ss.releases.record(projectID, &aggregates.Attrs.Release)
+	ss.environments.record(projectID, environment)
+
+	if err := ss.db.Create(&sessions).Error; err != nil {
+		return fmt.Errorf("failed to store session aggregates: %w", err)
+	}
+
+	return nil
+}
+
+// GetReleaseHealth computes crash-free session and user rates per release and environment
+func (ss *SessionService) GetReleaseHealth(projectID uuid.UUID, filters *dto.ReleaseHealthFilters) ([]dto.ReleaseHealthResponse, error) {
+	// Sessions that ended normally but reported errors count as errored
+	query := ss.db.Model(&models.Session{}).
+		Select(`release, environment,
+			SUM(quantity) AS total_sessions,
+			SUM(CASE WHEN status = 'crashed' THEN quantity ELSE 0 END) AS crashed_sessions,
+			SUM(CASE WHEN status = 'errored' OR (errors > 0 AND status IN ('ok', 'exited')) THEN quantity ELSE 0 END) AS errored_sessions,
+			SUM(CASE WHEN status = 'abnormal' THEN quantity ELSE 0 END) AS abnormal_sessions,
+			COUNT(DISTINCT distinct_id) AS total_users,
+			COUNT(DISTINCT CASE WHEN status = 'crashed' THEN distinct_id END) AS crashed_users`).
+		Where("project_id = ?", projectID).
+		Group("release, environment").
+		Order("release DESC, environment")
+
+	if filters != nil {
+		if filters.Release != "" {
+			query = query.Where("release = ?", filters.Release)
+		}
+		if filters.Environment != "" {
+			query = query.Where("environment = ?", filters.Environment)
+		}
+		if filters.Since != nil {
+			query = query.Where("started >= ?", *filters.Since)
+		}
+	}
+
+	var results []dto.ReleaseHealthResponse
+	if err := query.Scan(&results).Error; err != nil {
+		return nil, fmt.Errorf("failed to get release health: %w", err)
+	}
+
+	for i := range results {
+		results[i].CrashFreeSessions = crashFreeRate(results[i].CrashedSessions, results[i].TotalSessions)
+		results[i].CrashFreeUsers = crashFreeRate(results[i].CrashedUsers, results[i].TotalUsers)
+	}
+
+	return results, nil
+}
+
+// crashFreeRate returns the crash-free percentage, or 100 when nothing was recorded
+func crashFreeRate(crashed, total int64) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(total-crashed) / float64(total) * 100
+}
+
+func sessionEnvironment(environment *string) string {
+	if environment == nil || *environment == "" {
+		return defaultSessionEnvironment
+	}
+	return *environment
+}
+
+func isValidSessionStatus(status models.SessionStatus) bool {
+	switch status {
+	case models.SessionOk, models.SessionExited, models.SessionErrored, models.SessionCrashed, models.SessionAbnormal:
+		return true
+	}
+	return false
+}
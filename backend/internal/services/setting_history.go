@@ -0,0 +1,154 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Audit log target types of setting changes
+const (
+	settingTargetProject      = "project"
+	settingTargetOrganization = "organization"
+)
+
+// settingChange is the before and after value of one setting; From and To are the
+// audit log entry's data
+type settingChange struct {
+	Setting string      `json:"-"`
+	From    interface{} `json:"from"`
+	To      interface{} `json:"to"`
+}
+
+// settingDiff collects the settings an update actually changes
+type settingDiff []settingChange
+
+// add records a setting change unless the value stays the same. Pointers are compared
+// and recorded by value, nil pointers as null.
+func (d *settingDiff) add(setting string, from, to interface{}) {
+	from, to = settingValue(from), settingValue(to)
+	if reflect.DeepEqual(from, to) {
+		return
+	}
+	*d = append(*d, settingChange{Setting: setting, From: from, To: to})
+}
+
+func settingValue(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Ptr {
+		return value
+	}
+	if v.IsNil() {
+		return nil
+	}
+	return v.Elem().Interface()
+}
+
+// recordSettingChanges writes an audit log entry per changed setting within the
+// transaction of the change
+func recordSettingChanges(tx *gorm.DB, actorID uuid.UUID, action, targetType string, targetID, organizationID uuid.UUID, projectID *uuid.UUID, diff settingDiff) error {
+	for _, change := range diff {
+		encoded, err := json.Marshal(change)
+		if err != nil {
+			return fmt.Errorf("failed to encode setting change: %w", err)
+		}
+
+		setting := change.Setting
+		entry := models.AuditLogEntry{
+			ActorID:        &actorID,
+			Action:         action,
+			TargetType:     targetType,
+			TargetID:       targetID,
+			OrganizationID: &organizationID,
+			ProjectID:      projectID,
+			Setting:        &setting,
+			Data:           datatypes.JSON(encoded),
+		}
+		if err := tx.Create(&entry).Error; err != nil {
+			return fmt.Errorf("failed to record setting change: %w", err)
+		}
+	}
+	return nil
+}
+
+// listSettingChanges returns the setting changes of a project or organization, newest
+// first, optionally only those of one setting
+func listSettingChanges(db *gorm.DB, targetType string, targetID uuid.UUID, setting string, page, limit int) (*dto.SettingHistoryResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 25
+	}
+
+	query := db.Model(&models.AuditLogEntry{}).
+		Where("target_type = ? AND target_id = ? AND setting IS NOT NULL", targetType, targetID)
+	if setting != "" {
+		query = query.Where("setting = ?", setting)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count setting changes: %w", err)
+	}
+
+	var entries []models.AuditLogEntry
+	if err := query.Order("created_at DESC").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list setting changes: %w", err)
+	}
+
+	// Load the actors in one query
+	actorIDs := collectIDs(entries, func(entry *models.AuditLogEntry) uuid.UUID {
+		if entry.ActorID == nil {
+			return uuid.Nil
+		}
+		return *entry.ActorID
+	})
+	users, err := loadByID(db, actorIDs, func(user *models.User) uuid.UUID { return user.ID })
+	if err != nil {
+		return nil, fmt.Errorf("failed to load setting change actors: %w", err)
+	}
+	actors := make(map[uuid.UUID]*dto.SettingChangeActorResponse, len(users))
+	for id, user := range users {
+		actors[id] = &dto.SettingChangeActorResponse{ID: user.ID, Name: user.Name, Email: user.Email}
+	}
+
+	changes := make([]dto.SettingChangeResponse, len(entries))
+	for i, entry := range entries {
+		var change struct {
+			From json.RawMessage `json:"from"`
+			To   json.RawMessage `json:"to"`
+		}
+		if err := json.Unmarshal(entry.Data, &change); err != nil {
+			return nil, fmt.Errorf("failed to decode setting change: %w", err)
+		}
+
+		changes[i] = dto.SettingChangeResponse{
+			ID:        entry.ID,
+			Setting:   *entry.Setting,
+			From:      change.From,
+			To:        change.To,
+			CreatedAt: entry.CreatedAt,
+		}
+		if entry.ActorID != nil {
+			changes[i].Actor = actors[*entry.ActorID]
+		}
+	}
+
+	return &dto.SettingHistoryResponse{
+		Changes:    changes,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: dto.CalculateTotalPages(total, limit),
+	}, nil
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// defaultSpikeProtectionWindow is how far back dropped event stats look when no since is given
+	defaultSpikeProtectionWindow = 7 * 24 * time.Hour
+
+	// spikeProtectionWarmup is how many minutes of a project's rate are recorded before
+	// spikes are detected, so a restarted server does not mistake the usual rate for a spike
+	spikeProtectionWarmup = 5
+)
+
+// SpikeProtectionConfig configures spike protection for every project
+type SpikeProtectionConfig struct {
+	Multiplier float64       // a minute over Multiplier × the trailing average is a spike; 0 disables protection
+	Window     time.Duration // trailing window the average rate is taken over
+	MinRate    int           // events per minute always accepted, however quiet the project usually is
+	SampleRate float64       // fraction of the excess events kept during a spike; 0 drops them all
+}
+
+// SpikeCheck is the outcome of counting an ingestion request against a project's spike threshold
+type SpikeCheck struct {
+	Allowed    bool
+	RetryAfter time.Duration
+}
+
+// SpikeProtectionService drops or samples the events of projects whose rate suddenly
+// exceeds a multiple of their trailing average, so one misbehaving project cannot flood
+// ingestion. Rates are tracked in memory per server; dropped counts are buffered and
+// written to the database periodically so owners can see what was discarded.
+type SpikeProtectionService struct {
+	db     *database.DB
+	config SpikeProtectionConfig
+
+	mu       sync.Mutex
+	counters map[uuid.UUID]*spikeCounter
+	pending  map[spikeDropKey]int64 // dropped events not yet written
+}
+
+type spikeDropKey struct {
+	projectID uuid.UUID
+	bucket    time.Time
+}
+
+// spikeCounter holds a project's accepted events per minute over the trailing window
+type spikeCounter struct {
+	minute   int64 // current minute since the epoch
+	current  int   // events accepted in the current minute
+	dropped  int   // events dropped in the current minute
+	history  []int // events accepted in previous minutes, indexed by minute modulo the window
+	total    int   // sum of history
+	observed int   // minutes of history recorded, up to the window
+	spiking  bool
+}
+
+// NewSpikeProtectionService creates a new spike protection service
+func NewSpikeProtectionService(db *database.DB, config SpikeProtectionConfig) *SpikeProtectionService {
+	if config.Window < time.Minute {
+		config.Window = time.Hour
+	}
+	config.SampleRate = math.Max(0, math.Min(1, config.SampleRate))
+
+	return &SpikeProtectionService{
+		db:       db,
+		config:   config,
+		counters: make(map[uuid.UUID]*spikeCounter),
+		pending:  make(map[spikeDropKey]int64),
+	}
+}
+
+// Check counts an ingestion request against the project's spike threshold. During a spike
+// the excess is dropped, except for a sample of it when a sample rate is configured.
+func (sp *SpikeProtectionService) Check(projectID uuid.UUID) SpikeCheck {
+	if sp.config.Multiplier <= 0 {
+		return SpikeCheck{Allowed: true}
+	}
+	now := time.Now()
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	counter := sp.counterLocked(projectID, now)
+	threshold := sp.threshold(counter)
+	if threshold == 0 || counter.current < threshold || (sp.config.SampleRate > 0 && rand.Float64() < sp.config.SampleRate) {
+		counter.current++
+		return SpikeCheck{Allowed: true}
+	}
+
+	if !counter.spiking {
+		counter.spiking = true
+		log.Printf("Spike protection engaged for project %s: %d events this minute, baseline %.1f per minute",
+			projectID, counter.current, counter.baseline())
+	}
+	counter.dropped++
+	sp.pending[spikeDropKey{projectID: projectID, bucket: now.UTC().Truncate(time.Hour)}]++
+
+	return SpikeCheck{RetryAfter: now.Truncate(time.Minute).Add(time.Minute).Sub(now)}
+}
+
+// FlushDrops adds the buffered dropped event counts to the hourly totals in the database.
+// It is run periodically by the scheduler and on shutdown.
+func (sp *SpikeProtectionService) FlushDrops(ctx context.Context) error {
+	pending := takeCounts(&sp.mu, &sp.pending)
+	err := flushCounts(&sp.mu, &sp.pending, pending, func(key spikeDropKey, dropped int64) error {
+		row := models.SpikeProtectionDrop{ProjectID: key.projectID, Bucket: key.bucket, Dropped: dropped}
+		return sp.db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "project_id"}, {Name: "bucket"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"dropped":    clause.Expr{SQL: "spike_protection_drops.dropped + excluded.dropped"},
+				"updated_at": time.Now(),
+			}),
+		}).Create(&row).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record spike protection drops: %w", err)
+	}
+	return nil
+}
+
+// GetSpikeProtection returns the spike protection state of a project and the events it
+// dropped since the given time, per hour
+func (sp *SpikeProtectionService) GetSpikeProtection(projectID uuid.UUID, since time.Time) (*dto.SpikeProtectionResponse, error) {
+	if since.IsZero() {
+		since = time.Now().Add(-defaultSpikeProtectionWindow)
+	}
+	since = since.UTC().Truncate(time.Hour)
+
+	var rows []models.SpikeProtectionDrop
+	if err := sp.db.Where("project_id = ? AND bucket >= ?", projectID, since).
+		Order("bucket ASC").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve spike protection drops: %w", err)
+	}
+
+	dropped := make(map[time.Time]int64, len(rows))
+	for _, row := range rows {
+		dropped[row.Bucket.UTC()] += row.Dropped
+	}
+
+	response := &dto.SpikeProtectionResponse{
+		ProjectID:     projectID,
+		Enabled:       sp.config.Multiplier > 0,
+		Multiplier:    sp.config.Multiplier,
+		MinRate:       sp.config.MinRate,
+		WindowSeconds: int(sp.config.Window / time.Second),
+		SampleRate:    sp.config.SampleRate,
+		Since:         since,
+		Drops:         []dto.SpikeProtectionDropStats{},
+	}
+
+	sp.mu.Lock()
+	for key, count := range sp.pending {
+		if key.projectID == projectID && !key.bucket.Before(since) {
+			dropped[key.bucket] += count
+		}
+	}
+	if response.Enabled {
+		counter := sp.counterLocked(projectID, time.Now())
+		response.Active = counter.dropped > 0
+		response.CurrentRate = counter.current
+		response.BaselineRate = counter.baseline()
+		response.Threshold = sp.threshold(counter)
+	}
+	sp.mu.Unlock()
+
+	for bucket, count := range dropped {
+		response.Drops = append(response.Drops, dto.SpikeProtectionDropStats{Bucket: bucket, Dropped: count})
+		response.TotalDropped += count
+	}
+	sort.Slice(response.Drops, func(i, j int) bool {
+		return response.Drops[i].Bucket.Before(response.Drops[j].Bucket)
+	})
+	return response, nil
+}
+
+// threshold is the number of events per minute accepted before the excess is dropped,
+// or 0 while the project's baseline is still being recorded
+func (sp *SpikeProtectionService) threshold(counter *spikeCounter) int {
+	if counter.observed < spikeProtectionWarmup {
+		return 0
+	}
+	threshold := int(math.Ceil(sp.config.Multiplier * counter.baseline()))
+	if threshold < sp.config.MinRate {
+		threshold = sp.config.MinRate
+	}
+	return threshold
+}
+
+// counterLocked returns the project's counter, moved forward to the current minute; sp.mu must be held
+func (sp *SpikeProtectionService) counterLocked(projectID uuid.UUID, now time.Time) *spikeCounter {
+	minute := now.Unix() / 60
+	counter, ok := sp.counters[projectID]
+	if !ok {
+		counter = &spikeCounter{minute: minute, history: make([]int, int(sp.config.Window/time.Minute))}
+		sp.counters[projectID] = counter
+	}
+	if counter.advance(minute) {
+		log.Printf("Spike protection disengaged for project %s", projectID)
+	}
+	return counter
+}
+
+// advance moves the counter to the given minute, recording the minutes in between in the
+// history. It reports whether a spike ended: a minute passed without dropping anything.
+func (c *spikeCounter) advance(minute int64) bool {
+	if minute <= c.minute {
+		return false
+	}
+	// Minutes skipped over had no events, so nothing was dropped in them either
+	ended := c.spiking && (c.dropped == 0 || minute-c.minute > 1)
+	if ended {
+		c.spiking = false
+	}
+
+	size := int64(len(c.history))
+	if minute-c.minute > size {
+		// Idle for longer than the window: the whole history is quiet minutes
+		for i := range c.history {
+			c.history[i] = 0
+		}
+		c.total = 0
+		c.observed = len(c.history)
+	} else {
+		for m := c.minute; m < minute; m++ {
+			slot := m % size
+			c.total += c.current - c.history[slot]
+			c.history[slot] = c.current
+			c.current = 0
+			if c.observed < len(c.history) {
+				c.observed++
+			}
+		}
+	}
+
+	c.minute = minute
+	c.current = 0
+	c.dropped = 0
+	return ended
+}
+
+// baseline is the average number of events accepted per minute over the recorded history
+func (c *spikeCounter) baseline() float64 {
+	if c.observed == 0 {
+		return 0
+	}
+	return float64(c.total) / float64(c.observed)
+}
@@ -0,0 +1,711 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrStatusPageNotFound       = errors.New("status page not found")
+	ErrStatusComponentNotFound  = errors.New("status component not found")
+	ErrStatusSubscriberNotFound = errors.New("status subscriber not found")
+	ErrStatusProjectNotFound    = errors.New("project not found in organization")
+	ErrStatusPageInvalidRequest = errors.New("invalid status page request")
+)
+
+// Component statuses shown on the status page, derived from the incidents affecting a component
+const (
+	ComponentOperational = "operational"
+	ComponentDegraded    = "degraded"
+	ComponentOutage      = "outage"
+)
+
+// Resolved incidents stay on the status page for this long
+const statusPageResolvedRetention = 7 * 24 * time.Hour
+
+var componentStatusRank = map[string]int{
+	ComponentOperational: 0,
+	ComponentDegraded:    1,
+	ComponentOutage:      2,
+}
+
+type StatusPageService struct {
+	db        *database.DB
+	outbox    *OutboxService
+	publicURL string
+}
+
+// NewStatusPageService creates a new status page service. Subscription emails are sent
+// through the outbox; publicURL is the base URL of the API, used for the links in them.
+func NewStatusPageService(db *database.DB, outbox *OutboxService, publicURL string) *StatusPageService {
+	return &StatusPageService{
+		db:        db,
+		outbox:    outbox,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+// GetStatusPage returns the status page configuration of an organization; organizations
+// that never configured one get a disabled page titled after the organization
+func (ss *StatusPageService) GetStatusPage(orgID uuid.UUID) (*dto.StatusPageResponse, error) {
+	page, org, err := ss.loadStatusPage(orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	var subscribers int64
+	if err := ss.db.Model(&models.StatusSubscriber{}).
+		Where("organization_id = ? AND confirmed_at IS NOT NULL", orgID).
+		Count(&subscribers).Error; err != nil {
+		return nil, fmt.Errorf("failed to count subscribers: %w", err)
+	}
+
+	return &dto.StatusPageResponse{
+		OrganizationID:  orgID,
+		Enabled:         page.Enabled,
+		Title:           page.Title,
+		Description:     page.Description,
+		URL:             ss.statusURL(org.Slug),
+		SubscriberCount: subscribers,
+	}, nil
+}
+
+// UpdateStatusPage enables, disables or retitles the status page of an organization
+func (ss *StatusPageService) UpdateStatusPage(orgID uuid.UUID, request dto.StatusPageRequest) (*dto.StatusPageResponse, error) {
+	page, _, err := ss.loadStatusPage(orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Enabled != nil {
+		page.Enabled = *request.Enabled
+	}
+	if request.Title != nil {
+		title := strings.TrimSpace(*request.Title)
+		if title == "" {
+			return nil, fmt.Errorf("%w: title cannot be empty", ErrStatusPageInvalidRequest)
+		}
+		page.Title = title
+	}
+	if request.Description != nil {
+		if description := strings.TrimSpace(*request.Description); description == "" {
+			page.Description = nil
+		} else {
+			page.Description = &description
+		}
+	}
+
+	if err := ss.db.Save(page).Error; err != nil {
+		return nil, fmt.Errorf("failed to save status page: %w", err)
+	}
+	return ss.GetStatusPage(orgID)
+}
+
+// ListComponents lists the components of an organization's status page with their current status
+func (ss *StatusPageService) ListComponents(orgID uuid.UUID) ([]dto.StatusComponentResponse, error) {
+	components, err := ss.loadComponents(ss.db.DB, orgID)
+	if err != nil {
+		return nil, err
+	}
+	state, err := ss.loadIncidentState(orgID, components)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.StatusComponentResponse, len(components))
+	for i, component := range components {
+		responses[i] = convertStatusComponentToResponse(component, state.componentStatus(component.ID))
+	}
+	return responses, nil
+}
+
+// CreateComponent adds a component to the status page, mapping the given projects to it
+func (ss *StatusPageService) CreateComponent(orgID uuid.UUID, request dto.CreateStatusComponentRequest) (*dto.StatusComponentResponse, error) {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrStatusPageInvalidRequest)
+	}
+
+	component := models.StatusComponent{
+		OrganizationID: orgID,
+		Name:           name,
+		Description:    request.Description,
+		Position:       request.Position,
+	}
+	component.ID = uuid.New()
+
+	err := ss.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&component).Error; err != nil {
+			return fmt.Errorf("failed to create status component: %w", err)
+		}
+		return ss.setComponentProjects(tx, &component, request.ProjectIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ss.getComponent(orgID, component.ID)
+}
+
+// UpdateComponent changes a component; project_ids replaces its project mapping
+func (ss *StatusPageService) UpdateComponent(orgID, componentID uuid.UUID, request dto.UpdateStatusComponentRequest) (*dto.StatusComponentResponse, error) {
+	var component models.StatusComponent
+	if err := ss.db.Where("id = ? AND organization_id = ?", componentID, orgID).First(&component).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStatusComponentNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve status component: %w", err)
+	}
+
+	updates := make(map[string]interface{})
+	if request.Name != nil {
+		name := strings.TrimSpace(*request.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: name cannot be empty", ErrStatusPageInvalidRequest)
+		}
+		updates["name"] = name
+	}
+	if request.Description != nil {
+		updates["description"] = *request.Description
+	}
+	if request.Position != nil {
+		updates["position"] = *request.Position
+	}
+
+	err := ss.db.Transaction(func(tx *gorm.DB) error {
+		if len(updates) > 0 {
+			if err := tx.Model(&component).Updates(updates).Error; err != nil {
+				return fmt.Errorf("failed to update status component: %w", err)
+			}
+		}
+		if request.ProjectIDs == nil {
+			return nil
+		}
+		if err := tx.Where("component_id = ?", component.ID).Delete(&models.StatusComponentProject{}).Error; err != nil {
+			return fmt.Errorf("failed to clear component projects: %w", err)
+		}
+		return ss.setComponentProjects(tx, &component, *request.ProjectIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ss.getComponent(orgID, component.ID)
+}
+
+// DeleteComponent removes a component and its project mapping from the status page
+func (ss *StatusPageService) DeleteComponent(orgID, componentID uuid.UUID) error {
+	return ss.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND organization_id = ?", componentID, orgID).Delete(&models.StatusComponent{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete status component: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrStatusComponentNotFound
+		}
+		if err := tx.Where("component_id = ?", componentID).Delete(&models.StatusComponentProject{}).Error; err != nil {
+			return fmt.Errorf("failed to delete component projects: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListSubscribers lists the email subscribers of an organization's status page
+func (ss *StatusPageService) ListSubscribers(orgID uuid.UUID) ([]dto.StatusSubscriberResponse, error) {
+	var subscribers []models.StatusSubscriber
+	if err := ss.db.Where("organization_id = ?", orgID).Order("created_at ASC").Find(&subscribers).Error; err != nil {
+		return nil, fmt.Errorf("failed to list subscribers: %w", err)
+	}
+
+	responses := make([]dto.StatusSubscriberResponse, len(subscribers))
+	for i, subscriber := range subscribers {
+		responses[i] = dto.StatusSubscriberResponse{
+			ID:          subscriber.ID,
+			Email:       subscriber.Email,
+			ConfirmedAt: subscriber.ConfirmedAt,
+			CreatedAt:   subscriber.CreatedAt,
+		}
+	}
+	return responses, nil
+}
+
+// RemoveSubscriber removes an email subscriber from the status page
+func (ss *StatusPageService) RemoveSubscriber(orgID, subscriberID uuid.UUID) error {
+	result := ss.db.Where("id = ? AND organization_id = ?", subscriberID, orgID).Delete(&models.StatusSubscriber{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove subscriber: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrStatusSubscriberNotFound
+	}
+	return nil
+}
+
+// GetPublicStatus returns the public status of an organization. Only incidents affecting at
+// least one component are shown, so incidents of unmapped projects stay internal.
+func (ss *StatusPageService) GetPublicStatus(orgSlug string) (*dto.PublicStatusResponse, error) {
+	org, page, err := ss.findEnabledStatusPage(orgSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	components, err := ss.loadComponents(ss.db.DB, org.ID)
+	if err != nil {
+		return nil, err
+	}
+	state, err := ss.loadIncidentState(org.ID, components)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.PublicStatusResponse{
+		Organization: org.Name,
+		Title:        page.Title,
+		Description:  page.Description,
+		Status:       ComponentOperational,
+		Components:   make([]dto.PublicStatusComponent, len(components)),
+		Incidents:    []dto.PublicStatusIncident{},
+		UpdatedAt:    time.Now().UTC(),
+	}
+	for i, component := range components {
+		status := state.componentStatus(component.ID)
+		response.Components[i] = dto.PublicStatusComponent{
+			Name:        component.Name,
+			Description: component.Description,
+			Status:      status,
+		}
+		if componentStatusRank[status] > componentStatusRank[response.Status] {
+			response.Status = status
+		}
+	}
+	for _, incident := range state.incidents {
+		response.Incidents = append(response.Incidents, dto.PublicStatusIncident{
+			ID:         incident.ID,
+			Title:      incident.Title,
+			Status:     string(incident.Status),
+			Components: state.componentNames(incident.ID),
+			StartedAt:  incident.StartedAt,
+			ResolvedAt: incident.ResolvedAt,
+		})
+	}
+	return response, nil
+}
+
+// Subscribe registers an email address for status updates and sends it a confirmation link.
+// Subscribing an already confirmed address is a no-op, and repeated requests for an
+// unconfirmed address within a minute send a single email.
+func (ss *StatusPageService) Subscribe(orgSlug, email string) error {
+	org, page, err := ss.findEnabledStatusPage(orgSlug)
+	if err != nil {
+		return err
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	if len(email) > 255 || !emailRegex.MatchString(email) {
+		return fmt.Errorf("%w: invalid email address", ErrStatusPageInvalidRequest)
+	}
+
+	return ss.db.Transaction(func(tx *gorm.DB) error {
+		var subscriber models.StatusSubscriber
+		err := tx.Where("organization_id = ? AND email = ?", org.ID, email).First(&subscriber).Error
+		switch {
+		case err == nil:
+			if subscriber.ConfirmedAt != nil {
+				return nil
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			token, err := generateToken()
+			if err != nil {
+				return err
+			}
+			subscriber = models.StatusSubscriber{OrganizationID: org.ID, Email: email, Token: token}
+			if err := tx.Create(&subscriber).Error; err != nil {
+				return fmt.Errorf("failed to create subscriber: %w", err)
+			}
+		default:
+			return fmt.Errorf("failed to retrieve subscriber: %w", err)
+		}
+
+		key := fmt.Sprintf("status-confirm:%s:%d", subscriber.ID, time.Now().Unix()/60)
+		body := fmt.Sprintf("Confirm that you want to receive status updates for %s:\n\n%s\n\nIf you did not request this, ignore this email.\n",
+			page.Title, ss.subscriptionURL(org.Slug, "confirm", subscriber.Token))
+		return ss.outbox.EnqueueEmail(tx, key, email, fmt.Sprintf("Confirm your subscription to %s", page.Title), body)
+	})
+}
+
+// ConfirmSubscription confirms the subscriber the token was sent to
+func (ss *StatusPageService) ConfirmSubscription(orgSlug, token string) error {
+	subscriber, err := ss.findSubscriberByToken(orgSlug, token)
+	if err != nil {
+		return err
+	}
+	if subscriber.ConfirmedAt != nil {
+		return nil
+	}
+	if err := ss.db.Model(subscriber).Update("confirmed_at", time.Now()).Error; err != nil {
+		return fmt.Errorf("failed to confirm subscription: %w", err)
+	}
+	return nil
+}
+
+// Unsubscribe removes the subscriber the token was sent to
+func (ss *StatusPageService) Unsubscribe(orgSlug, token string) error {
+	subscriber, err := ss.findSubscriberByToken(orgSlug, token)
+	if err != nil {
+		return err
+	}
+	if err := ss.db.Delete(subscriber).Error; err != nil {
+		return fmt.Errorf("failed to unsubscribe: %w", err)
+	}
+	return nil
+}
+
+// NotifyIncidentChange emails confirmed subscribers when an incident affecting status page
+// components opens or changes status. It runs in the incident's transaction, so the emails
+// are queued in the outbox exactly when the change commits.
+func (ss *StatusPageService) NotifyIncidentChange(tx *gorm.DB, incident *models.Incident, oldStatus models.IncidentStatus) error {
+	var page models.StatusPage
+	if err := tx.Where("organization_id = ? AND enabled = ?", incident.OrganizationID, true).First(&page).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to retrieve status page: %w", err)
+	}
+
+	components, err := ss.loadComponents(tx, incident.OrganizationID)
+	if err != nil {
+		return err
+	}
+	affected, err := ss.affectedComponents(tx, []uuid.UUID{incident.ID}, components)
+	if err != nil {
+		return err
+	}
+	names := affected.componentNames(incident.ID)
+	if len(names) == 0 {
+		return nil
+	}
+
+	var org models.Organization
+	if err := tx.Select("id, slug").First(&org, incident.OrganizationID).Error; err != nil {
+		return fmt.Errorf("failed to retrieve organization: %w", err)
+	}
+
+	var subscribers []models.StatusSubscriber
+	if err := tx.Where("organization_id = ? AND confirmed_at IS NOT NULL", incident.OrganizationID).
+		Find(&subscribers).Error; err != nil {
+		return fmt.Errorf("failed to retrieve subscribers: %w", err)
+	}
+	if len(subscribers) == 0 {
+		return nil
+	}
+
+	status := incidentStatusLabel(incident.Status)
+	subject := fmt.Sprintf("[%s] %s: %s", page.Title, status, incident.Title)
+	if oldStatus == "" {
+		subject = fmt.Sprintf("[%s] New incident: %s", page.Title, incident.Title)
+	}
+	details := fmt.Sprintf("%s\n\nStatus: %s\nAffected components: %s\nStarted: %s\n",
+		incident.Title, status, strings.Join(names, ", "), incident.StartedAt.UTC().Format(time.RFC1123))
+	if incident.ResolvedAt != nil {
+		details += fmt.Sprintf("Resolved: %s\n", incident.ResolvedAt.UTC().Format(time.RFC1123))
+	}
+	details += fmt.Sprintf("\nCurrent status: %s\n", ss.statusURL(org.Slug))
+
+	// One message per subscriber and change; the transaction already guarantees a change
+	// is only queued once, so the key just has to be unique
+	changeID := uuid.New()
+	for _, subscriber := range subscribers {
+		body := details + fmt.Sprintf("Unsubscribe: %s\n", ss.subscriptionURL(org.Slug, "unsubscribe", subscriber.Token))
+		key := fmt.Sprintf("status-update:%s:%s", changeID, subscriber.ID)
+		if err := ss.outbox.EnqueueEmail(tx, key, subscriber.Email, subject, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadStatusPage returns the organization's status page, or an unsaved default one
+func (ss *StatusPageService) loadStatusPage(orgID uuid.UUID) (*models.StatusPage, *models.Organization, error) {
+	var org models.Organization
+	if err := ss.db.First(&org, orgID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrStatusPageNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to retrieve organization: %w", err)
+	}
+
+	var page models.StatusPage
+	err := ss.db.Where("organization_id = ?", orgID).First(&page).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.StatusPage{OrganizationID: orgID, Title: org.Name}, &org, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve status page: %w", err)
+	}
+	return &page, &org, nil
+}
+
+// findEnabledStatusPage looks up a public status page by organization slug
+func (ss *StatusPageService) findEnabledStatusPage(orgSlug string) (*models.Organization, *models.StatusPage, error) {
+	var org models.Organization
+	if err := ss.db.Where("slug = ?", orgSlug).First(&org).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrStatusPageNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to retrieve organization: %w", err)
+	}
+
+	var page models.StatusPage
+	if err := ss.db.Where("organization_id = ? AND enabled = ?", org.ID, true).First(&page).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrStatusPageNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to retrieve status page: %w", err)
+	}
+	return &org, &page, nil
+}
+
+func (ss *StatusPageService) findSubscriberByToken(orgSlug, token string) (*models.StatusSubscriber, error) {
+	if token == "" {
+		return nil, ErrStatusSubscriberNotFound
+	}
+
+	var subscriber models.StatusSubscriber
+	if err := ss.db.Joins("JOIN organizations ON organizations.id = status_subscribers.organization_id").
+		Where("organizations.slug = ? AND status_subscribers.token = ?", orgSlug, token).
+		First(&subscriber).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStatusSubscriberNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve subscription: %w", err)
+	}
+	return &subscriber, nil
+}
+
+func (ss *StatusPageService) loadComponents(db *gorm.DB, orgID uuid.UUID) ([]models.StatusComponent, error) {
+	var components []models.StatusComponent
+	if err := db.Preload("Projects").
+		Where("organization_id = ?", orgID).
+		Order("position ASC, name ASC").
+		Find(&components).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve status components: %w", err)
+	}
+	return components, nil
+}
+
+func (ss *StatusPageService) getComponent(orgID, componentID uuid.UUID) (*dto.StatusComponentResponse, error) {
+	components, err := ss.ListComponents(orgID)
+	if err != nil {
+		return nil, err
+	}
+	for _, component := range components {
+		if component.ID == componentID {
+			return &component, nil
+		}
+	}
+	return nil, ErrStatusComponentNotFound
+}
+
+// setComponentProjects maps projects of the component's organization to it. A project
+// mapped to another component moves to this one.
+func (ss *StatusPageService) setComponentProjects(tx *gorm.DB, component *models.StatusComponent, projectIDs []uuid.UUID) error {
+	if len(projectIDs) == 0 {
+		return nil
+	}
+
+	var validIDs []uuid.UUID
+	if err := tx.Model(&models.Project{}).
+		Where("id IN ? AND organization_id = ?", projectIDs, component.OrganizationID).
+		Pluck("id", &validIDs).Error; err != nil {
+		return fmt.Errorf("failed to verify projects: %w", err)
+	}
+	valid := make(map[uuid.UUID]bool, len(validIDs))
+	for _, id := range validIDs {
+		valid[id] = true
+	}
+	for _, id := range projectIDs {
+		if !valid[id] {
+			return fmt.Errorf("%w: %s", ErrStatusProjectNotFound, id)
+		}
+	}
+
+	if err := tx.Where("project_id IN ?", validIDs).Delete(&models.StatusComponentProject{}).Error; err != nil {
+		return fmt.Errorf("failed to clear project mappings: %w", err)
+	}
+	for _, id := range validIDs {
+		mapping := models.StatusComponentProject{ComponentID: component.ID, ProjectID: id}
+		if err := tx.Create(&mapping).Error; err != nil {
+			return fmt.Errorf("failed to map project to component: %w", err)
+		}
+	}
+	return nil
+}
+
+// statusIncidentState holds the incidents shown on a status page and the components they affect
+type statusIncidentState struct {
+	components map[uuid.UUID]models.StatusComponent
+	incidents  []models.Incident
+	affected   map[uuid.UUID][]uuid.UUID // incident ID -> component IDs
+}
+
+// loadIncidentState loads the unresolved and recently resolved incidents of an organization
+// that affect at least one of the components
+func (ss *StatusPageService) loadIncidentState(orgID uuid.UUID, components []models.StatusComponent) (*statusIncidentState, error) {
+	var incidents []models.Incident
+	if err := ss.db.Where("organization_id = ? AND (status <> ? OR resolved_at >= ?)",
+		orgID, models.IncidentResolved, time.Now().Add(-statusPageResolvedRetention)).
+		Order("started_at DESC").
+		Find(&incidents).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve incidents: %w", err)
+	}
+
+	incidentIDs := make([]uuid.UUID, len(incidents))
+	for i, incident := range incidents {
+		incidentIDs[i] = incident.ID
+	}
+	state, err := ss.affectedComponents(ss.db.DB, incidentIDs, components)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, incident := range incidents {
+		if len(state.affected[incident.ID]) > 0 {
+			state.incidents = append(state.incidents, incident)
+		}
+	}
+	return state, nil
+}
+
+// affectedComponents resolves the components each incident affects through the projects of its issues
+func (ss *StatusPageService) affectedComponents(db *gorm.DB, incidentIDs []uuid.UUID, components []models.StatusComponent) (*statusIncidentState, error) {
+	state := &statusIncidentState{
+		components: make(map[uuid.UUID]models.StatusComponent, len(components)),
+		affected:   make(map[uuid.UUID][]uuid.UUID),
+	}
+	projectComponents := make(map[uuid.UUID]uuid.UUID)
+	for _, component := range components {
+		state.components[component.ID] = component
+		for _, mapping := range component.Projects {
+			projectComponents[mapping.ProjectID] = component.ID
+		}
+	}
+	if len(incidentIDs) == 0 || len(projectComponents) == 0 {
+		return state, nil
+	}
+
+	var rows []struct {
+		IncidentID uuid.UUID
+		ProjectID  uuid.UUID
+	}
+	if err := db.Model(&models.IncidentIssue{}).
+		Select("DISTINCT incident_issues.incident_id, issues.project_id").
+		Joins("JOIN issues ON issues.id = incident_issues.issue_id").
+		Where("incident_issues.incident_id IN ?", incidentIDs).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve incident projects: %w", err)
+	}
+
+	seen := make(map[[2]uuid.UUID]bool)
+	for _, row := range rows {
+		componentID, ok := projectComponents[row.ProjectID]
+		if !ok || seen[[2]uuid.UUID{row.IncidentID, componentID}] {
+			continue
+		}
+		seen[[2]uuid.UUID{row.IncidentID, componentID}] = true
+		state.affected[row.IncidentID] = append(state.affected[row.IncidentID], componentID)
+	}
+	return state, nil
+}
+
+// componentStatus is the worst status among the unresolved incidents affecting the component
+func (s *statusIncidentState) componentStatus(componentID uuid.UUID) string {
+	status := ComponentOperational
+	for _, incident := range s.incidents {
+		incidentStatus := componentStatusForIncident(incident.Status)
+		if componentStatusRank[incidentStatus] <= componentStatusRank[status] {
+			continue
+		}
+		for _, id := range s.affected[incident.ID] {
+			if id == componentID {
+				status = incidentStatus
+				break
+			}
+		}
+	}
+	return status
+}
+
+func (s *statusIncidentState) componentNames(incidentID uuid.UUID) []string {
+	names := []string{}
+	for _, id := range s.affected[incidentID] {
+		names = append(names, s.components[id].Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func componentStatusForIncident(status models.IncidentStatus) string {
+	switch status {
+	case models.IncidentOpen, models.IncidentInvestigating:
+		return ComponentOutage
+	case models.IncidentMitigated:
+		return ComponentDegraded
+	default:
+		return ComponentOperational
+	}
+}
+
+func incidentStatusLabel(status models.IncidentStatus) string {
+	label := string(status)
+	if label == "" {
+		return label
+	}
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+func (ss *StatusPageService) statusURL(orgSlug string) string {
+	return fmt.Sprintf("%s/api/v1/status/%s", ss.publicURL, url.PathEscape(orgSlug))
+}
+
+func (ss *StatusPageService) subscriptionURL(orgSlug, action, token string) string {
+	return fmt.Sprintf("%s/%s?token=%s", ss.statusURL(orgSlug), action, url.QueryEscape(token))
+}
+
+func convertStatusComponentToResponse(component models.StatusComponent, status string) dto.StatusComponentResponse {
+	projectIDs := make([]uuid.UUID, len(component.Projects))
+	for i, mapping := range component.Projects {
+		projectIDs[i] = mapping.ProjectID
+	}
+	return dto.StatusComponentResponse{
+		ID:          component.ID,
+		Name:        component.Name,
+		Description: component.Description,
+		Position:    component.Position,
+		ProjectIDs:  projectIDs,
+		Status:      status,
+		CreatedAt:   component.CreatedAt,
+		UpdatedAt:   component.UpdatedAt,
+	}
+}
+
+// generateToken returns a random 64 character hex token, for links and signing secrets
+func generateToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// maxIndexedTagKeyLength bounds the keys of indexed tags; longer keys are not indexed
+	maxIndexedTagKeyLength = 200
+	// maxIndexedTagValueLength bounds the values of indexed tags, which are cut to it
+	maxIndexedTagValueLength = 200
+)
+
+// issueTagValueKey is a tag value of an issue
+type issueTagValueKey struct {
+	issueID uuid.UUID
+	key     string
+	value   string
+}
+
+// indexedTagValue cuts a tag value to the length of the values of the tag index
+func indexedTagValue(value string) string {
+	if utf8.RuneCountInString(value) <= maxIndexedTagValueLength {
+		return value
+	}
+	return string([]rune(value)[:maxIndexedTagValueLength])
+}
+
+// indexEventTags copies the tags of stored events to the event tags and counts them in the
+// tag values of their issues
+func indexEventTags(tx *gorm.DB, events []models.Event) error {
+	var tags []models.EventTag
+	values := make(map[issueTagValueKey]*models.IssueTagValue)
+	for i := range events {
+		event := &events[i]
+		if len(event.Tags) == 0 {
+			continue
+		}
+		var eventTags map[string]string
+		if err := json.Unmarshal(event.Tags, &eventTags); err != nil {
+			continue
+		}
+		for key, value := range eventTags {
+			if key == "" || value == "" || len(key) > maxIndexedTagKeyLength {
+				continue
+			}
+			value = indexedTagValue(value)
+			tags = append(tags, models.EventTag{
+				EventID:   event.ID,
+				ProjectID: event.ProjectID,
+				IssueID:   event.IssueID,
+				Key:       key,
+				Value:     value,
+				Timestamp: event.Timestamp,
+			})
+
+			valueKey := issueTagValueKey{issueID: event.IssueID, key: key, value: value}
+			row, ok := values[valueKey]
+			if !ok {
+				row = &models.IssueTagValue{
+					ProjectID: event.ProjectID,
+					IssueID:   event.IssueID,
+					Key:       key,
+					Value:     value,
+					FirstSeen: event.Timestamp,
+					LastSeen:  event.Timestamp,
+				}
+				values[valueKey] = row
+			}
+			row.TimesSeen++
+			if event.Timestamp.Before(row.FirstSeen) {
+				row.FirstSeen = event.Timestamp
+			}
+			if event.Timestamp.After(row.LastSeen) {
+				row.LastSeen = event.Timestamp
+			}
+		}
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	if err := tx.CreateInBatches(tags, 500).Error; err != nil {
+		return fmt.Errorf("failed to index event tags: %w", err)
+	}
+
+	// Upserting in a fixed order keeps concurrent batches from deadlocking on shared values
+	rows := make([]*models.IssueTagValue, 0, len(values))
+	for _, row := range values {
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.IssueID != b.IssueID {
+			return a.IssueID.String() < b.IssueID.String()
+		}
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		return a.Value < b.Value
+	})
+	for _, row := range rows {
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "issue_id"}, {Name: "key"}, {Name: "value"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"times_seen": clause.Expr{SQL: "issue_tag_values.times_seen + excluded.times_seen"},
+				"first_seen": clause.Expr{SQL: "CASE WHEN excluded.first_seen < issue_tag_values.first_seen THEN excluded.first_seen ELSE issue_tag_values.first_seen END"},
+				"last_seen":  clause.Expr{SQL: "CASE WHEN excluded.last_seen > issue_tag_values.last_seen THEN excluded.last_seen ELSE issue_tag_values.last_seen END"},
+				"updated_at": time.Now(),
+			}),
+		}).Create(row).Error
+		if err != nil {
+			return fmt.Errorf("failed to count issue tag values: %w", err)
+		}
+	}
+	return nil
+}
+
+// reindexIssueTags moves the event tags of issues whose events moved to other issues along
+// with their events, then rebuilds the tag values of the issues from their event tags.
+// issueIDs must cover the issues events left and those they joined.
+func reindexIssueTags(tx *gorm.DB, issueIDs []uuid.UUID) error {
+	if len(issueIDs) == 0 {
+		return nil
+	}
+	if err := tx.Model(&models.EventTag{}).Where("issue_id IN ?", issueIDs).
+		Update("issue_id", tx.Model(&models.Event{}).Select("events.issue_id").Where("events.id = event_tags.event_id")).Error; err != nil {
+		return fmt.Errorf("failed to move event tags: %w", err)
+	}
+
+	if err := tx.Where("issue_id IN ?", issueIDs).Delete(&models.IssueTagValue{}).Error; err != nil {
+		return fmt.Errorf("failed to reset issue tag values: %w", err)
+	}
+	if err := tx.Exec("INSERT INTO issue_tag_values (id, project_id, issue_id, key, value, times_seen, first_seen, last_seen, created_at, updated_at) "+
+		"SELECT gen_random_uuid(), project_id, issue_id, key, value, COUNT(*), MIN(timestamp), MAX(timestamp), now(), now() "+
+		"FROM event_tags WHERE issue_id IN ? GROUP BY project_id, issue_id, key, value", issueIDs).Error; err != nil {
+		return fmt.Errorf("failed to rebuild issue tag values: %w", err)
+	}
+	return nil
+}
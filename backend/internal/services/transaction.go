@@ -0,0 +1,318 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"minisentry/internal/database"
+	"minisentry/internal/dto"
+	"minisentry/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidTransactionData = errors.New("invalid transaction data")
+	ErrTransactionNotFound    = errors.New("transaction not found")
+)
+
+// unlabeledTransaction names transactions that arrive without a name, like Sentry does
+const unlabeledTransaction = "<unlabeled transaction>"
+
+type TransactionService struct {
+	db           *database.DB
+	releases     *releaseRecorder
+	environments *environmentRecorder
+}
+
+// NewTransactionService creates a new performance transaction service
+func NewTransactionService(db *database.DB) *TransactionService {
+	return &TransactionService{db: db, releases: newReleaseRecorder(db), environments: newEnvironmentRecorder(db)}
+}
+
+// ProcessTransaction validates a transaction event and stores it together with its spans
+func (ts *TransactionService) ProcessTransaction(projectID uuid.UUID, data *dto.TransactionEventRequest) (*dto.TransactionIngestResponse, error) {
+	if err := ts.ValidateTransactionPayload(data); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	transaction, spans, err := ts.buildTransaction(projectID, data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ts.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.Transaction{}).
+			Where("project_id = ? AND event_id = ?", projectID, transaction.EventID).
+			Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check transaction: %w", err)
+		}
+		if count > 0 {
+			return ErrEventExists
+		}
+
+		if err := tx.Create(transaction).Error; err != nil {
+			return fmt.Errorf("failed to create transaction: %w", err)
+		}
+
+		if len(spans) == 0 {
+			return nil
+		}
+		for i := range spans {
+			spans[i].TransactionID = transaction.ID
+		}
+		if err := tx.Create(&spans).Error; err != nil {
+			return fmt.Errorf("failed to create spans: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	ts.releases.record(projectID, data.Release)
+	ts.environments.record(projectID, transaction.Environment)
+
+	return &dto.TransactionIngestResponse{
+		ID:        transaction.ID,
+		EventID:   transaction.EventID,
+		ProjectID: transaction.ProjectID,
+		CreatedAt: transaction.CreatedAt,
+	}, nil
+}
+
+// ValidateTransactionPayload validates the incoming transaction payload
+func (ts *TransactionService) ValidateTransactionPayload(data *dto.TransactionEventRequest) error {
+	if data == nil {
+		return fmt.Errorf("%w: transaction data is nil", ErrInvalidTransactionData)
+	}
+	if data.StartTimestamp == nil || data.Timestamp == nil {
+		return fmt.Errorf("%w: start_timestamp and timestamp are required", ErrInvalidTransactionData)
+	}
+	if data.Timestamp.Before(*data.StartTimestamp) {
+		return fmt.Errorf("%w: timestamp is before start_timestamp", ErrInvalidTransactionData)
+	}
+	if data.Contexts.Trace == nil || data.Contexts.Trace.TraceID == "" {
+		return fmt.Errorf("%w: contexts.trace.trace_id is required", ErrInvalidTransactionData)
+	}
+	return nil
+}
+
+// buildTransaction converts the payload into the transaction and span models
+func (ts *TransactionService) buildTransaction(projectID uuid.UUID, data *dto.TransactionEventRequest) (*models.Transaction, []models.Span, error) {
+	trace := data.Contexts.Trace
+
+	transaction := &models.Transaction{
+		ProjectID:      projectID,
+		Name:           unlabeledTransaction,
+		Op:             trace.Op,
+		Status:         trace.Status,
+		TraceID:        trace.TraceID,
+		SpanID:         trace.SpanID,
+		StartTimestamp: *data.StartTimestamp,
+		Timestamp:      *data.Timestamp,
+		DurationMs:     durationMs(*data.StartTimestamp, *data.Timestamp),
+		Environment:    "production",
+		ReleaseVersion: data.Release,
+		Platform:       data.Platform,
+	}
+
+	if data.EventID != nil && *data.EventID != "" {
+		transaction.EventID = *data.EventID
+	} else {
+		transaction.EventID = strings.ReplaceAll(uuid.New().String(), "-", "")
+	}
+	if data.Transaction != nil && *data.Transaction != "" {
+		transaction.Name = *data.Transaction
+	}
+	if data.Environment != nil && *data.Environment != "" {
+		transaction.Environment = *data.Environment
+	}
+
+	var err error
+	if transaction.Tags, err = marshalJSONField(data.Tags); err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal tags: %w", err)
+	}
+	if transaction.Measurements, err = marshalJSONField(data.Measurements); err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal measurements: %w", err)
+	}
+
+	spans := make([]models.Span, 0, len(data.Spans))
+	for _, spanData := range data.Spans {
+		if spanData.SpanID == "" || spanData.StartTimestamp == nil {
+			continue
+		}
+
+		// Spans that never finished are recorded as ending with the transaction
+		end := *data.Timestamp
+		if spanData.Timestamp != nil {
+			end = *spanData.Timestamp
+		}
+
+		span := models.Span{
+			ProjectID:      projectID,
+			SpanID:         spanData.SpanID,
+			ParentSpanID:   spanData.ParentSpanID,
+			Op:             spanData.Op,
+			Description:    spanData.Description,
+			Status:         spanData.Status,
+			StartTimestamp: *spanData.StartTimestamp,
+			Timestamp:      end,
+			DurationMs:     durationMs(*spanData.StartTimestamp, end),
+		}
+		if span.Tags, err = marshalJSONField(spanData.Tags); err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal span tags: %w", err)
+		}
+		if span.Data, err = marshalJSONField(spanData.Data); err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal span data: %w", err)
+		}
+		spans = append(spans, span)
+	}
+
+	return transaction, spans, nil
+}
+
+// ListTransactions returns a page of transactions, newest first
+func (ts *TransactionService) ListTransactions(projectID uuid.UUID, filters dto.TransactionFilters) (*dto.TransactionListResponse, error) {
+	query := ts.applyTransactionFilters(ts.db.Model(&models.Transaction{}).Where("project_id = ?", projectID), filters)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count transactions: %w", err)
+	}
+
+	page, limit := filters.Page, filters.Limit
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 25
+	}
+
+	var transactions []models.Transaction
+	if err := query.Order("timestamp DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to retrieve transactions: %w", err)
+	}
+
+	responses := make([]dto.TransactionResponse, len(transactions))
+	for i, transaction := range transactions {
+		responses[i] = convertTransactionToResponse(transaction)
+	}
+
+	return &dto.TransactionListResponse{
+		Transactions: responses,
+		Total:        total,
+		Page:         page,
+		Limit:        limit,
+		TotalPages:   dto.CalculateTotalPages(total, limit),
+	}, nil
+}
+
+// GetTransaction returns a transaction of the project together with its spans
+func (ts *TransactionService) GetTransaction(projectID, transactionID uuid.UUID) (*dto.TransactionDetailResponse, error) {
+	var transaction models.Transaction
+	err := ts.db.Preload("Spans", func(db *gorm.DB) *gorm.DB {
+		return db.Order("start_timestamp ASC")
+	}).Where("id = ? AND project_id = ?", transactionID, projectID).First(&transaction).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	response := &dto.TransactionDetailResponse{
+		TransactionResponse: convertTransactionToResponse(transaction),
+		Spans:               make([]dto.SpanResponse, len(transaction.Spans)),
+	}
+	for i, span := range transaction.Spans {
+		response.Spans[i] = dto.SpanResponse{
+			SpanID:         span.SpanID,
+			ParentSpanID:   span.ParentSpanID,
+			Op:             span.Op,
+			Description:    span.Description,
+			Status:         span.Status,
+			StartTimestamp: span.StartTimestamp,
+			Timestamp:      span.Timestamp,
+			DurationMs:     span.DurationMs,
+			Tags:           span.Tags,
+			Data:           span.Data,
+		}
+	}
+
+	return response, nil
+}
+
+// GetTransactionSummary aggregates durations per transaction name and op
+func (ts *TransactionService) GetTransactionSummary(projectID uuid.UUID, filters dto.TransactionFilters) ([]dto.TransactionSummaryResponse, error) {
+	query := ts.applyTransactionFilters(ts.db.Model(&models.Transaction{}).Where("project_id = ?", projectID), filters)
+
+	var results []dto.TransactionSummaryResponse
+	if err := query.Select(`name, op,
+			COUNT(*) AS count,
+			AVG(duration_ms) AS avg_duration_ms,
+			MIN(duration_ms) AS min_duration_ms,
+			MAX(duration_ms) AS max_duration_ms`).
+		Group("name, op").
+		Order("count DESC").
+		Scan(&results).Error; err != nil {
+		return nil, fmt.Errorf("failed to summarize transactions: %w", err)
+	}
+
+	return results, nil
+}
+
+func (ts *TransactionService) applyTransactionFilters(query *gorm.DB, filters dto.TransactionFilters) *gorm.DB {
+	if filters.Name != nil && *filters.Name != "" {
+		query = query.Where("name = ?", *filters.Name)
+	}
+	if filters.Op != nil && *filters.Op != "" {
+		query = query.Where("op = ?", *filters.Op)
+	}
+	if filters.Environment != nil && *filters.Environment != "" {
+		query = query.Where("environment = ?", *filters.Environment)
+	}
+	return query
+}
+
+func convertTransactionToResponse(transaction models.Transaction) dto.TransactionResponse {
+	return dto.TransactionResponse{
+		ID:             transaction.ID,
+		EventID:        transaction.EventID,
+		Name:           transaction.Name,
+		Op:             transaction.Op,
+		Status:         transaction.Status,
+		TraceID:        transaction.TraceID,
+		StartTimestamp: transaction.StartTimestamp,
+		Timestamp:      transaction.Timestamp,
+		DurationMs:     transaction.DurationMs,
+		Environment:    transaction.Environment,
+		Release:        transaction.ReleaseVersion,
+		Tags:           transaction.Tags,
+		Measurements:   transaction.Measurements,
+	}
+}
+
+func durationMs(start, end time.Time) float64 {
+	return float64(end.Sub(start)) / float64(time.Millisecond)
+}
+
+// marshalJSONField encodes an optional value for a JSONB column, leaving it NULL when empty
+func marshalJSONField(value interface{}) (datatypes.JSON, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if string(encoded) == "null" {
+		return nil, nil
+	}
+	return datatypes.JSON(encoded), nil
+}
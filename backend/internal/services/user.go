@@ -233,6 +233,26 @@ func (s *UserService) DeactivateUser(userID uuid.UUID) error {
 	return nil
 }
 
+// GrantSuperuser makes the users with the given emails superusers, for bootstrapping
+// administrators from configuration. Emails without an account are skipped.
+func (s *UserService) GrantSuperuser(emails []string) error {
+	if len(emails) == 0 {
+		return nil
+	}
+
+	normalized := make([]string, len(emails))
+	for i, email := range emails {
+		normalized[i] = strings.ToLower(strings.TrimSpace(email))
+	}
+
+	if err := s.db.Model(&models.User{}).Where("LOWER(email) IN ? AND is_superuser = ?", normalized, false).
+		Update("is_superuser", true).Error; err != nil {
+		return fmt.Errorf("failed to grant superuser: %w", err)
+	}
+
+	return nil
+}
+
 // validateRegistrationRequest validates user registration input
 func (s *UserService) validateRegistrationRequest(req *dto.RegisterRequest) error {
 	if req.Email == "" {
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+
+	"minisentry/internal/dto"
+)
+
+var (
+	// userAgentBrowsers identify browsers by their user agent, in the order they are tried:
+	// browsers built on Chrome or Safari name those too, so they come first
+	userAgentBrowsers = []struct {
+		name    string
+		pattern *regexp.Regexp
+	}{
+		{"Edge", regexp.MustCompile(`(?:Edg|Edge|EdgA|EdgiOS)/(\d+)`)},
+		{"Opera", regexp.MustCompile(`(?:OPR|Opera)/(\d+)`)},
+		{"Samsung Internet", regexp.MustCompile(`SamsungBrowser/(\d+)`)},
+		{"Yandex Browser", regexp.MustCompile(`YaBrowser/(\d+)`)},
+		{"Firefox", regexp.MustCompile(`(?:Firefox|FxiOS)/(\d+)`)},
+		{"Chrome", regexp.MustCompile(`(?:Chrome|CriOS)/(\d+)`)},
+		{"Safari", regexp.MustCompile(`Version/(\d+)[.\d]* (?:Mobile/\w+ )?Safari/`)},
+		{"Internet Explorer", regexp.MustCompile(`(?:MSIE |Trident/.*rv:)(\d+)`)},
+	}
+
+	// userAgentSystems identify operating systems by their user agent, in the order they
+	// are tried; versions use dots even when the user agent has underscores
+	userAgentSystems = []struct {
+		name    string
+		pattern *regexp.Regexp
+	}{
+		{"Windows", regexp.MustCompile(`Windows NT (\d+\.\d+)`)},
+		{"iOS", regexp.MustCompile(`(?:iPhone|iPad|iPod).*? OS (\d+(?:_\d+)?)`)},
+		{"Mac OS X", regexp.MustCompile(`Mac OS X (\d+(?:[_.]\d+)?)`)},
+		{"Android", regexp.MustCompile(`Android (\d+(?:\.\d+)?)`)},
+		{"Chrome OS", regexp.MustCompile(`CrOS \w+ (\d+)`)},
+		{"Linux", regexp.MustCompile(`Linux()`)},
+	}
+
+	// windowsVersions maps Windows NT versions to the Windows releases they shipped with
+	windowsVersions = map[string]string{
+		"10.0": "10",
+		"6.3":  "8.1",
+		"6.2":  "8",
+		"6.1":  "7",
+		"6.0":  "Vista",
+		"5.1":  "XP",
+	}
+
+	// androidDevice captures the device model Android browsers add after the Android version
+	androidDevice = regexp.MustCompile(`Android [\d.]+; (?:[a-z]{2}[-_][a-z]{2}; )?([^;)]+?)(?: Build/[^;)]*)?\)`)
+)
+
+// userAgentTags derives the browser, browser.name, browser.version, os, os.name and device
+// tags from the user agent of a web browser; other user agents, such as those of server
+// SDKs, give none
+func userAgentTags(userAgent string) map[string]string {
+	if !strings.HasPrefix(userAgent, "Mozilla/") {
+		return nil
+	}
+
+	tags := make(map[string]string)
+	for _, browser := range userAgentBrowsers {
+		if match := browser.pattern.FindStringSubmatch(userAgent); match != nil {
+			tags["browser"] = browser.name + " " + match[1]
+			tags["browser.name"] = browser.name
+			tags["browser.version"] = match[1]
+			break
+		}
+	}
+
+	for _, system := range userAgentSystems {
+		match := system.pattern.FindStringSubmatch(userAgent)
+		if match == nil {
+			continue
+		}
+		version := strings.ReplaceAll(match[1], "_", ".")
+		if system.name == "Windows" {
+			if release, ok := windowsVersions[version]; ok {
+				version = release
+			}
+		}
+		tags["os.name"] = system.name
+		tags["os"] = strings.TrimSpace(system.name + " " + version)
+		break
+	}
+
+	switch {
+	case strings.Contains(userAgent, "iPhone"):
+		tags["device"] = "iPhone"
+	case strings.Contains(userAgent, "iPad"):
+		tags["device"] = "iPad"
+	case strings.Contains(userAgent, "iPod"):
+		tags["device"] = "iPod"
+	default:
+		if match := androidDevice.FindStringSubmatch(userAgent); match != nil && match[1] != "K" {
+			// Recent Chrome reduces the model to "K"
+			tags["device"] = strings.TrimSpace(match[1])
+		}
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
+// addUserAgentTags tags an event with the browser, OS and device of the user agent in its
+// request headers, or else the one that sent it, keeping the tags it already has
+func addUserAgentTags(normalized *dto.NormalizedErrorData, userAgent string) {
+	if eventUserAgent := requestHeader(normalized.RequestData, "User-Agent"); eventUserAgent != "" {
+		userAgent = eventUserAgent
+	}
+	for key, value := range userAgentTags(userAgent) {
+		if _, ok := normalized.Tags[key]; !ok {
+			normalized.Tags[key] = value
+		}
+	}
+}
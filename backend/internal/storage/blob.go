@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore persists binary payloads such as event attachments under opaque keys
+type BlobStore interface {
+	// Put writes the blob and returns the number of bytes stored
+	Put(key string, data io.Reader) (int64, error)
+	// Get returns ErrBlobNotFound when no blob is stored under the key
+	Get(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+// LocalBlobStore stores blobs as files below a root directory
+type LocalBlobStore struct {
+	root string
+}
+
+// NewLocalBlobStore creates a blob store writing to the given directory
+func NewLocalBlobStore(root string) *LocalBlobStore {
+	return &LocalBlobStore{root: root}
+}
+
+func (s *LocalBlobStore) Put(key string, data io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	// Write to a temporary file first so readers never see a partial blob
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create blob: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	size, err := io.Copy(tmp, data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to write blob: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, fmt.Errorf("failed to store blob: %w", err)
+	}
+	return size, nil
+}
+
+func (s *LocalBlobStore) Get(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrBlobNotFound
+		}
+		return nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+	return file, nil
+}
+
+func (s *LocalBlobStore) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
+
+// path maps a key to a file below the root, rejecting keys that would escape it
+func (s *LocalBlobStore) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if cleaned == "/" || strings.Contains(key, "..") {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.root, cleaned), nil
+}
+
+// Open creates the blob store for the configured backend: "local" (default) or "s3"
+func Open(backend, localPath string, s3Config S3Config) (BlobStore, error) {
+	switch backend {
+	case "", "local":
+		return NewLocalBlobStore(localPath), nil
+	case "s3":
+		return NewS3BlobStore(s3Config)
+	default:
+		return nil, fmt.Errorf("unsupported attachment storage backend %q", backend)
+	}
+}
@@ -0,0 +1,22 @@
+-- Release health sessions reported by SDKs
+CREATE TABLE sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    session_id VARCHAR(255), -- SDK session id (sid); NULL for aggregated buckets
+    distinct_id VARCHAR(255), -- User identifier (did), if available
+    release VARCHAR(100) NOT NULL,
+    environment VARCHAR(100) DEFAULT 'production',
+    status VARCHAR(50) NOT NULL, -- ok, exited, errored, crashed, abnormal
+    errors INTEGER DEFAULT 0,
+    sequence BIGINT DEFAULT 0,
+    duration DOUBLE PRECISION,
+    quantity INTEGER DEFAULT 1, -- Number of sessions this row represents
+    started TIMESTAMP WITH TIME ZONE,
+    timestamp TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_sessions_project_session_id ON sessions(project_id, session_id) WHERE session_id IS NOT NULL;
+CREATE INDEX idx_sessions_project_release_env ON sessions(project_id, release, environment);
+CREATE INDEX idx_sessions_started ON sessions(started DESC);
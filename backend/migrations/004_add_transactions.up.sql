@@ -0,0 +1,46 @@
+-- Performance transactions
+CREATE TABLE transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    event_id VARCHAR(255) NOT NULL, -- Unique identifier from SDK
+    name VARCHAR(500) NOT NULL, -- Transaction name, e.g. route or task name
+    op VARCHAR(255), -- Operation, e.g. http.server, pageload
+    status VARCHAR(100), -- Trace status, e.g. ok, internal_error
+    trace_id VARCHAR(64) NOT NULL,
+    span_id VARCHAR(32),
+    start_timestamp TIMESTAMP WITH TIME ZONE,
+    timestamp TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    duration_ms DOUBLE PRECISION,
+    environment VARCHAR(100) DEFAULT 'production',
+    release_version VARCHAR(100),
+    platform VARCHAR(50),
+    tags JSONB,
+    measurements JSONB, -- Web vitals and other measurements
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(project_id, event_id)
+);
+
+-- Spans belonging to a transaction
+CREATE TABLE spans (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    span_id VARCHAR(32) NOT NULL,
+    parent_span_id VARCHAR(32),
+    op VARCHAR(255),
+    description TEXT,
+    status VARCHAR(100),
+    start_timestamp TIMESTAMP WITH TIME ZONE,
+    timestamp TIMESTAMP WITH TIME ZONE,
+    duration_ms DOUBLE PRECISION,
+    tags JSONB,
+    data JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_transactions_project_timestamp ON transactions(project_id, timestamp DESC);
+CREATE INDEX idx_transactions_project_name ON transactions(project_id, name);
+CREATE INDEX idx_transactions_trace_id ON transactions(trace_id);
+CREATE INDEX idx_spans_transaction_id ON spans(transaction_id);
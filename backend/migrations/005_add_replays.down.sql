@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_events_replay_id;
+ALTER TABLE events DROP COLUMN IF EXISTS replay_id;
+DROP TABLE IF EXISTS replay_segments;
+DROP TABLE IF EXISTS replays;
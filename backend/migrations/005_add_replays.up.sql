@@ -0,0 +1,36 @@
+-- Session replay metadata; recordings are not stored
+CREATE TABLE replays (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    replay_id VARCHAR(64) NOT NULL, -- Replay identifier from SDK
+    replay_type VARCHAR(50), -- session or buffer
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    segment_count INTEGER DEFAULT 0,
+    environment VARCHAR(100) DEFAULT 'production',
+    release_version VARCHAR(100),
+    platform VARCHAR(50),
+    urls JSONB, -- Pages visited during the replay
+    trace_ids JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(project_id, replay_id)
+);
+
+-- Segments uploaded for a replay
+CREATE TABLE replay_segments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    replay_id UUID NOT NULL REFERENCES replays(id) ON DELETE CASCADE,
+    segment_id INTEGER NOT NULL,
+    timestamp TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    recording_size INTEGER DEFAULT 0, -- Size of the uploaded recording in bytes
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(replay_id, segment_id)
+);
+
+-- Link events to the replay that captured them
+ALTER TABLE events ADD COLUMN replay_id VARCHAR(64);
+
+CREATE INDEX idx_replays_project_started ON replays(project_id, started_at DESC);
+CREATE INDEX idx_events_replay_id ON events(project_id, replay_id) WHERE replay_id IS NOT NULL;
@@ -0,0 +1,12 @@
+-- Emoji reactions on issue comments
+CREATE TABLE issue_comment_reactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    comment_id UUID NOT NULL REFERENCES issue_comments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    emoji VARCHAR(64) NOT NULL, -- Unicode emoji or :shortcode:
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(comment_id, user_id, emoji)
+);
+
+CREATE INDEX idx_issue_comment_reactions_comment_id ON issue_comment_reactions(comment_id);
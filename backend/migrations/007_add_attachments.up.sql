@@ -0,0 +1,16 @@
+-- Files uploaded with events (minidumps, screenshots, logs); content is kept in blob storage
+CREATE TABLE attachments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255),
+    attachment_type VARCHAR(50) NOT NULL DEFAULT 'event.attachment', -- event.attachment, event.minidump
+    size BIGINT,
+    storage_key VARCHAR(500) NOT NULL, -- Key of the content in blob storage
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_attachments_event_id ON attachments(event_id);
+CREATE INDEX idx_attachments_project_id ON attachments(project_id);
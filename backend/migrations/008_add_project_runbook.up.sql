@@ -0,0 +1,2 @@
+-- Markdown runbook shown to on-call responders next to the project's issues
+ALTER TABLE projects ADD COLUMN runbook TEXT;
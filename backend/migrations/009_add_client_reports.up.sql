@@ -0,0 +1,13 @@
+-- Events discarded by SDKs before sending (rate limits, sampling, network errors), per hour
+CREATE TABLE client_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    bucket TIMESTAMP WITH TIME ZONE NOT NULL, -- Start of the hour the events were discarded in
+    reason VARCHAR(100) NOT NULL, -- ratelimit_backoff, sample_rate, network_error, queue_overflow...
+    category VARCHAR(50) NOT NULL, -- error, transaction, session, attachment...
+    quantity BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_client_reports_bucket ON client_reports(project_id, bucket, reason, category);
@@ -0,0 +1,14 @@
+-- Typed links between issues of the same organization (duplicate_of, blocked_by, related)
+CREATE TABLE issue_relations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_issue_id UUID NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    target_issue_id UUID NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL, -- Reads as "source <type> target"
+    created_by_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(source_issue_id, target_issue_id, type),
+    CHECK (source_issue_id <> target_issue_id)
+);
+
+CREATE INDEX idx_issue_relations_target_issue_id ON issue_relations(target_issue_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS incident_activities;
+DROP TABLE IF EXISTS incident_issues;
+DROP TABLE IF EXISTS incidents;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS status_subscribers;
+DROP TABLE IF EXISTS status_component_projects;
+DROP TABLE IF EXISTS status_components;
+DROP TABLE IF EXISTS status_pages;
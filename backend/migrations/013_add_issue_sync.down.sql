@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS issue_sync_deltas;
+DROP TABLE IF EXISTS issue_sync_integrations;
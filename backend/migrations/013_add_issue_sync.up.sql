@@ -0,0 +1,31 @@
+-- Issue sync integrations stream issue create/update/resolve/assign deltas to external systems
+CREATE TABLE issue_sync_integrations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL UNIQUE REFERENCES projects(id) ON DELETE CASCADE,
+    url VARCHAR(2048), -- NULL for pull-only integrations
+    secret VARCHAR(64) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    last_sequence BIGINT NOT NULL DEFAULT 0,
+    delivered_sequence BIGINT NOT NULL DEFAULT 0,
+    failure_count INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    last_delivery_at TIMESTAMP WITH TIME ZONE,
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE issue_sync_deltas (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    sequence BIGINT NOT NULL,
+    issue_id UUID NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL, -- issue.created, issue.updated, issue.resolved, issue.assigned
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(project_id, sequence)
+);
+
+CREATE INDEX idx_issue_sync_deltas_issue_id ON issue_sync_deltas(issue_id);
+CREATE INDEX idx_issue_sync_deltas_created_at ON issue_sync_deltas(created_at);
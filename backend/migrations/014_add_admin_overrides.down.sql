@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS audit_log_entries;
+DROP TABLE IF EXISTS quota_overrides;
+ALTER TABLE users DROP COLUMN IF EXISTS is_superuser;
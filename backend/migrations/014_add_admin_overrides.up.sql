@@ -0,0 +1,38 @@
+-- Superusers administer the whole instance through the admin API
+ALTER TABLE users ADD COLUMN is_superuser BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Temporary per-project overrides of the ingestion rate limit and daily quota
+CREATE TABLE quota_overrides (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    kind VARCHAR(50) NOT NULL, -- rate_limit, daily_quota
+    value INTEGER NOT NULL, -- 0 lifts the limit
+    reason TEXT,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at TIMESTAMP WITH TIME ZONE, -- set when the override expires or is revoked
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_quota_overrides_project_id ON quota_overrides(project_id);
+CREATE INDEX idx_quota_overrides_active ON quota_overrides(expires_at) WHERE ended_at IS NULL;
+
+-- Audit log of administrative actions
+CREATE TABLE audit_log_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL, -- NULL for actions taken by the system
+    action VARCHAR(100) NOT NULL,
+    target_type VARCHAR(50) NOT NULL,
+    target_id UUID NOT NULL,
+    organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE,
+    project_id UUID REFERENCES projects(id) ON DELETE CASCADE,
+    data JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_entries_target ON audit_log_entries(target_type, target_id);
+CREATE INDEX idx_audit_log_entries_organization_id ON audit_log_entries(organization_id);
+CREATE INDEX idx_audit_log_entries_project_id ON audit_log_entries(project_id);
+CREATE INDEX idx_audit_log_entries_created_at ON audit_log_entries(created_at);
@@ -0,0 +1,19 @@
+-- Transactional outbox: external side effects (emails, webhooks) are written in the same
+-- transaction as the change that triggers them and delivered by a background drainer
+CREATE TABLE outbox_messages (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(50) NOT NULL, -- email, webhook
+    idempotency_key VARCHAR(255) NOT NULL UNIQUE, -- enqueueing the same key twice is a no-op
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, sent, failed
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMP WITH TIME ZONE, -- lease held by the drainer delivering the message
+    last_error TEXT,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_outbox_messages_pending ON outbox_messages(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_outbox_messages_created_at ON outbox_messages(created_at);
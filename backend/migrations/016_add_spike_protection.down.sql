@@ -0,0 +1 @@
+DROP TABLE IF EXISTS spike_protection_drops;
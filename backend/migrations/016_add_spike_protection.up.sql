@@ -0,0 +1,11 @@
+-- Events dropped by spike protection, aggregated per project and hour
+CREATE TABLE spike_protection_drops (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    bucket TIMESTAMP WITH TIME ZONE NOT NULL, -- start of the hour
+    dropped BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_spike_protection_drops_bucket ON spike_protection_drops(project_id, bucket);
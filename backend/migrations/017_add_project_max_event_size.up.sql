@@ -0,0 +1,2 @@
+-- Per-project limit on the size of event payloads in bytes; NULL uses the server limit
+ALTER TABLE projects ADD COLUMN max_event_size INTEGER;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_audit_log_entries_target;
+CREATE INDEX idx_audit_log_entries_target ON audit_log_entries(target_type, target_id);
+
+ALTER TABLE audit_log_entries DROP COLUMN IF EXISTS setting;
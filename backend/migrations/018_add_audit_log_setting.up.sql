@@ -0,0 +1,5 @@
+-- Field-level change history of project and organization settings
+ALTER TABLE audit_log_entries ADD COLUMN setting VARCHAR(100);
+
+DROP INDEX IF EXISTS idx_audit_log_entries_target;
+CREATE INDEX idx_audit_log_entries_target ON audit_log_entries(target_type, target_id, setting);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS release_artifacts;
+DROP TABLE IF EXISTS org_ingest_tokens;
+
+ALTER TABLE releases DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE releases DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,37 @@
+-- Releases are now created through the API and use the common timestamps
+ALTER TABLE releases ADD COLUMN IF NOT EXISTS created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW();
+ALTER TABLE releases ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW();
+
+-- Organization tokens for CI systems, restricted to the release and artifact endpoints
+CREATE TABLE org_ingest_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE, -- SHA-256 of the token; the token itself is never stored
+    token_prefix VARCHAR(16) NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_org_ingest_tokens_organization_id ON org_ingest_tokens(organization_id);
+
+-- Source maps and other files uploaded for a release; content is kept in blob storage
+CREATE TABLE release_artifacts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    release_id UUID NOT NULL REFERENCES releases(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(500) NOT NULL, -- URL or ~/ path of the file as reported by SDKs
+    dist VARCHAR(64),
+    content_type VARCHAR(255),
+    size BIGINT,
+    checksum VARCHAR(40), -- SHA-1 of the content
+    storage_key VARCHAR(500) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_release_artifacts_release_id ON release_artifacts(release_id);
+CREATE INDEX idx_release_artifacts_project_id ON release_artifacts(project_id);
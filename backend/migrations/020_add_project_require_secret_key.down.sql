@@ -0,0 +1 @@
+ALTER TABLE projects DROP COLUMN IF EXISTS require_secret_key;
@@ -0,0 +1,2 @@
+-- Projects can require server-side SDKs to send the secret key (sentry_secret) on ingestion
+ALTER TABLE projects ADD COLUMN require_secret_key BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS inbound_filter_stats;
+DROP TABLE IF EXISTS project_inbound_filters;
@@ -0,0 +1,25 @@
+-- Per-project filters discarding error events before they are grouped into issues
+CREATE TABLE project_inbound_filters (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL UNIQUE REFERENCES projects(id) ON DELETE CASCADE,
+    browser_extensions BOOLEAN NOT NULL DEFAULT FALSE,
+    web_crawlers BOOLEAN NOT NULL DEFAULT FALSE,
+    localhost BOOLEAN NOT NULL DEFAULT FALSE,
+    legacy_browsers BOOLEAN NOT NULL DEFAULT FALSE,
+    error_messages JSONB, -- glob patterns matched against event messages
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Events discarded by inbound filters, aggregated per project, hour and filter
+CREATE TABLE inbound_filter_stats (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    bucket TIMESTAMP WITH TIME ZONE NOT NULL, -- start of the hour
+    reason VARCHAR(50) NOT NULL,
+    filtered BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_inbound_filter_stats_bucket ON inbound_filter_stats(project_id, bucket, reason);
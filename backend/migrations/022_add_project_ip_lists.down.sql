@@ -0,0 +1,2 @@
+ALTER TABLE projects DROP COLUMN IF EXISTS ip_deny_list;
+ALTER TABLE projects DROP COLUMN IF EXISTS ip_allow_list;
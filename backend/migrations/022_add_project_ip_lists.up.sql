@@ -0,0 +1,3 @@
+-- CIDR ranges ingestion accepts (allow list) or rejects (deny list) events from
+ALTER TABLE projects ADD COLUMN ip_allow_list JSONB;
+ALTER TABLE projects ADD COLUMN ip_deny_list JSONB;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_issues_transaction_name;
+DROP INDEX IF EXISTS idx_events_transaction_name;
+
+ALTER TABLE issues DROP COLUMN IF EXISTS transaction_name;
+ALTER TABLE events DROP COLUMN IF EXISTS transaction_name;
@@ -0,0 +1,7 @@
+-- Route errors happened on, derived from the request URL with IDs templated (e.g. /orders/{id}).
+-- Named transaction_name as TRANSACTION is reserved in SQLite.
+ALTER TABLE events ADD COLUMN transaction_name VARCHAR(200);
+ALTER TABLE issues ADD COLUMN transaction_name VARCHAR(200);
+
+CREATE INDEX idx_events_transaction_name ON events(transaction_name);
+CREATE INDEX idx_issues_transaction_name ON issues(transaction_name);
@@ -0,0 +1 @@
+ALTER TABLE project_inbound_filters DROP COLUMN IF EXISTS exception_types;
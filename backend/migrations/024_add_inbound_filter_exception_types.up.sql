@@ -0,0 +1,2 @@
+-- Glob or /regex/ patterns discarding events by exception type
+ALTER TABLE project_inbound_filters ADD COLUMN exception_types JSONB;
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_releases_semver;
+
+ALTER TABLE releases DROP COLUMN IF EXISTS build_number;
+ALTER TABLE releases DROP COLUMN IF EXISTS build_code;
+ALTER TABLE releases DROP COLUMN IF EXISTS semver_final;
+ALTER TABLE releases DROP COLUMN IF EXISTS semver_prerelease;
+ALTER TABLE releases DROP COLUMN IF EXISTS semver_revision;
+ALTER TABLE releases DROP COLUMN IF EXISTS semver_patch;
+ALTER TABLE releases DROP COLUMN IF EXISTS semver_minor;
+ALTER TABLE releases DROP COLUMN IF EXISTS semver_major;
+ALTER TABLE releases DROP COLUMN IF EXISTS version_format;
+ALTER TABLE releases DROP COLUMN IF EXISTS package;
@@ -0,0 +1,15 @@
+-- Comparable components parsed from release versions, so releases are filtered and ordered
+-- by version instead of comparing version strings. Existing releases are parsed by the
+-- server on startup (version_format is empty until then).
+ALTER TABLE releases ADD COLUMN package VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE releases ADD COLUMN version_format VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE releases ADD COLUMN semver_major BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE releases ADD COLUMN semver_minor BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE releases ADD COLUMN semver_patch BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE releases ADD COLUMN semver_revision BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE releases ADD COLUMN semver_prerelease VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE releases ADD COLUMN semver_final BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE releases ADD COLUMN build_code VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE releases ADD COLUMN build_number BIGINT;
+
+CREATE INDEX idx_releases_semver ON releases(project_id, semver_major, semver_minor, semver_patch, semver_revision);
@@ -0,0 +1,2 @@
+-- Data scrubbing rules (field selector and mask, hash or remove action) applied to events
+ALTER TABLE projects ADD COLUMN scrubbing_rules JSONB;
@@ -0,0 +1,2 @@
+ALTER TABLE projects DROP COLUMN IF EXISTS event_sample_rate;
+ALTER TABLE projects DROP COLUMN IF EXISTS event_sample_threshold;
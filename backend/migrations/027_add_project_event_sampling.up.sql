@@ -0,0 +1,3 @@
+-- Events of an issue stored per hour before only one in event_sample_rate is stored
+ALTER TABLE projects ADD COLUMN event_sample_threshold INTEGER;
+ALTER TABLE projects ADD COLUMN event_sample_rate INTEGER;
@@ -0,0 +1,2 @@
+-- Timestamp an SDK sent for an event whose clock skew was corrected on ingestion
+ALTER TABLE events ADD COLUMN original_timestamp TIMESTAMP WITH TIME ZONE;
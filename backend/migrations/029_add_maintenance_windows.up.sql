@@ -0,0 +1,15 @@
+-- Read-only maintenance windows of an organization or, without organization_id, the instance
+CREATE TABLE maintenance_windows (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE,
+    message TEXT NOT NULL,
+    ingestion VARCHAR(20) NOT NULL DEFAULT 'accept', -- accept, buffer, reject
+    started_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ended_at TIMESTAMP WITH TIME ZONE, -- set when the window is ended
+    ended_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_maintenance_windows_organization_id ON maintenance_windows(organization_id);
+CREATE INDEX idx_maintenance_windows_active ON maintenance_windows(created_at) WHERE ended_at IS NULL;
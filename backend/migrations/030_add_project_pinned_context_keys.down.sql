@@ -0,0 +1 @@
+ALTER TABLE projects DROP COLUMN IF EXISTS pinned_context_keys;
@@ -0,0 +1,2 @@
+-- Context and tag keys whose latest event values are shown in the issue list
+ALTER TABLE projects ADD COLUMN pinned_context_keys JSONB;
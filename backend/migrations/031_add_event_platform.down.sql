@@ -0,0 +1,2 @@
+ALTER TABLE issues DROP COLUMN IF EXISTS platform;
+ALTER TABLE events DROP COLUMN IF EXISTS platform;
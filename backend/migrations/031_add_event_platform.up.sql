@@ -0,0 +1,4 @@
+-- Platform of events, sent or inferred from the SDK, stack frames or exception types,
+-- and of the first event of issues
+ALTER TABLE events ADD COLUMN platform VARCHAR(64);
+ALTER TABLE issues ADD COLUMN platform VARCHAR(64);
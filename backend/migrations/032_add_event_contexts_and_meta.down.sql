@@ -0,0 +1,2 @@
+ALTER TABLE events DROP COLUMN IF EXISTS meta;
+ALTER TABLE events DROP COLUMN IF EXISTS contexts;
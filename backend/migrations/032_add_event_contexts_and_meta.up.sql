@@ -0,0 +1,4 @@
+-- Event contexts (device, os, runtime...) and the _meta annotations describing what the
+-- event limits trimmed
+ALTER TABLE events ADD COLUMN contexts JSONB;
+ALTER TABLE events ADD COLUMN meta JSONB;
@@ -0,0 +1,2 @@
+-- Chained exceptions (causes) of events with their stack traces, set when there are causes
+ALTER TABLE events ADD COLUMN exceptions JSONB;
@@ -0,0 +1,24 @@
+-- Environments seen per project, so listing them does not scan events
+CREATE TABLE environments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(), -- when the environment was first seen
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_environments_project_name ON environments(project_id, name);
+
+-- Environments of the data stored so far, first seen at their earliest event, transaction or session
+INSERT INTO environments (project_id, name, created_at, updated_at)
+SELECT project_id, environment, MIN(seen_at), NOW()
+FROM (
+    SELECT project_id, environment, created_at AS seen_at FROM events
+    UNION ALL
+    SELECT project_id, environment, created_at FROM transactions
+    UNION ALL
+    SELECT project_id, environment, created_at FROM sessions
+) seen
+WHERE environment IS NOT NULL AND environment <> ''
+GROUP BY project_id, environment
+ON CONFLICT (project_id, name) DO NOTHING;
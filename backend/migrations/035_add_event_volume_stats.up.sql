@@ -0,0 +1,26 @@
+-- Error events accepted per project, UTC day, environment and release ('' without one)
+CREATE TABLE event_volume_stats (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    day TIMESTAMP WITH TIME ZONE NOT NULL, -- midnight UTC
+    environment VARCHAR(100) NOT NULL,
+    release_version VARCHAR(100) NOT NULL,
+    events BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_event_volume_stats_dimensions ON event_volume_stats(project_id, day, environment, release_version);
+
+-- Volume of the events stored so far
+INSERT INTO event_volume_stats (project_id, day, environment, release_version, events, created_at, updated_at)
+SELECT project_id,
+       date_trunc('day', COALESCE(timestamp, created_at) AT TIME ZONE 'UTC') AT TIME ZONE 'UTC',
+       COALESCE(environment, ''),
+       COALESCE(release_version, ''),
+       COUNT(*),
+       NOW(),
+       NOW()
+FROM events
+GROUP BY 1, 2, 3, 4
+ON CONFLICT (project_id, day, environment, release_version) DO NOTHING;
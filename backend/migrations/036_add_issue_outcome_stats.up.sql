@@ -0,0 +1,13 @@
+-- Error events not stored per project, fingerprint ('' for rate limited events), outcome and UTC day
+CREATE TABLE issue_outcome_stats (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    fingerprint VARCHAR(255) NOT NULL,
+    outcome VARCHAR(50) NOT NULL, -- duplicate, sampled or rate_limited
+    day TIMESTAMP WITH TIME ZONE NOT NULL, -- midnight UTC
+    events BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_issue_outcome_stats_dimensions ON issue_outcome_stats(project_id, fingerprint, outcome, day);
@@ -0,0 +1,18 @@
+-- API calls per organization, project (nil UUID for organization routes), caller, UTC day,
+-- route and status class
+CREATE TABLE api_usage_stats (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL,
+    caller_type VARCHAR(20) NOT NULL, -- user or ingest_token
+    caller_id UUID NOT NULL,
+    day TIMESTAMP WITH TIME ZONE NOT NULL, -- midnight UTC
+    route VARCHAR(255) NOT NULL, -- method and route pattern, e.g. GET /api/v1/issues/{issue_id}
+    status_class VARCHAR(10) NOT NULL, -- 2xx, 3xx, 4xx, 429 or 5xx
+    calls BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_api_usage_stats_dimensions ON api_usage_stats(organization_id, project_id, caller_type, caller_id, day, route, status_class);
+CREATE INDEX idx_api_usage_stats_organization_day ON api_usage_stats(organization_id, day);
@@ -0,0 +1,3 @@
+-- How events are grouped into issues: stack frames fingerprinted, whether the message is,
+-- and a fingerprint template for events sent without one
+ALTER TABLE projects ADD COLUMN grouping_config JSONB;
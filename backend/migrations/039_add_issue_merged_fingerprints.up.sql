@@ -0,0 +1,15 @@
+-- Fingerprints of issues merged into another issue; events with them are grouped into that issue
+CREATE TABLE issue_merged_fingerprints (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    fingerprint VARCHAR(255) NOT NULL,
+    issue_id UUID NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    merged_issue_id UUID NOT NULL, -- The issue the fingerprint had before the merge, since deleted
+    title VARCHAR(500) NOT NULL,
+    merged_by_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(project_id, fingerprint)
+);
+
+CREATE INDEX idx_issue_merged_fingerprints_issue_id ON issue_merged_fingerprints(issue_id);
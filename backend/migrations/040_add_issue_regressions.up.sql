@@ -0,0 +1,2 @@
+-- Set when a resolved issue receives new events, and cleared when it is resolved again
+ALTER TABLE issues ADD COLUMN is_regression BOOLEAN NOT NULL DEFAULT FALSE;
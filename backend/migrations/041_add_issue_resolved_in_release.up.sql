@@ -0,0 +1,2 @@
+-- The release an issue was resolved in; events of that release and older ones keep it resolved
+ALTER TABLE issues ADD COLUMN resolved_in_release VARCHAR(100);
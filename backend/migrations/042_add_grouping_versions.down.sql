@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS grouping_regroup_jobs;
+ALTER TABLE events DROP COLUMN IF EXISTS custom_fingerprint;
+ALTER TABLE events DROP COLUMN IF EXISTS grouping_version;
+ALTER TABLE issues DROP COLUMN IF EXISTS grouping_version;
+ALTER TABLE projects DROP COLUMN IF EXISTS grouping_version;
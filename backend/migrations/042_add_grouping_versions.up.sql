@@ -0,0 +1,29 @@
+-- Grouping config versions, counting the changes of a project's grouping config; events and
+-- issues record the version they were grouped with. Events sent with their own fingerprint
+-- or fixed by their type (CSP reports) are never regrouped.
+ALTER TABLE projects ADD COLUMN grouping_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE issues ADD COLUMN grouping_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE events ADD COLUMN grouping_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE events ADD COLUMN custom_fingerprint BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Jobs recomputing the fingerprints of a project's recent events with its current grouping config
+CREATE TABLE grouping_regroup_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    requested_by_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    grouping_version INTEGER NOT NULL,
+    since TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_event_id UUID,
+    events_checked INTEGER NOT NULL DEFAULT 0,
+    events_moved INTEGER NOT NULL DEFAULT 0,
+    issues_created INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_grouping_regroup_jobs_project_id ON grouping_regroup_jobs(project_id);
+CREATE INDEX idx_grouping_regroup_jobs_status ON grouping_regroup_jobs(status);
@@ -0,0 +1,14 @@
+-- Rules folding the future events of a project matching their patterns into an issue
+CREATE TABLE issue_fingerprint_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    issue_id UUID NOT NULL UNIQUE REFERENCES issues(id) ON DELETE CASCADE,
+    key VARCHAR(200) NOT NULL,
+    exception_type VARCHAR(500), -- Glob or /regex/ patterns; unset ones match any event
+    message VARCHAR(500),
+    transaction_name VARCHAR(500),
+    created_by_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(project_id, key)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_issues_deletion_requested_at;
+ALTER TABLE issues DROP COLUMN IF EXISTS deletion_requested_by_id;
+ALTER TABLE issues DROP COLUMN IF EXISTS deletion_requested_at;
@@ -0,0 +1,6 @@
+-- Issues queued for deletion by the bulk delete action; a background task deletes them with
+-- their events, comments and activity
+ALTER TABLE issues ADD COLUMN deletion_requested_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE issues ADD COLUMN deletion_requested_by_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_issues_deletion_requested_at ON issues(deletion_requested_at) WHERE deletion_requested_at IS NOT NULL;
@@ -0,0 +1,3 @@
+-- When ignored issues are unignored by the events they receive: after a time, or once a
+-- count of events or of users affected is reached since they were ignored
+ALTER TABLE issues ADD COLUMN ignore_condition JSONB;
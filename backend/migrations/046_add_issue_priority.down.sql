@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_issues_priority;
+ALTER TABLE issues DROP COLUMN IF EXISTS priority_locked;
+ALTER TABLE issues DROP COLUMN IF EXISTS priority;
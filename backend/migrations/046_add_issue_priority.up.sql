@@ -0,0 +1,12 @@
+-- Issue priority: low, medium or high. It starts from the level of the first event and rises
+-- as issues reach 100 and 1000 events, unless set by a user (priority_locked).
+ALTER TABLE issues ADD COLUMN priority VARCHAR(20) NOT NULL DEFAULT 'medium';
+ALTER TABLE issues ADD COLUMN priority_locked BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE issues SET priority = CASE
+    WHEN level = 'fatal' OR times_seen >= 1000 THEN 'high'
+    WHEN level = 'error' OR times_seen >= 100 THEN 'medium'
+    ELSE 'low'
+END;
+
+CREATE INDEX idx_issues_priority ON issues(priority);
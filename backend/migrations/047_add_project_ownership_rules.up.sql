@@ -0,0 +1,2 @@
+-- Ownership rules (path, module or tag pattern and owner) auto-assigning new issues
+ALTER TABLE projects ADD COLUMN ownership_rules JSONB;
@@ -0,0 +1,16 @@
+-- Named issue list filters, personal (owner and project) or shared with the organization
+CREATE TABLE saved_searches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    project_id UUID REFERENCES projects(id) ON DELETE CASCADE, -- NULL for organization searches of every project
+    owner_id UUID REFERENCES users(id) ON DELETE CASCADE,      -- NULL for organization searches
+    name VARCHAR(100) NOT NULL,
+    filters JSONB,
+    created_by_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_saved_searches_organization_id ON saved_searches(organization_id);
+CREATE INDEX idx_saved_searches_project_id ON saved_searches(project_id);
+CREATE INDEX idx_saved_searches_owner_id ON saved_searches(owner_id);
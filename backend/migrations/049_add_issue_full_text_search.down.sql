@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_events_search;
+DROP INDEX IF EXISTS idx_issues_search;
@@ -0,0 +1,7 @@
+-- Full-text search of issues: the words of their titles and culprits, and of the messages of
+-- their events. The expressions match those the issue search queries.
+CREATE INDEX idx_issues_search ON issues
+    USING GIN (to_tsvector('simple', title || ' ' || COALESCE(culprit, '')));
+
+CREATE INDEX idx_events_search ON events
+    USING GIN (to_tsvector('simple', COALESCE(message, '') || ' ' || COALESCE(exception_type, '') || ' ' || COALESCE(exception_value, '')));
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS issue_tag_values;
+DROP TABLE IF EXISTS event_tags;
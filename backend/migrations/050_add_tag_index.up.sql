@@ -0,0 +1,47 @@
+-- Tags of events, copied out of events.tags so issues can be filtered by tag with an index
+CREATE TABLE event_tags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    issue_id UUID NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    key VARCHAR(200) NOT NULL,
+    value VARCHAR(200) NOT NULL,
+    timestamp TIMESTAMP WITH TIME ZONE NOT NULL, -- the event's
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_event_tags_event_key ON event_tags(event_id, key);
+CREATE INDEX idx_event_tags_issue_id ON event_tags(issue_id);
+CREATE INDEX idx_event_tags_project_key_value ON event_tags(project_id, key, value);
+
+-- Stored events of each issue per tag value
+CREATE TABLE issue_tag_values (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    issue_id UUID NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    key VARCHAR(200) NOT NULL,
+    value VARCHAR(200) NOT NULL,
+    times_seen BIGINT NOT NULL DEFAULT 0,
+    first_seen TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_seen TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_issue_tag_values_issue_key_value ON issue_tag_values(issue_id, key, value);
+CREATE INDEX idx_issue_tag_values_project_id ON issue_tag_values(project_id);
+-- Tag filters of the issue list compare values case-insensitively
+CREATE INDEX idx_issue_tag_values_issue_key_lower_value ON issue_tag_values(issue_id, key, LOWER(value));
+
+-- Tags of the events stored so far
+INSERT INTO event_tags (event_id, project_id, issue_id, key, value, timestamp)
+SELECT events.id, events.project_id, events.issue_id, tag.key, LEFT(tag.value, 200), COALESCE(events.timestamp, events.created_at)
+FROM events, jsonb_each_text(events.tags) AS tag
+WHERE jsonb_typeof(events.tags) = 'object' AND LENGTH(tag.key) <= 200 AND tag.value IS NOT NULL AND tag.value <> ''
+ON CONFLICT (event_id, key) DO NOTHING;
+
+INSERT INTO issue_tag_values (project_id, issue_id, key, value, times_seen, first_seen, last_seen)
+SELECT project_id, issue_id, key, value, COUNT(*), MIN(timestamp), MAX(timestamp)
+FROM event_tags
+GROUP BY project_id, issue_id, key, value;
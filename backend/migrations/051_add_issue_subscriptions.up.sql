@@ -0,0 +1,23 @@
+-- Participants of issues: users who commented, were assigned or subscribed. Unsubscribed
+-- users keep an inactive row so taking part again does not subscribe them back.
+CREATE TABLE issue_subscriptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    issue_id UUID NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    reason VARCHAR(32) NOT NULL, -- commented, assigned or subscribed
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_issue_subscription ON issue_subscriptions(issue_id, user_id);
+CREATE INDEX idx_issue_subscriptions_user_id ON issue_subscriptions(user_id);
+
+-- Commenters and assignees so far
+INSERT INTO issue_subscriptions (issue_id, user_id, reason)
+SELECT DISTINCT issue_id, user_id, 'commented' FROM issue_comments
+ON CONFLICT (issue_id, user_id) DO NOTHING;
+
+INSERT INTO issue_subscriptions (issue_id, user_id, reason)
+SELECT id, assignee_id, 'assigned' FROM issues WHERE assignee_id IS NOT NULL
+ON CONFLICT (issue_id, user_id) DO NOTHING;
@@ -0,0 +1,12 @@
+-- Organization members mentioned in issue comments as @email or @name
+CREATE TABLE issue_comment_mentions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    comment_id UUID NOT NULL REFERENCES issue_comments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    text VARCHAR(255) NOT NULL, -- the mention as written, without the @
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_comment_mention ON issue_comment_mentions(comment_id, user_id);
+CREATE INDEX idx_issue_comment_mentions_user_id ON issue_comment_mentions(user_id);
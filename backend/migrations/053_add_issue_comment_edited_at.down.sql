@@ -0,0 +1 @@
+ALTER TABLE issue_comments DROP COLUMN IF EXISTS edited_at;
@@ -0,0 +1,3 @@
+-- When issue comments were last edited by their authors or organization admins; their
+-- previous content is kept in the issue's comment_edited activity
+ALTER TABLE issue_comments ADD COLUMN edited_at TIMESTAMP WITH TIME ZONE;
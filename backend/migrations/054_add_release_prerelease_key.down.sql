@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_releases_semver;
+CREATE INDEX idx_releases_semver ON releases(project_id, semver_major, semver_minor, semver_patch, semver_revision);
+
+ALTER TABLE releases DROP COLUMN IF EXISTS semver_prerelease_key;
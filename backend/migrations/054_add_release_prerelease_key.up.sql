@@ -0,0 +1,7 @@
+-- Prereleases ordered like semver (rc.2 before rc.10) when compared as bytes, so the latest
+-- release and release comparisons agree with version comparisons made in Go. Existing
+-- prereleases get their key from the server on startup.
+ALTER TABLE releases ADD COLUMN semver_prerelease_key TEXT COLLATE "C" NOT NULL DEFAULT '';
+
+DROP INDEX IF EXISTS idx_releases_semver;
+CREATE INDEX idx_releases_semver ON releases(project_id, semver_major, semver_minor, semver_patch, semver_revision, semver_final, semver_prerelease_key);
@@ -0,0 +1,3 @@
+DELETE FROM issue_sync_deltas WHERE issue_id NOT IN (SELECT id FROM issues);
+ALTER TABLE issue_sync_deltas ADD CONSTRAINT issue_sync_deltas_issue_id_fkey
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE;
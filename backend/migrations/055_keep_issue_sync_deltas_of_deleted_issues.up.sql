@@ -0,0 +1,3 @@
+-- Deltas outlive their issue, so consumers following the feed see the issue.deleted delta
+-- and the changes before it; they are removed by the retention cleanup like the others.
+ALTER TABLE issue_sync_deltas DROP CONSTRAINT IF EXISTS issue_sync_deltas_issue_id_fkey;
@@ -41,11 +41,13 @@ export interface ApiError extends Error {
 class ApiClient {
   private axios: AxiosInstance
   private tokenRefreshPromise: Promise<string> | null = null
+  private csrfToken: string | null = null
 
   constructor() {
     this.axios = axios.create({
       baseURL: '/api',
       timeout: 30000,
+      withCredentials: true,
       headers: {
         'Content-Type': 'application/json',
       }
@@ -61,6 +63,12 @@ class ApiClient {
         const token = this.getAccessToken()
         if (token) {
           config.headers.Authorization = `Bearer ${token}`
+        } else {
+          // Cookie sessions authenticate unsafe requests with their CSRF token
+          const csrfToken = this.getCSRFToken()
+          if (csrfToken) {
+            config.headers['X-CSRF-Token'] = csrfToken
+          }
         }
         return config
       },
@@ -79,7 +87,11 @@ class ApiClient {
           try {
             const newToken = await this.refreshAccessToken()
             if (newToken && originalRequest.headers) {
-              originalRequest.headers.Authorization = `Bearer ${newToken}`
+              if (this.getAccessToken()) {
+                originalRequest.headers.Authorization = `Bearer ${newToken}`
+              } else {
+                originalRequest.headers['X-CSRF-Token'] = newToken
+              }
               return this.axios(originalRequest)
             }
           } catch (refreshError) {
@@ -119,6 +131,31 @@ class ApiClient {
   private clearTokens(): void {
     localStorage.removeItem('access_token')
     localStorage.removeItem('refresh_token')
+    this.csrfToken = null
+  }
+
+  // getCSRFToken returns the CSRF token of a cookie session, kept in memory or else read
+  // back from its cookie after a reload
+  private getCSRFToken(): string | null {
+    if (this.csrfToken) {
+      return this.csrfToken
+    }
+    const match = document.cookie.match(/(?:^|;\s*)minisentry_csrf=([^;]*)/)
+    return match ? decodeURIComponent(match[1]) : null
+  }
+
+  // hasCookieSession reports whether the server keeps the session in httpOnly cookies
+  hasCookieSession(): boolean {
+    return this.getCSRFToken() !== null
+  }
+
+  // storeSession keeps the tokens of a login, or the CSRF token of a cookie session
+  private storeSession(response: AuthResponse): void {
+    if (response.access_token && response.refresh_token) {
+      this.setTokens(response.access_token, response.refresh_token)
+    } else if (response.csrf_token) {
+      this.csrfToken = response.csrf_token
+    }
   }
 
   private async refreshAccessToken(): Promise<string | null> {
@@ -127,11 +164,13 @@ class ApiClient {
     }
 
     const refreshToken = this.getRefreshToken()
-    if (!refreshToken) {
+    if (!refreshToken && !this.hasCookieSession()) {
       return null
     }
 
-    this.tokenRefreshPromise = this.performTokenRefresh(refreshToken)
+    this.tokenRefreshPromise = refreshToken
+      ? this.performTokenRefresh(refreshToken)
+      : this.performCookieRefresh()
     
     try {
       const newToken = await this.tokenRefreshPromise
@@ -147,22 +186,29 @@ class ApiClient {
     })
 
     const { access_token, refresh_token: newRefreshToken } = response.data
-    this.setTokens(access_token, newRefreshToken)
-    return access_token
+    this.setTokens(access_token!, newRefreshToken!)
+    return access_token!
+  }
+
+  // performCookieRefresh refreshes a cookie session, whose refresh token is sent as a cookie
+  private async performCookieRefresh(): Promise<string> {
+    const response = await axios.post<{ csrf_token: string }>('/api/v1/auth/refresh', undefined, {
+      withCredentials: true
+    })
+    this.csrfToken = response.data.csrf_token
+    return this.csrfToken
   }
 
   // Authentication methods
   async login(credentials: LoginRequest): Promise<AuthResponse> {
     const response = await this.axios.post<AuthResponse>('/v1/auth/login', credentials)
-    const { access_token, refresh_token } = response.data
-    this.setTokens(access_token, refresh_token)
+    this.storeSession(response.data)
     return response.data
   }
 
   async register(data: RegisterRequest): Promise<AuthResponse> {
     const response = await this.axios.post<AuthResponse>('/v1/auth/register', data)
-    const { access_token, refresh_token } = response.data
-    this.setTokens(access_token, refresh_token)
+    this.storeSession(response.data)
     return response.data
   }
 
@@ -178,8 +224,7 @@ class ApiClient {
     const response = await this.axios.post<AuthResponse>('/v1/auth/refresh', {
       refresh_token: refreshToken
     })
-    const { access_token, refresh_token: newRefreshToken } = response.data
-    this.setTokens(access_token, newRefreshToken)
+    this.storeSession(response.data)
     return response.data
   }
 
@@ -36,7 +36,7 @@ export const AuthProvider = ({ children }: AuthProviderProps) => {
   useEffect(() => {
     const initializeAuth = async () => {
       const token = localStorage.getItem('access_token')
-      if (!token) {
+      if (!token && !apiClient.hasCookieSession()) {
         setLoading(false)
         return
       }
@@ -50,10 +50,12 @@ export interface LoginRequest {
 }
 
 export interface AuthResponse {
-  access_token: string
-  refresh_token: string
+  // Cookie sessions return csrf_token instead of the tokens
+  access_token?: string
+  refresh_token?: string
   token_type: string
   expires_in: number
+  csrf_token?: string
   user: User
 }
 